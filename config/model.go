@@ -9,17 +9,22 @@ import (
 )
 
 type Config struct {
-	App      string
-	AppVer   string
-	Env      string
-	Http     HttpConfig
-	Log      LogConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Toggle   ToggleConfig
-	JWT      JwtConfig
-	Email    EmailConfig
-	Domain   DomainConfig
+	App       string
+	AppVer    string
+	Env       string
+	Http      HttpConfig
+	Log       LogConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	Toggle    ToggleConfig
+	JWT       JwtConfig
+	Email     EmailConfig
+	Domain    DomainConfig
+	DocNumber DocNumberConfig
+	Print     PrintConfig
+	Purchase  PurchaseConfig
+	Currency  CurrencyConfig
+	Company   CompanyConfig
 }
 
 type HttpConfig struct {
@@ -84,6 +89,56 @@ type DomainConfig struct {
 	ChangePassDuration int
 }
 
+// DocNumberConfig mengatur penomoran dokumen. Key Patterns adalah kategori
+// logactivity dalam huruf kecil (viper menyimpan key dalam lowercase).
+type DocNumberConfig struct {
+	Site     string
+	Patterns map[string]DocNumberPattern
+}
+
+type DocNumberPattern struct {
+	Format string
+	Period string
+}
+
+// PrintConfig mengatur template cetak dokumen. Template di TemplateDir/<site>/
+// dipakai lebih dulu, lalu TemplateDir/, lalu template bawaan aplikasi.
+type PrintConfig struct {
+	TemplateDir string
+}
+
+// PurchaseConfig toleransi three-way match vendor invoice dalam persen. Qty
+// dibandingkan dengan qty diterima, harga dengan harga satuan PO; 0 berarti
+// harus sama persis.
+type PurchaseConfig struct {
+	QtyTolerance     float32
+	PriceTolerance   float32
+	QuotationRanking QuotationRankingConfig
+}
+
+// QuotationRankingConfig bobot tiap kriteria perbandingan vendor quotation.
+// DeliveryTypes memberi nilai 0..1 per delivery type (key lowercase), delivery
+// type yang tidak terdaftar bernilai 0.
+type QuotationRankingConfig struct {
+	Price         float64
+	Payment       float64
+	Delivery      float64
+	Rating        float64
+	DeliveryTypes map[string]float64
+}
+
+// CurrencyConfig mata uang dasar perusahaan
+type CurrencyConfig struct {
+	Base string
+}
+
+// CompanyConfig profil perusahaan sebagai penjual di faktur pajak
+type CompanyConfig struct {
+	Name    string
+	NPWP    string
+	Address string
+}
+
 func (c *Config) LoadConfig(path string) {
 	viper.AddConfigPath(".")
 	viper.SetConfigName(path)
@@ -0,0 +1,189 @@
+package sqlx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/domain/logactivity"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+// kolom yang selalu berubah setiap update atau tidak boleh tercatat
+var auditSkipColumn = map[string]bool{
+	"CreateBy": true,
+	"CreateDt": true,
+	"LastUpBy": true,
+	"LastUpDt": true,
+	"Pwd":      true,
+}
+
+// auditTrail menyimpan isi data sebelum di-update. Write dipanggil di transaksi
+// yang sama setelah update, membandingkan per field lalu menulis tbllogactivity.
+//
+//	ALTER TABLE tbllogactivity
+//		ADD COLUMN RequestId VARCHAR(64) NULL,
+//		ADD COLUMN Changes JSON NULL;
+type auditTrail struct {
+	category string
+	code     string
+	before   map[string]*string
+}
+
+func newAuditTrail(ctx context.Context, tx *sqlx.Tx, category, code string) (*auditTrail, error) {
+	before, err := auditSnapshot(ctx, tx, category, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auditTrail{category: category, code: code, before: before}, nil
+}
+
+func (a *auditTrail) Write(ctx context.Context, tx *sqlx.Tx, userCode, date string) error {
+	after, err := auditSnapshot(ctx, tx, a.category, a.code)
+	if err != nil {
+		return err
+	}
+
+	changes, err := json.Marshal(auditDiff(a.before, after))
+	if err != nil {
+		return fmt.Errorf("error encode audit changes: %w", err)
+	}
+
+	// request id dari middleware requestid, ikut terbawa di context fiber
+	requestId, _ := ctx.Value("requestid").(string)
+
+	query := "INSERT INTO tbllogactivity (UserCode, Code, Category, RequestId, Changes, LastUpDt) VALUES (?, ?, ?, ?, ?, ?)"
+	if _, err := tx.ExecContext(ctx, query, userCode, a.code, a.category, requestId, string(changes), date); err != nil {
+		log.Printf("Error insert log activity: %+v", err)
+		return fmt.Errorf("error insert to log activity: %w", err)
+	}
+
+	return nil
+}
+
+// auditSnapshot membaca baris header dan detail sebuah data menjadi map
+// field -> nilai. Kategori yang tidak terdaftar menghasilkan map kosong.
+func auditSnapshot(ctx context.Context, tx *sqlx.Tx, category, code string) (map[string]*string, error) {
+	snapshot := make(map[string]*string)
+
+	table, err := logactivity.TableOf(category)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrKeyNotFound) {
+			return snapshot, nil
+		}
+		return nil, err
+	}
+	primKey, err := logactivity.PrimaryKeyOf(category)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s = ?", table, primKey)
+	if err := auditScan(ctx, tx, query, code, func(row map[string]*string) {
+		for column, value := range row {
+			snapshot[column] = value
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, detail := range logactivity.DetailTablesOf(category) {
+		query = fmt.Sprintf("SELECT * FROM %s WHERE %s = ?", detail.Table, detail.Parent)
+		if err := auditScan(ctx, tx, query, code, func(row map[string]*string) {
+			keys := make([]string, len(detail.Key))
+			for i, column := range detail.Key {
+				if row[column] != nil {
+					keys[i] = *row[column]
+				}
+			}
+			prefix := fmt.Sprintf("%s[%s].", detail.Table, strings.Join(keys, "/"))
+
+			for column, value := range row {
+				if column != detail.Parent {
+					snapshot[prefix+column] = value
+				}
+			}
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return snapshot, nil
+}
+
+func auditScan(ctx context.Context, tx *sqlx.Tx, query, code string, fn func(map[string]*string)) error {
+	rows, err := tx.QueryxContext(ctx, query, code)
+	if err != nil {
+		log.Printf("Error audit snapshot: %+v", err)
+		return fmt.Errorf("error audit snapshot: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		values := make(map[string]interface{})
+		if err := rows.MapScan(values); err != nil {
+			return fmt.Errorf("error scan audit snapshot: %w", err)
+		}
+
+		row := make(map[string]*string, len(values))
+		for column, value := range values {
+			if auditSkipColumn[column] {
+				continue
+			}
+			row[column] = auditValue(value)
+		}
+		fn(row)
+	}
+
+	return rows.Err()
+}
+
+func auditValue(value interface{}) *string {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		s = string(v)
+	default:
+		s = fmt.Sprint(v)
+	}
+	return &s
+}
+
+// auditDiff mengembalikan field yang nilainya berbeda, urut berdasarkan nama field.
+func auditDiff(before, after map[string]*string) []logactivity.Change {
+	fields := make(map[string]bool, len(before)+len(after))
+	for field := range before {
+		fields[field] = true
+	}
+	for field := range after {
+		fields[field] = true
+	}
+
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	changes := make([]logactivity.Change, 0)
+	for _, field := range names {
+		oldValue, newValue := before[field], after[field]
+		if oldValue == nil && newValue == nil {
+			continue
+		}
+		if oldValue != nil && newValue != nil && *oldValue == *newValue {
+			continue
+		}
+		changes = append(changes, logactivity.Change{Field: field, OldValue: oldValue, NewValue: newValue})
+	}
+
+	return changes
+}
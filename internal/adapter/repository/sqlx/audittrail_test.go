@@ -0,0 +1,67 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/domain/logactivity"
+)
+
+type AuditTrailSuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	db      *sqlx.DB
+}
+
+func (suite *AuditTrailSuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+}
+
+func (suite *AuditTrailSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *AuditTrailSuite) TestWrite_FieldDiff() {
+	columns := []string{"UomCode", "UomName", "ActInd", "LastUpDt"}
+
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectQuery("SELECT * FROM tbluom WHERE UomCode = ?").
+		WithArgs("PCS").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("PCS", "Pieces", "Y", "202601010800"))
+	suite.mockSQL.ExpectQuery("SELECT * FROM tbluom WHERE UomCode = ?").
+		WithArgs("PCS").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("PCS", "Pcs", "Y", "202601020900"))
+	suite.mockSQL.ExpectExec("INSERT INTO tbllogactivity (UserCode, Code, Category, RequestId, Changes, LastUpDt) VALUES (?, ?, ?, ?, ?, ?)").
+		WithArgs("admin", "PCS", "Uom", "req-1", `[{"field":"UomName","old_value":"Pieces","new_value":"Pcs"}]`, "202601020900").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	ctx := context.WithValue(context.Background(), "requestid", "req-1")
+	tx, err := suite.db.Beginx()
+	suite.Require().NoError(err)
+
+	audit, err := newAuditTrail(ctx, tx, "Uom", "PCS")
+	suite.Require().NoError(err)
+
+	err = audit.Write(ctx, tx, "admin", "202601020900")
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func (suite *AuditTrailSuite) TestDiff_NullValue() {
+	value := "10"
+	changes := auditDiff(map[string]*string{"Qty": &value, "Remark": nil}, map[string]*string{"Qty": nil, "Remark": nil})
+
+	suite.Equal([]logactivity.Change{{Field: "Qty", OldValue: &value}}, changes)
+}
+
+func TestAuditTrailSuite(t *testing.T) {
+	suite.Run(t, new(AuditTrailSuite))
+}
@@ -0,0 +1,218 @@
+package sqlx
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/batch"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+// BatchRepository mengelola master batch. BatchNo di dokumen tetap free-text,
+// batch yang belum punya master dianggap tidak punya tanggal kedaluwarsa.
+//
+//	CREATE TABLE tblbatch (
+//		ItCode VARCHAR(40) NOT NULL,
+//		BatchNo VARCHAR(60) NOT NULL,
+//		MfgDt VARCHAR(8) NULL,
+//		ExpDt VARCHAR(8) NULL,
+//		VendorLot VARCHAR(60) NULL,
+//		Remark VARCHAR(400) NULL,
+//		CreateBy VARCHAR(16) NOT NULL,
+//		CreateDt VARCHAR(12) NOT NULL,
+//		LastUpBy VARCHAR(16) NULL,
+//		LastUpDt VARCHAR(12) NULL,
+//		PRIMARY KEY (ItCode, BatchNo),
+//		KEY idx_batch_exp (ExpDt)
+//	);
+type BatchRepository struct {
+	DB *repository.Sqlx `inject:"database"`
+}
+
+func (t *BatchRepository) Fetch(ctx context.Context, itemCode, batchNo string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	var totalRecords int
+	args := []interface{}{"%" + itemCode + "%", "%" + batchNo + "%"}
+
+	countQuery := "SELECT COUNT(*) FROM tblbatch b WHERE b.ItCode LIKE ? AND b.BatchNo LIKE ?"
+	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+
+	var totalPages, offset int
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+	}
+
+	data := make([]*batch.Read, 0)
+	query := `SELECT
+			b.ItCode,
+			i.ItName,
+			b.BatchNo,
+			b.MfgDt,
+			b.ExpDt,
+			b.VendorLot,
+			b.Remark,
+			b.CreateDt
+		FROM tblbatch b
+		JOIN tblitem i ON b.ItCode = i.ItCode
+		WHERE b.ItCode LIKE ? AND b.BatchNo LIKE ?
+		ORDER BY b.ItCode, b.ExpDt IS NULL, b.ExpDt, b.BatchNo
+		LIMIT ? OFFSET ?`
+	if err := t.DB.SelectContext(ctx, &data, query, append(args, param.PageSize, offset)...); err != nil {
+		return nil, fmt.Errorf("error Fetch batch: %w", err)
+	}
+
+	j := offset
+	for _, d := range data {
+		j++
+		d.Number = uint(j)
+		if d.ManufacturingDate.Valid {
+			d.ManufacturingDate.String = share.FormatDate(d.ManufacturingDate.String)
+		}
+		if d.ExpiredDate.Valid {
+			d.ExpiredDate.String = share.FormatDate(d.ExpiredDate.String)
+		}
+		d.CreateDate = share.FormatDate(d.CreateDate)
+	}
+
+	return &pagination.PaginationResponse{
+		Data:         data,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}, nil
+}
+
+// Save membuat atau mengganti master batch, CreateBy/CreateDt dipertahankan
+// kalau batch sudah ada
+func (t *BatchRepository) Save(ctx context.Context, data *batch.Save) (*batch.Save, error) {
+	query := `INSERT INTO tblbatch (
+			ItCode,
+			BatchNo,
+			MfgDt,
+			ExpDt,
+			VendorLot,
+			Remark,
+			CreateBy,
+			CreateDt
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			MfgDt = VALUES(MfgDt),
+			ExpDt = VALUES(ExpDt),
+			VendorLot = VALUES(VendorLot),
+			Remark = VALUES(Remark),
+			LastUpBy = VALUES(CreateBy),
+			LastUpDt = VALUES(CreateDt)`
+	if _, err := t.DB.ExecContext(ctx, query,
+		data.ItemCode,
+		data.Batch,
+		data.ManufacturingDate,
+		data.ExpiredDate,
+		data.VendorLot,
+		data.Remark,
+		data.CreateBy,
+		data.CreateDate,
+	); err != nil {
+		log.Printf("Detailed error: %+v", err)
+		return nil, fmt.Errorf("error Save Batch: %w", err)
+	}
+
+	return data, nil
+}
+
+// Expiring mengambil saldo batch yang kedaluwarsa sampai tanggal until
+// (yyyymmdd), termasuk yang sudah lewat
+func (t *BatchRepository) Expiring(ctx context.Context, warehouse, until string) ([]*batch.Expiring, error) {
+	filters := []string{"b.ExpDt <= ?"}
+	args := []interface{}{until}
+	if warehouse != "" {
+		filters = append(filters, "s.WhsCode = ?")
+		args = append(args, warehouse)
+	}
+	if scope, scopeArgs := warehouseScope(ctx, "s.WhsCode"); scope != "" {
+		filters = append(filters, scope)
+		args = append(args, scopeArgs...)
+	}
+
+	data := make([]*batch.Expiring, 0)
+	query := `SELECT
+			s.WhsCode,
+			w.WhsName,
+			s.ItCode,
+			i.ItName,
+			s.BatchNo,
+			COALESCE(b.VendorLot, '') AS VendorLot,
+			b.ExpDt,
+			SUM(s.Qty + s.Qty2 - s.Qty3) AS Stock,
+			u.UomName
+		FROM tblstocksummary s
+		JOIN tblbatch b ON s.ItCode = b.ItCode AND s.BatchNo = b.BatchNo
+		JOIN tblitem i ON s.ItCode = i.ItCode
+		JOIN tbluom u ON i.InventoryUOMCode = u.UomCode
+		JOIN tblwarehouse w ON s.WhsCode = w.WhsCode
+		WHERE ` + strings.Join(filters, " AND ") + `
+		GROUP BY s.WhsCode, w.WhsName, s.ItCode, i.ItName, s.BatchNo, b.VendorLot, b.ExpDt, u.UomName
+		HAVING SUM(s.Qty + s.Qty2 - s.Qty3) > 0
+		ORDER BY b.ExpDt, s.WhsCode, s.ItCode, s.BatchNo`
+	if err := t.DB.SelectContext(ctx, &data, query, args...); err != nil {
+		return nil, fmt.Errorf("error fetch expiring stock: %w", err)
+	}
+
+	return data, nil
+}
+
+// CheckExpired menolak pengeluaran batch yang ExpDt-nya sebelum tanggal
+// dokumen atau tanggal server hari ini, mana yang lebih akhir, supaya dokumen
+// yang di-backdate tidak bisa mengeluarkan batch yang sudah expired. Batch
+// tanpa master atau tanpa ExpDt lolos.
+func (t *BatchRepository) CheckExpired(ctx context.Context, tx *sqlx.Tx, docDt string, lines []batch.Line) error {
+	var pairs []string
+	args := []interface{}{max(docDt, time.Now().Format("20060102"))}
+	for _, l := range lines {
+		if l.Batch == "" {
+			continue
+		}
+		pairs = append(pairs, "(?, ?)")
+		args = append(args, l.ItemCode, l.Batch)
+	}
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	var expired []struct {
+		ItCode  string `db:"ItCode"`
+		BatchNo string `db:"BatchNo"`
+		ExpDt   string `db:"ExpDt"`
+	}
+	query := `SELECT ItCode, BatchNo, ExpDt
+		FROM tblbatch
+		WHERE ExpDt < ?
+		AND (ItCode, BatchNo) IN (` + strings.Join(pairs, ",") + `)
+		ORDER BY ExpDt
+		LIMIT 1`
+	if err := tx.SelectContext(ctx, &expired, query, args...); err != nil {
+		return fmt.Errorf("error check batch expiry: %w", err)
+	}
+	if len(expired) > 0 {
+		e := expired[0]
+		return fmt.Errorf("%w: %s batch %s expired on %s", customerrors.ErrBatchExpired, e.ItCode, e.BatchNo, share.FormatDate(e.ExpDt))
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/batch"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+const queryBatchExpired = "SELECT ItCode, BatchNo, ExpDt FROM tblbatch WHERE ExpDt < ? AND (ItCode, BatchNo) IN ((?, ?)) ORDER BY ExpDt LIMIT 1"
+
+type BatchRepositorySuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	repo    *BatchRepository
+	db      *sqlx.DB
+}
+
+func (suite *BatchRepositorySuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.repo = &BatchRepository{
+		DB: &repository.Sqlx{DB: suite.db},
+	}
+}
+
+func (suite *BatchRepositorySuite) TearDownTest() {
+	suite.db.Close()
+}
+
+// baris tanpa batch tidak ikut dicek
+func (suite *BatchRepositorySuite) TestCheckExpired_RejectsExpiredBatch() {
+	suite.mockSQL.ExpectBegin()
+	tx, err := suite.db.Beginx()
+	suite.Require().NoError(err)
+
+	suite.mockSQL.ExpectQuery(queryBatchExpired).
+		WithArgs("20990115", "IT001", "B1").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "BatchNo", "ExpDt"}).AddRow("IT001", "B1", "20990110"))
+
+	err = suite.repo.CheckExpired(context.Background(), tx, "20990115", []batch.Line{
+		{ItemCode: "IT001", Batch: "B1"},
+		{ItemCode: "IT002"},
+	})
+
+	suite.ErrorIs(err, customerrors.ErrBatchExpired)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// dokumen backdate tetap dicek terhadap tanggal server
+func (suite *BatchRepositorySuite) TestCheckExpired_BackdatedUsesToday() {
+	suite.mockSQL.ExpectBegin()
+	tx, err := suite.db.Beginx()
+	suite.Require().NoError(err)
+
+	suite.mockSQL.ExpectQuery(queryBatchExpired).
+		WithArgs(time.Now().Format("20060102"), "IT001", "B1").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "BatchNo", "ExpDt"}))
+
+	err = suite.repo.CheckExpired(context.Background(), tx, "20200101", []batch.Line{{ItemCode: "IT001", Batch: "B1"}})
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func (suite *BatchRepositorySuite) TestCheckExpired_NoBatchSkipsQuery() {
+	suite.mockSQL.ExpectBegin()
+	tx, err := suite.db.Beginx()
+	suite.Require().NoError(err)
+
+	err = suite.repo.CheckExpired(context.Background(), tx, "20260115", []batch.Line{{ItemCode: "IT001"}})
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func TestBatchRepositorySuite(t *testing.T) {
+	suite.Run(t, new(BatchRepositorySuite))
+}
@@ -0,0 +1,787 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/bin"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+// BinRepository mengelola master bin per gudang, saran put-away untuk dokumen
+// penerimaan, pick list untuk dokumen pengeluaran dan perpindahan antar bin.
+// Saldo per bin dibaca dari kolom Bin di tblstocksummary.
+//
+//	CREATE TABLE tblbin (
+//		BinCode VARCHAR(16) NOT NULL PRIMARY KEY,
+//		WhsCode VARCHAR(16) NOT NULL,
+//		Zone VARCHAR(10) NOT NULL,
+//		Aisle VARCHAR(10) NOT NULL,
+//		Rack VARCHAR(10) NOT NULL,
+//		Level VARCHAR(10) NOT NULL,
+//		Capacity DECIMAL(18,4) NOT NULL DEFAULT 0,
+//		ActInd CHAR(1) NOT NULL DEFAULT 'Y',
+//		Remark VARCHAR(400) NULL,
+//		CreateBy VARCHAR(16) NOT NULL,
+//		CreateDt VARCHAR(12) NOT NULL,
+//		LastUpBy VARCHAR(16) NULL,
+//		LastUpDt VARCHAR(12) NULL,
+//		KEY idx_bin_whs (WhsCode, Zone, Aisle, Rack, Level)
+//	);
+//
+//	CREATE TABLE tblbintransferhdr (
+//		DocNo VARCHAR(30) NOT NULL PRIMARY KEY,
+//		DocDt VARCHAR(8) NOT NULL,
+//		WhsCode VARCHAR(16) NOT NULL,
+//		Remark VARCHAR(400) NULL,
+//		CreateBy VARCHAR(16) NOT NULL,
+//		CreateDt VARCHAR(12) NOT NULL
+//	);
+//
+//	CREATE TABLE tblbintransferdtl (
+//		DocNo VARCHAR(30) NOT NULL,
+//		DNo VARCHAR(6) NOT NULL,
+//		ItCode VARCHAR(40) NOT NULL,
+//		BatchNo VARCHAR(60) NOT NULL,
+//		Source VARCHAR(80) NOT NULL,
+//		BinFrom VARCHAR(16) NOT NULL,
+//		BinTo VARCHAR(16) NOT NULL,
+//		Qty DECIMAL(18,4) NOT NULL,
+//		Remark VARCHAR(400) NULL,
+//		CreateBy VARCHAR(16) NOT NULL,
+//		CreateDt VARCHAR(12) NOT NULL,
+//		PRIMARY KEY (DocNo, DNo)
+//	);
+type BinRepository struct {
+	DB     *repository.Sqlx            `inject:"database"`
+	Ledger inventoryledger.Repository  `inject:"inventoryLedgerRepository"`
+	ID     *formatid.GenerateIDHandler `inject:"generateID"`
+}
+
+const binSelect = `SELECT
+		b.BinCode,
+		b.WhsCode,
+		w.WhsName,
+		b.Zone,
+		b.Aisle,
+		b.Rack,
+		b.Level,
+		b.Capacity,
+		COALESCE((
+			SELECT SUM(s.Qty + s.Qty2 - s.Qty3)
+			FROM tblstocksummary s
+			WHERE s.WhsCode = b.WhsCode AND s.Bin = b.BinCode
+		), 0) AS Occupied,
+		b.ActInd,
+		b.Remark,
+		b.CreateDt
+	FROM tblbin b
+	JOIN tblwarehouse w ON b.WhsCode = w.WhsCode`
+
+func (t *BinRepository) Fetch(ctx context.Context, warehouse, search string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	var totalRecords int
+
+	filters := []string{"b.WhsCode LIKE ?", "b.BinCode LIKE ?"}
+	args := []interface{}{"%" + warehouse + "%", "%" + search + "%"}
+	if scope, scopeArgs := warehouseScope(ctx, "b.WhsCode"); scope != "" {
+		filters = append(filters, scope)
+		args = append(args, scopeArgs...)
+	}
+	where := strings.Join(filters, " AND ")
+
+	countQuery := "SELECT COUNT(*) FROM tblbin b WHERE " + where
+	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+
+	var totalPages int
+	var offset int
+
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+		offset = 0
+	}
+
+	var data []*bin.Read
+	query := binSelect + " WHERE " + where + " ORDER BY b.WhsCode, b.Zone, b.Aisle, b.Rack, b.Level, b.BinCode LIMIT ? OFFSET ?"
+	if err := t.DB.SelectContext(ctx, &data, query, append(args, param.PageSize, offset)...); err != nil {
+		return nil, fmt.Errorf("error fetch bin: %w", err)
+	}
+
+	j := offset
+	for _, d := range data {
+		j++
+		d.Number = uint(j)
+		d.CreateDate = share.FormatDate(d.CreateDate)
+	}
+	if data == nil {
+		data = []*bin.Read{}
+	}
+
+	return &pagination.PaginationResponse{
+		Data:         data,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}, nil
+}
+
+func (t *BinRepository) Detail(ctx context.Context, code string) (*bin.Read, error) {
+	var detail bin.Read
+	if err := t.DB.GetContext(ctx, &detail, binSelect+" WHERE b.BinCode = ?", code); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrDataNotFound
+		}
+		return nil, fmt.Errorf("error detail bin: %w", err)
+	}
+	if err := checkWarehouse(ctx, detail.WarehouseCode); err != nil {
+		return nil, err
+	}
+	detail.CreateDate = share.FormatDate(detail.CreateDate)
+
+	return &detail, nil
+}
+
+func (t *BinRepository) Create(ctx context.Context, data *bin.Create) (*bin.Create, error) {
+	if err := checkWarehouse(ctx, data.WarehouseCode); err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO tblbin (
+			BinCode,
+			WhsCode,
+			Zone,
+			Aisle,
+			Rack,
+			Level,
+			Capacity,
+			ActInd,
+			Remark,
+			CreateBy,
+			CreateDt
+		) VALUES (?, ?, ?, ?, ?, ?, ?, 'Y', ?, ?, ?)`
+	if _, err := t.DB.ExecContext(ctx, query, data.BinCode, data.WarehouseCode, data.Zone, data.Aisle, data.Rack, data.Level, data.Capacity, data.Remark, data.CreateBy, data.CreateDate); err != nil {
+		log.Printf("Error insert bin: %+v", err)
+		return nil, fmt.Errorf("error Insert Bin: %w", err)
+	}
+
+	return data, nil
+}
+
+func (t *BinRepository) Update(ctx context.Context, data *bin.Update) (*bin.Update, error) {
+	var whsCode string
+	if err := t.DB.GetContext(ctx, &whsCode, "SELECT WhsCode FROM tblbin WHERE BinCode = ?", data.BinCode); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrDataNotFound
+		}
+		return nil, fmt.Errorf("error get bin: %w", err)
+	}
+	if err := checkWarehouse(ctx, whsCode); err != nil {
+		return nil, err
+	}
+
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	var audit *auditTrail
+	if audit, err = newAuditTrail(ctx, tx, "Bin", data.BinCode); err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE tblbin
+		SET Zone = ?, Aisle = ?, Rack = ?, Level = ?, Capacity = ?, ActInd = ?, Remark = ?, LastUpBy = ?, LastUpDt = ?
+		WHERE BinCode = ?`
+	if _, err = tx.ExecContext(ctx, query, data.Zone, data.Aisle, data.Rack, data.Level, data.Capacity, data.Active, data.Remark, data.LastUpBy, data.LastUpdateDate, data.BinCode); err != nil {
+		log.Printf("Error update bin: %+v", err)
+		return nil, fmt.Errorf("error Update Bin: %w", err)
+	}
+
+	if err = audit.Write(ctx, tx, data.LastUpBy, data.LastUpdateDate); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return data, nil
+}
+
+// Stock menampilkan saldo per bin dan source. Minimal salah satu filter gudang,
+// bin atau item diisi oleh service.
+func (t *BinRepository) Stock(ctx context.Context, warehouse, binCode, itemCode string) ([]*bin.Stock, error) {
+	var filters []string
+	var args []interface{}
+	if warehouse != "" {
+		filters = append(filters, "s.WhsCode = ?")
+		args = append(args, warehouse)
+	}
+	if binCode != "" {
+		filters = append(filters, "s.Bin = ?")
+		args = append(args, binCode)
+	}
+	if itemCode != "" {
+		filters = append(filters, "s.ItCode = ?")
+		args = append(args, itemCode)
+	}
+	if scope, scopeArgs := warehouseScope(ctx, "s.WhsCode"); scope != "" {
+		filters = append(filters, scope)
+		args = append(args, scopeArgs...)
+	}
+
+	var result []*bin.Stock
+	query := binStockSelect + " WHERE " + strings.Join(filters, " AND ") + `
+		GROUP BY s.WhsCode, s.Bin, b.Zone, b.Aisle, b.Rack, b.Level, s.ItCode, i.ItName, s.BatchNo, s.Source
+		HAVING SUM(s.Qty + s.Qty2 - s.Qty3) > 0
+		ORDER BY s.WhsCode, ` + binPickOrder + `, s.ItCode, s.BatchNo, s.Source`
+	if err := t.DB.SelectContext(ctx, &result, query, args...); err != nil {
+		return nil, fmt.Errorf("error fetch bin stock: %w", err)
+	}
+	if result == nil {
+		result = []*bin.Stock{}
+	}
+
+	return result, nil
+}
+
+const binStockSelect = `SELECT
+		s.WhsCode,
+		s.Bin,
+		b.Zone,
+		b.Aisle,
+		b.Rack,
+		b.Level,
+		s.ItCode,
+		i.ItName,
+		s.BatchNo,
+		s.Source,
+		SUM(s.Qty + s.Qty2 - s.Qty3) AS Qty
+	FROM tblstocksummary s
+	JOIN tblitem i ON s.ItCode = i.ItCode
+	LEFT JOIN tblbin b ON s.WhsCode = b.WhsCode AND s.Bin = b.BinCode`
+
+// putAwayDocs dan pickDocs adalah query baris dokumen per jenis dokumen,
+// kolomnya mengikuti bin.DocLine.
+var putAwayDocs = map[string]string{
+	bin.DocPurchaseMaterialReceive: `SELECT h.WhsCode, d.DNo, d.ItCode, i.ItName, d.BatchNo, d.Source, d.PurchaseQty AS Qty
+		FROM tblpurchasematerialreceivehdr h
+		JOIN tblpurchasematerialreceivedtl d ON h.DocNo = d.DocNo
+		JOIN tblitem i ON d.ItCode = i.ItCode
+		WHERE h.DocNo = ? AND d.CancelInd = 'N'
+		ORDER BY d.DNo`,
+	bin.DocMaterialReceive: `SELECT h.WhsCodeTo AS WhsCode, d.DNo, d.ItCode, i.ItName, d.BatchNo, d.Source, d.QtyActual AS Qty
+		FROM tblmaterialreceivehdr h
+		JOIN tblmaterialreceivedtl d ON h.DocNo = d.DocNo
+		JOIN tblitem i ON d.ItCode = i.ItCode
+		WHERE h.DocNo = ? AND d.CancelInd = 'N'
+		ORDER BY d.DNo`,
+}
+
+var pickDocs = map[string]string{
+	bin.DocDirectSalesDelivery: `SELECT h.WhsCode, d.DNo, d.ItCode, i.ItName, d.BatchNo, d.Source, d.Qty
+		FROM tbldirectsalesdelivhdr h
+		JOIN tbldirectsalesdelivdtl d ON h.DocNo = d.DocNo
+		JOIN tblitem i ON d.ItCode = i.ItCode
+		WHERE h.DocNo = ? AND d.CancelInd = 'N'
+		ORDER BY d.DNo`,
+	bin.DocMaterialTransfer: `SELECT h.WhsCodeFrom AS WhsCode, d.DNo, d.ItCode, i.ItName, d.BatchNo, '' AS Source, d.Qty
+		FROM tblmaterialtransferhdr h
+		JOIN tblmaterialtransferdtl d ON h.DocNo = d.DocNo
+		JOIN tblitem i ON d.ItCode = i.ItCode
+		WHERE h.DocNo = ? AND d.CancelInd = 'N'
+		ORDER BY d.DNo`,
+}
+
+// docLines mengambil baris dokumen untuk put-away / pick list. Semua baris
+// satu dokumen berada di satu gudang.
+func (t *BinRepository) docLines(ctx context.Context, queries map[string]string, docType, docNo string) ([]*bin.DocLine, string, error) {
+	query, ok := queries[docType]
+	if !ok {
+		return nil, "", fmt.Errorf("%w: unknown document type %s", customerrors.ErrInvalidInput, docType)
+	}
+
+	var lines []*bin.DocLine
+	if err := t.DB.SelectContext(ctx, &lines, query, docNo); err != nil {
+		return nil, "", fmt.Errorf("error get document lines: %w", err)
+	}
+	if len(lines) == 0 {
+		return nil, "", customerrors.ErrDataNotFound
+	}
+	if err := checkWarehouse(ctx, lines[0].WhsCode); err != nil {
+		return nil, "", err
+	}
+
+	return lines, lines[0].WhsCode, nil
+}
+
+type binSpace struct {
+	BinCode  string  `db:"BinCode"`
+	Zone     string  `db:"Zone"`
+	Aisle    string  `db:"Aisle"`
+	Rack     string  `db:"Rack"`
+	Level    string  `db:"Level"`
+	Capacity float32 `db:"Capacity"`
+	Occupied float32 `db:"Occupied"`
+}
+
+// PutAway menyarankan bin untuk setiap baris penerimaan. Bin yang sudah berisi
+// item yang sama didahulukan, lalu bin lain sesuai urutan zone / aisle / rack /
+// level. Kapasitas yang sudah disarankan untuk baris sebelumnya ikut dihitung.
+func (t *BinRepository) PutAway(ctx context.Context, docType, docNo string) (*bin.PutAway, error) {
+	lines, whsCode, err := t.docLines(ctx, putAwayDocs, docType, docNo)
+	if err != nil {
+		return nil, err
+	}
+
+	var spaces []*binSpace
+	query := `SELECT b.BinCode, b.Zone, b.Aisle, b.Rack, b.Level, b.Capacity,
+			COALESCE(SUM(s.Qty + s.Qty2 - s.Qty3), 0) AS Occupied
+		FROM tblbin b
+		LEFT JOIN tblstocksummary s ON b.WhsCode = s.WhsCode AND b.BinCode = s.Bin
+		WHERE b.WhsCode = ? AND b.ActInd = 'Y'
+		GROUP BY b.BinCode, b.Zone, b.Aisle, b.Rack, b.Level, b.Capacity
+		ORDER BY b.Zone, b.Aisle, b.Rack, b.Level, b.BinCode`
+	if err := t.DB.SelectContext(ctx, &spaces, query, whsCode); err != nil {
+		return nil, fmt.Errorf("error get bin capacity: %w", err)
+	}
+
+	items := make([]interface{}, 0, len(lines))
+	placeholders := make([]string, 0, len(lines))
+	for _, line := range lines {
+		items = append(items, line.ItCode)
+		placeholders = append(placeholders, "?")
+	}
+
+	var holdings []struct {
+		Bin    string `db:"Bin"`
+		ItCode string `db:"ItCode"`
+	}
+	query = `SELECT s.Bin, s.ItCode
+		FROM tblstocksummary s
+		WHERE s.WhsCode = ? AND s.Bin <> '-' AND s.ItCode IN (` + strings.Join(placeholders, ",") + `)
+		GROUP BY s.Bin, s.ItCode
+		HAVING SUM(s.Qty + s.Qty2 - s.Qty3) > 0`
+	if err := t.DB.SelectContext(ctx, &holdings, query, append([]interface{}{whsCode}, items...)...); err != nil {
+		return nil, fmt.Errorf("error get bin holding: %w", err)
+	}
+
+	holding := make(map[string]map[string]bool)
+	for _, h := range holdings {
+		if holding[h.ItCode] == nil {
+			holding[h.ItCode] = make(map[string]bool)
+		}
+		holding[h.ItCode][h.Bin] = true
+	}
+
+	return &bin.PutAway{
+		DocType:       docType,
+		DocNo:         docNo,
+		WarehouseCode: whsCode,
+		Lines:         suggestPutAway(lines, spaces, holding),
+	}, nil
+}
+
+func suggestPutAway(lines []*bin.DocLine, spaces []*binSpace, holding map[string]map[string]bool) []*bin.PutAwayLine {
+	result := make([]*bin.PutAwayLine, 0, len(lines))
+	for _, line := range lines {
+		ordered := make([]*binSpace, len(spaces))
+		copy(ordered, spaces)
+		// stable, jadi urutan lokasi dari query tetap berlaku di tiap kelompok
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return holding[line.ItCode][ordered[i].BinCode] && !holding[line.ItCode][ordered[j].BinCode]
+		})
+
+		suggestion := &bin.PutAwayLine{
+			DNo:         line.DNo,
+			ItemCode:    line.ItCode,
+			ItemName:    line.ItName,
+			Batch:       line.BatchNo,
+			Source:      line.Source,
+			Qty:         line.Qty,
+			Suggestions: []*bin.Location{},
+		}
+
+		qty := line.Qty
+		for _, space := range ordered {
+			if qty <= binTolerance {
+				break
+			}
+			take := qty
+			if space.Capacity > 0 {
+				take = min(qty, space.Capacity-space.Occupied)
+			}
+			if take <= 0 {
+				continue
+			}
+			space.Occupied += take
+			qty -= take
+			suggestion.Suggestions = append(suggestion.Suggestions, &bin.Location{
+				BinCode: space.BinCode,
+				Zone:    space.Zone,
+				Aisle:   space.Aisle,
+				Rack:    space.Rack,
+				Level:   space.Level,
+				Qty:     take,
+			})
+		}
+		if qty > binTolerance {
+			suggestion.Unplaced = qty
+		}
+
+		result = append(result, suggestion)
+	}
+
+	return result
+}
+
+// PickList menyusun langkah pengambilan untuk dokumen pengeluaran dengan urutan
+// yang sama seperti pengurangan saldo di inventory ledger, lalu diurutkan per
+// bin supaya picker cukup sekali jalan.
+func (t *BinRepository) PickList(ctx context.Context, docType, docNo string) (*bin.PickList, error) {
+	lines, whsCode, err := t.docLines(ctx, pickDocs, docType, docNo)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]interface{}, 0, len(lines)+1)
+	items = append(items, whsCode)
+	placeholders := make([]string, 0, len(lines))
+	for _, line := range lines {
+		items = append(items, line.ItCode)
+		placeholders = append(placeholders, "?")
+	}
+
+	var stocks []*bin.Stock
+	query := binStockSelect + `
+		WHERE s.WhsCode = ? AND s.ItCode IN (` + strings.Join(placeholders, ",") + `)
+		GROUP BY s.WhsCode, s.Bin, b.Zone, b.Aisle, b.Rack, b.Level, s.ItCode, i.ItName, s.BatchNo, s.Source
+		HAVING SUM(s.Qty + s.Qty2 - s.Qty3) > 0
+		ORDER BY ` + binPickOrder + `, s.Source`
+	if err := t.DB.SelectContext(ctx, &stocks, query, items...); err != nil {
+		return nil, fmt.Errorf("error get bin stock: %w", err)
+	}
+
+	result := &bin.PickList{
+		DocType:       docType,
+		DocNo:         docNo,
+		WarehouseCode: whsCode,
+	}
+	result.Lines, result.Shortage = pickFromBins(lines, stocks)
+
+	return result, nil
+}
+
+// pickFromBins mengalokasikan baris dokumen ke saldo bin (sudah urut pick order)
+// lalu mengelompokkan hasilnya per bin sesuai urutan bin tersebut.
+func pickFromBins(lines []*bin.DocLine, stocks []*bin.Stock) ([]*bin.PickLine, float32) {
+	position := make(map[string]int)
+	for i, s := range stocks {
+		if _, ok := position[s.BinCode]; !ok {
+			position[s.BinCode] = i
+		}
+	}
+
+	var shortage float32
+	picks := []*bin.PickLine{}
+	for _, line := range lines {
+		qty := line.Qty
+		for _, s := range stocks {
+			if qty <= binTolerance {
+				break
+			}
+			if s.Qty <= 0 || s.ItemCode != line.ItCode || s.Batch != line.BatchNo || (line.Source != "" && s.Source != line.Source) {
+				continue
+			}
+			take := min(s.Qty, qty)
+			s.Qty -= take
+			qty -= take
+			picks = append(picks, &bin.PickLine{
+				BinCode:  s.BinCode,
+				Zone:     s.Zone.String,
+				Aisle:    s.Aisle.String,
+				Rack:     s.Rack.String,
+				Level:    s.Level.String,
+				DNo:      line.DNo,
+				ItemCode: line.ItCode,
+				ItemName: line.ItName,
+				Batch:    line.BatchNo,
+				Source:   s.Source,
+				Qty:      take,
+			})
+		}
+		if qty > binTolerance {
+			shortage += qty
+		}
+	}
+
+	sort.SliceStable(picks, func(i, j int) bool {
+		return position[picks[i].BinCode] < position[picks[j].BinCode]
+	})
+	for i, pick := range picks {
+		pick.Number = uint(i + 1)
+	}
+
+	return picks, shortage
+}
+
+func (t *BinRepository) FetchTransfer(ctx context.Context, doc, warehouse string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	var totalRecords int
+
+	filters := []string{"h.DocNo LIKE ?", "h.WhsCode LIKE ?"}
+	args := []interface{}{"%" + doc + "%", "%" + warehouse + "%"}
+	if scope, scopeArgs := warehouseScope(ctx, "h.WhsCode"); scope != "" {
+		filters = append(filters, scope)
+		args = append(args, scopeArgs...)
+	}
+	where := strings.Join(filters, " AND ")
+
+	countQuery := "SELECT COUNT(*) FROM tblbintransferhdr h WHERE " + where
+	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+
+	var totalPages int
+	var offset int
+
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+		offset = 0
+	}
+
+	var data []*bin.Transfer
+	query := binTransferSelect + " WHERE " + where + " ORDER BY h.CreateDt DESC LIMIT ? OFFSET ?"
+	if err := t.DB.SelectContext(ctx, &data, query, append(args, param.PageSize, offset)...); err != nil {
+		return nil, fmt.Errorf("error fetch bin transfer: %w", err)
+	}
+
+	j := offset
+	for _, d := range data {
+		j++
+		d.Number = uint(j)
+		d.Date = share.FormatDate(d.Date)
+	}
+	if data == nil {
+		data = []*bin.Transfer{}
+	}
+
+	return &pagination.PaginationResponse{
+		Data:         data,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}, nil
+}
+
+const binTransferSelect = `SELECT
+		h.DocNo,
+		h.DocDt,
+		h.WhsCode,
+		w.WhsName,
+		h.Remark,
+		h.CreateBy
+	FROM tblbintransferhdr h
+	JOIN tblwarehouse w ON h.WhsCode = w.WhsCode`
+
+func (t *BinRepository) DetailTransfer(ctx context.Context, docNo string) (*bin.Transfer, error) {
+	var detail bin.Transfer
+	if err := t.DB.GetContext(ctx, &detail, binTransferSelect+" WHERE h.DocNo = ?", docNo); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrDataNotFound
+		}
+		return nil, fmt.Errorf("error detail bin transfer: %w", err)
+	}
+	if err := checkWarehouse(ctx, detail.WarehouseCode); err != nil {
+		return nil, err
+	}
+	detail.Date = share.FormatDate(detail.Date)
+
+	query := `SELECT d.DNo, d.ItCode, i.ItName, d.BatchNo, d.Source, d.BinFrom, d.BinTo, d.Qty, d.Remark
+		FROM tblbintransferdtl d
+		JOIN tblitem i ON d.ItCode = i.ItCode
+		WHERE d.DocNo = ?
+		ORDER BY d.DNo`
+	if err := t.DB.SelectContext(ctx, &detail.Details, query, docNo); err != nil {
+		return nil, fmt.Errorf("error detail bin transfer lines: %w", err)
+	}
+
+	return &detail, nil
+}
+
+// CreateTransfer memindahkan stok antar bin dalam satu gudang. Setiap baris
+// menjadi dua movement: keluar dari BinFrom dan masuk ke BinTo dengan source
+// yang sama. Saldo gudang tidak berubah, jadi movement-nya tidak divaluasi.
+func (t *BinRepository) CreateTransfer(ctx context.Context, data *bin.Transfer) (*bin.Transfer, error) {
+	if err := checkWarehouse(ctx, data.WarehouseCode); err != nil {
+		return nil, err
+	}
+
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	// bin tujuan / asal harus bin aktif di gudang yang sama
+	codes := make(map[string]bool)
+	for _, d := range data.Details {
+		for _, code := range []string{d.BinFrom, d.BinTo} {
+			if code != bin.Unassigned {
+				codes[code] = true
+			}
+		}
+	}
+	if len(codes) > 0 {
+		placeholders := make([]string, 0, len(codes))
+		args := []interface{}{data.WarehouseCode}
+		for code := range codes {
+			placeholders = append(placeholders, "?")
+			args = append(args, code)
+		}
+
+		var found int
+		query := "SELECT COUNT(*) FROM tblbin WHERE WhsCode = ? AND ActInd = 'Y' AND BinCode IN (" + strings.Join(placeholders, ",") + ")"
+		if err = tx.GetContext(ctx, &found, query, args...); err != nil {
+			return nil, fmt.Errorf("error check bin: %w", err)
+		}
+		if found != len(codes) {
+			err = fmt.Errorf("%w: bin is not an active bin of warehouse %s", customerrors.ErrInvalidInput, data.WarehouseCode)
+			return nil, err
+		}
+	}
+
+	data.DocNo, err = t.ID.GenerateID(ctx, tx, "BinTransfer", data.Date)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
+	query := `INSERT INTO tblbintransferhdr (
+			DocNo,
+			DocDt,
+			WhsCode,
+			Remark,
+			CreateBy,
+			CreateDt
+		) VALUES (?, ?, ?, ?, ?, ?)`
+	if _, err = tx.ExecContext(ctx, query, data.DocNo, data.Date, data.WarehouseCode, data.Remark, data.CreateBy, data.CreateDate); err != nil {
+		log.Printf("Error insert bin transfer header: %+v", err)
+		return nil, fmt.Errorf("error Insert Header: %w", err)
+	}
+
+	var placeholders []string
+	var args []interface{}
+	var movements []inventoryledger.Movement
+	for i := range data.Details {
+		d := &data.Details[i]
+		d.DNo = fmt.Sprintf("%03d", i+1)
+
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		args = append(args, data.DocNo, d.DNo, d.ItemCode, d.Batch, d.Source, d.BinFrom, d.BinTo, d.Qty, d.Remark, data.CreateBy, data.CreateDate)
+
+		for _, side := range []struct {
+			docType   string
+			bin       string
+			direction inventoryledger.Direction
+		}{
+			{"Bin Transfer (From)", d.BinFrom, inventoryledger.Out},
+			{"Bin Transfer (To)", d.BinTo, inventoryledger.In},
+		} {
+			movements = append(movements, inventoryledger.Movement{
+				DocType:   side.docType,
+				DocNo:     data.DocNo,
+				DNo:       d.DNo,
+				DocDt:     data.Date,
+				WhsCode:   data.WarehouseCode,
+				Bin:       side.bin,
+				Source:    d.Source,
+				ItCode:    d.ItemCode,
+				BatchNo:   d.Batch,
+				Qty:       d.Qty,
+				Direction: side.direction,
+				BinMove:   true,
+				Remark:    d.Remark,
+				CreateBy:  data.CreateBy,
+				CreateDt:  data.CreateDate,
+			})
+		}
+	}
+
+	query = `INSERT INTO tblbintransferdtl (
+			DocNo,
+			DNo,
+			ItCode,
+			BatchNo,
+			Source,
+			BinFrom,
+			BinTo,
+			Qty,
+			Remark,
+			CreateBy,
+			CreateDt
+		) VALUES ` + strings.Join(placeholders, ",")
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		log.Printf("Error insert bin transfer detail: %+v", err)
+		return nil, fmt.Errorf("error Insert Detail: %w", err)
+	}
+
+	if err = t.Ledger.Post(ctx, tx, movements); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,111 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/bin"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+const (
+	queryPutAwayLines   = "SELECT h.WhsCode, d.DNo, d.ItCode, i.ItName, d.BatchNo, d.Source, d.PurchaseQty AS Qty FROM tblpurchasematerialreceivehdr h JOIN tblpurchasematerialreceivedtl d ON h.DocNo = d.DocNo JOIN tblitem i ON d.ItCode = i.ItCode WHERE h.DocNo = ? AND d.CancelInd = 'N' ORDER BY d.DNo"
+	queryPutAwaySpace   = "SELECT b.BinCode, b.Zone, b.Aisle, b.Rack, b.Level, b.Capacity, COALESCE(SUM(s.Qty + s.Qty2 - s.Qty3), 0) AS Occupied FROM tblbin b LEFT JOIN tblstocksummary s ON b.WhsCode = s.WhsCode AND b.BinCode = s.Bin WHERE b.WhsCode = ? AND b.ActInd = 'Y' GROUP BY b.BinCode, b.Zone, b.Aisle, b.Rack, b.Level, b.Capacity ORDER BY b.Zone, b.Aisle, b.Rack, b.Level, b.BinCode"
+	queryPutAwayHolding = "SELECT s.Bin, s.ItCode FROM tblstocksummary s WHERE s.WhsCode = ? AND s.Bin <> '-' AND s.ItCode IN (?) GROUP BY s.Bin, s.ItCode HAVING SUM(s.Qty + s.Qty2 - s.Qty3) > 0"
+	queryPickLines      = "SELECT h.WhsCode, d.DNo, d.ItCode, i.ItName, d.BatchNo, d.Source, d.Qty FROM tbldirectsalesdelivhdr h JOIN tbldirectsalesdelivdtl d ON h.DocNo = d.DocNo JOIN tblitem i ON d.ItCode = i.ItCode WHERE h.DocNo = ? AND d.CancelInd = 'N' ORDER BY d.DNo"
+	queryPickStock      = "SELECT s.WhsCode, s.Bin, b.Zone, b.Aisle, b.Rack, b.Level, s.ItCode, i.ItName, s.BatchNo, s.Source, SUM(s.Qty + s.Qty2 - s.Qty3) AS Qty FROM tblstocksummary s JOIN tblitem i ON s.ItCode = i.ItCode LEFT JOIN tblbin b ON s.WhsCode = b.WhsCode AND s.Bin = b.BinCode WHERE s.WhsCode = ? AND s.ItCode IN (?,?) GROUP BY s.WhsCode, s.Bin, b.Zone, b.Aisle, b.Rack, b.Level, s.ItCode, i.ItName, s.BatchNo, s.Source HAVING SUM(s.Qty + s.Qty2 - s.Qty3) > 0 ORDER BY s.Bin <> '-', b.Zone, b.Aisle, b.Rack, b.Level, s.Bin, s.Source"
+)
+
+type BinRepositorySuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	repo    *BinRepository
+	db      *sqlx.DB
+}
+
+func (suite *BinRepositorySuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.repo = &BinRepository{
+		DB: &repository.Sqlx{DB: suite.db},
+	}
+}
+
+func (suite *BinRepositorySuite) TearDownTest() {
+	suite.db.Close()
+}
+
+// terima 12: A-02 sudah berisi item yang sama jadi diisi dulu (sisa 5), lalu
+// A-01 (sisa 4), 3 sisanya tidak muat di bin manapun
+func (suite *BinRepositorySuite) TestPutAway_PrefersBinHoldingItem() {
+	suite.mockSQL.ExpectQuery(queryPutAwayLines).
+		WithArgs("0001/RCV").
+		WillReturnRows(sqlmock.NewRows([]string{"WhsCode", "DNo", "ItCode", "ItName", "BatchNo", "Source", "Qty"}).
+			AddRow("WHS01", "001", "IT001", "Item 1", "B1", "01*0001/RCV*001", 12))
+	suite.mockSQL.ExpectQuery(queryPutAwaySpace).
+		WithArgs("WHS01").
+		WillReturnRows(sqlmock.NewRows([]string{"BinCode", "Zone", "Aisle", "Rack", "Level", "Capacity", "Occupied"}).
+			AddRow("A-01", "A", "01", "01", "1", 10, 6).
+			AddRow("A-02", "A", "01", "01", "2", 10, 5))
+	suite.mockSQL.ExpectQuery(queryPutAwayHolding).
+		WithArgs("WHS01", "IT001").
+		WillReturnRows(sqlmock.NewRows([]string{"Bin", "ItCode"}).AddRow("A-02", "IT001"))
+
+	result, err := suite.repo.PutAway(context.Background(), bin.DocPurchaseMaterialReceive, "0001/RCV")
+
+	suite.Require().NoError(err)
+	suite.Require().Len(result.Lines, 1)
+	line := result.Lines[0]
+	suite.Require().Len(line.Suggestions, 2)
+	suite.Equal("A-02", line.Suggestions[0].BinCode)
+	suite.Equal(float32(5), line.Suggestions[0].Qty)
+	suite.Equal("A-01", line.Suggestions[1].BinCode)
+	suite.Equal(float32(4), line.Suggestions[1].Qty)
+	suite.Equal(float32(3), line.Unplaced)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// dua baris dokumen yang stoknya bersilangan di bin disusun ulang per urutan bin
+func (suite *BinRepositorySuite) TestPickList_OrderedByBin() {
+	suite.mockSQL.ExpectQuery(queryPickLines).
+		WithArgs("0001/DSDV").
+		WillReturnRows(sqlmock.NewRows([]string{"WhsCode", "DNo", "ItCode", "ItName", "BatchNo", "Source", "Qty"}).
+			AddRow("WHS01", "001", "IT001", "Item 1", "B1", "SRC1", 5).
+			AddRow("WHS01", "002", "IT002", "Item 2", "B2", "SRC2", 4))
+	suite.mockSQL.ExpectQuery(queryPickStock).
+		WithArgs("WHS01", "IT001", "IT002").
+		WillReturnRows(sqlmock.NewRows([]string{"WhsCode", "Bin", "Zone", "Aisle", "Rack", "Level", "ItCode", "ItName", "BatchNo", "Source", "Qty"}).
+			AddRow("WHS01", "A-01", "A", "01", "01", "1", "IT002", "Item 2", "B2", "SRC2", 4).
+			AddRow("WHS01", "A-02", "A", "01", "01", "2", "IT001", "Item 1", "B1", "SRC1", 3).
+			AddRow("WHS01", "B-01", "B", "01", "01", "1", "IT001", "Item 1", "B1", "SRC1", 9))
+
+	result, err := suite.repo.PickList(context.Background(), bin.DocDirectSalesDelivery, "0001/DSDV")
+
+	suite.Require().NoError(err)
+	suite.Require().Len(result.Lines, 3)
+	suite.Equal("A-01", result.Lines[0].BinCode)
+	suite.Equal("002", result.Lines[0].DNo)
+	suite.Equal("A-02", result.Lines[1].BinCode)
+	suite.Equal(float32(3), result.Lines[1].Qty)
+	suite.Equal("B-01", result.Lines[2].BinCode)
+	suite.Equal(float32(2), result.Lines[2].Qty)
+	suite.Equal(float32(0), result.Shortage)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func (suite *BinRepositorySuite) TestPickList_UnknownDocType() {
+	_, err := suite.repo.PickList(context.Background(), bin.DocPurchaseMaterialReceive, "0001/RCV")
+
+	suite.ErrorIs(err, customerrors.ErrInvalidInput)
+}
+
+func TestBinRepositorySuite(t *testing.T) {
+	suite.Run(t, new(BinRepositorySuite))
+}
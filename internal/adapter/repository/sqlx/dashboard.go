@@ -7,13 +7,15 @@ import (
 
 	"gitlab.com/ayaka/internal/adapter/repository"
 	"gitlab.com/ayaka/internal/domain/dashboard"
+	"gitlab.com/ayaka/internal/domain/exchangerate"
+	"gitlab.com/ayaka/internal/domain/intransit"
 )
 
 type DashboardRepository struct {
 	DB *repository.Sqlx `inject:"database"`
 }
 
-func (t *DashboardRepository) Fetch(ctx context.Context) (*dashboard.Read, error) {
+func (t *DashboardRepository) Fetch(ctx context.Context, base string) (*dashboard.Read, error) {
 	var dashboardRead dashboard.Read
 
 	var outstandingMaterialTransfer uint
@@ -35,6 +37,8 @@ func (t *DashboardRepository) Fetch(ctx context.Context) (*dashboard.Read, error
 			AND r.ItCode = t.ItCode
 			AND (r.BatchNo = t.BatchNo OR (r.BatchNo IS NULL AND t.BatchNo IS NULL)) -- handle batch null
 		WHERE t.CancelInd = 'N'
+		-- transfer in transit selesai begitu diterima, kekurangannya jadi discrepancy
+		AND (t.InTransitInd = 'N' OR t.SuccessInd = 'N')
 		GROUP BY 
 				t.DocNo,
 				t.DNo,
@@ -42,15 +46,21 @@ func (t *DashboardRepository) Fetch(ctx context.Context) (*dashboard.Read, error
 				i.ItName,
 				t.BatchNo,
 				t.Qty,
+				t.InTransitInd,
 				h.WhsCodeFrom,
 				h.WhsCodeTo
-		HAVING QtyRemaining > 0
+		HAVING QtyRemaining > 0 OR t.InTransitInd = 'Y'
 	) as grouped`
 
 	if err := t.DB.GetContext(ctx, &outstandingMaterialTransfer, query); err != nil {
 		return nil, fmt.Errorf("error counting records: %w", err)
 	}
 
+	if err := t.DB.GetContext(ctx, &dashboardRead.OpenTransferDiscrepancy,
+		"SELECT COUNT(*) FROM tblmaterialtransferdiscrepancy WHERE Status = ?", intransit.Open); err != nil {
+		return nil, fmt.Errorf("error counting transfer discrepancy: %w", err)
+	}
+
 
 	var outstandingPurchaseOrder uint
 	query = `SELECT COUNT(*) FROM (
@@ -130,6 +140,57 @@ func (t *DashboardRepository) Fetch(ctx context.Context) (*dashboard.Read, error
 		return nil, fmt.Errorf("error counting records: %w", err)
 	}
 
+	// nilai sisa PO, dijumlah per mata uang lalu dikonversi di tanggal PO
+	var outstandingValues []struct {
+		exchangerate.Amount
+		BaseAmount  float64 `db:"BaseAmount"`
+		RateMissing bool    `db:"RateMissing"`
+	}
+	query = `SELECT
+			o.CurCode,
+			SUM(o.OutstandingQty * o.Price) AS Amount,
+			SUM(o.OutstandingQty * o.Price * o.Rate) AS BaseAmount,
+			SUM(o.Rate = 0) > 0 AS RateMissing
+		FROM (
+			SELECT
+				vq.CurCode,
+				d.Total / NULLIF(d.Qty, 0) AS Price,
+				d.Qty - COALESCE((
+					SELECT SUM(r.PurchaseQty)
+					FROM tblpurchasematerialreceivedtl r
+					WHERE r.PurchaseOrderDocNo = d.DocNo
+					AND r.PurchaseOrderDNo = d.DNo
+					AND (r.CancelInd IS NULL OR r.CancelInd != 'Y')
+				), 0) AS OutstandingQty,
+				` + baseRateSQL("vq.CurCode", "h.DocDt") + ` AS Rate
+			FROM tblpurchaseorderdtl d
+			JOIN tblpurchaseorderhdr h ON d.DocNo = h.DocNo
+			JOIN tblpurchaseorderreqdtl por
+				ON d.PurchaseOrderReqDocNo = por.DocNo
+				AND d.PurchaseOrderReqDNo = por.DNo
+			JOIN tblmaterialrequestdtl mr
+				ON por.MaterialReqDocNo = mr.DocNo
+				AND por.MaterialReqDNo = mr.DNo
+			JOIN tblvendorquotationhdr vq
+				ON por.VendorQTDocNo = vq.DocNo
+			WHERE d.CancelInd != 'Y'
+		) o
+		WHERE o.OutstandingQty > 0
+		GROUP BY o.CurCode
+		ORDER BY o.CurCode`
+
+	if err := t.DB.SelectContext(ctx, &outstandingValues, query, baseRateArgs(base)...); err != nil {
+		return nil, fmt.Errorf("error fetch outstanding purchase order value: %w", err)
+	}
+
+	dashboardRead.BaseCurrency = base
+	dashboardRead.OutstandingPurchaseOrderAmounts = make([]exchangerate.Amount, 0, len(outstandingValues))
+	for _, v := range outstandingValues {
+		dashboardRead.OutstandingPurchaseOrderAmounts = append(dashboardRead.OutstandingPurchaseOrderAmounts, v.Amount)
+		dashboardRead.OutstandingPurchaseOrderValue += v.BaseAmount
+		dashboardRead.OutstandingPurchaseOrderNoRate = dashboardRead.OutstandingPurchaseOrderNoRate || v.RateMissing
+	}
+
 	dashboardRead.OutstandingMaterialOrder = outstandingMaterialOrder
 	dashboardRead.OutstandingMaterialTransfer = outstandingMaterialTransfer
 	dashboardRead.OutstandingPurchaseOrder = outstandingPurchaseOrder
@@ -0,0 +1,563 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/docapproval"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+// DocApprovalRepository menjalankan approval bertingkat untuk material request,
+// PO request dan PO. Dokumen tanpa rule yang cocok langsung Approved.
+//
+//	CREATE TABLE tblapprovalrule (
+//		RuleCode   VARCHAR(20) NOT NULL PRIMARY KEY,
+//		RuleName   VARCHAR(80) NOT NULL,
+//		DocType    VARCHAR(30) NOT NULL,
+//		SiteCode   VARCHAR(20) NOT NULL DEFAULT '',
+//		Department VARCHAR(50) NOT NULL DEFAULT '',
+//		ActInd     CHAR(1) NOT NULL DEFAULT 'Y',
+//		CreateBy VARCHAR(50), CreateDt VARCHAR(12),
+//		LastUpBy VARCHAR(50), LastUpDt VARCHAR(12)
+//	);
+//	CREATE TABLE tblapprovalruledtl (
+//		RuleCode  VARCHAR(20) NOT NULL,
+//		Level     INT NOT NULL,
+//		UserCode  VARCHAR(50) NOT NULL,
+//		MinAmount DECIMAL(18,4) NOT NULL DEFAULT 0,
+//		PRIMARY KEY (RuleCode, Level, UserCode)
+//	);
+//	CREATE TABLE tbldocapproval (
+//		DocType  VARCHAR(30) NOT NULL,
+//		DocNo    VARCHAR(30) NOT NULL,
+//		Level    INT NOT NULL,
+//		UserCode VARCHAR(50) NOT NULL,
+//		Status   VARCHAR(10) NOT NULL,
+//		Remark   VARCHAR(250) NULL,
+//		CreateDt VARCHAR(12), LastUpDt VARCHAR(12),
+//		PRIMARY KEY (DocType, DocNo, Level, UserCode)
+//	);
+//	CREATE TABLE tbldocapprovalhist (
+//		DocType  VARCHAR(30) NOT NULL,
+//		DocNo    VARCHAR(30) NOT NULL,
+//		UserCode VARCHAR(50) NOT NULL,
+//		Action   VARCHAR(10) NOT NULL,
+//		Remark   VARCHAR(250) NULL,
+//		CreateDt VARCHAR(12)
+//	);
+//	ALTER TABLE tblmaterialrequesthdr ADD COLUMN ApprovalStatus VARCHAR(10) NOT NULL DEFAULT 'Approved';
+//	ALTER TABLE tblpurchaseorderreqhdr ADD COLUMN ApprovalStatus VARCHAR(10) NOT NULL DEFAULT 'Approved';
+//	ALTER TABLE tblpurchaseorderhdr ADD COLUMN ApprovalStatus VARCHAR(10) NOT NULL DEFAULT 'Approved';
+type DocApprovalRepository struct {
+	DB *repository.Sqlx `inject:"database"`
+}
+
+var approvalHeader = map[string]string{
+	docapproval.MaterialRequest:      "tblmaterialrequesthdr",
+	docapproval.PurchaseOrderRequest: "tblpurchaseorderreqhdr",
+	docapproval.PurchaseOrder:        "tblpurchaseorderhdr",
+}
+
+// site dan department PO request / PO diambil dari material request asalnya,
+// nilai dokumen dari EstimatedPrice * Qty atau Total PO.
+var approvalAttribute = map[string]string{
+	docapproval.MaterialRequest: `SELECT
+			COALESCE(MIN(h.SiteCode), '') AS SiteCode,
+			COALESCE(MIN(h.Department), '') AS Department,
+			COALESCE(SUM(d.Qty * d.EstimatedPrice), 0) AS Amount
+		FROM tblmaterialrequesthdr h
+		LEFT JOIN tblmaterialrequestdtl d ON h.DocNo = d.DocNo AND d.CancelInd = 'N'
+		WHERE h.DocNo = ?`,
+	docapproval.PurchaseOrderRequest: `SELECT
+			COALESCE(MIN(mh.SiteCode), '') AS SiteCode,
+			COALESCE(MIN(mh.Department), '') AS Department,
+			COALESCE(SUM(d.Qty * md.EstimatedPrice), 0) AS Amount
+		FROM tblpurchaseorderreqdtl d
+		JOIN tblmaterialrequestdtl md ON d.MaterialReqDocNo = md.DocNo AND d.MaterialReqDNo = md.DNo
+		JOIN tblmaterialrequesthdr mh ON md.DocNo = mh.DocNo
+		WHERE d.DocNo = ? AND d.CancelInd = 'N'`,
+	docapproval.PurchaseOrder: `SELECT
+			COALESCE(MIN(mh.SiteCode), '') AS SiteCode,
+			COALESCE(MIN(mh.Department), '') AS Department,
+			COALESCE(SUM(d.Total), 0) AS Amount
+		FROM tblpurchaseorderdtl d
+		LEFT JOIN tblpurchaseorderreqdtl pr ON d.PurchaseOrderReqDocNo = pr.DocNo AND d.PurchaseOrderReqDNo = pr.DNo
+		LEFT JOIN tblmaterialrequestdtl md ON pr.MaterialReqDocNo = md.DocNo AND pr.MaterialReqDNo = md.DNo
+		LEFT JOIN tblmaterialrequesthdr mh ON md.DocNo = mh.DocNo
+		WHERE d.DocNo = ? AND d.CancelInd = 'N'`,
+}
+
+func (t *DocApprovalRepository) Start(ctx context.Context, tx *sqlx.Tx, docType, docNo, userCode, date string) error {
+	header, ok := approvalHeader[docType]
+	if !ok {
+		return fmt.Errorf("unknown approval document type %s", docType)
+	}
+
+	var attribute struct {
+		SiteCode   string  `db:"SiteCode"`
+		Department string  `db:"Department"`
+		Amount     float32 `db:"Amount"`
+	}
+	if err := tx.GetContext(ctx, &attribute, approvalAttribute[docType], docNo); err != nil {
+		log.Printf("Error get approval attribute: %+v", err)
+		return fmt.Errorf("error Get Approval Attribute: %w", err)
+	}
+
+	// rule paling spesifik (site + department) didahulukan
+	var ruleCode string
+	query := `SELECT RuleCode
+		FROM tblapprovalrule
+		WHERE DocType = ?
+		AND ActInd = 'Y'
+		AND SiteCode IN ('', ?)
+		AND Department IN ('', ?)
+		ORDER BY (SiteCode <> '') + (Department <> '') DESC, RuleCode
+		LIMIT 1`
+	err := tx.GetContext(ctx, &ruleCode, query, docType, attribute.SiteCode, attribute.Department)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Error get approval rule: %+v", err)
+		return fmt.Errorf("error Get Approval Rule: %w", err)
+	}
+
+	status := docapproval.Approved
+	if ruleCode != "" {
+		query = `INSERT INTO tbldocapproval (DocType, DocNo, Level, UserCode, Status, CreateDt)
+			SELECT ?, ?, Level, UserCode, ?, ?
+			FROM tblapprovalruledtl
+			WHERE RuleCode = ? AND MinAmount <= ?`
+		result, err := tx.ExecContext(ctx, query, docType, docNo, docapproval.Pending, date, ruleCode, attribute.Amount)
+		if err != nil {
+			log.Printf("Error insert doc approval: %+v", err)
+			return fmt.Errorf("error Insert Doc Approval: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error check row affected: %w", err)
+		}
+		if rowsAffected > 0 {
+			status = docapproval.Pending
+		}
+	}
+
+	query = "UPDATE " + header + " SET ApprovalStatus = ? WHERE DocNo = ?"
+	if _, err := tx.ExecContext(ctx, query, status, docNo); err != nil {
+		log.Printf("Failed to update approval status: %+v", err)
+		return fmt.Errorf("error updating approval status: %w", err)
+	}
+
+	return insertApprovalHistory(ctx, tx, docType, docNo, userCode, docapproval.Submitted, "", date)
+}
+
+func (t *DocApprovalRepository) Detail(ctx context.Context, docType, docNo string) (*docapproval.Detail, error) {
+	header, ok := approvalHeader[docType]
+	if !ok {
+		return nil, customerrors.ErrDataNotFound
+	}
+
+	detail := docapproval.Detail{DocType: docType, DocNo: docNo}
+
+	query := "SELECT ApprovalStatus FROM " + header + " WHERE DocNo = ?"
+	if err := t.DB.GetContext(ctx, &detail.ApprovalStatus, query, docNo); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrDataNotFound
+		}
+		return nil, fmt.Errorf("error Detail Approval: %w", err)
+	}
+
+	detail.Approvers = make([]docapproval.Approver, 0)
+	query = `SELECT a.Level, a.UserCode, u.UserName, a.Status, a.Remark, a.LastUpDt
+		FROM tbldocapproval a
+		JOIN tbluser u ON a.UserCode = u.UserCode
+		WHERE a.DocType = ? AND a.DocNo = ?
+		ORDER BY a.Level, a.UserCode`
+	if err := t.DB.SelectContext(ctx, &detail.Approvers, query, docType, docNo); err != nil {
+		return nil, fmt.Errorf("error fetching approver: %w", err)
+	}
+
+	detail.History = make([]docapproval.History, 0)
+	query = `SELECT UserCode, Action, Remark, CreateDt
+		FROM tbldocapprovalhist
+		WHERE DocType = ? AND DocNo = ?
+		ORDER BY CreateDt`
+	if err := t.DB.SelectContext(ctx, &detail.History, query, docType, docNo); err != nil {
+		return nil, fmt.Errorf("error fetching approval history: %w", err)
+	}
+
+	return &detail, nil
+}
+
+// Act mencatat keputusan approver pada level yang sedang berjalan. Approve
+// meneruskan dokumen ke level berikutnya, reject dan return menghentikan
+// approval saat itu juga.
+func (t *DocApprovalRepository) Act(ctx context.Context, data *docapproval.Act) (*docapproval.Act, error) {
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	header, err := lockApprovalStatus(ctx, tx, data.DocType, data.DocNo, docapproval.Pending)
+	if err != nil {
+		return nil, err
+	}
+
+	var level int
+	query := "SELECT COALESCE(MIN(Level), 0) FROM tbldocapproval WHERE DocType = ? AND DocNo = ? AND Status = ?"
+	if err = tx.GetContext(ctx, &level, query, data.DocType, data.DocNo, docapproval.Pending); err != nil {
+		log.Printf("Error get approval level: %+v", err)
+		return nil, fmt.Errorf("error Get Approval Level: %w", err)
+	}
+
+	query = `UPDATE tbldocapproval
+		SET Status = ?, Remark = ?, LastUpDt = ?
+		WHERE DocType = ? AND DocNo = ? AND Level = ? AND UserCode = ? AND Status = ?`
+	result, err := tx.ExecContext(ctx, query, data.Status, data.Remark, data.Date, data.DocType, data.DocNo, level, data.UserCode, docapproval.Pending)
+	if err != nil {
+		log.Printf("Failed to update doc approval: %+v", err)
+		return nil, fmt.Errorf("error updating doc approval: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("error check row affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		err = customerrors.ErrNotApprover
+		return nil, err
+	}
+
+	// cukup satu approver per level
+	query = "UPDATE tbldocapproval SET Status = ?, LastUpDt = ? WHERE DocType = ? AND DocNo = ? AND Level = ? AND Status = ?"
+	if _, err = tx.ExecContext(ctx, query, docapproval.Skipped, data.Date, data.DocType, data.DocNo, level, docapproval.Pending); err != nil {
+		log.Printf("Failed to skip doc approval: %+v", err)
+		return nil, fmt.Errorf("error updating doc approval: %w", err)
+	}
+
+	status := data.Status
+	if status == docapproval.Approved {
+		var pending int
+		query = "SELECT COUNT(*) FROM tbldocapproval WHERE DocType = ? AND DocNo = ? AND Status = ?"
+		if err = tx.GetContext(ctx, &pending, query, data.DocType, data.DocNo, docapproval.Pending); err != nil {
+			log.Printf("Error count pending approval: %+v", err)
+			return nil, fmt.Errorf("error Count Pending Approval: %w", err)
+		}
+		if pending > 0 {
+			status = docapproval.Pending
+		}
+	}
+
+	query = "UPDATE " + header + " SET ApprovalStatus = ? WHERE DocNo = ?"
+	if _, err = tx.ExecContext(ctx, query, status, data.DocNo); err != nil {
+		log.Printf("Failed to update approval status: %+v", err)
+		return nil, fmt.Errorf("error updating approval status: %w", err)
+	}
+
+	if err = insertApprovalHistory(ctx, tx, data.DocType, data.DocNo, data.UserCode, data.Status, data.Remark, data.Date); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return data, nil
+}
+
+// Resubmit mengulang approval dokumen yang di-return, rule dan nilai dokumen
+// dihitung ulang karena dokumen bisa saja sudah diubah.
+func (t *DocApprovalRepository) Resubmit(ctx context.Context, docType, docNo, userCode, date string) error {
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	if _, err = lockApprovalStatus(ctx, tx, docType, docNo, docapproval.Returned); err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM tbldocapproval WHERE DocType = ? AND DocNo = ?", docType, docNo); err != nil {
+		log.Printf("Failed to delete doc approval: %+v", err)
+		return fmt.Errorf("error deleting doc approval: %w", err)
+	}
+
+	if err = t.Start(ctx, tx, docType, docNo, userCode, date); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (t *DocApprovalRepository) Inbox(ctx context.Context, userCode string) ([]*docapproval.Inbox, error) {
+	data := make([]*docapproval.Inbox, 0)
+
+	query := `SELECT a.DocType, a.DocNo, a.Level, a.CreateDt
+		FROM tbldocapproval a
+		WHERE a.UserCode = ?
+		AND a.Status = ?
+		AND a.Level = (
+			SELECT MIN(b.Level) FROM tbldocapproval b
+			WHERE b.DocType = a.DocType
+			AND b.DocNo = a.DocNo
+			AND b.Status = ?
+		)
+		ORDER BY a.CreateDt, a.DocNo`
+	if err := t.DB.SelectContext(ctx, &data, query, userCode, docapproval.Pending, docapproval.Pending); err != nil {
+		return nil, fmt.Errorf("error fetching approval inbox: %w", err)
+	}
+
+	return data, nil
+}
+
+func (t *DocApprovalRepository) FetchRule(ctx context.Context, search string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	var totalRecords int
+
+	searchRule := "%" + search + "%"
+
+	countQuery := "SELECT COUNT(*) FROM tblapprovalrule WHERE RuleCode LIKE ? OR RuleName LIKE ?"
+	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, searchRule, searchRule); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+
+	var totalPages int
+	var offset int
+
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+		offset = 0
+	}
+
+	data := make([]*docapproval.Rule, 0)
+	query := `SELECT RuleCode,
+				RuleName,
+				DocType,
+				SiteCode,
+				Department,
+				ActInd
+				FROM tblapprovalrule
+				WHERE RuleCode LIKE ? OR RuleName LIKE ?
+				ORDER BY RuleCode
+				LIMIT ? OFFSET ?`
+	if err := t.DB.SelectContext(ctx, &data, query, searchRule, searchRule, param.PageSize, offset); err != nil {
+		return nil, fmt.Errorf("error Fetch Approval Rule: %w", err)
+	}
+
+	for i, rule := range data {
+		rule.Number = uint(offset + i + 1)
+	}
+
+	response := &pagination.PaginationResponse{
+		Data:         data,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}
+
+	return response, nil
+}
+
+func (t *DocApprovalRepository) DetailRule(ctx context.Context, code string) (*docapproval.Rule, error) {
+	var rule docapproval.Rule
+
+	query := "SELECT RuleCode, RuleName, DocType, SiteCode, Department, ActInd FROM tblapprovalrule WHERE RuleCode = ?"
+	if err := t.DB.GetContext(ctx, &rule, query, code); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrDataNotFound
+		}
+		return nil, fmt.Errorf("error Detail Approval Rule: %w", err)
+	}
+
+	rule.Levels = make([]docapproval.Level, 0)
+	query = `SELECT d.Level, d.UserCode, u.UserName, d.MinAmount
+		FROM tblapprovalruledtl d
+		JOIN tbluser u ON d.UserCode = u.UserCode
+		WHERE d.RuleCode = ?
+		ORDER BY d.Level, d.UserCode`
+	if err := t.DB.SelectContext(ctx, &rule.Levels, query, code); err != nil {
+		return nil, fmt.Errorf("error fetching approval level: %w", err)
+	}
+
+	return &rule, nil
+}
+
+func (t *DocApprovalRepository) CreateRule(ctx context.Context, data *docapproval.CreateRule) (*docapproval.CreateRule, error) {
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	query := `INSERT INTO tblapprovalrule (RuleCode, RuleName, DocType, SiteCode, Department, ActInd, CreateBy, CreateDt)
+		VALUES (?, ?, ?, ?, ?, 'Y', ?, ?)`
+	if _, err = tx.ExecContext(ctx, query, data.RuleCode, data.RuleName, data.DocType, data.SiteCode, data.Department, data.CreateBy, data.CreateDt); err != nil {
+		log.Printf("Error insert approval rule: %+v", err)
+		return nil, fmt.Errorf("error Create Approval Rule: %w", err)
+	}
+
+	if err = insertApprovalLevel(ctx, tx, data.RuleCode, data.Levels); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return data, nil
+}
+
+// UpdateRule hanya berlaku untuk dokumen yang dibuat setelahnya, approver
+// dokumen yang sedang berjalan tidak berubah.
+func (t *DocApprovalRepository) UpdateRule(ctx context.Context, data *docapproval.UpdateRule) (*docapproval.UpdateRule, error) {
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	audit, err := newAuditTrail(ctx, tx, "ApprovalRule", data.RuleCode)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE tblapprovalrule
+		SET RuleName = ?, SiteCode = ?, Department = ?, ActInd = ?, LastUpBy = ?, LastUpDt = ?
+		WHERE RuleCode = ?`
+	if _, err = tx.ExecContext(ctx, query, data.RuleName, data.SiteCode, data.Department, data.ActiveInd, data.LastUpBy, data.LastUpDt, data.RuleCode); err != nil {
+		log.Printf("Failed to update approval rule: %+v", err)
+		return nil, fmt.Errorf("error updating approval rule: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM tblapprovalruledtl WHERE RuleCode = ?", data.RuleCode); err != nil {
+		log.Printf("Failed to delete approval level: %+v", err)
+		return nil, fmt.Errorf("error deleting approval level: %w", err)
+	}
+
+	if err = insertApprovalLevel(ctx, tx, data.RuleCode, data.Levels); err != nil {
+		return nil, err
+	}
+
+	if err = audit.Write(ctx, tx, data.LastUpBy, data.LastUpDt); err != nil {
+		log.Printf("Failed to insert log activity: %+v", err)
+		return nil, fmt.Errorf("error insert to log activity: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return data, nil
+}
+
+// lockApprovalStatus mengunci header dokumen dan memastikan status approval-nya
+// sesuai sebelum diproses, mengembalikan nama tabel header.
+func lockApprovalStatus(ctx context.Context, tx *sqlx.Tx, docType, docNo, expected string) (string, error) {
+	header, ok := approvalHeader[docType]
+	if !ok {
+		return "", customerrors.ErrDataNotFound
+	}
+
+	var status string
+	query := "SELECT ApprovalStatus FROM " + header + " WHERE DocNo = ? FOR UPDATE"
+	if err := tx.GetContext(ctx, &status, query, docNo); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", customerrors.ErrDataNotFound
+		}
+		log.Printf("Error lock approval status: %+v", err)
+		return "", fmt.Errorf("error Lock Approval Status: %w", err)
+	}
+
+	if status != expected {
+		return "", fmt.Errorf("%w: document %s is %s", customerrors.ErrInvalidApprovalStatus, docNo, status)
+	}
+
+	return header, nil
+}
+
+func insertApprovalHistory(ctx context.Context, tx *sqlx.Tx, docType, docNo, userCode, action, remark, date string) error {
+	query := "INSERT INTO tbldocapprovalhist (DocType, DocNo, UserCode, Action, Remark, CreateDt) VALUES (?, ?, ?, ?, NULLIF(?, ''), ?)"
+	if _, err := tx.ExecContext(ctx, query, docType, docNo, userCode, action, remark, date); err != nil {
+		log.Printf("Error insert approval history: %+v", err)
+		return fmt.Errorf("error Insert Approval History: %w", err)
+	}
+
+	return nil
+}
+
+func insertApprovalLevel(ctx context.Context, tx *sqlx.Tx, ruleCode string, levels []docapproval.Level) error {
+	var placeholders []string
+	var args []interface{}
+	for _, level := range levels {
+		placeholders = append(placeholders, "(?, ?, ?, ?)")
+		args = append(args, ruleCode, level.Level, level.UserCode, level.MinAmount)
+	}
+
+	query := "INSERT INTO tblapprovalruledtl (RuleCode, Level, UserCode, MinAmount) VALUES " + strings.Join(placeholders, ",")
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		log.Printf("Error insert approval level: %+v", err)
+		return fmt.Errorf("error Insert Approval Level: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/docapproval"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+const (
+	queryApprovalRule = `SELECT RuleCode FROM tblapprovalrule WHERE DocType = ? AND ActInd = 'Y' AND SiteCode IN ('', ?) AND Department IN ('', ?)
+		ORDER BY (SiteCode <> '') + (Department <> '') DESC, RuleCode LIMIT 1`
+	queryInsertApprover = `INSERT INTO tbldocapproval (DocType, DocNo, Level, UserCode, Status, CreateDt)
+		SELECT ?, ?, Level, UserCode, ?, ? FROM tblapprovalruledtl WHERE RuleCode = ? AND MinAmount <= ?`
+	queryApprovalHistory = "INSERT INTO tbldocapprovalhist (DocType, DocNo, UserCode, Action, Remark, CreateDt) VALUES (?, ?, ?, ?, NULLIF(?, ''), ?)"
+	queryLockPO          = "SELECT ApprovalStatus FROM tblpurchaseorderhdr WHERE DocNo = ? FOR UPDATE"
+	queryCurrentLevel    = "SELECT COALESCE(MIN(Level), 0) FROM tbldocapproval WHERE DocType = ? AND DocNo = ? AND Status = ?"
+	queryActApprover     = "UPDATE tbldocapproval SET Status = ?, Remark = ?, LastUpDt = ? WHERE DocType = ? AND DocNo = ? AND Level = ? AND UserCode = ? AND Status = ?"
+)
+
+type DocApprovalSuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	db      *sqlx.DB
+	repo    *DocApprovalRepository
+}
+
+func (suite *DocApprovalSuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.repo = &DocApprovalRepository{DB: &repository.Sqlx{DB: suite.db}}
+}
+
+func (suite *DocApprovalSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *DocApprovalSuite) expectAttribute(amount float32) {
+	suite.mockSQL.ExpectQuery(approvalAttribute[docapproval.PurchaseOrder]).
+		WithArgs("PO-1").
+		WillReturnRows(sqlmock.NewRows([]string{"SiteCode", "Department", "Amount"}).AddRow("JKT", "IT", amount))
+}
+
+// dokumen tanpa rule langsung bisa dipakai
+func (suite *DocApprovalSuite) TestStart_NoRuleApproved() {
+	suite.mockSQL.ExpectBegin()
+	suite.expectAttribute(500)
+	suite.mockSQL.ExpectQuery(queryApprovalRule).
+		WithArgs(docapproval.PurchaseOrder, "JKT", "IT").
+		WillReturnRows(sqlmock.NewRows([]string{"RuleCode"}))
+	suite.mockSQL.ExpectExec("UPDATE tblpurchaseorderhdr SET ApprovalStatus = ? WHERE DocNo = ?").
+		WithArgs(docapproval.Approved, "PO-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryApprovalHistory).
+		WithArgs(docapproval.PurchaseOrder, "PO-1", "clerk", docapproval.Submitted, "", "202601010800").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	tx, err := suite.db.Beginx()
+	suite.Require().NoError(err)
+
+	err = suite.repo.Start(context.Background(), tx, docapproval.PurchaseOrder, "PO-1", "clerk", "202601010800")
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func (suite *DocApprovalSuite) TestStart_RulePending() {
+	suite.mockSQL.ExpectBegin()
+	suite.expectAttribute(15000000)
+	suite.mockSQL.ExpectQuery(queryApprovalRule).
+		WithArgs(docapproval.PurchaseOrder, "JKT", "IT").
+		WillReturnRows(sqlmock.NewRows([]string{"RuleCode"}).AddRow("PO-JKT"))
+	suite.mockSQL.ExpectExec(queryInsertApprover).
+		WithArgs(docapproval.PurchaseOrder, "PO-1", docapproval.Pending, "202601010800", "PO-JKT", float32(15000000)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mockSQL.ExpectExec("UPDATE tblpurchaseorderhdr SET ApprovalStatus = ? WHERE DocNo = ?").
+		WithArgs(docapproval.Pending, "PO-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryApprovalHistory).
+		WithArgs(docapproval.PurchaseOrder, "PO-1", "clerk", docapproval.Submitted, "", "202601010800").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	tx, err := suite.db.Beginx()
+	suite.Require().NoError(err)
+
+	err = suite.repo.Start(context.Background(), tx, docapproval.PurchaseOrder, "PO-1", "clerk", "202601010800")
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func (suite *DocApprovalSuite) TestAct_NotCurrentApprover() {
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectQuery(queryLockPO).
+		WithArgs("PO-1").
+		WillReturnRows(sqlmock.NewRows([]string{"ApprovalStatus"}).AddRow(docapproval.Pending))
+	suite.mockSQL.ExpectQuery(queryCurrentLevel).
+		WithArgs(docapproval.PurchaseOrder, "PO-1", docapproval.Pending).
+		WillReturnRows(sqlmock.NewRows([]string{"Level"}).AddRow(1))
+	suite.mockSQL.ExpectExec(queryActApprover).
+		WithArgs(docapproval.Approved, "", "202601020900", docapproval.PurchaseOrder, "PO-1", 1, "director", docapproval.Pending).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mockSQL.ExpectRollback()
+
+	_, err := suite.repo.Act(context.Background(), &docapproval.Act{
+		DocType:  docapproval.PurchaseOrder,
+		DocNo:    "PO-1",
+		Status:   docapproval.Approved,
+		UserCode: "director",
+		Date:     "202601020900",
+	})
+
+	suite.ErrorIs(err, customerrors.ErrNotApprover)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// approve di level terakhir membuat dokumen Approved
+func (suite *DocApprovalSuite) TestAct_LastLevelApproved() {
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectQuery(queryLockPO).
+		WithArgs("PO-1").
+		WillReturnRows(sqlmock.NewRows([]string{"ApprovalStatus"}).AddRow(docapproval.Pending))
+	suite.mockSQL.ExpectQuery(queryCurrentLevel).
+		WithArgs(docapproval.PurchaseOrder, "PO-1", docapproval.Pending).
+		WillReturnRows(sqlmock.NewRows([]string{"Level"}).AddRow(2))
+	suite.mockSQL.ExpectExec(queryActApprover).
+		WithArgs(docapproval.Approved, "ok", "202601020900", docapproval.PurchaseOrder, "PO-1", 2, "director", docapproval.Pending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec("UPDATE tbldocapproval SET Status = ?, LastUpDt = ? WHERE DocType = ? AND DocNo = ? AND Level = ? AND Status = ?").
+		WithArgs(docapproval.Skipped, "202601020900", docapproval.PurchaseOrder, "PO-1", 2, docapproval.Pending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectQuery("SELECT COUNT(*) FROM tbldocapproval WHERE DocType = ? AND DocNo = ? AND Status = ?").
+		WithArgs(docapproval.PurchaseOrder, "PO-1", docapproval.Pending).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+	suite.mockSQL.ExpectExec("UPDATE tblpurchaseorderhdr SET ApprovalStatus = ? WHERE DocNo = ?").
+		WithArgs(docapproval.Approved, "PO-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryApprovalHistory).
+		WithArgs(docapproval.PurchaseOrder, "PO-1", "director", docapproval.Approved, "ok", "202601020900").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectCommit()
+
+	_, err := suite.repo.Act(context.Background(), &docapproval.Act{
+		DocType:  docapproval.PurchaseOrder,
+		DocNo:    "PO-1",
+		Status:   docapproval.Approved,
+		Remark:   "ok",
+		UserCode: "director",
+		Date:     "202601020900",
+	})
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func (suite *DocApprovalSuite) TestAct_AlreadyRejected() {
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectQuery(queryLockPO).
+		WithArgs("PO-1").
+		WillReturnRows(sqlmock.NewRows([]string{"ApprovalStatus"}).AddRow(docapproval.Rejected))
+	suite.mockSQL.ExpectRollback()
+
+	_, err := suite.repo.Act(context.Background(), &docapproval.Act{
+		DocType:  docapproval.PurchaseOrder,
+		DocNo:    "PO-1",
+		Status:   docapproval.Approved,
+		UserCode: "director",
+	})
+
+	suite.ErrorIs(err, customerrors.ErrInvalidApprovalStatus)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func TestDocApprovalSuite(t *testing.T) {
+	suite.Run(t, new(DocApprovalSuite))
+}
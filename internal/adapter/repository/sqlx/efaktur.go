@@ -0,0 +1,342 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/efaktur"
+	"gitlab.com/ayaka/internal/domain/taxengine"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+// EfakturRepository export faktur keluaran dari direct sales delivery dan
+// rentang nomor seri faktur pajak. Nomor diambil berurutan per rentang,
+// dokumen yang sudah punya TaxInvoiceNo tidak diberi nomor lagi, tapi satu
+// export (EFakturBatch = nomor faktur pertamanya) bisa didownload ulang.
+//
+//	ALTER TABLE tblvendorhdr ADD COLUMN NPWP VARCHAR(16) NULL;
+//	ALTER TABLE tblcustomerhdr ADD COLUMN NPWP VARCHAR(16) NULL;
+//	ALTER TABLE tbldirectsalesdelivhdr
+//		ADD COLUMN NPWP VARCHAR(16) NULL,
+//		ADD COLUMN TaxInvoiceNo VARCHAR(13) NULL,
+//		ADD COLUMN EFakturExportBy VARCHAR(16) NULL,
+//		ADD COLUMN EFakturExportDt VARCHAR(12) NULL,
+//		ADD COLUMN EFakturBatch VARCHAR(13) NULL,
+//		ADD KEY (EFakturBatch);
+//	CREATE TABLE tbltaxinvoicerange (
+//		Prefix CHAR(5) NOT NULL,
+//		StartNo INT NOT NULL,
+//		EndNo INT NOT NULL,
+//		LastNo INT NOT NULL DEFAULT 0,
+//		CreateBy VARCHAR(16) NOT NULL,
+//		CreateDt VARCHAR(12) NOT NULL,
+//		PRIMARY KEY (Prefix, StartNo)
+//	);
+type EfakturRepository struct {
+	DB  *repository.Sqlx     `inject:"database"`
+	Tax taxengine.Repository `inject:"taxEngineRepository"`
+}
+
+func (t *EfakturRepository) FetchRange(ctx context.Context, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	var totalRecords int
+	if err := t.DB.GetContext(ctx, &totalRecords, "SELECT COUNT(*) FROM tbltaxinvoicerange"); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+
+	var totalPages, offset int
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+	}
+
+	data := make([]*efaktur.Range, 0)
+	query := `SELECT
+			Prefix,
+			StartNo,
+			EndNo,
+			LastNo
+		FROM tbltaxinvoicerange
+		ORDER BY Prefix DESC, StartNo
+		LIMIT ? OFFSET ?`
+	if err := t.DB.SelectContext(ctx, &data, query, param.PageSize, offset); err != nil {
+		return nil, fmt.Errorf("error Fetch tax invoice range: %w", err)
+	}
+
+	j := offset
+	for _, d := range data {
+		j++
+		d.Number = uint(j)
+		d.Remaining = d.EndNo - max(d.LastNo, d.StartNo-1)
+	}
+
+	return &pagination.PaginationResponse{
+		Data:         data,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}, nil
+}
+
+func (t *EfakturRepository) CreateRange(ctx context.Context, data *efaktur.Range) (*efaktur.Range, error) {
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var overlap int
+	if err = tx.GetContext(ctx, &overlap, `SELECT COUNT(*) FROM tbltaxinvoicerange
+		WHERE Prefix = ? AND StartNo <= ? AND EndNo >= ?
+		FOR UPDATE`, data.Prefix, data.EndNo, data.StartNo); err != nil {
+		return nil, fmt.Errorf("error checking tax invoice range: %w", err)
+	}
+	if overlap > 0 {
+		err = customerrors.ErrDataAlreadyExists
+		return nil, err
+	}
+
+	query := `INSERT INTO tbltaxinvoicerange (
+			Prefix,
+			StartNo,
+			EndNo,
+			LastNo,
+			CreateBy,
+			CreateDt
+		) VALUES (?, ?, ?, 0, ?, ?)`
+	if _, err = tx.ExecContext(ctx, query,
+		data.Prefix,
+		data.StartNo,
+		data.EndNo,
+		data.CreateBy,
+		data.CreateDate,
+	); err != nil {
+		log.Printf("Detailed error: %+v", err)
+		return nil, fmt.Errorf("error Create tax invoice range: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	data.Remaining = data.EndNo - data.StartNo + 1
+	return data, nil
+}
+
+// efakturHeader kolom header faktur, kondisi WHERE ditambahkan pemanggil
+const efakturHeader = `SELECT
+			h.DocNo,
+			h.DocDt,
+			COALESCE(h.TaxInvoiceNo, '') AS TaxInvoiceNo,
+			COALESCE(h.NPWP, '') AS NPWP,
+			COALESCE(h.CustomerName, '') AS CustomerName,
+			COALESCE(h.Address, '') AS Address,
+			COALESCE(c.CityName, '') AS CityName,
+			COALESCE(h.PostalCode, '') AS PostalCode,
+			COALESCE(h.Phone, '') AS Phone,
+			COALESCE(h.TaxCode, '') AS TaxCode,
+			h.TaxInclusiveInd
+		FROM tbldirectsalesdelivhdr h
+		LEFT JOIN tblcity c ON h.CityCode = c.CityCode
+		WHERE `
+
+func (t *EfakturRepository) Export(ctx context.Context, startDate, endDate, userCode, date string) ([]efaktur.Invoice, error) {
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	query := efakturHeader + `h.DocDt BETWEEN ? AND ?
+		AND h.TaxInvoiceNo IS NULL
+		AND (h.TaxCode IS NOT NULL OR EXISTS (
+			SELECT 1 FROM tbldoctaxdtl x WHERE x.DocType = ? AND x.DocNo = h.DocNo
+		))`
+	args := []interface{}{startDate, endDate, taxengine.DirectSalesDelivery}
+	if scope, scopeArgs := warehouseScope(ctx, "h.WhsCode"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+	query += " ORDER BY h.DocDt, h.DocNo FOR UPDATE"
+
+	var headers []efaktur.Invoice
+	if err = tx.SelectContext(ctx, &headers, query, args...); err != nil {
+		return nil, fmt.Errorf("error fetching direct sales delivery: %w", err)
+	}
+
+	result, err := t.invoices(ctx, tx, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var batch string
+	for i := range result {
+		if result[i].TaxInvoiceNo, err = t.nextTaxInvoiceNo(ctx, tx, result[i].DocDt); err != nil {
+			return nil, err
+		}
+		if batch == "" {
+			batch = result[i].TaxInvoiceNo
+		}
+
+		if _, err = tx.ExecContext(ctx, `UPDATE tbldirectsalesdelivhdr
+			SET TaxInvoiceNo = ?, EFakturExportBy = ?, EFakturExportDt = ?, EFakturBatch = ?
+			WHERE DocNo = ?`, result[i].TaxInvoiceNo, userCode, date, batch, result[i].DocNo); err != nil {
+			log.Printf("Detailed error: %+v", err)
+			return nil, fmt.Errorf("error marking e-Faktur export: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// Reexport membaca ulang faktur satu batch dengan nomor yang sudah diberikan,
+// tanpa mengambil nomor baru
+func (t *EfakturRepository) Reexport(ctx context.Context, batch string) ([]efaktur.Invoice, error) {
+	query := efakturHeader + "h.EFakturBatch = ?"
+	args := []interface{}{batch}
+	if scope, scopeArgs := warehouseScope(ctx, "h.WhsCode"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+	query += " ORDER BY h.TaxInvoiceNo"
+
+	var headers []efaktur.Invoice
+	if err := t.DB.SelectContext(ctx, &headers, query, args...); err != nil {
+		return nil, fmt.Errorf("error fetching direct sales delivery: %w", err)
+	}
+
+	return t.invoices(ctx, t.DB, headers)
+}
+
+// invoices melengkapi header dengan baris OF serta DPP / PPN dari tax engine.
+// Dokumen tanpa PPN (hanya withholding) dibuang.
+func (t *EfakturRepository) invoices(ctx context.Context, q sqlx.QueryerContext, headers []efaktur.Invoice) ([]efaktur.Invoice, error) {
+	if len(headers) == 0 {
+		return nil, nil
+	}
+
+	docNos := make([]string, len(headers))
+	for i, h := range headers {
+		docNos[i] = h.DocNo
+	}
+
+	detailQuery, detailArgs, err := sqlx.In(`SELECT d.DocNo, d.DNo, d.ItCode, i.ItName, d.Price, d.Qty
+		FROM tbldirectsalesdelivdtl d
+		JOIN tblitem i ON d.ItCode = i.ItCode
+		WHERE d.DocNo IN (?) AND d.CancelInd = 'N'
+		ORDER BY d.DocNo, d.DNo`, docNos)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing detail query: %w", err)
+	}
+
+	var details []efaktur.Line
+	if err = sqlx.SelectContext(ctx, q, &details, t.DB.Rebind(detailQuery), detailArgs...); err != nil {
+		return nil, fmt.Errorf("error fetching details: %w", err)
+	}
+	detailMap := make(map[string][]efaktur.Line)
+	for _, d := range details {
+		detailMap[d.DocNo] = append(detailMap[d.DocNo], d)
+	}
+
+	breakdowns, err := t.Tax.Breakdowns(ctx, taxengine.DirectSalesDelivery, docNos)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []efaktur.Invoice
+	for _, h := range headers {
+		var lines []taxengine.Line
+		for _, d := range detailMap[h.DocNo] {
+			lines = append(lines, taxengine.Line{DNo: d.DNo, Amount: d.Price * d.Qty})
+		}
+
+		var breakdown *taxengine.Breakdown
+		if taxes, ok := breakdowns[h.DocNo]; ok {
+			breakdown = taxengine.Summarize(h.Inclusive == "Y", lines, activeLineTaxes(lines, taxes))
+		} else if breakdown, err = t.Tax.Calculate(ctx, &taxengine.Request{
+			TaxCode:   h.TaxCode,
+			Inclusive: h.Inclusive == "Y",
+			Lines:     lines,
+		}); err != nil {
+			return nil, err
+		}
+
+		// faktur hanya memuat baris yang kena PPN (pajak non-withholding)
+		vat := make(map[string]float64)
+		base := make(map[string]float64)
+		for _, tax := range breakdown.Lines {
+			if !tax.Withholding.ToBool() {
+				vat[tax.DNo] += tax.TaxAmount
+				base[tax.DNo] = tax.BaseAmount
+			}
+		}
+
+		for _, d := range detailMap[h.DocNo] {
+			if vat[d.DNo] == 0 {
+				continue
+			}
+			d.DPP = base[d.DNo]
+			d.VAT = vat[d.DNo]
+			h.DPP += d.DPP
+			h.VAT += d.VAT
+			h.Lines = append(h.Lines, d)
+		}
+		if h.VAT == 0 {
+			continue
+		}
+
+		result = append(result, h)
+	}
+
+	return result, nil
+}
+
+// nextTaxInvoiceNo mengambil nomor berikutnya dari rentang yang tahunnya
+// (digit 4-5 Prefix) sama dengan tahun dokumen
+func (t *EfakturRepository) nextTaxInvoiceNo(ctx context.Context, tx *sqlx.Tx, docDt string) (string, error) {
+	var r efaktur.Range
+	err := tx.GetContext(ctx, &r, `SELECT Prefix, StartNo, EndNo, LastNo
+		FROM tbltaxinvoicerange
+		WHERE SUBSTRING(Prefix, 4, 2) = ? AND LastNo < EndNo
+		ORDER BY StartNo
+		LIMIT 1
+		FOR UPDATE`, docDt[2:4])
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%w: year %s", customerrors.ErrTaxInvoiceRange, docDt[0:4])
+		}
+		return "", fmt.Errorf("error fetching tax invoice range: %w", err)
+	}
+
+	next := max(r.LastNo+1, r.StartNo)
+	if _, err := tx.ExecContext(ctx, "UPDATE tbltaxinvoicerange SET LastNo = ? WHERE Prefix = ? AND StartNo = ?", next, r.Prefix, r.StartNo); err != nil {
+		return "", fmt.Errorf("error updating tax invoice range: %w", err)
+	}
+
+	return fmt.Sprintf("%s%08d", r.Prefix, next), nil
+}
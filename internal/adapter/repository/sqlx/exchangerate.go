@@ -0,0 +1,211 @@
+package sqlx
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/exchangerate"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+// ExchangeRateRepository kurs harian antar mata uang. Rate adalah nilai satu
+// CurCode1 dalam CurCode2, kurs terakhir sebelum tanggal dokumen yang dipakai.
+//
+//	CREATE TABLE tblcurrencyrate (
+//		CurCode1 VARCHAR(3) NOT NULL,
+//		CurCode2 VARCHAR(3) NOT NULL,
+//		RateDt VARCHAR(8) NOT NULL,
+//		Rate DECIMAL(18,6) NOT NULL,
+//		CreateBy VARCHAR(16) NOT NULL,
+//		CreateDt VARCHAR(12) NOT NULL,
+//		LastUpBy VARCHAR(16) NULL,
+//		LastUpDt VARCHAR(12) NULL,
+//		PRIMARY KEY (CurCode1, CurCode2, RateDt)
+//	);
+type ExchangeRateRepository struct {
+	DB *repository.Sqlx `inject:"database"`
+}
+
+type currencyRate struct {
+	CurCode1 string  `db:"CurCode1"`
+	CurCode2 string  `db:"CurCode2"`
+	Rate     float64 `db:"Rate"`
+}
+
+func (t *ExchangeRateRepository) Fetch(ctx context.Context, curCode, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	var totalRecords int
+	where := "(r.CurCode1 LIKE ? OR r.CurCode2 LIKE ?)"
+	args := []interface{}{"%" + curCode + "%", "%" + curCode + "%"}
+	if startDate != "" && endDate != "" {
+		where += " AND r.RateDt BETWEEN ? AND ?"
+		args = append(args, startDate, endDate)
+	}
+
+	countQuery := "SELECT COUNT(*) FROM tblcurrencyrate r WHERE " + where
+	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+
+	var totalPages, offset int
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+	}
+
+	data := make([]*exchangerate.Rate, 0)
+	query := `SELECT
+			r.CurCode1,
+			r.CurCode2,
+			r.RateDt,
+			r.Rate
+		FROM tblcurrencyrate r
+		WHERE ` + where + `
+		ORDER BY r.RateDt DESC, r.CurCode1, r.CurCode2
+		LIMIT ? OFFSET ?`
+	if err := t.DB.SelectContext(ctx, &data, query, append(args, param.PageSize, offset)...); err != nil {
+		return nil, fmt.Errorf("error Fetch exchange rate: %w", err)
+	}
+
+	j := offset
+	for _, d := range data {
+		j++
+		d.Number = uint(j)
+		d.Date = share.ToDatePicker(d.Date)
+	}
+
+	return &pagination.PaginationResponse{
+		Data:         data,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}, nil
+}
+
+func (t *ExchangeRateRepository) Save(ctx context.Context, data *exchangerate.Rate) (*exchangerate.Rate, error) {
+	if err := saveRate(ctx, t.DB, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (t *ExchangeRateRepository) Delete(ctx context.Context, curCode1, curCode2, date string) error {
+	res, err := t.DB.ExecContext(ctx, "DELETE FROM tblcurrencyrate WHERE CurCode1 = ? AND CurCode2 = ? AND RateDt = ?", curCode1, curCode2, date)
+	if err != nil {
+		return fmt.Errorf("error Delete Exchange Rate: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return customerrors.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// saveRate dipakai juga oleh import, kurs di tanggal yang sama ditimpa
+func saveRate(ctx context.Context, db sqlx.ExecerContext, data *exchangerate.Rate) error {
+	query := `INSERT INTO tblcurrencyrate (
+			CurCode1,
+			CurCode2,
+			RateDt,
+			Rate,
+			CreateBy,
+			CreateDt
+		) VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			Rate = VALUES(Rate),
+			LastUpBy = VALUES(CreateBy),
+			LastUpDt = VALUES(CreateDt)`
+	if _, err := db.ExecContext(ctx, query,
+		data.CurCode1,
+		data.CurCode2,
+		data.Date,
+		data.Rate,
+		data.CreateBy,
+		data.CreateDate,
+	); err != nil {
+		log.Printf("Detailed error: %+v", err)
+		return fmt.Errorf("error Save Exchange Rate: %w", err)
+	}
+
+	return nil
+}
+
+func (t *ExchangeRateRepository) Rates(ctx context.Context, curCodes []string, base, date string) (map[string]float64, error) {
+	rates := map[string]float64{base: 1}
+
+	var placeholders []string
+	var codes []interface{}
+	for _, code := range curCodes {
+		if _, ok := rates[code]; ok {
+			continue
+		}
+		placeholders = append(placeholders, "?")
+		codes = append(codes, code)
+	}
+	if len(codes) == 0 {
+		return rates, nil
+	}
+
+	in := strings.Join(placeholders, ",")
+	query := `SELECT r.CurCode1, r.CurCode2, r.Rate
+		FROM tblcurrencyrate r
+		WHERE ((r.CurCode1 IN (` + in + `) AND r.CurCode2 = ?) OR (r.CurCode2 IN (` + in + `) AND r.CurCode1 = ?))
+		AND r.RateDt = (
+			SELECT MAX(x.RateDt) FROM tblcurrencyrate x
+			WHERE x.CurCode1 = r.CurCode1 AND x.CurCode2 = r.CurCode2 AND x.RateDt <= ?
+		)`
+	args := append(append(append([]interface{}{}, codes...), base), codes...)
+	args = append(args, base, date)
+
+	var data []currencyRate
+	if err := t.DB.SelectContext(ctx, &data, query, args...); err != nil {
+		return nil, fmt.Errorf("error get currency rate: %w", err)
+	}
+
+	// kurs langsung ke base didahulukan, kurs kebalikan hanya pengganti
+	for _, r := range data {
+		if r.CurCode2 == base && r.Rate > 0 {
+			rates[r.CurCode1] = r.Rate
+		}
+	}
+	for _, r := range data {
+		if _, ok := rates[r.CurCode2]; !ok && r.CurCode1 == base && r.Rate > 0 {
+			rates[r.CurCode2] = 1 / r.Rate
+		}
+	}
+
+	return rates, nil
+}
+
+// baseRateSQL ekspresi kurs cur ke base pada tanggal date (keduanya ekspresi
+// kolom) untuk laporan, 0 kalau kurs belum ada. Aturannya sama dengan Rates,
+// argumennya base tiga kali (baseRateArgs).
+func baseRateSQL(cur, date string) string {
+	return `CASE WHEN ` + cur + ` = ? THEN 1 ELSE COALESCE(
+			(SELECT r.Rate FROM tblcurrencyrate r
+				WHERE r.CurCode1 = ` + cur + ` AND r.CurCode2 = ? AND r.RateDt <= ` + date + ` AND r.Rate > 0
+				ORDER BY r.RateDt DESC LIMIT 1),
+			(SELECT 1 / r.Rate FROM tblcurrencyrate r
+				WHERE r.CurCode2 = ` + cur + ` AND r.CurCode1 = ? AND r.RateDt <= ` + date + ` AND r.Rate > 0
+				ORDER BY r.RateDt DESC LIMIT 1),
+			0
+		) END`
+}
+
+func baseRateArgs(base string) []interface{} {
+	return []interface{}{base, base, base}
+}
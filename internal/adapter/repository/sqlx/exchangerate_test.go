@@ -0,0 +1,51 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+const queryDeleteExchangeRate = "DELETE FROM tblcurrencyrate WHERE CurCode1 = ? AND CurCode2 = ? AND RateDt = ?"
+
+type ExchangeRateRepositorySuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	repo    *ExchangeRateRepository
+	db      *sqlx.DB
+}
+
+func (suite *ExchangeRateRepositorySuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.repo = &ExchangeRateRepository{
+		DB: &repository.Sqlx{DB: suite.db},
+	}
+}
+
+func (suite *ExchangeRateRepositorySuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *ExchangeRateRepositorySuite) TestDelete_NotFound() {
+	suite.mockSQL.ExpectExec(queryDeleteExchangeRate).
+		WithArgs("USD", "IDR", "20240105").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := suite.repo.Delete(context.Background(), "USD", "IDR", "20240105")
+
+	suite.ErrorIs(err, customerrors.ErrDataNotFound)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func TestExchangeRateRepository(t *testing.T) {
+	suite.Run(t, new(ExchangeRateRepositorySuite))
+}
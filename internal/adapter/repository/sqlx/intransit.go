@@ -0,0 +1,266 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/intransit"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+// InTransitRepository stok material transfer di gudang transit dan discrepancy
+// penerimaannya. Baris transfer ditutup pada penerimaan pertama, pengiriman
+// terpisah (split delivery) tidak didukung: sisa yang belum diterima menjadi
+// discrepancy. Loss dijurnal ke akun adjustment, Return kembali ke gudang
+// asal dengan source baru supaya tidak bentrok dengan source saat transfer.
+//
+//	-- gudang virtual, kategori dan kota disalin dari gudang pertama
+//	INSERT INTO tblwarehouse (WhsCode, WhsName, WhsCtCode, CityCode, CreateBy, CreateDt)
+//		SELECT 'TRANSIT', 'In Transit', WhsCtCode, CityCode, 'system', DATE_FORMAT(NOW(), '%Y%m%d%H%i')
+//		FROM tblwarehouse
+//		ORDER BY WhsCode
+//		LIMIT 1;
+//	CREATE TABLE tblmaterialtransferdiscrepancy (
+//		DocNo VARCHAR(30) NOT NULL,
+//		DNo VARCHAR(3) NOT NULL,
+//		TransferDocNo VARCHAR(30) NOT NULL,
+//		TransferDNo VARCHAR(3) NOT NULL,
+//		Qty DECIMAL(18,4) NOT NULL,
+//		Status VARCHAR(10) NOT NULL DEFAULT 'Open',
+//		ResolveBy VARCHAR(16) NULL,
+//		ResolveDt VARCHAR(12) NULL,
+//		Remark VARCHAR(250) NULL,
+//		CreateBy VARCHAR(16) NOT NULL,
+//		CreateDt VARCHAR(12) NOT NULL,
+//		PRIMARY KEY (DocNo, DNo),
+//		KEY (TransferDocNo, TransferDNo)
+//	);
+type InTransitRepository struct {
+	DB     *repository.Sqlx           `inject:"database"`
+	Ledger inventoryledger.Repository `inject:"inventoryLedgerRepository"`
+}
+
+func (t *InTransitRepository) Stock(ctx context.Context, warehouseFrom, warehouseTo string) ([]*intransit.Stock, error) {
+	query := `SELECT
+			d.DocNo,
+			d.DNo,
+			h.DocDt,
+			h.WhsCodeFrom,
+			w.WhsName AS WhsNameFrom,
+			h.WhsCodeTo,
+			w2.WhsName AS WhsNameTo,
+			d.ItCode,
+			i.ItName,
+			d.BatchNo,
+			u.UomName,
+			d.Qty,
+			d.QtyReceived,
+			` + inTransitQty + ` AS QtyInTransit,
+			EXISTS (
+				SELECT 1 FROM tblmaterialtransferdiscrepancy x
+				WHERE x.TransferDocNo = d.DocNo AND x.TransferDNo = d.DNo AND x.Status = ?
+			) AS Discrepancy
+		FROM tblmaterialtransferdtl d
+		JOIN tblmaterialtransferhdr h ON d.DocNo = h.DocNo
+		JOIN tblwarehouse w ON h.WhsCodeFrom = w.WhsCode
+		JOIN tblwarehouse w2 ON h.WhsCodeTo = w2.WhsCode
+		JOIN tblitem i ON d.ItCode = i.ItCode
+		JOIN tbluom u ON i.InventoryUOMCode = u.UomCode
+		WHERE h.WhsCodeFrom LIKE ? AND h.WhsCodeTo LIKE ?
+		AND ` + inTransitQty + ` > 0`
+	args := []interface{}{intransit.Open, "%" + warehouseFrom + "%", "%" + warehouseTo + "%"}
+
+	if scope, scopeArgs := warehouseScope(ctx, "h.WhsCodeFrom", "h.WhsCodeTo"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+	query += " ORDER BY h.DocDt, d.DocNo, d.DNo"
+
+	data := make([]*intransit.Stock, 0)
+	if err := t.DB.SelectContext(ctx, &data, query, args...); err != nil {
+		return nil, fmt.Errorf("error fetching in transit stock: %w", err)
+	}
+
+	return data, nil
+}
+
+func (t *InTransitRepository) FetchDiscrepancy(ctx context.Context, status, warehouseFrom, warehouseTo string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	from := ` FROM tblmaterialtransferdiscrepancy x
+		JOIN tblmaterialreceivehdr r ON x.DocNo = r.DocNo
+		JOIN tblmaterialtransferdtl d ON x.TransferDocNo = d.DocNo AND x.TransferDNo = d.DNo
+		JOIN tblmaterialtransferhdr h ON d.DocNo = h.DocNo
+		JOIN tblwarehouse w ON h.WhsCodeFrom = w.WhsCode
+		JOIN tblwarehouse w2 ON h.WhsCodeTo = w2.WhsCode
+		JOIN tblitem i ON d.ItCode = i.ItCode
+		WHERE x.Status LIKE ? AND h.WhsCodeFrom LIKE ? AND h.WhsCodeTo LIKE ?`
+	args := []interface{}{"%" + status + "%", "%" + warehouseFrom + "%", "%" + warehouseTo + "%"}
+
+	if scope, scopeArgs := warehouseScope(ctx, "h.WhsCodeFrom", "h.WhsCodeTo"); scope != "" {
+		from += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
+	var totalRecords int
+	if err := t.DB.GetContext(ctx, &totalRecords, "SELECT COUNT(*)"+from, args...); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+
+	var totalPages, offset int
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+	}
+
+	data := make([]*intransit.Discrepancy, 0)
+	query := `SELECT
+			x.DocNo,
+			x.DNo,
+			r.DocDt,
+			x.TransferDocNo,
+			x.TransferDNo,
+			h.WhsCodeFrom,
+			w.WhsName AS WhsNameFrom,
+			h.WhsCodeTo,
+			w2.WhsName AS WhsNameTo,
+			d.ItCode,
+			i.ItName,
+			d.BatchNo,
+			x.Qty,
+			x.Status,
+			x.ResolveBy,
+			x.ResolveDt,
+			x.Remark` + from + `
+		ORDER BY r.DocDt DESC, x.DocNo, x.DNo
+		LIMIT ? OFFSET ?`
+	if err := t.DB.SelectContext(ctx, &data, query, append(args, param.PageSize, offset)...); err != nil {
+		return nil, fmt.Errorf("error Fetch discrepancy: %w", err)
+	}
+
+	j := offset
+	for _, d := range data {
+		j++
+		d.Number = uint(j)
+		d.Date = share.ToDatePicker(d.Date)
+	}
+
+	return &pagination.PaginationResponse{
+		Data:         data,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}, nil
+}
+
+func (t *InTransitRepository) Resolve(ctx context.Context, data *intransit.Resolve) (*intransit.Discrepancy, error) {
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	var line struct {
+		intransit.Discrepancy
+		Source string `db:"Source"`
+	}
+	query := `SELECT x.DocNo, x.DNo, x.TransferDocNo, x.TransferDNo, x.Qty, x.Status,
+			h.WhsCodeFrom, h.WhsCodeTo, d.ItCode, d.BatchNo, d.Source
+		FROM tblmaterialtransferdiscrepancy x
+		JOIN tblmaterialtransferdtl d ON x.TransferDocNo = d.DocNo AND x.TransferDNo = d.DNo
+		JOIN tblmaterialtransferhdr h ON d.DocNo = h.DocNo
+		WHERE x.DocNo = ? AND x.DNo = ?
+		FOR UPDATE`
+	if err = tx.GetContext(ctx, &line, query, data.DocNo, data.DNo); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = customerrors.ErrDataNotFound
+			return nil, err
+		}
+		return nil, fmt.Errorf("error fetching discrepancy: %w", err)
+	}
+
+	// discrepancy diselesaikan oleh gudang penerima yang mencatatnya
+	if err = checkWarehouse(ctx, line.WhsCodeTo); err != nil {
+		return nil, err
+	}
+	if line.Status != intransit.Open {
+		err = fmt.Errorf("%w: discrepancy already resolved as %s", customerrors.ErrInvalidInput, line.Status)
+		return nil, err
+	}
+
+	out := inventoryledger.Movement{
+		DocType:   "Material Transfer Loss",
+		DocNo:     line.DocNo,
+		DNo:       line.DNo,
+		DocDt:     data.ResolveDt[:8],
+		WhsCode:   intransit.Warehouse,
+		Source:    line.Source,
+		ItCode:    line.ItCode,
+		BatchNo:   line.BatchNo,
+		Qty:       line.Qty,
+		Direction: inventoryledger.Out,
+		Remark:    data.Remark,
+		CreateBy:  data.ResolveBy,
+		CreateDt:  data.ResolveDt,
+	}
+	movements := []inventoryledger.Movement{out}
+	column := "QtyLoss"
+	if data.Resolution == intransit.Return {
+		out.DocType = "Material Transfer Return"
+		in := out
+		in.WhsCode = line.WhsCodeFrom
+		in.Source = line.Source + "*R"
+		in.Direction = inventoryledger.In
+		movements = []inventoryledger.Movement{out, in}
+		column = "QtyReturned"
+	}
+
+	if err = t.Ledger.Post(ctx, tx, movements); err != nil {
+		return nil, err
+	}
+
+	if _, err = tx.ExecContext(ctx, "UPDATE tblmaterialtransferdtl SET "+column+" = "+column+" + ? WHERE DocNo = ? AND DNo = ?",
+		line.Qty, line.TransferDocNo, line.TransferDNo); err != nil {
+		log.Printf("Error Update Material Transfer: %+v", err)
+		return nil, fmt.Errorf("error Update Material Transfer: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, `UPDATE tblmaterialtransferdiscrepancy
+		SET Status = ?, ResolveBy = ?, ResolveDt = ?, Remark = ?
+		WHERE DocNo = ? AND DNo = ?`,
+		data.Resolution, data.ResolveBy, data.ResolveDt, data.Remark, data.DocNo, data.DNo); err != nil {
+		log.Printf("Error resolve discrepancy: %+v", err)
+		return nil, fmt.Errorf("error resolve discrepancy: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	result := line.Discrepancy
+	result.Status = data.Resolution
+	result.ResolveBy = nulldatatype.NewNullStringDataType(data.ResolveBy)
+	result.ResolveDate = nulldatatype.NewNullStringDataType(data.ResolveDt)
+	result.Remark = data.Remark
+	return &result, nil
+}
@@ -0,0 +1,274 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/intransit"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/tblmaterialreceive"
+	"gitlab.com/ayaka/internal/domain/tblmaterialtransfer"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+const (
+	queryDiscrepancyLine    = "SELECT x.DocNo, x.DNo, x.TransferDocNo, x.TransferDNo, x.Qty, x.Status, h.WhsCodeFrom, h.WhsCodeTo, d.ItCode, d.BatchNo, d.Source FROM tblmaterialtransferdiscrepancy x JOIN tblmaterialtransferdtl d ON x.TransferDocNo = d.DocNo AND x.TransferDNo = d.DNo JOIN tblmaterialtransferhdr h ON d.DocNo = h.DocNo WHERE x.DocNo = ? AND x.DNo = ? FOR UPDATE"
+	queryDiscrepancyResolve = "UPDATE tblmaterialtransferdiscrepancy SET Status = ?, ResolveBy = ?, ResolveDt = ?, Remark = ? WHERE DocNo = ? AND DNo = ?"
+	queryTransitLine        = "SELECT DocNo, DNo, Source, SuccessInd, Qty - QtyReceived - QtyLoss - QtyReturned AS Outstanding FROM tblmaterialtransferdtl WHERE DocNo = ? AND ItCode = ? AND BatchNo = ? AND CancelInd = 'N' AND InTransitInd = 'Y' ORDER BY SuccessInd, DNo LIMIT 1 FOR UPDATE"
+	queryReceiveSeq         = "INSERT INTO tbldocsequence (Category, SiteCode, Period, LastNo) VALUES (?, ?, ?, LAST_INSERT_ID(1)) ON DUPLICATE KEY UPDATE LastNo = LAST_INSERT_ID(LastNo + 1)"
+	queryReceiveLastNo      = "SELECT LAST_INSERT_ID()"
+	queryReceiveHeader      = "INSERT INTO tblmaterialreceivehdr ( DocNo, DocDt, WhsCodeFrom, WhsCodeTo, Remark, CreateDt, CreateBy ) VALUES (?, ?, ?, ?, ?, ?, ?);"
+	queryReceiveDetail      = "INSERT INTO tblmaterialreceivedtl ( DocNo, DNo, DocNoMaterialTransfer, ItCode, BatchNo, Source, QtyTransfer, QtyActual, Remark, CreateDt, CreateBy ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
+	queryReceiveReport      = "INSERT INTO tbltransferbetweenwhs ( DocNo, DocDt, WhsFrom, WhsTo, ItCode, BatchNo, Qty, Remark, CreateBy, CreateDt ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
+	queryTransferReceived   = "UPDATE tblmaterialtransferdtl SET QtyReceived = QtyReceived + ?, SuccessInd = 'Y' WHERE DocNo = ? AND DNo = ?"
+	queryDiscrepancyInsert  = "INSERT INTO tblmaterialtransferdiscrepancy ( DocNo, DNo, TransferDocNo, TransferDNo, Qty, Status, CreateBy, CreateDt ) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	queryTransferStatus     = "UPDATE tblmaterialtransferhdr h SET Status = CASE -- Jika semua detail sudah success WHEN ( SELECT COUNT(*) FROM tblmaterialtransferdtl WHERE DocNo = h.DocNo AND (CancelInd IS NULL OR CancelInd != 'Y') ) = ( SELECT COUNT(*) FROM tblmaterialtransferdtl WHERE DocNo = h.DocNo AND SuccessInd = 'Y' AND (CancelInd IS NULL OR CancelInd != 'Y') ) AND ( SELECT COUNT(*) FROM tblmaterialtransferdtl WHERE DocNo = h.DocNo AND (CancelInd IS NULL OR CancelInd != 'Y') ) > 0 THEN 'Success' -- Jika ada yang sudah mulai diterima (QtyActual > 0) WHEN EXISTS ( SELECT 1 FROM tblmaterialreceivedtl r WHERE r.DocNoMaterialTransfer = h.DocNo AND r.QtyActual > 0 ) THEN 'Partial' -- Tetap status sebelumnya ELSE Status END WHERE h.DocNo IN (?)"
+)
+
+// fakeLedger menangkap movement yang diposting tanpa menyentuh tblstock
+type fakeLedger struct {
+	inventoryledger.Repository
+	posted []inventoryledger.Movement
+}
+
+func (f *fakeLedger) Post(ctx context.Context, tx *sqlx.Tx, movements []inventoryledger.Movement) error {
+	f.posted = append(f.posted, movements...)
+	return nil
+}
+
+type InTransitRepositorySuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	repo    *InTransitRepository
+	ledger  *fakeLedger
+	db      *sqlx.DB
+}
+
+func (suite *InTransitRepositorySuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.ledger = &fakeLedger{}
+	suite.repo = &InTransitRepository{
+		DB:     &repository.Sqlx{DB: suite.db},
+		Ledger: suite.ledger,
+	}
+}
+
+func (suite *InTransitRepositorySuite) receiveRepo() *TblMaterialReceiveRepository {
+	return &TblMaterialReceiveRepository{
+		DB:     &repository.Sqlx{DB: suite.db},
+		Ledger: suite.ledger,
+		ID:     &formatid.GenerateIDHandler{},
+	}
+}
+
+func (suite *InTransitRepositorySuite) expectOpenDiscrepancy() {
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectQuery(queryDiscrepancyLine).
+		WithArgs("MR1", "001").
+		WillReturnRows(sqlmock.NewRows([]string{"DocNo", "DNo", "TransferDocNo", "TransferDNo", "Qty", "Status", "WhsCodeFrom", "WhsCodeTo", "ItCode", "BatchNo", "Source"}).
+			AddRow("MR1", "001", "MT1", "001", 2, intransit.Open, "WH1", "WH2", "IT001", "B1", "01*MT1*001"))
+}
+
+func receiveLine(qtyActual float32) *tblmaterialreceive.Create {
+	return &tblmaterialreceive.Create{
+		Date:        "20261018",
+		WhsCodeFrom: "WH1",
+		WhsCodeTo:   "WH2",
+		CreateBy:    "admin",
+		CreateDt:    "202610181000",
+		Details: []tblmaterialreceive.Detail{{
+			DNo:                   "001",
+			DocNoMaterialTransfer: "MT1",
+			ItCode:                "IT001",
+			BatchNo:               "B1",
+			QtyTransfer:           99,
+			QtyActual:             qtyActual,
+		}},
+	}
+}
+
+// transfer memindahkan qty dari gudang asal ke gudang transit
+func (suite *InTransitRepositorySuite) TestTransferMovements_ToTransit() {
+	movements := transferMovements("MT1", "20261018", "WH1", "admin", "20261018", tblmaterialtransfer.Detail{
+		DNo:     "001",
+		ItCode:  "IT001",
+		BatchNo: "B1",
+		Source:  "18*MT1*001",
+		Qty:     10,
+	})
+
+	suite.Require().Len(movements, 2)
+	suite.Equal("WH1", movements[0].WhsCode)
+	suite.Equal(inventoryledger.Out, movements[0].Direction)
+	suite.Equal(intransit.Warehouse, movements[1].WhsCode)
+	suite.Equal(inventoryledger.In, movements[1].Direction)
+	suite.Equal(float32(10), movements[1].Qty)
+	suite.Equal(movements[0].Source, movements[1].Source)
+}
+
+// terima kurang dari yang dikirim: stok diambil dari transit sebesar yang
+// diterima, baris transfer ditutup dan kekurangannya jadi discrepancy Open
+func (suite *InTransitRepositorySuite) TestReceive_ShortReceiptCreatesDiscrepancy() {
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectExec(queryReceiveSeq).
+		WithArgs("MaterialReceive", "R1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mockSQL.ExpectQuery(queryReceiveLastNo).
+		WillReturnRows(sqlmock.NewRows([]string{"LAST_INSERT_ID()"}).AddRow(1))
+	suite.mockSQL.ExpectExec(queryReceiveHeader).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectQuery(queryTransitLine).
+		WithArgs("MT1", "IT001", "B1").
+		WillReturnRows(sqlmock.NewRows([]string{"DocNo", "DNo", "Source", "SuccessInd", "Outstanding"}).
+			AddRow("MT1", "001", "10*MT1*001", "N", 10))
+	suite.mockSQL.ExpectExec(queryTransferReceived).
+		WithArgs(float32(8), "MT1", "001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryReceiveDetail).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryReceiveReport).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryDiscrepancyInsert).
+		WithArgs(sqlmock.AnyArg(), "001", "MT1", "001", float32(2), intransit.Open, "admin", "202610181000").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryTransferStatus).
+		WithArgs("MT1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectCommit()
+
+	data, err := suite.receiveRepo().Create(context.Background(), receiveLine(8))
+
+	suite.Require().NoError(err)
+	// qty kirim diambil dari sisa transit, bukan dari request
+	suite.Equal(float32(10), data.Details[0].QtyTransfer)
+	suite.Require().Len(suite.ledger.posted, 2)
+	suite.Equal(intransit.Warehouse, suite.ledger.posted[0].WhsCode)
+	suite.Equal("10*MT1*001", suite.ledger.posted[0].Source)
+	suite.Equal(inventoryledger.Out, suite.ledger.posted[0].Direction)
+	suite.Equal(float32(8), suite.ledger.posted[0].Qty)
+	suite.Equal("WH2", suite.ledger.posted[1].WhsCode)
+	suite.Equal(inventoryledger.In, suite.ledger.posted[1].Direction)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// baris transfer yang sudah ditutup tidak bisa diterima lagi
+func (suite *InTransitRepositorySuite) TestReceive_ClosedLineRejected() {
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectExec(queryReceiveSeq).
+		WithArgs("MaterialReceive", "R1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mockSQL.ExpectQuery(queryReceiveLastNo).
+		WillReturnRows(sqlmock.NewRows([]string{"LAST_INSERT_ID()"}).AddRow(2))
+	suite.mockSQL.ExpectExec(queryReceiveHeader).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectQuery(queryTransitLine).
+		WithArgs("MT1", "IT001", "B1").
+		WillReturnRows(sqlmock.NewRows([]string{"DocNo", "DNo", "Source", "SuccessInd", "Outstanding"}).
+			AddRow("MT1", "001", "10*MT1*001", "Y", 2))
+	suite.mockSQL.ExpectRollback()
+
+	_, err := suite.receiveRepo().Create(context.Background(), receiveLine(2))
+
+	suite.ErrorIs(err, customerrors.ErrInvalidQuantity)
+	suite.Empty(suite.ledger.posted)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// Loss mengeluarkan qty discrepancy dari gudang transit saja
+func (suite *InTransitRepositorySuite) TestResolve_Loss() {
+	suite.expectOpenDiscrepancy()
+	suite.mockSQL.ExpectExec("UPDATE tblmaterialtransferdtl SET QtyLoss = QtyLoss + ? WHERE DocNo = ? AND DNo = ?").
+		WithArgs(float32(2), "MT1", "001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryDiscrepancyResolve).
+		WithArgs(intransit.Loss, "admin", "202610181000", sqlmock.AnyArg(), "MR1", "001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectCommit()
+
+	result, err := suite.repo.Resolve(context.Background(), &intransit.Resolve{
+		DocNo:      "MR1",
+		DNo:        "001",
+		Resolution: intransit.Loss,
+		ResolveBy:  "admin",
+		ResolveDt:  "202610181000",
+	})
+
+	suite.Require().NoError(err)
+	suite.Equal(intransit.Loss, result.Status)
+	suite.Require().Len(suite.ledger.posted, 1)
+	suite.Equal("Material Transfer Loss", suite.ledger.posted[0].DocType)
+	suite.Equal(intransit.Warehouse, suite.ledger.posted[0].WhsCode)
+	suite.Equal(inventoryledger.Out, suite.ledger.posted[0].Direction)
+	suite.Equal(float32(2), suite.ledger.posted[0].Qty)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// Return memindahkan qty discrepancy dari transit kembali ke gudang asal
+// dengan source baru
+func (suite *InTransitRepositorySuite) TestResolve_Return() {
+	suite.expectOpenDiscrepancy()
+	suite.mockSQL.ExpectExec("UPDATE tblmaterialtransferdtl SET QtyReturned = QtyReturned + ? WHERE DocNo = ? AND DNo = ?").
+		WithArgs(float32(2), "MT1", "001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryDiscrepancyResolve).
+		WithArgs(intransit.Return, "admin", "202610181000", sqlmock.AnyArg(), "MR1", "001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectCommit()
+
+	_, err := suite.repo.Resolve(context.Background(), &intransit.Resolve{
+		DocNo:      "MR1",
+		DNo:        "001",
+		Resolution: intransit.Return,
+		ResolveBy:  "admin",
+		ResolveDt:  "202610181000",
+	})
+
+	suite.Require().NoError(err)
+	suite.Require().Len(suite.ledger.posted, 2)
+	out, in := suite.ledger.posted[0], suite.ledger.posted[1]
+	suite.Equal("Material Transfer Return", out.DocType)
+	suite.Equal(intransit.Warehouse, out.WhsCode)
+	suite.Equal(inventoryledger.Out, out.Direction)
+	suite.Equal("WH1", in.WhsCode)
+	suite.Equal(inventoryledger.In, in.Direction)
+	suite.Equal("01*MT1*001*R", in.Source)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func (suite *InTransitRepositorySuite) TearDownTest() {
+	suite.db.Close()
+}
+
+// discrepancy yang sudah diselesaikan tidak boleh diposting ke ledger lagi
+func (suite *InTransitRepositorySuite) TestResolve_AlreadyResolved() {
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectQuery(queryDiscrepancyLine).
+		WithArgs("MR1", "001").
+		WillReturnRows(sqlmock.NewRows([]string{"DocNo", "DNo", "TransferDocNo", "TransferDNo", "Qty", "Status", "WhsCodeFrom", "WhsCodeTo", "ItCode", "BatchNo", "Source"}).
+			AddRow("MR1", "001", "MT1", "001", 2, intransit.Loss, "WH1", "WH2", "IT001", "B1", "01*MT1*001"))
+	suite.mockSQL.ExpectRollback()
+
+	_, err := suite.repo.Resolve(context.Background(), &intransit.Resolve{
+		DocNo:      "MR1",
+		DNo:        "001",
+		Resolution: intransit.Return,
+		ResolveBy:  "admin",
+		ResolveDt:  "202610181000",
+	})
+
+	suite.ErrorIs(err, customerrors.ErrInvalidInput)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func TestInTransitRepository(t *testing.T) {
+	suite.Run(t, new(InTransitRepositorySuite))
+}
@@ -0,0 +1,619 @@
+package sqlx
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/domain/bin"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
+	"gitlab.com/ayaka/internal/domain/journal"
+	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+	"gitlab.com/ayaka/internal/domain/stockopname"
+	"gitlab.com/ayaka/internal/domain/stockvaluation"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+// InventoryLedgerRepository adalah satu-satunya tempat yang menulis ke
+// tblstocksummary, tblstockmovement dan tblhistoryofstock. Repository dokumen
+// memanggil Post / Reverse di dalam transaksi mereka sendiri. Nilai persediaan
+// dan jurnal GL-nya ikut di-posting lewat Valuation dan Journal pada transaksi
+// yang sama. Gudang yang sedang stock opname ditolak lewat Opname.
+//
+// Saldo dan movement dicatat per bin, satu movement bisa terpecah menjadi
+// beberapa baris dengan DNo yang sama jika diambil dari beberapa bin.
+//
+//	ALTER TABLE tblstockmovement ADD COLUMN Bin VARCHAR(16) NOT NULL DEFAULT '-' AFTER WhsCode;
+type InventoryLedgerRepository struct {
+	Valuation stockvaluation.Repository `inject:"stockValuationRepository"`
+	Journal   journal.Repository        `inject:"journalRepository"`
+	Opname    stockopname.Repository    `inject:"stockOpnameRepository"`
+}
+
+// stockKey adalah granularity saldo di tblstocksummary.
+type stockKey struct {
+	WhsCode string
+	ItCode  string
+	BatchNo string
+	Source  string
+}
+
+func (t *InventoryLedgerRepository) Post(ctx context.Context, tx *sqlx.Tx, movements []inventoryledger.Movement) error {
+	if len(movements) == 0 {
+		return nil
+	}
+
+	// barang keluar mengurangi saldo, jadi saldonya dikunci dan dicek dulu
+	required := make(map[stockKey]float32)
+	for _, m := range movements {
+		if m.Direction == inventoryledger.Out {
+			required[movementKey(m)] += m.Qty
+		}
+	}
+	stocks, err := reserveStock(ctx, tx, required)
+	if err != nil {
+		return err
+	}
+
+	var placeholdersSummary, placeholdersMovement, placeholdersHistory []string
+	var argsSummary, argsMovement, argsHistory []interface{}
+	// movement per bin final, untuk cek opname per bin
+	var binned []inventoryledger.Movement
+
+	for _, m := range movements {
+		parts := []binQty{{binOrUnassigned(m.Bin), m.Qty}}
+		if m.Direction == inventoryledger.Out {
+			if parts, err = takeFromBins(stocks[movementKey(m)], m, m.Qty); err != nil {
+				return err
+			}
+		}
+
+		for _, part := range parts {
+			b := m
+			b.Bin = part.Bin
+			binned = append(binned, b)
+
+			qty, qty2, qty3 := splitQty(m.Direction, part.Qty)
+
+			placeholdersSummary = append(placeholdersSummary, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+			argsSummary = append(argsSummary,
+				m.WhsCode,
+				"-",
+				part.Bin,
+				m.Source,
+				m.ItCode,
+				m.BatchNo,
+				qty,
+				qty2,
+				qty3,
+				m.CreateBy,
+				m.CreateDt,
+			)
+
+			placeholdersMovement = append(placeholdersMovement, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+			argsMovement = append(argsMovement,
+				m.DocType,
+				m.DocNo,
+				m.DNo,
+				"N",
+				m.DocDt,
+				m.WhsCode,
+				part.Bin,
+				m.Source,
+				m.ItCode,
+				m.BatchNo,
+				qty,
+				qty2,
+				qty3,
+				m.Remark,
+				m.CreateBy,
+				m.CreateDt,
+			)
+		}
+
+		// history hanya mencatat source yang bertambah (stok awal / barang masuk)
+		if m.Direction != inventoryledger.Out {
+			placeholdersHistory = append(placeholdersHistory, "(?, ?, ?, ?, ?, ?)")
+			argsHistory = append(argsHistory,
+				m.ItCode,
+				m.BatchNo,
+				m.Source,
+				"N",
+				m.CreateBy,
+				m.CreateDt,
+			)
+		}
+	}
+
+	if t.Opname != nil {
+		if err := t.Opname.CheckFrozen(ctx, tx, binned); err != nil {
+			return err
+		}
+	}
+
+	if err := insertStockSummary(ctx, tx, placeholdersSummary, argsSummary); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO tblstockmovement (
+			DocType,
+			DocNo,
+			DNo,
+			CancelInd,
+			DocDt,
+			WhsCode,
+			Bin,
+			Source,
+			ItCode,
+			BatchNo,
+			Qty,
+			Qty2,
+			Qty3,
+			Remark,
+			CreateBy,
+			CreateDt
+		) VALUES ` + strings.Join(placeholdersMovement, ",") + ";"
+	if _, err := tx.ExecContext(ctx, query, argsMovement...); err != nil {
+		log.Printf("Error insert stock movement: %+v", err)
+		return fmt.Errorf("error Insert Stock Movement: %w", err)
+	}
+
+	if len(placeholdersHistory) > 0 {
+		query = `INSERT INTO tblhistoryofstock (
+			ItCode,
+			BatchNo,
+			Source,
+			CancelInd,
+			CreateBy,
+			CreateDt
+		) VALUES ` + strings.Join(placeholdersHistory, ",") + ";"
+		if _, err := tx.ExecContext(ctx, query, argsHistory...); err != nil {
+			log.Printf("Error insert history of stock: %+v", err)
+			return fmt.Errorf("error Insert History of Stock: %w", err)
+		}
+	}
+
+	valued := valuedMovements(movements)
+	if t.Valuation == nil || len(valued) == 0 {
+		return nil
+	}
+	entries, err := t.Valuation.Post(ctx, tx, valued)
+	if err != nil || t.Journal == nil {
+		return err
+	}
+
+	return t.Journal.Post(ctx, tx, entries, false)
+}
+
+// Reverse membatalkan movement yang sebelumnya di-Post. Movement dari dokumen
+// hanya dipakai untuk menentukan baris (DocType, DocNo, DNo) yang dibatalkan
+// serta CreateBy / CreateDt pembatalan; gudang, source, batch, bin dan quantity
+// diambil dari tblstockmovement yang tersimpan sehingga pembatalan tidak bisa
+// membuat atau menghilangkan stok. Saldo tidak di-update di tempat, melainkan
+// ditambah baris summary dengan quantity negatif pada kolom yang sama, sehingga
+// SUM(Qty + Qty2 - Qty3) kembali seperti semula.
+func (t *InventoryLedgerRepository) Reverse(ctx context.Context, tx *sqlx.Tx, movements []inventoryledger.Movement) error {
+	if len(movements) == 0 {
+		return nil
+	}
+
+	movements, err := postedMovements(ctx, tx, movements)
+	if err != nil || len(movements) == 0 {
+		return err
+	}
+	if t.Opname != nil {
+		if err := t.Opname.CheckFrozen(ctx, tx, movements); err != nil {
+			return err
+		}
+	}
+
+	// membatalkan barang masuk / stok awal mengurangi saldo, stok yang sudah
+	// terpakai dokumen lain tidak boleh ikut ditarik
+	required := make(map[stockKey]float32)
+	for _, m := range movements {
+		if m.Direction != inventoryledger.Out {
+			required[movementKey(m)] += m.Qty
+		}
+	}
+	stocks, err := reserveStock(ctx, tx, required)
+	if err != nil {
+		return err
+	}
+
+	var placeholdersSummary, inMovement, inHistory []string
+	var argsSummary, argsMovement, argsHistory []interface{}
+	lines := make(map[docLineKey]bool)
+
+	for _, m := range movements {
+		// barang keluar dikembalikan ke bin asal pengambilannya
+		parts := []binQty{{binOrUnassigned(m.Bin), m.Qty}}
+		if m.Direction != inventoryledger.Out {
+			if parts, err = takeFromBins(stocks[movementKey(m)], m, m.Qty); err != nil {
+				return err
+			}
+		}
+
+		for _, part := range parts {
+			qty, qty2, qty3 := splitQty(m.Direction, -part.Qty)
+
+			placeholdersSummary = append(placeholdersSummary, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+			argsSummary = append(argsSummary,
+				m.WhsCode,
+				"-",
+				part.Bin,
+				m.Source,
+				m.ItCode,
+				m.BatchNo,
+				qty,
+				qty2,
+				qty3,
+				m.CreateBy,
+				m.CreateDt,
+			)
+		}
+
+		key := docLineKey{m.DocType, m.DocNo, m.DNo}
+		if !lines[key] {
+			lines[key] = true
+			inMovement = append(inMovement, "(?, ?, ?)")
+			argsMovement = append(argsMovement, m.DocType, m.DocNo, m.DNo)
+		}
+
+		inHistory = append(inHistory, "(?, ?, ?)")
+		argsHistory = append(argsHistory, m.ItCode, m.Source, m.BatchNo)
+	}
+
+	if err := insertStockSummary(ctx, tx, placeholdersSummary, argsSummary); err != nil {
+		return err
+	}
+
+	query := `UPDATE tblstockmovement
+		SET CancelInd = 'Y'
+		WHERE (DocType, DocNo, DNo) IN (` + strings.Join(inMovement, ",") + `)`
+	if _, err := tx.ExecContext(ctx, query, argsMovement...); err != nil {
+		log.Printf("Failed to update stock movement: %+v", err)
+		return fmt.Errorf("error updating stock movement: %w", err)
+	}
+
+	// source hanya hilang dari history jika semua movement-nya sudah di-cancel
+	query = `UPDATE tblhistoryofstock h
+		SET h.CancelInd = 'Y'
+		WHERE (h.ItCode, h.Source, h.BatchNo) IN (` + strings.Join(inHistory, ",") + `)
+		AND NOT EXISTS (
+			SELECT 1 FROM tblstockmovement m
+			WHERE m.ItCode = h.ItCode
+			AND m.Source = h.Source
+			AND m.BatchNo = h.BatchNo
+			AND m.CancelInd = 'N'
+		)`
+	if _, err := tx.ExecContext(ctx, query, argsHistory...); err != nil {
+		log.Printf("Failed to update history of stock: %+v", err)
+		return fmt.Errorf("error updating history of stock: %w", err)
+	}
+
+	valued := valuedMovements(mergeBins(movements))
+	if t.Valuation == nil || len(valued) == 0 {
+		return nil
+	}
+	entries, err := t.Valuation.Reverse(ctx, tx, valued)
+	if err != nil || t.Journal == nil {
+		return err
+	}
+
+	return t.Journal.Post(ctx, tx, entries, true)
+}
+
+// reserveStock mengunci baris tblstocksummary per key dengan SELECT ... FOR UPDATE
+// sampai transaksi selesai, lalu menolak jika saldo tidak mencukupi. Key dikunci
+// dengan urutan yang sama di setiap transaksi agar tidak terjadi deadlock.
+// Saldo dikembalikan per bin, sudah urut sesuai binPickOrder.
+func reserveStock(ctx context.Context, tx *sqlx.Tx, required map[stockKey]float32) (map[stockKey][]*binStock, error) {
+	keys := make([]stockKey, 0, len(required))
+	for key := range required {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.WhsCode != b.WhsCode {
+			return a.WhsCode < b.WhsCode
+		}
+		if a.ItCode != b.ItCode {
+			return a.ItCode < b.ItCode
+		}
+		if a.BatchNo != b.BatchNo {
+			return a.BatchNo < b.BatchNo
+		}
+		return a.Source < b.Source
+	})
+
+	query := `SELECT s.Bin, COALESCE(SUM(s.Qty + s.Qty2 - s.Qty3), 0) AS Qty
+		FROM tblstocksummary s
+		LEFT JOIN tblbin b ON s.WhsCode = b.WhsCode AND s.Bin = b.BinCode
+		WHERE s.WhsCode = ?
+		AND s.ItCode = ?
+		AND s.BatchNo = ?
+		AND s.Source = ?
+		GROUP BY s.Bin, b.Zone, b.Aisle, b.Rack, b.Level
+		ORDER BY ` + binPickOrder + `
+		FOR UPDATE`
+
+	result := make(map[stockKey][]*binStock, len(keys))
+	for _, key := range keys {
+		var stocks []*binStock
+		if err := tx.SelectContext(ctx, &stocks, query, key.WhsCode, key.ItCode, key.BatchNo, key.Source); err != nil {
+			log.Printf("Error lock stock summary: %+v", err)
+			return nil, fmt.Errorf("error Lock Stock Summary: %w", err)
+		}
+
+		var available float32
+		for _, s := range stocks {
+			if s.Qty > 0 {
+				available += s.Qty
+			}
+		}
+		if available < required[key] {
+			return nil, fmt.Errorf("%w: item %s batch %s in %s (available %v, required %v)",
+				customerrors.ErrInsufficientStock, key.ItCode, key.BatchNo, key.WhsCode, available, required[key])
+		}
+		result[key] = stocks
+	}
+
+	return result, nil
+}
+
+// binPickOrder adalah urutan pengambilan stok: yang belum di-put-away dulu,
+// lalu per zone, aisle, rack dan level. Dipakai juga oleh pick list supaya
+// bin yang disarankan sama dengan bin yang dikurangi saat posting.
+const binPickOrder = "s.Bin <> '-', b.Zone, b.Aisle, b.Rack, b.Level, s.Bin"
+
+type binStock struct {
+	Bin string  `db:"Bin"`
+	Qty float32 `db:"Qty"`
+}
+
+// binQty adalah bagian quantity satu movement yang ditulis ke satu bin.
+type binQty struct {
+	Bin string
+	Qty float32
+}
+
+// selisih pembulatan float32 saat membagi quantity ke beberapa bin
+const binTolerance = 0.0001
+
+// valuedMovements membuang perpindahan antar bin, cost layer disimpan per gudang
+// sehingga tidak perlu ikut dikonsumsi lalu dibuat ulang.
+func valuedMovements(movements []inventoryledger.Movement) []inventoryledger.Movement {
+	valued := make([]inventoryledger.Movement, 0, len(movements))
+	for _, m := range movements {
+		if !m.BinMove {
+			valued = append(valued, m)
+		}
+	}
+	return valued
+}
+
+func movementKey(m inventoryledger.Movement) stockKey {
+	return stockKey{m.WhsCode, m.ItCode, m.BatchNo, m.Source}
+}
+
+func binOrUnassigned(code string) string {
+	if code == "" {
+		return bin.Unassigned
+	}
+	return code
+}
+
+// takeFromBins mengurangi saldo bin hasil reserveStock sebanyak qty. Movement
+// tanpa bin diambil berurutan dari bin yang ada, selain itu hanya dari bin-nya.
+func takeFromBins(stocks []*binStock, m inventoryledger.Movement, qty float32) ([]binQty, error) {
+	var parts []binQty
+	for _, s := range stocks {
+		if qty <= binTolerance {
+			break
+		}
+		if s.Qty <= 0 || (m.Bin != "" && s.Bin != m.Bin) {
+			continue
+		}
+		take := min(s.Qty, qty)
+		s.Qty -= take
+		qty -= take
+		parts = append(parts, binQty{s.Bin, take})
+	}
+	if qty > binTolerance {
+		return nil, fmt.Errorf("%w: item %s batch %s in %s bin %s (short %v)",
+			customerrors.ErrInsufficientStock, m.ItCode, m.BatchNo, m.WhsCode, binOrUnassigned(m.Bin), qty)
+	}
+
+	return parts, nil
+}
+
+type docLineKey struct {
+	DocType string
+	DocNo   string
+	DNo     string
+}
+
+// postedMovements mengunci dan membaca baris tblstockmovement yang belum
+// di-cancel untuk baris dokumen yang diminta, satu movement per baris tersimpan
+// (per bin). CreateBy / CreateDt, Remark dan BinMove diambil dari movement
+// dokumen. Baris yang tidak punya movement (sudah di-cancel) dilewati.
+func postedMovements(ctx context.Context, tx *sqlx.Tx, movements []inventoryledger.Movement) ([]inventoryledger.Movement, error) {
+	requested := make(map[docLineKey]inventoryledger.Movement, len(movements))
+	var placeholders []string
+	var args []interface{}
+	for _, m := range movements {
+		key := docLineKey{m.DocType, m.DocNo, m.DNo}
+		if _, ok := requested[key]; ok {
+			continue
+		}
+		requested[key] = m
+		placeholders = append(placeholders, "(?, ?, ?)")
+		args = append(args, m.DocType, m.DocNo, m.DNo)
+	}
+
+	var rows []struct {
+		DocType string  `db:"DocType"`
+		DocNo   string  `db:"DocNo"`
+		DNo     string  `db:"DNo"`
+		DocDt   string  `db:"DocDt"`
+		WhsCode string  `db:"WhsCode"`
+		Bin     string  `db:"Bin"`
+		Source  string  `db:"Source"`
+		ItCode  string  `db:"ItCode"`
+		BatchNo string  `db:"BatchNo"`
+		Qty     float32 `db:"Qty"`
+		Qty2    float32 `db:"Qty2"`
+		Qty3    float32 `db:"Qty3"`
+	}
+	query := `SELECT DocType, DocNo, DNo, DocDt, WhsCode, Bin, Source, ItCode, BatchNo, Qty, Qty2, Qty3
+		FROM tblstockmovement
+		WHERE CancelInd = 'N'
+		AND (DocType, DocNo, DNo) IN (` + strings.Join(placeholders, ",") + `)
+		ORDER BY DocType, DocNo, DNo, WhsCode, Bin
+		FOR UPDATE`
+	if err := tx.SelectContext(ctx, &rows, query, args...); err != nil {
+		log.Printf("Error get stock movement: %+v", err)
+		return nil, fmt.Errorf("error Get Stock Movement: %w", err)
+	}
+
+	result := make([]inventoryledger.Movement, 0, len(rows))
+	for _, row := range rows {
+		m := requested[docLineKey{row.DocType, row.DocNo, row.DNo}]
+		m.DocDt = row.DocDt
+		m.WhsCode = row.WhsCode
+		m.Source = row.Source
+		m.ItCode = row.ItCode
+		m.BatchNo = row.BatchNo
+		m.Bin = row.Bin
+		m.UnitCost = 0
+
+		switch {
+		case row.Qty3 != 0:
+			m.Direction, m.Qty = inventoryledger.Out, row.Qty3
+		case row.Qty2 != 0:
+			m.Direction, m.Qty = inventoryledger.In, row.Qty2
+		default:
+			m.Direction, m.Qty = inventoryledger.Initial, row.Qty
+		}
+		// barang masuk yang belum di-put-away boleh ditarik dari bin mana saja
+		if m.Direction != inventoryledger.Out && m.Bin == bin.Unassigned {
+			m.Bin = ""
+		}
+
+		result = append(result, m)
+	}
+
+	return result, nil
+}
+
+// reverseLine movement untuk membatalkan satu baris dokumen, isinya dibaca
+// Reverse dari tblstockmovement.
+func reverseLine(docType, docNo, dNo, cancelBy, cancelDt string) inventoryledger.Movement {
+	return inventoryledger.Movement{
+		DocType:  docType,
+		DocNo:    docNo,
+		DNo:      dNo,
+		CreateBy: cancelBy,
+		CreateDt: cancelDt,
+	}
+}
+
+// documentLines movement pembatalan untuk semua baris dokumen yang pernah di-Post,
+// dipakai dokumen yang di-cancel per header.
+func documentLines(ctx context.Context, tx *sqlx.Tx, docType, docNo, cancelBy, cancelDt string) ([]inventoryledger.Movement, error) {
+	var dnos []string
+	query := `SELECT DISTINCT DNo FROM tblstockmovement WHERE DocType = ? AND DocNo = ? AND CancelInd = 'N' ORDER BY DNo`
+	if err := tx.SelectContext(ctx, &dnos, query, docType, docNo); err != nil {
+		log.Printf("Error get stock movement line: %+v", err)
+		return nil, fmt.Errorf("error Get Stock Movement Line: %w", err)
+	}
+
+	movements := make([]inventoryledger.Movement, 0, len(dnos))
+	for _, dNo := range dnos {
+		movements = append(movements, reverseLine(docType, docNo, dNo, cancelBy, cancelDt))
+	}
+
+	return movements, nil
+}
+
+// mergeBins menggabungkan movement yang terpecah per bin, valuasi dicatat per gudang.
+func mergeBins(movements []inventoryledger.Movement) []inventoryledger.Movement {
+	type mergeKey struct {
+		docLineKey
+		stockKey
+		Direction inventoryledger.Direction
+	}
+
+	index := make(map[mergeKey]int)
+	merged := make([]inventoryledger.Movement, 0, len(movements))
+	for _, m := range movements {
+		key := mergeKey{docLineKey{m.DocType, m.DocNo, m.DNo}, movementKey(m), m.Direction}
+		if i, ok := index[key]; ok {
+			merged[i].Qty += m.Qty
+			continue
+		}
+		index[key] = len(merged)
+		m.Bin = ""
+		merged = append(merged, m)
+	}
+
+	return merged
+}
+
+func insertStockSummary(ctx context.Context, tx *sqlx.Tx, placeholders []string, args []interface{}) error {
+	query := `INSERT INTO tblstocksummary (
+			WhsCode,
+			Lot,
+			Bin,
+			Source,
+			ItCode,
+			BatchNo,
+			Qty,
+			Qty2,
+			Qty3,
+			CreateBy,
+			CreateDt
+		) VALUES ` + strings.Join(placeholders, ",") + ";"
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		log.Printf("Error insert stock summary: %+v", err)
+		return fmt.Errorf("error Insert Stock Summary: %w", err)
+	}
+
+	return nil
+}
+
+func splitQty(direction inventoryledger.Direction, qty float32) (float32, float32, float32) {
+	switch direction {
+	case inventoryledger.In:
+		return 0, qty, 0
+	case inventoryledger.Out:
+		return 0, 0, qty
+	default:
+		return qty, 0, 0
+	}
+}
+
+// cancelIndByDNo mengambil status CancelInd per DNo dari tabel detail dokumen,
+// dipakai untuk menentukan baris mana yang baru saja di-cancel sebelum Reverse.
+func cancelIndByDNo(ctx context.Context, tx *sqlx.Tx, table, docNo string) (map[string]booldatatype.BoolDataType, error) {
+	var rows []struct {
+		DNo       string                    `db:"DNo"`
+		CancelInd booldatatype.BoolDataType `db:"CancelInd"`
+	}
+
+	query := "SELECT DNo, CancelInd FROM " + table + " WHERE DocNo = ?"
+	if err := tx.SelectContext(ctx, &rows, query, docNo); err != nil {
+		log.Printf("Failed to fetch existing cancel status: %+v", err)
+		return nil, fmt.Errorf("error fetching existing cancel status: %w", err)
+	}
+
+	result := make(map[string]booldatatype.BoolDataType, len(rows))
+	for _, row := range rows {
+		result[row.DNo] = row.CancelInd
+	}
+
+	return result, nil
+}
@@ -0,0 +1,378 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/domain/batch"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
+	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+const (
+	querySummary  = "INSERT INTO tblstocksummary ( WhsCode, Lot, Bin, Source, ItCode, BatchNo, Qty, Qty2, Qty3, CreateBy, CreateDt ) VALUES "
+	queryMovement = "INSERT INTO tblstockmovement ( DocType, DocNo, DNo, CancelInd, DocDt, WhsCode, Bin, Source, ItCode, BatchNo, Qty, Qty2, Qty3, Remark, CreateBy, CreateDt ) VALUES "
+	queryHistory  = "INSERT INTO tblhistoryofstock ( ItCode, BatchNo, Source, CancelInd, CreateBy, CreateDt ) VALUES "
+	queryPosted   = "SELECT DocType, DocNo, DNo, DocDt, WhsCode, Bin, Source, ItCode, BatchNo, Qty, Qty2, Qty3 FROM tblstockmovement WHERE CancelInd = 'N' AND (DocType, DocNo, DNo) IN ((?, ?, ?)) ORDER BY DocType, DocNo, DNo, WhsCode, Bin FOR UPDATE"
+	queryLock     = "SELECT s.Bin, COALESCE(SUM(s.Qty + s.Qty2 - s.Qty3), 0) AS Qty FROM tblstocksummary s LEFT JOIN tblbin b ON s.WhsCode = b.WhsCode AND s.Bin = b.BinCode WHERE s.WhsCode = ? AND s.ItCode = ? AND s.BatchNo = ? AND s.Source = ? GROUP BY s.Bin, b.Zone, b.Aisle, b.Rack, b.Level ORDER BY s.Bin <> '-', b.Zone, b.Aisle, b.Rack, b.Level, s.Bin FOR UPDATE"
+)
+
+// fakeBatch tidak ada batch yang kedaluwarsa
+type fakeBatch struct {
+	batch.Repository
+}
+
+func (fakeBatch) CheckExpired(ctx context.Context, tx *sqlx.Tx, docDt string, lines []batch.Line) error {
+	return nil
+}
+
+// expectDocNo nomor dokumen dari sequence yang sudah ada
+func expectDocNo(mock sqlmock.Sqlmock, category string) {
+	mock.ExpectExec(queryReceiveSeq).
+		WithArgs(category, "R1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(queryReceiveLastNo).
+		WillReturnRows(sqlmock.NewRows([]string{"LAST_INSERT_ID()"}).AddRow(1))
+}
+
+// expectPickedSourceLock baris keluar harus mengunci stok di Source yang dipilih
+// dari stok, bukan Source baru dokumen. Saldonya kosong supaya Post berhenti di sini.
+func expectPickedSourceLock(mock sqlmock.Sqlmock, whsCode, itCode, batchNo, source string) {
+	mock.ExpectQuery(queryLock).
+		WithArgs(whsCode, itCode, batchNo, source).
+		WillReturnRows(sqlmock.NewRows([]string{"Bin", "Qty"}))
+	mock.ExpectRollback()
+}
+
+type InventoryLedgerRepositorySuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	repo    *InventoryLedgerRepository
+	db      *sqlx.DB
+	tx      *sqlx.Tx
+}
+
+func (suite *InventoryLedgerRepositorySuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.repo = &InventoryLedgerRepository{}
+
+	suite.mockSQL.ExpectBegin()
+	suite.tx, err = suite.db.Beginx()
+	suite.Require().NoError(err)
+}
+
+func (suite *InventoryLedgerRepositorySuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func movement(direction inventoryledger.Direction) inventoryledger.Movement {
+	return inventoryledger.Movement{
+		DocType:   "Stock Mutation (From)",
+		DocNo:     "0001/R1/SM/01/25",
+		DNo:       "001",
+		DocDt:     "20250101",
+		WhsCode:   "WHS01",
+		Source:    "01*0001/R1/IS/01/25*001",
+		ItCode:    "ITM01",
+		BatchNo:   "B01",
+		Qty:       5,
+		Direction: direction,
+		Remark:    nulldatatype.NewNullStringDataType("remark"),
+		CreateBy:  "USR01",
+		CreateDt:  "20250101",
+	}
+}
+
+// postedRows baris tblstockmovement hasil Post dari movement m
+func postedRows(m inventoryledger.Movement, bin string) *sqlmock.Rows {
+	qty, qty2, qty3 := splitQty(m.Direction, m.Qty)
+	return sqlmock.NewRows([]string{"DocType", "DocNo", "DNo", "DocDt", "WhsCode", "Bin", "Source", "ItCode", "BatchNo", "Qty", "Qty2", "Qty3"}).
+		AddRow(m.DocType, m.DocNo, m.DNo, m.DocDt, m.WhsCode, bin, m.Source, m.ItCode, m.BatchNo, qty, qty2, qty3)
+}
+
+// barang keluar hanya mengisi Qty3 dan tidak menambah history
+func (suite *InventoryLedgerRepositorySuite) TestPost_OutMovement() {
+	m := movement(inventoryledger.Out)
+
+	suite.mockSQL.ExpectQuery(queryLock).
+		WithArgs(m.WhsCode, m.ItCode, m.BatchNo, m.Source).
+		WillReturnRows(sqlmock.NewRows([]string{"Bin", "Qty"}).AddRow("-", 10))
+
+	suite.mockSQL.ExpectExec(querySummary+"(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);").
+		WithArgs(m.WhsCode, "-", "-", m.Source, m.ItCode, m.BatchNo, float64(0), float64(0), float64(5), m.CreateBy, m.CreateDt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mockSQL.ExpectExec(queryMovement+"(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);").
+		WithArgs(m.DocType, m.DocNo, m.DNo, "N", m.DocDt, m.WhsCode, "-", m.Source, m.ItCode, m.BatchNo, float64(0), float64(0), float64(5), "remark", m.CreateBy, m.CreateDt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := suite.repo.Post(context.Background(), suite.tx, []inventoryledger.Movement{m})
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// keluar 5 tanpa bin: unassigned 2 habis dulu, sisanya 3 dari bin pertama
+func (suite *InventoryLedgerRepositorySuite) TestPost_OutSplitsAcrossBins() {
+	m := movement(inventoryledger.Out)
+
+	suite.mockSQL.ExpectQuery(queryLock).
+		WithArgs(m.WhsCode, m.ItCode, m.BatchNo, m.Source).
+		WillReturnRows(sqlmock.NewRows([]string{"Bin", "Qty"}).AddRow("-", 2).AddRow("A-01-01", 4).AddRow("A-01-02", 6))
+
+	suite.mockSQL.ExpectExec(querySummary+"(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?),(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);").
+		WithArgs(
+			m.WhsCode, "-", "-", m.Source, m.ItCode, m.BatchNo, float64(0), float64(0), float64(2), m.CreateBy, m.CreateDt,
+			m.WhsCode, "-", "A-01-01", m.Source, m.ItCode, m.BatchNo, float64(0), float64(0), float64(3), m.CreateBy, m.CreateDt,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	suite.mockSQL.ExpectExec(queryMovement+"(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?),(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);").
+		WithArgs(
+			m.DocType, m.DocNo, m.DNo, "N", m.DocDt, m.WhsCode, "-", m.Source, m.ItCode, m.BatchNo, float64(0), float64(0), float64(2), "remark", m.CreateBy, m.CreateDt,
+			m.DocType, m.DocNo, m.DNo, "N", m.DocDt, m.WhsCode, "A-01-01", m.Source, m.ItCode, m.BatchNo, float64(0), float64(0), float64(3), "remark", m.CreateBy, m.CreateDt,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+
+	err := suite.repo.Post(context.Background(), suite.tx, []inventoryledger.Movement{m})
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// dua baris keluar dengan key yang sama dijumlahkan sebelum dibandingkan dengan saldo
+func (suite *InventoryLedgerRepositorySuite) TestPost_InsufficientStock() {
+	m := movement(inventoryledger.Out)
+	m2 := m
+	m2.DNo = "002"
+
+	suite.mockSQL.ExpectQuery(queryLock).
+		WithArgs(m.WhsCode, m.ItCode, m.BatchNo, m.Source).
+		WillReturnRows(sqlmock.NewRows([]string{"Bin", "Qty"}).AddRow("-", 8))
+
+	err := suite.repo.Post(context.Background(), suite.tx, []inventoryledger.Movement{m, m2})
+
+	suite.ErrorIs(err, customerrors.ErrInsufficientStock)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func (suite *InventoryLedgerRepositorySuite) TestPost_InMovementWritesHistory() {
+	m := movement(inventoryledger.In)
+
+	suite.mockSQL.ExpectExec(querySummary+"(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);").
+		WithArgs(m.WhsCode, "-", "-", m.Source, m.ItCode, m.BatchNo, float64(0), float64(5), float64(0), m.CreateBy, m.CreateDt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mockSQL.ExpectExec(queryMovement+"(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);").
+		WithArgs(m.DocType, m.DocNo, m.DNo, "N", m.DocDt, m.WhsCode, "-", m.Source, m.ItCode, m.BatchNo, float64(0), float64(5), float64(0), "remark", m.CreateBy, m.CreateDt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mockSQL.ExpectExec(queryHistory+"(?, ?, ?, ?, ?, ?);").
+		WithArgs(m.ItCode, m.BatchNo, m.Source, "N", m.CreateBy, m.CreateDt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := suite.repo.Post(context.Background(), suite.tx, []inventoryledger.Movement{m})
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func (suite *InventoryLedgerRepositorySuite) TestPost_ErrSummary() {
+	m := movement(inventoryledger.Initial)
+
+	suite.mockSQL.ExpectExec(querySummary + "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);").
+		WillReturnError(errors.New("db down"))
+
+	err := suite.repo.Post(context.Background(), suite.tx, []inventoryledger.Movement{m})
+
+	suite.EqualError(err, "error Insert Stock Summary: db down")
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func (suite *InventoryLedgerRepositorySuite) TestPost_Empty() {
+	err := suite.repo.Post(context.Background(), suite.tx, nil)
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// reverse menambah baris summary negatif di kolom yang sama, bukan mengubah saldo lama
+func (suite *InventoryLedgerRepositorySuite) TestReverse_InitialMovement() {
+	m := movement(inventoryledger.Initial)
+
+	suite.mockSQL.ExpectQuery(queryPosted).
+		WithArgs(m.DocType, m.DocNo, m.DNo).
+		WillReturnRows(postedRows(m, "-"))
+	suite.mockSQL.ExpectQuery(queryLock).
+		WithArgs(m.WhsCode, m.ItCode, m.BatchNo, m.Source).
+		WillReturnRows(sqlmock.NewRows([]string{"Bin", "Qty"}).AddRow("-", 5))
+	suite.mockSQL.ExpectExec(querySummary+"(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);").
+		WithArgs(m.WhsCode, "-", "-", m.Source, m.ItCode, m.BatchNo, float64(-5), float64(0), float64(0), m.CreateBy, m.CreateDt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mockSQL.ExpectExec("UPDATE tblstockmovement SET CancelInd = 'Y' WHERE (DocType, DocNo, DNo) IN ((?, ?, ?))").
+		WithArgs(m.DocType, m.DocNo, m.DNo).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec("UPDATE tblhistoryofstock h SET h.CancelInd = 'Y' WHERE (h.ItCode, h.Source, h.BatchNo) IN ((?, ?, ?)) AND NOT EXISTS ( SELECT 1 FROM tblstockmovement m WHERE m.ItCode = h.ItCode AND m.Source = h.Source AND m.BatchNo = h.BatchNo AND m.CancelInd = 'N' )").
+		WithArgs(m.ItCode, m.Source, m.BatchNo).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.repo.Reverse(context.Background(), suite.tx, []inventoryledger.Movement{m})
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func (suite *InventoryLedgerRepositorySuite) TestReverse_StockAlreadyUsed() {
+	m := movement(inventoryledger.In)
+
+	suite.mockSQL.ExpectQuery(queryPosted).
+		WithArgs(m.DocType, m.DocNo, m.DNo).
+		WillReturnRows(postedRows(m, "-"))
+	suite.mockSQL.ExpectQuery(queryLock).
+		WithArgs(m.WhsCode, m.ItCode, m.BatchNo, m.Source).
+		WillReturnRows(sqlmock.NewRows([]string{"Bin", "Qty"}).AddRow("-", 2))
+
+	err := suite.repo.Reverse(context.Background(), suite.tx, []inventoryledger.Movement{m})
+
+	suite.ErrorIs(err, customerrors.ErrInsufficientStock)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// gudang, source dan qty dari dokumen diabaikan, yang dibalik adalah movement tersimpan
+func (suite *InventoryLedgerRepositorySuite) TestReverse_UsesPostedMovement() {
+	posted := movement(inventoryledger.Out)
+	m := reverseLine(posted.DocType, posted.DocNo, posted.DNo, "USR02", "202501020800")
+	m.WhsCode, m.Qty, m.Direction = "WHS99", 500, inventoryledger.Out
+
+	suite.mockSQL.ExpectQuery(queryPosted).
+		WithArgs(m.DocType, m.DocNo, m.DNo).
+		WillReturnRows(postedRows(posted, "A-01"))
+	suite.mockSQL.ExpectExec(querySummary+"(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);").
+		WithArgs(posted.WhsCode, "-", "A-01", posted.Source, posted.ItCode, posted.BatchNo, float64(0), float64(0), float64(-5), "USR02", "202501020800").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mockSQL.ExpectExec("UPDATE tblstockmovement SET CancelInd = 'Y' WHERE (DocType, DocNo, DNo) IN ((?, ?, ?))").
+		WithArgs(m.DocType, m.DocNo, m.DNo).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec("UPDATE tblhistoryofstock h SET h.CancelInd = 'Y' WHERE (h.ItCode, h.Source, h.BatchNo) IN ((?, ?, ?)) AND NOT EXISTS ( SELECT 1 FROM tblstockmovement m WHERE m.ItCode = h.ItCode AND m.Source = h.Source AND m.BatchNo = h.BatchNo AND m.CancelInd = 'N' )").
+		WithArgs(posted.ItCode, posted.Source, posted.BatchNo).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := suite.repo.Reverse(context.Background(), suite.tx, []inventoryledger.Movement{m})
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// baris yang movement-nya sudah di-cancel tidak dibalik dua kali
+func (suite *InventoryLedgerRepositorySuite) TestReverse_AlreadyCancelled() {
+	m := movement(inventoryledger.In)
+
+	suite.mockSQL.ExpectQuery(queryPosted).
+		WithArgs(m.DocType, m.DocNo, m.DNo).
+		WillReturnRows(sqlmock.NewRows([]string{"DocType", "DocNo", "DNo", "DocDt", "WhsCode", "Bin", "Source", "ItCode", "BatchNo", "Qty", "Qty2", "Qty3"}))
+
+	err := suite.repo.Reverse(context.Background(), suite.tx, []inventoryledger.Movement{m})
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// TestInventoryLedgerRepository_ParallelOutRejectsShortLockedBalance memanggil
+// Post paralel untuk batch yang sama. Tiap transaksi diberi saldo hasil query
+// lock (10, 5, lalu 0) dan yang saldonya kurang harus gagal ErrInsufficientStock
+// tanpa menulis apa pun. sqlmock tidak punya row lock, jadi test ini hanya
+// memastikan Post memakai saldo dari query FOR UPDATE dan tidak berbagi state
+// antar goroutine; pencegahan overselling antar transaksi dijamin oleh lock di
+// database, bukan dibuktikan di sini.
+func TestInventoryLedgerRepository_ParallelOutRejectsShortLockedBalance(t *testing.T) {
+	const workers = 10
+
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := sqlx.NewDb(mockDb, "mysql")
+	defer db.Close()
+
+	mock.MatchExpectationsInOrder(false)
+
+	m := movement(inventoryledger.Out)
+	stocks := []int{10, 5}
+	for i := 0; i < workers; i++ {
+		mock.ExpectBegin()
+
+		stock := 0
+		if i < len(stocks) {
+			stock = stocks[i]
+		}
+		mock.ExpectQuery(queryLock).
+			WithArgs(m.WhsCode, m.ItCode, m.BatchNo, m.Source).
+			WillReturnRows(sqlmock.NewRows([]string{"Bin", "Qty"}).AddRow("-", stock))
+	}
+	for i := 0; i < len(stocks); i++ {
+		mock.ExpectExec(querySummary + "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(queryMovement + "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+	}
+	for i := len(stocks); i < workers; i++ {
+		mock.ExpectRollback()
+	}
+
+	repo := &InventoryLedgerRepository{}
+	errs := make(chan error, workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			tx, err := db.Beginx()
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			if err := repo.Post(context.Background(), tx, []inventoryledger.Movement{m}); err != nil {
+				tx.Rollback()
+				errs <- err
+				return
+			}
+
+			errs <- tx.Commit()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var success, rejected int
+	for err := range errs {
+		switch {
+		case err == nil:
+			success++
+		case errors.Is(err, customerrors.ErrInsufficientStock):
+			rejected++
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	if success != len(stocks) || rejected != workers-len(stocks) {
+		t.Errorf("expected %d success and %d rejected, got %d and %d", len(stocks), workers-len(stocks), success, rejected)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestInventoryLedgerRepositorySuite(t *testing.T) {
+	suite.Run(t, new(InventoryLedgerRepositorySuite))
+}
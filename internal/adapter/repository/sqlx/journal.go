@@ -0,0 +1,615 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/journal"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/stockvaluation"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+// JournalRepository membuat jurnal GL dari nilai persediaan yang di-posting ledger.
+// Akun diambil dari tbljournalaccount (mapping gudang lebih diutamakan dari mapping
+// tanpa gudang), akun persediaan dan COGS fallback ke COA item category. Dokumen
+// dengan akun yang belum di-mapping ditolak dengan ErrJournalAccount supaya
+// persediaan dan GL tidak selisih.
+//
+//	CREATE TABLE tbljournalaccount (
+//		ItCtCode     VARCHAR(16) NOT NULL,
+//		WhsCode      VARCHAR(16) NOT NULL DEFAULT '',
+//		AcInventory  VARCHAR(40) NULL,
+//		AcGRIR       VARCHAR(40) NULL,
+//		AcCOGS       VARCHAR(40) NULL,
+//		AcAdjustment VARCHAR(40) NULL,
+//		AcTaxPayable VARCHAR(40) NULL,
+//		CreateBy VARCHAR(50), CreateDt VARCHAR(12),
+//		PRIMARY KEY (ItCtCode, WhsCode)
+//	);
+//	CREATE TABLE tbljournalhdr (
+//		DocNo      VARCHAR(30) NOT NULL PRIMARY KEY,
+//		DocDt      VARCHAR(8) NOT NULL,
+//		RefDocType VARCHAR(50) NOT NULL,
+//		RefDocNo   VARCHAR(30) NOT NULL,
+//		Remark     VARCHAR(250) NULL,
+//		CreateBy VARCHAR(50), CreateDt VARCHAR(12),
+//		KEY (RefDocNo)
+//	);
+//	CREATE TABLE tbljournaldtl (
+//		DocNo  VARCHAR(30) NOT NULL,
+//		DNo    VARCHAR(3) NOT NULL,
+//		AcNo   VARCHAR(40) NOT NULL,
+//		DAmt   DECIMAL(18,4) NOT NULL DEFAULT 0,
+//		CAmt   DECIMAL(18,4) NOT NULL DEFAULT 0,
+//		Remark VARCHAR(250) NULL,
+//		PRIMARY KEY (DocNo, DNo),
+//		KEY (AcNo)
+//	);
+//	CREATE TABLE tbljournaltax (
+//		DocType   VARCHAR(50) NOT NULL,
+//		DocNo     VARCHAR(30) NOT NULL,
+//		DNo       VARCHAR(3) NOT NULL,
+//		DocDt     VARCHAR(8) NOT NULL,
+//		WhsCode   VARCHAR(16) NOT NULL,
+//		ItCode    VARCHAR(16) NOT NULL,
+//		Amount    DECIMAL(18,4) NOT NULL,
+//		CancelInd CHAR(1) NOT NULL DEFAULT 'N',
+//		CreateBy VARCHAR(50), CreateDt VARCHAR(12),
+//		PRIMARY KEY (DocType, DocNo, DNo)
+//	);
+type JournalRepository struct {
+	DB *repository.Sqlx            `inject:"database"`
+	ID *formatid.GenerateIDHandler `inject:"generateID"`
+}
+
+// journalOffset adalah lawan akun persediaan per jenis dokumen ledger. Dokumen
+// yang tidak ada di sini (transfer, mutasi, stok awal) tidak dijurnal.
+var journalOffset = map[string]func(a journal.Account) string{
+	"Purchase Material Receive": func(a journal.Account) string { return a.GRIR.String },
+	"Direct Purchase Receive":   func(a journal.Account) string { return a.GRIR.String },
+	"Purchase Return Delivery":  func(a journal.Account) string { return a.GRIR.String },
+	"Direct Sales Delivery":     func(a journal.Account) string { return a.COGS.String },
+	"Stock Adjustment":          func(a journal.Account) string { return a.Adjustment.String },
+	"Material Transfer Loss":    func(a journal.Account) string { return a.Adjustment.String },
+}
+
+type journalRef struct {
+	DocType string
+	DocNo   string
+}
+
+// Post membuat satu jurnal per dokumen sumber. Nilai positif (persediaan bertambah)
+// mendebit akun persediaan dan mengkredit lawannya, nilai negatif sebaliknya,
+// sehingga pembatalan otomatis menjadi jurnal balik.
+func (t *JournalRepository) Post(ctx context.Context, tx *sqlx.Tx, entries []stockvaluation.Entry, cancel bool) error {
+	var refs []journalRef
+	grouped := make(map[journalRef][]stockvaluation.Entry)
+	for _, e := range entries {
+		if _, ok := journalOffset[e.DocType]; !ok || e.Value == 0 {
+			continue
+		}
+		ref := journalRef{e.DocType, e.DocNo}
+		if _, ok := grouped[ref]; !ok {
+			refs = append(refs, ref)
+		}
+		grouped[ref] = append(grouped[ref], e)
+	}
+
+	accounts := make(map[string]journal.Account)
+	for _, ref := range refs {
+		var acNos []string
+		amounts := make(map[string]float32)
+		add := func(acNo string, amount float32) {
+			if _, ok := amounts[acNo]; !ok {
+				acNos = append(acNos, acNo)
+			}
+			amounts[acNo] += amount
+		}
+
+		for _, e := range grouped[ref] {
+			account, err := t.account(ctx, tx, accounts, e.ItCode, e.WhsCode)
+			if err != nil {
+				return err
+			}
+			inventory, offset := account.Inventory.String, journalOffset[ref.DocType](account)
+			if inventory == "" || offset == "" {
+				return fmt.Errorf("%w: %s %s item %s in %s", customerrors.ErrJournalAccount, ref.DocType, ref.DocNo, e.ItCode, e.WhsCode)
+			}
+			add(inventory, e.Value)
+			add(offset, -e.Value)
+		}
+
+		lines := journalLines(acNos, amounts)
+		if len(lines) == 0 {
+			continue
+		}
+
+		first := grouped[ref][0]
+		remark := ref.DocType + " " + ref.DocNo
+		if cancel {
+			remark = "Cancel " + remark
+		}
+		if err := t.insert(ctx, tx, first, remark, lines); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// journalLines baris jurnal per akun, saldo positif di debit dan negatif di kredit
+func journalLines(acNos []string, amounts map[string]float32) []journal.Line {
+	var lines []journal.Line
+	for _, acNo := range acNos {
+		line := journal.Line{AcNo: acNo}
+		if amounts[acNo] > 0 {
+			line.Debit = amounts[acNo]
+		} else {
+			line.Credit = -amounts[acNo]
+		}
+		if line.Debit == 0 && line.Credit == 0 {
+			continue
+		}
+		line.DNo = fmt.Sprintf("%03d", len(lines)+1)
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// PostTax menjurnal pajak dokumen pembelian, satu jurnal per dokumen: PPN
+// mendebit akun tax payable dan PPh mengkreditnya, lawannya akun offset dokumen
+// (GR/IR). Pajaknya dicatat di tbljournaltax supaya bisa dibalik ReverseTax.
+func (t *JournalRepository) PostTax(ctx context.Context, tx *sqlx.Tx, taxes []journal.Tax) error {
+	var placeholders []string
+	var args []interface{}
+	for _, tax := range taxes {
+		if tax.Amount == 0 {
+			continue
+		}
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		args = append(args, tax.DocType, tax.DocNo, tax.DNo, tax.DocDt, tax.WhsCode, tax.ItCode, tax.Amount, tax.CreateBy, tax.CreateDt)
+	}
+	if len(placeholders) == 0 {
+		return nil
+	}
+
+	query := `INSERT INTO tbljournaltax (
+			DocType,
+			DocNo,
+			DNo,
+			DocDt,
+			WhsCode,
+			ItCode,
+			Amount,
+			CreateBy,
+			CreateDt
+		) VALUES ` + strings.Join(placeholders, ",")
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		log.Printf("Error insert journal tax: %+v", err)
+		return fmt.Errorf("error Insert Journal Tax: %w", err)
+	}
+
+	return t.postTax(ctx, tx, taxes, false)
+}
+
+// ReverseTax membalik pajak baris yang dibatalkan pada tanggal pembatalan,
+// baris yang sudah dibalik tidak dibalik lagi.
+func (t *JournalRepository) ReverseTax(ctx context.Context, tx *sqlx.Tx, docType, docNo string, dNos []string, cancelBy, cancelDt string) error {
+	if len(dNos) == 0 {
+		return nil
+	}
+
+	query, args, err := sqlx.In(`SELECT DocType, DocNo, DNo, WhsCode, ItCode, Amount
+		FROM tbljournaltax
+		WHERE DocType = ? AND DocNo = ? AND DNo IN (?) AND CancelInd = 'N'
+		ORDER BY DNo
+		FOR UPDATE`, docType, docNo, dNos)
+	if err != nil {
+		return fmt.Errorf("error build journal tax query: %w", err)
+	}
+	var taxes []journal.Tax
+	if err := tx.SelectContext(ctx, &taxes, tx.Rebind(query), args...); err != nil {
+		log.Printf("Error get journal tax: %+v", err)
+		return fmt.Errorf("error Get Journal Tax: %w", err)
+	}
+	if len(taxes) == 0 {
+		return nil
+	}
+
+	query, args, err = sqlx.In(`UPDATE tbljournaltax SET CancelInd = 'Y'
+		WHERE DocType = ? AND DocNo = ? AND DNo IN (?) AND CancelInd = 'N'`, docType, docNo, dNos)
+	if err != nil {
+		return fmt.Errorf("error build journal tax query: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind(query), args...); err != nil {
+		log.Printf("Error cancel journal tax: %+v", err)
+		return fmt.Errorf("error Cancel Journal Tax: %w", err)
+	}
+
+	docDt := cancelDt
+	if len(docDt) > 8 {
+		docDt = docDt[:8]
+	}
+	for i := range taxes {
+		taxes[i].DocDt, taxes[i].CreateBy, taxes[i].CreateDt = docDt, cancelBy, cancelDt
+	}
+
+	return t.postTax(ctx, tx, taxes, true)
+}
+
+func (t *JournalRepository) postTax(ctx context.Context, tx *sqlx.Tx, taxes []journal.Tax, cancel bool) error {
+	var refs []journalRef
+	grouped := make(map[journalRef][]journal.Tax)
+	for _, tax := range taxes {
+		if _, ok := journalOffset[tax.DocType]; !ok || tax.Amount == 0 {
+			continue
+		}
+		ref := journalRef{tax.DocType, tax.DocNo}
+		if _, ok := grouped[ref]; !ok {
+			refs = append(refs, ref)
+		}
+		grouped[ref] = append(grouped[ref], tax)
+	}
+
+	accounts := make(map[string]journal.Account)
+	for _, ref := range refs {
+		var acNos []string
+		amounts := make(map[string]float32)
+		add := func(acNo string, amount float32) {
+			if _, ok := amounts[acNo]; !ok {
+				acNos = append(acNos, acNo)
+			}
+			amounts[acNo] += amount
+		}
+
+		for _, tax := range grouped[ref] {
+			account, err := t.account(ctx, tx, accounts, tax.ItCode, tax.WhsCode)
+			if err != nil {
+				return err
+			}
+			payable, offset := account.TaxPayable.String, journalOffset[ref.DocType](account)
+			if payable == "" || offset == "" {
+				return fmt.Errorf("%w: tax %s %s item %s in %s", customerrors.ErrJournalAccount, ref.DocType, ref.DocNo, tax.ItCode, tax.WhsCode)
+			}
+			amount := tax.Amount
+			if cancel {
+				amount = -amount
+			}
+			add(payable, amount)
+			add(offset, -amount)
+		}
+
+		lines := journalLines(acNos, amounts)
+		if len(lines) == 0 {
+			continue
+		}
+
+		first := grouped[ref][0]
+		remark := "Tax " + ref.DocType + " " + ref.DocNo
+		if cancel {
+			remark = "Cancel " + remark
+		}
+		source := stockvaluation.Entry{DocType: first.DocType, DocNo: first.DocNo, DocDt: first.DocDt, CreateBy: first.CreateBy, CreateDt: first.CreateDt}
+		if err := t.insert(ctx, tx, source, remark, lines); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *JournalRepository) insert(ctx context.Context, tx *sqlx.Tx, source stockvaluation.Entry, remark string, lines []journal.Line) error {
+	docNo, err := t.ID.GenerateID(ctx, tx, "Journal", source.DocDt)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return fmt.Errorf("error Generate ID: %w", err)
+	}
+
+	query := `INSERT INTO tbljournalhdr (
+			DocNo,
+			DocDt,
+			RefDocType,
+			RefDocNo,
+			Remark,
+			CreateBy,
+			CreateDt
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, query, docNo, source.DocDt, source.DocType, source.DocNo, remark, source.CreateBy, source.CreateDt); err != nil {
+		log.Printf("Error insert journal header: %+v", err)
+		return fmt.Errorf("error Insert Journal Header: %w", err)
+	}
+
+	var placeholders []string
+	var args []interface{}
+	for _, line := range lines {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?)")
+		args = append(args, docNo, line.DNo, line.AcNo, line.Debit, line.Credit)
+	}
+
+	query = `INSERT INTO tbljournaldtl (
+			DocNo,
+			DNo,
+			AcNo,
+			DAmt,
+			CAmt
+		) VALUES ` + strings.Join(placeholders, ",") + ";"
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		log.Printf("Error insert journal detail: %+v", err)
+		return fmt.Errorf("error Insert Journal Detail: %w", err)
+	}
+
+	return nil
+}
+
+func (t *JournalRepository) account(ctx context.Context, tx *sqlx.Tx, cache map[string]journal.Account, itCode, whsCode string) (journal.Account, error) {
+	key := itCode + "*" + whsCode
+	if account, ok := cache[key]; ok {
+		return account, nil
+	}
+
+	var account journal.Account
+	query := `SELECT
+			i.ItCtCode,
+			COALESCE(m.WhsCode, '') AS WhsCode,
+			COALESCE(m.AcInventory, c.AcNo) AS AcInventory,
+			m.AcGRIR,
+			COALESCE(m.AcCOGS, c.AcNo3) AS AcCOGS,
+			m.AcAdjustment,
+			m.AcTaxPayable
+		FROM tblitem i
+		JOIN tblitemcategory c ON i.ItCtCode = c.ItCtCode
+		LEFT JOIN tbljournalaccount m ON m.ItCtCode = i.ItCtCode AND m.WhsCode IN (?, '')
+		WHERE i.ItCode = ?
+		ORDER BY m.WhsCode DESC
+		LIMIT 1`
+	if err := tx.GetContext(ctx, &account, query, whsCode, itCode); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Error get journal account: %+v", err)
+		return account, fmt.Errorf("error Get Journal Account: %w", err)
+	}
+
+	cache[key] = account
+	return account, nil
+}
+
+func (t *JournalRepository) Fetch(ctx context.Context, search, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	var totalRecords int
+	var args []interface{}
+
+	where := []string{"(h.DocNo LIKE ? OR h.RefDocNo LIKE ? OR h.RefDocType LIKE ?)"}
+	args = append(args, "%"+search+"%", "%"+search+"%", "%"+search+"%")
+	if startDate != "" {
+		where = append(where, "h.DocDt >= ?")
+		args = append(args, startDate)
+	}
+	if endDate != "" {
+		where = append(where, "h.DocDt <= ?")
+		args = append(args, endDate)
+	}
+
+	countQuery := "SELECT COUNT(*) FROM tbljournalhdr h WHERE " + strings.Join(where, " AND ")
+	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+
+	var totalPages, offset int
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+	}
+
+	data := make([]*journal.Read, 0)
+	query := `SELECT
+			h.DocNo,
+			h.DocDt,
+			h.RefDocType,
+			h.RefDocNo,
+			h.Remark,
+			h.CreateBy,
+			COALESCE(SUM(d.DAmt), 0) AS TotalDebit,
+			COALESCE(SUM(d.CAmt), 0) AS TotalCredit
+		FROM tbljournalhdr h
+		LEFT JOIN tbljournaldtl d ON h.DocNo = d.DocNo
+		WHERE ` + strings.Join(where, " AND ") + `
+		GROUP BY h.DocNo, h.DocDt, h.RefDocType, h.RefDocNo, h.Remark, h.CreateBy
+		ORDER BY h.DocDt DESC, h.DocNo DESC
+		LIMIT ? OFFSET ?`
+	args = append(args, param.PageSize, offset)
+
+	if err := t.DB.SelectContext(ctx, &data, query, args...); err != nil {
+		log.Printf("Error executing query: %v", err)
+		return nil, fmt.Errorf("error Fetch journal: %w", err)
+	}
+
+	for i, detail := range data {
+		detail.Number = uint(offset + i + 1)
+	}
+
+	return &pagination.PaginationResponse{
+		Data:         data,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}, nil
+}
+
+func (t *JournalRepository) Detail(ctx context.Context, docNo string) (*journal.Read, error) {
+	var header journal.Read
+	query := `SELECT
+			DocNo,
+			DocDt,
+			RefDocType,
+			RefDocNo,
+			Remark,
+			CreateBy
+		FROM tbljournalhdr
+		WHERE DocNo = ?`
+	if err := t.DB.GetContext(ctx, &header, query, docNo); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrDataNotFound
+		}
+		return nil, fmt.Errorf("error detail header journal: %w", err)
+	}
+
+	query = `SELECT
+			d.DNo,
+			d.AcNo,
+			COALESCE(c.AcDesc, '') AS AcDesc,
+			d.DAmt,
+			d.CAmt,
+			d.Remark
+		FROM tbljournaldtl d
+		LEFT JOIN tblcoa c ON d.AcNo = c.AcNo
+		WHERE d.DocNo = ?
+		ORDER BY d.DNo`
+	if err := t.DB.SelectContext(ctx, &header.Lines, query, docNo); err != nil {
+		return nil, fmt.Errorf("error detail journal: %w", err)
+	}
+
+	for _, line := range header.Lines {
+		header.TotalDebit += line.Debit
+		header.TotalCredit += line.Credit
+	}
+
+	return &header, nil
+}
+
+func (t *JournalRepository) TrialBalance(ctx context.Context, startDate, endDate string) (*journal.TrialBalanceReport, error) {
+	accounts := make([]*journal.TrialBalance, 0)
+	query := `SELECT
+			c.AcNo,
+			c.AcDesc,
+			COALESCE(SUM(CASE WHEN h.DocDt < ? THEN d.DAmt - d.CAmt ELSE 0 END), 0) AS Opening,
+			COALESCE(SUM(CASE WHEN h.DocDt >= ? THEN d.DAmt ELSE 0 END), 0) AS Debit,
+			COALESCE(SUM(CASE WHEN h.DocDt >= ? THEN d.CAmt ELSE 0 END), 0) AS Credit
+		FROM tblcoa c
+		JOIN tbljournaldtl d ON c.AcNo = d.AcNo
+		JOIN tbljournalhdr h ON d.DocNo = h.DocNo
+		WHERE h.DocDt <= ?
+		GROUP BY c.AcNo, c.AcDesc
+		ORDER BY c.AcNo`
+	if err := t.DB.SelectContext(ctx, &accounts, query, startDate, startDate, startDate, endDate); err != nil {
+		log.Printf("Error executing query: %v", err)
+		return nil, fmt.Errorf("error Fetch trial balance: %w", err)
+	}
+
+	report := &journal.TrialBalanceReport{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Accounts:  accounts,
+	}
+	for _, account := range accounts {
+		account.Closing = account.Opening + account.Debit - account.Credit
+		report.TotalDebit += account.Debit
+		report.TotalCredit += account.Credit
+	}
+
+	return report, nil
+}
+
+func (t *JournalRepository) FetchAccount(ctx context.Context, search string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	var totalRecords int
+	search = "%" + search + "%"
+
+	countQuery := `SELECT COUNT(*)
+		FROM tbljournalaccount m
+		JOIN tblitemcategory c ON m.ItCtCode = c.ItCtCode
+		WHERE m.ItCtCode LIKE ? OR c.ItCtName LIKE ?`
+	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, search, search); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+
+	var totalPages, offset int
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+	}
+
+	data := make([]*journal.Account, 0)
+	query := `SELECT
+			m.ItCtCode,
+			c.ItCtName,
+			m.WhsCode,
+			COALESCE(w.WhsName, '') AS WhsName,
+			m.AcInventory,
+			m.AcGRIR,
+			m.AcCOGS,
+			m.AcAdjustment,
+			m.AcTaxPayable
+		FROM tbljournalaccount m
+		JOIN tblitemcategory c ON m.ItCtCode = c.ItCtCode
+		LEFT JOIN tblwarehouse w ON m.WhsCode = w.WhsCode
+		WHERE m.ItCtCode LIKE ? OR c.ItCtName LIKE ?
+		ORDER BY m.ItCtCode, m.WhsCode
+		LIMIT ? OFFSET ?`
+	if err := t.DB.SelectContext(ctx, &data, query, search, search, param.PageSize, offset); err != nil {
+		return nil, fmt.Errorf("error Fetch journal account: %w", err)
+	}
+
+	return &pagination.PaginationResponse{
+		Data:         data,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}, nil
+}
+
+// SaveAccount membuat atau mengganti mapping satu item category + gudang
+func (t *JournalRepository) SaveAccount(ctx context.Context, data *journal.Account) (*journal.Account, error) {
+	query := `INSERT INTO tbljournalaccount (
+			ItCtCode,
+			WhsCode,
+			AcInventory,
+			AcGRIR,
+			AcCOGS,
+			AcAdjustment,
+			AcTaxPayable,
+			CreateBy,
+			CreateDt
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			AcInventory = VALUES(AcInventory),
+			AcGRIR = VALUES(AcGRIR),
+			AcCOGS = VALUES(AcCOGS),
+			AcAdjustment = VALUES(AcAdjustment),
+			AcTaxPayable = VALUES(AcTaxPayable)`
+	if _, err := t.DB.ExecContext(ctx, query,
+		data.ItemCategoryCode,
+		data.WarehouseCode,
+		data.Inventory,
+		data.GRIR,
+		data.COGS,
+		data.Adjustment,
+		data.TaxPayable,
+		data.CreateBy,
+		data.CreateDt,
+	); err != nil {
+		log.Printf("Detailed error: %+v", err)
+		return nil, fmt.Errorf("error Save Journal Account: %w", err)
+	}
+
+	return data, nil
+}
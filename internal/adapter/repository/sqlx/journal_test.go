@@ -0,0 +1,206 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/domain/journal"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/stockvaluation"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+const (
+	queryJournalAccount = "SELECT i.ItCtCode, COALESCE(m.WhsCode, '') AS WhsCode, COALESCE(m.AcInventory, c.AcNo) AS AcInventory, m.AcGRIR, COALESCE(m.AcCOGS, c.AcNo3) AS AcCOGS, m.AcAdjustment, m.AcTaxPayable FROM tblitem i JOIN tblitemcategory c ON i.ItCtCode = c.ItCtCode LEFT JOIN tbljournalaccount m ON m.ItCtCode = i.ItCtCode AND m.WhsCode IN (?, '') WHERE i.ItCode = ? ORDER BY m.WhsCode DESC LIMIT 1"
+	queryJournalSeq     = "INSERT INTO tbldocsequence (Category, SiteCode, Period, LastNo) VALUES (?, ?, ?, LAST_INSERT_ID(1)) ON DUPLICATE KEY UPDATE LastNo = LAST_INSERT_ID(LastNo + 1)"
+	queryJournalLastNo  = "SELECT LAST_INSERT_ID()"
+	queryJournalHeader  = "INSERT INTO tbljournalhdr ( DocNo, DocDt, RefDocType, RefDocNo, Remark, CreateBy, CreateDt ) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	queryJournalDetail  = "INSERT INTO tbljournaldtl ( DocNo, DNo, AcNo, DAmt, CAmt ) VALUES (?, ?, ?, ?, ?),(?, ?, ?, ?, ?);"
+	queryJournalTax     = "INSERT INTO tbljournaltax ( DocType, DocNo, DNo, DocDt, WhsCode, ItCode, Amount, CreateBy, CreateDt ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	queryJournalTaxLock = "SELECT DocType, DocNo, DNo, WhsCode, ItCode, Amount FROM tbljournaltax WHERE DocType = ? AND DocNo = ? AND DNo IN (?) AND CancelInd = 'N' ORDER BY DNo FOR UPDATE"
+	queryJournalTaxDone = "UPDATE tbljournaltax SET CancelInd = 'Y' WHERE DocType = ? AND DocNo = ? AND DNo IN (?) AND CancelInd = 'N'"
+)
+
+type JournalRepositorySuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	repo    *JournalRepository
+	db      *sqlx.DB
+	tx      *sqlx.Tx
+}
+
+func (suite *JournalRepositorySuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.repo = &JournalRepository{ID: &formatid.GenerateIDHandler{}}
+
+	suite.mockSQL.ExpectBegin()
+	suite.tx, err = suite.db.Beginx()
+	suite.Require().NoError(err)
+}
+
+func (suite *JournalRepositorySuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func receiptEntry(value float32) stockvaluation.Entry {
+	return stockvaluation.Entry{
+		DocType:  "Purchase Material Receive",
+		DocNo:    "0001/R1/RPM/01/25",
+		DNo:      "001",
+		DocDt:    "20250101",
+		WhsCode:  "WHS01",
+		ItCode:   "ITM01",
+		Qty:      10,
+		Value:    value,
+		CreateBy: "USR01",
+		CreateDt: "202501011000",
+	}
+}
+
+func (suite *JournalRepositorySuite) expectAccount(grir interface{}) {
+	suite.mockSQL.ExpectQuery(queryJournalAccount).
+		WithArgs("WHS01", "ITM01").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCtCode", "WhsCode", "AcInventory", "AcGRIR", "AcCOGS", "AcAdjustment", "AcTaxPayable"}).
+			AddRow("CT01", "", "1101", grir, "5101", nil, "2201"))
+}
+
+func receiptTax(amount float32) journal.Tax {
+	return journal.Tax{
+		DocType:  "Direct Purchase Receive",
+		DocNo:    "0001/R1/DPR/01/25",
+		DNo:      "001",
+		DocDt:    "20250101",
+		WhsCode:  "WHS01",
+		ItCode:   "ITM01",
+		Amount:   amount,
+		CreateBy: "USR01",
+		CreateDt: "202501011000",
+	}
+}
+
+func (suite *JournalRepositorySuite) expectJournalNo() {
+	suite.mockSQL.ExpectExec(queryJournalSeq).
+		WithArgs("Journal", "R1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mockSQL.ExpectQuery(queryJournalLastNo).
+		WillReturnRows(sqlmock.NewRows([]string{"LAST_INSERT_ID()"}).AddRow(1))
+}
+
+// penerimaan mendebit persediaan dan mengkredit GR/IR
+func (suite *JournalRepositorySuite) TestPost_Receipt() {
+	e := receiptEntry(100)
+
+	suite.expectAccount("2101")
+	suite.expectJournalNo()
+	suite.mockSQL.ExpectExec(queryJournalHeader).
+		WithArgs(sqlmock.AnyArg(), e.DocDt, e.DocType, e.DocNo, "Purchase Material Receive 0001/R1/RPM/01/25", e.CreateBy, e.CreateDt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryJournalDetail).
+		WithArgs(sqlmock.AnyArg(), "001", "1101", float32(100), float32(0), sqlmock.AnyArg(), "002", "2101", float32(0), float32(100)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := suite.repo.Post(context.Background(), suite.tx, []stockvaluation.Entry{e}, false)
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// pembatalan membawa nilai negatif sehingga sisi debit / kredit terbalik
+func (suite *JournalRepositorySuite) TestPost_Reversal() {
+	e := receiptEntry(-100)
+
+	suite.expectAccount("2101")
+	suite.expectJournalNo()
+	suite.mockSQL.ExpectExec(queryJournalHeader).
+		WithArgs(sqlmock.AnyArg(), e.DocDt, e.DocType, e.DocNo, "Cancel Purchase Material Receive 0001/R1/RPM/01/25", e.CreateBy, e.CreateDt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryJournalDetail).
+		WithArgs(sqlmock.AnyArg(), "001", "1101", float32(0), float32(100), sqlmock.AnyArg(), "002", "2101", float32(100), float32(0)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := suite.repo.Post(context.Background(), suite.tx, []stockvaluation.Entry{e}, true)
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// akun lawan belum di-mapping, dokumen ditolak tanpa jurnal
+func (suite *JournalRepositorySuite) TestPost_UnmappedAccount() {
+	suite.expectAccount(nil)
+
+	err := suite.repo.Post(context.Background(), suite.tx, []stockvaluation.Entry{receiptEntry(100)}, false)
+
+	suite.True(errors.Is(err, customerrors.ErrJournalAccount))
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// dokumen di luar journalOffset (mis. transfer) tidak dijurnal
+func (suite *JournalRepositorySuite) TestPost_NotJournaled() {
+	e := receiptEntry(100)
+	e.DocType = "Material Transfer"
+
+	err := suite.repo.Post(context.Background(), suite.tx, []stockvaluation.Entry{e}, false)
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// PPN pembelian mendebit tax payable dan mengkredit GR/IR
+func (suite *JournalRepositorySuite) TestPostTax() {
+	tax := receiptTax(11)
+
+	suite.mockSQL.ExpectExec(queryJournalTax).
+		WithArgs(tax.DocType, tax.DocNo, tax.DNo, tax.DocDt, tax.WhsCode, tax.ItCode, tax.Amount, tax.CreateBy, tax.CreateDt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.expectAccount("2101")
+	suite.expectJournalNo()
+	suite.mockSQL.ExpectExec(queryJournalHeader).
+		WithArgs(sqlmock.AnyArg(), tax.DocDt, tax.DocType, tax.DocNo, "Tax Direct Purchase Receive 0001/R1/DPR/01/25", tax.CreateBy, tax.CreateDt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryJournalDetail).
+		WithArgs(sqlmock.AnyArg(), "001", "2201", float32(11), float32(0), sqlmock.AnyArg(), "002", "2101", float32(0), float32(11)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := suite.repo.PostTax(context.Background(), suite.tx, []journal.Tax{tax})
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// baris yang dibatalkan dibalik dari pajak yang dulu di-posting, pada tanggal batal
+func (suite *JournalRepositorySuite) TestReverseTax() {
+	tax := receiptTax(11)
+
+	suite.mockSQL.ExpectQuery(queryJournalTaxLock).
+		WithArgs(tax.DocType, tax.DocNo, "001").
+		WillReturnRows(sqlmock.NewRows([]string{"DocType", "DocNo", "DNo", "WhsCode", "ItCode", "Amount"}).
+			AddRow(tax.DocType, tax.DocNo, "001", tax.WhsCode, tax.ItCode, tax.Amount))
+	suite.mockSQL.ExpectExec(queryJournalTaxDone).
+		WithArgs(tax.DocType, tax.DocNo, "001").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.expectAccount("2101")
+	suite.expectJournalNo()
+	suite.mockSQL.ExpectExec(queryJournalHeader).
+		WithArgs(sqlmock.AnyArg(), "20250201", tax.DocType, tax.DocNo, "Cancel Tax Direct Purchase Receive 0001/R1/DPR/01/25", "USR02", "202502010900").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryJournalDetail).
+		WithArgs(sqlmock.AnyArg(), "001", "2201", float32(0), float32(11), sqlmock.AnyArg(), "002", "2101", float32(11), float32(0)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := suite.repo.ReverseTax(context.Background(), suite.tx, tax.DocType, tax.DocNo, []string{"001"}, "USR02", "202502010900")
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func TestJournalRepository(t *testing.T) {
+	suite.Run(t, new(JournalRepositorySuite))
+}
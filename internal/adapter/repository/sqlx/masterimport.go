@@ -0,0 +1,97 @@
+package sqlx
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/exchangerate"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/tblmasteritem"
+	"gitlab.com/ayaka/internal/domain/tblmastervendor"
+	"gitlab.com/ayaka/internal/domain/tbluom"
+	"gitlab.com/ayaka/internal/domain/tblwarehouse"
+)
+
+type MasterImportRepository struct {
+	DB *repository.Sqlx            `inject:"database"`
+	ID *formatid.GenerateIDHandler `inject:"generateID"`
+}
+
+func (t *MasterImportRepository) ImportItems(ctx context.Context, data []*tblmasteritem.Create) error {
+	return t.inTx(ctx, func(tx *sqlx.Tx) error {
+		for i, item := range data {
+			if err := insertItem(ctx, tx, item); err != nil {
+				return fmt.Errorf("row %d: %w", i+1, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (t *MasterImportRepository) ImportVendors(ctx context.Context, data []*tblmastervendor.Create) error {
+	return t.inTx(ctx, func(tx *sqlx.Tx) error {
+		for i, vendor := range data {
+			if err := insertVendor(ctx, tx, t.ID, vendor); err != nil {
+				return fmt.Errorf("row %d: %w", i+1, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (t *MasterImportRepository) ImportWarehouses(ctx context.Context, data []*tblwarehouse.CreateTblWarehouse) error {
+	return t.inTx(ctx, func(tx *sqlx.Tx) error {
+		for i, warehouse := range data {
+			if err := insertWarehouse(ctx, tx, warehouse); err != nil {
+				return fmt.Errorf("row %d: %w", i+1, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (t *MasterImportRepository) ImportUoms(ctx context.Context, data []*tbluom.CreateTblUom) error {
+	return t.inTx(ctx, func(tx *sqlx.Tx) error {
+		for i, uom := range data {
+			if err := insertUom(ctx, tx, uom); err != nil {
+				return fmt.Errorf("row %d: %w", i+1, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (t *MasterImportRepository) ImportExchangeRates(ctx context.Context, data []*exchangerate.Rate) error {
+	return t.inTx(ctx, func(tx *sqlx.Tx) error {
+		for i, rate := range data {
+			if err := saveRate(ctx, tx, rate); err != nil {
+				return fmt.Errorf("row %d: %w", i+1, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (t *MasterImportRepository) inTx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Failed to rollback transaction: %+v", rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/tbluom"
+)
+
+const queryInsertUom = "INSERT INTO tbluom (UomCode, UomName, CreateBy, CreateDt) VALUES (?, ?, ?, ?)"
+
+type MasterImportSuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	db      *sqlx.DB
+	repo    *MasterImportRepository
+}
+
+func (suite *MasterImportSuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.repo = &MasterImportRepository{DB: &repository.Sqlx{DB: suite.db}}
+}
+
+func (suite *MasterImportSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func uoms() []*tbluom.CreateTblUom {
+	return []*tbluom.CreateTblUom{
+		{UomCode: "PCS", UomName: "Pieces", CreateBy: "admin", CreateDate: "202601010800"},
+		{UomCode: "BOX", UomName: "Box", CreateBy: "admin", CreateDate: "202601010800"},
+	}
+}
+
+func (suite *MasterImportSuite) TestImportUoms_Commit() {
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectExec(queryInsertUom).
+		WithArgs("PCS", "Pieces", "admin", "202601010800").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryInsertUom).
+		WithArgs("BOX", "Box", "admin", "202601010800").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectCommit()
+
+	err := suite.repo.ImportUoms(context.Background(), uoms())
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// satu baris gagal membatalkan semua baris sebelumnya
+func (suite *MasterImportSuite) TestImportUoms_RollbackOnError() {
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectExec(queryInsertUom).
+		WithArgs("PCS", "Pieces", "admin", "202601010800").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryInsertUom).
+		WithArgs("BOX", "Box", "admin", "202601010800").
+		WillReturnError(errors.New("duplicate entry"))
+	suite.mockSQL.ExpectRollback()
+
+	err := suite.repo.ImportUoms(context.Background(), uoms())
+
+	suite.ErrorContains(err, "row 2")
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func TestMasterImportSuite(t *testing.T) {
+	suite.Run(t, new(MasterImportSuite))
+}
@@ -0,0 +1,404 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/printout"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+type PrintoutRepository struct {
+	DB *repository.Sqlx `inject:"database"`
+}
+
+// header yang sama untuk semua dokumen, Site* kosong jika dokumen tidak punya site
+type printHeader struct {
+	DocNo       string  `db:"DocNo"`
+	DocDt       string  `db:"DocDt"`
+	Status      string  `db:"Status"`
+	SiteCode    string  `db:"SiteCode"`
+	SiteName    string  `db:"SiteName"`
+	SiteAddress string  `db:"SiteAddress"`
+	TaxCode     string  `db:"TaxCode"`
+	TaxName     string  `db:"TaxName"`
+	TaxRate     float32 `db:"TaxRate"`
+	Remark      string  `db:"Remark"`
+}
+
+const printVendorColumns = `v.VendorCode AS Code,
+			v.VendorName AS Name,
+			COALESCE(v.Address, '') AS Address,
+			COALESCE(c.CityName, '') AS City,
+			COALESCE(v.PostalCode, '') AS PostalCode,
+			COALESCE(v.Phone, '') AS Phone,
+			COALESCE(v.Email, '') AS Email`
+
+const printWarehouseColumns = `w.WhsCode AS Code,
+			w.WhsName AS Name,
+			COALESCE(w.Address, '') AS Address,
+			COALESCE(c.CityName, '') AS City,
+			COALESCE(w.PostalCd, '') AS PostalCode,
+			COALESCE(w.Phone, '') AS Phone,
+			COALESCE(w.Email, '') AS Email,
+			COALESCE(w.ContactPerson, '') AS ContactName`
+
+func (t *PrintoutRepository) Document(ctx context.Context, docType, docNo string) (*printout.Document, error) {
+	var doc *printout.Document
+	var err error
+
+	switch docType {
+	case printout.PurchaseOrder:
+		doc, err = t.purchaseOrder(ctx, docNo)
+	case printout.DirectSalesDelivery:
+		doc, err = t.directSalesDelivery(ctx, docNo)
+	case printout.MaterialTransfer:
+		doc, err = t.materialTransfer(ctx, docNo)
+	case printout.PurchaseMaterialReceive:
+		doc, err = t.purchaseMaterialReceive(ctx, docNo)
+	default:
+		return nil, customerrors.ErrKeyNotFound
+	}
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrDataNotFound
+		}
+		log.Printf("Error get print %s %s: %+v", docType, docNo, err)
+		return nil, fmt.Errorf("error get print document: %w", err)
+	}
+
+	doc.Type = docType
+	for i := range doc.Lines {
+		doc.Lines[i].No = i + 1
+	}
+	if doc.ShowPrice {
+		for _, line := range doc.Lines {
+			doc.SubTotal += line.Total
+		}
+		doc.TaxAmount = doc.SubTotal * doc.TaxRate
+		doc.GrandTotal = doc.SubTotal + doc.TaxAmount
+	}
+
+	return doc, nil
+}
+
+func newPrintDocument(title string, header *printHeader) *printout.Document {
+	return &printout.Document{
+		Title:       title,
+		DocNo:       header.DocNo,
+		Date:        share.FormatDate(header.DocDt),
+		Status:      header.Status,
+		SiteCode:    header.SiteCode,
+		SiteName:    header.SiteName,
+		SiteAddress: header.SiteAddress,
+		TaxCode:     header.TaxCode,
+		TaxName:     header.TaxName,
+		TaxRate:     header.TaxRate,
+		Remark:      header.Remark,
+	}
+}
+
+func (t *PrintoutRepository) purchaseOrder(ctx context.Context, docNo string) (*printout.Document, error) {
+	var header printHeader
+	query := `SELECT
+			h.DocNo,
+			h.DocDt,
+			h.ApprovalStatus AS Status,
+			COALESCE(s.SiteCode, '') AS SiteCode,
+			COALESCE(s.SiteName, '') AS SiteName,
+			COALESCE(s.Address, '') AS SiteAddress,
+			COALESCE(h.TaxCode, '') AS TaxCode,
+			COALESCE(tx.TaxName, '') AS TaxName,
+			COALESCE(tx.TaxRate, 0) AS TaxRate,
+			COALESCE(h.Remark, '') AS Remark
+		FROM tblpurchaseorderhdr h
+		LEFT JOIN tbltax tx ON h.TaxCode = tx.TaxCode
+		LEFT JOIN tblsite s ON s.SiteCode = (
+			SELECT MIN(mh.SiteCode)
+			FROM tblpurchaseorderdtl d
+			JOIN tblpurchaseorderreqdtl pr ON d.PurchaseOrderReqDocNo = pr.DocNo AND d.PurchaseOrderReqDNo = pr.DNo
+			JOIN tblmaterialrequesthdr mh ON pr.MaterialReqDocNo = mh.DocNo
+			WHERE d.DocNo = h.DocNo
+		)
+		WHERE h.DocNo = ?`
+	if err := t.DB.GetContext(ctx, &header, query, docNo); err != nil {
+		return nil, err
+	}
+
+	var vendor printout.Party
+	query = `SELECT
+			` + printVendorColumns + `,
+			COALESCE(cv.Name, '') AS ContactName,
+			COALESCE(cv.Number, '') AS ContactNumber,
+			COALESCE(cv.Position, '') AS ContactPosition
+		FROM tblpurchaseorderhdr h
+		JOIN tblvendorhdr v ON h.VendorCode = v.VendorCode
+		LEFT JOIN tblcity c ON v.CityCode = c.CityCode
+		LEFT JOIN tblcontactvendordtl cv ON h.VendorCode = cv.VendorCode AND h.ContactPersonDNo = cv.DNo
+		WHERE h.DocNo = ?`
+	if err := t.DB.GetContext(ctx, &vendor, query, docNo); err != nil {
+		return nil, err
+	}
+
+	doc := newPrintDocument("Purchase Order", &header)
+	doc.Parties = []printout.NamedParty{{Label: "Vendor", Party: vendor}}
+	doc.ShowPrice = true
+
+	var lines []struct {
+		printout.Line
+		CurName string `db:"CurName"`
+	}
+	query = `SELECT
+			d.ItCode,
+			i.ItName,
+			'' AS BatchNo,
+			d.Qty,
+			COALESCE(u.UomName, '') AS UomName,
+			vqd.Price,
+			d.Qty * vqd.Price AS Total,
+			d.PurchaseOrderReqDocNo AS Reference,
+			COALESCE(d.Remark, '') AS Remark,
+			c.CurName
+		FROM tblpurchaseorderdtl d
+		JOIN tblitem i ON d.ItCode = i.ItCode
+		LEFT JOIN tbluom u ON i.PurchaseUomCode = u.UomCode
+		JOIN tblpurchaseorderreqdtl por ON d.PurchaseOrderReqDocNo = por.DocNo AND d.PurchaseOrderReqDNo = por.DNo
+		JOIN tblvendorquotationhdr vqh ON por.VendorQTDocNo = vqh.DocNo
+		JOIN tblvendorquotationdtl vqd ON por.VendorQTDocNo = vqd.DocNo AND por.VendorQTDNo = vqd.DNo
+		JOIN tblcurrency c ON vqh.CurCode = c.CurCode
+		WHERE d.DocNo = ? AND d.CancelInd = 'N'
+		ORDER BY d.DNo`
+	if err := t.DB.SelectContext(ctx, &lines, query, docNo); err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		doc.Lines = append(doc.Lines, line.Line)
+		doc.Currency = line.CurName
+	}
+
+	return doc, nil
+}
+
+func (t *PrintoutRepository) directSalesDelivery(ctx context.Context, docNo string) (*printout.Document, error) {
+	var header struct {
+		printHeader
+		printout.Party
+		WhsName string `db:"WhsName"`
+	}
+	query := `SELECT
+			h.DocNo,
+			h.DocDt,
+			'' AS Status,
+			COALESCE(h.TaxCode, '') AS TaxCode,
+			COALESCE(tx.TaxName, '') AS TaxName,
+			COALESCE(tx.TaxRate, 0) AS TaxRate,
+			COALESCE(h.Remark, '') AS Remark,
+			'' AS Code,
+			h.CustomerName AS Name,
+			COALESCE(h.Address, '') AS Address,
+			COALESCE(c.CityName, '') AS City,
+			COALESCE(h.PostalCode, '') AS PostalCode,
+			COALESCE(h.Phone, h.Mobile, '') AS Phone,
+			COALESCE(h.Email, '') AS Email,
+			w.WhsName
+		FROM tbldirectsalesdelivhdr h
+		JOIN tblwarehouse w ON h.WhsCode = w.WhsCode
+		LEFT JOIN tblcity c ON h.CityCode = c.CityCode
+		LEFT JOIN tbltax tx ON h.TaxCode = tx.TaxCode
+		WHERE h.DocNo = ?`
+	args := []interface{}{docNo}
+	if scope, scopeArgs := warehouseScope(ctx, "h.WhsCode"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+	if err := t.DB.GetContext(ctx, &header, query, args...); err != nil {
+		return nil, err
+	}
+
+	doc := newPrintDocument("Delivery Note", &header.printHeader)
+	doc.Parties = []printout.NamedParty{{Label: "Customer", Party: header.Party}}
+	doc.Info = []printout.Info{{Label: "Warehouse", Value: header.WhsName}}
+	doc.ShowPrice = true
+
+	query = `SELECT
+			d.ItCode,
+			i.ItName,
+			d.BatchNo,
+			d.Qty,
+			COALESCE(u.UomName, '') AS UomName,
+			d.Price,
+			d.Qty * d.Price AS Total,
+			d.Source AS Reference,
+			'' AS Remark
+		FROM tbldirectsalesdelivdtl d
+		JOIN tblitem i ON d.ItCode = i.ItCode
+		LEFT JOIN tbluom u ON i.PurchaseUomCode = u.UomCode
+		WHERE d.DocNo = ? AND d.CancelInd = 'N'
+		ORDER BY d.DNo`
+	if err := t.DB.SelectContext(ctx, &doc.Lines, query, docNo); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func (t *PrintoutRepository) materialTransfer(ctx context.Context, docNo string) (*printout.Document, error) {
+	var header struct {
+		printHeader
+		WhsFrom       string `db:"WhsCodeFrom"`
+		WhsTo         string `db:"WhsCodeTo"`
+		Transporter   string `db:"VendorName"`
+		Driver        string `db:"Driver"`
+		TransportType string `db:"TransportType"`
+		LicenceNo     string `db:"LicenceNo"`
+	}
+	query := `SELECT
+			h.DocNo,
+			h.DocDt,
+			'' AS Status,
+			COALESCE(h.Note, '') AS Remark,
+			h.WhsCodeFrom,
+			h.WhsCodeTo,
+			COALESCE(v.VendorName, '') AS VendorName,
+			COALESCE(h.Driver, '') AS Driver,
+			COALESCE(h.TransportType, '') AS TransportType,
+			COALESCE(h.LicenceNo, '') AS LicenceNo
+		FROM tblmaterialtransferhdr h
+		LEFT JOIN tblvendorhdr v ON h.VendorCode = v.VendorCode
+		WHERE h.DocNo = ?`
+	args := []interface{}{docNo}
+	if scope, scopeArgs := warehouseScope(ctx, "h.WhsCodeFrom", "h.WhsCodeTo"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+	if err := t.DB.GetContext(ctx, &header, query, args...); err != nil {
+		return nil, err
+	}
+
+	doc := newPrintDocument("Material Transfer", &header.printHeader)
+	doc.Info = []printout.Info{
+		{Label: "Transporter", Value: header.Transporter},
+		{Label: "Transport Type", Value: header.TransportType},
+		{Label: "Driver", Value: header.Driver},
+		{Label: "Licence No", Value: header.LicenceNo},
+	}
+
+	for _, whs := range []struct{ label, code string }{{"From", header.WhsFrom}, {"To", header.WhsTo}} {
+		party, err := t.warehouse(ctx, whs.code)
+		if err != nil {
+			return nil, err
+		}
+		doc.Parties = append(doc.Parties, printout.NamedParty{Label: whs.label, Party: *party})
+	}
+
+	query = `SELECT
+			d.ItCode,
+			i.ItName,
+			d.BatchNo,
+			d.Qty,
+			COALESCE(u.UomName, '') AS UomName,
+			0 AS Price,
+			0 AS Total,
+			'' AS Reference,
+			COALESCE(d.Remark, '') AS Remark
+		FROM tblmaterialtransferdtl d
+		JOIN tblitem i ON d.ItCode = i.ItCode
+		LEFT JOIN tbluom u ON i.PurchaseUomCode = u.UomCode
+		WHERE d.DocNo = ? AND d.CancelInd = 'N'
+		ORDER BY d.DNo`
+	if err := t.DB.SelectContext(ctx, &doc.Lines, query, docNo); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func (t *PrintoutRepository) purchaseMaterialReceive(ctx context.Context, docNo string) (*printout.Document, error) {
+	var header struct {
+		printHeader
+		WhsCode string `db:"WhsCode"`
+	}
+	query := `SELECT
+			h.DocNo,
+			h.DocDt,
+			'' AS Status,
+			COALESCE(s.SiteCode, '') AS SiteCode,
+			COALESCE(s.SiteName, '') AS SiteName,
+			COALESCE(s.Address, '') AS SiteAddress,
+			COALESCE(h.Remark, '') AS Remark,
+			h.WhsCode
+		FROM tblpurchasematerialreceivehdr h
+		LEFT JOIN tblsite s ON h.SiteCode = s.SiteCode
+		WHERE h.DocNo = ?`
+	args := []interface{}{docNo}
+	if scope, scopeArgs := warehouseScope(ctx, "h.WhsCode"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+	if err := t.DB.GetContext(ctx, &header, query, args...); err != nil {
+		return nil, err
+	}
+
+	var vendor printout.Party
+	query = `SELECT
+			` + printVendorColumns + `
+		FROM tblpurchasematerialreceivehdr h
+		JOIN tblvendorhdr v ON h.VendorCode = v.VendorCode
+		LEFT JOIN tblcity c ON v.CityCode = c.CityCode
+		WHERE h.DocNo = ?`
+	if err := t.DB.GetContext(ctx, &vendor, query, docNo); err != nil {
+		return nil, err
+	}
+
+	warehouse, err := t.warehouse(ctx, header.WhsCode)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := newPrintDocument("Goods Receipt", &header.printHeader)
+	doc.Parties = []printout.NamedParty{
+		{Label: "Vendor", Party: vendor},
+		{Label: "Warehouse", Party: *warehouse},
+	}
+
+	query = `SELECT
+			d.ItCode,
+			i.ItName,
+			d.BatchNo,
+			d.PurchaseQty AS Qty,
+			COALESCE(u.UomName, '') AS UomName,
+			0 AS Price,
+			0 AS Total,
+			d.PurchaseOrderDocNo AS Reference,
+			COALESCE(d.Remark, '') AS Remark
+		FROM tblpurchasematerialreceivedtl d
+		JOIN tblitem i ON d.ItCode = i.ItCode
+		LEFT JOIN tbluom u ON i.PurchaseUomCode = u.UomCode
+		WHERE d.DocNo = ? AND d.CancelInd = 'N'
+		ORDER BY d.DNo`
+	if err := t.DB.SelectContext(ctx, &doc.Lines, query, docNo); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func (t *PrintoutRepository) warehouse(ctx context.Context, whsCode string) (*printout.Party, error) {
+	var party printout.Party
+	query := `SELECT
+			` + printWarehouseColumns + `
+		FROM tblwarehouse w
+		LEFT JOIN tblcity c ON w.CityCode = c.CityCode
+		WHERE w.WhsCode = ?`
+	if err := t.DB.GetContext(ctx, &party, query, whsCode); err != nil {
+		return nil, err
+	}
+
+	return &party, nil
+}
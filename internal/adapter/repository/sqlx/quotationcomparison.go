@@ -0,0 +1,324 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/exchangerate"
+	"gitlab.com/ayaka/internal/domain/quotationcomparison"
+	sharedfunc "gitlab.com/ayaka/internal/domain/shared/sharedFunc"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+// QuotationComparisonRepository membandingkan vendor quotation per item dan
+// memasang pemenangnya ke baris PO request
+type QuotationComparisonRepository struct {
+	DB   *repository.Sqlx        `inject:"database"`
+	Rate exchangerate.Repository `inject:"exchangeRateRepository"`
+}
+
+type porLine struct {
+	ItCode        string  `db:"ItCode"`
+	Qty           float64 `db:"Qty"`
+	CancelInd     string  `db:"CancelInd"`
+	SuccessInd    string  `db:"SuccessInd"`
+	VendorQTDocNo string  `db:"VendorQTDocNo"`
+	VendorQTDNo   string  `db:"VendorQTDNo"`
+}
+
+type quotationLine struct {
+	ItCode     string  `db:"ItCode"`
+	VendorCode string  `db:"VendorCode"`
+	Price      float64 `db:"Price"`
+	ActiveInd  string  `db:"ActiveInd"`
+	UsedInd    string  `db:"UsedInd"`
+}
+
+// Compare mengurutkan quotation aktif yang belum dipakai per baris PO request
+// (atau per item). Harga dikonversi ke base dengan kurs tblcurrencyrate hari
+// ini; quotation dalam base tidak butuh kurs, mata uang lain tanpa kurs
+// dilaporkan di MissingRates dan ditaruh paling bawah.
+func (t *QuotationComparisonRepository) Compare(ctx context.Context, porDocNo string, itemCodes []string, base string, weight quotationcomparison.Weight) ([]*quotationcomparison.Comparison, error) {
+	var lines []*quotationcomparison.Comparison
+	if porDocNo != "" {
+		query := `SELECT
+				d.DocNo,
+				d.DNo,
+				d.ItCode,
+				i.ItName,
+				d.Qty,
+				COALESCE(d.VendorQTDocNo, '') AS VendorQTDocNo,
+				COALESCE(d.VendorQTDNo, '') AS VendorQTDNo
+			FROM tblpurchaseorderreqdtl d
+			JOIN tblitem i ON d.ItCode = i.ItCode
+			WHERE d.DocNo = ? AND d.CancelInd = 'N' AND d.SuccessInd = 'N'
+			ORDER BY d.DNo`
+		if err := t.DB.SelectContext(ctx, &lines, query, porDocNo); err != nil {
+			return nil, fmt.Errorf("error get purchase order request: %w", err)
+		}
+	} else if len(itemCodes) > 0 {
+		query, args, err := sqlx.In("SELECT ItCode, ItName FROM tblitem WHERE ItCode IN (?) ORDER BY ItCode", itemCodes)
+		if err != nil {
+			return nil, fmt.Errorf("error build item query: %w", err)
+		}
+		if err := t.DB.SelectContext(ctx, &lines, t.DB.Rebind(query), args...); err != nil {
+			return nil, fmt.Errorf("error get item: %w", err)
+		}
+	}
+	if len(lines) == 0 {
+		return nil, customerrors.ErrDataNotFound
+	}
+
+	codes := make([]string, 0, len(lines))
+	for _, l := range lines {
+		codes = append(codes, l.ItemCode)
+	}
+	codes = sharedfunc.UniqueStringSlice(codes)
+
+	// skor vendor = rata-rata nilai rating aktif dengan bobot indikator
+	query, args, err := sqlx.In(`SELECT
+			d.DocNo,
+			d.DNo,
+			d.ItCode,
+			h.VendorCode,
+			v.VendorName,
+			h.CurCode,
+			d.Price,
+			h.TermOfPayment,
+			h.DeliveryType,
+			COALESCE(r.Rating, 0) AS VendorRating
+		FROM tblvendorquotationdtl d
+		JOIN tblvendorquotationhdr h ON d.DocNo = h.DocNo
+		JOIN tblvendorhdr v ON h.VendorCode = v.VendorCode
+		LEFT JOIN (
+			SELECT rv.VendorCode, SUM(rv.Value * vr.Weight) / SUM(vr.Weight) AS Rating
+			FROM tblratingvendordtl rv
+			JOIN tblvendorrating vr ON rv.VendorRatingCode = vr.IndicatorCode AND vr.ActiveInd = 'Y'
+			WHERE rv.Active = 'Y'
+			GROUP BY rv.VendorCode
+		) r ON h.VendorCode = r.VendorCode
+		WHERE d.ItCode IN (?) AND d.ActiveInd = 'Y' AND d.UsedInd = 'N'
+		ORDER BY d.DocNo, d.DNo`, codes)
+	if err != nil {
+		return nil, fmt.Errorf("error build quotation query: %w", err)
+	}
+
+	var candidates []*quotationcomparison.Candidate
+	if err := t.DB.SelectContext(ctx, &candidates, t.DB.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("error get vendor quotation: %w", err)
+	}
+
+	curCodes := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		curCodes = append(curCodes, c.CurCode)
+	}
+	rates, err := t.Rate.Rates(ctx, sharedfunc.UniqueStringSlice(curCodes), base, time.Now().Format("20060102"))
+	if err != nil {
+		return nil, err
+	}
+
+	byItem := map[string][]*quotationcomparison.Candidate{}
+	for _, c := range candidates {
+		c.Rate, c.RateMissing = rates[c.CurCode], rates[c.CurCode] == 0
+		c.NormalizedPrice = c.Price * c.Rate
+		c.PaymentDays = paymentTermDays(c.TermOfPayment)
+		byItem[c.ItemCode] = append(byItem[c.ItemCode], c)
+	}
+	for _, items := range byItem {
+		rankQuotations(items, weight)
+	}
+
+	for _, l := range lines {
+		l.BaseCurrency = base
+		l.Candidates = byItem[l.ItemCode]
+		if l.Candidates == nil {
+			l.Candidates = make([]*quotationcomparison.Candidate, 0)
+		}
+		l.MissingRates = make([]string, 0)
+		for _, c := range l.Candidates {
+			if c.RateMissing && !slices.Contains(l.MissingRates, c.CurCode) {
+				l.MissingRates = append(l.MissingRates, c.CurCode)
+			}
+		}
+	}
+
+	return lines, nil
+}
+
+// rankQuotations memberi skor 0..100 dan mengurutkan quotation satu item.
+// Harga termurah, termin terpanjang dan rating 5 mendapat nilai penuh;
+// quotation tanpa kurs ke base tidak ikut dinilai harganya dan ditaruh paling bawah.
+func rankQuotations(candidates []*quotationcomparison.Candidate, weight quotationcomparison.Weight) {
+	minPrice, maxDays := 0.0, 0
+	for _, c := range candidates {
+		if !c.RateMissing && c.NormalizedPrice > 0 && (minPrice == 0 || c.NormalizedPrice < minPrice) {
+			minPrice = c.NormalizedPrice
+		}
+		maxDays = max(maxDays, c.PaymentDays)
+	}
+
+	total := weight.Price + weight.Payment + weight.Delivery + weight.Rating
+	for _, c := range candidates {
+		if total <= 0 {
+			break
+		}
+
+		var price, payment float64
+		if !c.RateMissing {
+			price = 1
+			if c.NormalizedPrice > 0 {
+				price = minPrice / c.NormalizedPrice
+			}
+		}
+		if maxDays > 0 {
+			payment = float64(c.PaymentDays) / float64(maxDays)
+		}
+		delivery := math.Min(math.Max(weight.DeliveryTypes[strings.ToLower(strings.TrimSpace(c.DeliveryType.String))], 0), 1)
+		rating := math.Min(c.VendorRating/5, 1)
+
+		score := (weight.Price*price + weight.Payment*payment + weight.Delivery*delivery + weight.Rating*rating) / total * 100
+		c.Score = math.Round(score*100) / 100
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.RateMissing != b.RateMissing {
+			return !a.RateMissing
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.NormalizedPrice < b.NormalizedPrice
+	})
+	for i, c := range candidates {
+		c.Rank = i + 1
+	}
+}
+
+func (t *QuotationComparisonRepository) Select(ctx context.Context, data *quotationcomparison.Selection) (*quotationcomparison.Selection, error) {
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	var line porLine
+	if err = tx.GetContext(ctx, &line, `SELECT
+			ItCode,
+			Qty,
+			CancelInd,
+			SuccessInd,
+			COALESCE(VendorQTDocNo, '') AS VendorQTDocNo,
+			COALESCE(VendorQTDNo, '') AS VendorQTDNo
+		FROM tblpurchaseorderreqdtl
+		WHERE DocNo = ? AND DNo = ?
+		FOR UPDATE`, data.PurchaseOrderRequestDocNo, data.PurchaseOrderRequestDNo); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = customerrors.ErrDataNotFound
+			return nil, err
+		}
+		return nil, fmt.Errorf("error get purchase order request detail: %w", err)
+	}
+	// baris yang sudah jadi PO atau dibatalkan tidak boleh ganti vendor
+	if line.CancelInd == "Y" || line.SuccessInd == "Y" {
+		err = fmt.Errorf("%w: purchase order request line is closed", customerrors.ErrInvalidInput)
+		return nil, err
+	}
+
+	var quotation quotationLine
+	if err = tx.GetContext(ctx, &quotation, `SELECT
+			d.ItCode,
+			h.VendorCode,
+			d.Price,
+			d.ActiveInd,
+			d.UsedInd
+		FROM tblvendorquotationdtl d
+		JOIN tblvendorquotationhdr h ON d.DocNo = h.DocNo
+		WHERE d.DocNo = ? AND d.DNo = ?
+		FOR UPDATE`, data.VendorQuotationDocNo, data.VendorQuotationDNo); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = customerrors.ErrDataNotFound
+			return nil, err
+		}
+		return nil, fmt.Errorf("error get vendor quotation detail: %w", err)
+	}
+	if quotation.ItCode != line.ItCode || quotation.ActiveInd != "Y" || quotation.UsedInd != "N" {
+		err = fmt.Errorf("%w: vendor quotation is not available for this item", customerrors.ErrInvalidInput)
+		return nil, err
+	}
+
+	audit, err := newAuditTrail(ctx, tx, "PurchaseOrderRequest", data.PurchaseOrderRequestDocNo)
+	if err != nil {
+		return nil, err
+	}
+
+	// quotation lama dilepas supaya bisa dipakai PO request lain
+	if line.VendorQTDocNo != "" {
+		if _, err = tx.ExecContext(ctx, "UPDATE tblvendorquotationdtl SET UsedInd = 'N' WHERE DocNo = ? AND DNo = ?", line.VendorQTDocNo, line.VendorQTDNo); err != nil {
+			return nil, fmt.Errorf("error release vendor quotation: %w", err)
+		}
+		if _, err = tx.ExecContext(ctx, "UPDATE tblvendorquotationhdr SET Status = 'Outstanding' WHERE DocNo = ? AND Status = 'Used'", line.VendorQTDocNo); err != nil {
+			return nil, fmt.Errorf("error release vendor quotation header: %w", err)
+		}
+	}
+
+	data.VendorCode = quotation.VendorCode
+	data.Price = quotation.Price
+	data.Total = line.Qty * quotation.Price
+	if _, err = tx.ExecContext(ctx, `UPDATE tblpurchaseorderreqdtl SET
+			VendorCode = ?,
+			VendorQTDocNo = ?,
+			VendorQTDNo = ?,
+			Total = ?
+		WHERE DocNo = ? AND DNo = ?`,
+		data.VendorCode,
+		data.VendorQuotationDocNo,
+		data.VendorQuotationDNo,
+		data.Total,
+		data.PurchaseOrderRequestDocNo,
+		data.PurchaseOrderRequestDNo,
+	); err != nil {
+		log.Printf("Detailed error: %+v", err)
+		return nil, fmt.Errorf("error update purchase order request detail: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, "UPDATE tblvendorquotationdtl SET UsedInd = 'Y' WHERE DocNo = ? AND DNo = ?", data.VendorQuotationDocNo, data.VendorQuotationDNo); err != nil {
+		return nil, fmt.Errorf("error update vendor quotation detail: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, `UPDATE tblvendorquotationhdr h
+		SET Status = 'Used'
+		WHERE h.DocNo = ? AND NOT EXISTS (
+			SELECT 1 FROM tblvendorquotationdtl d WHERE d.DocNo = h.DocNo AND d.UsedInd = 'N'
+		)`, data.VendorQuotationDocNo); err != nil {
+		return nil, fmt.Errorf("error update vendor quotation header status: %w", err)
+	}
+
+	if err = audit.Write(ctx, tx, data.LastUpdateBy, data.LastUpdateDate); err != nil {
+		return nil, fmt.Errorf("error inserting log activity: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,125 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/exchangerate"
+	"gitlab.com/ayaka/internal/domain/quotationcomparison"
+	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+const (
+	queryComparisonPorLine   = "SELECT ItCode, Qty, CancelInd, SuccessInd, COALESCE(VendorQTDocNo, '') AS VendorQTDocNo, COALESCE(VendorQTDNo, '') AS VendorQTDNo FROM tblpurchaseorderreqdtl WHERE DocNo = ? AND DNo = ? FOR UPDATE"
+	queryComparisonItem      = "SELECT ItCode, ItName FROM tblitem WHERE ItCode IN (?) ORDER BY ItCode"
+	queryComparisonCandidate = "SELECT d.DocNo, d.DNo, d.ItCode, h.VendorCode, v.VendorName, h.CurCode, d.Price, h.TermOfPayment, h.DeliveryType, COALESCE(r.Rating, 0) AS VendorRating FROM tblvendorquotationdtl d JOIN tblvendorquotationhdr h ON d.DocNo = h.DocNo JOIN tblvendorhdr v ON h.VendorCode = v.VendorCode LEFT JOIN ( SELECT rv.VendorCode, SUM(rv.Value * vr.Weight) / SUM(vr.Weight) AS Rating FROM tblratingvendordtl rv JOIN tblvendorrating vr ON rv.VendorRatingCode = vr.IndicatorCode AND vr.ActiveInd = 'Y' WHERE rv.Active = 'Y' GROUP BY rv.VendorCode ) r ON h.VendorCode = r.VendorCode WHERE d.ItCode IN (?) AND d.ActiveInd = 'Y' AND d.UsedInd = 'N' ORDER BY d.DocNo, d.DNo"
+	queryComparisonQuotation = "SELECT d.ItCode, h.VendorCode, d.Price, d.ActiveInd, d.UsedInd FROM tblvendorquotationdtl d JOIN tblvendorquotationhdr h ON d.DocNo = h.DocNo WHERE d.DocNo = ? AND d.DNo = ? FOR UPDATE"
+)
+
+// fakeRates kurs tetap, mata uang yang tidak ada dianggap belum punya kurs
+type fakeRates struct {
+	exchangerate.Repository
+	rates map[string]float64
+}
+
+func (f *fakeRates) Rates(ctx context.Context, curCodes []string, base, date string) (map[string]float64, error) {
+	rates := map[string]float64{base: 1}
+	for _, code := range curCodes {
+		if rate, ok := f.rates[code]; ok {
+			rates[code] = rate
+		}
+	}
+	return rates, nil
+}
+
+type QuotationComparisonRepositorySuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	repo    *QuotationComparisonRepository
+	db      *sqlx.DB
+}
+
+func (suite *QuotationComparisonRepositorySuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.repo = &QuotationComparisonRepository{
+		DB:   &repository.Sqlx{DB: suite.db},
+		Rate: &fakeRates{},
+	}
+}
+
+func (suite *QuotationComparisonRepositorySuite) TearDownTest() {
+	suite.db.Close()
+}
+
+// harga dibandingkan setelah dikonversi ke base, tanpa kurs selalu paling bawah
+func (suite *QuotationComparisonRepositorySuite) TestRankQuotations() {
+	weight := quotationcomparison.Weight{Price: 60, Payment: 10, Delivery: 10, Rating: 20, DeliveryTypes: map[string]float64{"franco": 1}}
+	candidates := []*quotationcomparison.Candidate{
+		{VendorCode: "V1", NormalizedPrice: 1000, PaymentDays: 30, VendorRating: 5},
+		{VendorCode: "V2", NormalizedPrice: 800, PaymentDays: 0, DeliveryType: nulldatatype.NewNullStringDataType("Franco"), VendorRating: 4},
+		{VendorCode: "V3", RateMissing: true, PaymentDays: 60, VendorRating: 5},
+	}
+
+	rankQuotations(candidates, weight)
+
+	suite.Equal([]string{"V2", "V1", "V3"}, []string{candidates[0].VendorCode, candidates[1].VendorCode, candidates[2].VendorCode})
+	suite.Equal(86.0, candidates[0].Score)
+	suite.Equal(73.0, candidates[1].Score)
+	suite.Equal(3, candidates[2].Rank)
+}
+
+// quotation USD tanpa kurs tetap tampil tapi paling bawah dan dilaporkan
+func (suite *QuotationComparisonRepositorySuite) TestCompare_MissingRate() {
+	suite.mockSQL.ExpectQuery(queryComparisonItem).
+		WithArgs("IT001").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "ItName"}).AddRow("IT001", "Baut"))
+	suite.mockSQL.ExpectQuery(queryComparisonCandidate).
+		WithArgs("IT001").
+		WillReturnRows(sqlmock.NewRows([]string{"DocNo", "DNo", "ItCode", "VendorCode", "VendorName", "CurCode", "Price", "TermOfPayment", "DeliveryType", "VendorRating"}).
+			AddRow("VQ1", "001", "IT001", "V001", "Vendor USD", "USD", 1, "", nil, 5).
+			AddRow("VQ2", "001", "IT001", "V002", "Vendor IDR", "IDR", 15000, "", nil, 3))
+
+	lines, err := suite.repo.Compare(context.Background(), "", []string{"IT001"}, "IDR", quotationcomparison.Weight{Price: 100})
+
+	suite.NoError(err)
+	suite.Equal([]string{"USD"}, lines[0].MissingRates)
+	suite.Equal("V002", lines[0].Candidates[0].VendorCode)
+	suite.True(lines[0].Candidates[1].RateMissing)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func (suite *QuotationComparisonRepositorySuite) TestSelect_RejectsUsedQuotation() {
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectQuery(queryComparisonPorLine).
+		WithArgs("POR1", "001").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "Qty", "CancelInd", "SuccessInd", "VendorQTDocNo", "VendorQTDNo"}).
+			AddRow("IT001", 10, "N", "N", "", ""))
+	suite.mockSQL.ExpectQuery(queryComparisonQuotation).
+		WithArgs("VQ1", "001").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "VendorCode", "Price", "ActiveInd", "UsedInd"}).
+			AddRow("IT001", "V001", 100, "Y", "Y"))
+	suite.mockSQL.ExpectRollback()
+
+	_, err := suite.repo.Select(context.Background(), &quotationcomparison.Selection{
+		PurchaseOrderRequestDocNo: "POR1",
+		PurchaseOrderRequestDNo:   "001",
+		VendorQuotationDocNo:      "VQ1",
+		VendorQuotationDNo:        "001",
+	})
+
+	suite.ErrorIs(err, customerrors.ErrInvalidInput)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func TestQuotationComparisonRepository(t *testing.T) {
+	suite.Run(t, new(QuotationComparisonRepositorySuite))
+}
@@ -0,0 +1,322 @@
+package sqlx
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/replenishment"
+	"gitlab.com/ayaka/internal/domain/uomconversion"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+// ReplenishmentRepository menyimpan min/max, reorder point dan lead time per
+// item + gudang, lalu menghitung usulan pesan dari stok on hand, open PO dan
+// material request yang belum jadi PO.
+//
+//	CREATE TABLE tblreplenishment (
+//		ItCode VARCHAR(40) NOT NULL,
+//		WhsCode VARCHAR(16) NOT NULL,
+//		SafetyStock DECIMAL(18,4) NOT NULL DEFAULT 0,
+//		ReorderPoint DECIMAL(18,4) NOT NULL DEFAULT 0,
+//		MinQty DECIMAL(18,4) NOT NULL DEFAULT 0,
+//		MaxQty DECIMAL(18,4) NOT NULL DEFAULT 0,
+//		LeadTime INT NOT NULL DEFAULT 0,
+//		ActInd CHAR(1) NOT NULL DEFAULT 'Y',
+//		Remark VARCHAR(400) NULL,
+//		CreateBy VARCHAR(16) NOT NULL,
+//		CreateDt VARCHAR(12) NOT NULL,
+//		LastUpBy VARCHAR(16) NULL,
+//		LastUpDt VARCHAR(12) NULL,
+//		PRIMARY KEY (ItCode, WhsCode)
+//	);
+type ReplenishmentRepository struct {
+	DB  *repository.Sqlx         `inject:"database"`
+	Uom uomconversion.Repository `inject:"uomConversionRepository"`
+}
+
+func (t *ReplenishmentRepository) FetchSetting(ctx context.Context, warehouse, search string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	var totalRecords int
+
+	filters := []string{"r.WhsCode LIKE ?", "(r.ItCode LIKE ? OR i.ItName LIKE ?)"}
+	args := []interface{}{"%" + warehouse + "%", "%" + search + "%", "%" + search + "%"}
+	if scope, scopeArgs := warehouseScope(ctx, "r.WhsCode"); scope != "" {
+		filters = append(filters, scope)
+		args = append(args, scopeArgs...)
+	}
+	where := strings.Join(filters, " AND ")
+
+	countQuery := "SELECT COUNT(*) FROM tblreplenishment r JOIN tblitem i ON r.ItCode = i.ItCode WHERE " + where
+	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+
+	var totalPages, offset int
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+	}
+
+	data := make([]*replenishment.Setting, 0)
+	query := `SELECT
+			r.ItCode,
+			i.ItName,
+			r.WhsCode,
+			w.WhsName,
+			r.SafetyStock,
+			r.ReorderPoint,
+			r.MinQty,
+			r.MaxQty,
+			r.LeadTime,
+			r.ActInd,
+			r.Remark
+		FROM tblreplenishment r
+		JOIN tblitem i ON r.ItCode = i.ItCode
+		JOIN tblwarehouse w ON r.WhsCode = w.WhsCode
+		WHERE ` + where + `
+		ORDER BY r.ItCode, r.WhsCode
+		LIMIT ? OFFSET ?`
+	if err := t.DB.SelectContext(ctx, &data, query, append(args, param.PageSize, offset)...); err != nil {
+		return nil, fmt.Errorf("error Fetch replenishment setting: %w", err)
+	}
+
+	j := offset
+	for _, d := range data {
+		j++
+		d.Number = uint(j)
+	}
+
+	return &pagination.PaginationResponse{
+		Data:         data,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}, nil
+}
+
+// SaveSetting membuat atau mengganti setting satu item + gudang
+func (t *ReplenishmentRepository) SaveSetting(ctx context.Context, data *replenishment.Setting) (*replenishment.Setting, error) {
+	if err := checkWarehouse(ctx, data.WarehouseCode); err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO tblreplenishment (
+			ItCode,
+			WhsCode,
+			SafetyStock,
+			ReorderPoint,
+			MinQty,
+			MaxQty,
+			LeadTime,
+			ActInd,
+			Remark,
+			CreateBy,
+			CreateDt
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			SafetyStock = VALUES(SafetyStock),
+			ReorderPoint = VALUES(ReorderPoint),
+			MinQty = VALUES(MinQty),
+			MaxQty = VALUES(MaxQty),
+			LeadTime = VALUES(LeadTime),
+			ActInd = VALUES(ActInd),
+			Remark = VALUES(Remark),
+			LastUpBy = VALUES(CreateBy),
+			LastUpDt = VALUES(CreateDt)`
+	if _, err := t.DB.ExecContext(ctx, query,
+		data.ItemCode,
+		data.WarehouseCode,
+		data.SafetyStock,
+		data.ReorderPoint,
+		data.MinQty,
+		data.MaxQty,
+		data.LeadTime,
+		data.Active,
+		data.Remark,
+		data.CreateBy,
+		data.CreateDate,
+	); err != nil {
+		log.Printf("Detailed error: %+v", err)
+		return nil, fmt.Errorf("error Save Replenishment Setting: %w", err)
+	}
+
+	return data, nil
+}
+
+// Proposals menghitung usulan replenishment untuk semua setting aktif. Open
+// PO dan material request tidak mencatat gudang, jadi sisa pesanan per item
+// dibagi ke gudang berurutan sampai kebutuhan masing-masing terpenuhi.
+func (t *ReplenishmentRepository) Proposals(ctx context.Context, warehouse, itemCategory string) ([]*replenishment.Proposal, error) {
+	filters := []string{"r.ActInd = 'Y'"}
+	var args []interface{}
+	if warehouse != "" {
+		filters = append(filters, "r.WhsCode = ?")
+		args = append(args, warehouse)
+	}
+	if itemCategory != "" {
+		filters = append(filters, "i.ItCtCode = ?")
+		args = append(args, itemCategory)
+	}
+	if scope, scopeArgs := warehouseScope(ctx, "r.WhsCode"); scope != "" {
+		filters = append(filters, scope)
+		args = append(args, scopeArgs...)
+	}
+
+	data := make([]*replenishment.Proposal, 0)
+	query := `SELECT
+			r.ItCode,
+			i.ItName,
+			r.WhsCode,
+			w.WhsName,
+			u.UomName,
+			r.SafetyStock,
+			r.ReorderPoint,
+			r.MinQty,
+			r.MaxQty,
+			r.LeadTime,
+			COALESCE((
+				SELECT SUM(s.Qty + s.Qty2 - s.Qty3)
+				FROM tblstocksummary s
+				WHERE s.ItCode = r.ItCode AND s.WhsCode = r.WhsCode
+			), 0) AS OnHand
+		FROM tblreplenishment r
+		JOIN tblitem i ON r.ItCode = i.ItCode
+		JOIN tbluom u ON i.InventoryUOMCode = u.UomCode
+		JOIN tblwarehouse w ON r.WhsCode = w.WhsCode
+		WHERE ` + strings.Join(filters, " AND ") + `
+		ORDER BY r.ItCode, r.WhsCode`
+	if err := t.DB.SelectContext(ctx, &data, query, args...); err != nil {
+		return nil, fmt.Errorf("error fetch replenishment: %w", err)
+	}
+	if len(data) == 0 {
+		return data, nil
+	}
+
+	var items []interface{}
+	var itemCodes, placeholders []string
+	seen := map[string]bool{}
+	for _, p := range data {
+		if !seen[p.ItemCode] {
+			seen[p.ItemCode] = true
+			items = append(items, p.ItemCode)
+			itemCodes = append(itemCodes, p.ItemCode)
+			placeholders = append(placeholders, "?")
+		}
+	}
+	in := strings.Join(placeholders, ",")
+
+	// stok dan setting dalam UoM inventory, PO dan MR dalam UoM beli
+	factors, err := t.Uom.ItemFactors(ctx, t.DB, itemCodes, uomconversion.Purchase, uomconversion.Inventory)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range data {
+		p.PurchaseFactor = factors[p.ItemCode]
+	}
+
+	// sama dengan outstanding PO: qty PO dikurangi penerimaan yang tidak batal
+	openPO, err := t.openQty(ctx, `SELECT o.ItCode, SUM(o.OutstandingQty) AS Qty
+		FROM (
+			SELECT
+				d.ItCode,
+				d.Qty - COALESCE((
+					SELECT SUM(r.PurchaseQty)
+					FROM tblpurchasematerialreceivedtl r
+					WHERE r.PurchaseOrderDocNo = d.DocNo
+					AND r.PurchaseOrderDNo = d.DNo
+					AND (r.CancelInd IS NULL OR r.CancelInd != 'Y')
+				), 0) AS OutstandingQty
+			FROM tblpurchaseorderdtl d
+			WHERE d.CancelInd != 'Y' AND d.ItCode IN (`+in+`)
+		) o
+		WHERE o.OutstandingQty > 0
+		GROUP BY o.ItCode`, items)
+	if err != nil {
+		return nil, err
+	}
+
+	// material request yang masih terbuka, dikurangi bagian yang sudah jadi PO
+	// supaya tidak terhitung dua kali dengan open PO
+	openRequest, err := t.openQty(ctx, `SELECT o.ItCode, SUM(o.OutstandingQty) AS Qty
+		FROM (
+			SELECT
+				mr.ItCode,
+				mr.Qty - COALESCE(SUM(po.Qty), 0) AS OutstandingQty
+			FROM tblmaterialrequestdtl mr
+			LEFT JOIN tblpurchaseorderreqdtl por
+				ON mr.DocNo = por.MaterialReqDocNo AND mr.DNo = por.MaterialReqDNo
+			LEFT JOIN tblpurchaseorderdtl po
+				ON por.DocNo = po.PurchaseOrderReqDocNo AND por.DNo = po.PurchaseOrderReqDNo AND po.CancelInd != 'Y'
+			WHERE mr.CancelInd != 'Y' AND mr.OpenInd = 'Y' AND mr.ItCode IN (`+in+`)
+			GROUP BY mr.DocNo, mr.DNo, mr.ItCode, mr.Qty
+		) o
+		WHERE o.OutstandingQty > 0
+		GROUP BY o.ItCode`, items)
+	if err != nil {
+		return nil, err
+	}
+
+	for item, qty := range openPO {
+		openPO[item] = qty * factors[item]
+	}
+	for item, qty := range openRequest {
+		openRequest[item] = qty * factors[item]
+	}
+
+	propose(data, openPO, openRequest)
+
+	return data, nil
+}
+
+func (t *ReplenishmentRepository) openQty(ctx context.Context, query string, items []interface{}) (map[string]float32, error) {
+	var rows []struct {
+		ItCode string  `db:"ItCode"`
+		Qty    float32 `db:"Qty"`
+	}
+	if err := t.DB.SelectContext(ctx, &rows, query, items...); err != nil {
+		return nil, fmt.Errorf("error fetch open quantity: %w", err)
+	}
+
+	open := make(map[string]float32, len(rows))
+	for _, r := range rows {
+		open[r.ItCode] = r.Qty
+	}
+
+	return open, nil
+}
+
+// propose mengisi Projected dan SuggestedQty. Target stok adalah MaxQty (atau
+// ReorderPoint + SafetyStock kalau max kosong), pesan dipicu kalau Projected
+// sudah sampai max(ReorderPoint, MinQty).
+func propose(proposals []*replenishment.Proposal, openPO, openRequest map[string]float32) {
+	for _, p := range proposals {
+		target := p.MaxQty
+		if target == 0 {
+			target = p.ReorderPoint + p.SafetyStock
+		}
+		target = max(target, p.MinQty)
+
+		need := max(target-p.OnHand, 0)
+		p.OpenPO = min(openPO[p.ItemCode], need)
+		openPO[p.ItemCode] -= p.OpenPO
+		need -= p.OpenPO
+		p.OpenRequest = min(openRequest[p.ItemCode], need)
+		openRequest[p.ItemCode] -= p.OpenRequest
+
+		p.Projected = p.OnHand + p.OpenPO + p.OpenRequest
+		p.Reorder = target > 0 && p.Projected <= max(p.ReorderPoint, p.MinQty) && p.Projected < target
+		if p.Reorder {
+			p.SuggestedQty = target - p.Projected
+		}
+	}
+}
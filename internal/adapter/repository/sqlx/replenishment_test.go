@@ -0,0 +1,82 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/adapter/repository"
+)
+
+const (
+	queryReplenishment        = "SELECT r.ItCode, i.ItName, r.WhsCode, w.WhsName, u.UomName, r.SafetyStock, r.ReorderPoint, r.MinQty, r.MaxQty, r.LeadTime, COALESCE(( SELECT SUM(s.Qty + s.Qty2 - s.Qty3) FROM tblstocksummary s WHERE s.ItCode = r.ItCode AND s.WhsCode = r.WhsCode ), 0) AS OnHand FROM tblreplenishment r JOIN tblitem i ON r.ItCode = i.ItCode JOIN tbluom u ON i.InventoryUOMCode = u.UomCode JOIN tblwarehouse w ON r.WhsCode = w.WhsCode WHERE r.ActInd = 'Y' ORDER BY r.ItCode, r.WhsCode"
+	queryReplenishmentOpenPO  = "SELECT o.ItCode, SUM(o.OutstandingQty) AS Qty FROM ( SELECT d.ItCode, d.Qty - COALESCE(( SELECT SUM(r.PurchaseQty) FROM tblpurchasematerialreceivedtl r WHERE r.PurchaseOrderDocNo = d.DocNo AND r.PurchaseOrderDNo = d.DNo AND (r.CancelInd IS NULL OR r.CancelInd != 'Y') ), 0) AS OutstandingQty FROM tblpurchaseorderdtl d WHERE d.CancelInd != 'Y' AND d.ItCode IN (?) ) o WHERE o.OutstandingQty > 0 GROUP BY o.ItCode"
+	queryReplenishmentOpenReq = "SELECT o.ItCode, SUM(o.OutstandingQty) AS Qty FROM ( SELECT mr.ItCode, mr.Qty - COALESCE(SUM(po.Qty), 0) AS OutstandingQty FROM tblmaterialrequestdtl mr LEFT JOIN tblpurchaseorderreqdtl por ON mr.DocNo = por.MaterialReqDocNo AND mr.DNo = por.MaterialReqDNo LEFT JOIN tblpurchaseorderdtl po ON por.DocNo = po.PurchaseOrderReqDocNo AND por.DNo = po.PurchaseOrderReqDNo AND po.CancelInd != 'Y' WHERE mr.CancelInd != 'Y' AND mr.OpenInd = 'Y' AND mr.ItCode IN (?) GROUP BY mr.DocNo, mr.DNo, mr.ItCode, mr.Qty ) o WHERE o.OutstandingQty > 0 GROUP BY o.ItCode"
+)
+
+type ReplenishmentRepositorySuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	repo    *ReplenishmentRepository
+	db      *sqlx.DB
+}
+
+func (suite *ReplenishmentRepositorySuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	db := &repository.Sqlx{DB: suite.db}
+	suite.repo = &ReplenishmentRepository{
+		DB:  db,
+		Uom: &UomConversionRepository{DB: db},
+	}
+}
+
+func (suite *ReplenishmentRepositorySuite) TearDownTest() {
+	suite.db.Close()
+}
+
+// open PO 3 BOX dan MR 1 BOX (1 BOX = 10 PCS) habis dipakai WHS01 (butuh 80 PCS)
+// sehingga projected 60 sudah di atas reorder point, WHS02 tidak kebagian dan
+// tetap harus pesan 40
+func (suite *ReplenishmentRepositorySuite) TestProposals_SharesOpenQtyAcrossWarehouses() {
+	suite.mockSQL.ExpectQuery(queryReplenishment).
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "ItName", "WhsCode", "WhsName", "UomName", "SafetyStock", "ReorderPoint", "MinQty", "MaxQty", "LeadTime", "OnHand"}).
+			AddRow("IT001", "Item 1", "WHS01", "Gudang 1", "PCS", 10, 40, 20, 100, 7, 20).
+			AddRow("IT001", "Item 1", "WHS02", "Gudang 2", "PCS", 0, 20, 0, 50, 7, 10))
+	suite.mockSQL.ExpectQuery("SELECT i.ItCode, i.PurchaseUomCode AS UomFrom, i.InventoryUOMCode AS UomTo FROM tblitem i WHERE i.ItCode IN (?)").
+		WithArgs("IT001").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "UomFrom", "UomTo"}).AddRow("IT001", "BOX", "PCS"))
+	suite.mockSQL.ExpectQuery("SELECT ItCode, UomFrom, UomTo, Factor FROM tbluomconversion WHERE ItCode = '' OR ItCode IN (?)").
+		WithArgs("IT001").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "UomFrom", "UomTo", "Factor"}).AddRow("", "BOX", "PCS", 10))
+	suite.mockSQL.ExpectQuery(queryReplenishmentOpenPO).
+		WithArgs("IT001").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "Qty"}).AddRow("IT001", 3))
+	suite.mockSQL.ExpectQuery(queryReplenishmentOpenReq).
+		WithArgs("IT001").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "Qty"}).AddRow("IT001", 1))
+
+	result, err := suite.repo.Proposals(context.Background(), "", "")
+
+	suite.Require().NoError(err)
+	suite.Require().Len(result, 2)
+	suite.Equal(float32(30), result[0].OpenPO)
+	suite.Equal(float32(10), result[0].OpenRequest)
+	suite.Equal(float32(60), result[0].Projected)
+	suite.False(result[0].Reorder)
+	suite.Equal(float32(0), result[1].OpenPO)
+	suite.Equal(float32(0), result[1].OpenRequest)
+	suite.True(result[1].Reorder)
+	suite.Equal(float32(40), result[1].SuggestedQty)
+	suite.Equal(float32(10), result[1].PurchaseFactor)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func TestReplenishmentRepositorySuite(t *testing.T) {
+	suite.Run(t, new(ReplenishmentRepositorySuite))
+}
@@ -0,0 +1,123 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"gitlab.com/ayaka/internal/adapter/repository"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/domain/stockcard"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+type StockCardRepository struct {
+	DB *repository.Sqlx `inject:"database"`
+}
+
+// Fetch menyusun kartu stok dari tblstockmovement. Saldo awal adalah semua
+// movement sebelum startDate, saldo akhir dicocokkan ke tblstocksummary.
+func (t *StockCardRepository) Fetch(ctx context.Context, itemCode, warehouse, batch, startDate, endDate string) (*stockcard.Read, error) {
+	result := &stockcard.Read{}
+	query := `SELECT i.ItCode, i.ItName, u.UomName
+		FROM tblitem i
+		JOIN tbluom u ON i.InventoryUOMCode = u.UomCode
+		WHERE i.ItCode = ?`
+	if err := t.DB.GetContext(ctx, result, query, itemCode); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrDataNotFound
+		}
+		return nil, fmt.Errorf("error get item stock card: %w", err)
+	}
+	result.WarehouseCode = warehouse
+	result.BatchNo = batch
+	result.StartDate = share.FormatDate(startDate)
+	result.EndDate = share.FormatDate(endDate)
+
+	// filter yang sama dipakai untuk tblstockmovement dan tblstocksummary (alias s)
+	filters := []string{"s.ItCode = ?"}
+	args := []interface{}{itemCode}
+	if warehouse != "" {
+		filters = append(filters, "s.WhsCode = ?")
+		args = append(args, warehouse)
+	}
+	if batch != "" {
+		filters = append(filters, "s.BatchNo = ?")
+		args = append(args, batch)
+	}
+	if scope, scopeArgs := warehouseScope(ctx, "s.WhsCode"); scope != "" {
+		filters = append(filters, scope)
+		args = append(args, scopeArgs...)
+	}
+	where := strings.Join(filters, " AND ")
+
+	var balance struct {
+		Opening float32 `db:"Opening"`
+		After   float32 `db:"After"`
+	}
+	query = `SELECT
+			COALESCE(SUM(CASE WHEN s.DocDt < ? THEN s.Qty + s.Qty2 - s.Qty3 ELSE 0 END), 0) AS Opening,
+			COALESCE(SUM(CASE WHEN s.DocDt > ? THEN s.Qty + s.Qty2 - s.Qty3 ELSE 0 END), 0) AS After
+		FROM tblstockmovement s
+		WHERE s.CancelInd = 'N' AND ` + where
+	if err := t.DB.GetContext(ctx, &balance, query, append([]interface{}{startDate, endDate}, args...)...); err != nil {
+		return nil, fmt.Errorf("error get opening balance: %w", err)
+	}
+
+	var lines []*stockcard.Line
+	query = `SELECT
+			s.DocDt,
+			s.DocType,
+			s.DocNo,
+			(
+				SELECT w2.WhsName
+				FROM tblstockmovement s2
+				JOIN tblwarehouse w2 ON s2.WhsCode = w2.WhsCode
+				WHERE s2.DocNo = s.DocNo
+					AND s2.ItCode = s.ItCode
+					AND s2.WhsCode <> s.WhsCode
+					AND s2.CancelInd = 'N'
+				LIMIT 1
+			) AS FromTo,
+			w.WhsName,
+			s.BatchNo,
+			(s.Qty + s.Qty2) AS InQty,
+			s.Qty3 AS OutQty,
+			s.Remark
+		FROM tblstockmovement s
+		JOIN tblwarehouse w ON s.WhsCode = w.WhsCode
+		WHERE s.CancelInd = 'N' AND ` + where + ` AND s.DocDt BETWEEN ? AND ?
+		ORDER BY s.DocDt, s.CreateDt, s.DocNo, s.DNo`
+	if err := t.DB.SelectContext(ctx, &lines, query, append(args, startDate, endDate)...); err != nil {
+		return nil, fmt.Errorf("error fetch stock card: %w", err)
+	}
+
+	running := balance.Opening
+	for i, line := range lines {
+		running += line.InQty - line.OutQty
+		line.Number = uint(i + 1)
+		line.Balance = running
+		line.DocDt = share.FormatDate(line.DocDt)
+		result.TotalIn += line.InQty
+		result.TotalOut += line.OutQty
+	}
+	if lines == nil {
+		lines = []*stockcard.Line{}
+	}
+	result.Lines = lines
+	result.Opening = balance.Opening
+	result.Closing = running
+
+	query = `SELECT COALESCE(SUM(s.Qty + s.Qty2 - s.Qty3), 0)
+		FROM tblstocksummary s
+		WHERE ` + where
+	if err := t.DB.GetContext(ctx, &result.SummaryBalance, query, args...); err != nil {
+		return nil, fmt.Errorf("error get stock summary balance: %w", err)
+	}
+	result.Reconciled = math.Abs(float64(result.Closing+balance.After-result.SummaryBalance)) < 0.01
+
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+const (
+	queryStockCardItem    = "SELECT i.ItCode, i.ItName, u.UomName FROM tblitem i JOIN tbluom u ON i.InventoryUOMCode = u.UomCode WHERE i.ItCode = ?"
+	queryStockCardBalance = "SELECT COALESCE(SUM(CASE WHEN s.DocDt < ? THEN s.Qty + s.Qty2 - s.Qty3 ELSE 0 END), 0) AS Opening, COALESCE(SUM(CASE WHEN s.DocDt > ? THEN s.Qty + s.Qty2 - s.Qty3 ELSE 0 END), 0) AS After FROM tblstockmovement s WHERE s.CancelInd = 'N' AND s.ItCode = ? AND s.WhsCode = ?"
+	queryStockCardLines   = "SELECT s.DocDt, s.DocType, s.DocNo, ( SELECT w2.WhsName FROM tblstockmovement s2 JOIN tblwarehouse w2 ON s2.WhsCode = w2.WhsCode WHERE s2.DocNo = s.DocNo AND s2.ItCode = s.ItCode AND s2.WhsCode <> s.WhsCode AND s2.CancelInd = 'N' LIMIT 1 ) AS FromTo, w.WhsName, s.BatchNo, (s.Qty + s.Qty2) AS InQty, s.Qty3 AS OutQty, s.Remark FROM tblstockmovement s JOIN tblwarehouse w ON s.WhsCode = w.WhsCode WHERE s.CancelInd = 'N' AND s.ItCode = ? AND s.WhsCode = ? AND s.DocDt BETWEEN ? AND ? ORDER BY s.DocDt, s.CreateDt, s.DocNo, s.DNo"
+	queryStockCardSummary = "SELECT COALESCE(SUM(s.Qty + s.Qty2 - s.Qty3), 0) FROM tblstocksummary s WHERE s.ItCode = ? AND s.WhsCode = ?"
+)
+
+type StockCardRepositorySuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	repo    *StockCardRepository
+	db      *sqlx.DB
+}
+
+func (suite *StockCardRepositorySuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.repo = &StockCardRepository{
+		DB: &repository.Sqlx{DB: suite.db},
+	}
+}
+
+func (suite *StockCardRepositorySuite) TearDownTest() {
+	suite.db.Close()
+}
+
+// saldo awal 10, masuk 5, keluar 3 -> saldo akhir 12, ditambah 2 setelah periode = summary 14
+func (suite *StockCardRepositorySuite) TestFetch_RunningBalance() {
+	suite.mockSQL.ExpectQuery(queryStockCardItem).
+		WithArgs("IT001").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "ItName", "UomName"}).AddRow("IT001", "Item 1", "PCS"))
+	suite.mockSQL.ExpectQuery(queryStockCardBalance).
+		WithArgs("20260101", "20260131", "IT001", "WHS01").
+		WillReturnRows(sqlmock.NewRows([]string{"Opening", "After"}).AddRow(10, 2))
+	suite.mockSQL.ExpectQuery(queryStockCardLines).
+		WithArgs("IT001", "WHS01", "20260101", "20260131").
+		WillReturnRows(sqlmock.NewRows([]string{"DocDt", "DocType", "DocNo", "FromTo", "WhsName", "BatchNo", "InQty", "OutQty", "Remark"}).
+			AddRow("20260105", "Purchase Material Receive", "0001/RCV", nil, "Gudang 1", "B1", 5, 0, nil).
+			AddRow("20260110", "Stock Mutation (From)", "0001/MUT", "Gudang 2", "Gudang 1", "B1", 0, 3, nil))
+	suite.mockSQL.ExpectQuery(queryStockCardSummary).
+		WithArgs("IT001", "WHS01").
+		WillReturnRows(sqlmock.NewRows([]string{"Qty"}).AddRow(14))
+
+	result, err := suite.repo.Fetch(context.Background(), "IT001", "WHS01", "", "20260101", "20260131")
+
+	suite.Require().NoError(err)
+	suite.Equal(float32(10), result.Opening)
+	suite.Equal(float32(15), result.Lines[0].Balance)
+	suite.Equal(float32(12), result.Lines[1].Balance)
+	suite.Equal("Gudang 2", result.Lines[1].FromTo.String)
+	suite.Equal(float32(12), result.Closing)
+	suite.True(result.Reconciled)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func (suite *StockCardRepositorySuite) TestFetch_ItemNotFound() {
+	suite.mockSQL.ExpectQuery(queryStockCardItem).
+		WithArgs("NOPE").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "ItName", "UomName"}))
+
+	_, err := suite.repo.Fetch(context.Background(), "NOPE", "", "", "20260101", "20260131")
+
+	suite.ErrorIs(err, customerrors.ErrDataNotFound)
+}
+
+func TestStockCardRepositorySuite(t *testing.T) {
+	suite.Run(t, new(StockCardRepositorySuite))
+}
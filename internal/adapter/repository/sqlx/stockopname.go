@@ -0,0 +1,602 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+	"gitlab.com/ayaka/internal/domain/stockopname"
+	"gitlab.com/ayaka/internal/domain/tblstockadjustmentdtl"
+	"gitlab.com/ayaka/internal/domain/tblstockadjustmenthdr"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+// StockOpnameRepository menyimpan snapshot stok saat opname dibuka, hasil hitung
+// per round, lalu saat approve membuat stock adjustment atas selisihnya.
+//
+//	CREATE TABLE tblstockopnamehdr (
+//		DocNo VARCHAR(30) NOT NULL PRIMARY KEY,
+//		DocDt VARCHAR(8) NOT NULL,
+//		WhsCode VARCHAR(16) NOT NULL,
+//		ItCtCode VARCHAR(16) NULL,
+//		Bin VARCHAR(16) NULL,
+//		Status CHAR(1) NOT NULL DEFAULT 'O',
+//		AdjDocNo VARCHAR(30) NULL,
+//		Remark VARCHAR(400) NULL,
+//		CreateBy VARCHAR(16) NOT NULL,
+//		CreateDt VARCHAR(12) NOT NULL,
+//		LastUpBy VARCHAR(16) NULL,
+//		LastUpDt VARCHAR(12) NULL,
+//		KEY idx_stockopname_whs (WhsCode, Status)
+//	);
+//
+//	CREATE TABLE tblstockopnamedtl (
+//		DocNo VARCHAR(30) NOT NULL,
+//		DNo VARCHAR(6) NOT NULL,
+//		ItCode VARCHAR(40) NOT NULL,
+//		BatchNo VARCHAR(60) NOT NULL,
+//		Source VARCHAR(80) NOT NULL,
+//		Qty DECIMAL(18,4) NOT NULL,
+//		CreateBy VARCHAR(16) NOT NULL,
+//		CreateDt VARCHAR(12) NOT NULL,
+//		PRIMARY KEY (DocNo, DNo)
+//	);
+//
+//	CREATE TABLE tblstockopnamecount (
+//		DocNo VARCHAR(30) NOT NULL,
+//		Round INT NOT NULL,
+//		ItCode VARCHAR(40) NOT NULL,
+//		BatchNo VARCHAR(60) NOT NULL,
+//		QtyCount DECIMAL(18,4) NOT NULL,
+//		CreateBy VARCHAR(16) NOT NULL,
+//		CreateDt VARCHAR(12) NOT NULL,
+//		PRIMARY KEY (DocNo, Round, ItCode, BatchNo)
+//	);
+type StockOpnameRepository struct {
+	DB     *repository.Sqlx                 `inject:"database"`
+	Adjust tblstockadjustmenthdr.Repository `inject:"tblStockAdjustRepository"`
+	ID     *formatid.GenerateIDHandler      `inject:"generateID"`
+}
+
+func (t *StockOpnameRepository) Fetch(ctx context.Context, doc, warehouse, status string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	var totalRecords int
+
+	filters := []string{"h.DocNo LIKE ?", "h.WhsCode LIKE ?"}
+	args := []interface{}{"%" + doc + "%", "%" + warehouse + "%"}
+	if status != "" {
+		filters = append(filters, "h.Status = ?")
+		args = append(args, status)
+	}
+	if scope, scopeArgs := warehouseScope(ctx, "h.WhsCode"); scope != "" {
+		filters = append(filters, scope)
+		args = append(args, scopeArgs...)
+	}
+	where := strings.Join(filters, " AND ")
+
+	countQuery := "SELECT COUNT(*) FROM tblstockopnamehdr h WHERE " + where
+	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+
+	var totalPages int
+	var offset int
+
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+		offset = 0
+	}
+
+	var data []*stockopname.Read
+	query := stockOpnameSelect + " WHERE " + where + " ORDER BY h.CreateDt DESC LIMIT ? OFFSET ?"
+	if err := t.DB.SelectContext(ctx, &data, query, append(args, param.PageSize, offset)...); err != nil {
+		return nil, fmt.Errorf("error fetch stock opname: %w", err)
+	}
+
+	j := offset
+	for _, d := range data {
+		j++
+		d.Number = uint(j)
+		d.Date = share.FormatDate(d.Date)
+	}
+	if data == nil {
+		data = []*stockopname.Read{}
+	}
+
+	return &pagination.PaginationResponse{
+		Data:         data,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}, nil
+}
+
+const stockOpnameSelect = `SELECT
+		h.DocNo,
+		h.DocDt,
+		h.WhsCode,
+		w.WhsName,
+		h.ItCtCode,
+		c.ItCtName,
+		h.Bin,
+		h.Status,
+		h.AdjDocNo,
+		h.Remark,
+		h.CreateBy
+	FROM tblstockopnamehdr h
+	JOIN tblwarehouse w ON h.WhsCode = w.WhsCode
+	LEFT JOIN tblitemcategory c ON h.ItCtCode = c.ItCtCode`
+
+func (t *StockOpnameRepository) Detail(ctx context.Context, docNo string) (*stockopname.Detail, error) {
+	var detail stockopname.Detail
+	if err := t.DB.GetContext(ctx, &detail.Read, stockOpnameSelect+" WHERE h.DocNo = ?", docNo); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrDataNotFound
+		}
+		return nil, fmt.Errorf("error detail stock opname: %w", err)
+	}
+	detail.Date = share.FormatDate(detail.Date)
+
+	var lines []*stockopname.Variance
+	query := `SELECT d.ItCode, i.ItName, d.BatchNo, u.UomName, SUM(d.Qty) AS Qty
+		FROM tblstockopnamedtl d
+		JOIN tblitem i ON d.ItCode = i.ItCode
+		JOIN tbluom u ON i.PurchaseUOMCode = u.UomCode
+		WHERE d.DocNo = ?
+		GROUP BY d.ItCode, i.ItName, d.BatchNo, u.UomName
+		ORDER BY d.ItCode, d.BatchNo`
+	if err := t.DB.SelectContext(ctx, &lines, query, docNo); err != nil {
+		return nil, fmt.Errorf("error detail stock opname snapshot: %w", err)
+	}
+
+	var counts []*stockopname.Variance
+	query = `SELECT c.ItCode, i.ItName, c.BatchNo, u.UomName, c.Round, c.QtyCount
+		FROM tblstockopnamecount c
+		JOIN tblitem i ON c.ItCode = i.ItCode
+		JOIN tbluom u ON i.PurchaseUOMCode = u.UomCode
+		WHERE c.DocNo = ?
+		AND c.Round = (
+			SELECT MAX(c2.Round) FROM tblstockopnamecount c2
+			WHERE c2.DocNo = c.DocNo AND c2.ItCode = c.ItCode AND c2.BatchNo = c.BatchNo
+		)
+		ORDER BY c.ItCode, c.BatchNo`
+	if err := t.DB.SelectContext(ctx, &counts, query, docNo); err != nil {
+		return nil, fmt.Errorf("error detail stock opname count: %w", err)
+	}
+
+	detail.Lines = mergeOpnameCount(lines, counts)
+
+	return &detail, nil
+}
+
+// mergeOpnameCount menggabungkan hitungan ke baris snapshot. Item / batch yang
+// ditemukan saat hitung tapi tidak ada di snapshot ditambahkan dengan stok sistem 0.
+func mergeOpnameCount(lines, counts []*stockopname.Variance) []*stockopname.Variance {
+	index := make(map[string]*stockopname.Variance, len(lines))
+	for _, line := range lines {
+		index[line.ItemCode+"*"+line.Batch] = line
+	}
+	for _, count := range counts {
+		line, ok := index[count.ItemCode+"*"+count.Batch]
+		if !ok {
+			line = count
+			lines = append(lines, line)
+		}
+		line.Counted = true
+		line.Round = count.Round
+		line.CountedQty = count.CountedQty
+	}
+	for _, line := range lines {
+		if line.Counted {
+			line.Variance = line.CountedQty - line.SystemQty
+		}
+	}
+	if lines == nil {
+		lines = []*stockopname.Variance{}
+	}
+	return lines
+}
+
+func (t *StockOpnameRepository) Create(ctx context.Context, data *stockopname.Create) (*stockopname.Create, error) {
+	if err := checkWarehouse(ctx, data.WarehouseCode); err != nil {
+		return nil, err
+	}
+
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	// satu gudang hanya boleh punya satu opname yang masih terbuka
+	var open int
+	if err = tx.GetContext(ctx, &open, "SELECT COUNT(*) FROM tblstockopnamehdr WHERE WhsCode = ? AND Status = ? FOR UPDATE", data.WarehouseCode, stockopname.Open); err != nil {
+		return nil, fmt.Errorf("error check open stock opname: %w", err)
+	}
+	if open > 0 {
+		err = fmt.Errorf("%w: warehouse %s already has an open stock opname", customerrors.ErrDataAlreadyExists, data.WarehouseCode)
+		return nil, err
+	}
+
+	data.DocNo, err = t.ID.GenerateID(ctx, tx, "StockOpname", data.Date)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
+	query := `INSERT INTO tblstockopnamehdr (
+			DocNo,
+			DocDt,
+			WhsCode,
+			ItCtCode,
+			Bin,
+			Status,
+			Remark,
+			CreateBy,
+			CreateDt
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	if _, err = tx.ExecContext(ctx, query, data.DocNo, data.Date, data.WarehouseCode, data.ItemCategoryCode, data.Bin, stockopname.Open, data.Remark, data.CreateBy, data.CreateDate); err != nil {
+		log.Printf("Error insert stock opname header: %+v", err)
+		return nil, fmt.Errorf("error Insert Header: %w", err)
+	}
+
+	// snapshot saldo per source, disimpan agar selisih kurang bisa diambil
+	// dari source yang sama saat approve
+	filters := []string{"s.WhsCode = ?"}
+	args := []interface{}{data.WarehouseCode}
+	if data.ItemCategoryCode.Valid {
+		filters = append(filters, "i.ItCtCode = ?")
+		args = append(args, data.ItemCategoryCode.String)
+	}
+	if data.Bin.Valid {
+		filters = append(filters, "s.Bin = ?")
+		args = append(args, data.Bin.String)
+	}
+
+	var snapshot []stockopname.Snapshot
+	query = `SELECT s.ItCode, s.BatchNo, s.Source, SUM(s.Qty + s.Qty2 - s.Qty3) AS Qty
+		FROM tblstocksummary s
+		JOIN tblitem i ON s.ItCode = i.ItCode
+		WHERE ` + strings.Join(filters, " AND ") + `
+		GROUP BY s.ItCode, s.BatchNo, s.Source
+		HAVING SUM(s.Qty + s.Qty2 - s.Qty3) > 0
+		ORDER BY s.ItCode, s.BatchNo, s.Source`
+	if err = tx.SelectContext(ctx, &snapshot, query, args...); err != nil {
+		return nil, fmt.Errorf("error snapshot stock summary: %w", err)
+	}
+
+	if len(snapshot) > 0 {
+		var placeholders []string
+		args = args[:0]
+		for i, s := range snapshot {
+			placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?)")
+			args = append(args, data.DocNo, fmt.Sprintf("%05d", i+1), s.ItCode, s.BatchNo, s.Source, s.Qty, data.CreateBy, data.CreateDate)
+		}
+
+		query = `INSERT INTO tblstockopnamedtl (
+				DocNo,
+				DNo,
+				ItCode,
+				BatchNo,
+				Source,
+				Qty,
+				CreateBy,
+				CreateDt
+			) VALUES ` + strings.Join(placeholders, ",")
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+			log.Printf("Error insert stock opname snapshot: %+v", err)
+			return nil, fmt.Errorf("error Insert Detail: %w", err)
+		}
+	}
+	data.TotalItems = len(snapshot)
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return data, nil
+}
+
+// Count menyimpan hasil hitung satu round. Header dikunci selama insert supaya
+// hitungan tidak masuk setelah opname di-approve atau di-cancel.
+func (t *StockOpnameRepository) Count(ctx context.Context, data *stockopname.Count) (*stockopname.Count, error) {
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	var header *opnameHeader
+	if header, err = t.openHeader(ctx, tx, data.DocNo, true); err != nil {
+		return nil, err
+	}
+	if err = checkWarehouse(ctx, header.WhsCode); err != nil {
+		return nil, err
+	}
+
+	var placeholders []string
+	var args []interface{}
+	for _, d := range data.Details {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?)")
+		args = append(args, data.DocNo, data.Round, d.ItemCode, d.Batch, d.Qty, data.CreateBy, data.CreateDate)
+	}
+
+	query := `INSERT INTO tblstockopnamecount (
+			DocNo,
+			Round,
+			ItCode,
+			BatchNo,
+			QtyCount,
+			CreateBy,
+			CreateDt
+		) VALUES ` + strings.Join(placeholders, ",") + `
+		ON DUPLICATE KEY UPDATE
+			QtyCount = VALUES(QtyCount),
+			CreateBy = VALUES(CreateBy),
+			CreateDt = VALUES(CreateDt)`
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		log.Printf("Error insert stock opname count: %+v", err)
+		return nil, fmt.Errorf("error Insert Count: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return data, nil
+}
+
+type opnameHeader struct {
+	DocNo   string                    `db:"DocNo"`
+	WhsCode string                    `db:"WhsCode"`
+	Bin     nulldatatype.NullDataType `db:"Bin"`
+	Status  string                    `db:"Status"`
+}
+
+// openHeader mengambil header opname dan menolak jika statusnya bukan Open.
+func (t *StockOpnameRepository) openHeader(ctx context.Context, q sqlx.QueryerContext, docNo string, lock bool) (*opnameHeader, error) {
+	query := "SELECT DocNo, WhsCode, Bin, Status FROM tblstockopnamehdr WHERE DocNo = ?"
+	if lock {
+		query += " FOR UPDATE"
+	}
+
+	var header opnameHeader
+	if err := sqlx.GetContext(ctx, q, &header, query, docNo); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrDataNotFound
+		}
+		return nil, fmt.Errorf("error get stock opname: %w", err)
+	}
+	if header.Status != stockopname.Open {
+		return nil, fmt.Errorf("%w: stock opname %s is not open", customerrors.ErrInvalidInput, docNo)
+	}
+
+	return &header, nil
+}
+
+// Approve menutup opname lalu membuat stock adjustment atas selisih hitung round
+// terakhir. Selisih kurang diambil dari source snapshot berurutan, selisih lebih
+// masuk sebagai source baru milik adjustment. Opname per bin meng-adjust bin
+// tersebut. Keduanya dalam satu transaksi.
+func (t *StockOpnameRepository) Approve(ctx context.Context, docNo, userName, date string) (*stockopname.Detail, error) {
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	var header *opnameHeader
+	if header, err = t.openHeader(ctx, tx, docNo, true); err != nil {
+		return nil, err
+	}
+	if err = checkWarehouse(ctx, header.WhsCode); err != nil {
+		return nil, err
+	}
+
+	var snapshot []stockopname.Snapshot
+	if err = tx.SelectContext(ctx, &snapshot, "SELECT DNo, ItCode, BatchNo, Source, Qty FROM tblstockopnamedtl WHERE DocNo = ? ORDER BY DNo", docNo); err != nil {
+		return nil, fmt.Errorf("error get stock opname snapshot: %w", err)
+	}
+
+	var counts []struct {
+		ItCode   string  `db:"ItCode"`
+		BatchNo  string  `db:"BatchNo"`
+		QtyCount float32 `db:"QtyCount"`
+	}
+	query := `SELECT c.ItCode, c.BatchNo, c.QtyCount
+		FROM tblstockopnamecount c
+		WHERE c.DocNo = ?
+		AND c.Round = (
+			SELECT MAX(c2.Round) FROM tblstockopnamecount c2
+			WHERE c2.DocNo = c.DocNo AND c2.ItCode = c.ItCode AND c2.BatchNo = c.BatchNo
+		)
+		ORDER BY c.ItCode, c.BatchNo`
+	if err = tx.SelectContext(ctx, &counts, query, docNo); err != nil {
+		return nil, fmt.Errorf("error get stock opname count: %w", err)
+	}
+
+	// status ditutup dulu supaya posting adjustment di bawah tidak ditolak CheckFrozen
+	query = "UPDATE tblstockopnamehdr SET Status = ?, LastUpBy = ?, LastUpDt = ? WHERE DocNo = ?"
+	if _, err = tx.ExecContext(ctx, query, stockopname.Approved, userName, date, docNo); err != nil {
+		return nil, fmt.Errorf("error approve stock opname: %w", err)
+	}
+
+	sources := make(map[string][]stockopname.Snapshot)
+	for _, s := range snapshot {
+		key := s.ItCode + "*" + s.BatchNo
+		sources[key] = append(sources[key], s)
+	}
+
+	var details []tblstockadjustmentdtl.Create
+	for _, c := range counts {
+		var system float32
+		for _, s := range sources[c.ItCode+"*"+c.BatchNo] {
+			system += s.Qty
+		}
+
+		switch variance := c.QtyCount - system; {
+		case variance > 0:
+			details = append(details, tblstockadjustmentdtl.Create{
+				ItemCode:    c.ItCode,
+				Batch:       c.BatchNo,
+				Bin:         header.Bin.String,
+				StockSystem: system,
+				StockActual: c.QtyCount,
+			})
+		case variance < 0:
+			shortage := -variance
+			for _, s := range sources[c.ItCode+"*"+c.BatchNo] {
+				if shortage <= 0 {
+					break
+				}
+				take := min(s.Qty, shortage)
+				shortage -= take
+				details = append(details, tblstockadjustmentdtl.Create{
+					ItemCode:    c.ItCode,
+					Batch:       c.BatchNo,
+					Bin:         header.Bin.String,
+					StockSystem: s.Qty,
+					StockActual: s.Qty - take,
+					Source:      s.Source,
+				})
+			}
+		}
+	}
+
+	if len(details) > 0 {
+		for i := range details {
+			details[i].DNo = fmt.Sprintf("%03d", i+1)
+		}
+
+		adjustment := &tblstockadjustmenthdr.Create{
+			Date:          date[:8],
+			WarehouseCode: header.WhsCode,
+			Remark:        nulldatatype.NewNullStringDataType("Stock Opname " + docNo),
+			Details:       details,
+			CreateBy:      userName,
+			CreateDate:    date,
+		}
+		if _, err = t.Adjust.CreateTx(ctx, tx, adjustment); err != nil {
+			return nil, err
+		}
+
+		if _, err = tx.ExecContext(ctx, "UPDATE tblstockopnamehdr SET AdjDocNo = ? WHERE DocNo = ?", adjustment.DocNo, docNo); err != nil {
+			return nil, fmt.Errorf("error update stock opname adjustment: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return t.Detail(ctx, docNo)
+}
+
+// Cancel membatalkan opname yang masih terbuka tanpa adjustment, gudangnya
+// bisa menerima posting lagi.
+func (t *StockOpnameRepository) Cancel(ctx context.Context, docNo, userName, date string) error {
+	header, err := t.openHeader(ctx, t.DB.DB, docNo, false)
+	if err != nil {
+		return err
+	}
+	if err := checkWarehouse(ctx, header.WhsCode); err != nil {
+		return err
+	}
+
+	query := "UPDATE tblstockopnamehdr SET Status = ?, LastUpBy = ?, LastUpDt = ? WHERE DocNo = ? AND Status = ?"
+	if _, err := t.DB.ExecContext(ctx, query, stockopname.Cancelled, userName, date, docNo, stockopname.Open); err != nil {
+		return fmt.Errorf("error cancel stock opname: %w", err)
+	}
+
+	return nil
+}
+
+// CheckFrozen menolak movement ke gudang yang sedang opname. Opname yang dibatasi
+// item category hanya membekukan item dalam category tersebut, opname per bin
+// hanya membekukan bin tersebut. Bin movement harus sudah final (barang keluar
+// sudah dibagi ke bin asalnya), bin kosong dianggap bin unassigned.
+func (t *StockOpnameRepository) CheckFrozen(ctx context.Context, tx *sqlx.Tx, movements []inventoryledger.Movement) error {
+	if len(movements) == 0 {
+		return nil
+	}
+
+	var conds []string
+	var args []interface{}
+	args = append(args, stockopname.Open)
+	seen := make(map[[3]string]struct{})
+	for _, m := range movements {
+		key := [3]string{m.WhsCode, m.ItCode, binOrUnassigned(m.Bin)}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		conds = append(conds, "(h.WhsCode = ? AND i.ItCode = ? AND (h.Bin IS NULL OR h.Bin = ?))")
+		args = append(args, key[0], key[1], key[2])
+	}
+
+	var frozen []struct {
+		DocNo   string `db:"DocNo"`
+		WhsCode string `db:"WhsCode"`
+	}
+	query := `SELECT h.DocNo, h.WhsCode
+		FROM tblstockopnamehdr h
+		JOIN tblitem i ON h.ItCtCode IS NULL OR i.ItCtCode = h.ItCtCode
+		WHERE h.Status = ?
+		AND (` + strings.Join(conds, " OR ") + `)
+		LIMIT 1`
+	if err := tx.SelectContext(ctx, &frozen, query, args...); err != nil {
+		return fmt.Errorf("error check stock opname: %w", err)
+	}
+	if len(frozen) > 0 {
+		return fmt.Errorf("%w: %s (%s)", customerrors.ErrWarehouseFrozen, frozen[0].WhsCode, frozen[0].DocNo)
+	}
+
+	return nil
+}
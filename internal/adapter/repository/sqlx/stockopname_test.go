@@ -0,0 +1,204 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
+	"gitlab.com/ayaka/internal/domain/stockopname"
+	"gitlab.com/ayaka/internal/domain/tblstockadjustmenthdr"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+const (
+	queryOpnameFrozen   = "SELECT h.DocNo, h.WhsCode FROM tblstockopnamehdr h JOIN tblitem i ON h.ItCtCode IS NULL OR i.ItCtCode = h.ItCtCode WHERE h.Status = ? AND ((h.WhsCode = ? AND i.ItCode = ? AND (h.Bin IS NULL OR h.Bin = ?))) LIMIT 1"
+	queryOpnameHeader   = "SELECT DocNo, WhsCode, Bin, Status FROM tblstockopnamehdr WHERE DocNo = ? FOR UPDATE"
+	queryOpnameSnapshot = "SELECT DNo, ItCode, BatchNo, Source, Qty FROM tblstockopnamedtl WHERE DocNo = ? ORDER BY DNo"
+	queryOpnameCount    = "SELECT c.ItCode, c.BatchNo, c.QtyCount FROM tblstockopnamecount c WHERE c.DocNo = ? AND c.Round = ( SELECT MAX(c2.Round) FROM tblstockopnamecount c2 WHERE c2.DocNo = c.DocNo AND c2.ItCode = c.ItCode AND c2.BatchNo = c.BatchNo ) ORDER BY c.ItCode, c.BatchNo"
+	queryOpnameApprove  = "UPDATE tblstockopnamehdr SET Status = ?, LastUpBy = ?, LastUpDt = ? WHERE DocNo = ?"
+	queryOpnameLines    = "SELECT d.ItCode, i.ItName, d.BatchNo, u.UomName, SUM(d.Qty) AS Qty FROM tblstockopnamedtl d JOIN tblitem i ON d.ItCode = i.ItCode JOIN tbluom u ON i.PurchaseUOMCode = u.UomCode WHERE d.DocNo = ? GROUP BY d.ItCode, i.ItName, d.BatchNo, u.UomName ORDER BY d.ItCode, d.BatchNo"
+	queryOpnameCounted  = "SELECT c.ItCode, i.ItName, c.BatchNo, u.UomName, c.Round, c.QtyCount FROM tblstockopnamecount c JOIN tblitem i ON c.ItCode = i.ItCode JOIN tbluom u ON i.PurchaseUOMCode = u.UomCode WHERE c.DocNo = ? AND c.Round = ( SELECT MAX(c2.Round) FROM tblstockopnamecount c2 WHERE c2.DocNo = c.DocNo AND c2.ItCode = c.ItCode AND c2.BatchNo = c.BatchNo ) ORDER BY c.ItCode, c.BatchNo"
+)
+
+// fakeStockAdjust menangkap adjustment yang dibuat approval opname
+type fakeStockAdjust struct {
+	tblstockadjustmenthdr.Repository
+	created *tblstockadjustmenthdr.Create
+}
+
+func (f *fakeStockAdjust) CreateTx(ctx context.Context, tx *sqlx.Tx, data *tblstockadjustmenthdr.Create) (*tblstockadjustmenthdr.Create, error) {
+	data.DocNo = "0001/SA/01/26"
+	f.created = data
+	return data, nil
+}
+
+type StockOpnameRepositorySuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	repo    *StockOpnameRepository
+	adjust  *fakeStockAdjust
+	db      *sqlx.DB
+}
+
+func (suite *StockOpnameRepositorySuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.adjust = &fakeStockAdjust{}
+	suite.repo = &StockOpnameRepository{
+		DB:     &repository.Sqlx{DB: suite.db},
+		Adjust: suite.adjust,
+	}
+}
+
+func (suite *StockOpnameRepositorySuite) TearDownTest() {
+	suite.db.Close()
+}
+
+// bin movement ikut dicek, opname per bin hanya membekukan bin tersebut
+func (suite *StockOpnameRepositorySuite) TestCheckFrozen_OpenOpnameRejectsPosting() {
+	m := movement(inventoryledger.In)
+	m.Bin = "A-01"
+
+	suite.mockSQL.ExpectBegin()
+	tx, err := suite.db.Beginx()
+	suite.Require().NoError(err)
+
+	suite.mockSQL.ExpectQuery(queryOpnameFrozen).
+		WithArgs(stockopname.Open, m.WhsCode, m.ItCode, "A-01").
+		WillReturnRows(sqlmock.NewRows([]string{"DocNo", "WhsCode"}).AddRow("0001/OPN/01/26", m.WhsCode))
+
+	err = suite.repo.CheckFrozen(context.Background(), tx, []inventoryledger.Movement{m})
+
+	suite.ErrorIs(err, customerrors.ErrWarehouseFrozen)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// sistem 7 (source A 3, source B 4), dihitung 2: kurang 5 diambil A habis lalu B 2
+func (suite *StockOpnameRepositorySuite) TestApprove_ShortageSpreadOverSources() {
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectQuery(queryOpnameHeader).
+		WithArgs("0001/OPN/01/26").
+		WillReturnRows(sqlmock.NewRows([]string{"DocNo", "WhsCode", "Bin", "Status"}).AddRow("0001/OPN/01/26", "WHS01", nil, stockopname.Open))
+	suite.mockSQL.ExpectQuery(queryOpnameSnapshot).
+		WithArgs("0001/OPN/01/26").
+		WillReturnRows(sqlmock.NewRows([]string{"DNo", "ItCode", "BatchNo", "Source", "Qty"}).
+			AddRow("00001", "IT001", "B1", "SRC-A", 3).
+			AddRow("00002", "IT001", "B1", "SRC-B", 4))
+	suite.mockSQL.ExpectQuery(queryOpnameCount).
+		WithArgs("0001/OPN/01/26").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "BatchNo", "QtyCount"}).AddRow("IT001", "B1", 2))
+	suite.mockSQL.ExpectExec(queryOpnameApprove).
+		WithArgs(stockopname.Approved, "admin", "202601310900", "0001/OPN/01/26").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec("UPDATE tblstockopnamehdr SET AdjDocNo = ? WHERE DocNo = ?").
+		WithArgs("0001/SA/01/26", "0001/OPN/01/26").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectCommit()
+	suite.mockSQL.ExpectQuery(stockOpnameSelect + " WHERE h.DocNo = ?").
+		WithArgs("0001/OPN/01/26").
+		WillReturnRows(sqlmock.NewRows([]string{"DocNo", "DocDt", "WhsCode", "WhsName", "ItCtCode", "ItCtName", "Bin", "Status", "AdjDocNo", "Remark", "CreateBy"}).
+			AddRow("0001/OPN/01/26", "20260130", "WHS01", "Gudang 1", nil, nil, nil, stockopname.Approved, "0001/SA/01/26", nil, "admin"))
+	suite.mockSQL.ExpectQuery(queryOpnameLines).
+		WithArgs("0001/OPN/01/26").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "ItName", "BatchNo", "UomName", "Qty"}).AddRow("IT001", "Item 1", "B1", "PCS", 7))
+	suite.mockSQL.ExpectQuery(queryOpnameCounted).
+		WithArgs("0001/OPN/01/26").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "ItName", "BatchNo", "UomName", "Round", "QtyCount"}).AddRow("IT001", "Item 1", "B1", "PCS", 2, 2))
+
+	result, err := suite.repo.Approve(context.Background(), "0001/OPN/01/26", "admin", "202601310900")
+
+	suite.Require().NoError(err)
+	suite.Equal(float32(-5), result.Lines[0].Variance)
+	suite.Require().NotNil(suite.adjust.created)
+	suite.Equal("20260131", suite.adjust.created.Date)
+	suite.Require().Len(suite.adjust.created.Details, 2)
+	suite.Equal("SRC-A", suite.adjust.created.Details[0].Source)
+	suite.Equal(float32(0), suite.adjust.created.Details[0].StockActual)
+	suite.Equal("SRC-B", suite.adjust.created.Details[1].Source)
+	suite.Equal(float32(2), suite.adjust.created.Details[1].StockActual)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// opname per bin: selisih lebih masuk ke bin yang dihitung, bukan bin unassigned
+func (suite *StockOpnameRepositorySuite) TestApprove_SurplusKeepsOpnameBin() {
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectQuery(queryOpnameHeader).
+		WithArgs("0001/OPN/01/26").
+		WillReturnRows(sqlmock.NewRows([]string{"DocNo", "WhsCode", "Bin", "Status"}).AddRow("0001/OPN/01/26", "WHS01", "A-01", stockopname.Open))
+	suite.mockSQL.ExpectQuery(queryOpnameSnapshot).
+		WithArgs("0001/OPN/01/26").
+		WillReturnRows(sqlmock.NewRows([]string{"DNo", "ItCode", "BatchNo", "Source", "Qty"}).AddRow("00001", "IT001", "B1", "SRC-A", 3))
+	suite.mockSQL.ExpectQuery(queryOpnameCount).
+		WithArgs("0001/OPN/01/26").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "BatchNo", "QtyCount"}).AddRow("IT001", "B1", 5))
+	suite.mockSQL.ExpectExec(queryOpnameApprove).
+		WithArgs(stockopname.Approved, "admin", "202601310900", "0001/OPN/01/26").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec("UPDATE tblstockopnamehdr SET AdjDocNo = ? WHERE DocNo = ?").
+		WithArgs("0001/SA/01/26", "0001/OPN/01/26").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectCommit()
+	suite.mockSQL.ExpectQuery(stockOpnameSelect + " WHERE h.DocNo = ?").
+		WithArgs("0001/OPN/01/26").
+		WillReturnRows(sqlmock.NewRows([]string{"DocNo", "DocDt", "WhsCode", "WhsName", "ItCtCode", "ItCtName", "Bin", "Status", "AdjDocNo", "Remark", "CreateBy"}).
+			AddRow("0001/OPN/01/26", "20260130", "WHS01", "Gudang 1", nil, nil, "A-01", stockopname.Approved, "0001/SA/01/26", nil, "admin"))
+	suite.mockSQL.ExpectQuery(queryOpnameLines).
+		WithArgs("0001/OPN/01/26").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "ItName", "BatchNo", "UomName", "Qty"}).AddRow("IT001", "Item 1", "B1", "PCS", 3))
+	suite.mockSQL.ExpectQuery(queryOpnameCounted).
+		WithArgs("0001/OPN/01/26").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "ItName", "BatchNo", "UomName", "Round", "QtyCount"}).AddRow("IT001", "Item 1", "B1", "PCS", 1, 5))
+
+	_, err := suite.repo.Approve(context.Background(), "0001/OPN/01/26", "admin", "202601310900")
+
+	suite.Require().NoError(err)
+	suite.Require().NotNil(suite.adjust.created)
+	suite.Require().Len(suite.adjust.created.Details, 1)
+	suite.Equal("A-01", suite.adjust.created.Details[0].Bin)
+	suite.Equal("", suite.adjust.created.Details[0].Source)
+	suite.Equal(float32(5), suite.adjust.created.Details[0].StockActual)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func (suite *StockOpnameRepositorySuite) TestApprove_NotOpen() {
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectQuery(queryOpnameHeader).
+		WithArgs("0001/OPN/01/26").
+		WillReturnRows(sqlmock.NewRows([]string{"DocNo", "WhsCode", "Bin", "Status"}).AddRow("0001/OPN/01/26", "WHS01", nil, stockopname.Approved))
+	suite.mockSQL.ExpectRollback()
+
+	_, err := suite.repo.Approve(context.Background(), "0001/OPN/01/26", "admin", "202601310900")
+
+	suite.ErrorIs(err, customerrors.ErrInvalidInput)
+	suite.Nil(suite.adjust.created)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// hitungan ditolak kalau opname sudah tidak Open saat header dikunci
+func (suite *StockOpnameRepositorySuite) TestCount_NotOpen() {
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectQuery(queryOpnameHeader).
+		WithArgs("0001/OPN/01/26").
+		WillReturnRows(sqlmock.NewRows([]string{"DocNo", "WhsCode", "Bin", "Status"}).AddRow("0001/OPN/01/26", "WHS01", nil, stockopname.Approved))
+	suite.mockSQL.ExpectRollback()
+
+	_, err := suite.repo.Count(context.Background(), &stockopname.Count{
+		DocNo:   "0001/OPN/01/26",
+		Round:   1,
+		Details: []stockopname.CountDetail{{ItemCode: "IT001", Batch: "B1", Qty: 2}},
+	})
+
+	suite.ErrorIs(err, customerrors.ErrInvalidInput)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func TestStockOpnameRepositorySuite(t *testing.T) {
+	suite.Run(t, new(StockOpnameRepositorySuite))
+}
@@ -0,0 +1,545 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
+	"gitlab.com/ayaka/internal/domain/stockvaluation"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+// StockValuationRepository menghitung nilai persediaan dari movement ledger.
+// Setiap barang masuk menjadi cost layer per gudang, item dan batch; barang keluar
+// menghabiskan layer tertua lebih dulu. Untuk item category dengan CostMethod 'A'
+// semua layer item di gudang yang sama selalu bernilai harga rata-rata, sehingga
+// hasilnya moving average. Perubahan nilai dicatat di tblstockvaluation.
+//
+//	CREATE TABLE tblstockcostlayer (
+//		LayerId   BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+//		DocType   VARCHAR(50) NOT NULL,
+//		DocNo     VARCHAR(30) NOT NULL,
+//		DNo       VARCHAR(3) NOT NULL,
+//		DocDt     VARCHAR(8) NOT NULL,
+//		WhsCode   VARCHAR(16) NOT NULL,
+//		ItCode    VARCHAR(16) NOT NULL,
+//		BatchNo   VARCHAR(250) NOT NULL,
+//		Qty       DECIMAL(18,4) NOT NULL,
+//		RemainQty DECIMAL(18,4) NOT NULL,
+//		UnitCost  DECIMAL(18,4) NOT NULL,
+//		CreateBy VARCHAR(50), CreateDt VARCHAR(12),
+//		KEY (WhsCode, ItCode, BatchNo),
+//		KEY (DocType, DocNo, DNo)
+//	);
+//	CREATE TABLE tblstockvaluation (
+//		DocType VARCHAR(50) NOT NULL,
+//		DocNo   VARCHAR(30) NOT NULL,
+//		DNo     VARCHAR(3) NOT NULL,
+//		DocDt   VARCHAR(8) NOT NULL,
+//		WhsCode VARCHAR(16) NOT NULL,
+//		ItCode  VARCHAR(16) NOT NULL,
+//		BatchNo VARCHAR(250) NOT NULL,
+//		Qty     DECIMAL(18,4) NOT NULL,
+//		Value   DECIMAL(18,4) NOT NULL,
+//		CreateBy VARCHAR(50), CreateDt VARCHAR(12),
+//		KEY (WhsCode, ItCode, DocDt),
+//		KEY (DocType, DocNo, DNo)
+//	);
+//	ALTER TABLE tblitemcategory ADD COLUMN CostMethod CHAR(1) NOT NULL DEFAULT 'A';
+type StockValuationRepository struct {
+	DB *repository.Sqlx `inject:"database"`
+}
+
+type valuationLine struct {
+	DocNo  string
+	DNo    string
+	ItCode string
+}
+
+func (t *StockValuationRepository) Post(ctx context.Context, tx *sqlx.Tx, movements []inventoryledger.Movement) ([]stockvaluation.Entry, error) {
+	methods := make(map[string]string)
+	var entries []stockvaluation.Entry
+	movements = append([]inventoryledger.Movement(nil), movements...)
+
+	// barang keluar diproses dulu, harganya dipakai barang masuk pasangannya
+	// (material transfer -> receive, stock mutation from -> to)
+	outCost := make(map[valuationLine]float32)
+	outValue := make(map[string]float32)
+	for _, m := range movements {
+		if m.Direction != inventoryledger.Out || m.Qty <= 0 {
+			continue
+		}
+		value, err := consumeLayers(ctx, tx, m)
+		if err != nil {
+			return nil, err
+		}
+		outCost[valuationLine{m.DocNo, m.DNo, m.ItCode}] = value / m.Qty
+		outValue[m.DocNo] += value
+		entries = append(entries, valuationEntry(m, m.DocDt, -m.Qty, -value))
+	}
+
+	var unpriced []int
+	unpricedQty := make(map[string]float32)
+	for i, m := range movements {
+		if m.Direction == inventoryledger.Out || m.Qty <= 0 || m.UnitCost != 0 {
+			continue
+		}
+		if cost, ok := outCost[valuationLine{m.DocNo, m.DNo, m.ItCode}]; ok {
+			movements[i].UnitCost = cost
+			outValue[m.DocNo] -= cost * m.Qty
+			continue
+		}
+		unpriced = append(unpriced, i)
+		unpricedQty[m.DocNo] += m.Qty
+	}
+
+	// sisa nilai barang keluar dibagi rata ke barang masuk dokumen yang sama,
+	// selain itu barang masuk tanpa harga (adjustment) memakai harga rata-rata
+	for _, i := range unpriced {
+		m := movements[i]
+		if outValue[m.DocNo] > 0 {
+			movements[i].UnitCost = outValue[m.DocNo] / unpricedQty[m.DocNo]
+			continue
+		}
+		cost, err := averageCost(ctx, tx, m.WhsCode, m.ItCode)
+		if err != nil {
+			return nil, err
+		}
+		movements[i].UnitCost = cost
+	}
+
+	for _, m := range movements {
+		if m.Direction == inventoryledger.Out || m.Qty <= 0 {
+			continue
+		}
+		if err := addLayer(ctx, tx, methods, m, m.DocDt, m.UnitCost); err != nil {
+			return nil, err
+		}
+		entries = append(entries, valuationEntry(m, m.DocDt, m.Qty, m.Qty*m.UnitCost))
+	}
+
+	if err := insertValuation(ctx, tx, entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Reverse mengembalikan nilai movement yang dibatalkan. Barang keluar dikembalikan
+// sebagai layer baru dengan harga saat keluar, barang masuk menarik sisa layer-nya
+// (kekurangannya diambil dari layer lain item yang sama). Untuk moving average
+// barang masuk ditarik senilai yang di-posting di tblstockvaluation lalu sisa
+// layer dirata-rata ulang. Entry dicatat pada tanggal pembatalan sehingga
+// laporan per tanggal sebelumnya tidak berubah.
+func (t *StockValuationRepository) Reverse(ctx context.Context, tx *sqlx.Tx, movements []inventoryledger.Movement) ([]stockvaluation.Entry, error) {
+	methods := make(map[string]string)
+	var entries []stockvaluation.Entry
+
+	for _, m := range movements {
+		if m.Qty <= 0 {
+			continue
+		}
+		docDt := m.CreateDt
+		if len(docDt) > 8 {
+			docDt = docDt[:8]
+		}
+
+		if m.Direction == inventoryledger.Out {
+			cost, err := postedCost(ctx, tx, m)
+			if err != nil {
+				return nil, err
+			}
+			if err := addLayer(ctx, tx, methods, m, docDt, cost); err != nil {
+				return nil, err
+			}
+			entries = append(entries, valuationEntry(m, docDt, m.Qty, m.Qty*cost))
+			continue
+		}
+
+		method, err := costMethod(ctx, tx, methods, m.ItCode)
+		if err != nil {
+			return nil, err
+		}
+		var posted, balanceQty, balanceValue float32
+		if method == stockvaluation.MovingAverage {
+			cost, err := postedCost(ctx, tx, m)
+			if err != nil {
+				return nil, err
+			}
+			posted = m.Qty * cost
+			if balanceQty, balanceValue, err = layerBalance(ctx, tx, m.WhsCode, m.ItCode); err != nil {
+				return nil, err
+			}
+		}
+
+		var layers []stockvaluation.Layer
+		query := `SELECT LayerId, RemainQty, UnitCost
+			FROM tblstockcostlayer
+			WHERE DocType = ? AND DocNo = ? AND DNo = ? AND WhsCode = ? AND ItCode = ? AND BatchNo = ? AND RemainQty > 0
+			ORDER BY LayerId
+			FOR UPDATE`
+		if err := tx.SelectContext(ctx, &layers, query, m.DocType, m.DocNo, m.DNo, m.WhsCode, m.ItCode, m.BatchNo); err != nil {
+			log.Printf("Error lock cost layer: %+v", err)
+			return nil, fmt.Errorf("error Lock Cost Layer: %w", err)
+		}
+
+		value, remaining, err := drawLayers(ctx, tx, layers, m.Qty)
+		if err != nil {
+			return nil, err
+		}
+		if remaining > 0 {
+			rest := m
+			rest.Qty = remaining
+			restValue, err := consumeLayers(ctx, tx, rest)
+			if err != nil {
+				return nil, err
+			}
+			value += restValue
+		}
+
+		if method == stockvaluation.MovingAverage {
+			value = posted
+			if balanceQty-m.Qty > 0 {
+				// nilai sisa tidak boleh minus kalau harga sudah turun jauh
+				average := max(balanceValue-posted, 0) / (balanceQty - m.Qty)
+				if err := setAverageCost(ctx, tx, m.WhsCode, m.ItCode, average); err != nil {
+					return nil, err
+				}
+			}
+		}
+		entries = append(entries, valuationEntry(m, docDt, -m.Qty, -value))
+	}
+
+	if err := insertValuation(ctx, tx, entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// consumeLayers mengambil qty dari layer gudang / item / batch, yang tertua lebih dulu.
+// Stok yang belum punya layer (sebelum valuasi berjalan) dinilai dengan harga rata-rata.
+func consumeLayers(ctx context.Context, tx *sqlx.Tx, m inventoryledger.Movement) (float32, error) {
+	var layers []stockvaluation.Layer
+	query := `SELECT LayerId, RemainQty, UnitCost
+		FROM tblstockcostlayer
+		WHERE WhsCode = ? AND ItCode = ? AND BatchNo = ? AND RemainQty > 0
+		ORDER BY DocDt, LayerId
+		FOR UPDATE`
+	if err := tx.SelectContext(ctx, &layers, query, m.WhsCode, m.ItCode, m.BatchNo); err != nil {
+		log.Printf("Error lock cost layer: %+v", err)
+		return 0, fmt.Errorf("error Lock Cost Layer: %w", err)
+	}
+
+	value, remaining, err := drawLayers(ctx, tx, layers, m.Qty)
+	if err != nil {
+		return 0, err
+	}
+	if remaining > 0 {
+		cost, err := averageCost(ctx, tx, m.WhsCode, m.ItCode)
+		if err != nil {
+			return 0, err
+		}
+		value += remaining * cost
+	}
+
+	return value, nil
+}
+
+func drawLayers(ctx context.Context, tx *sqlx.Tx, layers []stockvaluation.Layer, qty float32) (value, remaining float32, err error) {
+	remaining = qty
+	query := `UPDATE tblstockcostlayer SET RemainQty = RemainQty - ? WHERE LayerId = ?`
+	for _, layer := range layers {
+		if remaining <= 0 {
+			break
+		}
+		take := min(remaining, layer.RemainQty)
+		if _, err := tx.ExecContext(ctx, query, take, layer.LayerID); err != nil {
+			log.Printf("Error update cost layer: %+v", err)
+			return 0, 0, fmt.Errorf("error Update Cost Layer: %w", err)
+		}
+		value += take * layer.UnitCost
+		remaining -= take
+	}
+
+	return value, remaining, nil
+}
+
+// addLayer menambah layer barang masuk. Untuk moving average seluruh layer item
+// di gudang tersebut di-update ke harga rata-rata yang baru.
+func addLayer(ctx context.Context, tx *sqlx.Tx, methods map[string]string, m inventoryledger.Movement, docDt string, cost float32) error {
+	method, err := costMethod(ctx, tx, methods, m.ItCode)
+	if err != nil {
+		return err
+	}
+
+	layerCost := cost
+	if method == stockvaluation.MovingAverage {
+		qty, value, err := layerBalance(ctx, tx, m.WhsCode, m.ItCode)
+		if err != nil {
+			return err
+		}
+		if qty+m.Qty > 0 {
+			layerCost = (value + m.Qty*cost) / (qty + m.Qty)
+		}
+	}
+
+	query := `INSERT INTO tblstockcostlayer (
+			DocType,
+			DocNo,
+			DNo,
+			DocDt,
+			WhsCode,
+			ItCode,
+			BatchNo,
+			Qty,
+			RemainQty,
+			UnitCost,
+			CreateBy,
+			CreateDt
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, query, m.DocType, m.DocNo, m.DNo, docDt, m.WhsCode, m.ItCode, m.BatchNo, m.Qty, m.Qty, layerCost, m.CreateBy, m.CreateDt); err != nil {
+		log.Printf("Error insert cost layer: %+v", err)
+		return fmt.Errorf("error Insert Cost Layer: %w", err)
+	}
+
+	if method == stockvaluation.MovingAverage {
+		return setAverageCost(ctx, tx, m.WhsCode, m.ItCode, layerCost)
+	}
+
+	return nil
+}
+
+// setAverageCost menyamakan harga semua sisa layer item di gudang (moving average)
+func setAverageCost(ctx context.Context, tx *sqlx.Tx, whsCode, itCode string, cost float32) error {
+	query := `UPDATE tblstockcostlayer SET UnitCost = ? WHERE WhsCode = ? AND ItCode = ? AND RemainQty > 0`
+	if _, err := tx.ExecContext(ctx, query, cost, whsCode, itCode); err != nil {
+		log.Printf("Error update average cost: %+v", err)
+		return fmt.Errorf("error Update Average Cost: %w", err)
+	}
+	return nil
+}
+
+// postedCost harga satuan movement seperti yang dicatat di tblstockvaluation saat posting
+func postedCost(ctx context.Context, tx *sqlx.Tx, m inventoryledger.Movement) (float32, error) {
+	var posted struct {
+		Qty   float32 `db:"Qty"`
+		Value float32 `db:"Value"`
+	}
+	query := `SELECT COALESCE(SUM(Qty), 0) AS Qty, COALESCE(SUM(Value), 0) AS Value
+		FROM tblstockvaluation
+		WHERE DocType = ? AND DocNo = ? AND DNo = ? AND WhsCode = ? AND ItCode = ? AND BatchNo = ?`
+	if err := tx.GetContext(ctx, &posted, query, m.DocType, m.DocNo, m.DNo, m.WhsCode, m.ItCode, m.BatchNo); err != nil {
+		log.Printf("Error get posted valuation: %+v", err)
+		return 0, fmt.Errorf("error Get Stock Valuation: %w", err)
+	}
+
+	if posted.Qty == 0 {
+		return 0, nil
+	}
+	return posted.Value / posted.Qty, nil
+}
+
+func costMethod(ctx context.Context, tx *sqlx.Tx, methods map[string]string, itCode string) (string, error) {
+	if method, ok := methods[itCode]; ok {
+		return method, nil
+	}
+
+	method := stockvaluation.MovingAverage
+	query := `SELECT COALESCE(c.CostMethod, 'A')
+		FROM tblitem i
+		LEFT JOIN tblitemcategory c ON i.ItCtCode = c.ItCtCode
+		WHERE i.ItCode = ?`
+	if err := tx.GetContext(ctx, &method, query, itCode); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Error get cost method: %+v", err)
+		return "", fmt.Errorf("error Get Cost Method: %w", err)
+	}
+
+	methods[itCode] = method
+	return method, nil
+}
+
+// layerBalance mengunci dan menjumlahkan sisa layer item di satu gudang (semua batch).
+func layerBalance(ctx context.Context, tx *sqlx.Tx, whsCode, itCode string) (qty, value float32, err error) {
+	var balance struct {
+		Qty   float32 `db:"Qty"`
+		Value float32 `db:"Value"`
+	}
+	query := `SELECT COALESCE(SUM(RemainQty), 0) AS Qty, COALESCE(SUM(RemainQty * UnitCost), 0) AS Value
+		FROM tblstockcostlayer
+		WHERE WhsCode = ? AND ItCode = ? AND RemainQty > 0
+		FOR UPDATE`
+	if err := tx.GetContext(ctx, &balance, query, whsCode, itCode); err != nil {
+		log.Printf("Error get cost layer balance: %+v", err)
+		return 0, 0, fmt.Errorf("error Get Cost Layer Balance: %w", err)
+	}
+
+	return balance.Qty, balance.Value, nil
+}
+
+func averageCost(ctx context.Context, tx *sqlx.Tx, whsCode, itCode string) (float32, error) {
+	qty, value, err := layerBalance(ctx, tx, whsCode, itCode)
+	if err != nil || qty <= 0 {
+		return 0, err
+	}
+	return value / qty, nil
+}
+
+func valuationEntry(m inventoryledger.Movement, docDt string, qty, value float32) stockvaluation.Entry {
+	return stockvaluation.Entry{
+		DocType:  m.DocType,
+		DocNo:    m.DocNo,
+		DNo:      m.DNo,
+		DocDt:    docDt,
+		WhsCode:  m.WhsCode,
+		ItCode:   m.ItCode,
+		BatchNo:  m.BatchNo,
+		Qty:      qty,
+		Value:    value,
+		CreateBy: m.CreateBy,
+		CreateDt: m.CreateDt,
+	}
+}
+
+func insertValuation(ctx context.Context, tx *sqlx.Tx, entries []stockvaluation.Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var placeholders []string
+	var args []interface{}
+	for _, e := range entries {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		args = append(args,
+			e.DocType,
+			e.DocNo,
+			e.DNo,
+			e.DocDt,
+			e.WhsCode,
+			e.ItCode,
+			e.BatchNo,
+			e.Qty,
+			e.Value,
+			e.CreateBy,
+			e.CreateDt,
+		)
+	}
+
+	query := `INSERT INTO tblstockvaluation (
+			DocType,
+			DocNo,
+			DNo,
+			DocDt,
+			WhsCode,
+			ItCode,
+			BatchNo,
+			Qty,
+			Value,
+			CreateBy,
+			CreateDt
+		) VALUES ` + strings.Join(placeholders, ",") + ";"
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		log.Printf("Error insert stock valuation: %+v", err)
+		return fmt.Errorf("error Insert Stock Valuation: %w", err)
+	}
+
+	return nil
+}
+
+func (t *StockValuationRepository) Fetch(ctx context.Context, date, warehouse, itemCatCode, itemName string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	var totalRecords int
+	var args []interface{}
+
+	where := []string{"v.DocDt <= ?"}
+	args = append(args, date)
+	if warehouse != "" {
+		where = append(where, "v.WhsCode = ?")
+		args = append(args, warehouse)
+	}
+	if itemCatCode != "" {
+		where = append(where, "i.ItCtCode = ?")
+		args = append(args, itemCatCode)
+	}
+	if itemName != "" {
+		where = append(where, "(i.ItCode LIKE ? OR i.ItName LIKE ?)")
+		args = append(args, "%"+itemName+"%", "%"+itemName+"%")
+	}
+	if scope, scopeArgs := warehouseScope(ctx, "v.WhsCode"); scope != "" {
+		where = append(where, scope)
+		args = append(args, scopeArgs...)
+	}
+
+	countQuery := `SELECT COUNT(*) FROM (
+			SELECT 1
+			FROM tblstockvaluation v
+			JOIN tblitem i ON v.ItCode = i.ItCode
+			WHERE ` + strings.Join(where, " AND ") + `
+			GROUP BY v.WhsCode, v.ItCode
+			HAVING SUM(v.Qty) != 0 OR SUM(v.Value) != 0
+		) AS grouped`
+	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+
+	var totalPages, offset int
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+	}
+
+	data := make([]*stockvaluation.Read, 0)
+	query := `SELECT
+			v.WhsCode,
+			w.WhsName,
+			v.ItCode,
+			i.ItName,
+			COALESCE(c.ItCtName, '') AS ItCtName,
+			COALESCE(c.CostMethod, 'A') AS CostMethod,
+			COALESCE(u.UomName, '') AS UomName,
+			SUM(v.Qty) AS Qty,
+			SUM(v.Value) AS Value
+		FROM tblstockvaluation v
+		JOIN tblwarehouse w ON v.WhsCode = w.WhsCode
+		JOIN tblitem i ON v.ItCode = i.ItCode
+		LEFT JOIN tblitemcategory c ON i.ItCtCode = c.ItCtCode
+		LEFT JOIN tbluom u ON i.InventoryUOMCode = u.UomCode
+		WHERE ` + strings.Join(where, " AND ") + `
+		GROUP BY v.WhsCode, w.WhsName, v.ItCode, i.ItName, c.ItCtName, c.CostMethod, u.UomName
+		HAVING SUM(v.Qty) != 0 OR SUM(v.Value) != 0
+		ORDER BY v.WhsCode, v.ItCode
+		LIMIT ? OFFSET ?`
+	args = append(args, param.PageSize, offset)
+
+	if err := t.DB.SelectContext(ctx, &data, query, args...); err != nil {
+		log.Printf("Error executing query: %v", err)
+		return nil, fmt.Errorf("error Fetch stock valuation: %w", err)
+	}
+
+	for i, detail := range data {
+		detail.Number = uint(offset + i + 1)
+		if detail.Quantity != 0 {
+			detail.UnitCost = detail.TotalValue / detail.Quantity
+		}
+	}
+
+	return &pagination.PaginationResponse{
+		Data:         data,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}, nil
+}
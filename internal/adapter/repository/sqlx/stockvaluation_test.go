@@ -0,0 +1,171 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
+)
+
+const (
+	queryConsumeLayer = "SELECT LayerId, RemainQty, UnitCost FROM tblstockcostlayer WHERE WhsCode = ? AND ItCode = ? AND BatchNo = ? AND RemainQty > 0 ORDER BY DocDt, LayerId FOR UPDATE"
+	queryDrawLayer    = "UPDATE tblstockcostlayer SET RemainQty = RemainQty - ? WHERE LayerId = ?"
+	queryCostMethod   = "SELECT COALESCE(c.CostMethod, 'A') FROM tblitem i LEFT JOIN tblitemcategory c ON i.ItCtCode = c.ItCtCode WHERE i.ItCode = ?"
+	queryLayerBalance = "SELECT COALESCE(SUM(RemainQty), 0) AS Qty, COALESCE(SUM(RemainQty * UnitCost), 0) AS Value FROM tblstockcostlayer WHERE WhsCode = ? AND ItCode = ? AND RemainQty > 0 FOR UPDATE"
+	queryInsertLayer  = "INSERT INTO tblstockcostlayer ( DocType, DocNo, DNo, DocDt, WhsCode, ItCode, BatchNo, Qty, RemainQty, UnitCost, CreateBy, CreateDt ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	queryAverageCost  = "UPDATE tblstockcostlayer SET UnitCost = ? WHERE WhsCode = ? AND ItCode = ? AND RemainQty > 0"
+	queryPostedValue  = "SELECT COALESCE(SUM(Qty), 0) AS Qty, COALESCE(SUM(Value), 0) AS Value FROM tblstockvaluation WHERE DocType = ? AND DocNo = ? AND DNo = ? AND WhsCode = ? AND ItCode = ? AND BatchNo = ?"
+	queryOwnLayer     = "SELECT LayerId, RemainQty, UnitCost FROM tblstockcostlayer WHERE DocType = ? AND DocNo = ? AND DNo = ? AND WhsCode = ? AND ItCode = ? AND BatchNo = ? AND RemainQty > 0 ORDER BY LayerId FOR UPDATE"
+	queryValuation    = "INSERT INTO tblstockvaluation ( DocType, DocNo, DNo, DocDt, WhsCode, ItCode, BatchNo, Qty, Value, CreateBy, CreateDt ) VALUES "
+)
+
+type StockValuationRepositorySuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	repo    *StockValuationRepository
+	db      *sqlx.DB
+	tx      *sqlx.Tx
+}
+
+func (suite *StockValuationRepositorySuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.repo = &StockValuationRepository{}
+
+	suite.mockSQL.ExpectBegin()
+	suite.tx, err = suite.db.Beginx()
+	suite.Require().NoError(err)
+}
+
+func (suite *StockValuationRepositorySuite) TearDownTest() {
+	suite.db.Close()
+}
+
+// barang keluar menghabiskan layer tertua dulu: 3 x 10 + 2 x 12
+func (suite *StockValuationRepositorySuite) TestPost_OutConsumesOldestLayers() {
+	m := movement(inventoryledger.Out)
+
+	suite.mockSQL.ExpectQuery(queryConsumeLayer).
+		WithArgs(m.WhsCode, m.ItCode, m.BatchNo).
+		WillReturnRows(sqlmock.NewRows([]string{"LayerId", "RemainQty", "UnitCost"}).
+			AddRow(1, 3, 10).
+			AddRow(2, 4, 12))
+	suite.mockSQL.ExpectExec(queryDrawLayer).
+		WithArgs(float64(3), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryDrawLayer).
+		WithArgs(float64(2), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryValuation+"(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);").
+		WithArgs(m.DocType, m.DocNo, m.DNo, m.DocDt, m.WhsCode, m.ItCode, m.BatchNo, float64(-5), float64(-54), m.CreateBy, m.CreateDt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	_, err := suite.repo.Post(context.Background(), suite.tx, []inventoryledger.Movement{m})
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// moving average: 10 x 10 yang ada + 10 x 20 masuk, semua layer jadi 15
+func (suite *StockValuationRepositorySuite) TestPost_InMovingAverage() {
+	m := movement(inventoryledger.In)
+	m.UnitCost = 20
+	m.Qty = 10
+
+	suite.mockSQL.ExpectQuery(queryCostMethod).
+		WithArgs(m.ItCode).
+		WillReturnRows(sqlmock.NewRows([]string{"CostMethod"}).AddRow("A"))
+	suite.mockSQL.ExpectQuery(queryLayerBalance).
+		WithArgs(m.WhsCode, m.ItCode).
+		WillReturnRows(sqlmock.NewRows([]string{"Qty", "Value"}).AddRow(10, 100))
+	suite.mockSQL.ExpectExec(queryInsertLayer).
+		WithArgs(m.DocType, m.DocNo, m.DNo, m.DocDt, m.WhsCode, m.ItCode, m.BatchNo, float64(10), float64(10), float64(15), m.CreateBy, m.CreateDt).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	suite.mockSQL.ExpectExec(queryAverageCost).
+		WithArgs(float64(15), m.WhsCode, m.ItCode).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mockSQL.ExpectExec(queryValuation+"(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);").
+		WithArgs(m.DocType, m.DocNo, m.DNo, m.DocDt, m.WhsCode, m.ItCode, m.BatchNo, float64(10), float64(200), m.CreateBy, m.CreateDt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	_, err := suite.repo.Post(context.Background(), suite.tx, []inventoryledger.Movement{m})
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// barang masuk pasangan transfer tanpa harga memakai harga barang keluarnya
+func (suite *StockValuationRepositorySuite) TestPost_TransferInheritsOutCost() {
+	out := movement(inventoryledger.Out)
+	in := movement(inventoryledger.In)
+	in.DocType = "Stock Mutation (To)"
+	in.WhsCode = "WHS02"
+
+	suite.mockSQL.ExpectQuery(queryConsumeLayer).
+		WithArgs(out.WhsCode, out.ItCode, out.BatchNo).
+		WillReturnRows(sqlmock.NewRows([]string{"LayerId", "RemainQty", "UnitCost"}).AddRow(1, 10, 8))
+	suite.mockSQL.ExpectExec(queryDrawLayer).
+		WithArgs(float64(5), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectQuery(queryCostMethod).
+		WithArgs(in.ItCode).
+		WillReturnRows(sqlmock.NewRows([]string{"CostMethod"}).AddRow("F"))
+	suite.mockSQL.ExpectExec(queryInsertLayer).
+		WithArgs(in.DocType, in.DocNo, in.DNo, in.DocDt, in.WhsCode, in.ItCode, in.BatchNo, float64(5), float64(5), float64(8), in.CreateBy, in.CreateDt).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	suite.mockSQL.ExpectExec(queryValuation+"(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?),(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);").
+		WithArgs(
+			out.DocType, out.DocNo, out.DNo, out.DocDt, out.WhsCode, out.ItCode, out.BatchNo, float64(-5), float64(-40), out.CreateBy, out.CreateDt,
+			in.DocType, in.DocNo, in.DNo, in.DocDt, in.WhsCode, in.ItCode, in.BatchNo, float64(5), float64(40), in.CreateBy, in.CreateDt,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+
+	_, err := suite.repo.Post(context.Background(), suite.tx, []inventoryledger.Movement{in, out})
+
+	suite.NoError(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// batal barang masuk moving average ditarik senilai posting (5 x 10), bukan harga
+// rata-rata saat ini (15), lalu sisa layer dirata-rata ulang: (150 - 50) / 5
+func (suite *StockValuationRepositorySuite) TestReverse_InMovingAverage() {
+	m := movement(inventoryledger.In)
+
+	suite.mockSQL.ExpectQuery(queryCostMethod).
+		WithArgs(m.ItCode).
+		WillReturnRows(sqlmock.NewRows([]string{"CostMethod"}).AddRow("A"))
+	suite.mockSQL.ExpectQuery(queryPostedValue).
+		WithArgs(m.DocType, m.DocNo, m.DNo, m.WhsCode, m.ItCode, m.BatchNo).
+		WillReturnRows(sqlmock.NewRows([]string{"Qty", "Value"}).AddRow(5, 50))
+	suite.mockSQL.ExpectQuery(queryLayerBalance).
+		WithArgs(m.WhsCode, m.ItCode).
+		WillReturnRows(sqlmock.NewRows([]string{"Qty", "Value"}).AddRow(10, 150))
+	suite.mockSQL.ExpectQuery(queryOwnLayer).
+		WithArgs(m.DocType, m.DocNo, m.DNo, m.WhsCode, m.ItCode, m.BatchNo).
+		WillReturnRows(sqlmock.NewRows([]string{"LayerId", "RemainQty", "UnitCost"}).AddRow(7, 5, 15))
+	suite.mockSQL.ExpectExec(queryDrawLayer).
+		WithArgs(float64(5), int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryAverageCost).
+		WithArgs(float64(20), m.WhsCode, m.ItCode).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryValuation+"(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);").
+		WithArgs(m.DocType, m.DocNo, m.DNo, m.DocDt, m.WhsCode, m.ItCode, m.BatchNo, float64(-5), float64(-50), m.CreateBy, m.CreateDt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	entries, err := suite.repo.Reverse(context.Background(), suite.tx, []inventoryledger.Movement{m})
+
+	suite.NoError(err)
+	suite.Equal(float32(-50), entries[0].Value)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func TestStockValuationRepositorySuite(t *testing.T) {
+	suite.Run(t, new(StockValuationRepositorySuite))
+}
@@ -0,0 +1,233 @@
+package sqlx
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	sharedfunc "gitlab.com/ayaka/internal/domain/shared/sharedFunc"
+	"gitlab.com/ayaka/internal/domain/taxengine"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+// TaxEngineRepository menghitung pajak dokumen per baris (bisa lebih dari
+// satu pajak) dan menyimpan breakdown-nya. Tarif dan flag withholding
+// di-snapshot supaya dokumen lama tidak berubah saat master pajak diedit.
+//
+//	ALTER TABLE tbltax
+//		ADD COLUMN WithholdInd CHAR(1) NOT NULL DEFAULT 'N',
+//		ADD COLUMN RoundingMode VARCHAR(10) NOT NULL DEFAULT 'HALF_UP',
+//		ADD COLUMN RoundingDecimal TINYINT NOT NULL DEFAULT 2;
+//	ALTER TABLE tblvendorhdr ADD COLUMN TaxGroupCode VARCHAR(16) NULL;
+//	ALTER TABLE tblcustomerhdr ADD COLUMN TaxGroupCode VARCHAR(16) NULL;
+//	ALTER TABLE tblpurchaseorderhdr ADD COLUMN TaxInclusiveInd CHAR(1) NOT NULL DEFAULT 'N';
+//	ALTER TABLE tbldirectsalesdelivhdr ADD COLUMN TaxInclusiveInd CHAR(1) NOT NULL DEFAULT 'N';
+//	ALTER TABLE tbldirectpurchasercvhdr ADD COLUMN TaxInclusiveInd CHAR(1) NOT NULL DEFAULT 'N';
+//	CREATE TABLE tbldoctaxdtl (
+//		DocType VARCHAR(30) NOT NULL,
+//		DocNo VARCHAR(30) NOT NULL,
+//		DNo VARCHAR(5) NOT NULL,
+//		SeqNo TINYINT NOT NULL,
+//		TaxCode VARCHAR(12) NOT NULL,
+//		TaxRate DECIMAL(9,6) NOT NULL,
+//		WithholdInd CHAR(1) NOT NULL,
+//		BaseAmt DECIMAL(18,4) NOT NULL,
+//		TaxAmt DECIMAL(18,4) NOT NULL,
+//		PRIMARY KEY (DocType, DocNo, DNo, TaxCode)
+//	);
+type TaxEngineRepository struct {
+	DB *repository.Sqlx `inject:"database"`
+}
+
+func (t *TaxEngineRepository) Calculate(ctx context.Context, req *taxengine.Request) (*taxengine.Breakdown, error) {
+	if err := resolveTaxes(ctx, t.DB, req); err != nil {
+		return nil, err
+	}
+
+	return taxengine.Calculate(req.Inclusive, req.Lines), nil
+}
+
+func (t *TaxEngineRepository) Apply(ctx context.Context, tx *sqlx.Tx, docType, docNo string, req *taxengine.Request) (*taxengine.Breakdown, error) {
+	if err := resolveTaxes(ctx, tx, req); err != nil {
+		return nil, err
+	}
+
+	result := taxengine.Calculate(req.Inclusive, req.Lines)
+	if len(result.Lines) == 0 {
+		return result, nil
+	}
+
+	var placeholders []string
+	var args []interface{}
+	seq := make(map[string]int)
+	for _, line := range result.Lines {
+		seq[line.DNo]++
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		args = append(args,
+			docType,
+			docNo,
+			line.DNo,
+			seq[line.DNo],
+			line.TaxCode,
+			line.TaxRate,
+			line.Withholding,
+			line.BaseAmount,
+			line.TaxAmount,
+		)
+	}
+
+	query := `INSERT INTO tbldoctaxdtl
+		(
+			DocType,
+			DocNo,
+			DNo,
+			SeqNo,
+			TaxCode,
+			TaxRate,
+			WithholdInd,
+			BaseAmt,
+			TaxAmt
+		) VALUES ` + strings.Join(placeholders, ",")
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		log.Printf("Error insert document tax: %+v", err)
+		return nil, fmt.Errorf("error insert document tax: %w", err)
+	}
+
+	return result, nil
+}
+
+func (t *TaxEngineRepository) Breakdowns(ctx context.Context, docType string, docNos []string) (map[string][]taxengine.LineTax, error) {
+	result := make(map[string][]taxengine.LineTax)
+	if len(docNos) == 0 {
+		return result, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT d.DocNo, d.DNo, d.TaxCode, t.TaxName, d.TaxRate, d.WithholdInd, d.BaseAmt, d.TaxAmt
+		FROM tbldoctaxdtl d
+		JOIN tbltax t ON d.TaxCode = t.TaxCode
+		WHERE d.DocType = ? AND d.DocNo IN (?)
+		ORDER BY d.DocNo, d.DNo, d.SeqNo`, docType, docNos)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing document tax query: %w", err)
+	}
+
+	var rows []taxengine.LineTax
+	if err := t.DB.SelectContext(ctx, &rows, t.DB.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("error fetching document tax: %w", err)
+	}
+
+	for _, row := range rows {
+		result[row.DocNo] = append(result[row.DocNo], row)
+	}
+
+	return result, nil
+}
+
+// resolveTaxes mengisi Taxes tiap baris. Baris tanpa TaxCodes memakai TaxCode
+// header, kalau kosong juga memakai semua pajak di tax group vendor/customer.
+// DNo atau TaxCode yang dobel dalam satu baris ditolak karena akan bentrok
+// dengan primary key tbldoctaxdtl.
+func resolveTaxes(ctx context.Context, db sqlx.ExtContext, req *taxengine.Request) error {
+	needDefault := false
+	dnos := make(map[string]struct{}, len(req.Lines))
+	for _, line := range req.Lines {
+		if _, ok := dnos[line.DNo]; ok {
+			return fmt.Errorf("%w: duplicate detail number %s", customerrors.ErrInvalidInput, line.DNo)
+		}
+		dnos[line.DNo] = struct{}{}
+
+		if len(line.TaxCodes) == 0 {
+			needDefault = true
+		}
+		if len(sharedfunc.UniqueStringSlice(line.TaxCodes)) != len(line.TaxCodes) {
+			return fmt.Errorf("%w: duplicate tax code on detail %s", customerrors.ErrInvalidInput, line.DNo)
+		}
+	}
+
+	var defaults []string
+	if needDefault {
+		var err error
+		switch {
+		case req.TaxCode != "":
+			defaults = []string{req.TaxCode}
+		case req.VendorCode != "":
+			err = sqlx.SelectContext(ctx, db, &defaults, `SELECT t.TaxCode
+				FROM tbltax t
+				JOIN tblvendorhdr v ON t.TaxGroupCode = v.TaxGroupCode
+				WHERE v.VendorCode = ?
+				ORDER BY t.TaxCode`, req.VendorCode)
+		case req.CustomerCode != "":
+			err = sqlx.SelectContext(ctx, db, &defaults, `SELECT t.TaxCode
+				FROM tbltax t
+				JOIN tblcustomerhdr c ON t.TaxGroupCode = c.TaxGroupCode
+				WHERE c.CustCode = ?
+				ORDER BY t.TaxCode`, req.CustomerCode)
+		}
+		if err != nil {
+			return fmt.Errorf("error fetching default tax: %w", err)
+		}
+	}
+
+	codes := append([]string{}, defaults...)
+	for _, line := range req.Lines {
+		codes = append(codes, line.TaxCodes...)
+	}
+	codes = sharedfunc.UniqueStringSlice(codes)
+
+	taxes := make(map[string]taxengine.Tax)
+	if len(codes) > 0 {
+		query, args, err := sqlx.In(`SELECT TaxCode, TaxName, TaxRate, WithholdInd, RoundingMode, RoundingDecimal
+			FROM tbltax
+			WHERE TaxCode IN (?)`, codes)
+		if err != nil {
+			return fmt.Errorf("error preparing tax query: %w", err)
+		}
+
+		var rows []taxengine.Tax
+		if err := sqlx.SelectContext(ctx, db, &rows, db.Rebind(query), args...); err != nil {
+			return fmt.Errorf("error fetching tax: %w", err)
+		}
+		for _, row := range rows {
+			taxes[row.TaxCode] = row
+		}
+	}
+
+	for i := range req.Lines {
+		line := &req.Lines[i]
+		lineCodes := line.TaxCodes
+		if len(lineCodes) == 0 {
+			lineCodes = defaults
+		}
+
+		line.Taxes = nil
+		for _, code := range lineCodes {
+			tax, ok := taxes[code]
+			if !ok {
+				return fmt.Errorf("%w: tax %s not found", customerrors.ErrInvalidInput, code)
+			}
+			line.Taxes = append(line.Taxes, tax)
+		}
+	}
+
+	return nil
+}
+
+// activeLineTaxes membuang pajak baris yang sudah tidak ada di lines
+// (misal baris dibatalkan setelah dokumen dibuat)
+func activeLineTaxes(lines []taxengine.Line, taxes []taxengine.LineTax) []taxengine.LineTax {
+	active := make(map[string]struct{}, len(lines))
+	for _, line := range lines {
+		active[line.DNo] = struct{}{}
+	}
+
+	var result []taxengine.LineTax
+	for _, tax := range taxes {
+		if _, ok := active[tax.DNo]; ok {
+			result = append(result, tax)
+		}
+	}
+	return result
+}
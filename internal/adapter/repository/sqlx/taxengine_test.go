@@ -0,0 +1,33 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/ayaka/internal/domain/taxengine"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+// TaxCode dobel di satu baris ditolak sebelum insert tbldoctaxdtl
+func TestApply_DuplicateTaxCode(t *testing.T) {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	db := sqlx.NewDb(mockDb, "mysql")
+	defer db.Close()
+
+	mock.ExpectBegin()
+	tx, err := db.Beginx()
+	assert.NoError(t, err)
+
+	repo := &TaxEngineRepository{}
+	_, err = repo.Apply(context.Background(), tx, taxengine.DirectPurchaseReceive, "0001/R1/DPR/01/26", &taxengine.Request{
+		Lines: []taxengine.Line{{DNo: "001", Amount: 1000, TaxCodes: []string{"PPN11", "PPN11"}}},
+	})
+
+	assert.True(t, errors.Is(err, customerrors.ErrInvalidInput))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -143,6 +143,14 @@ func (t *TblCityRepository) Update(ctx context.Context, data *tblcity.UpdateTblC
 		return nil, fmt.Errorf("error starting transaction: %w", err)
 	}
 
+	audit, err := newAuditTrail(ctx, tx, "City", data.CityCode)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Failed to rollback transaction: %+v", rbErr)
+		}
+		return nil, err
+	}
+
 	log.Printf("Executing query: %s", query)
 	_, err = tx.ExecContext(ctx, query, data.CityName, data.Province, data.RingArea, data.Location, data.UserCode, data.LastUpdateDate, data.CityCode)
 
@@ -154,10 +162,7 @@ func (t *TblCityRepository) Update(ctx context.Context, data *tblcity.UpdateTblC
 		return nil, fmt.Errorf("error updating City: %w", err)
 	}
 
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
-	log.Printf("Executing query: %s with UserCode: %s, Code: %s, Category: %s, Date: %s", query, data.UserCode, data.CityCode, "City", data.LastUpdateDate)
-	_, err = tx.ExecContext(ctx, query, data.UserCode, data.CityCode, "City", data.LastUpdateDate)
+	err = audit.Write(ctx, tx, data.UserCode, data.LastUpdateDate)
 
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
@@ -123,6 +123,14 @@ func (t *TblCountryRepository) Update(ctx context.Context, data *tblcountry.Upda
 		return nil, fmt.Errorf("error starting transaction: %w", err)
 	}
 
+	audit, err := newAuditTrail(ctx, tx, "Country", data.CountryCode)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Failed to rollback transaction: %+v", rbErr)
+		}
+		return nil, err
+	}
+
 	log.Printf("Executing query: %s with CntCode: %s and CntName: %s by %s", query, data.CountryCode, data.CountryName, data.UserCode)
 	_, err = tx.ExecContext(ctx, query, data.CountryName, data.UserCode, data.LastUpdateDate, data.CountryCode)
 
@@ -134,10 +142,7 @@ func (t *TblCountryRepository) Update(ctx context.Context, data *tblcountry.Upda
 		return nil, fmt.Errorf("error updating country: %w", err)
 	}
 
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
-	log.Printf("Executing query: %s with UserCode: %s, Code: %s, Category: %s, Date: %s", query, data.UserCode, data.CountryCode, "Country", data.LastUpdateDate)
-	_, err = tx.ExecContext(ctx, query, data.UserCode, data.CountryCode, "Country", data.LastUpdateDate)
+	err = audit.Write(ctx, tx, data.UserCode, data.LastUpdateDate)
 
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
@@ -124,6 +124,14 @@ func (t *TblCurrencyRepository) Update(ctx context.Context, data *tblcurrency.Up
 		return nil, fmt.Errorf("error starting transaction: %w", err)
 	}
 
+	audit, err := newAuditTrail(ctx, tx, "Currency", data.CurrencyCode)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Failed to rollback transaction: %+v", rbErr)
+		}
+		return nil, err
+	}
+
 	_, err = tx.ExecContext(ctx, query, data.CurrencyName, data.UserCode, data.LastUpdateDate, data.CurrencyCode)
 
 	if err != nil {
@@ -134,9 +142,7 @@ func (t *TblCurrencyRepository) Update(ctx context.Context, data *tblcurrency.Up
 		return nil, fmt.Errorf("error updating currency: %w", err)
 	}
 
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
-	_, err = tx.ExecContext(ctx, query, data.UserCode, data.CurrencyCode, "Currency", data.LastUpdateDate)
+	err = audit.Write(ctx, tx, data.UserCode, data.LastUpdateDate)
 
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
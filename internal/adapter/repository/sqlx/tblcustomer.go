@@ -0,0 +1,406 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/tblmastercustomer"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+// TblCustomerRepository master customer, strukturnya mengikuti master vendor.
+//
+//	CREATE TABLE tblcustomerhdr (
+//		CustCode VARCHAR(16) NOT NULL PRIMARY KEY,
+//		CustName VARCHAR(255) NOT NULL,
+//		CustCatCode VARCHAR(50) NOT NULL,
+//		Address VARCHAR(255) NULL,
+//		CityCode VARCHAR(16) NOT NULL,
+//		PostalCode VARCHAR(10) NULL,
+//		Phone VARCHAR(20) NULL,
+//		Mobile VARCHAR(20) NULL,
+//		Email VARCHAR(255) NULL,
+//		Remark VARCHAR(255) NULL,
+//		TaxGroupCode VARCHAR(16) NULL,
+//		NPWP VARCHAR(16) NULL,
+//		CreateBy VARCHAR(16) NOT NULL,
+//		CreateDt VARCHAR(12) NOT NULL,
+//		LastUpBy VARCHAR(16) NULL,
+//		LastUpDt VARCHAR(12) NULL
+//	);
+//	CREATE TABLE tblcontactcustomerdtl (
+//		CustCode VARCHAR(16) NOT NULL,
+//		DNo VARCHAR(5) NOT NULL,
+//		Name VARCHAR(255) NOT NULL,
+//		Number VARCHAR(20) NULL,
+//		Position VARCHAR(50) NULL,
+//		Type VARCHAR(50) NULL,
+//		Active CHAR(1) NOT NULL DEFAULT 'Y',
+//		PRIMARY KEY (CustCode, DNo)
+//	);
+//	CREATE TABLE tbladdresscustomerdtl (
+//		CustCode VARCHAR(16) NOT NULL,
+//		DNo VARCHAR(5) NOT NULL,
+//		Name VARCHAR(255) NOT NULL,
+//		Address VARCHAR(255) NULL,
+//		CityCode VARCHAR(16) NOT NULL,
+//		PostalCode VARCHAR(10) NULL,
+//		Phone VARCHAR(20) NULL,
+//		Active CHAR(1) NOT NULL DEFAULT 'Y',
+//		PRIMARY KEY (CustCode, DNo)
+//	);
+type TblCustomerRepository struct {
+	DB *repository.Sqlx            `inject:"database"`
+	ID *formatid.GenerateIDHandler `inject:"generateID"`
+}
+
+func (t *TblCustomerRepository) Create(ctx context.Context, data *tblmastercustomer.Create) (*tblmastercustomer.Create, error) {
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	data.CustomerCode, err = t.ID.GenerateID(ctx, tx, "MasterCustomer", data.CreateDate)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
+	query := `INSERT INTO tblcustomerhdr
+		(
+			CustCode,
+			CustName,
+			CustCatCode,
+			Address,
+			CityCode,
+			PostalCode,
+			Phone,
+			Mobile,
+			Email,
+			Remark,
+			TaxGroupCode,
+			NPWP,
+			CreateDt,
+			CreateBy
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	if _, err = tx.ExecContext(ctx, query,
+		data.CustomerCode,
+		data.CustomerName,
+		data.CustomerCategoryCode,
+		data.Address,
+		data.CityCode,
+		data.PostalCode,
+		data.Phone,
+		data.Mobile,
+		data.Email,
+		data.Remark,
+		data.TaxGroupCode,
+		data.NPWP,
+		data.CreateDate,
+		data.CreateBy,
+	); err != nil {
+		log.Printf("Detailed error: %+v", err)
+		return nil, fmt.Errorf("error Create Customer Header: %w", err)
+	}
+
+	if err = saveCustomerDetails(ctx, tx, data.CustomerCode, data.ContactCustomer, data.AddressCustomer); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return data, nil
+}
+
+func (t *TblCustomerRepository) Fetch(ctx context.Context, name, cat string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	var totalRecords int
+
+	search := "%" + name + "%"
+	where := "(c.CustCode LIKE ? OR c.CustName LIKE ?)"
+	args := []interface{}{search, search}
+	if cat != "" {
+		where += " AND c.CustCatCode = ?"
+		args = append(args, cat)
+	}
+
+	if err := t.DB.GetContext(ctx, &totalRecords, "SELECT COUNT(*) FROM tblcustomerhdr c WHERE "+where, args...); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+
+	var totalPages, offset int
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+	}
+
+	items := make([]*tblmastercustomer.Read, 0)
+	query := `SELECT
+			c.CustCode,
+			c.CustName,
+			cc.CustCatName,
+			c.Address,
+			ct.CityName,
+			c.CreateDt
+		FROM tblcustomerhdr c
+		JOIN tblcustomercategory cc ON c.CustCatCode = cc.CustCatCode
+		JOIN tblcity ct ON c.CityCode = ct.CityCode
+		WHERE ` + where + `
+		ORDER BY c.CustName
+		LIMIT ? OFFSET ?`
+	if err := t.DB.SelectContext(ctx, &items, query, append(args, param.PageSize, offset)...); err != nil {
+		return nil, fmt.Errorf("error Fetch Customer: %w", err)
+	}
+
+	j := offset
+	for _, item := range items {
+		j++
+		item.Number = uint(j)
+		item.CreateDate = share.FormatDate(item.CreateDate)
+	}
+
+	return &pagination.PaginationResponse{
+		Data:         items,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}, nil
+}
+
+func (t *TblCustomerRepository) Detail(ctx context.Context, customerCode string) (*tblmastercustomer.Detail, error) {
+	query := `SELECT
+			CustCode,
+			CustName,
+			CustCatCode,
+			Address,
+			CityCode,
+			PostalCode,
+			Phone,
+			Mobile,
+			Email,
+			Remark,
+			TaxGroupCode,
+			NPWP
+		FROM tblcustomerhdr
+		WHERE CustCode = ?`
+
+	var detail tblmastercustomer.Detail
+	if err := t.DB.GetContext(ctx, &detail, query, customerCode); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrDataNotFound
+		}
+		return nil, fmt.Errorf("error fetching customer detail: %w", err)
+	}
+
+	detail.ContactCustomer = make([]tblmastercustomer.ContactCustomer, 0)
+	query = `SELECT CustCode, DNo, Name, Number, Position, Type, Active
+		FROM tblcontactcustomerdtl
+		WHERE CustCode = ? AND Active = 'Y'
+		ORDER BY DNo`
+	if err := t.DB.SelectContext(ctx, &detail.ContactCustomer, query, customerCode); err != nil {
+		return nil, fmt.Errorf("error fetching contact customer: %w", err)
+	}
+
+	detail.AddressCustomer = make([]tblmastercustomer.AddressCustomer, 0)
+	query = `SELECT CustCode, DNo, Name, Address, CityCode, PostalCode, Phone, Active
+		FROM tbladdresscustomerdtl
+		WHERE CustCode = ? AND Active = 'Y'
+		ORDER BY DNo`
+	if err := t.DB.SelectContext(ctx, &detail.AddressCustomer, query, customerCode); err != nil {
+		return nil, fmt.Errorf("error fetching address customer: %w", err)
+	}
+
+	return &detail, nil
+}
+
+func (t *TblCustomerRepository) Update(ctx context.Context, data *tblmastercustomer.Update) (*tblmastercustomer.Update, error) {
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			log.Printf("Transaction rollback due to error: %+v", err)
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	audit, err := newAuditTrail(ctx, tx, "MasterCustomer", data.CustomerCode)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE tblcustomerhdr SET
+			CustName = ?,
+			CustCatCode = ?,
+			Address = ?,
+			CityCode = ?,
+			PostalCode = ?,
+			Phone = ?,
+			Mobile = ?,
+			Email = ?,
+			Remark = ?,
+			TaxGroupCode = ?,
+			NPWP = ?,
+			LastUpBy = ?,
+			LastUpDt = ?
+		WHERE CustCode = ?`
+	if _, err = tx.ExecContext(ctx, query,
+		data.CustomerName,
+		data.CustomerCategoryCode,
+		data.Address,
+		data.CityCode,
+		data.PostalCode,
+		data.Phone,
+		data.Mobile,
+		data.Email,
+		data.Remark,
+		data.TaxGroupCode,
+		data.NPWP,
+		data.LastUpdateBy,
+		data.LastUpdateDate,
+		data.CustomerCode,
+	); err != nil {
+		log.Printf("Detailed error: %+v", err)
+		return nil, fmt.Errorf("error Update Customer Header: %w", err)
+	}
+
+	if err = saveCustomerDetails(ctx, tx, data.CustomerCode, data.ContactCustomer, data.AddressCustomer); err != nil {
+		return nil, err
+	}
+
+	if err = audit.Write(ctx, tx, data.LastUpdateBy, data.LastUpdateDate); err != nil {
+		log.Printf("Detailed error: %+v", err)
+		return nil, fmt.Errorf("error inserting log activity: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return data, nil
+}
+
+// saveCustomerDetails menyimpan contact dan alamat kirim. Baris yang tidak
+// dikirim lagi dinonaktifkan (bukan dihapus) karena DNo alamat bisa sudah
+// dirujuk dokumen penjualan; baris baru (DNo kosong) diberi nomor lanjutan.
+func saveCustomerDetails(ctx context.Context, tx *sqlx.Tx, custCode string, contacts []tblmastercustomer.ContactCustomer, addresses []tblmastercustomer.AddressCustomer) error {
+	for _, table := range []string{"tblcontactcustomerdtl", "tbladdresscustomerdtl"} {
+		if _, err := tx.ExecContext(ctx, "UPDATE "+table+" SET Active = 'N' WHERE CustCode = ? AND Active = 'Y'", custCode); err != nil {
+			return fmt.Errorf("error deactivate %s: %w", table, err)
+		}
+	}
+
+	if len(contacts) > 0 {
+		next, err := nextCustomerDNo(ctx, tx, "tblcontactcustomerdtl", custCode)
+		if err != nil {
+			return err
+		}
+
+		var placeholders []string
+		var args []interface{}
+		for i := range contacts {
+			c := &contacts[i]
+			if c.DNo == "" {
+				c.DNo = fmt.Sprintf("%05d", next)
+				next++
+			}
+			c.CustomerCode = custCode
+			placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, 'Y')")
+			args = append(args, custCode, c.DNo, c.Name, c.Number, c.Position, c.Type)
+		}
+
+		query := `INSERT INTO tblcontactcustomerdtl (CustCode, DNo, Name, Number, Position, Type, Active)
+			VALUES ` + strings.Join(placeholders, ", ") + `
+			ON DUPLICATE KEY UPDATE
+				Name = VALUES(Name),
+				Number = VALUES(Number),
+				Position = VALUES(Position),
+				Type = VALUES(Type),
+				Active = VALUES(Active)`
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			log.Printf("Detailed error: %+v", err)
+			return fmt.Errorf("error Save Contact Customer: %w", err)
+		}
+	}
+
+	if len(addresses) > 0 {
+		next, err := nextCustomerDNo(ctx, tx, "tbladdresscustomerdtl", custCode)
+		if err != nil {
+			return err
+		}
+
+		var placeholders []string
+		var args []interface{}
+		for i := range addresses {
+			a := &addresses[i]
+			if a.DNo == "" {
+				a.DNo = fmt.Sprintf("%05d", next)
+				next++
+			}
+			a.CustomerCode = custCode
+			placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, 'Y')")
+			args = append(args, custCode, a.DNo, a.Name, a.Address, a.CityCode, a.PostalCode, a.Phone)
+		}
+
+		query := `INSERT INTO tbladdresscustomerdtl (CustCode, DNo, Name, Address, CityCode, PostalCode, Phone, Active)
+			VALUES ` + strings.Join(placeholders, ", ") + `
+			ON DUPLICATE KEY UPDATE
+				Name = VALUES(Name),
+				Address = VALUES(Address),
+				CityCode = VALUES(CityCode),
+				PostalCode = VALUES(PostalCode),
+				Phone = VALUES(Phone),
+				Active = VALUES(Active)`
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			log.Printf("Detailed error: %+v", err)
+			return fmt.Errorf("error Save Address Customer: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func nextCustomerDNo(ctx context.Context, tx *sqlx.Tx, table, custCode string) (int, error) {
+	var last int
+	if err := tx.GetContext(ctx, &last, "SELECT COALESCE(MAX(CAST(DNo AS UNSIGNED)), 0) FROM "+table+" WHERE CustCode = ?", custCode); err != nil {
+		return 0, fmt.Errorf("error get last detail number %s: %w", table, err)
+	}
+
+	return last + 1, nil
+}
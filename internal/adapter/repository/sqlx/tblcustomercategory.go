@@ -133,6 +133,14 @@ func (t *TblCustomerCategoryRepository) Update(ctx context.Context, data *tblcus
 		return nil, fmt.Errorf("error starting transaction: %w", err)
 	}
 
+	audit, err := newAuditTrail(ctx, tx, "CustomerCategory", data.CustomerCategoryCode)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Failed to rollback transaction: %+v", rbErr)
+		}
+		return nil, err
+	}
+
 	result, err := tx.ExecContext(ctx, query,
 		data.CustomerCategoryName,
 		data.CustomerCategoryCode)
@@ -159,9 +167,7 @@ func (t *TblCustomerCategoryRepository) Update(ctx context.Context, data *tblcus
 		return nil, customerrors.ErrNoDataEdited
 	}
 
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
-	_, err = tx.ExecContext(ctx, query, data.LastUpdateBy, data.CustomerCategoryCode, "CustomerCategory", data.LastUpdateDate)
+	err = audit.Write(ctx, tx, data.LastUpdateBy, data.LastUpdateDate)
 
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
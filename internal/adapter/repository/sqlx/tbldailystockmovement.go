@@ -54,6 +54,10 @@ func (t *TblDailyStockMovementRepository) Fetch(ctx context.Context, warehouse,
 		endquery = append(endquery, `s.WhsCode = ?`)
 		search = append(search, warehouse)
 	}
+	if scope, scopeArgs := warehouseScope(ctx, "s.WhsCode"); scope != "" {
+		endquery = append(endquery, scope)
+		search = append(search, scopeArgs...)
+	}
 
 	if len(endquery) > 0 {
 		countQuery += " WHERE " + strings.Join(endquery, " AND ") + " AND s.CancelInd = 'N'"
@@ -104,7 +108,7 @@ func (t *TblDailyStockMovementRepository) Fetch(ctx context.Context, warehouse,
 		FROM tblstockmovement s
 		JOIN tblitem i ON s.ItCode = i.ItCode
 		JOIN tblitemcategory c ON i.ItCtCode = c.ItCtCode
-		JOIN tbluom u ON i.PurchaseUOMCode = u.UomCode
+		JOIN tbluom u ON i.InventoryUOMCode = u.UomCode
 	`
 
 	// Tambahkan filter WHERE
@@ -10,18 +10,55 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/tbldirectmaterialreceive"
 
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
 
 type TblDirectMaterialReceiveRepository struct {
-	DB *repository.Sqlx `inject:"database"`
+	DB     *repository.Sqlx            `inject:"database"`
+	Ledger inventoryledger.Repository  `inject:"inventoryLedgerRepository"`
+	ID     *formatid.GenerateIDHandler `inject:"generateID"`
 }
 
 func (t *TblDirectMaterialReceiveRepository) Create(ctx context.Context, data *tbldirectmaterialreceive.Create) (*tbldirectmaterialreceive.Create, error) {
+	// penerimaan dicatat oleh gudang tujuan
+	if err := checkWarehouse(ctx, data.WhsCodeTo); err != nil {
+		return nil, err
+	}
+
+	// transaction begin
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	// Pastikan rollback dipanggil jika transaksi tidak berhasil
+	defer func() {
+		if err != nil {
+			// Rollback jika error terjadi
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	data.DocNo, err = t.ID.GenerateID(ctx, tx, "DirectMaterialReceive", data.Date)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
+	for i := range data.Details {
+		data.Details[i].Source = fmt.Sprintf("%s*%s*%s", data.Date[6:8], data.DocNo, data.Details[i].DNo)
+	}
+
 	query := `INSERT INTO tbldirectmaterialreceivehdr 
 	(
 		DocNo,
@@ -47,24 +84,6 @@ func (t *TblDirectMaterialReceiveRepository) Create(ctx context.Context, data *t
 		data.CreateBy,
 	)
 
-	// transaction begin
-	var err error
-	tx, err := t.DB.BeginTxx(ctx, nil)
-	if err != nil {
-		log.Printf("Failed to start transaction: %+v", err)
-		return nil, fmt.Errorf("error starting transaction: %w", err)
-	}
-
-	// Pastikan rollback dipanggil jika transaksi tidak berhasil
-	defer func() {
-		if err != nil {
-			// Rollback jika error terjadi
-			if rbErr := tx.Rollback(); rbErr != nil {
-				log.Printf("Failed to rollback transaction: %+v", rbErr)
-			}
-		}
-	}()
-
 	// insert header
 	query += strings.Join(placeholders, "")
 	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
@@ -91,55 +110,7 @@ func (t *TblDirectMaterialReceiveRepository) Create(ctx context.Context, data *t
 		placeholders = placeholders[:0]
 		args = args[:0]
 
-		// stock summary query
-		queryStockSummary := `INSERT INTO tblstocksummary (
-			WhsCode,
-			Lot,
-			Bin,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersStockSummary []string
-		var argsStockSummary []interface{}
-
-		// stock movement query
-		queryStockMovement := `INSERT INTO tblstockmovement (
-			DocType,
-			DocNo,
-			DNo,
-			CancelInd,
-			DocDt,
-			WhsCode,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			Remark,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersStockMovement []string
-		var argsStockMovement []interface{}
-
-		// stock history of stock
-		queryHistory := `INSERT INTO tblhistoryofstock (
-			ItCode,
-			BatchNo,
-			Source,
-			CancelInd,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersHistory []string
-		var argsHistory []interface{}
+		var movements []inventoryledger.Movement
 
 		// order report
 		queryOrder := `INSERT INTO tbltransferbetweenwhs (
@@ -175,89 +146,37 @@ func (t *TblDirectMaterialReceiveRepository) Create(ctx context.Context, data *t
 				data.CreateBy,
 			)
 
-			//////////////////////////// TO WAREHOUSE
-			// stock summary
-			placeholdersStockSummary = append(placeholdersStockSummary, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-			argsStockSummary = append(argsStockSummary,
-				data.WhsCodeTo,
-				"-",
-				"-",
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				detail.Qty,
-				0,
-				data.CreateBy,
-				data.Date,
-			)
-
-			// stock movement
-			placeholdersStockMovement = append(placeholdersStockMovement, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-			argsStockMovement = append(argsStockMovement,
-				"Direct Material Receive",
-				data.DocNo,
-				detail.DNo,
-				"N",
-				data.Date,
-				data.WhsCodeTo,
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				detail.Qty,
-				0,
-				data.Remark,
-				data.CreateBy,
-				data.Date,
-			)
-
-			//////////////////////////// FROM WAREHOUSE
-			// stock summary
-			placeholdersStockSummary = append(placeholdersStockSummary, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-			argsStockSummary = append(argsStockSummary,
-				data.WhsCodeFrom,
-				"-",
-				"-",
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				0,
-				detail.Qty,
-				data.CreateBy,
-				data.Date,
-			)
-
-			// stock movement
-			placeholdersStockMovement = append(placeholdersStockMovement, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-			argsStockMovement = append(argsStockMovement,
-				"Direct Material Receive",
-				data.DocNo,
-				detail.DNo,
-				"N",
-				data.Date,
-				data.WhsCodeFrom,
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				0,
-				detail.Qty,
-				data.Remark,
-				data.CreateBy,
-				data.Date,
-			)
-
-			// history of stock
-			placeholdersHistory = append(placeholdersHistory, "(?, ?, ?, ?, ?, ?)")
-			argsHistory = append(argsHistory,
-				detail.ItCode,
-				detail.BatchNo,
-				detail.Source,
-				"N",
-				data.CreateBy,
-				data.Date,
+			movements = append(movements,
+				inventoryledger.Movement{
+					DocType:   "Direct Material Receive",
+					DocNo:     data.DocNo,
+					DNo:       fmt.Sprintf("%03d", i+1),
+					DocDt:     data.Date,
+					WhsCode:   data.WhsCodeTo,
+					Source:    detail.Source,
+					ItCode:    detail.ItCode,
+					BatchNo:   detail.BatchNo,
+					Qty:       detail.Qty,
+					Direction: inventoryledger.In,
+					Remark:    data.Remark,
+					CreateBy:  data.CreateBy,
+					CreateDt:  data.Date,
+				},
+				inventoryledger.Movement{
+					DocType:   "Direct Material Receive",
+					DocNo:     data.DocNo,
+					DNo:       fmt.Sprintf("%03d", i+1),
+					DocDt:     data.Date,
+					WhsCode:   data.WhsCodeFrom,
+					Source:    detail.Source,
+					ItCode:    detail.ItCode,
+					BatchNo:   detail.BatchNo,
+					Qty:       detail.Qty,
+					Direction: inventoryledger.Out,
+					Remark:    data.Remark,
+					CreateBy:  data.CreateBy,
+					CreateDt:  data.Date,
+				},
 			)
 
 			// transfer between whs report
@@ -284,25 +203,8 @@ func (t *TblDirectMaterialReceiveRepository) Create(ctx context.Context, data *t
 			return nil, fmt.Errorf("error Insert Detail: %w", err)
 		}
 
-		// insert stock summary
-		queryStockSummary += strings.Join(placeholdersStockSummary, ",") + `;`
-		if _, err = tx.ExecContext(ctx, queryStockSummary, argsStockSummary...); err != nil {
-			log.Printf("Error insert stock summary: %+v", err)
-			return nil, fmt.Errorf("error Insert Stock Summary: %w", err)
-		}
-
-		// insert stock movement
-		queryStockMovement += strings.Join(placeholdersStockMovement, ",") + ";"
-		if _, err = tx.ExecContext(ctx, queryStockMovement, argsStockMovement...); err != nil {
-			log.Printf("Error insert stock movement: %+v", err)
-			return nil, fmt.Errorf("error Insert Stock Movement: %w", err)
-		}
-
-		// insert history
-		queryHistory += strings.Join(placeholdersHistory, ",") + ";"
-		if _, err = tx.ExecContext(ctx, queryHistory, argsHistory...); err != nil {
-			log.Printf("Error insert history of stock: %+v", err)
-			return nil, fmt.Errorf("error Insert History of Stock: %w", err)
+		if err = t.Ledger.Post(ctx, tx, movements); err != nil {
+			return nil, err
 		}
 
 		// insert order
@@ -338,6 +240,11 @@ func (t *TblDirectMaterialReceiveRepository) Fetch(ctx context.Context, doc, war
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "WhsCodeFrom", "WhsCodeTo"); scope != "" {
+		countQuery += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
 		return nil, fmt.Errorf("error counting records: %w", err)
 	}
@@ -375,6 +282,11 @@ func (t *TblDirectMaterialReceiveRepository) Fetch(ctx context.Context, doc, war
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "t.WhsCodeFrom", "t.WhsCodeTo"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	query += " LIMIT ? OFFSET ?"
 	args = append(args, param.PageSize, offset)
 
@@ -480,18 +392,12 @@ func (t *TblDirectMaterialReceiveRepository) Update(ctx context.Context, lastUpb
 	}
 
 	var (
-		err                                        error
-		resultDetail                               sql.Result
-		rowsAffectedDtl                            int64
-		placeholders, placeholdersStockSummary2    []string
-		placeholdersStockSummary, placeholdersEdit []string
-		placeholdersTransfer, placeholdersMov      []string
-		inTuplesSummaryTo, inTuplesSummaryFrom     []string
-		inTuplesMovHist, inTupleMov                []string
-		args, argsStockSummary, argsStockSummary2  []interface{}
-		argsEdit, argsTransfer, argsMov            []interface{}
-		argsInSumTo, argsInSumFrom                 []interface{}
-		argsInHistMov, argsTuplesMov               []interface{}
+		err                                error
+		resultDetail                       sql.Result
+		rowsAffectedDtl                    int64
+		placeholders, placeholdersTransfer []string
+		args, argsTransfer                 []interface{}
+		movements                          []inventoryledger.Movement
 	)
 
 	tx, err := t.DB.BeginTxx(ctx, nil)
@@ -508,38 +414,29 @@ func (t *TblDirectMaterialReceiveRepository) Update(ctx context.Context, lastUpb
 		}
 	}()
 
+	audit, err := newAuditTrail(ctx, tx, "DirectMaterialReceive", data.DocNo)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkDocWarehouse(ctx, tx, "tbldirectmaterialreceivehdr", "WhsCodeTo", data.DocNo); err != nil {
+		return nil, err
+	}
+
+	existingCancels, err := cancelIndByDNo(ctx, tx, "tbldirectmaterialreceivedtl", data.DocNo)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, detail := range data.Details {
 		placeholders = append(placeholders, " WHEN DocNo = ? AND DNo = ? THEN ? ")
 		args = append(args, data.DocNo, detail.DNo, detail.Cancel)
 
-		// Qty2: FROM WhsCodeTo (barang keluar dari transfer tujuan)
-		placeholdersStockSummary = append(placeholdersStockSummary, " WHEN WhsCode = ? AND Source = ? AND ItCode = ? THEN (Qty2 - ?) ")
-		argsStockSummary = append(argsStockSummary, data.WhsCodeTo, detail.Source, detail.ItCode, detail.Qty)
-		inTuplesSummaryTo = append(inTuplesSummaryTo, "(?, ?, ?)")
-		argsInSumTo = append(argsInSumTo, data.WhsCodeTo, detail.Source, detail.ItCode)
-
-		// Qty3: FROM WhsCodeFrom (barang masuk ke transfer asal)
-		placeholdersStockSummary2 = append(placeholdersStockSummary2, " WHEN WhsCode = ? AND Source = ? AND ItCode = ? THEN (Qty3 - ?) ")
-		argsStockSummary2 = append(argsStockSummary2, data.WhsCodeFrom, detail.Source, detail.ItCode, detail.Qty)
-		inTuplesSummaryFrom = append(inTuplesSummaryFrom, "(?, ?, ?)")
-		argsInSumFrom = append(argsInSumFrom, data.WhsCodeFrom, detail.Source, detail.ItCode)
-
-		// History (tanpa WhsCode)
-		placeholdersEdit = append(placeholdersEdit, " WHEN ItCode = ? AND Source = ? AND BatchNo = ? THEN ? ")
-		argsEdit = append(argsEdit, detail.ItCode, detail.Source, detail.BatchNo, detail.Cancel)
-		inTuplesMovHist = append(inTuplesMovHist, "(?, ?, ?)")
-		argsInHistMov = append(argsInHistMov, detail.ItCode, detail.Source, detail.BatchNo)
-
-		// Stock Movement
-		placeholdersMov = append(placeholdersMov, " WHEN WhsCode = ? AND ItCode = ? AND Source = ? AND BatchNo = ? THEN ? ")
-		argsMov = append(argsMov, data.WhsCodeFrom, detail.ItCode, detail.Source, detail.BatchNo, detail.Cancel)
-		inTupleMov = append(inTupleMov, "(?, ?, ?, ?)")
-		argsTuplesMov = append(argsTuplesMov, data.WhsCodeFrom, detail.ItCode, detail.Source, detail.BatchNo)
-
-		placeholdersMov = append(placeholdersMov, " WHEN WhsCode = ? AND ItCode = ? AND Source = ? AND BatchNo = ? THEN ? ")
-		argsMov = append(argsMov, data.WhsCodeTo, detail.ItCode, detail.Source, detail.BatchNo, detail.Cancel)
-		inTupleMov = append(inTupleMov, "(?, ?, ?, ?)")
-		argsTuplesMov = append(argsTuplesMov, data.WhsCodeTo, detail.ItCode, detail.Source, detail.BatchNo)
+		// reverse stock: masuk ke WhsCodeTo dan keluar dari WhsCodeFrom
+		prevCancel, exists := existingCancels[detail.DNo]
+		if exists && !prevCancel.ToBool() && detail.Cancel.ToBool() {
+			movements = append(movements, reverseLine("Direct Material Receive", data.DocNo, detail.DNo, lastUpby, lastUpDate))
+		}
 
 		// Transfer antar gudang
 		placeholdersTransfer = append(placeholdersTransfer, " WHEN DocNo = ? AND ItCode = ? AND BatchNo = ? THEN ? ")
@@ -563,46 +460,8 @@ func (t *TblDirectMaterialReceiveRepository) Update(ctx context.Context, lastUpb
 		return data, err
 	}
 
-	// Update Qty2
-	query = `UPDATE tblstocksummary SET Qty2 = CASE ` + strings.Join(placeholdersStockSummary, " ") + ` ELSE Qty2 END
-		WHERE (WhsCode, Source, ItCode) IN (` + strings.Join(inTuplesSummaryTo, ",") + `)`
-	argsStockSummary = append(argsStockSummary, argsInSumTo...)
-	if _, err := tx.ExecContext(ctx, query, argsStockSummary...); err != nil {
-		return nil, fmt.Errorf("error updating stock summary Qty2: %w", err)
-	}
-
-	// Update Qty3
-	query = `UPDATE tblstocksummary SET Qty3 = CASE ` + strings.Join(placeholdersStockSummary2, " ") + ` ELSE Qty3 END
-		WHERE (WhsCode, Source, ItCode) IN (` + strings.Join(inTuplesSummaryFrom, ",") + `)`
-	argsStockSummary2 = append(argsStockSummary2, argsInSumFrom...)
-	if _, err := tx.ExecContext(ctx, query, argsStockSummary2...); err != nil {
-		return nil, fmt.Errorf("error updating stock summary Qty3: %w", err)
-	}
-
-	// Update history of stock
-	// Build the correct number of "(?, ?, ?)" placeholders for the IN clause
-	// numTuples := len(argsInHistMov) / 4 * 2 // Each detail adds 2 tuples (from and to)
-	// inPlaceholders := make([]string, 0, numTuples)
-	// for i := 0; i < numTuples; i++ {
-	// 	inPlaceholders = append(inPlaceholders, "(?, ?, ?)")
-	// }
-	query = `UPDATE tblhistoryofstock SET CancelInd = CASE ` + strings.Join(placeholdersEdit, " ") + ` ELSE CancelInd END
-		WHERE (ItCode, Source, BatchNo) IN (` + strings.Join(inTuplesMovHist, ",") + `)`
-	argsEdit = append(argsEdit, argsInHistMov...)
-
-	fmt.Println("query detail: ", query)
-	fmt.Println("args detail: ", argsEdit)
-	if _, err := tx.ExecContext(ctx, query, argsEdit...); err != nil {
-		return nil, fmt.Errorf("error updating history of stock: %w", err)
-	}
-
-	// Update stock movement
-	query = `UPDATE tblstockmovement SET CancelInd = CASE ` + strings.Join(placeholdersMov, " ") + ` ELSE CancelInd END
-		WHERE (WhsCode, ItCode, Source, BatchNo) IN (` + strings.Join(inTupleMov, ",") + `)`
-	
-	argsMov = append(argsMov, argsTuplesMov...)
-	if _, err := tx.ExecContext(ctx, query, argsMov...); err != nil {
-		return nil, fmt.Errorf("error updating stock movement: %w", err)
+	if err = t.Ledger.Reverse(ctx, tx, movements); err != nil {
+		return nil, err
 	}
 
 	// Update transfer antar gudang
@@ -614,8 +473,7 @@ func (t *TblDirectMaterialReceiveRepository) Update(ctx context.Context, lastUpb
 	}
 
 	// Insert log
-	query = `INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)`
-	if _, err = tx.ExecContext(ctx, query, lastUpby, data.DocNo, "DirectMaterialReceive", lastUpDate); err != nil {
+	if err = audit.Write(ctx, tx, lastUpby, lastUpDate); err != nil {
 		return nil, fmt.Errorf("error inserting log activity: %w", err)
 	}
 
@@ -10,18 +10,72 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/exchangerate"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
+	"gitlab.com/ayaka/internal/domain/journal"
 	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/domain/taxengine"
 	"gitlab.com/ayaka/internal/domain/tbldirectpurchasercv"
 
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
 
 type TblDirectPurchaseRcvRepository struct {
-	DB *repository.Sqlx `inject:"database"`
+	DB     *repository.Sqlx            `inject:"database"`
+	Ledger inventoryledger.Repository  `inject:"inventoryLedgerRepository"`
+	ID     *formatid.GenerateIDHandler `inject:"generateID"`
+	Rate   exchangerate.Repository     `inject:"exchangeRateRepository"`
+	Tax     taxengine.Repository        `inject:"taxEngineRepository"`
+	Journal journal.Repository          `inject:"journalRepository"`
 }
 
 func (t *TblDirectPurchaseRcvRepository) Create(ctx context.Context, data *tbldirectpurchasercv.Create) (*tbldirectpurchasercv.Create, error) {
+	if err := checkWarehouse(ctx, data.WhsCode); err != nil {
+		return nil, err
+	}
+	if err := checkSite(ctx, data.SiteCode.String); err != nil {
+		return nil, err
+	}
+
+	// harga pokok di ledger selalu dalam base currency
+	rates, err := t.Rate.Rates(ctx, []string{data.CurCode}, data.BaseCurrency, data.Date)
+	if err != nil {
+		return nil, err
+	}
+	rate, ok := rates[data.CurCode]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s to %s on %s", customerrors.ErrExchangeRate, data.CurCode, data.BaseCurrency, data.Date)
+	}
+
+	// transaction begin
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	// Pastikan rollback dipanggil jika transaksi tidak berhasil
+	defer func() {
+		if err != nil {
+			// Rollback jika error terjadi
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	data.DocNo, err = t.ID.GenerateIDForSite(ctx, tx, "DirectPurchaseReceive", data.SiteCode.String, data.Date)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
+	for i := range data.Details {
+		data.Details[i].Source = fmt.Sprintf("%s*%s*%s", data.Date[6:8], data.DocNo, data.Details[i].DNo)
+	}
+
 	query := `INSERT INTO tbldirectpurchasercvhdr 
 	(
 		DocNo,
@@ -33,6 +87,7 @@ func (t *TblDirectPurchaseRcvRepository) Create(ctx context.Context, data *tbldi
 		TermOfPayment,
 		CurCode,
 		TaxCode,
+		TaxInclusiveInd,
 		Remark,
 		CreateDt,
 		CreateBy
@@ -41,7 +96,7 @@ func (t *TblDirectPurchaseRcvRepository) Create(ctx context.Context, data *tbldi
 	var args []interface{}
 	var placeholders []string
 
-	placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);")
+	placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);")
 	args = append(args,
 		data.DocNo,
 		data.Date,
@@ -52,29 +107,12 @@ func (t *TblDirectPurchaseRcvRepository) Create(ctx context.Context, data *tbldi
 		data.TermOfPayment,
 		data.CurCode,
 		data.TaxCode,
+		data.TaxInclusive,
 		data.Remark,
 		data.CreateDt,
 		data.CreateBy,
 	)
 
-	// transaction begin
-	var err error
-	tx, err := t.DB.BeginTxx(ctx, nil)
-	if err != nil {
-		log.Printf("Failed to start transaction: %+v", err)
-		return nil, fmt.Errorf("error starting transaction: %w", err)
-	}
-
-	// Pastikan rollback dipanggil jika transaksi tidak berhasil
-	defer func() {
-		if err != nil {
-			// Rollback jika error terjadi
-			if rbErr := tx.Rollback(); rbErr != nil {
-				log.Printf("Failed to rollback transaction: %+v", rbErr)
-			}
-		}
-	}()
-
 	// insert header
 	query += strings.Join(placeholders, "")
 	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
@@ -82,13 +120,37 @@ func (t *TblDirectPurchaseRcvRepository) Create(ctx context.Context, data *tbldi
 		return nil, fmt.Errorf("error Insert Header: %w", err)
 	}
 
-	var taxRate float32 = 0.0
-	if data.TaxCode.String != "" {
-		queryTax := `SELECT TaxRate FROM tbltax WHERE TaxCode = ?`
-		if err = tx.GetContext(ctx, &taxRate, queryTax, data.TaxCode); err != nil {
-			log.Printf("Error get tax rate: %+v", err)
-			return nil, fmt.Errorf("error get tax rate: %w", err)
+	// pajak per baris, default dari TaxCode header lalu tax group vendor
+	taxReq := &taxengine.Request{
+		VendorCode: data.VendorCode,
+		TaxCode:    data.TaxCode.String,
+		Inclusive:  data.TaxInclusive.ToBool(),
+	}
+	for _, detail := range data.Details {
+		taxReq.Lines = append(taxReq.Lines, taxengine.Line{
+			DNo:      detail.DNo,
+			Amount:   float64(detail.Qty * detail.Price),
+			TaxCodes: detail.TaxCodes,
+		})
+	}
+	if data.TaxBreakdown, err = t.Tax.Apply(ctx, tx, taxengine.DirectPurchaseReceive, data.DocNo, taxReq); err != nil {
+		return nil, err
+	}
+	data.GrandTotal = float32(data.TaxBreakdown.GrandTotal)
+
+	// order report menyimpan total tarif PPN (non-withholding) per baris,
+	// jurnal pajak per baris dalam base currency (PPh negatif)
+	taxRates := make(map[string]float64)
+	taxAmounts := make(map[string]float64)
+	baseAmounts := make(map[string]float64)
+	for _, line := range data.TaxBreakdown.Lines {
+		baseAmounts[line.DNo] = line.BaseAmount
+		if line.Withholding.ToBool() {
+			taxAmounts[line.DNo] -= line.TaxAmount
+			continue
 		}
+		taxRates[line.DNo] += line.TaxRate
+		taxAmounts[line.DNo] += line.TaxAmount
 	}
 
 	if len(data.Details) > 0 {
@@ -109,56 +171,8 @@ func (t *TblDirectPurchaseRcvRepository) Create(ctx context.Context, data *tbldi
 		placeholders = placeholders[:0]
 		args = args[:0]
 
-		// stock summary query
-		queryStockSummary := `INSERT INTO tblstocksummary (
-			WhsCode,
-			Lot,
-			Bin,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersStockSummary []string
-		var argsStockSummary []interface{}
-
-		// stock movement query
-		queryStockMovement := `INSERT INTO tblstockmovement (
-			DocType,
-			DocNo,
-			DNo,
-			CancelInd,
-			DocDt,
-			WhsCode,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			Remark,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersStockMovement []string
-		var argsStockMovement []interface{}
-
-		// stock history of stock
-		queryHistory := `INSERT INTO tblhistoryofstock (
-			ItCode,
-			BatchNo,
-			Source,
-			CancelInd,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersHistory []string
-		var argsHistory []interface{}
-
+		var movements []inventoryledger.Movement
+		var taxes []journal.Tax
 
 		// order report
 		queryOrder := `INSERT INTO tblorderreport (
@@ -191,52 +205,40 @@ func (t *TblDirectPurchaseRcvRepository) Create(ctx context.Context, data *tbldi
 				data.CreateBy,
 			)
 
-			// stock summary
-			placeholdersStockSummary = append(placeholdersStockSummary, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-			argsStockSummary = append(argsStockSummary,
-				data.WhsCode,
-				"-",
-				"-",
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				detail.Qty,
-				0,
-				data.CreateBy,
-				data.Date,
-			)
-
-			// stock movement
-			placeholdersStockMovement = append(placeholdersStockMovement, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-			argsStockMovement = append(argsStockMovement,
-				"Direct Purchase Receive",
-				data.DocNo,
-				detail.DNo,
-				detail.Cancel,
-				data.Date,
-				data.WhsCode,
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				detail.Qty,
-				0,
-				data.Remark,
-				data.CreateBy,
-				data.Date,
-			)
+			// harga inclusive sudah termasuk pajak, persediaan dinilai dari DPP
+			unitCost := detail.Price * float32(rate)
+			if base, ok := baseAmounts[detail.DNo]; ok && data.TaxInclusive.ToBool() && detail.Qty > 0 {
+				unitCost = float32(base*rate) / detail.Qty
+			}
 
-			// history of stock
-			placeholdersHistory = append(placeholdersHistory, "(?, ?, ?, ?, ?, ?)")
-			argsHistory = append(argsHistory,
-				detail.ItCode,
-				detail.BatchNo,
-				detail.Source,
-				"N",
-				data.CreateBy,
-				data.Date,
-			)
+			movements = append(movements, inventoryledger.Movement{
+				DocType:   "Direct Purchase Receive",
+				DocNo:     data.DocNo,
+				DNo:       fmt.Sprintf("%03d", i+1),
+				DocDt:     data.Date,
+				WhsCode:   data.WhsCode,
+				Source:    detail.Source,
+				ItCode:    detail.ItCode,
+				BatchNo:   detail.BatchNo,
+				Qty:       detail.Qty,
+				Direction: inventoryledger.In,
+				UnitCost:  unitCost,
+				Remark:    data.Remark,
+				CreateBy:  data.CreateBy,
+				CreateDt:  data.Date,
+			})
+
+			taxes = append(taxes, journal.Tax{
+				DocType:  "Direct Purchase Receive",
+				DocNo:    data.DocNo,
+				DNo:      fmt.Sprintf("%03d", i+1),
+				DocDt:    data.Date,
+				WhsCode:  data.WhsCode,
+				ItCode:   detail.ItCode,
+				Amount:   float32(taxAmounts[detail.DNo] * rate),
+				CreateBy: data.CreateBy,
+				CreateDt: data.CreateDt,
+			})
 
 			// order report
 			placeholdersOrder = append(placeholdersOrder, "(?, ?, ?, ?, ?, ?, ?, ?, ?)")
@@ -248,7 +250,7 @@ func (t *TblDirectPurchaseRcvRepository) Create(ctx context.Context, data *tbldi
 				detail.BatchNo,
 				detail.Qty,
 				detail.Price,
-				taxRate,
+				taxRates[detail.DNo],
 				data.Date,
 			)
 		}
@@ -260,25 +262,11 @@ func (t *TblDirectPurchaseRcvRepository) Create(ctx context.Context, data *tbldi
 			return nil, fmt.Errorf("error Insert Detail: %w", err)
 		}
 
-		// insert stock summary
-		queryStockSummary += strings.Join(placeholdersStockSummary, ",") + `;`
-		if _, err = tx.ExecContext(ctx, queryStockSummary, argsStockSummary...); err != nil {
-			log.Printf("Error insert stock summary: %+v", err)
-			return nil, fmt.Errorf("error Insert Stock Summary: %w", err)
-		}
-
-		// insert stock movement
-		queryStockMovement += strings.Join(placeholdersStockMovement, ",") + ";"
-		if _, err = tx.ExecContext(ctx, queryStockMovement, argsStockMovement...); err != nil {
-			log.Printf("Error insert stock movement: %+v", err)
-			return nil, fmt.Errorf("error Insert Stock Movement: %w", err)
+		if err = t.Ledger.Post(ctx, tx, movements); err != nil {
+			return nil, err
 		}
-
-		// insert history
-		queryHistory += strings.Join(placeholdersHistory, ",") + ";"
-		if _, err = tx.ExecContext(ctx, queryHistory, argsHistory...); err != nil {
-			log.Printf("Error insert history of stock: %+v", err)
-			return nil, fmt.Errorf("error Insert History of Stock: %w", err)
+		if err = t.Journal.PostTax(ctx, tx, taxes); err != nil {
+			return nil, err
 		}
 
 		// insert order
@@ -313,6 +301,16 @@ func (t *TblDirectPurchaseRcvRepository) Fetch(ctx context.Context, doc, warehou
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "WhsCode"); scope != "" {
+		countQuery += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
+	if scope, scopeArgs := siteScope(ctx, "SiteCode"); scope != "" {
+		countQuery += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
 		return nil, fmt.Errorf("error counting records: %w", err)
 	}
@@ -344,6 +342,7 @@ func (t *TblDirectPurchaseRcvRepository) Fetch(ctx context.Context, doc, warehou
 				t.TermOfPayment,
 				t.CurCode,
 				t.TaxCode,
+				t.TaxInclusiveInd,
 				t.Remark
 			FROM tbldirectpurchasercvhdr t
 			JOIN tblwarehouse w ON t.WhsCode = w.WhsCode
@@ -358,6 +357,16 @@ func (t *TblDirectPurchaseRcvRepository) Fetch(ctx context.Context, doc, warehou
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "t.WhsCode"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
+	if scope, scopeArgs := siteScope(ctx, "t.SiteCode"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	query += " LIMIT ? OFFSET ?"
 	args = append(args, param.PageSize, offset)
 
@@ -432,31 +441,42 @@ func (t *TblDirectPurchaseRcvRepository) Fetch(ctx context.Context, doc, warehou
 		detailMap[d.DocNo] = append(detailMap[d.DocNo], *d)
 	}
 
+	breakdowns, err := t.Tax.Breakdowns(ctx, taxengine.DirectPurchaseReceive, docsNo)
+	if err != nil {
+		return nil, err
+	}
+
 	// Hitung TotalQuantity dan GrandTotal + Pajak
 	for _, h := range data {
 		h.Details = detailMap[h.DocNo]
 
-		var subtotal float32 = 0.0
-		var taxRate float32 = 0.0
 		var count float32 = 0.0
 
-		// Ambil tax rate dari tbltax
-		if h.TaxCode.String != "" {	
-			queryTax := `SELECT TaxRate FROM tbltax WHERE TaxCode = ?`
-			if err := t.DB.GetContext(ctx, &taxRate, queryTax, h.TaxCode.String); err != nil {
-				return nil, fmt.Errorf("error fetching tax rate for %s: %w", h.TaxCode.String, err)
-			}
-		}
-
+		// baris yang dibatalkan tidak ikut dihitung
+		var lines []taxengine.Line
 		for i := range h.Details {
 			d := &h.Details[i]
-			subtotal += d.Price * d.Qty
+			if d.Cancel.ToBool() {
+				continue
+			}
+			lines = append(lines, taxengine.Line{DNo: d.DNo, Amount: float64(d.Price * d.Qty)})
 			count += float32(d.Qty)
 		}
 
-		taxAmount := subtotal * taxRate
-		h.GrandTotal = subtotal + taxAmount
-		h.TotalTax = taxAmount
+		if taxes, ok := breakdowns[h.DocNo]; ok {
+			h.TaxBreakdown = taxengine.Summarize(h.TaxInclusive.ToBool(), lines, activeLineTaxes(lines, taxes))
+		} else {
+			// dokumen sebelum ada tbldoctaxdtl dihitung ulang dari TaxCode header
+			if h.TaxBreakdown, err = t.Tax.Calculate(ctx, &taxengine.Request{
+				TaxCode:   h.TaxCode.String,
+				Inclusive: h.TaxInclusive.ToBool(),
+				Lines:     lines,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		h.TotalTax = float32(h.TaxBreakdown.TotalTax)
+		h.GrandTotal = float32(h.TaxBreakdown.GrandTotal)
 		h.TotalQuantity = count
 	}
 
@@ -477,8 +497,10 @@ func (t *TblDirectPurchaseRcvRepository) Update(ctx context.Context, lastUpby, l
 	var resultDetail sql.Result
 	var rowsAffectedDtl int64
 
-	var placeholders, placeholdersStockSummary, placeholdersEdit, inTuples []string
-	var args, argsStockSummary, argsEdit, argsIn, argsInSum []interface{}
+	var placeholders, placeholdersEdit, inTuples []string
+	var args, argsEdit, argsIn []interface{}
+	var movements []inventoryledger.Movement
+	var cancelled []string
 
 	var err error
 
@@ -504,22 +526,37 @@ func (t *TblDirectPurchaseRcvRepository) Update(ctx context.Context, lastUpby, l
 		}
 	}()
 
+	audit, err := newAuditTrail(ctx, tx, "DirectPurchaseReceive", data.DocNo)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkDocWarehouse(ctx, tx, "tbldirectpurchasercvhdr", "WhsCode", data.DocNo); err != nil {
+		return nil, err
+	}
+
+	existingCancels, err := cancelIndByDNo(ctx, tx, "tbldirectpurchasercvdtl", data.DocNo)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, detail := range data.Details {
 		// detail cancel
 		placeholders = append(placeholders, ` WHEN DocNo = ? AND DNo = ? THEN ? `)
 		args = append(args, data.DocNo, detail.DNo, detail.Cancel)
 
-		// min qty
-		placeholdersStockSummary = append(placeholdersStockSummary, ` WHEN WhsCode = ? AND Source = ? AND ItCode = ? THEN (Qty2 - ?) `)
-		argsStockSummary = append(argsStockSummary, data.WhsCode, detail.Source, detail.ItCode, detail.Qty)
+		prevCancel, exists := existingCancels[detail.DNo]
+		if exists && !prevCancel.ToBool() && detail.Cancel.ToBool() {
+			movements = append(movements, reverseLine("Direct Purchase Receive", data.DocNo, detail.DNo, lastUpby, lastUpDate))
+			cancelled = append(cancelled, detail.DNo)
+		}
 
-		// edit cancel status on history of stock and stock movement and order report
+		// edit cancel status on order report
 		placeholdersEdit = append(placeholdersEdit, ` WHEN  ItCode = ? AND Source = ? AND BatchNo = ? THEN ? `)
 		argsEdit = append(argsEdit, detail.ItCode, detail.Source, detail.BatchNo, detail.Cancel)
 		
 		inTuples = append(inTuples, "(?, ?, ?)")
 		argsIn = append(argsIn, detail.ItCode, detail.Source, detail.BatchNo)
-		argsInSum = append(argsInSum, data.WhsCode, detail.Source, detail.ItCode)
 	}
 
 	query := `UPDATE tbldirectpurchasercvdtl
@@ -546,54 +583,15 @@ func (t *TblDirectPurchaseRcvRepository) Update(ctx context.Context, lastUpby, l
 		return data, err
 	}
 
-	// Update stock summary
-	query = `UPDATE tblstocksummary
-		SET Qty2 = CASE
-			` + strings.Join(placeholdersStockSummary, " ") + `
-			ELSE Qty2
-		END
-		WHERE (WhsCode, Source, ItCode) IN (` + strings.Join(inTuples, ",") + `)
-	`
-	argsStockSummary = append(argsStockSummary, argsInSum...)
-
-	if _, err := tx.ExecContext(ctx, query, argsStockSummary...); err != nil {
-		log.Printf("Failed to update stock summary: %+v", err)
-		return nil, fmt.Errorf("error updating stock summary: %w", err)
+	if err = t.Ledger.Reverse(ctx, tx, movements); err != nil {
+		return nil, err
 	}
-
-	// Update history of stock
-	query = `UPDATE tblhistoryofstock
-		SET CancelInd = CASE
-			` + strings.Join(placeholdersEdit, " ") + `
-			ELSE CancelInd
-		END
-		WHERE (ItCode, Source, BatchNo) IN (` + strings.Join(inTuples, ",") + `)
-	`
-
-	fmt.Println("Query history of stock: ", query)
-	argsEdit = append(argsEdit, argsIn...)
-	fmt.Println("args history of stock: ", argsEdit)
-	if _, err := tx.ExecContext(ctx, query, argsEdit...); err != nil {
-		log.Printf("Failed to update history of stock: %+v", err)
-		return nil, fmt.Errorf("error updating history of stock: %w", err)
-	}
-
-	// Update stock movement
-	query = `UPDATE tblstockmovement
-		SET CancelInd = CASE
-			` + strings.Join(placeholdersEdit, " ") + `
-			ELSE CancelInd
-		END
-		WHERE (ItCode, Source, BatchNo) IN (` + strings.Join(inTuples, ",") + `)
-	`
-	fmt.Println("Query stock sum: ", query)
-	fmt.Println("args stock sum: ", argsEdit)
-	if _, err := tx.ExecContext(ctx, query, argsEdit...); err != nil {
-		log.Printf("Failed to update stock movement: %+v", err)
-		return nil, fmt.Errorf("error updating stock movement: %w", err)
+	if err = t.Journal.ReverseTax(ctx, tx, "Direct Purchase Receive", data.DocNo, cancelled, lastUpby, lastUpDate); err != nil {
+		return nil, err
 	}
 
 	// Update order report
+	argsEdit = append(argsEdit, argsIn...)
 	query = `UPDATE tblorderreport
 		SET CancelInd = CASE
 			` + strings.Join(placeholdersEdit, " ") + `
@@ -609,10 +607,8 @@ func (t *TblDirectPurchaseRcvRepository) Update(ctx context.Context, lastUpby, l
 	}
 
 	// Update log activity
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
 	fmt.Println("--Update Log--")
-	if _, err = tx.ExecContext(ctx, query, lastUpby, data.DocNo, "DirectPurchaseReceive", lastUpDate); err != nil {
+	if err = audit.Write(ctx, tx, lastUpby, lastUpDate); err != nil {
 		log.Printf("Detailed error: %+v", err)
 		return nil, fmt.Errorf("error inserting log activity: %w", err)
 	}
@@ -10,24 +10,88 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/batch"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
 	share "gitlab.com/ayaka/internal/domain/shared"
-	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+	"gitlab.com/ayaka/internal/domain/taxengine"
 	"gitlab.com/ayaka/internal/domain/tbldirectsalesdelivery"
+	"gitlab.com/ayaka/internal/domain/tblmastercustomer"
 
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
 
+// TblDirectSalesDeliveryRepository pengiriman penjualan langsung, baris bisa
+// merujuk ke baris sales order.
+//
+//	ALTER TABLE tbldirectsalesdelivhdr ADD COLUMN CustCode VARCHAR(16) NULL AFTER WhsCode;
+//	ALTER TABLE tbldirectsalesdelivdtl
+//		ADD COLUMN SalesOrderDocNo VARCHAR(30) NULL,
+//		ADD COLUMN SalesOrderDNo VARCHAR(3) NULL;
 type TblDirectSalesDeliveryRepository struct {
-	DB *repository.Sqlx `inject:"database"`
+	DB     *repository.Sqlx            `inject:"database"`
+	Ledger inventoryledger.Repository  `inject:"inventoryLedgerRepository"`
+	ID     *formatid.GenerateIDHandler `inject:"generateID"`
+	Batch  batch.Repository            `inject:"batchRepository"`
+	Tax    taxengine.Repository        `inject:"taxEngineRepository"`
 }
 
 func (t *TblDirectSalesDeliveryRepository) Create(ctx context.Context, data *tbldirectsalesdelivery.Create) (*tbldirectsalesdelivery.Create, error) {
+	if err := checkWarehouse(ctx, data.WhsCode); err != nil {
+		return nil, err
+	}
+
+	// transaction begin
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	// Pastikan rollback dipanggil jika transaksi tidak berhasil
+	defer func() {
+		if err != nil {
+			// Rollback jika error terjadi
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	// batch kedaluwarsa tidak boleh dikirim ke customer
+	lines := make([]batch.Line, 0, len(data.Details))
+	for _, d := range data.Details {
+		lines = append(lines, batch.Line{ItemCode: d.ItCode, Batch: d.BatchNo})
+	}
+	if err = t.Batch.CheckExpired(ctx, tx, data.Date, lines); err != nil {
+		return nil, err
+	}
+
+	if data.CustomerCode.String != "" {
+		if err = t.fillCustomer(ctx, tx, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = t.checkSalesOrderLines(ctx, tx, data); err != nil {
+		return nil, err
+	}
+
+	data.DocNo, err = t.ID.GenerateID(ctx, tx, "DirectSalesDelivery", data.Date)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
 	query := `INSERT INTO tbldirectsalesdelivhdr 
 	(
 		DocNo,
 		DocDt,
 		WhsCode,
+		CustCode,
 		CustomerName,
 		Address,
 		CityCode,
@@ -35,7 +99,9 @@ func (t *TblDirectSalesDeliveryRepository) Create(ctx context.Context, data *tbl
 		Phone,
 		Email,
 		Mobile,
+		NPWP,
 		TaxCode,
+		TaxInclusiveInd,
 		Remark,
 		CreateDt,
 		CreateBy
@@ -44,11 +110,12 @@ func (t *TblDirectSalesDeliveryRepository) Create(ctx context.Context, data *tbl
 	var args []interface{}
 	var placeholders []string
 
-	placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);")
+	placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);")
 	args = append(args,
 		data.DocNo,
 		data.Date,
 		data.WhsCode,
+		data.CustomerCode,
 		data.Customer,
 		data.Address,
 		data.CityCode,
@@ -56,30 +123,14 @@ func (t *TblDirectSalesDeliveryRepository) Create(ctx context.Context, data *tbl
 		data.Phone,
 		data.Email,
 		data.Mobile,
+		data.NPWP,
 		data.TaxCode,
+		data.TaxInclusive,
 		data.Remark,
 		data.CreateDt,
 		data.CreateBy,
 	)
 
-	// transaction begin
-	var err error
-	tx, err := t.DB.BeginTxx(ctx, nil)
-	if err != nil {
-		log.Printf("Failed to start transaction: %+v", err)
-		return nil, fmt.Errorf("error starting transaction: %w", err)
-	}
-
-	// Pastikan rollback dipanggil jika transaksi tidak berhasil
-	defer func() {
-		if err != nil {
-			// Rollback jika error terjadi
-			if rbErr := tx.Rollback(); rbErr != nil {
-				log.Printf("Failed to rollback transaction: %+v", rbErr)
-			}
-		}
-	}()
-
 	// insert header
 	query += strings.Join(placeholders, "")
 	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
@@ -99,6 +150,8 @@ func (t *TblDirectSalesDeliveryRepository) Create(ctx context.Context, data *tbl
 			Stock,
 			Qty,
 			Source,
+			SalesOrderDocNo,
+			SalesOrderDNo,
 			CreateDt,
 			CreateBy
 		) VALUES `
@@ -106,59 +159,11 @@ func (t *TblDirectSalesDeliveryRepository) Create(ctx context.Context, data *tbl
 		placeholders = placeholders[:0]
 		args = args[:0]
 
-		// stock summary query
-		queryStockSummary := `INSERT INTO tblstocksummary (
-			WhsCode,
-			Lot,
-			Bin,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersStockSummary []string
-		var argsStockSummary []interface{}
-
-		// stock movement query
-		queryStockMovement := `INSERT INTO tblstockmovement (
-			DocType,
-			DocNo,
-			DNo,
-			CancelInd,
-			DocDt,
-			WhsCode,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			Remark,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersStockMovement []string
-		var argsStockMovement []interface{}
-
-		// history of stock
-		queryHistory := `INSERT INTO tblhistoryofstock (
-			ItCode,
-			BatchNo,
-			Source,
-			CancelInd,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersHistory []string
-		var argsHistory []interface{}
+		var movements []inventoryledger.Movement
 
 		for i, detail := range data.Details {
 			// detail
-			placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+			placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
 			args = append(args,
 				data.DocNo,
 				fmt.Sprintf("%03d", i+1),
@@ -169,56 +174,27 @@ func (t *TblDirectSalesDeliveryRepository) Create(ctx context.Context, data *tbl
 				detail.Stock,
 				detail.Qty,
 				detail.Source,
+				detail.SalesOrderDocNo,
+				detail.SalesOrderDNo,
 				data.CreateDt,
 				data.CreateBy,
 			)
 
-			// stock summary
-			placeholdersStockSummary = append(placeholdersStockSummary, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-			argsStockSummary = append(argsStockSummary,
-				data.WhsCode,
-				"-",
-				"-",
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				0,
-				detail.Qty,
-				data.CreateBy,
-				data.Date,
-			)
-
-			// stock movement
-			placeholdersStockMovement = append(placeholdersStockMovement, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-			argsStockMovement = append(argsStockMovement,
-				"Direct Sales Delivery",
-				data.DocNo,
-				detail.DNo,
-				detail.Cancel,
-				data.Date,
-				data.WhsCode,
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				0,
-				detail.Qty,
-				data.Remark,
-				data.CreateBy,
-				data.Date,
-			)
-
-			// history of stock
-			placeholdersHistory = append(placeholdersHistory, "(?, ?, ?, ?, ?, ?)")
-			argsHistory = append(argsHistory,
-				detail.ItCode,
-				detail.BatchNo,
-				detail.Source,
-				"N",
-				data.CreateBy,
-				data.Date,
-			)
+			movements = append(movements, inventoryledger.Movement{
+				DocType:   "Direct Sales Delivery",
+				DocNo:     data.DocNo,
+				DNo:       fmt.Sprintf("%03d", i+1),
+				DocDt:     data.Date,
+				WhsCode:   data.WhsCode,
+				Source:    detail.Source,
+				ItCode:    detail.ItCode,
+				BatchNo:   detail.BatchNo,
+				Qty:       detail.Qty,
+				Direction: inventoryledger.Out,
+				Remark:    data.Remark,
+				CreateBy:  data.CreateBy,
+				CreateDt:  data.Date,
+			})
 		}
 
 		// insert detail
@@ -228,26 +204,25 @@ func (t *TblDirectSalesDeliveryRepository) Create(ctx context.Context, data *tbl
 			return nil, fmt.Errorf("error Insert Detail: %w", err)
 		}
 
-		// insert stock summary
-		queryStockSummary += strings.Join(placeholdersStockSummary, ",") + `;`
-		if _, err = tx.ExecContext(ctx, queryStockSummary, argsStockSummary...); err != nil {
-			log.Printf("Error insert stock summary: %+v", err)
-			return nil, fmt.Errorf("error Insert Stock Summary: %w", err)
+		if err = t.Ledger.Post(ctx, tx, movements); err != nil {
+			return nil, err
 		}
+	}
 
-		// insert stock movement
-		queryStockMovement += strings.Join(placeholdersStockMovement, ",") + ";"
-		if _, err = tx.ExecContext(ctx, queryStockMovement, argsStockMovement...); err != nil {
-			log.Printf("Error insert stock movement: %+v", err)
-			return nil, fmt.Errorf("error Insert Stock Movement: %w", err)
-		}
-		
-		// insert history
-		queryHistory += strings.Join(placeholdersHistory, ",") + ";"
-		if _, err = tx.ExecContext(ctx, queryHistory, argsHistory...); err != nil {
-			log.Printf("Error insert history of stock: %+v", err)
-			return nil, fmt.Errorf("error Insert History of Stock: %w", err)
-		}
+	taxReq := &taxengine.Request{
+		CustomerCode: data.CustomerCode.String,
+		TaxCode:      data.TaxCode.String,
+		Inclusive:    data.TaxInclusive.ToBool(),
+	}
+	for i, detail := range data.Details {
+		taxReq.Lines = append(taxReq.Lines, taxengine.Line{
+			DNo:      fmt.Sprintf("%03d", i+1),
+			Amount:   float64(detail.Price * detail.Qty),
+			TaxCodes: detail.TaxCodes,
+		})
+	}
+	if data.TaxBreakdown, err = t.Tax.Apply(ctx, tx, taxengine.DirectSalesDelivery, data.DocNo, taxReq); err != nil {
+		return nil, err
 	}
 
 	// Commit the transaction
@@ -259,6 +234,115 @@ func (t *TblDirectSalesDeliveryRepository) Create(ctx context.Context, data *tbl
 	return data, nil
 }
 
+// fillCustomer mengisi data customer dari master, alamat kirim menimpa alamat utama
+func (t *TblDirectSalesDeliveryRepository) fillCustomer(ctx context.Context, tx *sqlx.Tx, data *tbldirectsalesdelivery.Create) error {
+	var customer tblmastercustomer.Detail
+	query := "SELECT CustCode, CustName, CustCatCode, Address, CityCode, PostalCode, Phone, Mobile, Email, Remark, NPWP FROM tblcustomerhdr WHERE CustCode = ?"
+	if err := tx.GetContext(ctx, &customer, query, data.CustomerCode.String); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: customer %s not found", customerrors.ErrInvalidInput, data.CustomerCode.String)
+		}
+		return fmt.Errorf("error get customer: %w", err)
+	}
+
+	data.Customer = customer.CustomerName
+	data.Address = customer.Address
+	data.CityCode = nulldatatype.NewNullStringDataType(customer.CityCode)
+	data.PostalCode = customer.PostalCode
+	data.Phone = customer.Phone
+	data.Email = customer.Email
+	data.Mobile = customer.Mobile
+	if !data.NPWP.Valid {
+		data.NPWP = customer.NPWP
+	}
+
+	if data.AddressDNo == "" {
+		return nil
+	}
+
+	var address tblmastercustomer.AddressCustomer
+	query = "SELECT CustCode, DNo, Name, Address, CityCode, PostalCode, Phone, Active FROM tbladdresscustomerdtl WHERE CustCode = ? AND DNo = ? AND Active = 'Y'"
+	if err := tx.GetContext(ctx, &address, query, data.CustomerCode.String, data.AddressDNo); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: customer address %s not found", customerrors.ErrInvalidInput, data.AddressDNo)
+		}
+		return fmt.Errorf("error get customer address: %w", err)
+	}
+
+	data.Address = address.Address
+	data.CityCode = nulldatatype.NewNullStringDataType(address.CityCode)
+	data.PostalCode = address.PostalCode
+	if address.Phone.Valid {
+		data.Phone = address.Phone
+	}
+
+	return nil
+}
+
+// checkSalesOrderLines memastikan baris yang merujuk sales order milik customer
+// yang sama, itemnya sama dan qty tidak melebihi sisa order
+func (t *TblDirectSalesDeliveryRepository) checkSalesOrderLines(ctx context.Context, tx *sqlx.Tx, data *tbldirectsalesdelivery.Create) error {
+	type orderLine struct {
+		DocNo        string  `db:"DocNo"`
+		DNo          string  `db:"DNo"`
+		CustCode     string  `db:"CustCode"`
+		CancelInd    string  `db:"CancelInd"`
+		ItCode       string  `db:"ItCode"`
+		Qty          float32 `db:"Qty"`
+		DeliveredQty float32 `db:"DeliveredQty"`
+	}
+
+	// sisa qty dan item per baris SO, baris yang sama bisa muncul lebih dari sekali
+	remaining := map[string]float32{}
+	items := map[string]string{}
+	for _, d := range data.Details {
+		if d.SalesOrderDocNo.String == "" {
+			continue
+		}
+		if data.CustomerCode.String == "" {
+			return fmt.Errorf("%w: customer is required for sales order delivery", customerrors.ErrInvalidInput)
+		}
+
+		key := d.SalesOrderDocNo.String + "*" + d.SalesOrderDNo.String
+		left, ok := remaining[key]
+		if !ok {
+			var line orderLine
+			query := `SELECT d.DocNo, d.DNo, h.CustCode, d.CancelInd, d.ItCode, d.Qty, ` + salesOrderDeliveredQty + ` AS DeliveredQty
+				FROM tblsalesorderdtl d
+				JOIN tblsalesorderhdr h ON d.DocNo = h.DocNo
+				WHERE d.DocNo = ? AND d.DNo = ?
+				FOR UPDATE`
+			if err := tx.GetContext(ctx, &line, query, d.SalesOrderDocNo.String, d.SalesOrderDNo.String); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fmt.Errorf("%w: sales order line %s not found", customerrors.ErrInvalidInput, key)
+				}
+				return fmt.Errorf("error get sales order line: %w", err)
+			}
+
+			switch {
+			case line.CancelInd == "Y":
+				return fmt.Errorf("%w: sales order line %s is cancelled", customerrors.ErrInvalidInput, key)
+			case line.CustCode != data.CustomerCode.String:
+				return fmt.Errorf("%w: sales order %s belongs to another customer", customerrors.ErrInvalidInput, line.DocNo)
+			}
+			left = line.Qty - line.DeliveredQty
+			remaining[key] = left
+			items[key] = line.ItCode
+		}
+
+		if items[key] != d.ItCode {
+			return fmt.Errorf("%w: item %s does not match sales order line %s", customerrors.ErrInvalidInput, d.ItCode, key)
+		}
+
+		if d.Qty > left {
+			return fmt.Errorf("%w: quantity exceeds outstanding sales order line %s", customerrors.ErrInvalidInput, key)
+		}
+		remaining[key] = left - d.Qty
+	}
+
+	return nil
+}
+
 func (t *TblDirectSalesDeliveryRepository) Fetch(ctx context.Context, doc, warehouse, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
 	var totalRecords int
 
@@ -274,6 +358,11 @@ func (t *TblDirectSalesDeliveryRepository) Fetch(ctx context.Context, doc, wareh
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "WhsCode"); scope != "" {
+		countQuery += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
 		return nil, fmt.Errorf("error counting records: %w", err)
 	}
@@ -298,6 +387,7 @@ func (t *TblDirectSalesDeliveryRepository) Fetch(ctx context.Context, doc, wareh
 				t.DocDt,
 				t.WhsCode,
 				w.WhsName,
+				t.CustCode,
 				t.CustomerName,
 				t.Address,
 				t.CityCode,
@@ -305,7 +395,10 @@ func (t *TblDirectSalesDeliveryRepository) Fetch(ctx context.Context, doc, wareh
 				t.Phone,
 				t.Email,
 				t.Mobile,
+				t.NPWP,
 				t.TaxCode,
+				t.TaxInclusiveInd,
+				t.TaxInvoiceNo,
 				t.Remark
 			FROM tbldirectsalesdelivhdr t
 			JOIN tblwarehouse w ON t.WhsCode = w.WhsCode
@@ -318,6 +411,11 @@ func (t *TblDirectSalesDeliveryRepository) Fetch(ctx context.Context, doc, wareh
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "t.WhsCode"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	query += " LIMIT ? OFFSET ?"
 	args = append(args, param.PageSize, offset)
 
@@ -369,6 +467,8 @@ func (t *TblDirectSalesDeliveryRepository) Fetch(ctx context.Context, doc, wareh
 				d.Qty,
 				d.Stock,
 				d.Price,
+				d.SalesOrderDocNo,
+				d.SalesOrderDNo,
 				i.ItName,
 				u.UomName
 			FROM tbldirectsalesdelivdtl d
@@ -393,11 +493,15 @@ func (t *TblDirectSalesDeliveryRepository) Fetch(ctx context.Context, doc, wareh
 		detailMap[d.DocNo] = append(detailMap[d.DocNo], *d)
 	}
 
+	breakdowns, err := t.Tax.Breakdowns(ctx, taxengine.DirectSalesDelivery, docsNo)
+	if err != nil {
+		return nil, err
+	}
+
 	// Hitung TotalQuantity dan GrandTotal + Pajak
 	for _, h := range data {
 		h.Details = detailMap[h.DocNo]
 
-		var subtotal float32 = 0.0
 		var taxRate float32 = 0.0
 		var count float32 = 0.0
 
@@ -409,14 +513,31 @@ func (t *TblDirectSalesDeliveryRepository) Fetch(ctx context.Context, doc, wareh
 			}
 		}
 
+		h.TaxRate = taxRate
+
+		var lines []taxengine.Line
 		for i := range h.Details {
 			d := &h.Details[i]
-			subtotal += d.Price * d.Qty
 			count += float32(d.Qty)
+			if d.Cancel.ToBool() {
+				continue
+			}
+			lines = append(lines, taxengine.Line{DNo: d.DNo, Amount: float64(d.Price * d.Qty)})
 		}
 
-		taxAmount := subtotal * taxRate
-		h.TotalAmount = subtotal + taxAmount
+		if taxes, ok := breakdowns[h.DocNo]; ok {
+			h.TaxBreakdown = taxengine.Summarize(h.TaxInclusive.ToBool(), lines, activeLineTaxes(lines, taxes))
+		} else {
+			// dokumen sebelum ada tbldoctaxdtl dihitung ulang dari TaxCode header
+			if h.TaxBreakdown, err = t.Tax.Calculate(ctx, &taxengine.Request{
+				TaxCode:   h.TaxCode.String,
+				Inclusive: h.TaxInclusive.ToBool(),
+				Lines:     lines,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		h.TotalAmount = float32(h.TaxBreakdown.GrandTotal)
 		h.TotalQuantity = count
 	}
 
@@ -437,8 +558,9 @@ func (t *TblDirectSalesDeliveryRepository) Update(ctx context.Context, lastUpby,
 	var resultDetail sql.Result
 	var rowsAffectedDtl int64
 
-	var placeholders, placeholdersStockSummary, placeholdersEdit, inTuples, inTuplesSum []string
-	var args, argsStockSummary, argsEdit, argsIn, argsInSum []interface{}
+	var placeholders []string
+	var args []interface{}
+	var movements []inventoryledger.Movement
 
 	var err error
 
@@ -464,22 +586,18 @@ func (t *TblDirectSalesDeliveryRepository) Update(ctx context.Context, lastUpby,
 		}
 	}()
 
-	existingCancels := make(map[string]booldatatype.BoolDataType)
-
-	querySelect := `SELECT DNo, CancelInd FROM tbldirectsalesdelivdtl WHERE DocNo = ?`
-	rows, err := t.DB.QueryxContext(ctx, querySelect, data.DocNo)
+	audit, err := newAuditTrail(ctx, tx, "DirectSalesDelivery", data.DocNo)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching existing cancel status: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var dno string
-		var cancel booldatatype.BoolDataType
-		if err := rows.Scan(&dno, &cancel); err != nil {
-			return nil, fmt.Errorf("error scanning cancel status: %w", err)
-		}
-		existingCancels[dno] = cancel
+	if err = checkDocWarehouse(ctx, tx, "tbldirectsalesdelivhdr", "WhsCode", data.DocNo); err != nil {
+		return nil, err
+	}
+
+	existingCancels, err := cancelIndByDNo(ctx, tx, "tbldirectsalesdelivdtl", data.DocNo)
+	if err != nil {
+		return nil, err
 	}
 
 	for _, detail := range data.Details {
@@ -487,23 +605,10 @@ func (t *TblDirectSalesDeliveryRepository) Update(ctx context.Context, lastUpby,
 		placeholders = append(placeholders, ` WHEN DocNo = ? AND DNo = ? THEN ? `)
 		args = append(args, data.DocNo, detail.DNo, detail.Cancel)
 
-		// stock sum
 		prevCancel, exists := existingCancels[detail.DNo]
 		if exists && !prevCancel.ToBool() && detail.Cancel.ToBool() {
-			// Jika sebelumnya false dan sekarang true, maka kurangi Qty3
-			placeholdersStockSummary = append(placeholdersStockSummary,
-				` WHEN WhsCode = ? AND Source = ? AND ItCode = ? THEN (Qty3 - ?) `)
-			argsStockSummary = append(argsStockSummary, data.WhsCode, detail.Source, detail.ItCode, detail.Qty)
-			inTuplesSum = append(inTuplesSum, "(?, ?, ?)")
-			argsInSum = append(argsInSum, data.WhsCode, detail.Source, detail.ItCode)
+			movements = append(movements, reverseLine("Direct Sales Delivery", data.DocNo, detail.DNo, lastUpby, lastUpDate))
 		}
-
-		// edit cancel status on history of stock and stock movement
-		placeholdersEdit = append(placeholdersEdit, ` WHEN  ItCode = ? AND Source = ? AND BatchNo = ? THEN ? `)
-		argsEdit = append(argsEdit, detail.ItCode, detail.Source, detail.BatchNo, detail.Cancel)
-
-		inTuples = append(inTuples, "(?, ?, ?)")
-		argsIn = append(argsIn, detail.ItCode, detail.Source, detail.BatchNo)
 	}
 
 	query := `UPDATE tbldirectsalesdelivdtl
@@ -530,61 +635,13 @@ func (t *TblDirectSalesDeliveryRepository) Update(ctx context.Context, lastUpby,
 		return data, err
 	}
 
-	// Update stock summary
-	query = `UPDATE tblstocksummary
-		SET Qty3 = CASE
-			` + strings.Join(placeholdersStockSummary, " ") + `
-			ELSE Qty3
-		END
-		WHERE (WhsCode, Source, ItCode) IN (` + strings.Join(inTuplesSum, ",") + `)
-	`
-	argsStockSummary = append(argsStockSummary, argsInSum...)
-
-	fmt.Println("query sum: ", query)
-	fmt.Println("args stock sum: ", argsStockSummary)
-
-	if _, err := tx.ExecContext(ctx, query, argsStockSummary...); err != nil {
-		log.Printf("Failed to update stock summary: %+v", err)
-		return nil, fmt.Errorf("error updating stock summary: %w", err)
-	}
-
-	// Update history of stock
-	query = `UPDATE tblhistoryofstock
-		SET CancelInd = CASE
-			` + strings.Join(placeholdersEdit, " ") + `
-			ELSE CancelInd
-		END
-		WHERE (ItCode, Source, BatchNo) IN (` + strings.Join(inTuples, ",") + `)
-	`
-
-	fmt.Println("Query history of stock: ", query)
-	argsEdit = append(argsEdit, argsIn...)
-	fmt.Println("args history of stock: ", argsEdit)
-	if _, err := tx.ExecContext(ctx, query, argsEdit...); err != nil {
-		log.Printf("Failed to update history of stock: %+v", err)
-		return nil, fmt.Errorf("error updating history of stock: %w", err)
-	}
-
-	// Update stock movement
-	query = `UPDATE tblstockmovement
-		SET CancelInd = CASE
-			` + strings.Join(placeholdersEdit, " ") + `
-			ELSE CancelInd
-		END
-		WHERE (ItCode, Source, BatchNo) IN (` + strings.Join(inTuples, ",") + `)
-	`
-	fmt.Println("Query stock sum: ", query)
-	fmt.Println("args stock sum: ", argsEdit)
-	if _, err := tx.ExecContext(ctx, query, argsEdit...); err != nil {
-		log.Printf("Failed to update stock movement: %+v", err)
-		return nil, fmt.Errorf("error updating stock movement: %w", err)
+	if err = t.Ledger.Reverse(ctx, tx, movements); err != nil {
+		return nil, err
 	}
 
 	// Update log activity
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
 	fmt.Println("--Update Log--")
-	if _, err = tx.ExecContext(ctx, query, lastUpby, data.DocNo, "DirectSalesDelivery", lastUpDate); err != nil {
+	if err = audit.Write(ctx, tx, lastUpby, lastUpDate); err != nil {
 		log.Printf("Detailed error: %+v", err)
 		return nil, fmt.Errorf("error inserting log activity: %w", err)
 	}
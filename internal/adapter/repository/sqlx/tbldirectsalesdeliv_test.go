@@ -0,0 +1,125 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+	"gitlab.com/ayaka/internal/domain/tbldirectsalesdelivery"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+const (
+	querySalesOrderLine         = "SELECT d.DocNo, d.DNo, h.CustCode, d.CancelInd, d.ItCode, d.Qty, COALESCE(( SELECT SUM(s.Qty) FROM tbldirectsalesdelivdtl s WHERE s.SalesOrderDocNo = d.DocNo AND s.SalesOrderDNo = d.DNo AND s.CancelInd = 'N' ), 0) AS DeliveredQty FROM tblsalesorderdtl d JOIN tblsalesorderhdr h ON d.DocNo = h.DocNo WHERE d.DocNo = ? AND d.DNo = ? FOR UPDATE"
+	queryDirectSalesDelivHeader = "INSERT INTO tbldirectsalesdelivhdr ( DocNo, DocDt, WhsCode, CustCode, CustomerName, Address, CityCode, PostalCode, Phone, Email, Mobile, NPWP, TaxCode, TaxInclusiveInd, Remark, CreateDt, CreateBy ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
+	queryDirectSalesDelivDetail = "INSERT INTO tbldirectsalesdelivdtl ( DocNo, DNo, CancelInd, ItCode, BatchNo, Price, Stock, Qty, Source, SalesOrderDocNo, SalesOrderDNo, CreateDt, CreateBy ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
+)
+
+type TblDirectSalesDeliveryRepositorySuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	repo    *TblDirectSalesDeliveryRepository
+	db      *sqlx.DB
+}
+
+func (suite *TblDirectSalesDeliveryRepositorySuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.repo = &TblDirectSalesDeliveryRepository{
+		DB:     &repository.Sqlx{DB: suite.db},
+		Ledger: &InventoryLedgerRepository{},
+		ID:     &formatid.GenerateIDHandler{},
+		Batch:  fakeBatch{},
+	}
+}
+
+func (suite *TblDirectSalesDeliveryRepositorySuite) TearDownTest() {
+	suite.db.Close()
+}
+
+// dua baris ke baris SO yang sama dijumlah terhadap sisa order
+func (suite *TblDirectSalesDeliveryRepositorySuite) TestCheckSalesOrderLines_OverDelivery() {
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectQuery(querySalesOrderLine).
+		WithArgs("SO1", "001").
+		WillReturnRows(sqlmock.NewRows([]string{"DocNo", "DNo", "CustCode", "CancelInd", "ItCode", "Qty", "DeliveredQty"}).
+			AddRow("SO1", "001", "C001", "N", "IT001", 10, 4))
+
+	tx, err := suite.db.Beginx()
+	suite.Require().NoError(err)
+
+	soLine := func(qty float32) tbldirectsalesdelivery.Detail {
+		return tbldirectsalesdelivery.Detail{
+			ItCode:          "IT001",
+			Qty:             qty,
+			SalesOrderDocNo: nulldatatype.NewNullStringDataType("SO1"),
+			SalesOrderDNo:   nulldatatype.NewNullStringDataType("001"),
+		}
+	}
+	err = suite.repo.checkSalesOrderLines(context.Background(), tx, &tbldirectsalesdelivery.Create{
+		CustomerCode: nulldatatype.NewNullStringDataType("C001"),
+		Details:      []tbldirectsalesdelivery.Detail{soLine(4), soLine(3)},
+	})
+
+	suite.ErrorIs(err, customerrors.ErrInvalidInput)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// baris kedua ke baris SO yang sama tetap dicek itemnya walau baris SO sudah di-cache
+func (suite *TblDirectSalesDeliveryRepositorySuite) TestCheckSalesOrderLines_CachedLineItemMismatch() {
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectQuery(querySalesOrderLine).
+		WithArgs("SO1", "001").
+		WillReturnRows(sqlmock.NewRows([]string{"DocNo", "DNo", "CustCode", "CancelInd", "ItCode", "Qty", "DeliveredQty"}).
+			AddRow("SO1", "001", "C001", "N", "IT001", 10, 0))
+
+	tx, err := suite.db.Beginx()
+	suite.Require().NoError(err)
+
+	soLine := func(itCode string) tbldirectsalesdelivery.Detail {
+		return tbldirectsalesdelivery.Detail{
+			ItCode:          itCode,
+			Qty:             1,
+			SalesOrderDocNo: nulldatatype.NewNullStringDataType("SO1"),
+			SalesOrderDNo:   nulldatatype.NewNullStringDataType("001"),
+		}
+	}
+	err = suite.repo.checkSalesOrderLines(context.Background(), tx, &tbldirectsalesdelivery.Create{
+		CustomerCode: nulldatatype.NewNullStringDataType("C001"),
+		Details:      []tbldirectsalesdelivery.Detail{soLine("IT001"), soLine("IT002")},
+	})
+
+	suite.ErrorIs(err, customerrors.ErrInvalidInput)
+	suite.ErrorContains(err, "item IT002")
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// barang dikirim dari Source yang dipilih dari stok
+func (suite *TblDirectSalesDeliveryRepositorySuite) TestCreate_KeepsPickedSource() {
+	suite.mockSQL.ExpectBegin()
+	expectDocNo(suite.mockSQL, "DirectSalesDelivery")
+	suite.mockSQL.ExpectExec(queryDirectSalesDelivHeader).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryDirectSalesDelivDetail).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPickedSourceLock(suite.mockSQL, "WH1", "IT001", "B1", "15*0001/R1/PMR/01/26*001")
+
+	_, err := suite.repo.Create(context.Background(), &tbldirectsalesdelivery.Create{
+		Date:    "20260120",
+		WhsCode: "WH1",
+		Details: []tbldirectsalesdelivery.Detail{{ItCode: "IT001", BatchNo: "B1", Source: "15*0001/R1/PMR/01/26*001", Qty: 1, Price: 1000}},
+	})
+
+	suite.ErrorIs(err, customerrors.ErrInsufficientStock)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func TestTblDirectSalesDeliveryRepository(t *testing.T) {
+	suite.Run(t, new(TblDirectSalesDeliveryRepositorySuite))
+}
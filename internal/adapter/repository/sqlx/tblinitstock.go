@@ -10,12 +10,13 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/tblinitialstock"
 	"gitlab.com/ayaka/internal/domain/tblinitialstockdtl"
 	"gitlab.com/ayaka/internal/domain/tblmasteritem"
 
-	// "gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
 
 	// "gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
@@ -23,7 +24,9 @@ import (
 )
 
 type TblInitStockRepository struct {
-	DB *repository.Sqlx `inject:"database"`
+	DB     *repository.Sqlx            `inject:"database"`
+	Ledger inventoryledger.Repository  `inject:"inventoryLedgerRepository"`
+	ID     *formatid.GenerateIDHandler `inject:"generateID"`
 }
 
 func (t *TblInitStockRepository) Fetch(ctx context.Context, doc, warehouse, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -41,6 +44,11 @@ func (t *TblInitStockRepository) Fetch(ctx context.Context, doc, warehouse, star
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "WhsCode"); scope != "" {
+		countQuery += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
 		return nil, fmt.Errorf("error counting records: %w", err)
 	}
@@ -85,6 +93,11 @@ func (t *TblInitStockRepository) Fetch(ctx context.Context, doc, warehouse, star
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "i.WhsCode"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	query += " LIMIT ? OFFSET ?"
 	args = append(args, param.PageSize, offset)
 
@@ -195,6 +208,10 @@ func (t *TblInitStockRepository) Detail(ctx context.Context, docNo string) (*tbl
 		return nil, fmt.Errorf("error detail header init stock: %w", err)
 	}
 
+	if err := checkWarehouse(ctx, header.WarehouseCode); err != nil {
+		return nil, customerrors.ErrDataNotFound
+	}
+
 	query = `SELECT
 		d.DNo AS DNo,
 		d.CancelInd AS CancelInd,
@@ -230,6 +247,38 @@ func (t *TblInitStockRepository) Detail(ctx context.Context, docNo string) (*tbl
 }
 
 func (t *TblInitStockRepository) Create(ctx context.Context, data *tblinitialstock.Create) (*tblinitialstock.Create, error) {
+	if err := checkWarehouse(ctx, data.WarehouseCode); err != nil {
+		return nil, err
+	}
+
+	// transaction begin
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	// Pastikan rollback dipanggil jika transaksi tidak berhasil
+	defer func() {
+		if err != nil {
+			// Rollback jika error terjadi
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	data.DocNo, err = t.ID.GenerateID(ctx, tx, "StockInitial", data.Date)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
+	for i := range data.Detail {
+		data.Detail[i].Source = fmt.Sprintf("%s*%s*%s", data.Date[6:8], data.DocNo, data.Detail[i].DNo)
+	}
+
 	query := `INSERT INTO tblstockinitialhdr 
 	(
 		DocNo,
@@ -257,24 +306,6 @@ func (t *TblInitStockRepository) Create(ctx context.Context, data *tblinitialsto
 		data.CreateBy,
 	)
 
-	// transaction begin
-	var err error
-	tx, err := t.DB.BeginTxx(ctx, nil)
-	if err != nil {
-		log.Printf("Failed to start transaction: %+v", err)
-		return nil, fmt.Errorf("error starting transaction: %w", err)
-	}
-
-	// Pastikan rollback dipanggil jika transaksi tidak berhasil
-	defer func() {
-		if err != nil {
-			// Rollback jika error terjadi
-			if rbErr := tx.Rollback(); rbErr != nil {
-				log.Printf("Failed to rollback transaction: %+v", rbErr)
-			}
-		}
-	}()
-
 	// insert header
 	query += strings.Join(placeholders, "")
 	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
@@ -303,55 +334,7 @@ func (t *TblInitStockRepository) Create(ctx context.Context, data *tblinitialsto
 		placeholders = placeholders[:0]
 		args = args[:0]
 
-		// stock summary query
-		queryStockSummary := `INSERT INTO tblstocksummary (
-			WhsCode,
-			Lot,
-			Bin,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersStockSummary []string
-		var argsStockSummary []interface{}
-
-		// stock movement query
-		queryStockMovement := `INSERT INTO tblstockmovement (
-			DocType,
-			DocNo,
-			DNo,
-			CancelInd,
-			DocDt,
-			WhsCode,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			Remark,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersStockMovement []string
-		var argsStockMovement []interface{}
-
-		// stock history of stock
-		queryHistory := `INSERT INTO tblhistoryofstock (
-			ItCode,
-			BatchNo,
-			Source,
-			CancelInd,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersHistory []string
-		var argsHistory []interface{}
+		var movements []inventoryledger.Movement
 
 		for i, detail := range data.Detail {
 			// detail
@@ -372,52 +355,22 @@ func (t *TblInitStockRepository) Create(ctx context.Context, data *tblinitialsto
 				data.CreateBy,
 			)
 
-			// stock summary
-			placeholdersStockSummary = append(placeholdersStockSummary, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-			argsStockSummary = append(argsStockSummary,
-				data.WarehouseCode,
-				"-",
-				"-",
-				detail.Source,
-				detail.ItemCode,
-				detail.Batch,
-				detail.Quantity,
-				0,
-				0,
-				data.CreateBy,
-				data.Date,
-			)
-
-			// stock movement
-			placeholdersStockMovement = append(placeholdersStockMovement, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-			argsStockMovement = append(argsStockMovement,
-				"Initial Stock",
-				data.DocNo,
-				detail.DNo,
-				detail.Cancel,
-				data.Date,
-				data.WarehouseCode,
-				detail.Source,
-				detail.ItemCode,
-				detail.Batch,
-				detail.Quantity,
-				0,
-				0,
-				data.Remark,
-				data.CreateBy,
-				data.Date,
-			)
-
-			// history of stock
-			placeholdersHistory = append(placeholdersHistory, "(?, ?, ?, ?, ?, ?)")
-			argsHistory = append(argsHistory,
-				detail.ItemCode,
-				detail.Batch,
-				detail.Source,
-				"N",
-				data.CreateBy,
-				data.Date,
-			)
+			movements = append(movements, inventoryledger.Movement{
+				DocType:   "Initial Stock",
+				DocNo:     data.DocNo,
+				DNo:       fmt.Sprintf("%03d", i+1),
+				DocDt:     data.Date,
+				WhsCode:   data.WarehouseCode,
+				Source:    detail.Source,
+				ItCode:    detail.ItemCode,
+				BatchNo:   detail.Batch,
+				Qty:       detail.Quantity,
+				Direction: inventoryledger.Initial,
+				UnitCost:  detail.Price * data.Rate,
+				Remark:    data.Remark,
+				CreateBy:  data.CreateBy,
+				CreateDt:  data.Date,
+			})
 		}
 
 		// insert detail
@@ -427,25 +380,8 @@ func (t *TblInitStockRepository) Create(ctx context.Context, data *tblinitialsto
 			return nil, fmt.Errorf("error Insert Detail: %w", err)
 		}
 
-		// insert stock summary
-		queryStockSummary += strings.Join(placeholdersStockSummary, ",") + `;`
-		if _, err = tx.ExecContext(ctx, queryStockSummary, argsStockSummary...); err != nil {
-			log.Printf("Error insert stock summary: %+v", err)
-			return nil, fmt.Errorf("error Insert Stock Summary: %w", err)
-		}
-
-		// insert stock movement
-		queryStockMovement += strings.Join(placeholdersStockMovement, ",") + ";"
-		if _, err = tx.ExecContext(ctx, queryStockMovement, argsStockMovement...); err != nil {
-			log.Printf("Error insert stock movement: %+v", err)
-			return nil, fmt.Errorf("error Insert Stock Movement: %w", err)
-		}
-
-		// insert history
-		queryHistory += strings.Join(placeholdersHistory, ",") + ";"
-		if _, err = tx.ExecContext(ctx, queryHistory, argsHistory...); err != nil {
-			log.Printf("Error insert history of stock: %+v", err)
-			return nil, fmt.Errorf("error Insert History of Stock: %w", err)
+		if err = t.Ledger.Post(ctx, tx, movements); err != nil {
+			return nil, err
 		}
 	}
 
@@ -462,8 +398,9 @@ func (t *TblInitStockRepository) Update(ctx context.Context, lastUpby, lastUpDat
 	var resultDetail sql.Result
 	var rowsAffectedDtl int64
 
-	var placeholders, placeholdersStockSummary, placeholdersEdit, inTuples []string
-	var args, argsStockSummary, argsEdit, argsIn, argsInSum []interface{}
+	var placeholders []string
+	var args []interface{}
+	var movements []inventoryledger.Movement
 
 	var err error
 
@@ -489,22 +426,29 @@ func (t *TblInitStockRepository) Update(ctx context.Context, lastUpby, lastUpDat
 		}
 	}()
 
+	audit, err := newAuditTrail(ctx, tx, "StockInitial", data.DocNo)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkDocWarehouse(ctx, tx, "tblstockinitialhdr", "WhsCode", data.DocNo); err != nil {
+		return nil, err
+	}
+
+	existingCancels, err := cancelIndByDNo(ctx, tx, "tblstockinitialdtl", data.DocNo)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, detail := range data.Detail {
 		// detail cancel
 		placeholders = append(placeholders, ` WHEN DocNo = ? AND DNo = ? THEN ? `)
 		args = append(args, data.DocNo, detail.DNo, detail.Cancel)
 
-		// min qty
-		placeholdersStockSummary = append(placeholdersStockSummary, ` WHEN WhsCode = ? AND Source = ? AND ItCode = ? THEN (Qty - ?) `)
-		argsStockSummary = append(argsStockSummary, data.WarehouseCode, detail.Source, detail.ItemCode, detail.Quantity)
-
-		// edit cancel status on history of stock and stock movement
-		placeholdersEdit = append(placeholdersEdit, ` WHEN  ItCode = ? AND Source = ? AND BatchNo = ? THEN ? `)
-		argsEdit = append(argsEdit, detail.ItemCode, detail.Source, detail.Batch, detail.Cancel)
-		
-		inTuples = append(inTuples, "(?, ?, ?)")
-		argsIn = append(argsIn, detail.ItemCode, detail.Source, detail.Batch)
-		argsInSum = append(argsInSum, data.WarehouseCode, detail.Source, detail.ItemCode)
+		prevCancel, exists := existingCancels[detail.DNo]
+		if exists && !prevCancel.ToBool() && detail.Cancel.ToBool() {
+			movements = append(movements, reverseLine("Initial Stock", data.DocNo, detail.DNo, lastUpby, lastUpDate))
+		}
 	}
 
 	query := `UPDATE tblstockinitialdtl
@@ -531,58 +475,13 @@ func (t *TblInitStockRepository) Update(ctx context.Context, lastUpby, lastUpDat
 		return data, err
 	}
 
-	// Update stock summary
-	query = `UPDATE tblstocksummary
-		SET Qty = CASE
-			` + strings.Join(placeholdersStockSummary, " ") + `
-			ELSE Qty
-		END
-		WHERE (WhsCode, Source, ItCode) IN (` + strings.Join(inTuples, ",") + `)
-	`
-	argsStockSummary = append(argsStockSummary, argsInSum...)
-
-	if _, err := tx.ExecContext(ctx, query, argsStockSummary...); err != nil {
-		log.Printf("Failed to update stock summary: %+v", err)
-		return nil, fmt.Errorf("error updating stock summary: %w", err)
-	}
-
-	// Update history of stock
-	query = `UPDATE tblhistoryofstock
-		SET CancelInd = CASE
-			` + strings.Join(placeholdersEdit, " ") + `
-			ELSE CancelInd
-		END
-		WHERE (ItCode, Source, BatchNo) IN (` + strings.Join(inTuples, ",") + `)
-	`
-
-	fmt.Println("Query history of stock: ", query)
-	argsEdit = append(argsEdit, argsIn...)
-	fmt.Println("args history of stock: ", argsEdit)
-	if _, err := tx.ExecContext(ctx, query, argsEdit...); err != nil {
-		log.Printf("Failed to update history of stock: %+v", err)
-		return nil, fmt.Errorf("error updating history of stock: %w", err)
-	}
-
-	// Update stock movement
-	query = `UPDATE tblstockmovement
-		SET CancelInd = CASE
-			` + strings.Join(placeholdersEdit, " ") + `
-			ELSE CancelInd
-		END
-		WHERE (ItCode, Source, BatchNo) IN (` + strings.Join(inTuples, ",") + `)
-	`
-	fmt.Println("Query stock sum: ", query)
-	fmt.Println("args stock sum: ", argsEdit)
-	if _, err := tx.ExecContext(ctx, query, argsEdit...); err != nil {
-		log.Printf("Failed to update stock movement: %+v", err)
-		return nil, fmt.Errorf("error updating stock movement: %w", err)
+	if err = t.Ledger.Reverse(ctx, tx, movements); err != nil {
+		return nil, err
 	}
 
 	// Update log activity
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
 	fmt.Println("--Update Log--")
-	if _, err = tx.ExecContext(ctx, query, lastUpby, data.DocNo, "StockInitial", lastUpDate); err != nil {
+	if err = audit.Write(ctx, tx, lastUpby, lastUpDate); err != nil {
 		log.Printf("Detailed error: %+v", err)
 		return nil, fmt.Errorf("error inserting log activity: %w", err)
 	}
@@ -596,8 +495,6 @@ func (t *TblInitStockRepository) Update(ctx context.Context, lastUpby, lastUpDat
 	return data, nil
 }
 
-
-
 // func (t *TblInitStockRepository) Create(ctx context.Context, data *tblinitialstock.Create) (*tblinitialstock.Create, error) {
 // 	countDetail := len(data.Detail)
 // 	var args []interface{}
@@ -61,7 +61,8 @@ func (t *TblItemCatRepository) FetchItemCat(ctx context.Context, name string, pa
 				i.AcNo5,
 				c5.AcDesc AS AcDesc5,
 				i.AcNo6,
-				c6.AcDesc AS AcDesc6
+				c6.AcDesc AS AcDesc6,
+				i.CostMethod
 				FROM tblitemcategory i
 				LEFT JOIN tblcoa c1 ON i.AcNo = c1.AcNo
 				LEFT JOIN tblcoa c2 ON i.AcNo2= c2.AcNo
@@ -110,6 +111,7 @@ func (t *TblItemCatRepository) FetchItemCat(ctx context.Context, name string, pa
 			CoaPurchaseReturnDesc:  itemCategory.CoaPurchaseReturnDesc,
 			CoaConsumptionCost:     itemCategory.CoaConsumptionCost,
 			CoaConsumptionCostDesc: itemCategory.CoaConsumptionCostDesc,
+			CostMethod:             itemCategory.CostMethod,
 		}
 	}
 
@@ -139,11 +141,12 @@ func (t *TblItemCatRepository) Create(ctx context.Context, data *tblitemcategory
 					AcNo4,
 					AcNo5,
 					AcNo6,
+					CostMethod,
 					CreateDt,
 					CreateBy
 				)
 				VALUES
-				(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+				(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err := t.DB.ExecContext(ctx, query,
 		data.ItemCategoryCode,
@@ -155,6 +158,7 @@ func (t *TblItemCatRepository) Create(ctx context.Context, data *tblitemcategory
 		data.CoaSalesReturn,
 		data.CoaPurchaseReturn,
 		data.CoaConsumptionCost,
+		data.CostMethod,
 		data.CreateDate,
 		data.CreateBy,
 	)
@@ -176,7 +180,8 @@ func (t *TblItemCatRepository) Update(ctx context.Context, data *tblitemcategory
 					AcNo3,
 					AcNo4,
 					AcNo5,
-					AcNo6
+					AcNo6,
+					CostMethod
 				FROM tblitemcategory WHERE ItCtCode = ?`
 
 	var check tblitemcategory.ReadTblItemCategory
@@ -195,6 +200,7 @@ func (t *TblItemCatRepository) Update(ctx context.Context, data *tblitemcategory
 			AcNo4 = ?,
 			AcNo5 = ?,
 			AcNo6 = ?,
+			CostMethod = COALESCE(NULLIF(?, ''), CostMethod),
 			LastUpDt = ?,
 			LastUpBy = ?
 			WHERE ItCtCode = ?`
@@ -205,6 +211,14 @@ func (t *TblItemCatRepository) Update(ctx context.Context, data *tblitemcategory
 		return nil, fmt.Errorf("error starting transaction: %w", err)
 	}
 
+	audit, err := newAuditTrail(ctx, tx, "ItemCategory", data.ItemCategoryCode)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Failed to rollback transaction: %+v", rbErr)
+		}
+		return nil, err
+	}
+
 	_, err = tx.ExecContext(ctx, query,
 		data.ItemCategoryName,
 		data.Active,
@@ -214,6 +228,7 @@ func (t *TblItemCatRepository) Update(ctx context.Context, data *tblitemcategory
 		data.CoaSalesReturn,
 		data.CoaPurchaseReturn,
 		data.CoaConsumptionCost,
+		data.CostMethod,
 		data.LastUpdateDate,
 		data.LastUpdateBy,
 		data.ItemCategoryCode)
@@ -229,9 +244,7 @@ func (t *TblItemCatRepository) Update(ctx context.Context, data *tblitemcategory
 		return nil, fmt.Errorf("error updating item's category: %w", err)
 	}
 
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
-	_, err = tx.ExecContext(ctx, query, data.LastUpdateBy, data.ItemCategoryCode, "ItemCategory", data.LastUpdateDate)
+	err = audit.Write(ctx, tx, data.LastUpdateBy, data.LastUpdateDate)
 
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
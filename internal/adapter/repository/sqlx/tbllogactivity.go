@@ -2,8 +2,7 @@ package sqlx
 
 import (
 	"context"
-	"database/sql"
-	"errors"
+	"encoding/json"
 	"fmt"
 	"log"
 
@@ -11,52 +10,154 @@ import (
 	"gitlab.com/ayaka/internal/domain/logactivity"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
 )
 
 type TblLogRepository struct {
 	DB *repository.Sqlx `inject:"database"`
 }
 
-func (t *TblLogRepository) GetActivityLog(ctx context.Context, code, category string) ([]*logactivity.LogActivity, error) {
-	table, err := logactivity.TableOf(category)
-	if err != nil {
-		return nil, err
+// GetActivityLog mengurutkan perubahan dari yang terbaru. Baris "created"
+// selalu paling lama sehingga ikut dihitung sebagai baris terakhir halaman.
+func (t *TblLogRepository) GetActivityLog(ctx context.Context, filter *logactivity.Filter, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	var created []*logactivity.LogActivity
+
+	// baris "created" hanya bisa dibuat jika data yang diminta spesifik
+	if filter.Category != "" && filter.Code != "" {
+		table, err := logactivity.TableOf(filter.Category)
+		if err != nil {
+			return nil, err
+		}
+		primKey, err := logactivity.PrimaryKeyOf(filter.Category)
+		if err != nil {
+			return nil, err
+		}
+
+		var create []*logactivity.LogActivity
+		query := fmt.Sprintf(`SELECT CreateDt AS Date,
+				CONCAT(CreateBy, ' created this data') AS Log,
+				? AS Category,
+				%s AS Code,
+				CreateBy AS UserCode,
+				CreateBy AS UserName,
+				'' AS RequestId
+			FROM %s WHERE %s = ?`, primKey, table, primKey)
+		if err := t.DB.SelectContext(ctx, &create, query, filter.Category, filter.Code); err != nil {
+			return nil, fmt.Errorf("error log activity: %w", err)
+		}
+		if create == nil {
+			return nil, customerrors.ErrDataNotFound
+		}
+
+		if (filter.UserCode == "" || filter.UserCode == create[0].UserCode) && inDateRange(create[0].Date, filter) {
+			created = create
+		}
+	} else if filter.Category != "" {
+		if _, err := logactivity.TableOf(filter.Category); err != nil {
+			return nil, err
+		}
 	}
-	primKey, err := logactivity.PrimaryKeyOf(category)
-	if err != nil {
-		return nil, err
+
+	var rows []struct {
+		logactivity.LogActivity
+		Changes *string `db:"Changes"`
 	}
+	var args []interface{}
 
-	var detailsActivity []*logactivity.LogActivity
+	where := "1 = 1"
 
-	var create []*logactivity.LogActivity
-	query := fmt.Sprintf("SELECT CreateDt AS Date, CONCAT(CreateBy, ' created this data') AS Log FROM %s WHERE %s = ?", table, primKey)
-	if err := t.DB.SelectContext(ctx, &create, query, code); err != nil {
-		return nil, fmt.Errorf("error log activity: %w", err)
+	if filter.Category != "" {
+		where += " AND a.Category = ?"
+		args = append(args, filter.Category)
+	}
+	if filter.Code != "" {
+		where += " AND a.Code = ?"
+		args = append(args, filter.Code)
 	}
-	if create == nil {
-		return nil, customerrors.ErrDataNotFound
+	if filter.UserCode != "" {
+		where += " AND a.UserCode = ?"
+		args = append(args, filter.UserCode)
+	}
+	if filter.StartDate != "" && filter.EndDate != "" {
+		where += " AND LEFT(a.LastUpDt, 8) BETWEEN ? AND ?"
+		args = append(args, filter.StartDate, filter.EndDate)
 	}
 
-	detailsActivity = append(detailsActivity, create...)
+	var totalUpdates int
+	countQuery := "SELECT COUNT(*) FROM tbllogactivity a JOIN tbluser u ON u.UserCode = a.UserCode WHERE " + where
+	if err := t.DB.GetContext(ctx, &totalUpdates, countQuery, args...); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+	totalRecords := totalUpdates + len(created)
 
-	query = "SELECT a.LastUpDt AS Date, CONCAT(u.UserName, ' made changes to this data') AS Log FROM tbluser u JOIN tbllogactivity a ON u.UserCode = a.UserCode WHERE a.Code = ? AND a.Category = ? ORDER BY Date DESC"
-	var detailsActivityUpdate []*logactivity.LogActivity
-	log.Printf("Executing query: %s with code: %s From %s", query, code, table)
-	if err := t.DB.SelectContext(ctx, &detailsActivityUpdate, query, code, category); err != nil {
+	var totalPages, offset int
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+	}
+
+	query := `SELECT a.LastUpDt AS Date,
+			CONCAT(u.UserName, ' made changes to this data') AS Log,
+			a.Category,
+			a.Code,
+			a.UserCode,
+			u.UserName,
+			COALESCE(a.RequestId, '') AS RequestId,
+			a.Changes
+		FROM tbllogactivity a
+		JOIN tbluser u ON u.UserCode = a.UserCode
+		WHERE ` + where + `
+		ORDER BY a.LastUpDt DESC
+		LIMIT ? OFFSET ?`
+	if err := t.DB.SelectContext(ctx, &rows, query, append(args, param.PageSize, offset)...); err != nil {
 		log.Printf("Detailed error: %+v", err)
-		if !errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("error log activity: %w", err)
+		return nil, fmt.Errorf("error log activity: %w", err)
+	}
+
+	updates := make([]*logactivity.LogActivity, len(rows))
+	for i, row := range rows {
+		activity := row.LogActivity
+		activity.Changes = make([]logactivity.Change, 0)
+		// log lama sebelum audit trail tidak punya Changes
+		if row.Changes != nil {
+			if err := json.Unmarshal([]byte(*row.Changes), &activity.Changes); err != nil {
+				return nil, fmt.Errorf("error decode log changes: %w", err)
+			}
 		}
+		updates[i] = &activity
 	}
 
-	if detailsActivityUpdate != nil {
-		detailsActivity = append(detailsActivityUpdate, detailsActivity...)
+	detailsActivity := updates
+	if totalUpdates >= offset && totalUpdates < offset+param.PageSize {
+		detailsActivity = append(detailsActivity, created...)
 	}
 
 	for _, detail := range detailsActivity {
 		detail.Date = share.FormatDate(detail.Date)
+		if detail.Changes == nil {
+			detail.Changes = make([]logactivity.Change, 0)
+		}
 	}
 
-	return detailsActivity, nil
+	return &pagination.PaginationResponse{
+		Data:         detailsActivity,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}, nil
+}
+
+func inDateRange(date string, filter *logactivity.Filter) bool {
+	if filter.StartDate == "" || filter.EndDate == "" || len(date) < 8 {
+		return true
+	}
+	return date[:8] >= filter.StartDate && date[:8] <= filter.EndDate
 }
@@ -8,6 +8,7 @@ import (
 	"log"
 	"strings"
 
+	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/shared/formatid"
@@ -73,6 +74,8 @@ func (t *TblItemRepository) FetchItems(ctx context.Context, name, category, act
 				i.ActInd,
 				i.ItemRequestDocNo,
 				i.PurchaseUomCode,
+				i.InventoryUOMCode,
+				i.SalesUomCode,
 				i.HSCode,
 				i.Remark,
 				i.InventoryItemInd,
@@ -102,6 +105,8 @@ func (t *TblItemRepository) FetchItems(ctx context.Context, name, category, act
 				i.ActInd,
 				i.ItemRequestDocNo,
 				i.PurchaseUomCode,
+				i.InventoryUOMCode,
+				i.SalesUomCode,
 				i.HSCode,
 				i.Remark,
 				i.InventoryItemInd,
@@ -176,6 +181,8 @@ func (t *TblItemRepository) Detail(ctx context.Context, search string) (*tblmast
 	query := `SELECT
 				i.ItemRequestDocNo,
 				i.PurchaseUomCode,
+				i.InventoryUOMCode,
+				i.SalesUomCode,
 				u.UomName,
 				i.HSCode,
 				i.Remark,
@@ -234,23 +241,40 @@ func (t *TblItemRepository) Create(ctx context.Context, data *tblmasteritem.Crea
 		}
 	}
 
+	if err := insertItem(ctx, t.DB, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// insertItem membuat kode item baru lalu insert. db bisa *sqlx.DB atau *sqlx.Tx (import).
+func insertItem(ctx context.Context, db sqlx.ExtContext, data *tblmasteritem.Create) error {
 	query := "SELECT ItCode, ItName FROM tblitem ORDER BY ItCode DESC LIMIT 1"
 	var check tblmasteritem.Check
 	var id string
 	prefixId := "ITC0004"
-	if err := t.DB.GetContext(ctx, &check, query); err != nil {
+	if err := sqlx.GetContext(ctx, db, &check, query); err != nil {
 		if !errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("error check code: %w", err)
+			return fmt.Errorf("error check code: %w", err)
 		}
 		id = fmt.Sprintf("%s-00001", prefixId)
 	} else {
 		id, err = formatid.FormatId(check.ItemCode, prefixId)
 		if err != nil {
-			return nil, fmt.Errorf("error format id: %w", err)
+			return fmt.Errorf("error format id: %w", err)
 		}
 	}
 	data.ItemCode = id
 
+	// UoM inventory dan sales mengikuti UoM beli kalau tidak diisi
+	if data.InventoryUom == "" {
+		data.InventoryUom = data.Uom
+	}
+	if data.SalesUom == "" {
+		data.SalesUom = data.Uom
+	}
+
 	query = `INSERT INTO tblitem
 				(
 					ItCode,
@@ -284,7 +308,7 @@ func (t *TblItemRepository) Create(ctx context.Context, data *tblmasteritem.Crea
 				VALUES
 				(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	_, err := t.DB.ExecContext(ctx, query,
+	_, err := db.ExecContext(ctx, query,
 		data.ItemCode,
 		data.ItemName,
 		data.LocalCode,
@@ -305,21 +329,21 @@ func (t *TblItemRepository) Create(ctx context.Context, data *tblmasteritem.Crea
 		data.TaxLiable,
 		data.CreateBy,
 		data.Source,
-		data.Uom,
-		data.Uom,
-		data.Uom,
-		data.Uom,
-		data.Uom,
+		data.SalesUom,
+		data.SalesUom,
+		data.InventoryUom,
+		data.InventoryUom,
+		data.InventoryUom,
 		data.Uom,
 		data.Uom,
 	)
 
 	if err != nil {
 		log.Printf("Detailed error: %+v", err)
-		return nil, fmt.Errorf("error Create Item: %w", err)
+		return fmt.Errorf("error Create Item: %w", err)
 	}
 
-	return data, nil
+	return nil
 }
 
 func (t *TblItemRepository) Update(ctx context.Context, data *tblmasteritem.Update, confirm bool) (*tblmasteritem.Update, error) {
@@ -367,6 +391,8 @@ func (t *TblItemRepository) Update(ctx context.Context, data *tblmasteritem.Upda
 			PurchaseItemInd = ?,
 			ServiceItemInd = ?,
 			TaxLiableInd = ?,
+			InventoryUOMCode = COALESCE(NULLIF(?, ''), InventoryUOMCode),
+			SalesUomCode = COALESCE(NULLIF(?, ''), SalesUomCode),
 			LastUpBy = ?,
 			LastUpDt = ?
 			WHERE ItCode = ?`
@@ -377,6 +403,21 @@ func (t *TblItemRepository) Update(ctx context.Context, data *tblmasteritem.Upda
 		return nil, fmt.Errorf("error starting transaction: %w", err)
 	}
 
+	audit, err := newAuditTrail(ctx, tx, "Item", data.ItemCode)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Failed to rollback transaction: %+v", rbErr)
+		}
+		return nil, err
+	}
+
+	if err := checkInventoryUom(ctx, tx, data.ItemCode, data.InventoryUom); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Failed to rollback transaction: %+v", rbErr)
+		}
+		return nil, err
+	}
+
 	_, err = tx.ExecContext(ctx, query,
 		data.ItemName,
 		data.LocalCode,
@@ -391,6 +432,8 @@ func (t *TblItemRepository) Update(ctx context.Context, data *tblmasteritem.Upda
 		data.PurchaseItem,
 		data.ServiceItem,
 		data.TaxLiable,
+		data.InventoryUom,
+		data.SalesUom,
 		data.LastUpdateBy,
 		data.LastUpdateDate,
 		data.ItemCode,
@@ -407,9 +450,7 @@ func (t *TblItemRepository) Update(ctx context.Context, data *tblmasteritem.Upda
 		return nil, fmt.Errorf("error updating item's category: %w", err)
 	}
 
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
-	_, err = tx.ExecContext(ctx, query, data.LastUpdateBy, data.ItemCode, "Item", data.LastUpdateDate)
+	err = audit.Write(ctx, tx, data.LastUpdateBy, data.LastUpdateDate)
 
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
@@ -426,3 +467,29 @@ func (t *TblItemRepository) Update(ctx context.Context, data *tblmasteritem.Upda
 
 	return data, nil
 }
+
+// checkInventoryUom menolak ganti UoM inventory kalau item sudah punya
+// pergerakan stok, karena qty lama di stock summary tercatat di UoM lama
+func checkInventoryUom(ctx context.Context, tx *sqlx.Tx, itemCode, uom string) error {
+	if uom == "" {
+		return nil
+	}
+
+	var current string
+	if err := tx.GetContext(ctx, &current, "SELECT InventoryUOMCode FROM tblitem WHERE ItCode = ?", itemCode); err != nil {
+		return fmt.Errorf("error get inventory uom: %w", err)
+	}
+	if current == uom {
+		return nil
+	}
+
+	var moved bool
+	if err := tx.GetContext(ctx, &moved, "SELECT EXISTS(SELECT 1 FROM tblstockmovement WHERE ItCode = ?)", itemCode); err != nil {
+		return fmt.Errorf("error check stock movement: %w", err)
+	}
+	if moved {
+		return fmt.Errorf("%w: inventory uom of %s can't be changed after stock movement", customerrors.ErrInvalidInput, itemCode)
+	}
+
+	return nil
+}
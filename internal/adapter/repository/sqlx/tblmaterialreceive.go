@@ -10,14 +10,20 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/intransit"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
 	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	sharedfunc "gitlab.com/ayaka/internal/domain/shared/sharedFunc"
 	"gitlab.com/ayaka/internal/domain/tblmaterialreceive"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
 
 type TblMaterialReceiveRepository struct {
-	DB *repository.Sqlx `inject:"database"`
+	DB     *repository.Sqlx            `inject:"database"`
+	Ledger inventoryledger.Repository  `inject:"inventoryLedgerRepository"`
+	ID     *formatid.GenerateIDHandler `inject:"generateID"`
 }
 
 func (t *TblMaterialReceiveRepository) Fetch(ctx context.Context, doc, warehouseFrom, warehouseTo, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -36,6 +42,11 @@ func (t *TblMaterialReceiveRepository) Fetch(ctx context.Context, doc, warehouse
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "WhsCodeFrom", "WhsCodeTo"); scope != "" {
+		countQuery += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
 		return nil, fmt.Errorf("error counting records: %w", err)
 	}
@@ -79,6 +90,11 @@ func (t *TblMaterialReceiveRepository) Fetch(ctx context.Context, doc, warehouse
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "i.WhsCodeFrom", "i.WhsCodeTo"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	query += " LIMIT ? OFFSET ?"
 	args = append(args, param.PageSize, offset)
 
@@ -174,6 +190,39 @@ func (t *TblMaterialReceiveRepository) Fetch(ctx context.Context, doc, warehouse
 }
 
 func (t *TblMaterialReceiveRepository) Create(ctx context.Context, data *tblmaterialreceive.Create) (*tblmaterialreceive.Create, error) {
+	// penerimaan dicatat oleh gudang tujuan
+	if err := checkWarehouse(ctx, data.WhsCodeTo); err != nil {
+		return nil, err
+	}
+
+	// transaction begin
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	// Pastikan rollback dipanggil jika transaksi tidak berhasil
+	defer func() {
+		if err != nil {
+			// Rollback jika error terjadi
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	data.DocNo, err = t.ID.GenerateID(ctx, tx, "MaterialReceive", data.Date)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
+	for i := range data.Details {
+		data.Details[i].Source = fmt.Sprintf("%s*%s*%s", data.Date[6:8], data.DocNo, data.Details[i].DNo)
+	}
+
 	query := `INSERT INTO tblmaterialreceivehdr 
 	(
 		DocNo,
@@ -199,24 +248,6 @@ func (t *TblMaterialReceiveRepository) Create(ctx context.Context, data *tblmate
 		data.CreateDt,
 	)
 
-	// transaction begin
-	var err error
-	tx, err := t.DB.BeginTxx(ctx, nil)
-	if err != nil {
-		log.Printf("Failed to start transaction: %+v", err)
-		return nil, fmt.Errorf("error starting transaction: %w", err)
-	}
-
-	// Pastikan rollback dipanggil jika transaksi tidak berhasil
-	defer func() {
-		if err != nil {
-			// Rollback jika error terjadi
-			if rbErr := tx.Rollback(); rbErr != nil {
-				log.Printf("Failed to rollback transaction: %+v", rbErr)
-			}
-		}
-	}()
-
 	// insert header
 	query += strings.Join(placeholders, "")
 	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
@@ -242,55 +273,7 @@ func (t *TblMaterialReceiveRepository) Create(ctx context.Context, data *tblmate
 		placeholders = placeholders[:0]
 		args = args[:0]
 
-		// stock summary query
-		queryStockSummary := `INSERT INTO tblstocksummary (
-			WhsCode,
-			Lot,
-			Bin,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersStockSummary []string
-		var argsStockSummary []interface{}
-
-		// stock movement query
-		queryStockMovement := `INSERT INTO tblstockmovement (
-			DocType,
-			DocNo,
-			DNo,
-			CancelInd,
-			DocDt,
-			WhsCode,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			Remark,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersStockMovement []string
-		var argsStockMovement []interface{}
-
-		// stock history of stock
-		queryHistory := `INSERT INTO tblhistoryofstock (
-			ItCode,
-			BatchNo,
-			Source,
-			CancelInd,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersHistory []string
-		var argsHistory []interface{}
+		var movements []inventoryledger.Movement
 
 		// transfer between warehouse
 		queryTransfer := `INSERT INTO tbltransferbetweenwhs (
@@ -316,7 +299,25 @@ func (t *TblMaterialReceiveRepository) Create(ctx context.Context, data *tblmate
 		var wheresUpdate []string
 		var argsUpdate, argsInUpdate []interface{}
 
-		for _, detail := range data.Details {
+		var discrepancies []string
+		var argsDiscrepancy []interface{}
+
+		for i := range data.Details {
+			detail := &data.Details[i]
+
+			var line *transitStock
+			if line, err = transitLine(ctx, tx, detail.DocNoMaterialTransfer, detail.ItCode, detail.BatchNo); err != nil {
+				return nil, err
+			}
+			if line != nil {
+				// qty kirim diambil dari sisa di gudang transit, bukan dari request
+				detail.QtyTransfer = line.Outstanding
+				if detail.QtyActual < 0 || detail.QtyActual > line.Outstanding {
+					err = fmt.Errorf("%w: item %s received %v, in transit %v", customerrors.ErrInvalidQuantity, detail.ItCode, detail.QtyActual, line.Outstanding)
+					return nil, err
+				}
+			}
+
 			// detail
 			placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
 			args = append(args,
@@ -333,92 +334,34 @@ func (t *TblMaterialReceiveRepository) Create(ctx context.Context, data *tblmate
 				data.CreateBy,
 			)
 
-			// stock summary
-			placeholdersStockSummary = append(placeholdersStockSummary, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-
-			// stock movement
-			placeholdersStockMovement = append(placeholdersStockMovement, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-
-			////////////////// WAREHOUSE FROM
-			argsStockSummary = append(argsStockSummary,
-				data.WhsCodeFrom,
-				"-",
-				"-",
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				0,
-				detail.QtyActual,
-				data.CreateBy,
-				data.Date,
-			)
-			argsStockMovement = append(argsStockMovement,
-				"Material Transfer",
-				data.DocNo,
-				detail.DNo,
-				"N",
-				data.Date,
-				data.WhsCodeFrom,
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				0,
-				detail.QtyActual,
-				data.Remark,
-				data.CreateBy,
-				data.Date,
-			)
-
-			// history of stock
-			placeholdersHistory = append(placeholdersHistory, "(?, ?, ?, ?, ?, ?)")
-			argsHistory = append(argsHistory,
-				detail.ItCode,
-				detail.BatchNo,
-				detail.Source,
-				"N",
-				data.CreateBy,
-				data.Date,
-			)
-
-			////////////////// WAREHOUSE TO
-			// stock summary
-			placeholdersStockSummary = append(placeholdersStockSummary, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-
-			// stock movement
-			placeholdersStockMovement = append(placeholdersStockMovement, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-
-			argsStockSummary = append(argsStockSummary,
-				data.WhsCodeTo,
-				"-",
-				"-",
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				detail.QtyActual,
-				0,
-				data.CreateBy,
-				data.Date,
-			)
-			argsStockMovement = append(argsStockMovement,
-				"Material Receive",
-				data.DocNo,
-				detail.DNo,
-				"N",
-				data.Date,
-				data.WhsCodeTo,
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				detail.QtyActual,
-				0,
-				data.Remark,
-				data.CreateBy,
-				data.Date,
-			)
+			from := inventoryledger.Movement{
+				DocType:   "Material Transfer",
+				DocNo:     data.DocNo,
+				DNo:       detail.DNo,
+				DocDt:     data.Date,
+				WhsCode:   data.WhsCodeFrom,
+				Source:    detail.Source,
+				ItCode:    detail.ItCode,
+				BatchNo:   detail.BatchNo,
+				Qty:       detail.QtyActual,
+				Direction: inventoryledger.Out,
+				Remark:    data.Remark,
+				CreateBy:  data.CreateBy,
+				CreateDt:  data.Date,
+			}
+			if line != nil {
+				// stok sudah keluar dari gudang asal saat transfer, ambil dari transit
+				from.WhsCode = intransit.Warehouse
+				from.Source = line.Source
+			}
+			to := from
+			to.DocType = "Material Receive"
+			to.WhsCode = data.WhsCodeTo
+			to.Source = detail.Source
+			to.Direction = inventoryledger.In
+			if detail.QtyActual > 0 {
+				movements = append(movements, from, to)
+			}
 
 			placeholdersTransfer = append(placeholdersTransfer, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
 			argsTransfer = append(argsTransfer,
@@ -434,6 +377,30 @@ func (t *TblMaterialReceiveRepository) Create(ctx context.Context, data *tblmate
 				data.CreateDt,
 			)
 
+			if line != nil {
+				// baris transfer ditutup, kekurangannya jadi discrepancy (lihat transitLine)
+				if _, err = tx.ExecContext(ctx, `UPDATE tblmaterialtransferdtl
+					SET QtyReceived = QtyReceived + ?, SuccessInd = 'Y'
+					WHERE DocNo = ? AND DNo = ?`, detail.QtyActual, line.DocNo, line.DNo); err != nil {
+					log.Printf("Error Update Material Transfer: %+v", err)
+					return nil, fmt.Errorf("error Update Material Transfer: %w", err)
+				}
+				if shortfall := detail.QtyTransfer - detail.QtyActual; shortfall > 0 {
+					discrepancies = append(discrepancies, "(?, ?, ?, ?, ?, ?, ?, ?)")
+					argsDiscrepancy = append(argsDiscrepancy,
+						data.DocNo,
+						detail.DNo,
+						line.DocNo,
+						line.DNo,
+						shortfall,
+						intransit.Open,
+						data.CreateBy,
+						data.CreateDt,
+					)
+				}
+				continue
+			}
+
 			if detail.QtyActual >= detail.QtyTransfer {
 				whensUpdate = append(whensUpdate, `
 					WHEN DocNo = ? AND ItCode = ? AND BatchNo = ? THEN 'Y'
@@ -455,25 +422,8 @@ func (t *TblMaterialReceiveRepository) Create(ctx context.Context, data *tblmate
 			return nil, fmt.Errorf("error Insert Detail: %w", err)
 		}
 
-		// insert stock summary
-		queryStockSummary += strings.Join(placeholdersStockSummary, ",") + `;`
-		if _, err = tx.ExecContext(ctx, queryStockSummary, argsStockSummary...); err != nil {
-			log.Printf("Error insert stock summary: %+v", err)
-			return nil, fmt.Errorf("error Insert Stock Summary: %w", err)
-		}
-
-		// insert stock movement
-		queryStockMovement += strings.Join(placeholdersStockMovement, ",") + ";"
-		if _, err = tx.ExecContext(ctx, queryStockMovement, argsStockMovement...); err != nil {
-			log.Printf("Error insert stock movement: %+v", err)
-			return nil, fmt.Errorf("error Insert Stock Movement: %w", err)
-		}
-
-		// insert history
-		queryHistory += strings.Join(placeholdersHistory, ",") + ";"
-		if _, err = tx.ExecContext(ctx, queryHistory, argsHistory...); err != nil {
-			log.Printf("Error insert history of stock: %+v", err)
-			return nil, fmt.Errorf("error Insert History of Stock: %w", err)
+		if err = t.Ledger.Post(ctx, tx, movements); err != nil {
+			return nil, err
 		}
 
 		// insert report
@@ -483,6 +433,23 @@ func (t *TblMaterialReceiveRepository) Create(ctx context.Context, data *tblmate
 			return nil, fmt.Errorf("error Insert report transfer: %w", err)
 		}
 
+		if len(discrepancies) > 0 {
+			queryDiscrepancy := `INSERT INTO tblmaterialtransferdiscrepancy (
+				DocNo,
+				DNo,
+				TransferDocNo,
+				TransferDNo,
+				Qty,
+				Status,
+				CreateBy,
+				CreateDt
+			) VALUES ` + strings.Join(discrepancies, ",")
+			if _, err = tx.ExecContext(ctx, queryDiscrepancy, argsDiscrepancy...); err != nil {
+				log.Printf("Error insert discrepancy: %+v", err)
+				return nil, fmt.Errorf("error Insert discrepancy: %w", err)
+			}
+		}
+
 		if len(wheresUpdate) > 0 && len(whensUpdate) > 0 && len(argsUpdate) > 0 {
 			// update detail
 			queryUpdate += strings.Join(whensUpdate, " ")
@@ -561,3 +528,41 @@ func (t *TblMaterialReceiveRepository) Create(ctx context.Context, data *tblmate
 
 	return data, nil
 }
+
+type transitStock struct {
+	DocNo       string  `db:"DocNo"`
+	DNo         string  `db:"DNo"`
+	Source      string  `db:"Source"`
+	SuccessInd  string  `db:"SuccessInd"`
+	Outstanding float32 `db:"Outstanding"`
+}
+
+// transitLine baris transfer yang barangnya masih di gudang transit, nil untuk
+// transfer lama yang stoknya belum dipindah saat transfer.
+//
+// Satu baris transfer hanya diterima sekali: penerimaan pertama menutup baris
+// (SuccessInd = 'Y') dan kekurangannya menjadi discrepancy yang diselesaikan
+// lewat Loss / Return, bukan ditunggu dari pengiriman susulan. Penerimaan
+// berikutnya ke baris yang sudah ditutup ditolak supaya tidak jatuh ke jalur
+// transfer lama yang mengambil stok dari gudang asal.
+func transitLine(ctx context.Context, tx *sqlx.Tx, docNo, itCode, batchNo string) (*transitStock, error) {
+	var line transitStock
+	query := `SELECT DocNo, DNo, Source, SuccessInd, Qty - QtyReceived - QtyLoss - QtyReturned AS Outstanding
+		FROM tblmaterialtransferdtl
+		WHERE DocNo = ? AND ItCode = ? AND BatchNo = ?
+		AND CancelInd = 'N' AND InTransitInd = 'Y'
+		ORDER BY SuccessInd, DNo
+		LIMIT 1
+		FOR UPDATE`
+	if err := tx.GetContext(ctx, &line, query, docNo, itCode, batchNo); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error fetching material transfer dtl: %w", err)
+	}
+	if line.SuccessInd == "Y" {
+		return nil, fmt.Errorf("%w: %s item %s batch %s already received, shortfall is an in-transit discrepancy", customerrors.ErrInvalidQuantity, docNo, itCode, batchNo)
+	}
+
+	return &line, nil
+}
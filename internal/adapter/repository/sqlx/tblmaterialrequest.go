@@ -10,10 +10,11 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/docapproval"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/tblmaterialrequest"
 
-	// "gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
 
 	// "gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
@@ -21,7 +22,9 @@ import (
 )
 
 type TblMaterialRequestRepository struct {
-	DB *repository.Sqlx `inject:"database"`
+	DB       *repository.Sqlx            `inject:"database"`
+	ID       *formatid.GenerateIDHandler `inject:"generateID"`
+	Approval docapproval.Repository      `inject:"docApprovalRepository"`
 }
 
 func (t *TblMaterialRequestRepository) Fetch(ctx context.Context, doc, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -38,6 +41,11 @@ func (t *TblMaterialRequestRepository) Fetch(ctx context.Context, doc, startDate
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := siteScope(ctx, "SiteCode"); scope != "" {
+		countQuery += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
 		return nil, fmt.Errorf("error counting records: %w", err)
 	}
@@ -67,6 +75,7 @@ func (t *TblMaterialRequestRepository) Fetch(ctx context.Context, doc, startDate
 			i.DocDt,
 			i.SiteCode,
 			i.Department,
+			i.ApprovalStatus,
 			i.Remark
 			FROM tblmaterialrequesthdr i
 			LEFT JOIN tblsite s ON i.SiteCode = s.SiteCode
@@ -78,6 +87,11 @@ func (t *TblMaterialRequestRepository) Fetch(ctx context.Context, doc, startDate
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := siteScope(ctx, "i.SiteCode"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	query += " LIMIT ? OFFSET ?"
 	args = append(args, param.PageSize, offset)
 
@@ -179,6 +193,34 @@ func (t *TblMaterialRequestRepository) Fetch(ctx context.Context, doc, startDate
 }
 
 func (t *TblMaterialRequestRepository) Create(ctx context.Context, data *tblmaterialrequest.Create) (*tblmaterialrequest.Create, error) {
+	if err := checkSite(ctx, data.SiteCode.String); err != nil {
+		return nil, err
+	}
+
+	// transaction begin
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	// Pastikan rollback dipanggil jika transaksi tidak berhasil
+	defer func() {
+		if err != nil {
+			// Rollback jika error terjadi
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	data.DocNo, err = t.ID.GenerateIDForSite(ctx, tx, "MaterialRequest", data.SiteCode.String, data.Date)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
 	query := `INSERT INTO tblmaterialrequesthdr 
 	(
 		DocNo,
@@ -204,24 +246,6 @@ func (t *TblMaterialRequestRepository) Create(ctx context.Context, data *tblmate
 		data.CreateDt,
 	)
 
-	// transaction begin
-	var err error
-	tx, err := t.DB.BeginTxx(ctx, nil)
-	if err != nil {
-		log.Printf("Failed to start transaction: %+v", err)
-		return nil, fmt.Errorf("error starting transaction: %w", err)
-	}
-
-	// Pastikan rollback dipanggil jika transaksi tidak berhasil
-	defer func() {
-		if err != nil {
-			// Rollback jika error terjadi
-			if rbErr := tx.Rollback(); rbErr != nil {
-				log.Printf("Failed to rollback transaction: %+v", rbErr)
-			}
-		}
-	}()
-
 	// insert header
 	query += strings.Join(placeholders, "")
 	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
@@ -280,6 +304,10 @@ func (t *TblMaterialRequestRepository) Create(ctx context.Context, data *tblmate
 		}
 	}
 
+	if err = t.Approval.Start(ctx, tx, docapproval.MaterialRequest, data.DocNo, data.CreateBy, data.CreateDt); err != nil {
+		return nil, err
+	}
+
 	// Commit the transaction
 	if err = tx.Commit(); err != nil {
 		log.Printf("Failed to commit transaction: %+v", err)
@@ -320,6 +348,11 @@ func (t *TblMaterialRequestRepository) Update(ctx context.Context, lastUpby, las
 		}
 	}()
 
+	audit, err := newAuditTrail(ctx, tx, "MaterialRequest", data.DocNo)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, detail := range data.Details {
 		// detail cancel
 		placeholders = append(placeholders, ` WHEN DocNo = ? AND DNo = ? THEN ? `)
@@ -351,10 +384,8 @@ func (t *TblMaterialRequestRepository) Update(ctx context.Context, lastUpby, las
 	}
 
 	// Update log activity
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
 	fmt.Println("--Update Log--")
-	if _, err = tx.ExecContext(ctx, query, lastUpby, data.DocNo, "MaterialRequest", lastUpDate); err != nil {
+	if err = audit.Write(ctx, tx, lastUpby, lastUpDate); err != nil {
 		log.Printf("Detailed error: %+v", err)
 		return nil, fmt.Errorf("error inserting log activity: %w", err)
 	}
@@ -388,7 +419,7 @@ func (t *TblMaterialRequestRepository) GetMaterialRequest(ctx context.Context, d
 			ON por.DocNo = po.PurchaseOrderReqDocNo AND por.DNo = po.PurchaseOrderReqDNo
 			LEFT JOIN tblpurchasematerialreceivedtl r
 			ON po.DocNo = r.PurchaseOrderDocNo AND po.DNo = r.PurchaseOrderDNo
-			WHERE mr.CancelInd = 'N' AND mr.OpenInd = 'Y'
+			WHERE mr.CancelInd = 'N' AND mr.OpenInd = 'Y' AND h.ApprovalStatus = 'Approved'
 			AND (h.DocNo LIKE ? OR i.ItName LIKE ?)
 			GROUP BY mr.DocNo, mr.DNo, h.Department, mr.ItCode, i.ItName, mr.Qty, mr.EstimatedPrice, mr.UsageDt
 			HAVING (mr.Qty - COALESCE(SUM(r.PurchaseQty), 0)) > 0
@@ -436,7 +467,7 @@ func (t *TblMaterialRequestRepository) GetMaterialRequest(ctx context.Context, d
 			ON por.DocNo = po.PurchaseOrderReqDocNo AND por.DNo = po.PurchaseOrderReqDNo
 			LEFT JOIN tblpurchasematerialreceivedtl r
 			ON po.DocNo = r.PurchaseOrderDocNo AND po.DNo = r.PurchaseOrderDNo
-			WHERE mr.CancelInd != 'Y' AND mr.OpenInd = 'Y' AND (mr.DocNo LIKE ? OR i.ItName LIKE ?)
+			WHERE mr.CancelInd != 'Y' AND mr.OpenInd = 'Y' AND h.ApprovalStatus = 'Approved' AND (mr.DocNo LIKE ? OR i.ItName LIKE ?)
 			GROUP BY mr.DocNo, mr.DNo, mr.ItCode, mr.Qty
 			HAVING OutstandingQty > 0
 	`
@@ -605,3 +636,13 @@ func (t *TblMaterialRequestRepository) OutstandingMaterial(ctx context.Context,
 
 	return response, nil
 }
+
+// materialRequestSite site material request, kosong kalau tidak ada (site default)
+func materialRequestSite(ctx context.Context, tx *sqlx.Tx, docNo string) (string, error) {
+	var site string
+	query := "SELECT COALESCE(SiteCode, '') FROM tblmaterialrequesthdr WHERE DocNo = ?"
+	if err := tx.GetContext(ctx, &site, query, docNo); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("error get material request site: %w", err)
+	}
+	return site, nil
+}
@@ -10,20 +10,40 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/batch"
+	"gitlab.com/ayaka/internal/domain/intransit"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/tblmaterialtransfer"
 
-	// "gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
 
 	// "gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
 
+// TblMaterialTransferRepository material transfer antar gudang. Stok keluar
+// dari gudang asal saat transfer dibuat dan ditampung di gudang transit
+// sampai diterima (lihat InTransitRepository).
+//
+//	ALTER TABLE tblmaterialtransferdtl
+//		ADD COLUMN Source VARCHAR(100) NULL AFTER BatchNo,
+//		ADD COLUMN InTransitInd CHAR(1) NOT NULL DEFAULT 'N',
+//		ADD COLUMN QtyReceived DECIMAL(18,4) NOT NULL DEFAULT 0,
+//		ADD COLUMN QtyLoss DECIMAL(18,4) NOT NULL DEFAULT 0,
+//		ADD COLUMN QtyReturned DECIMAL(18,4) NOT NULL DEFAULT 0;
 type TblMaterialTransferRepository struct {
-	DB *repository.Sqlx `inject:"database"`
+	DB     *repository.Sqlx            `inject:"database"`
+	ID     *formatid.GenerateIDHandler `inject:"generateID"`
+	Batch  batch.Repository            `inject:"batchRepository"`
+	Ledger inventoryledger.Repository  `inject:"inventoryLedgerRepository"`
 }
 
+// inTransitQty sisa baris transfer di gudang transit, transfer sebelum ada
+// gudang transit (InTransitInd = N) tidak pernah memindahkan stok
+const inTransitQty = `IF(d.InTransitInd = 'Y' AND d.CancelInd = 'N', d.Qty - d.QtyReceived - d.QtyLoss - d.QtyReturned, 0)`
+
 func (t *TblMaterialTransferRepository) Fetch(ctx context.Context, doc, warehouseFrom, warehouseTo, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
 	var totalRecords int
 
@@ -40,6 +60,11 @@ func (t *TblMaterialTransferRepository) Fetch(ctx context.Context, doc, warehous
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "WhsCodeFrom", "WhsCodeTo"); scope != "" {
+		countQuery += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
 		return nil, fmt.Errorf("error counting records: %w", err)
 	}
@@ -89,6 +114,11 @@ func (t *TblMaterialTransferRepository) Fetch(ctx context.Context, doc, warehous
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "i.WhsCodeFrom", "i.WhsCodeTo"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	query += " LIMIT ? OFFSET ?"
 	args = append(args, param.PageSize, offset)
 
@@ -137,8 +167,10 @@ func (t *TblMaterialTransferRepository) Fetch(ctx context.Context, doc, warehous
 				d.ItCode,
 				i.ItName,
 				d.BatchNo,
+				COALESCE(d.Source, '') AS Source,
 				d.Stock,
 				d.Qty,
+				` + inTransitQty + ` AS QtyInTransit,
 				u.UomName,
 				d.CancelInd,
 				d.SuccessInd
@@ -183,6 +215,49 @@ func (t *TblMaterialTransferRepository) Fetch(ctx context.Context, doc, warehous
 }
 
 func (t *TblMaterialTransferRepository) Create(ctx context.Context, data *tblmaterialtransfer.Create) (*tblmaterialtransfer.Create, error) {
+	// transfer dibuat oleh gudang asal
+	if err := checkWarehouse(ctx, data.WhsCodeFrom); err != nil {
+		return nil, err
+	}
+
+	// transaction begin
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	// Pastikan rollback dipanggil jika transaksi tidak berhasil
+	defer func() {
+		if err != nil {
+			// Rollback jika error terjadi
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	// batch kedaluwarsa tidak ikut dipindah ke gudang lain
+	lines := make([]batch.Line, 0, len(data.Details))
+	for _, d := range data.Details {
+		lines = append(lines, batch.Line{ItemCode: d.ItCode, Batch: d.BatchNo})
+	}
+	if err = t.Batch.CheckExpired(ctx, tx, data.Date, lines); err != nil {
+		return nil, err
+	}
+
+	data.DocNo, err = t.ID.GenerateID(ctx, tx, "MaterialTransfer", data.Date)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
+	// Source tetap Source stok asal yang dipilih, ikut dibawa ke gudang TRANSIT
+	for i := range data.Details {
+		data.Details[i].QtyInTransit = data.Details[i].Qty
+	}
+
 	query := `INSERT INTO tblmaterialtransferhdr 
 	(
 		DocNo,
@@ -218,24 +293,6 @@ func (t *TblMaterialTransferRepository) Create(ctx context.Context, data *tblmat
 		data.CreateDt,
 	)
 
-	// transaction begin
-	var err error
-	tx, err := t.DB.BeginTxx(ctx, nil)
-	if err != nil {
-		log.Printf("Failed to start transaction: %+v", err)
-		return nil, fmt.Errorf("error starting transaction: %w", err)
-	}
-
-	// Pastikan rollback dipanggil jika transaksi tidak berhasil
-	defer func() {
-		if err != nil {
-			// Rollback jika error terjadi
-			if rbErr := tx.Rollback(); rbErr != nil {
-				log.Printf("Failed to rollback transaction: %+v", rbErr)
-			}
-		}
-	}()
-
 	// insert header
 	query += strings.Join(placeholders, "")
 	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
@@ -252,8 +309,10 @@ func (t *TblMaterialTransferRepository) Create(ctx context.Context, data *tblmat
 			SuccessInd,
 			ItCode,
 			BatchNo,
+			Source,
 			Stock,
 			Qty,
+			InTransitInd,
 			Remark,
 			CreateDt,
 			CreateBy
@@ -262,9 +321,11 @@ func (t *TblMaterialTransferRepository) Create(ctx context.Context, data *tblmat
 		placeholders = placeholders[:0]
 		args = args[:0]
 
+		var movements []inventoryledger.Movement
+
 		for _, detail := range data.Details {
 			// detail
-			placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+			placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
 			args = append(args,
 				data.DocNo,
 				detail.DNo,
@@ -272,12 +333,16 @@ func (t *TblMaterialTransferRepository) Create(ctx context.Context, data *tblmat
 				"N",
 				detail.ItCode,
 				detail.BatchNo,
+				detail.Source,
 				detail.Stock,
 				detail.Qty,
+				"Y",
 				detail.Remark,
 				data.CreateDt,
 				data.CreateBy,
 			)
+
+			movements = append(movements, transferMovements(data.DocNo, data.Date, data.WhsCodeFrom, data.CreateBy, data.CreateDt, detail)...)
 		}
 
 		// insert detail
@@ -286,6 +351,10 @@ func (t *TblMaterialTransferRepository) Create(ctx context.Context, data *tblmat
 			log.Printf("Error insert detail: %+v", err)
 			return nil, fmt.Errorf("error Insert Detail: %w", err)
 		}
+
+		if err = t.Ledger.Post(ctx, tx, movements); err != nil {
+			return nil, err
+		}
 	}
 
 	// Commit the transaction
@@ -328,6 +397,20 @@ func (t *TblMaterialTransferRepository) Update(ctx context.Context, lastUpby, la
 		}
 	}()
 
+	audit, err := newAuditTrail(ctx, tx, "MaterialTransfer", data.DocNo)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkDocWarehouse(ctx, tx, "tblmaterialtransferhdr", "WhsCodeFrom", data.DocNo); err != nil {
+		return nil, err
+	}
+
+	movements, err := t.cancelledMovements(ctx, tx, lastUpby, lastUpDate, data)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, detail := range data.Details {
 		// detail cancel
 		placeholders = append(placeholders, ` WHEN DocNo = ? AND DNo = ? THEN ? `)
@@ -358,11 +441,13 @@ func (t *TblMaterialTransferRepository) Update(ctx context.Context, lastUpby, la
 		return data, err
 	}
 
-	// Update log activity
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
+	if err = t.Ledger.Reverse(ctx, tx, movements); err != nil {
+		return nil, err
+	}
 
+	// Update log activity
 	fmt.Println("--Update Log--")
-	if _, err = tx.ExecContext(ctx, query, lastUpby, data.DocNo, "MaterialTransfer", lastUpDate); err != nil {
+	if err = audit.Write(ctx, tx, lastUpby, lastUpDate); err != nil {
 		log.Printf("Detailed error: %+v", err)
 		return nil, fmt.Errorf("error inserting log activity: %w", err)
 	}
@@ -375,3 +460,67 @@ func (t *TblMaterialTransferRepository) Update(ctx context.Context, lastUpby, la
 
 	return data, nil
 }
+
+// transferMovements memindahkan baris transfer dari gudang asal ke gudang
+// transit, dokumen dan DNo yang sama supaya harga keluarnya terbawa
+func transferMovements(docNo, docDt, whsCodeFrom, createBy, createDt string, detail tblmaterialtransfer.Detail) []inventoryledger.Movement {
+	out := inventoryledger.Movement{
+		DocType:   "Material Transfer",
+		DocNo:     docNo,
+		DNo:       detail.DNo,
+		DocDt:     docDt,
+		WhsCode:   whsCodeFrom,
+		Source:    detail.Source,
+		ItCode:    detail.ItCode,
+		BatchNo:   detail.BatchNo,
+		Qty:       detail.Qty,
+		Direction: inventoryledger.Out,
+		Remark:    detail.Remark,
+		CreateBy:  createBy,
+		CreateDt:  createDt,
+	}
+	in := out
+	in.WhsCode = intransit.Warehouse
+	in.Direction = inventoryledger.In
+
+	return []inventoryledger.Movement{out, in}
+}
+
+// cancelledMovements movement transfer dari baris yang baru di-cancel. Baris
+// yang sudah diterima atau punya discrepancy tidak bisa di-cancel lagi.
+func (t *TblMaterialTransferRepository) cancelledMovements(ctx context.Context, tx *sqlx.Tx, cancelBy, cancelDt string, data *tblmaterialtransfer.Read) ([]inventoryledger.Movement, error) {
+	var lines []struct {
+		tblmaterialtransfer.Detail
+		DocDt        string  `db:"DocDt"`
+		WhsCodeFrom  string  `db:"WhsCodeFrom"`
+		InTransitInd string  `db:"InTransitInd"`
+		QtyMoved     float32 `db:"QtyMoved"`
+	}
+	query := `SELECT d.DNo, d.ItCode, d.BatchNo, COALESCE(d.Source, '') AS Source, d.Qty, d.CancelInd, d.SuccessInd,
+			d.InTransitInd, d.QtyReceived + d.QtyLoss + d.QtyReturned AS QtyMoved, h.DocDt, h.WhsCodeFrom
+		FROM tblmaterialtransferdtl d
+		JOIN tblmaterialtransferhdr h ON d.DocNo = h.DocNo
+		WHERE d.DocNo = ?
+		FOR UPDATE`
+	if err := tx.SelectContext(ctx, &lines, query, data.DocNo); err != nil {
+		return nil, fmt.Errorf("error fetching material transfer dtl: %w", err)
+	}
+
+	cancel := make(map[string]bool, len(data.Details))
+	for _, detail := range data.Details {
+		cancel[detail.DNo] = detail.CancelInd.ToBool()
+	}
+
+	var movements []inventoryledger.Movement
+	for _, line := range lines {
+		if !cancel[line.DNo] || line.CancelInd.ToBool() || line.InTransitInd != "Y" {
+			continue
+		}
+		if line.SuccesInd.ToBool() || line.QtyMoved > 0 {
+			return nil, fmt.Errorf("%w: detail %s already received", customerrors.ErrInvalidQuantity, line.DNo)
+		}
+		movements = append(movements, transferMovements(data.DocNo, line.DocDt, line.WhsCodeFrom, cancelBy, cancelDt, line.Detail)...)
+	}
+
+	return movements, nil
+}
@@ -0,0 +1,67 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/tblmaterialtransfer"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+const (
+	queryMaterialTransferHeader = "INSERT INTO tblmaterialtransferhdr ( DocNo, DocDt, Status, WhsCodeFrom, WhsCodeTo, VendorCode, Driver, TransportType, LicenceNo, Note, CreateDt, CreateBy ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
+	queryMaterialTransferDetail = "INSERT INTO tblmaterialtransferdtl ( DocNo, DNo, CancelInd, SuccessInd, ItCode, BatchNo, Source, Stock, Qty, InTransitInd, Remark, CreateDt, CreateBy ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
+)
+
+type TblMaterialTransferRepositorySuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	repo    *TblMaterialTransferRepository
+	db      *sqlx.DB
+}
+
+func (suite *TblMaterialTransferRepositorySuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.repo = &TblMaterialTransferRepository{
+		DB:     &repository.Sqlx{DB: suite.db},
+		ID:     &formatid.GenerateIDHandler{},
+		Batch:  fakeBatch{},
+		Ledger: &InventoryLedgerRepository{},
+	}
+}
+
+func (suite *TblMaterialTransferRepositorySuite) TearDownTest() {
+	suite.db.Close()
+}
+
+// stok keluar dari gudang asal di Source yang dipilih
+func (suite *TblMaterialTransferRepositorySuite) TestCreate_KeepsPickedSource() {
+	suite.mockSQL.ExpectBegin()
+	expectDocNo(suite.mockSQL, "MaterialTransfer")
+	suite.mockSQL.ExpectExec(queryMaterialTransferHeader).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryMaterialTransferDetail).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPickedSourceLock(suite.mockSQL, "WH1", "IT001", "B1", "15*0001/R1/PMR/01/26*001")
+
+	_, err := suite.repo.Create(context.Background(), &tblmaterialtransfer.Create{
+		Date:        "20260120",
+		WhsCodeFrom: "WH1",
+		WhsCodeTo:   "WH2",
+		Details:     []tblmaterialtransfer.Detail{{DNo: "001", ItCode: "IT001", BatchNo: "B1", Source: "15*0001/R1/PMR/01/26*001", Qty: 3}},
+	})
+
+	suite.ErrorIs(err, customerrors.ErrInsufficientStock)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func TestTblMaterialTransferRepository(t *testing.T) {
+	suite.Run(t, new(TblMaterialTransferRepositorySuite))
+}
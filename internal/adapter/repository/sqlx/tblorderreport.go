@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/exchangerate"
 	"gitlab.com/ayaka/internal/domain/tblorderreport"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
@@ -16,7 +18,32 @@ type TblOrderReportRepository struct {
 	DB *repository.Sqlx `inject:"database"`
 }
 
-func (t *TblOrderReportRepository) ByVendor(ctx context.Context, date string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+// orderReportLines baris order report beserta harga PO dan mata uang dokumennya:
+// penerimaan PO dari quotation, direct purchase receive dari headernya
+const orderReportLines = `SELECT
+					o.VendorCode,
+					o.DocNo,
+					o.DocDt,
+					o.Qty,
+					o.Price,
+					pord.Total / NULLIF(pord.Qty, 0) AS PoPrice,
+					COALESCE(dpr.CurCode, vq.CurCode, ?) AS CurCode
+				FROM tblorderreport o
+				LEFT JOIN tblpurchasematerialreceivedtl pmrd
+					ON pmrd.DocNo = o.DocNo
+					AND pmrd.ItCode = o.ItCode
+				LEFT JOIN tblpurchaseorderdtl pord
+					ON pmrd.PurchaseOrderDocNo = pord.DocNo
+					AND pmrd.PurchaseOrderDNo = pord.DNo
+				LEFT JOIN tblpurchaseorderreqdtl por
+					ON pord.PurchaseOrderReqDocNo = por.DocNo
+					AND pord.PurchaseOrderReqDNo = por.DNo
+				LEFT JOIN tblvendorquotationhdr vq ON por.VendorQTDocNo = vq.DocNo
+				LEFT JOIN tbldirectpurchasercvhdr dpr ON o.DocNo = dpr.DocNo
+				WHERE o.CancelInd = 'N'
+					AND LEFT(o.DocDt, 6) = ?`
+
+func (t *TblOrderReportRepository) ByVendor(ctx context.Context, date, base string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
 	var totalRecords int
 	var args []interface{}
 
@@ -99,37 +126,39 @@ func (t *TblOrderReportRepository) ByVendor(ctx context.Context, date string, pa
 	}
 
 	var items []*tblorderreport.OrderReportByVendor
-	query := `WITH VendorOrders AS (
+	query := `WITH Orders AS (` + orderReportLines + `),
+
+				Converted AS (
+					SELECT
+						ol.*,
+						` + baseRateSQL("ol.CurCode", "ol.DocDt") + ` AS Rate
+					FROM Orders ol
+				),
+
+				VendorOrders AS (
 					SELECT 
-						o.VendorCode,
+						c.VendorCode,
 						v.VendorName,
-						COUNT(DISTINCT o.DocNo) AS OrderFreq,
+						COUNT(DISTINCT c.DocNo) AS OrderFreq,
 
-						-- Gunakan rata-rata dari PO jika tersedia, fallback ke harga order
+						-- Gunakan rata-rata dari PO jika tersedia, fallback ke harga order (dalam base)
 						ROUND(
 							COALESCE(
-								AVG(pord.Total / NULLIF(pord.Qty, 0)),
-								AVG(o.Price)
+								AVG(c.PoPrice * c.Rate),
+								AVG(c.Price * c.Rate)
 							),
 						2
 						) AS AveragePrice,
 
-						-- Total nilai order (prioritaskan data PO, fallback ke harga order)
+						-- Total nilai order dalam base, baris tanpa kurs bernilai 0
 						SUM(
-							o.Qty * COALESCE(pord.Total / NULLIF(pord.Qty, 0), o.Price)
-						) AS TotalOrderAmount
+							c.Qty * COALESCE(c.PoPrice, c.Price) * c.Rate
+						) AS TotalOrderAmount,
+						SUM(c.Rate = 0) > 0 AS RateMissing
 
-					FROM tblorderreport o
-					LEFT JOIN tblpurchasematerialreceivedtl pmrd
-						ON pmrd.DocNo = o.DocNo
-						AND pmrd.ItCode = o.ItCode
-					LEFT JOIN tblpurchaseorderdtl pord
-						ON pmrd.PurchaseOrderDocNo = pord.DocNo
-						AND pmrd.PurchaseOrderDNo = pord.DNo
-					JOIN tblvendorhdr v ON v.VendorCode = o.VendorCode
-					WHERE o.CancelInd = 'N'
-						AND LEFT(o.DocDt, 6) = ?
-					GROUP BY o.VendorCode, v.VendorName
+					FROM Converted c
+					JOIN tblvendorhdr v ON v.VendorCode = c.VendorCode
+					GROUP BY c.VendorCode, v.VendorName
 				),
 
 				Total AS (
@@ -139,10 +168,12 @@ func (t *TblOrderReportRepository) ByVendor(ctx context.Context, date string, pa
 				)
 
 				SELECT 
+				vo.VendorCode,
 				vo.VendorName,
 				vo.OrderFreq,
 				vo.AveragePrice,
 				vo.TotalOrderAmount,
+				vo.RateMissing,
 				ROUND(
 					vo.TotalOrderAmount / NULLIF(t.TotalAllOrderAmount, 0) * 100,
 					2
@@ -151,6 +182,7 @@ func (t *TblOrderReportRepository) ByVendor(ctx context.Context, date string, pa
 				CROSS JOIN Total t
 				ORDER BY vo.TotalOrderAmount DESC
 	`
+	args = append([]interface{}{base, date}, baseRateArgs(base)...)
 
 	query += " LIMIT ? OFFSET ?"
 	args = append(args, param.PageSize, offset)
@@ -172,9 +204,25 @@ func (t *TblOrderReportRepository) ByVendor(ctx context.Context, date string, pa
 
 	// proses data
 	j := offset
-	for _, item := range items {
+	vendorCodes := make([]string, len(items))
+	for i, item := range items {
 		j++
 		item.Number = uint(j)
+		item.BaseCurrency = base
+		item.Amounts = []exchangerate.Amount{}
+		vendorCodes[i] = item.VendorCode
+	}
+
+	if len(vendorCodes) > 0 {
+		amounts, err := t.amountsByCurrency(ctx, date, base, vendorCodes)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if a, ok := amounts[item.VendorCode]; ok {
+				item.Amounts = a
+			}
+		}
 	}
 
 	// response
@@ -190,3 +238,33 @@ func (t *TblOrderReportRepository) ByVendor(ctx context.Context, date string, pa
 
 	return response, nil
 }
+
+// amountsByCurrency total order tiap vendor dalam mata uang aslinya
+func (t *TblOrderReportRepository) amountsByCurrency(ctx context.Context, date, base string, vendorCodes []string) (map[string][]exchangerate.Amount, error) {
+	query, args, err := sqlx.In(`WITH Orders AS (`+orderReportLines+`)
+		SELECT
+			ol.VendorCode,
+			ol.CurCode,
+			SUM(ol.Qty * COALESCE(ol.PoPrice, ol.Price)) AS Amount
+		FROM Orders ol
+		WHERE ol.VendorCode IN (?)
+		GROUP BY ol.VendorCode, ol.CurCode
+		ORDER BY ol.VendorCode, ol.CurCode`, base, date, vendorCodes)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing amount query: %w", err)
+	}
+
+	var rows []struct {
+		VendorCode string `db:"VendorCode"`
+		exchangerate.Amount
+	}
+	if err := t.DB.SelectContext(ctx, &rows, t.DB.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("error fetch order amount by currency: %w", err)
+	}
+
+	amounts := make(map[string][]exchangerate.Amount)
+	for _, r := range rows {
+		amounts[r.VendorCode] = append(amounts[r.VendorCode], r.Amount)
+	}
+	return amounts, nil
+}
@@ -146,6 +146,14 @@ func (r *TblProvinceRepository) Update(ctx context.Context, data *tblprovince.Up
 		return fmt.Errorf("error starting transaction: %w", err)
 	}
 
+	audit, err := newAuditTrail(ctx, tx, "Province", data.ProvCode)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Failed to rollback transaction: %+v", rbErr)
+		}
+		return err
+	}
+
 	query = "UPDATE tblprovince SET ProvName = ?, CntCode = ?, LastUpBy = ?, LastUpDt = ? WHERE ProvCode = ?"
 	_, err = tx.ExecContext(ctx, query, data.ProvName, data.CountryCode, userCode, data.LastUpdateDate, data.ProvCode)
 
@@ -157,8 +165,7 @@ func (r *TblProvinceRepository) Update(ctx context.Context, data *tblprovince.Up
 	}
 
 	// Log the activity
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-	_, err = tx.ExecContext(ctx, query, userCode, data.ProvCode, "Province", data.LastUpdateDate)
+	err = audit.Write(ctx, tx, userCode, data.LastUpdateDate)
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
 			log.Printf("Failed to rollback transaction: %+v", rbErr)
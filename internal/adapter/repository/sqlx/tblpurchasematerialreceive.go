@@ -10,19 +10,79 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/exchangerate"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
+	"gitlab.com/ayaka/internal/domain/journal"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	sharedfunc "gitlab.com/ayaka/internal/domain/shared/sharedFunc"
+	"gitlab.com/ayaka/internal/domain/taxengine"
 	"gitlab.com/ayaka/internal/domain/tblpurchasematerialreceive"
+	"gitlab.com/ayaka/internal/domain/uomconversion"
 
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
 
 type TblPurchaseMaterialReceiveRepository struct {
-	DB *repository.Sqlx `inject:"database"`
+	DB      *repository.Sqlx            `inject:"database"`
+	Ledger  inventoryledger.Repository  `inject:"inventoryLedgerRepository"`
+	ID      *formatid.GenerateIDHandler `inject:"generateID"`
+	Uom     uomconversion.Repository    `inject:"uomConversionRepository"`
+	Rate    exchangerate.Repository     `inject:"exchangeRateRepository"`
+	Tax     taxengine.Repository        `inject:"taxEngineRepository"`
+	Journal journal.Repository          `inject:"journalRepository"`
 }
 
 func (t *TblPurchaseMaterialReceiveRepository) Create(ctx context.Context, data *tblpurchasematerialreceive.Create) (*tblpurchasematerialreceive.Create, error) {
+	if err := checkWarehouse(ctx, data.WhsCode); err != nil {
+		return nil, err
+	}
+	if err := checkSite(ctx, data.SiteCode.String); err != nil {
+		return nil, err
+	}
+
+	// transaction begin
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	// Pastikan rollback dipanggil jika transaksi tidak berhasil
+	defer func() {
+		if err != nil {
+			// Rollback jika error terjadi
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	data.DocNo, err = t.ID.GenerateIDForSite(ctx, tx, "PurchaseMaterialReceive", data.SiteCode.String, data.Date)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
+	for i := range data.Details {
+		data.Details[i].Source = fmt.Sprintf("%s*%s*%s", data.Date[6:8], data.DocNo, data.Details[i].DNo)
+	}
+
+	// qty diterima dalam UoM beli, stok dicatat dalam UoM inventory
+	itemCodes := make([]string, 0, len(data.Details))
+	for _, detail := range data.Details {
+		itemCodes = append(itemCodes, detail.ItCode)
+	}
+	var factors map[string]float32
+	if factors, err = t.Uom.ItemFactors(ctx, tx, itemCodes, uomconversion.Purchase, uomconversion.Inventory); err != nil {
+		return nil, err
+	}
+	for i := range data.Details {
+		data.Details[i].InventoryQty = data.Details[i].PurchaseQty * factors[data.Details[i].ItCode]
+	}
+
 	query := `INSERT INTO tblpurchasematerialreceivehdr 
 	(
 		DocNo,
@@ -50,24 +110,6 @@ func (t *TblPurchaseMaterialReceiveRepository) Create(ctx context.Context, data
 		data.CreateBy,
 	)
 
-	// transaction begin
-	var err error
-	tx, err := t.DB.BeginTxx(ctx, nil)
-	if err != nil {
-		log.Printf("Failed to start transaction: %+v", err)
-		return nil, fmt.Errorf("error starting transaction: %w", err)
-	}
-
-	// Pastikan rollback dipanggil jika transaksi tidak berhasil
-	defer func() {
-		if err != nil {
-			// Rollback jika error terjadi
-			if rbErr := tx.Rollback(); rbErr != nil {
-				log.Printf("Failed to rollback transaction: %+v", rbErr)
-			}
-		}
-	}()
-
 	// insert header
 	query += strings.Join(placeholders, "")
 	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
@@ -97,55 +139,7 @@ func (t *TblPurchaseMaterialReceiveRepository) Create(ctx context.Context, data
 		placeholders = placeholders[:0]
 		args = args[:0]
 
-		// stock summary query
-		queryStockSummary := `INSERT INTO tblstocksummary (
-			WhsCode,
-			Lot,
-			Bin,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersStockSummary []string
-		var argsStockSummary []interface{}
-
-		// stock movement query
-		queryStockMovement := `INSERT INTO tblstockmovement (
-			DocType,
-			DocNo,
-			DNo,
-			CancelInd,
-			DocDt,
-			WhsCode,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			Remark,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersStockMovement []string
-		var argsStockMovement []interface{}
-
-		// stock history of stock
-		queryHistory := `INSERT INTO tblhistoryofstock (
-			ItCode,
-			BatchNo,
-			Source,
-			CancelInd,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersHistory []string
-		var argsHistory []interface{}
+		var movements []inventoryledger.Movement
 
 		// order report
 		queryOrder := `INSERT INTO tblorderreport (
@@ -189,7 +183,7 @@ func (t *TblPurchaseMaterialReceiveRepository) Create(ctx context.Context, data
 				detail.Source,
 				detail.OutstandingQty,
 				detail.PurchaseQty,
-				0,
+				detail.InventoryQty,
 				detail.Remark,
 				data.CreateDt,
 				data.CreateBy,
@@ -208,52 +202,21 @@ func (t *TblPurchaseMaterialReceiveRepository) Create(ctx context.Context, data
 			wheresMatReq = append(wheresMatReq, "(?, ?)")
 			argsInMatReq = append(argsInMatReq, detail.PurchaseOrderDocNo, detail.PurchaseOrderDNo)
 
-			// stock summary
-			placeholdersStockSummary = append(placeholdersStockSummary, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-			argsStockSummary = append(argsStockSummary,
-				data.WhsCode,
-				"-",
-				"-",
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				detail.PurchaseQty,
-				0,
-				data.CreateBy,
-				data.Date,
-			)
-
-			// stock movement
-			placeholdersStockMovement = append(placeholdersStockMovement, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-			argsStockMovement = append(argsStockMovement,
-				"Purchase Material Receive",
-				data.DocNo,
-				detail.DNo,
-				detail.CancelInd,
-				data.Date,
-				data.WhsCode,
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				detail.PurchaseQty,
-				0,
-				data.Remark,
-				data.CreateBy,
-				data.Date,
-			)
-
-			// history of stock
-			placeholdersHistory = append(placeholdersHistory, "(?, ?, ?, ?, ?, ?)")
-			argsHistory = append(argsHistory,
-				detail.ItCode,
-				detail.BatchNo,
-				detail.Source,
-				"N",
-				data.CreateBy,
-				data.Date,
-			)
+			movements = append(movements, inventoryledger.Movement{
+				DocType:   "Purchase Material Receive",
+				DocNo:     data.DocNo,
+				DNo:       fmt.Sprintf("%03d", i+1),
+				DocDt:     data.Date,
+				WhsCode:   data.WhsCode,
+				Source:    detail.Source,
+				ItCode:    detail.ItCode,
+				BatchNo:   detail.BatchNo,
+				Qty:       detail.InventoryQty,
+				Direction: inventoryledger.In,
+				Remark:    data.Remark,
+				CreateBy:  data.CreateBy,
+				CreateDt:  data.Date,
+			})
 
 			// order report
 			placeholdersOrder = append(placeholdersOrder, "(?, ?, ?, ?, ?, ?, ?, ?, ?)")
@@ -277,25 +240,57 @@ func (t *TblPurchaseMaterialReceiveRepository) Create(ctx context.Context, data
 			return nil, fmt.Errorf("error Insert Detail: %w", err)
 		}
 
-		// insert stock summary
-		queryStockSummary += strings.Join(placeholdersStockSummary, ",") + `;`
-		if _, err = tx.ExecContext(ctx, queryStockSummary, argsStockSummary...); err != nil {
-			log.Printf("Error insert stock summary: %+v", err)
-			return nil, fmt.Errorf("error Insert Stock Summary: %w", err)
+		// harga pokok barang masuk diambil dari harga PO, dikonversi ke base
+		// dengan kurs tanggal penerimaan. Harga PO per UoM beli sedangkan qty
+		// movement dalam UoM inventory, jadi dibagi faktor konversinya.
+		var prices map[string]purchaseOrderPrice
+		if prices, err = purchaseOrderPrices(ctx, tx, wheresPurchaseOrderDtl, argsInPurchaseOrderDtl); err != nil {
+			return nil, err
 		}
-
-		// insert stock movement
-		queryStockMovement += strings.Join(placeholdersStockMovement, ",") + ";"
-		if _, err = tx.ExecContext(ctx, queryStockMovement, argsStockMovement...); err != nil {
-			log.Printf("Error insert stock movement: %+v", err)
-			return nil, fmt.Errorf("error Insert Stock Movement: %w", err)
+		var currencies []string
+		for _, p := range prices {
+			currencies = append(currencies, p.CurCode)
+		}
+		var rates map[string]float64
+		if rates, err = t.Rate.Rates(ctx, sharedfunc.UniqueStringSlice(currencies), data.BaseCurrency, data.Date); err != nil {
+			return nil, err
+		}
+		// pajak mengikuti breakdown baris PO, sebanding qty yang diterima
+		var poTaxes map[string]float64
+		if poTaxes, err = t.purchaseOrderTaxes(ctx, data.Details); err != nil {
+			return nil, err
+		}
+		var taxes []journal.Tax
+		for i, detail := range data.Details {
+			key := detail.PurchaseOrderDocNo + "*" + detail.PurchaseOrderDNo
+			price := prices[key]
+			rate, ok := rates[price.CurCode]
+			if price.CurCode != "" && !ok {
+				err = fmt.Errorf("%w: %s to %s on %s", customerrors.ErrExchangeRate, price.CurCode, data.BaseCurrency, data.Date)
+				return nil, err
+			}
+			movements[i].UnitCost = price.Price * float32(rate) / factors[detail.ItCode]
+
+			if price.Qty > 0 {
+				taxes = append(taxes, journal.Tax{
+					DocType:  "Purchase Material Receive",
+					DocNo:    data.DocNo,
+					DNo:      movements[i].DNo,
+					DocDt:    data.Date,
+					WhsCode:  data.WhsCode,
+					ItCode:   detail.ItCode,
+					Amount:   float32(poTaxes[key] * float64(detail.PurchaseQty/price.Qty) * rate),
+					CreateBy: data.CreateBy,
+					CreateDt: data.CreateDt,
+				})
+			}
 		}
 
-		// insert history
-		queryHistory += strings.Join(placeholdersHistory, ",") + ";"
-		if _, err = tx.ExecContext(ctx, queryHistory, argsHistory...); err != nil {
-			log.Printf("Error insert history of stock: %+v", err)
-			return nil, fmt.Errorf("error Insert History of Stock: %w", err)
+		if err = t.Ledger.Post(ctx, tx, movements); err != nil {
+			return nil, err
+		}
+		if err = t.Journal.PostTax(ctx, tx, taxes); err != nil {
+			return nil, err
 		}
 
 		// update purchase order detail
@@ -458,6 +453,16 @@ func (t *TblPurchaseMaterialReceiveRepository) Fetch(ctx context.Context, doc, w
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "WhsCode"); scope != "" {
+		countQuery += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
+	if scope, scopeArgs := siteScope(ctx, "SiteCode"); scope != "" {
+		countQuery += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
 		return nil, fmt.Errorf("error counting records: %w", err)
 	}
@@ -498,6 +503,16 @@ func (t *TblPurchaseMaterialReceiveRepository) Fetch(ctx context.Context, doc, w
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "t.WhsCode"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
+	if scope, scopeArgs := siteScope(ctx, "t.SiteCode"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	query += " LIMIT ? OFFSET ?"
 	args = append(args, param.PageSize, offset)
 
@@ -553,6 +568,7 @@ func (t *TblPurchaseMaterialReceiveRepository) Fetch(ctx context.Context, doc, w
 				d.Source,
 				d.OutstandingQty,
 				d.PurchaseQty,
+				d.InventoryQty,
 				d.Remark
 			FROM tblpurchasematerialreceivedtl d
 			LEFT JOIN tblitem i ON d.ItCode = i.ItCode
@@ -605,8 +621,10 @@ func (t *TblPurchaseMaterialReceiveRepository) Update(ctx context.Context, lastU
 	var resultDetail sql.Result
 	var rowsAffectedDtl int64
 
-	var placeholders, placeholdersStockSummary, placeholdersEdit, inTuples []string
-	var args, argsStockSummary, argsEdit, argsIn, argsInSum []interface{}
+	var placeholders, placeholdersEdit, inTuples []string
+	var args, argsEdit, argsIn []interface{}
+	var movements []inventoryledger.Movement
+	var cancelled []string
 
 	var err error
 
@@ -632,22 +650,37 @@ func (t *TblPurchaseMaterialReceiveRepository) Update(ctx context.Context, lastU
 		}
 	}()
 
+	audit, err := newAuditTrail(ctx, tx, "PurchaseMaterialReceive", data.DocNo)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkDocWarehouse(ctx, tx, "tblpurchasematerialreceivehdr", "WhsCode", data.DocNo); err != nil {
+		return nil, err
+	}
+
+	existingCancels, err := cancelIndByDNo(ctx, tx, "tblpurchasematerialreceivedtl", data.DocNo)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, detail := range data.Details {
 		// detail cancel
 		placeholders = append(placeholders, ` WHEN DocNo = ? AND DNo = ? THEN ? `)
 		args = append(args, data.DocNo, detail.DNo, detail.CancelInd)
 
-		// min qty
-		placeholdersStockSummary = append(placeholdersStockSummary, ` WHEN WhsCode = ? AND Source = ? AND ItCode = ? THEN (Qty2 - ?) `)
-		argsStockSummary = append(argsStockSummary, data.WhsCode, detail.Source, detail.ItCode, detail.PurchaseQty)
+		prevCancel, exists := existingCancels[detail.DNo]
+		if exists && !prevCancel.ToBool() && detail.CancelInd.ToBool() {
+			movements = append(movements, reverseLine("Purchase Material Receive", data.DocNo, detail.DNo, lastUpby, lastUpDate))
+			cancelled = append(cancelled, detail.DNo)
+		}
 
-		// edit cancel status on history of stock and stock movement and order report
+		// edit cancel status on order report
 		placeholdersEdit = append(placeholdersEdit, ` WHEN  ItCode = ? AND Source = ? AND BatchNo = ? THEN ? `)
 		argsEdit = append(argsEdit, detail.ItCode, detail.Source, detail.BatchNo, detail.CancelInd)
 
 		inTuples = append(inTuples, "(?, ?, ?)")
 		argsIn = append(argsIn, detail.ItCode, detail.Source, detail.BatchNo)
-		argsInSum = append(argsInSum, data.WhsCode, detail.Source, detail.ItCode)
 	}
 
 	query := `UPDATE tblpurchasematerialreceivedtl
@@ -674,54 +707,15 @@ func (t *TblPurchaseMaterialReceiveRepository) Update(ctx context.Context, lastU
 		return data, err
 	}
 
-	// Update stock summary
-	query = `UPDATE tblstocksummary
-		SET Qty2 = CASE
-			` + strings.Join(placeholdersStockSummary, " ") + `
-			ELSE Qty2
-		END
-		WHERE (WhsCode, Source, ItCode) IN (` + strings.Join(inTuples, ",") + `)
-	`
-	argsStockSummary = append(argsStockSummary, argsInSum...)
-
-	if _, err := tx.ExecContext(ctx, query, argsStockSummary...); err != nil {
-		log.Printf("Failed to update stock summary: %+v", err)
-		return nil, fmt.Errorf("error updating stock summary: %w", err)
+	if err = t.Ledger.Reverse(ctx, tx, movements); err != nil {
+		return nil, err
 	}
-
-	// Update history of stock
-	query = `UPDATE tblhistoryofstock
-		SET CancelInd = CASE
-			` + strings.Join(placeholdersEdit, " ") + `
-			ELSE CancelInd
-		END
-		WHERE (ItCode, Source, BatchNo) IN (` + strings.Join(inTuples, ",") + `)
-	`
-
-	fmt.Println("Query history of stock: ", query)
-	argsEdit = append(argsEdit, argsIn...)
-	fmt.Println("args history of stock: ", argsEdit)
-	if _, err := tx.ExecContext(ctx, query, argsEdit...); err != nil {
-		log.Printf("Failed to update history of stock: %+v", err)
-		return nil, fmt.Errorf("error updating history of stock: %w", err)
-	}
-
-	// Update stock movement
-	query = `UPDATE tblstockmovement
-		SET CancelInd = CASE
-			` + strings.Join(placeholdersEdit, " ") + `
-			ELSE CancelInd
-		END
-		WHERE (ItCode, Source, BatchNo) IN (` + strings.Join(inTuples, ",") + `)
-	`
-	fmt.Println("Query stock sum: ", query)
-	fmt.Println("args stock sum: ", argsEdit)
-	if _, err := tx.ExecContext(ctx, query, argsEdit...); err != nil {
-		log.Printf("Failed to update stock movement: %+v", err)
-		return nil, fmt.Errorf("error updating stock movement: %w", err)
+	if err = t.Journal.ReverseTax(ctx, tx, "Purchase Material Receive", data.DocNo, cancelled, lastUpby, lastUpDate); err != nil {
+		return nil, err
 	}
 
 	// Update order report
+	argsEdit = append(argsEdit, argsIn...)
 	query = `UPDATE tblorderreport
 		SET CancelInd = CASE
 			` + strings.Join(placeholdersEdit, " ") + `
@@ -866,10 +860,8 @@ func (t *TblPurchaseMaterialReceiveRepository) Update(ctx context.Context, lastU
 	}
 
 	// Update log activity
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
 	fmt.Println("--Update Log--")
-	if _, err = tx.ExecContext(ctx, query, lastUpby, data.DocNo, "DirectPurchaseReceive", lastUpDate); err != nil {
+	if err = audit.Write(ctx, tx, lastUpby, lastUpDate); err != nil {
 		log.Printf("Detailed error: %+v", err)
 		return nil, fmt.Errorf("error inserting log activity: %w", err)
 	}
@@ -911,6 +903,11 @@ func (t *TblPurchaseMaterialReceiveRepository) Reporting(ctx context.Context, do
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "pmrh.WhsCode"); scope != "" {
+		countQuery += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	countQuery += ` GROUP BY pmrd.DocNo, pmrd.DNo
 		) AS grouped`
 
@@ -931,6 +928,7 @@ func (t *TblPurchaseMaterialReceiveRepository) Reporting(ctx context.Context, do
 	}
 
 	var data []*tblpurchasematerialreceive.Reporting
+	args = []interface{}{searchDoc, searchWhs, searchVendor, searchItem}
 
 	query := `SELECT
 				pmrd.DocNo,
@@ -963,6 +961,11 @@ func (t *TblPurchaseMaterialReceiveRepository) Reporting(ctx context.Context, do
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "pmrh.WhsCode"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	query += " GROUP BY pmrd.DocNo, pmrd.DNo LIMIT ? OFFSET ?"
 	args = append(args, param.PageSize, offset)
 
@@ -1000,3 +1003,60 @@ func (t *TblPurchaseMaterialReceiveRepository) Reporting(ctx context.Context, do
 
 	return response, nil
 }
+
+type purchaseOrderPrice struct {
+	DocNo   string  `db:"DocNo"`
+	DNo     string  `db:"DNo"`
+	CurCode string  `db:"CurCode"`
+	Price   float32 `db:"Price"`
+	Qty     float32 `db:"Qty"`
+}
+
+// purchaseOrderPrices mengambil harga per unit detail PO (dari vendor quotation)
+// beserta mata uangnya, key-nya "DocNo*DNo"
+func purchaseOrderPrices(ctx context.Context, tx *sqlx.Tx, tuples []string, args []interface{}) (map[string]purchaseOrderPrice, error) {
+	var rows []purchaseOrderPrice
+
+	query := `SELECT d.DocNo, d.DNo, vqh.CurCode, vqd.Price, d.Qty
+		FROM tblpurchaseorderdtl d
+		JOIN tblpurchaseorderreqdtl por ON d.PurchaseOrderReqDocNo = por.DocNo AND d.PurchaseOrderReqDNo = por.DNo
+		JOIN tblvendorquotationdtl vqd ON por.VendorQTDocNo = vqd.DocNo AND por.VendorQTDNo = vqd.DNo
+		JOIN tblvendorquotationhdr vqh ON vqd.DocNo = vqh.DocNo
+		WHERE (d.DocNo, d.DNo) IN (` + strings.Join(tuples, ", ") + `)`
+	if err := tx.SelectContext(ctx, &rows, query, args...); err != nil {
+		log.Printf("Error get purchase order price: %+v", err)
+		return nil, fmt.Errorf("error get purchase order price: %w", err)
+	}
+
+	prices := make(map[string]purchaseOrderPrice, len(rows))
+	for _, row := range rows {
+		prices[row.DocNo+"*"+row.DNo] = row
+	}
+	return prices, nil
+}
+
+// purchaseOrderTaxes total pajak per baris PO dalam mata uang PO (PPh negatif),
+// key-nya "DocNo*DNo"
+func (t *TblPurchaseMaterialReceiveRepository) purchaseOrderTaxes(ctx context.Context, details []tblpurchasematerialreceive.Detail) (map[string]float64, error) {
+	docNos := make([]string, 0, len(details))
+	for _, detail := range details {
+		docNos = append(docNos, detail.PurchaseOrderDocNo)
+	}
+
+	breakdowns, err := t.Tax.Breakdowns(ctx, taxengine.PurchaseOrder, sharedfunc.UniqueStringSlice(docNos))
+	if err != nil {
+		return nil, err
+	}
+
+	taxes := make(map[string]float64)
+	for docNo, lines := range breakdowns {
+		for _, line := range lines {
+			amount := line.TaxAmount
+			if line.Withholding.ToBool() {
+				amount = -amount
+			}
+			taxes[docNo+"*"+line.DNo] += amount
+		}
+	}
+	return taxes, nil
+}
@@ -10,18 +10,56 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/docapproval"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/taxengine"
 	"gitlab.com/ayaka/internal/domain/tblpurchaseorder"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
 
 type TblPurchaseOrderRepository struct {
-	DB *repository.Sqlx `inject:"database"`
+	DB       *repository.Sqlx            `inject:"database"`
+	ID       *formatid.GenerateIDHandler `inject:"generateID"`
+	Approval docapproval.Repository      `inject:"docApprovalRepository"`
+	Tax      taxengine.Repository        `inject:"taxEngineRepository"`
 }
 
 func (t *TblPurchaseOrderRepository) Create(ctx context.Context, data *tblpurchaseorder.Create) (*tblpurchaseorder.Create, error) {
+	// transaction begin
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	// Pastikan rollback dipanggil jika transaksi tidak berhasil
+	defer func() {
+		if err != nil {
+			// Rollback jika error terjadi
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	// nomor mengikuti site material request asal PO request
+	var site string
+	if len(data.Details) > 0 {
+		if site, err = purchaseRequestSite(ctx, tx, data.Details[0].PurchaseOrderReqDocNo); err != nil {
+			return nil, err
+		}
+	}
+
+	data.DocNo, err = t.ID.GenerateIDForSite(ctx, tx, "PurchaseOrder", site, data.Date)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
 	query := `INSERT INTO tblpurchaseorderhdr
 	(
 		DocNo,
@@ -31,13 +69,14 @@ func (t *TblPurchaseOrderRepository) Create(ctx context.Context, data *tblpurcha
 		ContactPersonDNo,
 		Remark,
 		TaxCode,
+		TaxInclusiveInd,
 		CreateBy,
 		CreateDt
 	) VALUES `
 	var args []interface{}
 	var placeholders []string
 
-	placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?);")
+	placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?);")
 	args = append(args,
 		data.DocNo,
 		data.Date,
@@ -46,28 +85,11 @@ func (t *TblPurchaseOrderRepository) Create(ctx context.Context, data *tblpurcha
 		data.ContactPersonDNo,
 		data.Remark,
 		data.TaxCode,
+		data.TaxInclusive,
 		data.CreateBy,
 		data.CreateDt,
 	)
 
-	// transaction begin
-	var err error
-	tx, err := t.DB.BeginTxx(ctx, nil)
-	if err != nil {
-		log.Printf("Failed to start transaction: %+v", err)
-		return nil, fmt.Errorf("error starting transaction: %w", err)
-	}
-
-	// Pastikan rollback dipanggil jika transaksi tidak berhasil
-	defer func() {
-		if err != nil {
-			// Rollback jika error terjadi
-			if rbErr := tx.Rollback(); rbErr != nil {
-				log.Printf("Failed to rollback transaction: %+v", rbErr)
-			}
-		}
-	}()
-
 	// insert header
 	query += strings.Join(placeholders, "")
 	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
@@ -149,6 +171,27 @@ func (t *TblPurchaseOrderRepository) Create(ctx context.Context, data *tblpurcha
 
 	}
 
+	// pajak per baris, default dari TaxCode header lalu tax group vendor
+	taxReq := &taxengine.Request{
+		VendorCode: data.VendorCode,
+		TaxCode:    data.TaxCode.String,
+		Inclusive:  data.TaxInclusive.ToBool(),
+	}
+	for _, detail := range data.Details {
+		taxReq.Lines = append(taxReq.Lines, taxengine.Line{
+			DNo:      detail.DNo,
+			Amount:   float64(detail.Qty * detail.UPrice),
+			TaxCodes: detail.TaxCodes,
+		})
+	}
+	if data.TaxBreakdown, err = t.Tax.Apply(ctx, tx, taxengine.PurchaseOrder, data.DocNo, taxReq); err != nil {
+		return nil, err
+	}
+
+	if err = t.Approval.Start(ctx, tx, docapproval.PurchaseOrder, data.DocNo, data.CreateBy, data.CreateDt); err != nil {
+		return nil, err
+	}
+
 	// Commit the transaction
 	if err = tx.Commit(); err != nil {
 		log.Printf("Failed to commit transaction: %+v", err)
@@ -200,10 +243,12 @@ func (t *TblPurchaseOrderRepository) Fetch(ctx context.Context, doc, startDate,
 			i.DocNo,
 			i.DocDt,
 			i.Status,
+			i.ApprovalStatus,
 			i.VendorCode,
 			v.VendorName,
 			i.ContactPersonDNo AS DNo,
 			i.TaxCode,
+			i.TaxInclusiveInd,
 			i.Remark
 			FROM tblpurchaseorderhdr i
 			JOIN tblvendorhdr v ON i.VendorCode = v.VendorCode
@@ -305,11 +350,15 @@ func (t *TblPurchaseOrderRepository) Fetch(ctx context.Context, doc, startDate,
 		detailMap[d.DocNo] = append(detailMap[d.DocNo], *d)
 	}
 
+	breakdowns, err := t.Tax.Breakdowns(ctx, taxengine.PurchaseOrder, docsNo)
+	if err != nil {
+		return nil, err
+	}
+
 	// Gabungkan header dengan detail
 	for _, h := range data {
 		h.Details = detailMap[h.DocNo]
 
-		var subtotal float32 = 0.0
 		var taxRate float32 = 0.0
 		// Ambil tax rate dari tbltax
 		if h.TaxCode.String != "" {
@@ -320,13 +369,30 @@ func (t *TblPurchaseOrderRepository) Fetch(ctx context.Context, doc, startDate,
 		}
 		h.TaxRate = taxRate
 
+		// baris yang dibatalkan tidak ikut dihitung
+		var lines []taxengine.Line
 		for i := range h.Details {
 			d := &h.Details[i]
-			subtotal += d.UPrice * d.Qty
+			if d.CancelInd.ToBool() {
+				continue
+			}
+			lines = append(lines, taxengine.Line{DNo: d.DNo, Amount: float64(d.UPrice * d.Qty)})
 		}
-		taxAmount := subtotal * taxRate
-		h.GrandTotal = subtotal + taxAmount
-		h.TotalTax = taxAmount
+
+		if taxes, ok := breakdowns[h.DocNo]; ok {
+			h.TaxBreakdown = taxengine.Summarize(h.TaxInclusive.ToBool(), lines, activeLineTaxes(lines, taxes))
+		} else {
+			// PO sebelum ada tbldoctaxdtl dihitung ulang dari TaxCode header
+			if h.TaxBreakdown, err = t.Tax.Calculate(ctx, &taxengine.Request{
+				TaxCode:   h.TaxCode.String,
+				Inclusive: h.TaxInclusive.ToBool(),
+				Lines:     lines,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		h.TotalTax = float32(h.TaxBreakdown.TotalTax)
+		h.GrandTotal = float32(h.TaxBreakdown.GrandTotal)
 	}
 
 	// response
@@ -375,6 +441,11 @@ func (t *TblPurchaseOrderRepository) Update(ctx context.Context, lastUpby, lastU
 		}
 	}()
 
+	audit, err := newAuditTrail(ctx, tx, "PurchaseOrder", data.DocNo)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `UPDATE tblpurchaseorderdtl
 			SET CancelInd = CASE 
 		`
@@ -449,10 +520,8 @@ func (t *TblPurchaseOrderRepository) Update(ctx context.Context, lastUpby, lastU
 	}
 
 	// Update log activity
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
 	fmt.Println("--Update Log--")
-	if _, err = tx.ExecContext(ctx, query, lastUpby, data.DocNo, "PurchaseOrder", lastUpDate); err != nil {
+	if err = audit.Write(ctx, tx, lastUpby, lastUpDate); err != nil {
 		log.Printf("Detailed error: %+v", err)
 		return nil, fmt.Errorf("error inserting log activity: %w", err)
 	}
@@ -500,6 +569,7 @@ func (t *TblPurchaseOrderRepository) GetPurchaseOrder(ctx context.Context, doc,
 		WHERE 
 			h.VendorCode LIKE ?
 			AND (d.CancelInd != 'Y') AND i.ItName LIKE ?
+			AND h.ApprovalStatus = 'Approved'
 			AND h.DocNo LIKE ?
 		HAVING 
 			OutstandingQty > 0
@@ -556,6 +626,7 @@ func (t *TblPurchaseOrderRepository) GetPurchaseOrder(ctx context.Context, doc,
 	WHERE 
 		h.VendorCode LIKE ?
 		AND (d.CancelInd != 'Y') AND i.ItName LIKE ?
+		AND h.ApprovalStatus = 'Approved'
 		AND h.DocNo LIKE ?
 	HAVING 
 		OutstandingQty > 0`
@@ -604,7 +675,7 @@ func (t *TblPurchaseOrderRepository) GetPurchaseOrder(ctx context.Context, doc,
 	return response, nil
 }
 
-func (t *TblPurchaseOrderRepository) OutstandingPO(ctx context.Context, doc, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+func (t *TblPurchaseOrderRepository) OutstandingPO(ctx context.Context, doc, startDate, endDate, base string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
 	var totalRecords int
 
 	searchDoc := "%" + doc + "%"
@@ -683,7 +754,7 @@ func (t *TblPurchaseOrderRepository) OutstandingPO(ctx context.Context, doc, sta
 	}
 
 	var data []*tblpurchaseorder.OutstandingPO
-	args = args[:0]
+	args = baseRateArgs(base)
 
 	query := `SELECT 
 			d.DocNo,
@@ -702,7 +773,9 @@ func (t *TblPurchaseOrderRepository) OutstandingPO(ctx context.Context, doc, sta
 			u.UomName,
 			c.CurName,
 			(d.Total / d.Qty) AS Price,
-			d.Total
+			d.Total,
+			vq.CurCode,
+			` + baseRateSQL("vq.CurCode", "h.DocDt") + ` AS Rate
 		FROM 
 			tblpurchaseorderdtl d
 		JOIN 
@@ -759,6 +832,9 @@ func (t *TblPurchaseOrderRepository) OutstandingPO(ctx context.Context, doc, sta
 	for _, detail := range data {
 		j++
 		detail.Number = uint(j)
+		detail.BaseCurrency = base
+		detail.BaseTotal = float64(detail.Total) * detail.Rate
+		detail.RateMissing = detail.Rate == 0
 	}
 
 	// response
@@ -10,8 +10,10 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/docapproval"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	sharedfunc "gitlab.com/ayaka/internal/domain/shared/sharedFunc"
 	"gitlab.com/ayaka/internal/domain/tblpurchaseorderrequest"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
@@ -19,10 +21,44 @@ import (
 )
 
 type TblPurchaseOrderRequestRepository struct {
-	DB *repository.Sqlx `inject:"database"`
+	DB       *repository.Sqlx            `inject:"database"`
+	ID       *formatid.GenerateIDHandler `inject:"generateID"`
+	Approval docapproval.Repository      `inject:"docApprovalRepository"`
 }
 
 func (t *TblPurchaseOrderRequestRepository) Create(ctx context.Context, data *tblpurchaseorderrequest.Create) (*tblpurchaseorderrequest.Create, error) {
+	// transaction begin
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	// Pastikan rollback dipanggil jika transaksi tidak berhasil
+	defer func() {
+		if err != nil {
+			// Rollback jika error terjadi
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	// nomor mengikuti site material request asal
+	var site string
+	if len(data.Details) > 0 {
+		if site, err = materialRequestSite(ctx, tx, data.Details[0].MaterialReqDocNo); err != nil {
+			return nil, err
+		}
+	}
+
+	data.DocNo, err = t.ID.GenerateIDForSite(ctx, tx, "PurchaseOrderRequest", site, data.Date)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
 	query := `INSERT INTO tblpurchaseorderreqhdr
 	(
 		DocNo,
@@ -43,24 +79,6 @@ func (t *TblPurchaseOrderRequestRepository) Create(ctx context.Context, data *tb
 		data.CreateDt,
 	)
 
-	// transaction begin
-	var err error
-	tx, err := t.DB.BeginTxx(ctx, nil)
-	if err != nil {
-		log.Printf("Failed to start transaction: %+v", err)
-		return nil, fmt.Errorf("error starting transaction: %w", err)
-	}
-
-	// Pastikan rollback dipanggil jika transaksi tidak berhasil
-	defer func() {
-		if err != nil {
-			// Rollback jika error terjadi
-			if rbErr := tx.Rollback(); rbErr != nil {
-				log.Printf("Failed to rollback transaction: %+v", rbErr)
-			}
-		}
-	}()
-
 	// insert header
 	query += strings.Join(placeholders, "")
 	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
@@ -224,6 +242,10 @@ func (t *TblPurchaseOrderRequestRepository) Create(ctx context.Context, data *tb
 		}
 	}
 
+	if err = t.Approval.Start(ctx, tx, docapproval.PurchaseOrderRequest, data.DocNo, data.CreateBy, data.CreateDt); err != nil {
+		return nil, err
+	}
+
 	// Commit the transaction
 	if err = tx.Commit(); err != nil {
 		log.Printf("Failed to commit transaction: %+v", err)
@@ -274,6 +296,7 @@ func (t *TblPurchaseOrderRequestRepository) Fetch(ctx context.Context, doc, star
 	query := `SELECT 
 			i.DocNo,
 			i.DocDt,
+			i.ApprovalStatus,
 			i.Remark
 			FROM tblpurchaseorderreqhdr i
 			WHERE i.DocNo LIKE ?`
@@ -433,6 +456,11 @@ func (t *TblPurchaseOrderRequestRepository) Update(ctx context.Context, lastUpby
 		}
 	}()
 
+	audit, err := newAuditTrail(ctx, tx, "PurchaseOrderRequest", data.DocNo)
+	if err != nil {
+		return nil, err
+	}
+
 	// material request
 	var whensMatReq, whensOpenIndMatReq []string
 	var wheresMatReq []string
@@ -565,10 +593,8 @@ func (t *TblPurchaseOrderRequestRepository) Update(ctx context.Context, lastUpby
 	}
 
 	// Update log activity
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
 	fmt.Println("--Update Log--")
-	if _, err = tx.ExecContext(ctx, query, lastUpby, data.DocNo, "PurchaseOrderRequest", lastUpDate); err != nil {
+	if err = audit.Write(ctx, tx, lastUpby, lastUpDate); err != nil {
 		log.Printf("Detailed error: %+v", err)
 		return nil, fmt.Errorf("error inserting log activity: %w", err)
 	}
@@ -594,6 +620,7 @@ func (t *TblPurchaseOrderRequestRepository) GetVendorQuotation(ctx context.Conte
 					d.DocNo,
 					d.DNo
 				FROM tblpurchaseorderreqdtl d
+				JOIN tblpurchaseorderreqhdr h ON d.DocNo = h.DocNo
 				JOIN tblitem i ON d.ItCode = i.ItCode
 				JOIN tbluom u ON i.PurchaseUOMCode = u.UomCode
 				JOIN tblvendorquotationhdr vqh
@@ -602,7 +629,7 @@ func (t *TblPurchaseOrderRequestRepository) GetVendorQuotation(ctx context.Conte
 					ON d.VendorQTDocNo = vqd.DocNo
 					AND d.VendorQTDNo = vqd.DNo
 				JOIN tblcurrency c ON vqh.CurCode = c.CurCode
-				WHERE d.VendorCode LIKE ? AND i.ItName LIKE ? AND (d.CancelInd = 'N' AND d.SuccessInd = 'N')
+				WHERE d.VendorCode LIKE ? AND i.ItName LIKE ? AND (d.CancelInd = 'N' AND d.SuccessInd = 'N') AND h.ApprovalStatus = 'Approved'
 			) AS grouped`
 	args = append(args, searchDoc, searchItem)
 
@@ -643,6 +670,7 @@ func (t *TblPurchaseOrderRequestRepository) GetVendorQuotation(ctx context.Conte
 				vqd.Price,
 				vqh.DeliveryType
 			FROM tblpurchaseorderreqdtl d
+			JOIN tblpurchaseorderreqhdr h ON d.DocNo = h.DocNo
 			JOIN tblitem i ON d.ItCode = i.ItCode
 			JOIN tbluom u ON i.PurchaseUOMCode = u.UomCode
 			JOIN tblvendorquotationhdr vqh
@@ -651,7 +679,7 @@ func (t *TblPurchaseOrderRequestRepository) GetVendorQuotation(ctx context.Conte
 				ON d.VendorQTDocNo = vqd.DocNo
 				AND d.VendorQTDNo = vqd.DNo
 			JOIN tblcurrency c ON vqh.CurCode = c.CurCode
-			WHERE d.VendorCode LIKE ? AND i.ItName LIKE ? AND (d.CancelInd = 'N' AND d.SuccessInd = 'N')`
+			WHERE d.VendorCode LIKE ? AND i.ItName LIKE ? AND (d.CancelInd = 'N' AND d.SuccessInd = 'N') AND h.ApprovalStatus = 'Approved'`
 	args = append(args, searchDoc, searchItem)
 
 	query += " LIMIT ? OFFSET ?"
@@ -692,3 +720,17 @@ func (t *TblPurchaseOrderRequestRepository) GetVendorQuotation(ctx context.Conte
 
 	return response, nil
 }
+
+// purchaseRequestSite site material request asal PO request, kosong kalau tidak ada
+func purchaseRequestSite(ctx context.Context, tx *sqlx.Tx, docNo string) (string, error) {
+	var site string
+	query := `SELECT COALESCE(m.SiteCode, '')
+		FROM tblpurchaseorderreqdtl r
+		JOIN tblmaterialrequesthdr m ON r.MaterialReqDocNo = m.DocNo
+		WHERE r.DocNo = ?
+		LIMIT 1`
+	if err := tx.GetContext(ctx, &site, query, docNo); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("error get purchase order request site: %w", err)
+	}
+	return site, nil
+}
@@ -10,19 +10,61 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/batch"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
 	share "gitlab.com/ayaka/internal/domain/shared"
-	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
 	"gitlab.com/ayaka/internal/domain/tblpurchasereturndelivery"
 
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
 
 type TblPurchaseReturnDeliveryRepository struct {
-	DB *repository.Sqlx `inject:"database"`
+	DB     *repository.Sqlx            `inject:"database"`
+	Ledger inventoryledger.Repository  `inject:"inventoryLedgerRepository"`
+	ID     *formatid.GenerateIDHandler `inject:"generateID"`
+	Batch  batch.Repository            `inject:"batchRepository"`
 }
 
 func (t *TblPurchaseReturnDeliveryRepository) Create(ctx context.Context, data *tblpurchasereturndelivery.Create) (*tblpurchasereturndelivery.Create, error) {
+	if err := checkWarehouse(ctx, data.WhsCode); err != nil {
+		return nil, err
+	}
+
+	// transaction begin
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	// Pastikan rollback dipanggil jika transaksi tidak berhasil
+	defer func() {
+		if err != nil {
+			// Rollback jika error terjadi
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	// retur batch kedaluwarsa juga diblok, sesuai aturan pengeluaran barang
+	lines := make([]batch.Line, 0, len(data.Details))
+	for _, d := range data.Details {
+		lines = append(lines, batch.Line{ItemCode: d.ItCode, Batch: d.BatchNo})
+	}
+	if err = t.Batch.CheckExpired(ctx, tx, data.Date, lines); err != nil {
+		return nil, err
+	}
+
+	data.DocNo, err = t.ID.GenerateID(ctx, tx, "PurchaseReturnDelivery", data.Date)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
 	query := `INSERT INTO tblpurchasereturndeliveryhdr 
 	(
 		DocNo,
@@ -48,24 +90,6 @@ func (t *TblPurchaseReturnDeliveryRepository) Create(ctx context.Context, data *
 		data.CreateBy,
 	)
 
-	// transaction begin
-	var err error
-	tx, err := t.DB.BeginTxx(ctx, nil)
-	if err != nil {
-		log.Printf("Failed to start transaction: %+v", err)
-		return nil, fmt.Errorf("error starting transaction: %w", err)
-	}
-
-	// Pastikan rollback dipanggil jika transaksi tidak berhasil
-	defer func() {
-		if err != nil {
-			// Rollback jika error terjadi
-			if rbErr := tx.Rollback(); rbErr != nil {
-				log.Printf("Failed to rollback transaction: %+v", rbErr)
-			}
-		}
-	}()
-
 	// insert header
 	query += strings.Join(placeholders, "")
 	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
@@ -94,55 +118,7 @@ func (t *TblPurchaseReturnDeliveryRepository) Create(ctx context.Context, data *
 		placeholders = placeholders[:0]
 		args = args[:0]
 
-		// stock summary query
-		queryStockSummary := `INSERT INTO tblstocksummary (
-			WhsCode,
-			Lot,
-			Bin,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersStockSummary []string
-		var argsStockSummary []interface{}
-
-		// stock movement query
-		queryStockMovement := `INSERT INTO tblstockmovement (
-			DocType,
-			DocNo,
-			DNo,
-			CancelInd,
-			DocDt,
-			WhsCode,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			Remark,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersStockMovement []string
-		var argsStockMovement []interface{}
-
-		// history of stock
-		queryHistory := `INSERT INTO tblhistoryofstock (
-			ItCode,
-			BatchNo,
-			Source,
-			CancelInd,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersHistory []string
-		var argsHistory []interface{}
+		var movements []inventoryledger.Movement
 
 		for i, detail := range data.Details {
 			// detail
@@ -163,52 +139,21 @@ func (t *TblPurchaseReturnDeliveryRepository) Create(ctx context.Context, data *
 				data.CreateBy,
 			)
 
-			// stock summary
-			placeholdersStockSummary = append(placeholdersStockSummary, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-			argsStockSummary = append(argsStockSummary,
-				data.WhsCode,
-				"-",
-				"-",
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				0,
-				detail.Qty,
-				data.CreateBy,
-				data.Date,
-			)
-
-			// stock movement
-			placeholdersStockMovement = append(placeholdersStockMovement, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-			argsStockMovement = append(argsStockMovement,
-				"Purchase Return Delivery",
-				data.DocNo,
-				detail.DNo,
-				detail.CancelInd,
-				data.Date,
-				data.WhsCode,
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				0,
-				detail.Qty,
-				data.Remark,
-				data.CreateBy,
-				data.Date,
-			)
-
-			// history of stock
-			placeholdersHistory = append(placeholdersHistory, "(?, ?, ?, ?, ?, ?)")
-			argsHistory = append(argsHistory,
-				detail.ItCode,
-				detail.BatchNo,
-				detail.Source,
-				"N",
-				data.CreateBy,
-				data.Date,
-			)
+			movements = append(movements, inventoryledger.Movement{
+				DocType:   "Purchase Return Delivery",
+				DocNo:     data.DocNo,
+				DNo:       fmt.Sprintf("%03d", i+1),
+				DocDt:     data.Date,
+				WhsCode:   data.WhsCode,
+				Source:    detail.Source,
+				ItCode:    detail.ItCode,
+				BatchNo:   detail.BatchNo,
+				Qty:       detail.Qty,
+				Direction: inventoryledger.Out,
+				Remark:    data.Remark,
+				CreateBy:  data.CreateBy,
+				CreateDt:  data.Date,
+			})
 		}
 
 		// insert detail
@@ -218,25 +163,8 @@ func (t *TblPurchaseReturnDeliveryRepository) Create(ctx context.Context, data *
 			return nil, fmt.Errorf("error Insert Detail: %w", err)
 		}
 
-		// insert stock summary
-		queryStockSummary += strings.Join(placeholdersStockSummary, ",") + `;`
-		if _, err = tx.ExecContext(ctx, queryStockSummary, argsStockSummary...); err != nil {
-			log.Printf("Error insert stock summary: %+v", err)
-			return nil, fmt.Errorf("error Insert Stock Summary: %w", err)
-		}
-
-		// insert stock movement
-		queryStockMovement += strings.Join(placeholdersStockMovement, ",") + ";"
-		if _, err = tx.ExecContext(ctx, queryStockMovement, argsStockMovement...); err != nil {
-			log.Printf("Error insert stock movement: %+v", err)
-			return nil, fmt.Errorf("error Insert Stock Movement: %w", err)
-		}
-
-		// insert history
-		queryHistory += strings.Join(placeholdersHistory, ",") + ";"
-		if _, err = tx.ExecContext(ctx, queryHistory, argsHistory...); err != nil {
-			log.Printf("Error insert history of stock: %+v", err)
-			return nil, fmt.Errorf("error Insert History of Stock: %w", err)
+		if err = t.Ledger.Post(ctx, tx, movements); err != nil {
+			return nil, err
 		}
 	}
 
@@ -267,6 +195,11 @@ func (t *TblPurchaseReturnDeliveryRepository) Fetch(ctx context.Context, doc, wa
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "p.WhsCode"); scope != "" {
+		countQuery += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
 		return nil, fmt.Errorf("error counting records: %w", err)
 	}
@@ -304,6 +237,11 @@ func (t *TblPurchaseReturnDeliveryRepository) Fetch(ctx context.Context, doc, wa
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "t.WhsCode"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	query += " LIMIT ? OFFSET ?"
 	args = append(args, param.PageSize, offset)
 
@@ -414,8 +352,9 @@ func (t *TblPurchaseReturnDeliveryRepository) Update(ctx context.Context, lastUp
 	var resultDetail sql.Result
 	var rowsAffectedDtl int64
 
-	var placeholders, placeholdersStockSummary, placeholdersEdit, inTuples, inTuplesSum []string
-	var args, argsStockSummary, argsEdit, argsIn, argsInSum []interface{}
+	var placeholders []string
+	var args []interface{}
+	var movements []inventoryledger.Movement
 
 	var err error
 
@@ -441,22 +380,18 @@ func (t *TblPurchaseReturnDeliveryRepository) Update(ctx context.Context, lastUp
 		}
 	}()
 
-	existingCancels := make(map[string]booldatatype.BoolDataType)
-
-	querySelect := `SELECT DNo, CancelInd FROM tblpurchasereturndeliverydtl WHERE DocNo = ?`
-	rows, err := t.DB.QueryxContext(ctx, querySelect, data.DocNo)
+	audit, err := newAuditTrail(ctx, tx, "PurchaseReturnDelivery", data.DocNo)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching existing cancel status: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var dno string
-		var cancel booldatatype.BoolDataType
-		if err := rows.Scan(&dno, &cancel); err != nil {
-			return nil, fmt.Errorf("error scanning cancel status: %w", err)
-		}
-		existingCancels[dno] = cancel
+	if err = checkDocWarehouse(ctx, tx, "tblpurchasereturndeliveryhdr", "WhsCode", data.DocNo); err != nil {
+		return nil, err
+	}
+
+	existingCancels, err := cancelIndByDNo(ctx, tx, "tblpurchasereturndeliverydtl", data.DocNo)
+	if err != nil {
+		return nil, err
 	}
 
 	for _, detail := range data.Details {
@@ -464,23 +399,10 @@ func (t *TblPurchaseReturnDeliveryRepository) Update(ctx context.Context, lastUp
 		placeholders = append(placeholders, ` WHEN DocNo = ? AND DNo = ? THEN ? `)
 		args = append(args, data.DocNo, detail.DNo, detail.CancelInd)
 
-		// stock sum
 		prevCancel, exists := existingCancels[detail.DNo]
 		if exists && !prevCancel.ToBool() && detail.CancelInd.ToBool() {
-			// Jika sebelumnya false dan sekarang true, maka kurangi Qty3
-			placeholdersStockSummary = append(placeholdersStockSummary,
-				` WHEN WhsCode = ? AND Source = ? AND ItCode = ? THEN (Qty3 - ?) `)
-			argsStockSummary = append(argsStockSummary, data.WhsCode, detail.Source, detail.ItCode, detail.Qty)
-			inTuplesSum = append(inTuplesSum, "(?, ?, ?)")
-			argsInSum = append(argsInSum, data.WhsCode, detail.Source, detail.ItCode)
+			movements = append(movements, reverseLine("Purchase Return Delivery", data.DocNo, detail.DNo, lastUpby, lastUpDate))
 		}
-
-		// edit cancel status on history of stock and stock movement
-		placeholdersEdit = append(placeholdersEdit, ` WHEN  ItCode = ? AND Source = ? AND BatchNo = ? THEN ? `)
-		argsEdit = append(argsEdit, detail.ItCode, detail.Source, detail.BatchNo, detail.CancelInd)
-
-		inTuples = append(inTuples, "(?, ?, ?)")
-		argsIn = append(argsIn, detail.ItCode, detail.Source, detail.BatchNo)
 	}
 
 	query := `UPDATE tblpurchasereturndeliverydtl
@@ -507,61 +429,13 @@ func (t *TblPurchaseReturnDeliveryRepository) Update(ctx context.Context, lastUp
 		return data, err
 	}
 
-	// Update stock summary
-	query = `UPDATE tblstocksummary
-		SET Qty3 = CASE
-			` + strings.Join(placeholdersStockSummary, " ") + `
-			ELSE Qty3
-		END
-		WHERE (WhsCode, Source, ItCode) IN (` + strings.Join(inTuplesSum, ",") + `)
-	`
-	argsStockSummary = append(argsStockSummary, argsInSum...)
-
-	fmt.Println("query sum: ", query)
-	fmt.Println("args stock sum: ", argsStockSummary)
-
-	if _, err := tx.ExecContext(ctx, query, argsStockSummary...); err != nil {
-		log.Printf("Failed to update stock summary: %+v", err)
-		return nil, fmt.Errorf("error updating stock summary: %w", err)
-	}
-
-	// Update history of stock
-	query = `UPDATE tblhistoryofstock
-		SET CancelInd = CASE
-			` + strings.Join(placeholdersEdit, " ") + `
-			ELSE CancelInd
-		END
-		WHERE (ItCode, Source, BatchNo) IN (` + strings.Join(inTuples, ",") + `)
-	`
-
-	fmt.Println("Query history of stock: ", query)
-	argsEdit = append(argsEdit, argsIn...)
-	fmt.Println("args history of stock: ", argsEdit)
-	if _, err := tx.ExecContext(ctx, query, argsEdit...); err != nil {
-		log.Printf("Failed to update history of stock: %+v", err)
-		return nil, fmt.Errorf("error updating history of stock: %w", err)
-	}
-
-	// Update stock movement
-	query = `UPDATE tblstockmovement
-		SET CancelInd = CASE
-			` + strings.Join(placeholdersEdit, " ") + `
-			ELSE CancelInd
-		END
-		WHERE (ItCode, Source, BatchNo) IN (` + strings.Join(inTuples, ",") + `)
-	`
-	fmt.Println("Query stock sum: ", query)
-	fmt.Println("args stock sum: ", argsEdit)
-	if _, err := tx.ExecContext(ctx, query, argsEdit...); err != nil {
-		log.Printf("Failed to update stock movement: %+v", err)
-		return nil, fmt.Errorf("error updating stock movement: %w", err)
+	if err = t.Ledger.Reverse(ctx, tx, movements); err != nil {
+		return nil, err
 	}
 
 	// Update log activity
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
 	fmt.Println("--Update Log--")
-	if _, err = tx.ExecContext(ctx, query, lastUpby, data.DocNo, "PurchaseReturnDelivery", lastUpDate); err != nil {
+	if err = audit.Write(ctx, tx, lastUpby, lastUpDate); err != nil {
 		log.Printf("Detailed error: %+v", err)
 		return nil, fmt.Errorf("error inserting log activity: %w", err)
 	}
@@ -581,6 +455,15 @@ func (t *TblPurchaseReturnDeliveryRepository) GetReturnMaterial(ctx context.Cont
 	var searchDoc = "%" + doc + "%"
 	var searchItem = "%" + item + "%"
 
+	// gudang di luar hak user dianggap tidak punya barang untuk diretur
+	if checkWarehouse(ctx, warehouse) != nil {
+		return &pagination.PaginationResponse{
+			Data:         make([]*tblpurchasereturndelivery.GetReturnMaterial, 0),
+			TotalRecords: 0,
+			TotalPages:   0,
+		}, nil
+	}
+
 	countQuery := `SELECT COUNT(*) AS total FROM (
 		SELECT
 			pmrd.DocNo,
@@ -0,0 +1,73 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/tblpurchasereturndelivery"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+const (
+	queryPurchaseReturnHeader = "INSERT INTO tblpurchasereturndeliveryhdr ( DocNo, DocDt, WhsCode, VendorCode, Remark, CreateDt, CreateBy ) VALUES (?, ?, ?, ?, ?, ?, ?);"
+	queryPurchaseReturnDetail = "INSERT INTO tblpurchasereturndeliverydtl ( DocNo, DNo, CancelInd, PurchaseMaterialReceiveDocNo, PurchaseMaterialReceiveDNo, ItCode, BatchNo, Source, Stock, Qty, Remark, CreateDt, CreateBy ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
+)
+
+type TblPurchaseReturnDeliveryRepositorySuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	repo    *TblPurchaseReturnDeliveryRepository
+	db      *sqlx.DB
+}
+
+func (suite *TblPurchaseReturnDeliveryRepositorySuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.repo = &TblPurchaseReturnDeliveryRepository{
+		DB:     &repository.Sqlx{DB: suite.db},
+		Ledger: &InventoryLedgerRepository{},
+		ID:     &formatid.GenerateIDHandler{},
+		Batch:  fakeBatch{},
+	}
+}
+
+func (suite *TblPurchaseReturnDeliveryRepositorySuite) TearDownTest() {
+	suite.db.Close()
+}
+
+// barang yang diretur keluar dari Source penerimaannya
+func (suite *TblPurchaseReturnDeliveryRepositorySuite) TestCreate_KeepsPickedSource() {
+	suite.mockSQL.ExpectBegin()
+	expectDocNo(suite.mockSQL, "PurchaseReturnDelivery")
+	suite.mockSQL.ExpectExec(queryPurchaseReturnHeader).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryPurchaseReturnDetail).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectPickedSourceLock(suite.mockSQL, "WH1", "IT001", "B1", "15*0001/R1/PMR/01/26*001")
+
+	_, err := suite.repo.Create(context.Background(), &tblpurchasereturndelivery.Create{
+		Date:    "20260120",
+		WhsCode: "WH1",
+		Details: []tblpurchasereturndelivery.Detail{{
+			PurchaseMaterialReceiveDocNO: "0001/R1/PMR/01/26",
+			PurchaseMaterialReceiveDNo:   "001",
+			ItCode:                       "IT001",
+			BatchNo:                      "B1",
+			Source:                       "15*0001/R1/PMR/01/26*001",
+			Qty:                          2,
+		}},
+	})
+
+	suite.ErrorIs(err, customerrors.ErrInsufficientStock)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func TestTblPurchaseReturnDeliveryRepository(t *testing.T) {
+	suite.Run(t, new(TblPurchaseReturnDeliveryRepositorySuite))
+}
@@ -11,8 +11,9 @@ import (
 	"log"
 	"strings"
 
-	// "github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
 	// share "gitlab.com/ayaka/internal/domain/shared"
 
 	// "gitlab.com/ayaka/internal/domain/shared/booldatatype"
@@ -22,7 +23,7 @@ import (
 
 	// "gitlab.com/ayaka/internal/domain/tblmasteritem"
 
-	// "gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
 
 	// "gitlab.com/ayaka/internal/pkg/customerrors"
 	// "gitlab.com/ayaka/internal/pkg/customerrors"
@@ -31,7 +32,9 @@ import (
 )
 
 type TblDirectPurchaseReceiveRepository struct {
-	DB *repository.Sqlx `inject:"database"`
+	DB     *repository.Sqlx            `inject:"database"`
+	Ledger inventoryledger.Repository  `inject:"inventoryLedgerRepository"`
+	ID     *formatid.GenerateIDHandler `inject:"generateID"`
 }
 
 func (t *TblDirectPurchaseReceiveRepository) Fetch(ctx context.Context, doc string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -198,10 +201,8 @@ func (t *TblDirectPurchaseReceiveRepository) Detail(ctx context.Context, docNo s
 }
 
 func (t *TblDirectPurchaseReceiveRepository) Create(ctx context.Context, data *tblrecvvdhdr.Create) (*tblrecvvdhdr.Create, error) {
-	countDetail := len(data.Detail)
-	var args []interface{}
-
 	// Mulai transaksi
+	var err error
 	tx, err := t.DB.BeginTxx(ctx, nil)
 	if err != nil {
 		log.Printf("Failed to start transaction: %+v", err)
@@ -217,6 +218,19 @@ func (t *TblDirectPurchaseReceiveRepository) Create(ctx context.Context, data *t
 		}
 	}()
 
+	data.DocNo, err = t.ID.GenerateIDForSite(ctx, tx, "DirectPurchaseReceive", data.Site.String, data.Date)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
+	for i := range data.Detail {
+		data.Detail[i].LocalCode = fmt.Sprintf("%s*%s*%s", data.Date[6:8], data.DocNo, data.Detail[i].DNo)
+	}
+
+	countDetail := len(data.Detail)
+	var args []interface{}
+
 	// Insert header
 	query := `
 	INSERT INTO tblrecvvdhdr (
@@ -307,100 +321,34 @@ func (t *TblDirectPurchaseReceiveRepository) Create(ctx context.Context, data *t
 			return nil, fmt.Errorf("failed to insert details: %w", err)
 		}
 
-		// Insert ke tabel stock movement
-		query = `INSERT INTO tblstockmovement (
-			DocType,
-			DocNo,
-			DNo,
-			CancelInd,
-			DocDt,
-			WhsCode,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			Remark,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var movementValues []string
-		args = args[:0]
-
-		for _, detail := range data.Detail {
-			movementValues = append(movementValues, `(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
-			args = append(args,
-				"Direct Purchase Receive",
-				data.DocNo,
-				detail.DNo,
-				detail.Cancel,
-				data.Date,
-				data.WarehouseCode,
-				detail.LocalCode,
-				detail.ItemCode,
-				detail.Batch,
-				detail.Quantity,
-				detail.Quantity,
-				detail.Quantity,
-				data.Remark,
-				data.CreateBy,
-				data.CreateDate,
-			)
-		}
-
-		query += strings.Join(movementValues, ",")
-		_, err = tx.ExecContext(ctx, query, args...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to insert stock movement: %w", err)
-		}
-
-		// Insert ke tabel stock summary
-		query = `INSERT INTO tblstocksummary (
-			WhsCode,
-			Lot,
-			Bin,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			Remark,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var summaryValues []string
-		args = args[:0]
-
+		var movements []inventoryledger.Movement
 		for _, detail := range data.Detail {
-			summaryValues = append(summaryValues, `(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
-			args = append(args,
-				data.WarehouseCode,
-				"-",
-				"-",
-				detail.ItemCode,
-				detail.ItemCode,
-				detail.Batch,
-				detail.Quantity,
-				0,
-				0,
-				data.Remark,
-				data.CreateBy,
-				data.CreateDate,
-			)
+			// harga pokok per unit setelah diskon dan pembulatan
+			amount := (detail.Price * detail.Quantity) - (detail.Price * detail.Quantity * (detail.Discount / 100)) + detail.Rounding
+			var unitCost float32
+			if detail.Quantity != 0 {
+				unitCost = amount / detail.Quantity
+			}
+			movements = append(movements, inventoryledger.Movement{
+				DocType:   "Direct Purchase Receive",
+				DocNo:     data.DocNo,
+				DNo:       detail.DNo,
+				DocDt:     data.Date,
+				WhsCode:   data.WarehouseCode,
+				Source:    detail.LocalCode,
+				ItCode:    detail.ItemCode,
+				BatchNo:   detail.Batch,
+				Qty:       detail.Quantity,
+				Direction: inventoryledger.In,
+				UnitCost:  unitCost,
+				Remark:    data.Remark,
+				CreateBy:  data.CreateBy,
+				CreateDt:  data.CreateDate,
+			})
 		}
 
-		query += strings.Join(summaryValues, ",") + `
-			ON DUPLICATE KEY UPDATE
-				Qty = Qty + VALUES(Qty),
-				Qty2 = Qty2,
-				Qty3 = Qty3,
-				LastUpBy = VALUES(CreateBy),
-				LastUpDt = VALUES(CreateDt);`
-		_, err = tx.ExecContext(ctx, query, args...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to insert stock summary: %w", err)
+		if err = t.Ledger.Post(ctx, tx, movements); err != nil {
+			return nil, err
 		}
 	}
 
@@ -417,10 +365,10 @@ func (t *TblDirectPurchaseReceiveRepository) Update(ctx context.Context, data, o
 	count := len(data.Detail)
 
 	if count > 0 {
-		var args, argsReason, argsLog, argsMov []interface{}
+		var args, argsReason, argsLog []interface{}
 		var whenClauses []string
 		var placeholders []string
-		var whenClausesMov []string
+		var cancelledDNo []string
 
 		status := false
 
@@ -432,8 +380,9 @@ func (t *TblDirectPurchaseReceiveRepository) Update(ctx context.Context, data, o
 				args = append(args, data.Detail[i].DNo, data.Detail[i].Cancel)
 				argsReason = append(argsReason, data.Detail[i].DNo, data.Detail[i].CancelReason)
 
-				whenClausesMov = append(whenClausesMov, `WHEN DocNo = ? AND DNo = ? THEN ?`)
-				argsMov = append(argsMov, data.DocNo, data.Detail[i].DNo, data.Detail[i].Cancel)
+				if data.Detail[i].Cancel.ToBool() {
+					cancelledDNo = append(cancelledDNo, data.Detail[i].DNo)
+				}
 
 				placeholders = append(placeholders, (`(?, ?, ?, ?)`))
 				argsLog = append(argsLog, lastUpBy, data.Detail[i].DNo, "DirectPurchaseReceiveDtl", lastUpDt)
@@ -460,16 +409,6 @@ func (t *TblDirectPurchaseReceiveRepository) Update(ctx context.Context, data, o
 			args = append(args, argsReason...)
 			args = append(args, lastUpDt, lastUpBy, data.DocNo)
 
-			queryMov := `UPDATE tblstockmovement
-					SET 
-						CancelInd = CASE ` + strings.Join(whenClausesMov, " ") + `
-						ELSE CancelInd
-						END,
-						LastUpDt = ?,
-						LastUpBy = ?
-					WHERE DocNo = ?;`
-			argsMov = append(argsMov, lastUpDt, lastUpBy, data.DocNo)
-
 			qLog += `(?, ?, ?, ?)`
 			qLog += ", " + strings.Join(placeholders, ", ")
 			argsLog = append(argsLog, lastUpBy, data.DocNo, "StockInitial", lastUpDt)
@@ -495,9 +434,40 @@ func (t *TblDirectPurchaseReceiveRepository) Update(ctx context.Context, data, o
 				return nil, fmt.Errorf("error executing update query: %w", err)
 			}
 
-			_, err = tx.ExecContext(ctx, queryMov, argsMov...)
-			if err != nil {
-				return nil, fmt.Errorf("error executing update query for tblstockmovement: %w", err)
+			if len(cancelledDNo) > 0 {
+				var movements []inventoryledger.Movement
+				var queryStock string
+				var argsStock []interface{}
+
+				queryStock, argsStock, err = sqlx.In(`SELECT
+						h.WhsCode,
+						d.DNo,
+						d.Source,
+						d.ItCode,
+						d.BatchNo,
+						d.QtyPurchase AS Qty
+					FROM tblrecvvddtl d
+					JOIN tblrecvvdhdr h ON d.DocNo = h.DocNo
+					WHERE d.DocNo = ? AND d.DNo IN (?)`, data.DocNo, cancelledDNo)
+				if err != nil {
+					return nil, fmt.Errorf("error building cancelled detail query: %w", err)
+				}
+
+				if err = tx.SelectContext(ctx, &movements, tx.Rebind(queryStock), argsStock...); err != nil {
+					return nil, fmt.Errorf("error fetching cancelled detail: %w", err)
+				}
+
+				for i := range movements {
+					movements[i].DocType = "Direct Purchase Receive"
+					movements[i].DocNo = data.DocNo
+					movements[i].Direction = inventoryledger.In
+					movements[i].CreateBy = lastUpBy
+					movements[i].CreateDt = lastUpDt
+				}
+
+				if err = t.Ledger.Reverse(ctx, tx, movements); err != nil {
+					return nil, err
+				}
 			}
 
 			// Eksekusi log activity
@@ -0,0 +1,409 @@
+package sqlx
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/tblsalesorder"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+// TblSalesOrderRepository sales order customer. Qty terkirim dihitung dari
+// baris direct sales delivery yang merujuk baris sales order dan belum dibatalkan.
+//
+//	CREATE TABLE tblsalesorderhdr (
+//		DocNo VARCHAR(30) NOT NULL PRIMARY KEY,
+//		DocDt VARCHAR(8) NOT NULL,
+//		CustCode VARCHAR(16) NOT NULL,
+//		TaxCode VARCHAR(16) NULL,
+//		Remark VARCHAR(255) NULL,
+//		CreateBy VARCHAR(16) NOT NULL,
+//		CreateDt VARCHAR(12) NOT NULL,
+//		LastUpBy VARCHAR(16) NULL,
+//		LastUpDt VARCHAR(12) NULL
+//	);
+//	CREATE TABLE tblsalesorderdtl (
+//		DocNo VARCHAR(30) NOT NULL,
+//		DNo VARCHAR(3) NOT NULL,
+//		CancelInd CHAR(1) NOT NULL DEFAULT 'N',
+//		ItCode VARCHAR(40) NOT NULL,
+//		Qty DECIMAL(18,4) NOT NULL,
+//		Price DECIMAL(18,4) NOT NULL,
+//		Remark VARCHAR(255) NULL,
+//		CreateBy VARCHAR(16) NOT NULL,
+//		CreateDt VARCHAR(12) NOT NULL,
+//		PRIMARY KEY (DocNo, DNo)
+//	);
+type TblSalesOrderRepository struct {
+	DB *repository.Sqlx            `inject:"database"`
+	ID *formatid.GenerateIDHandler `inject:"generateID"`
+}
+
+// salesOrderDeliveredQty qty terkirim baris sales order alias d
+const salesOrderDeliveredQty = `COALESCE((
+		SELECT SUM(s.Qty)
+		FROM tbldirectsalesdelivdtl s
+		WHERE s.SalesOrderDocNo = d.DocNo
+		AND s.SalesOrderDNo = d.DNo
+		AND s.CancelInd = 'N'
+	), 0)`
+
+func (t *TblSalesOrderRepository) Create(ctx context.Context, data *tblsalesorder.Create) (*tblsalesorder.Create, error) {
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	data.DocNo, err = t.ID.GenerateID(ctx, tx, "SalesOrder", data.Date)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
+	query := `INSERT INTO tblsalesorderhdr
+		(
+			DocNo,
+			DocDt,
+			CustCode,
+			TaxCode,
+			Remark,
+			CreateBy,
+			CreateDt
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	if _, err = tx.ExecContext(ctx, query,
+		data.DocNo,
+		data.Date,
+		data.CustomerCode,
+		data.TaxCode,
+		data.Remark,
+		data.CreateBy,
+		data.CreateDt,
+	); err != nil {
+		log.Printf("Error insert header: %+v", err)
+		return nil, fmt.Errorf("error Insert Header: %w", err)
+	}
+
+	var placeholders []string
+	var args []interface{}
+	for i := range data.Details {
+		detail := &data.Details[i]
+		detail.DocNo = data.DocNo
+		detail.DNo = fmt.Sprintf("%03d", i+1)
+
+		placeholders = append(placeholders, "(?, ?, 'N', ?, ?, ?, ?, ?, ?)")
+		args = append(args,
+			data.DocNo,
+			detail.DNo,
+			detail.ItCode,
+			detail.Qty,
+			detail.Price,
+			detail.Remark,
+			data.CreateBy,
+			data.CreateDt,
+		)
+	}
+
+	query = `INSERT INTO tblsalesorderdtl
+		(
+			DocNo,
+			DNo,
+			CancelInd,
+			ItCode,
+			Qty,
+			Price,
+			Remark,
+			CreateBy,
+			CreateDt
+		) VALUES ` + strings.Join(placeholders, ", ")
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		log.Printf("Error insert detail: %+v", err)
+		return nil, fmt.Errorf("error Insert Detail: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return data, nil
+}
+
+func (t *TblSalesOrderRepository) Fetch(ctx context.Context, doc, customer, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	var totalRecords int
+
+	where := "h.DocNo LIKE ? AND (h.CustCode LIKE ? OR c.CustName LIKE ?)"
+	args := []interface{}{"%" + doc + "%", "%" + customer + "%", "%" + customer + "%"}
+	if startDate != "" && endDate != "" {
+		where += " AND h.DocDt BETWEEN ? AND ?"
+		args = append(args, startDate, endDate)
+	}
+
+	countQuery := "SELECT COUNT(*) FROM tblsalesorderhdr h JOIN tblcustomerhdr c ON h.CustCode = c.CustCode WHERE " + where
+	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+
+	var totalPages, offset int
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+	}
+
+	data := make([]*tblsalesorder.Read, 0)
+	query := `SELECT
+			h.DocNo,
+			h.DocDt,
+			h.CustCode,
+			c.CustName,
+			h.TaxCode,
+			h.Remark
+		FROM tblsalesorderhdr h
+		JOIN tblcustomerhdr c ON h.CustCode = c.CustCode
+		WHERE ` + where + `
+		ORDER BY h.DocDt DESC, h.DocNo DESC
+		LIMIT ? OFFSET ?`
+	if err := t.DB.SelectContext(ctx, &data, query, append(args, param.PageSize, offset)...); err != nil {
+		return nil, fmt.Errorf("error Fetch sales order: %w", err)
+	}
+
+	response := &pagination.PaginationResponse{
+		Data:         data,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}
+	if len(data) == 0 {
+		return response, nil
+	}
+
+	docsNo := make([]string, len(data))
+	j := offset
+	for i, h := range data {
+		j++
+		h.Number = uint(j)
+		h.TblDate = share.FormatDate(h.Date)
+		h.Date = share.ToDatePicker(h.Date)
+		docsNo[i] = h.DocNo
+	}
+
+	detailQuery, detailArgs, err := sqlx.In(`SELECT
+			d.DocNo,
+			d.DNo,
+			d.CancelInd,
+			d.ItCode,
+			i.ItName,
+			u.UomName,
+			d.Qty,
+			d.Price,
+			`+salesOrderDeliveredQty+` AS DeliveredQty,
+			d.Remark
+		FROM tblsalesorderdtl d
+		JOIN tblitem i ON d.ItCode = i.ItCode
+		JOIN tbluom u ON i.SalesUomCode = u.UomCode
+		WHERE d.DocNo IN (?)
+		ORDER BY d.DocNo, d.DNo`, docsNo)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing detail query: %w", err)
+	}
+
+	var details []tblsalesorder.Detail
+	if err := t.DB.SelectContext(ctx, &details, t.DB.Rebind(detailQuery), detailArgs...); err != nil {
+		return nil, fmt.Errorf("error fetching details: %w", err)
+	}
+
+	detailMap := make(map[string][]tblsalesorder.Detail)
+	for _, d := range details {
+		detailMap[d.DocNo] = append(detailMap[d.DocNo], d)
+	}
+	for _, h := range data {
+		h.Details = detailMap[h.DocNo]
+		for _, d := range h.Details {
+			if !d.Cancel.ToBool() {
+				h.TotalAmount += d.Qty * d.Price
+			}
+		}
+	}
+
+	return response, nil
+}
+
+// Update hanya membatalkan baris, baris yang sudah (sebagian) terkirim
+// harus dibatalkan pengirimannya lebih dulu
+func (t *TblSalesOrderRepository) Update(ctx context.Context, lastUpby, lastUpDate string, data *tblsalesorder.Read) (*tblsalesorder.Read, error) {
+	if len(data.Details) == 0 {
+		return data, nil
+	}
+
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			log.Printf("Transaction rollback due to error: %+v", err)
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	audit, err := newAuditTrail(ctx, tx, "SalesOrder", data.DocNo)
+	if err != nil {
+		return nil, err
+	}
+
+	var delivered []struct {
+		DNo          string  `db:"DNo"`
+		DeliveredQty float32 `db:"DeliveredQty"`
+	}
+	if err = tx.SelectContext(ctx, &delivered, "SELECT d.DNo, "+salesOrderDeliveredQty+" AS DeliveredQty FROM tblsalesorderdtl d WHERE d.DocNo = ? FOR UPDATE", data.DocNo); err != nil {
+		return nil, fmt.Errorf("error get delivered quantity: %w", err)
+	}
+	deliveredQty := make(map[string]float32, len(delivered))
+	for _, d := range delivered {
+		deliveredQty[d.DNo] = d.DeliveredQty
+	}
+
+	var whens []string
+	var args []interface{}
+	for _, detail := range data.Details {
+		if detail.Cancel.ToBool() && deliveredQty[detail.DNo] > 0 {
+			err = fmt.Errorf("%w: sales order line %s already delivered", customerrors.ErrInvalidInput, detail.DNo)
+			return nil, err
+		}
+		whens = append(whens, "WHEN DNo = ? THEN ?")
+		args = append(args, detail.DNo, detail.Cancel)
+	}
+
+	query := `UPDATE tblsalesorderdtl
+		SET CancelInd = CASE
+			` + strings.Join(whens, " ") + `
+			ELSE CancelInd
+		END
+		WHERE DocNo = ?`
+	if _, err = tx.ExecContext(ctx, query, append(args, data.DocNo)...); err != nil {
+		log.Printf("Failed to update sales order dtl: %+v", err)
+		return nil, fmt.Errorf("error updating sales order dtl: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, "UPDATE tblsalesorderhdr SET LastUpBy = ?, LastUpDt = ? WHERE DocNo = ?", lastUpby, lastUpDate, data.DocNo); err != nil {
+		return nil, fmt.Errorf("error updating sales order hdr: %w", err)
+	}
+
+	if err = audit.Write(ctx, tx, lastUpby, lastUpDate); err != nil {
+		log.Printf("Detailed error: %+v", err)
+		return nil, fmt.Errorf("error inserting log activity: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return data, nil
+}
+
+func (t *TblSalesOrderRepository) Outstanding(ctx context.Context, search, customer, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	searchDoc := "%" + search + "%"
+	from := `FROM tblsalesorderdtl d
+		JOIN tblsalesorderhdr h ON d.DocNo = h.DocNo
+		JOIN tblcustomerhdr c ON h.CustCode = c.CustCode
+		JOIN tblitem i ON d.ItCode = i.ItCode
+		JOIN tbluom u ON i.SalesUomCode = u.UomCode
+		WHERE d.CancelInd = 'N'
+		AND (h.DocNo LIKE ? OR i.ItName LIKE ? OR c.CustName LIKE ?)
+		AND d.Qty - ` + salesOrderDeliveredQty + ` > 0`
+	args := []interface{}{searchDoc, searchDoc, searchDoc}
+
+	if customer != "" {
+		from += " AND h.CustCode = ?"
+		args = append(args, customer)
+	}
+	if startDate != "" && endDate != "" {
+		from += " AND h.DocDt BETWEEN ? AND ?"
+		args = append(args, startDate, endDate)
+	}
+
+	var totalRecords int
+	if err := t.DB.GetContext(ctx, &totalRecords, "SELECT COUNT(*) "+from, args...); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+
+	var totalPages, offset int
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+	}
+
+	data := make([]*tblsalesorder.Outstanding, 0)
+	query := `SELECT
+			d.DocNo,
+			d.DNo,
+			h.DocDt,
+			h.CustCode,
+			c.CustName,
+			d.ItCode,
+			i.ItName,
+			d.Qty AS OrderQty,
+			` + salesOrderDeliveredQty + ` AS DeliveredQty,
+			d.Qty - ` + salesOrderDeliveredQty + ` AS OutstandingQty,
+			u.UomName,
+			d.Price,
+			(d.Qty - ` + salesOrderDeliveredQty + `) * d.Price AS Total
+		` + from + `
+		ORDER BY h.DocDt, d.DocNo, d.DNo
+		LIMIT ? OFFSET ?`
+	if err := t.DB.SelectContext(ctx, &data, query, append(args, param.PageSize, offset)...); err != nil {
+		return nil, fmt.Errorf("error fetch outstanding sales order: %w", err)
+	}
+
+	j := offset
+	for _, d := range data {
+		j++
+		d.Number = uint(j)
+		d.Date = share.FormatDate(d.Date)
+	}
+
+	return &pagination.PaginationResponse{
+		Data:         data,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}, nil
+}
@@ -138,6 +138,14 @@ func (t *TblSiteRepository) Update(ctx context.Context, data *tblsite.Update) (*
 		return nil, fmt.Errorf("error starting transaction: %w", err)
 	}
 
+	audit, err := newAuditTrail(ctx, tx, "Site", data.SiteCode)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Failed to rollback transaction: %+v", rbErr)
+		}
+		return nil, err
+	}
+
 	result, err := tx.ExecContext(ctx, query,
 		data.SiteName,
 		data.Address,
@@ -169,9 +177,7 @@ func (t *TblSiteRepository) Update(ctx context.Context, data *tblsite.Update) (*
 		return nil, customerrors.ErrNoDataEdited
 	}
 
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
-	_, err = tx.ExecContext(ctx, query, data.LastUpdateBy, data.SiteCode, "Site", data.LastUpdateDate)
+	err = audit.Write(ctx, tx, data.LastUpdateBy, data.LastUpdateDate)
 
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
@@ -10,19 +10,22 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/tblstockadjustmentdtl"
 	"gitlab.com/ayaka/internal/domain/tblstockadjustmenthdr"
 
-	// "gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
 
-	// "gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
 
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
 
 type TblStockAdjustRepository struct {
-	DB *repository.Sqlx `inject:"database"`
+	DB     *repository.Sqlx            `inject:"database"`
+	Ledger inventoryledger.Repository  `inject:"inventoryLedgerRepository"`
+	ID     *formatid.GenerateIDHandler `inject:"generateID"`
 }
 
 func (t *TblStockAdjustRepository) Fetch(ctx context.Context, doc, warehouse, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -40,6 +43,11 @@ func (t *TblStockAdjustRepository) Fetch(ctx context.Context, doc, warehouse, st
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "WhsCode"); scope != "" {
+		countQuery += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
 		return nil, fmt.Errorf("error counting records: %w", err)
 	}
@@ -80,6 +88,11 @@ func (t *TblStockAdjustRepository) Fetch(ctx context.Context, doc, warehouse, st
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "i.WhsCode"); scope != "" {
+		query += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	query += " LIMIT ? OFFSET ?"
 	args = append(args, param.PageSize, offset)
 
@@ -196,6 +209,10 @@ func (t *TblStockAdjustRepository) Detail(ctx context.Context, docNo string) (*t
 		return nil, fmt.Errorf("error detail header stock adjustment: %w", err)
 	}
 
+	if err := checkWarehouse(ctx, header.WarehouseCode); err != nil {
+		return nil, customerrors.ErrDataNotFound
+	}
+
 	query = `SELECT
 		d.DNo,
 		i.ItName,
@@ -223,6 +240,69 @@ func (t *TblStockAdjustRepository) Detail(ctx context.Context, docNo string) (*t
 }
 
 func (t *TblStockAdjustRepository) Create(ctx context.Context, data *tblstockadjustmenthdr.Create) (*tblstockadjustmenthdr.Create, error) {
+	if err := checkWarehouse(ctx, data.WarehouseCode); err != nil {
+		return nil, err
+	}
+
+	// transaction begin
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	// Pastikan rollback dipanggil jika transaksi tidak berhasil
+	defer func() {
+		if err != nil {
+			// Rollback jika error terjadi
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	if err = t.create(ctx, tx, data); err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return data, nil
+}
+
+// CreateTx membuat stock adjustment di dalam transaksi pemanggil, dipakai
+// approval stock opname agar status opname dan adjustment-nya satu transaksi.
+func (t *TblStockAdjustRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, data *tblstockadjustmenthdr.Create) (*tblstockadjustmenthdr.Create, error) {
+	if err := checkWarehouse(ctx, data.WarehouseCode); err != nil {
+		return nil, err
+	}
+	if err := t.create(ctx, tx, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (t *TblStockAdjustRepository) create(ctx context.Context, tx *sqlx.Tx, data *tblstockadjustmenthdr.Create) error {
+	var err error
+	data.DocNo, err = t.ID.GenerateID(ctx, tx, "StockAdjustment", data.Date)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return fmt.Errorf("error Generate ID: %w", err)
+	}
+
+	for i := range data.Details {
+		// source yang sudah diisi (barang berkurang dari stock opname) dipakai apa adanya
+		if data.Details[i].Source == "" {
+			data.Details[i].Source = fmt.Sprintf("%s*%s*%s", data.Date[6:8], data.DocNo, data.Details[i].DNo)
+		}
+	}
+
 	query := `INSERT INTO tblstockadjustmenthdr 
 	(
 		DocNo,
@@ -246,29 +326,11 @@ func (t *TblStockAdjustRepository) Create(ctx context.Context, data *tblstockadj
 		data.CreateBy,
 	)
 
-	// transaction begin
-	var err error
-	tx, err := t.DB.BeginTxx(ctx, nil)
-	if err != nil {
-		log.Printf("Failed to start transaction: %+v", err)
-		return nil, fmt.Errorf("error starting transaction: %w", err)
-	}
-
-	// Pastikan rollback dipanggil jika transaksi tidak berhasil
-	defer func() {
-		if err != nil {
-			// Rollback jika error terjadi
-			if rbErr := tx.Rollback(); rbErr != nil {
-				log.Printf("Failed to rollback transaction: %+v", rbErr)
-			}
-		}
-	}()
-
 	// insert header
 	query += strings.Join(placeholders, "")
 	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
 		log.Printf("Error insert header: %+v", err)
-		return nil, fmt.Errorf("error Insert Header: %w", err)
+		return fmt.Errorf("error Insert Header: %w", err)
 	}
 
 	if len(data.Details) > 0 {
@@ -293,55 +355,7 @@ func (t *TblStockAdjustRepository) Create(ctx context.Context, data *tblstockadj
 		placeholders = placeholders[:0]
 		args = args[:0]
 
-		// stock summary query
-		queryStockSummary := `INSERT INTO tblstocksummary (
-			WhsCode,
-			Lot,
-			Bin,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersStockSummary []string
-		var argsStockSummary []interface{}
-
-		// stock movement query
-		queryStockMovement := `INSERT INTO tblstockmovement (
-			DocType,
-			DocNo,
-			DNo,
-			CancelInd,
-			DocDt,
-			WhsCode,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			Remark,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersStockMovement []string
-		var argsStockMovement []interface{}
-
-		// stock history of stock
-		queryHistory := `INSERT INTO tblhistoryofstock (
-			ItCode,
-			BatchNo,
-			Source,
-			CancelInd,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersHistory []string
-		var argsHistory []interface{}
+		var movements []inventoryledger.Movement
 
 		for i, detail := range data.Details {
 			balance := detail.StockActual - detail.StockSystem
@@ -354,7 +368,7 @@ func (t *TblStockAdjustRepository) Create(ctx context.Context, data *tblstockadj
 				detail.ItemCode,
 				detail.Batch,
 				"-",
-				"-",
+				binOrUnassigned(detail.Bin),
 				detail.StockSystem,
 				0,
 				0,
@@ -365,124 +379,40 @@ func (t *TblStockAdjustRepository) Create(ctx context.Context, data *tblstockadj
 				data.CreateBy,
 			)
 
-			// stock summary
-			placeholdersStockSummary = append(placeholdersStockSummary, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-
-			// stock movement
-			placeholdersStockMovement = append(placeholdersStockMovement, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-
-			if balance > 0 {
-				argsStockSummary = append(argsStockSummary,
-					data.WarehouseCode,
-					"-",
-					"-",
-					detail.Source,
-					detail.ItemCode,
-					detail.Batch,
-					0,
-					balance,
-					0,
-					data.CreateBy,
-					data.Date,
-				)
-				argsStockMovement = append(argsStockMovement,
-					"Stock Adjustment",
-					data.DocNo,
-					detail.DNo,
-					"N",
-					data.Date,
-					data.WarehouseCode,
-					detail.Source,
-					detail.ItemCode,
-					detail.Batch,
-					0,
-					balance,
-					0,
-					data.Remark,
-					data.CreateBy,
-					data.Date,
-				)
-			} else {
-				argsStockSummary = append(argsStockSummary,
-					data.WarehouseCode,
-					"-",
-					"-",
-					detail.Source,
-					detail.ItemCode,
-					detail.Batch,
-					0,
-					0,
-					(0 - balance),
-					data.CreateBy,
-					data.Date,
-				)
-				argsStockMovement = append(argsStockMovement,
-					"Stock Adjustment",
-					data.DocNo,
-					detail.DNo,
-					"N",
-					data.Date,
-					data.WarehouseCode,
-					detail.Source,
-					detail.ItemCode,
-					detail.Batch,
-					0,
-					0,
-					(0 - balance),
-					data.Remark,
-					data.CreateBy,
-					data.Date,
-				)
+			movement := inventoryledger.Movement{
+				DocType:   "Stock Adjustment",
+				DocNo:     data.DocNo,
+				DNo:       fmt.Sprintf("%03d", i+1),
+				DocDt:     data.Date,
+				WhsCode:   data.WarehouseCode,
+				Bin:       detail.Bin,
+				Source:    detail.Source,
+				ItCode:    detail.ItemCode,
+				BatchNo:   detail.Batch,
+				Qty:       balance,
+				Direction: inventoryledger.In,
+				Remark:    data.Remark,
+				CreateBy:  data.CreateBy,
+				CreateDt:  data.Date,
 			}
-
-			// history of stock
-			placeholdersHistory = append(placeholdersHistory, "(?, ?, ?, ?, ?, ?)")
-			argsHistory = append(argsHistory,
-				detail.ItemCode,
-				detail.Batch,
-				detail.Source,
-				"N",
-				data.CreateBy,
-				data.Date,
-			)
+			if balance <= 0 {
+				movement.Qty = 0 - balance
+				movement.Direction = inventoryledger.Out
+			}
+			movements = append(movements, movement)
 		}
 
 		// insert detail
 		query += strings.Join(placeholders, ",") + ";"
 		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
 			log.Printf("Error insert detail: %+v", err)
-			return nil, fmt.Errorf("error Insert Detail: %w", err)
-		}
-
-		// insert stock summary
-		queryStockSummary += strings.Join(placeholdersStockSummary, ",") + `;`
-		if _, err = tx.ExecContext(ctx, queryStockSummary, argsStockSummary...); err != nil {
-			log.Printf("Error insert stock summary: %+v", err)
-			return nil, fmt.Errorf("error Insert Stock Summary: %w", err)
+			return fmt.Errorf("error Insert Detail: %w", err)
 		}
 
-		// insert stock movement
-		queryStockMovement += strings.Join(placeholdersStockMovement, ",") + ";"
-		if _, err = tx.ExecContext(ctx, queryStockMovement, argsStockMovement...); err != nil {
-			log.Printf("Error insert stock movement: %+v", err)
-			return nil, fmt.Errorf("error Insert Stock Movement: %w", err)
-		}
-
-		// insert history
-		queryHistory += strings.Join(placeholdersHistory, ",") + ";"
-		if _, err = tx.ExecContext(ctx, queryHistory, argsHistory...); err != nil {
-			log.Printf("Error insert history of stock: %+v", err)
-			return nil, fmt.Errorf("error Insert History of Stock: %w", err)
-		}
+		return t.Ledger.Post(ctx, tx, movements)
 	}
 
-	// Commit the transaction
-	if err = tx.Commit(); err != nil {
-		log.Printf("Failed to commit transaction: %+v", err)
-		return nil, fmt.Errorf("error committing transaction: %w", err)
-	}
-
-	return data, nil
+	return nil
 }
 
 
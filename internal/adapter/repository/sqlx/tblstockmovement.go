@@ -78,6 +78,10 @@ func (t *TblStockMovementRepository) Fetch(
 			search = append(search, detailWhs)
 		}
 	}
+	if scope, scopeArgs := warehouseScope(ctx, "s.WhsCode"); scope != "" {
+		endquery = append(endquery, scope)
+		search = append(search, scopeArgs...)
+	}
 
 	if len(endquery) > 0 {
 		countQuery += " AND " + strings.Join(endquery, " AND ")
@@ -129,7 +133,7 @@ func (t *TblStockMovementRepository) Fetch(
 		FROM tblstockmovement s
 		JOIN tblwarehouse w ON s.WhsCode = w.WhsCode
 		JOIN tblitem i ON s.ItCode = i.ItCode
-		JOIN tbluom u ON i.PurchaseUOMCode = u.UomCode WHERE s.CancelInd = 'N'`
+		JOIN tbluom u ON i.InventoryUOMCode = u.UomCode WHERE s.CancelInd = 'N'`
 
 	if len(endquery) > 0 {
 		query += " AND " + strings.Join(endquery, " AND ")
@@ -10,13 +10,15 @@ import (
 
 	// "github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
 	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
 
 	// "gitlab.com/ayaka/internal/domain/tblstockamutationdtl"
 	"gitlab.com/ayaka/internal/domain/tblstockmutationdtl"
 	"gitlab.com/ayaka/internal/domain/tblstockmutationhdr"
 
-	// "gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
 
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 
@@ -24,7 +26,9 @@ import (
 )
 
 type TblStockMutationRepository struct {
-	DB *repository.Sqlx `inject:"database"`
+	DB     *repository.Sqlx            `inject:"database"`
+	Ledger inventoryledger.Repository  `inject:"inventoryLedgerRepository"`
+	ID     *formatid.GenerateIDHandler `inject:"generateID"`
 }
 
 func (t *TblStockMutationRepository) Fetch(ctx context.Context, doc, warehouse, batch string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -57,8 +61,17 @@ func (t *TblStockMutationRepository) Fetch(ctx context.Context, doc, warehouse,
 		args = append(args, batch)
 	}
 
+	var where []string
 	if len(endQuery) > 0 {
-		countQuery += " WHERE " + strings.Join(endQuery, " OR ")
+		where = append(where, "("+strings.Join(endQuery, " OR ")+")")
+	}
+	if scope, scopeArgs := warehouseScope(ctx, "h.WhsCode"); scope != "" {
+		where = append(where, scope)
+		args = append(args, scopeArgs...)
+	}
+
+	if len(where) > 0 {
+		countQuery += " WHERE " + strings.Join(where, " AND ")
 	}
 
 	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
@@ -100,8 +113,8 @@ func (t *TblStockMutationRepository) Fetch(ctx context.Context, doc, warehouse,
 		JOIN tblwarehouse w ON w.WhsCode = h.WhsCode
 		JOIN tbluom u ON i.PurchaseUOMCode = u.UomCode`
 
-	if len(endQuery) > 0 {
-		query += " WHERE " + strings.Join(endQuery, " OR ")
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
 	}
 	query += ` LIMIT ? OFFSET ? `
 	args = append(args, param.PageSize, offset)
@@ -166,6 +179,10 @@ func (t *TblStockMutationRepository) Detail(ctx context.Context, docNo string) (
 		return nil, fmt.Errorf("error Get header: %w", err)
 	}
 
+	if err := checkWarehouse(ctx, data.WarehouseCode); err != nil {
+		return nil, customerrors.ErrDataNotFound
+	}
+
 	var detailFrom []tblstockmutationdtl.Detail
 	var detailTo []tblstockmutationdtl.Detail
 	query = `
@@ -215,6 +232,39 @@ func (t *TblStockMutationRepository) Detail(ctx context.Context, docNo string) (
 }
 
 func (t *TblStockMutationRepository) Create(ctx context.Context, data *tblstockmutationhdr.Create) (*tblstockmutationhdr.Create, error) {
+	if err := checkWarehouse(ctx, data.WarehouseCode); err != nil {
+		return nil, err
+	}
+
+	// transaction begin
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	// Pastikan rollback dipanggil jika transaksi tidak berhasil
+	defer func() {
+		if err != nil {
+			// Rollback jika error terjadi
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	data.DocNo, err = t.ID.GenerateID(ctx, tx, "StockMutation", data.DocDate)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
+	// baris From memakai Source stok yang dipilih, hanya baris To yang jadi Source baru
+	for i := range data.ToArray {
+		data.ToArray[i].Source = fmt.Sprintf("%s*%s*%s", data.DocDate[6:8], data.DocNo, data.ToArray[i].DNo)
+	}
+
 	query := `
 		INSERT INTO tblstockmutationhdr (
 			DocNo,
@@ -242,24 +292,6 @@ func (t *TblStockMutationRepository) Create(ctx context.Context, data *tblstockm
 		data.CreateDate,
 	)
 
-	// transaction begin
-	var err error
-	tx, err := t.DB.BeginTxx(ctx, nil)
-	if err != nil {
-		log.Printf("Failed to start transaction: %+v", err)
-		return nil, fmt.Errorf("error starting transaction: %w", err)
-	}
-
-	// Pastikan rollback dipanggil jika transaksi tidak berhasil
-	defer func() {
-		if err != nil {
-			// Rollback jika error terjadi
-			if rbErr := tx.Rollback(); rbErr != nil {
-				log.Printf("Failed to rollback transaction: %+v", rbErr)
-			}
-		}
-	}()
-
 	// insert header
 	query += strings.Join(placeholders, "")
 	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
@@ -285,55 +317,7 @@ func (t *TblStockMutationRepository) Create(ctx context.Context, data *tblstockm
 		placeholders = placeholders[:0]
 		args = args[:0]
 
-		// stock summary query
-		queryStockSummary := `INSERT INTO tblstocksummary (
-			WhsCode,
-			Lot,
-			Bin,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersStockSummary []string
-		var argsStockSummary []interface{}
-
-		// stock movement query
-		queryStockMovement := `INSERT INTO tblstockmovement (
-			DocType,
-			DocNo,
-			DNo,
-			CancelInd,
-			DocDt,
-			WhsCode,
-			Source,
-			ItCode,
-			BatchNo,
-			Qty,
-			Qty2,
-			Qty3,
-			Remark,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersStockMovement []string
-		var argsStockMovement []interface{}
-
-		// stock history of stock
-		queryHistory := `INSERT INTO tblhistoryofstock (
-			ItCode,
-			BatchNo,
-			Source,
-			CancelInd,
-			CreateBy,
-			CreateDt
-		) VALUES `
-		var placeholdersHistory []string
-		var argsHistory []interface{}
+		var movements []inventoryledger.Movement
 
 		for _, detail := range data.FromArray {
 			placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?)")
@@ -349,52 +333,21 @@ func (t *TblStockMutationRepository) Create(ctx context.Context, data *tblstockm
 				data.CreateBy,
 			)
 
-			// stock summary
-			placeholdersStockSummary = append(placeholdersStockSummary, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-			argsStockSummary = append(argsStockSummary,
-				data.WarehouseCode,
-				"-",
-				"-",
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				0,
-				detail.Qty,
-				data.CreateBy,
-				data.DocDate,
-			)
-
-			// stock movement
-			placeholdersStockMovement = append(placeholdersStockMovement, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-			argsStockMovement = append(argsStockMovement,
-				"Stock Mutation (From)",
-				data.DocNo,
-				detail.DNo,
-				"N",
-				data.DocDate,
-				data.WarehouseCode,
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				0,
-				detail.Qty,
-				data.Remark,
-				data.CreateBy,
-				data.DocDate,
-			)
-
-			// history of stock
-			placeholdersHistory = append(placeholdersHistory, "(?, ?, ?, ?, ?, ?)")
-			argsHistory = append(argsHistory,
-				detail.ItCode,
-				detail.BatchNo,
-				detail.Source,
-				"N",
-				data.CreateBy,
-				data.DocDate,
-			)
+			movements = append(movements, inventoryledger.Movement{
+				DocType:   "Stock Mutation (From)",
+				DocNo:     data.DocNo,
+				DNo:       detail.DNo,
+				DocDt:     data.DocDate,
+				WhsCode:   data.WarehouseCode,
+				Source:    detail.Source,
+				ItCode:    detail.ItCode,
+				BatchNo:   detail.BatchNo,
+				Qty:       detail.Qty,
+				Direction: inventoryledger.Out,
+				Remark:    data.Remark,
+				CreateBy:  data.CreateBy,
+				CreateDt:  data.DocDate,
+			})
 		}
 
 		for _, detail := range data.ToArray {
@@ -411,52 +364,21 @@ func (t *TblStockMutationRepository) Create(ctx context.Context, data *tblstockm
 				data.CreateBy,
 			)
 
-			// stock summary
-			placeholdersStockSummary = append(placeholdersStockSummary, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-			argsStockSummary = append(argsStockSummary,
-				data.WarehouseCode,
-				"-",
-				"-",
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				detail.Qty,
-				0,
-				data.CreateBy,
-				data.DocDate,
-			)
-
-			// stock movement
-			placeholdersStockMovement = append(placeholdersStockMovement, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-			argsStockMovement = append(argsStockMovement,
-				"Stock Mutation (To)",
-				data.DocNo,
-				detail.DNo,
-				"N",
-				data.DocDate,
-				data.WarehouseCode,
-				detail.Source,
-				detail.ItCode,
-				detail.BatchNo,
-				0,
-				detail.Qty,
-				0,
-				data.Remark,
-				data.CreateBy,
-				data.DocDate,
-			)
-
-			// history of stock
-			placeholdersHistory = append(placeholdersHistory, "(?, ?, ?, ?, ?, ?)")
-			argsHistory = append(argsHistory,
-				detail.ItCode,
-				detail.BatchNo,
-				detail.Source,
-				"N",
-				data.CreateBy,
-				data.DocDate,
-			)
+			movements = append(movements, inventoryledger.Movement{
+				DocType:   "Stock Mutation (To)",
+				DocNo:     data.DocNo,
+				DNo:       detail.DNo,
+				DocDt:     data.DocDate,
+				WhsCode:   data.WarehouseCode,
+				Source:    detail.Source,
+				ItCode:    detail.ItCode,
+				BatchNo:   detail.BatchNo,
+				Qty:       detail.Qty,
+				Direction: inventoryledger.In,
+				Remark:    data.Remark,
+				CreateBy:  data.CreateBy,
+				CreateDt:  data.DocDate,
+			})
 		}
 
 		// insert detail
@@ -466,25 +388,8 @@ func (t *TblStockMutationRepository) Create(ctx context.Context, data *tblstockm
 			return nil, fmt.Errorf("error Insert Detail: %w", err)
 		}
 
-		// insert stock summary
-		queryStockSummary += strings.Join(placeholdersStockSummary, ",") + `;`
-		if _, err = tx.ExecContext(ctx, queryStockSummary, argsStockSummary...); err != nil {
-			log.Printf("Error insert stock summary: %+v", err)
-			return nil, fmt.Errorf("error Insert Stock Summary: %w", err)
-		}
-
-		// insert stock movement
-		queryStockMovement += strings.Join(placeholdersStockMovement, ",") + ";"
-		if _, err = tx.ExecContext(ctx, queryStockMovement, argsStockMovement...); err != nil {
-			log.Printf("Error insert stock movement: %+v", err)
-			return nil, fmt.Errorf("error Insert Stock Movement: %w", err)
-		}
-
-		// insert history
-		queryHistory += strings.Join(placeholdersHistory, ",") + ";"
-		if _, err = tx.ExecContext(ctx, queryHistory, argsHistory...); err != nil {
-			log.Printf("Error insert history of stock: %+v", err)
-			return nil, fmt.Errorf("error Insert History of Stock: %w", err)
+		if err = t.Ledger.Post(ctx, tx, movements); err != nil {
+			return nil, err
 		}
 	}
 
@@ -500,9 +405,7 @@ func (t *TblStockMutationRepository) Create(ctx context.Context, data *tblstockm
 func (t *TblStockMutationRepository) Update(ctx context.Context, lastUpby, lastUpDate string, data *tblstockmutationhdr.Detail) (*tblstockmutationhdr.Detail, error) {
 	var resultDetail sql.Result
 	var rowsAffectedDtl int64
-
-	var placeholdersStockSummary, placeholdersStockSummary2, placeholdersEdit, inTuples, inTuplesSum2, inTuplesSum []string
-	var args, argsStockSummary, argsStockSummary2, argsEdit, argsIn, argsInSum, argsInSum2 []interface{}
+	var args []interface{}
 
 	var err error
 
@@ -528,42 +431,27 @@ func (t *TblStockMutationRepository) Update(ctx context.Context, lastUpby, lastU
 		}
 	}()
 
+	audit, err := newAuditTrail(ctx, tx, "StockMutation", data.DocNo)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkDocWarehouse(ctx, tx, "tblstockmutationhdr", "WhsCode", data.DocNo); err != nil {
+		return nil, err
+	}
+
+	var prevCancel booldatatype.BoolDataType
+	if err = tx.GetContext(ctx, &prevCancel, "SELECT CancelInd FROM tblstockmutationhdr WHERE DocNo = ?", data.DocNo); err != nil {
+		log.Printf("Failed to fetch existing cancel status: %+v", err)
+		return nil, fmt.Errorf("error fetching existing cancel status: %w", err)
+	}
+
 	query := `UPDATE tblstockmutationhdr SET
 		CancelInd = ?,
 		CancelReason = ?
 	WHERE DocNo = ?`
 	args = append(args, data.Cancel, data.CancelReason, data.DocNo)
 
-	for _, detail := range data.FromArray {
-		// min qty
-		placeholdersStockSummary = append(placeholdersStockSummary, `WHEN WhsCode = ? AND Source = ? AND ItCode = ? THEN (Qty3 - ?) `)
-		argsStockSummary = append(argsStockSummary, data.WarehouseCode, detail.Source, detail.ItemCode, detail.Quantity)
-
-		// edit cancel status on history of stock and stock movement
-		placeholdersEdit = append(placeholdersEdit, ` WHEN  ItCode = ? AND Source = ? AND BatchNo = ? THEN ? `)
-		argsEdit = append(argsEdit, detail.ItemCode, detail.Source, detail.Batch, data.Cancel)
-		
-		inTuples = append(inTuples, "(?, ?, ?)")
-		inTuplesSum = append(inTuplesSum, "(?, ?, ?)")
-		argsIn = append(argsIn, detail.ItemCode, detail.Source, detail.Batch)
-		argsInSum = append(argsInSum, data.WarehouseCode, detail.Source, detail.ItemCode)
-	}
-
-	for _, detail := range data.ToArray {
-		// min qty
-		placeholdersStockSummary2 = append(placeholdersStockSummary2, `WHEN WhsCode = ? AND Source = ? AND ItCode = ? THEN (Qty2 - ?) `)
-		argsStockSummary2 = append(argsStockSummary2, data.WarehouseCode, detail.Source, detail.ItemCode, detail.Quantity)
-
-		// edit cancel status on history of stock and stock movement
-		placeholdersEdit = append(placeholdersEdit, ` WHEN  ItCode = ? AND Source = ? AND BatchNo = ? THEN ? `)
-		argsEdit = append(argsEdit, detail.ItemCode, detail.Source, detail.Batch, data.Cancel)
-		
-		inTuples = append(inTuples, "(?, ?, ?)")
-		argsIn = append(argsIn, detail.ItemCode, detail.Source, detail.Batch)
-		inTuplesSum2 = append(inTuplesSum2, "(?, ?, ?)")
-		argsInSum2 = append(argsInSum2, data.WarehouseCode, detail.Source, detail.ItemCode)
-	}
-
 	if resultDetail, err = tx.ExecContext(ctx, query, args...); err != nil {
 		log.Printf("Failed to update stock mutation dtl: %+v", err)
 		return nil, fmt.Errorf("error updating stock mutation dtl: %w", err)
@@ -579,79 +467,24 @@ func (t *TblStockMutationRepository) Update(ctx context.Context, lastUpby, lastU
 		return data, err
 	}
 
-	// Update stock summary FROM
-	query = `UPDATE tblstocksummary
-		SET Qty3 = CASE
-			` + strings.Join(placeholdersStockSummary, " ") + `
-			ELSE Qty3
-		END
-		WHERE (WhsCode, Source, ItCode) IN (` + strings.Join(inTuplesSum, ",") + `)
-	`
-	argsStockSummary = append(argsStockSummary, argsInSum...)
-
-	fmt.Println("Query update stock summary FROM:", query)
-	fmt.Println("Args:", argsStockSummary)	
-
-	if _, err := tx.ExecContext(ctx, query, argsStockSummary...); err != nil {
-		log.Printf("Failed to update stock summary: %+v", err)
-		return nil, fmt.Errorf("error updating stock summary: %w", err)
-	}
-
-	// Update stock summary TO
-	query = `UPDATE tblstocksummary
-		SET Qty2 = CASE
-			` + strings.Join(placeholdersStockSummary2, " ") + `
-			ELSE Qty2
-		END
-		WHERE (WhsCode, Source, ItCode) IN (` + strings.Join(inTuplesSum2, ",") + `)
-	`
-	argsStockSummary2 = append(argsStockSummary2, argsInSum2...)
-
-	fmt.Println("Query update stock summary FROM:", query)
-	fmt.Println("Args:", argsStockSummary2)
-
-	if _, err := tx.ExecContext(ctx, query, argsStockSummary2...); err != nil {
-		log.Printf("Failed to update stock summary: %+v", err)
-		return nil, fmt.Errorf("error updating stock summary: %w", err)
-	}
-
-	// Update history of stock
-	query = `UPDATE tblhistoryofstock
-		SET CancelInd = CASE
-			` + strings.Join(placeholdersEdit, " ") + `
-			ELSE CancelInd
-		END
-		WHERE (ItCode, Source, BatchNo) IN (` + strings.Join(inTuples, ",") + `)
-	`
+	// reverse stock hanya jika dokumen baru saja di-cancel
+	if !prevCancel.ToBool() && data.Cancel.ToBool() {
+		var movements, to []inventoryledger.Movement
+		if movements, err = documentLines(ctx, tx, "Stock Mutation (From)", data.DocNo, lastUpby, lastUpDate); err != nil {
+			return nil, err
+		}
+		if to, err = documentLines(ctx, tx, "Stock Mutation (To)", data.DocNo, lastUpby, lastUpDate); err != nil {
+			return nil, err
+		}
 
-	fmt.Println("Query history of stock: ", query)
-	argsEdit = append(argsEdit, argsIn...)
-	fmt.Println("args history of stock: ", argsEdit)
-	if _, err := tx.ExecContext(ctx, query, argsEdit...); err != nil {
-		log.Printf("Failed to update history of stock: %+v", err)
-		return nil, fmt.Errorf("error updating history of stock: %w", err)
-	}
-
-	// Update stock movement
-	query = `UPDATE tblstockmovement
-		SET CancelInd = CASE
-			` + strings.Join(placeholdersEdit, " ") + `
-			ELSE CancelInd
-		END
-		WHERE (ItCode, Source, BatchNo) IN (` + strings.Join(inTuples, ",") + `)
-	`
-	fmt.Println("Query stock sum: ", query)
-	fmt.Println("args stock sum: ", argsEdit)
-	if _, err := tx.ExecContext(ctx, query, argsEdit...); err != nil {
-		log.Printf("Failed to update stock movement: %+v", err)
-		return nil, fmt.Errorf("error updating stock movement: %w", err)
+		if err = t.Ledger.Reverse(ctx, tx, append(movements, to...)); err != nil {
+			return nil, err
+		}
 	}
 
 	// Update log activity
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
 	fmt.Println("--Update Log--")
-	if _, err = tx.ExecContext(ctx, query, lastUpby, data.DocNo, "StockMutation", lastUpDate); err != nil {
+	if err = audit.Write(ctx, tx, lastUpby, lastUpDate); err != nil {
 		log.Printf("Detailed error: %+v", err)
 		return nil, fmt.Errorf("error inserting log activity: %w", err)
 	}
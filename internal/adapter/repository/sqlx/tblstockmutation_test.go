@@ -0,0 +1,69 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/tblstockmutationdtl"
+	"gitlab.com/ayaka/internal/domain/tblstockmutationhdr"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+const (
+	queryStockMutationHeader = "INSERT INTO tblstockmutationhdr ( DocNo, DocDt, WhsCode, BatchNo, Source, Remark, CreateBy, CreateDt ) VALUES (?, ?, ?, ?, ?, ?, ?, ?);"
+	queryStockMutationDetail = "INSERT INTO tblstockmutationdtl ( DocNo, DNo, ItCode, BatchNo, Source, Qty, FromTo, CreateDt, CreateBy ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?),(?, ?, ?, ?, ?, ?, ?, ?, ?);"
+)
+
+type TblStockMutationRepositorySuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	repo    *TblStockMutationRepository
+	db      *sqlx.DB
+}
+
+func (suite *TblStockMutationRepositorySuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.repo = &TblStockMutationRepository{
+		DB:     &repository.Sqlx{DB: suite.db},
+		Ledger: &InventoryLedgerRepository{},
+		ID:     &formatid.GenerateIDHandler{},
+	}
+}
+
+func (suite *TblStockMutationRepositorySuite) TearDownTest() {
+	suite.db.Close()
+}
+
+// baris From mengambil stok dari Source yang dipilih, baris To dapat Source baru
+func (suite *TblStockMutationRepositorySuite) TestCreate_FromLineKeepsPickedSource() {
+	suite.mockSQL.ExpectBegin()
+	expectDocNo(suite.mockSQL, "StockMutation")
+	suite.mockSQL.ExpectExec(queryStockMutationHeader).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectExec(queryStockMutationDetail).WillReturnResult(sqlmock.NewResult(0, 2))
+	expectPickedSourceLock(suite.mockSQL, "WH1", "IT001", "B1", "15*0001/R1/PMR/01/26*001")
+
+	data := &tblstockmutationhdr.Create{
+		DocDate:       "20260120",
+		WarehouseCode: "WH1",
+		FromArray:     []tblstockmutationdtl.Create{{DNo: "001", ItCode: "IT001", BatchNo: "B1", Source: "15*0001/R1/PMR/01/26*001", Qty: 5}},
+		ToArray:       []tblstockmutationdtl.Create{{DNo: "002", ItCode: "IT002", BatchNo: "B1", Qty: 5}},
+	}
+	_, err := suite.repo.Create(context.Background(), data)
+
+	suite.ErrorIs(err, customerrors.ErrInsufficientStock)
+	suite.Equal("15*0001/R1/PMR/01/26*001", data.FromArray[0].Source)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func TestTblStockMutationRepository(t *testing.T) {
+	suite.Run(t, new(TblStockMutationRepositorySuite))
+}
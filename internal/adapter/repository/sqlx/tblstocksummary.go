@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"database/sql"
 	"errors"
@@ -12,9 +13,9 @@ import (
 	// "strings"
 
 	"gitlab.com/ayaka/internal/adapter/repository"
+	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/tblstocksummary"
 
-	// share "gitlab.com/ayaka/internal/domain/shared"
 	// "gitlab.com/ayaka/internal/domain/shared/nulldatatype"
 	// "gitlab.com/ayaka/internal/pkg/customerrors"
 	// "gitlab.com/ayaka/internal/pkg/datagroup"
@@ -60,6 +61,10 @@ func (t *TblStockSummaryRepository) Fetch(ctx context.Context, warehouse []strin
 			search = append(search, detailWhs)
 		}
 	}
+	if scope, scopeArgs := warehouseScope(ctx, "s.WhsCode"); scope != "" {
+		endquery = append(endquery, scope)
+		search = append(search, scopeArgs...)
+	}
 
 	if len(endquery) > 0 {
 		countQuery += " WHERE " + strings.Join(endquery, " AND ")
@@ -108,7 +113,7 @@ func (t *TblStockSummaryRepository) Fetch(ctx context.Context, warehouse []strin
 		JOIN tblwarehouse w ON s.WhsCode = w.WhsCode
 		JOIN tblitem i ON s.ItCode = i.ItCode
 		JOIN tblitemcategory c ON c.ItCtCode = i.ItCtCode
-		JOIN tbluom u ON i.PurchaseUOMCode = u.UomCode`
+		JOIN tbluom u ON i.InventoryUOMCode = u.UomCode`
 
 	if len(endquery) > 0 {
 		query += " WHERE " + strings.Join(endquery, " AND ")
@@ -185,6 +190,10 @@ func (t *TblStockSummaryRepository) GetItem(ctx context.Context, itemName, itemC
 		batch = "%" + batch + "%"
 		search = append(search, batch)
 	}
+	if scope, scopeArgs := warehouseScope(ctx, "s.WhsCode"); scope != "" {
+		endquery = append(endquery, scope)
+		search = append(search, scopeArgs...)
+	}
 
 	if len(endquery) > 0 {
 		countQuery += " AND " + strings.Join(endquery, " AND ")
@@ -222,11 +231,13 @@ func (t *TblStockSummaryRepository) GetItem(ctx context.Context, itemName, itemC
 			s.ItCode,
 			i.ItName,
 			s.BatchNo,
+			b.ExpDt,
 			SUM(Qty + Qty2 - Qty3) AS Stock,
 			u.UomName
 		FROM tblstocksummary s
 		JOIN tblitem i ON s.ItCode = i.ItCode
-		JOIN tbluom u ON i.PurchaseUOMCode = u.UomCode
+		JOIN tbluom u ON i.InventoryUOMCode = u.UomCode
+		LEFT JOIN tblbatch b ON s.ItCode = b.ItCode AND s.BatchNo = b.BatchNo
 		WHERE s.WhsCode = ? `
 
 	if len(endquery) > 0 {
@@ -234,8 +245,10 @@ func (t *TblStockSummaryRepository) GetItem(ctx context.Context, itemName, itemC
 	}
 	query += " AND i.ActInd = 'Y'"
 
+	// FEFO: batch yang paling cepat kedaluwarsa di atas, batch tanpa master di akhir
 	query += `
-			GROUP BY s.ItCode, s.BatchNo
+			GROUP BY s.ItCode, s.BatchNo, b.ExpDt
+			ORDER BY s.ItCode, b.ExpDt IS NULL, b.ExpDt, s.BatchNo
 			LIMIT ? OFFSET ?`
 	search = append(search, param.PageSize, offset)
 
@@ -256,11 +269,16 @@ func (t *TblStockSummaryRepository) GetItem(ctx context.Context, itemName, itemC
 	}
 
 	j := offset
+	today := time.Now().Format("20060102")
 	var filtered []*tblstocksummary.GetItem
 	for _, detail := range data {
 		if detail.Stock != 0 {
 			j++
 			detail.Number = uint(j)
+			if detail.ExpiredDate.Valid {
+				detail.Expired = detail.ExpiredDate.String < today
+				detail.ExpiredDate.String = share.FormatDate(detail.ExpiredDate.String)
+			}
 			filtered = append(filtered, detail)
 		}
 	}
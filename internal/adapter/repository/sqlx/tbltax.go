@@ -63,7 +63,10 @@ func (t *TblTaxRepository) Fetch(ctx context.Context, name, category string, par
 				t.TaxGroupCode,
 				t.TaxRate,
 				tg.TaxGroupName,
-				t.CreateDt
+				t.CreateDt,
+				t.WithholdInd,
+				t.RoundingMode,
+				t.RoundingDecimal
 				FROM tbltax t
 				JOIN tbltaxgroup tg ON t.TaxGroupCode = tg.TaxGroupCode
 				WHERE (t.TaxCode LIKE ? OR t.TaxName LIKE ?)
@@ -75,7 +78,10 @@ func (t *TblTaxRepository) Fetch(ctx context.Context, name, category string, par
 				t.TaxGroupCode,
 				t.TaxRate,
 				tg.TaxGroupName,
-				t.CreateDt
+				t.CreateDt,
+				t.WithholdInd,
+				t.RoundingMode,
+				t.RoundingDecimal
 				FROM tbltax t
 				JOIN tbltaxgroup tg ON t.TaxGroupCode = tg.TaxGroupCode
 				WHERE (t.TaxCode LIKE ? OR t.TaxName LIKE ?)
@@ -144,17 +150,23 @@ func (t *TblTaxRepository) Create(ctx context.Context, data *tbltax.Create) (*tb
 					TaxName,
 					TaxRate,
 					TaxGroupCode,
+					WithholdInd,
+					RoundingMode,
+					RoundingDecimal,
 					CreateDt,
 					CreateBy
 				)
 				VALUES
-				(?, ?, ?, ?, ?, ?)`
+				(?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err := t.DB.ExecContext(ctx, query,
 		data.TaxCode,
 		data.TaxName,
 		data.TaxRate,
 		data.TaxGroupCode,
+		data.Withholding,
+		data.RoundingMode,
+		data.RoundingDecimal,
 		data.CreateDate,
 		data.CreateBy,
 	)
@@ -170,7 +182,10 @@ func (t *TblTaxRepository) Create(ctx context.Context, data *tbltax.Create) (*tb
 func (t *TblTaxRepository) Update(ctx context.Context, data *tbltax.Update) (*tbltax.Update, error) {
 	query := `SELECT TaxName,
 			TaxRate,
-			TaxGroupCode
+			TaxGroupCode,
+			WithholdInd,
+			RoundingMode,
+			RoundingDecimal
 			FROM tbltax
 			WHERE TaxCode = ? LIMIT 1`
 
@@ -186,7 +201,10 @@ func (t *TblTaxRepository) Update(ctx context.Context, data *tbltax.Update) (*tb
 	fmt.Printf("\ntax grup: %s - %s", data.TaxGroupCode, temp.TaxGroupCode)
 	if data.TaxName == temp.TaxName &&
 		fmt.Sprintf("%.6f", data.TaxRate) == fmt.Sprintf("%.6f", temp.TaxRate) &&
-		data.TaxGroupCode == temp.TaxGroupCode {
+		data.TaxGroupCode == temp.TaxGroupCode &&
+		data.Withholding.ToBool() == temp.Withholding.ToBool() &&
+		data.RoundingMode == temp.RoundingMode &&
+		data.RoundingDecimal == temp.RoundingDecimal {
 		return nil, customerrors.ErrNoDataEdited
 	}
 
@@ -194,6 +212,9 @@ func (t *TblTaxRepository) Update(ctx context.Context, data *tbltax.Update) (*tb
 			TaxName = ?,
 			TaxRate = ?,
 			TaxGroupCode = ?,
+			WithholdInd = ?,
+			RoundingMode = ?,
+			RoundingDecimal = ?,
 			LastUpBy = ?,
 			LastUpDt = ?
 			WHERE TaxCode = ?`
@@ -204,10 +225,21 @@ func (t *TblTaxRepository) Update(ctx context.Context, data *tbltax.Update) (*tb
 		return nil, fmt.Errorf("error starting transaction: %w", err)
 	}
 
+	audit, err := newAuditTrail(ctx, tx, "Tax", data.TaxCode)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Failed to rollback transaction: %+v", rbErr)
+		}
+		return nil, err
+	}
+
 	_, err = tx.ExecContext(ctx, query,
 		data.TaxName,
 		data.TaxRate,
 		data.TaxGroupCode,
+		data.Withholding,
+		data.RoundingMode,
+		data.RoundingDecimal,
 		data.LastUpdateBy,
 		data.LastUpdateDate,
 		data.TaxCode,
@@ -225,9 +257,7 @@ func (t *TblTaxRepository) Update(ctx context.Context, data *tbltax.Update) (*tb
 		return nil, fmt.Errorf("error updating tax: %w", err)
 	}
 
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
-	_, err = tx.ExecContext(ctx, query, data.LastUpdateBy, data.TaxCode, "Tax", data.LastUpdateDate)
+	err = audit.Write(ctx, tx, data.LastUpdateBy, data.LastUpdateDate)
 
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
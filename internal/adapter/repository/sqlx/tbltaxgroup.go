@@ -134,6 +134,14 @@ func (t *TblTaxGroupRepository) Update(ctx context.Context, data *tbltaxgroup.Up
 		return nil, fmt.Errorf("error starting transaction: %w", err)
 	}
 
+	audit, err := newAuditTrail(ctx, tx, "TaxGroup", data.TaxGroupCode)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Failed to rollback transaction: %+v", rbErr)
+		}
+		return nil, err
+	}
+
 	result, err := tx.ExecContext(ctx, query,
 		data.TaxGroupName,
 		data.LastUpdateDate,
@@ -161,9 +169,7 @@ func (t *TblTaxGroupRepository) Update(ctx context.Context, data *tbltaxgroup.Up
 		return nil, customerrors.ErrNoDataEdited
 	}
 
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
-	_, err = tx.ExecContext(ctx, query, data.LastUpdateBy, data.TaxGroupCode, "TaxGroup", data.LastUpdateDate)
+	err = audit.Write(ctx, tx, data.LastUpdateBy, data.LastUpdateDate)
 
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
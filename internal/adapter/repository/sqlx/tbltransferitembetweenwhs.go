@@ -53,6 +53,11 @@ func (t *TblTransferItemBetweenWhsRepository) GetMaterial(ctx context.Context, i
 		search = append(search, batch)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "h.WhsCodeFrom", "h.WhsCodeTo"); scope != "" {
+		endquery = append(endquery, scope)
+		search = append(search, scopeArgs...)
+	}
+
 	if len(endquery) > 0 {
 		countQuery += " AND " + strings.Join(endquery, " AND ")
 	}
@@ -198,6 +203,11 @@ func (t *TblTransferItemBetweenWhsRepository) Fetch(ctx context.Context, item, w
 		args = append(args, startDate, endDate)
 	}
 
+	if scope, scopeArgs := warehouseScope(ctx, "t.WhsFrom", "t.WhsTo"); scope != "" {
+		endQuery += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+
 	countQuery += endQuery
 	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
 		return nil, fmt.Errorf("error counting records: %w", err)
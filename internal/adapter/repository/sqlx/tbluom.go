@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/tbluom"
@@ -91,16 +92,24 @@ func (t *TblUomRepository) FetchUom(ctx context.Context, name string, param *pag
 }
 
 func (t *TblUomRepository) Create(ctx context.Context, data *tbluom.CreateTblUom) (*tbluom.CreateTblUom, error) {
+	if err := insertUom(ctx, t.DB, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func insertUom(ctx context.Context, db sqlx.ExecerContext, data *tbluom.CreateTblUom) error {
 	query := "INSERT INTO tbluom (UomCode, UomName, CreateBy, CreateDt) VALUES (?, ?, ?, ?)"
 
-	_, err := t.DB.ExecContext(ctx, query, data.UomCode, data.UomName, data.CreateBy, data.CreateDate)
+	_, err := db.ExecContext(ctx, query, data.UomCode, data.UomName, data.CreateBy, data.CreateDate)
 
 	if err != nil {
 		log.Printf("Detailed error: %+v", err)
-		return nil, fmt.Errorf("error Create Country: %w", err)
+		return fmt.Errorf("error Create Uom: %w", err)
 	}
 
-	return data, nil
+	return nil
 }
 
 func (t *TblUomRepository) Update(ctx context.Context, data *tbluom.UpdateTblUom) (*tbluom.UpdateTblUom, error) {
@@ -124,6 +133,14 @@ func (t *TblUomRepository) Update(ctx context.Context, data *tbluom.UpdateTblUom
 		return nil, fmt.Errorf("error starting transaction: %w", err)
 	}
 
+	audit, err := newAuditTrail(ctx, tx, "Uom", data.UomCode)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Failed to rollback transaction: %+v", rbErr)
+		}
+		return nil, err
+	}
+
 	_, err = tx.ExecContext(ctx, query, data.UomName, data.UserCode, data.LastUpdateDate, data.UomCode)
 
 	if err != nil {
@@ -134,9 +151,7 @@ func (t *TblUomRepository) Update(ctx context.Context, data *tbluom.UpdateTblUom
 		return nil, fmt.Errorf("error updating uom: %w", err)
 	}
 
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
-	_, err = tx.ExecContext(ctx, query, data.UserCode, data.UomCode, "Uom", data.LastUpdateDate)
+	err = audit.Write(ctx, tx, data.UserCode, data.LastUpdateDate)
 
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
@@ -0,0 +1,279 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/domain/tblusergroup"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+// TblUserGroupRepository menyimpan group user dan matrix permission-nya.
+// User tanpa group tidak punya permission apa pun, jadi user lama perlu
+// di-seed ke group ADMIN dengan permission *:* (semua menu, semua action):
+//
+//	CREATE TABLE tblgroup (
+//		GrpCode  VARCHAR(20) NOT NULL PRIMARY KEY,
+//		GrpName  VARCHAR(80) NOT NULL,
+//		CreateBy VARCHAR(50), CreateDt VARCHAR(12),
+//		LastUpBy VARCHAR(50), LastUpDt VARCHAR(12)
+//	);
+//	CREATE TABLE tblgroupmenu (
+//		GrpCode  VARCHAR(20) NOT NULL,
+//		Menu     VARCHAR(50) NOT NULL,
+//		Action   VARCHAR(10) NOT NULL,
+//		CreateBy VARCHAR(50), CreateDt VARCHAR(12),
+//		PRIMARY KEY (GrpCode, Menu, Action)
+//	);
+//	ALTER TABLE tbluser ADD COLUMN GrpCode VARCHAR(20) NULL;
+//	INSERT INTO tblgroup (GrpCode, GrpName) VALUES ('ADMIN', 'Administrator');
+//	INSERT INTO tblgroupmenu (GrpCode, Menu, Action) VALUES ('ADMIN', '*', '*');
+//	UPDATE tbluser SET GrpCode = 'ADMIN' WHERE GrpCode IS NULL;
+type TblUserGroupRepository struct {
+	DB *repository.Sqlx `inject:"database"`
+}
+
+func (t *TblUserGroupRepository) Fetch(ctx context.Context, name string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	var totalRecords int
+
+	search := "%" + name + "%"
+
+	countQuery := "SELECT COUNT(*) FROM tblgroup WHERE GrpCode LIKE ? OR GrpName LIKE ?"
+	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, search, search); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+
+	var totalPages int
+	var offset int
+
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+		offset = 0
+	}
+
+	var groups []*tblusergroup.Read
+	query := `SELECT GrpCode,
+				GrpName,
+				CreateDt
+				FROM tblgroup
+				WHERE GrpCode LIKE ? OR GrpName LIKE ?
+				ORDER BY GrpCode
+				LIMIT ? OFFSET ?`
+
+	if err := t.DB.SelectContext(ctx, &groups, query, search, search, param.PageSize, offset); err != nil {
+		return nil, fmt.Errorf("error Fetch User Group: %w", err)
+	}
+
+	j := offset
+	result := make([]*tblusergroup.Read, len(groups))
+	for i, item := range groups {
+		j++
+		result[i] = &tblusergroup.Read{
+			Number:     uint(j),
+			GroupCode:  item.GroupCode,
+			GroupName:  item.GroupName,
+			CreateDate: share.FormatDate(item.CreateDate),
+		}
+	}
+
+	response := &pagination.PaginationResponse{
+		Data:         result,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}
+
+	return response, nil
+}
+
+func (t *TblUserGroupRepository) Detail(ctx context.Context, code string) (*tblusergroup.Detail, error) {
+	var detail tblusergroup.Detail
+
+	query := "SELECT GrpCode, GrpName, CreateDt FROM tblgroup WHERE GrpCode = ?"
+	if err := t.DB.GetContext(ctx, &detail, query, code); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrDataNotFound
+		}
+		return nil, fmt.Errorf("error Detail User Group: %w", err)
+	}
+	detail.CreateDate = share.FormatDate(detail.CreateDate)
+
+	detail.Permissions = make([]tblusergroup.Permission, 0)
+	query = "SELECT Menu, Action FROM tblgroupmenu WHERE GrpCode = ? ORDER BY Menu, Action"
+	if err := t.DB.SelectContext(ctx, &detail.Permissions, query, code); err != nil {
+		return nil, fmt.Errorf("error fetching group permission: %w", err)
+	}
+
+	detail.Users = make([]tblusergroup.User, 0)
+	query = "SELECT UserCode, UserName FROM tbluser WHERE GrpCode = ? ORDER BY UserCode"
+	if err := t.DB.SelectContext(ctx, &detail.Users, query, code); err != nil {
+		return nil, fmt.Errorf("error fetching group user: %w", err)
+	}
+
+	return &detail, nil
+}
+
+func (t *TblUserGroupRepository) Create(ctx context.Context, data *tblusergroup.Create) (*tblusergroup.Create, error) {
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	query := "INSERT INTO tblgroup (GrpCode, GrpName, CreateBy, CreateDt) VALUES (?, ?, ?, ?)"
+	if _, err = tx.ExecContext(ctx, query, data.GroupCode, data.GroupName, data.CreateBy, data.CreateDate); err != nil {
+		log.Printf("Error insert group: %+v", err)
+		return nil, fmt.Errorf("error Create User Group: %w", err)
+	}
+
+	if err = insertGroupMenu(ctx, tx, data.GroupCode, data.Permissions, data.CreateBy, data.CreateDate); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return data, nil
+}
+
+// Update mengganti seluruh permission group dengan data yang dikirim.
+func (t *TblUserGroupRepository) Update(ctx context.Context, data *tblusergroup.Update) (*tblusergroup.Update, error) {
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	audit, err := newAuditTrail(ctx, tx, "UserGroup", data.GroupCode)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "UPDATE tblgroup SET GrpName = ?, LastUpBy = ?, LastUpDt = ? WHERE GrpCode = ?"
+	if _, err = tx.ExecContext(ctx, query, data.GroupName, data.LastUpdateBy, data.LastUpdateDate, data.GroupCode); err != nil {
+		log.Printf("Failed to update group: %+v", err)
+		return nil, fmt.Errorf("error updating user group: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM tblgroupmenu WHERE GrpCode = ?", data.GroupCode); err != nil {
+		log.Printf("Failed to delete group menu: %+v", err)
+		return nil, fmt.Errorf("error deleting group permission: %w", err)
+	}
+
+	if err = insertGroupMenu(ctx, tx, data.GroupCode, data.Permissions, data.LastUpdateBy, data.LastUpdateDate); err != nil {
+		return nil, err
+	}
+
+	if err = audit.Write(ctx, tx, data.LastUpdateBy, data.LastUpdateDate); err != nil {
+		log.Printf("Failed to insert log activity: %+v", err)
+		return nil, fmt.Errorf("error insert to log activity: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return data, nil
+}
+
+func (t *TblUserGroupRepository) AssignUser(ctx context.Context, data *tblusergroup.AssignUser) (*tblusergroup.AssignUser, error) {
+	query, args, err := sqlx.In("UPDATE tbluser SET GrpCode = ? WHERE UserCode IN (?)", data.GroupCode, data.UserCodes)
+	if err != nil {
+		return nil, fmt.Errorf("error building assign user query: %w", err)
+	}
+
+	result, err := t.DB.ExecContext(ctx, t.DB.Rebind(query), args...)
+	if err != nil {
+		log.Printf("Failed to assign user: %+v", err)
+		return nil, fmt.Errorf("error assigning user to group: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("error check row affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return data, customerrors.ErrNoDataEdited
+	}
+
+	return data, nil
+}
+
+func (t *TblUserGroupRepository) PermissionsOf(ctx context.Context, userCode string) ([]string, error) {
+	var permissions []tblusergroup.Permission
+
+	query := `SELECT m.Menu, m.Action
+		FROM tbluser u
+		JOIN tblgroupmenu m ON m.GrpCode = u.GrpCode
+		WHERE u.UserCode = ?`
+	if err := t.DB.SelectContext(ctx, &permissions, query, userCode); err != nil {
+		return nil, fmt.Errorf("error fetching user permission: %w", err)
+	}
+
+	result := make([]string, len(permissions))
+	for i, permission := range permissions {
+		result[i] = permission.String()
+	}
+
+	return result, nil
+}
+
+func insertGroupMenu(ctx context.Context, tx *sqlx.Tx, groupCode string, permissions []tblusergroup.Permission, user, date string) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	var placeholders []string
+	var args []interface{}
+	for _, permission := range permissions {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?)")
+		args = append(args, groupCode, permission.Menu, permission.Action, user, date)
+	}
+
+	query := "INSERT INTO tblgroupmenu (GrpCode, Menu, Action, CreateBy, CreateDt) VALUES " + strings.Join(placeholders, ",")
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		log.Printf("Error insert group menu: %+v", err)
+		return fmt.Errorf("error Insert Group Permission: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/tbluserscope"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+// TblUserScopeRepository menyimpan gudang dan site yang boleh diakses user.
+// WhsCode / SiteCode "*" berarti semua gudang / site. User tanpa baris sama
+// sekali tidak bisa melihat dokumen apa pun, jadi user lama perlu di-seed:
+//
+//	CREATE TABLE tbluserwarehouse (
+//		UserCode VARCHAR(50) NOT NULL,
+//		WhsCode  VARCHAR(20) NOT NULL,
+//		CreateBy VARCHAR(50), CreateDt VARCHAR(12),
+//		PRIMARY KEY (UserCode, WhsCode)
+//	);
+//	CREATE TABLE tblusersite (
+//		UserCode VARCHAR(50) NOT NULL,
+//		SiteCode VARCHAR(20) NOT NULL,
+//		CreateBy VARCHAR(50), CreateDt VARCHAR(12),
+//		PRIMARY KEY (UserCode, SiteCode)
+//	);
+//	INSERT INTO tbluserwarehouse (UserCode, WhsCode) SELECT UserCode, '*' FROM tbluser;
+//	INSERT INTO tblusersite (UserCode, SiteCode) SELECT UserCode, '*' FROM tbluser;
+type TblUserScopeRepository struct {
+	DB *repository.Sqlx `inject:"database"`
+}
+
+func (t *TblUserScopeRepository) Detail(ctx context.Context, userCode string) (*tbluserscope.Detail, error) {
+	detail := tbluserscope.Detail{UserCode: userCode}
+
+	if err := t.DB.GetContext(ctx, &detail.UserName, "SELECT UserName FROM tbluser WHERE UserCode = ?", userCode); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrDataNotFound
+		}
+		return nil, fmt.Errorf("error Detail User Scope: %w", err)
+	}
+
+	detail.Warehouses = make([]string, 0)
+	query := "SELECT WhsCode FROM tbluserwarehouse WHERE UserCode = ? ORDER BY WhsCode"
+	if err := t.DB.SelectContext(ctx, &detail.Warehouses, query, userCode); err != nil {
+		return nil, fmt.Errorf("error fetching user warehouse: %w", err)
+	}
+
+	detail.Sites = make([]string, 0)
+	query = "SELECT SiteCode FROM tblusersite WHERE UserCode = ? ORDER BY SiteCode"
+	if err := t.DB.SelectContext(ctx, &detail.Sites, query, userCode); err != nil {
+		return nil, fmt.Errorf("error fetching user site: %w", err)
+	}
+
+	return &detail, nil
+}
+
+// Update mengganti seluruh gudang dan site user dengan data yang dikirim.
+func (t *TblUserScopeRepository) Update(ctx context.Context, data *tbluserscope.Update) (*tbluserscope.Update, error) {
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	audit, err := newAuditTrail(ctx, tx, "UserScope", data.UserCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = replaceUserScope(ctx, tx, "tbluserwarehouse", "WhsCode", data.UserCode, data.Warehouses, data.LastUpdateBy, data.LastUpdateDate); err != nil {
+		return nil, err
+	}
+
+	if err = replaceUserScope(ctx, tx, "tblusersite", "SiteCode", data.UserCode, data.Sites, data.LastUpdateBy, data.LastUpdateDate); err != nil {
+		return nil, err
+	}
+
+	if err = audit.Write(ctx, tx, data.LastUpdateBy, data.LastUpdateDate); err != nil {
+		log.Printf("Failed to insert log activity: %+v", err)
+		return nil, fmt.Errorf("error insert to log activity: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return data, nil
+}
+
+func replaceUserScope(ctx context.Context, tx *sqlx.Tx, table, column, userCode string, codes []string, user, date string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE UserCode = ?", userCode); err != nil {
+		log.Printf("Failed to delete %s: %+v", table, err)
+		return fmt.Errorf("error deleting %s: %w", table, err)
+	}
+
+	if len(codes) == 0 {
+		return nil
+	}
+
+	var placeholders []string
+	var args []interface{}
+	for _, code := range codes {
+		placeholders = append(placeholders, "(?, ?, ?, ?)")
+		args = append(args, userCode, code, user, date)
+	}
+
+	query := "INSERT INTO " + table + " (UserCode, " + column + ", CreateBy, CreateDt) VALUES " + strings.Join(placeholders, ",")
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		log.Printf("Error insert %s: %+v", table, err)
+		return fmt.Errorf("error Insert %s: %w", table, err)
+	}
+
+	return nil
+}
@@ -8,19 +8,62 @@ import (
 	"log"
 	"strings"
 
+	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/domain/tblmastervendor"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
 
 type TblVendorRepository struct {
-	DB *repository.Sqlx `inject:"database"`
+	DB *repository.Sqlx            `inject:"database"`
+	ID *formatid.GenerateIDHandler `inject:"generateID"`
 }
 
 func (t *TblVendorRepository) Create(ctx context.Context, data *tblmastervendor.Create) (*tblmastervendor.Create, error) {
+	// start transaction
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	// Pastikan rollback dipanggil jika transaksi tidak berhasil
+	defer func() {
+		if err != nil {
+			// Rollback jika error terjadi
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	if err = insertVendor(ctx, tx, t.ID, data); err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return data, nil
+}
+
+// insertVendor insert header vendor beserta contact, item category, sector dan rating di dalam tx
+func insertVendor(ctx context.Context, tx *sqlx.Tx, id *formatid.GenerateIDHandler, data *tblmastervendor.Create) error {
+	var err error
+	data.VendorCode, err = id.GenerateID(ctx, tx, "MasterVendor", data.CreateDate)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return fmt.Errorf("error Generate ID: %w", err)
+	}
+
 	query := `INSERT INTO tblvendorhdr 
 		(
 			VendorCode, 
@@ -35,6 +78,8 @@ func (t *TblVendorRepository) Create(ctx context.Context, data *tblmastervendor.
 			Mobile, 
 			Email, 
 			Remark,
+			TaxGroupCode,
+			NPWP,
 			CreateDt,
 			CreateBy
 		) VALUES 
@@ -42,7 +87,7 @@ func (t *TblVendorRepository) Create(ctx context.Context, data *tblmastervendor.
 	var args []interface{}
 	var placeholders []string
 
-	placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);")
+	placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);")
 	args = append(args,
 		data.VendorCode,
 		data.VendorName,
@@ -56,32 +101,16 @@ func (t *TblVendorRepository) Create(ctx context.Context, data *tblmastervendor.
 		data.Mobile,
 		data.Email,
 		data.Remark,
+		data.TaxGroupCode,
+		data.NPWP,
 		data.CreateDate,
 		data.CreateBy)
 
-	// start transaction
-	var err error
-	tx, err := t.DB.BeginTxx(ctx, nil)
-	if err != nil {
-		log.Printf("Failed to start transaction: %+v", err)
-		return nil, fmt.Errorf("error starting transaction: %w", err)
-	}
-
-	// Pastikan rollback dipanggil jika transaksi tidak berhasil
-	defer func() {
-		if err != nil {
-			// Rollback jika error terjadi
-			if rbErr := tx.Rollback(); rbErr != nil {
-				log.Printf("Failed to rollback transaction: %+v", rbErr)
-			}
-		}
-	}()
-
 	// insert header
 	query += strings.Join(placeholders, " ")
 	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
 		log.Printf("Detailed error: %+v", err)
-		return nil, fmt.Errorf("error Create Vendor Header: %w", err)
+		return fmt.Errorf("error Create Vendor Header: %w", err)
 	}
 
 	if len(data.ContactVendor) > 0 {
@@ -119,7 +148,7 @@ func (t *TblVendorRepository) Create(ctx context.Context, data *tblmastervendor.
 		fmt.Println("query contact: ", query)
 		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
 			log.Printf("Detailed error: %+v", err)
-			return nil, fmt.Errorf("error Create Contact Vendor: %w", err)
+			return fmt.Errorf("error Create Contact Vendor: %w", err)
 		}
 	}
 
@@ -149,7 +178,7 @@ func (t *TblVendorRepository) Create(ctx context.Context, data *tblmastervendor.
 		query += strings.Join(placeholders, ", ") + ";"
 		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
 			log.Printf("Detailed error: %+v", err)
-			return nil, fmt.Errorf("error Create Item Category Vendor: %w", err)
+			return fmt.Errorf("error Create Item Category Vendor: %w", err)
 		}
 	}
 
@@ -181,7 +210,7 @@ func (t *TblVendorRepository) Create(ctx context.Context, data *tblmastervendor.
 		query += strings.Join(placeholders, ", ") + ";"
 		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
 			log.Printf("Detailed error: %+v", err)
-			return nil, fmt.Errorf("error Create Sector Vendor: %w", err)
+			return fmt.Errorf("error Create Sector Vendor: %w", err)
 		}
 	}
 
@@ -212,17 +241,11 @@ func (t *TblVendorRepository) Create(ctx context.Context, data *tblmastervendor.
 		query += strings.Join(placeholders, ", ") + ";"
 		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
 			log.Printf("Detailed error: %+v", err)
-			return nil, fmt.Errorf("error Create Rating Vendor: %w", err)
+			return fmt.Errorf("error Create Rating Vendor: %w", err)
 		}
 	}
 
-	// Commit the transaction
-	if err = tx.Commit(); err != nil {
-		log.Printf("Failed to commit transaction: %+v", err)
-		return nil, fmt.Errorf("error committing transaction: %w", err)
-	}
-
-	return data, nil
+	return nil
 }
 
 func (t *TblVendorRepository) Fetch(ctx context.Context, name, cat string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -330,7 +353,9 @@ func (t *TblVendorRepository) Detail(ctx context.Context, vendorCode string) (*t
 			Phone,
 			Mobile,
 			Email,
-			Remark
+			Remark,
+			TaxGroupCode,
+			NPWP
 		FROM tblvendorhdr
 		WHERE VendorCode = ?;`
 
@@ -411,7 +436,9 @@ func (t *TblVendorRepository) Update(ctx context.Context, data *tblmastervendor.
 			Phone = ?, 
 			Mobile = ?, 
 			Email = ?, 
-			Remark = ?
+			Remark = ?,
+			TaxGroupCode = ?,
+			NPWP = ?
 		WHERE VendorCode = ?`
 
 	var args []interface{}
@@ -428,6 +455,8 @@ func (t *TblVendorRepository) Update(ctx context.Context, data *tblmastervendor.
 		data.Mobile,
 		data.Email,
 		data.Remark,
+		data.TaxGroupCode,
+		data.NPWP,
 		data.VendorCode,
 	)
 
@@ -458,6 +487,11 @@ func (t *TblVendorRepository) Update(ctx context.Context, data *tblmastervendor.
 		}
 	}()
 
+	audit, err := newAuditTrail(ctx, tx, "MasterVendor", data.VendorCode)
+	if err != nil {
+		return nil, err
+	}
+
 	// Header
 	fmt.Println("--Update Header--")
 	resultHdr, err = tx.ExecContext(ctx, query, args...)
@@ -810,10 +844,8 @@ func (t *TblVendorRepository) Update(ctx context.Context, data *tblmastervendor.
 	}
 
 	// Insert to log activity
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
 	fmt.Println("--Update Log--")
-	if _, err = tx.ExecContext(ctx, query, data.LastUpdateBy, data.VendorCode, "Vendor", data.LastUpdateDate); err != nil {
+	if err = audit.Write(ctx, tx, data.LastUpdateBy, data.LastUpdateDate); err != nil {
 		log.Printf("Detailed error: %+v", err)
 		return nil, fmt.Errorf("error inserting log activity: %w", err)
 	}
@@ -128,6 +128,14 @@ func (t *TblVendorCategoryRepository) Update(ctx context.Context, data *tblvendo
 		return nil, fmt.Errorf("error starting transaction: %w", err)
 	}
 
+	audit, err := newAuditTrail(ctx, tx, "VendorCategory", data.VendorCategoryCode)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Failed to rollback transaction: %+v", rbErr)
+		}
+		return nil, err
+	}
+
 	result, err := tx.ExecContext(ctx, query,
 		data.VendorCategoryName,
 		data.VendorCategoryCode,
@@ -156,9 +164,7 @@ func (t *TblVendorCategoryRepository) Update(ctx context.Context, data *tblvendo
 		return nil, customerrors.ErrNoDataEdited
 	}
 
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
-	_, err = tx.ExecContext(ctx, query, data.LastUpdateBy, data.VendorCategoryCode, "VendorCategory", data.LastUpdateDate)
+	err = audit.Write(ctx, tx, data.LastUpdateBy, data.LastUpdateDate)
 
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
@@ -14,7 +14,7 @@ import (
 	"gitlab.com/ayaka/internal/domain/tblmasteritem"
 	"gitlab.com/ayaka/internal/domain/tblvendorquotation"
 
-	// "gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
 
 	// "gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
@@ -22,7 +22,8 @@ import (
 )
 
 type TblVendorQuotationRepository struct {
-	DB *repository.Sqlx `inject:"database"`
+	DB *repository.Sqlx            `inject:"database"`
+	ID *formatid.GenerateIDHandler `inject:"generateID"`
 }
 
 func (t *TblVendorQuotationRepository) Fetch(ctx context.Context, doc, vendor, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -172,6 +173,30 @@ func (t *TblVendorQuotationRepository) Fetch(ctx context.Context, doc, vendor, s
 }
 
 func (t *TblVendorQuotationRepository) Create(ctx context.Context, data *tblvendorquotation.Create) (*tblvendorquotation.Create, error) {
+	// transaction begin
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	// Pastikan rollback dipanggil jika transaksi tidak berhasil
+	defer func() {
+		if err != nil {
+			// Rollback jika error terjadi
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	data.DocNo, err = t.ID.GenerateID(ctx, tx, "VendorQuotation", data.Date)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
 	query := `INSERT INTO tblvendorquotationhdr 
 	(
 		DocNo,
@@ -203,24 +228,6 @@ func (t *TblVendorQuotationRepository) Create(ctx context.Context, data *tblvend
 		data.CreateBy,
 	)
 
-	// transaction begin
-	var err error
-	tx, err := t.DB.BeginTxx(ctx, nil)
-	if err != nil {
-		log.Printf("Failed to start transaction: %+v", err)
-		return nil, fmt.Errorf("error starting transaction: %w", err)
-	}
-
-	// Pastikan rollback dipanggil jika transaksi tidak berhasil
-	defer func() {
-		if err != nil {
-			// Rollback jika error terjadi
-			if rbErr := tx.Rollback(); rbErr != nil {
-				log.Printf("Failed to rollback transaction: %+v", rbErr)
-			}
-		}
-	}()
-
 	// insert header
 	query += strings.Join(placeholders, "")
 	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
@@ -309,6 +316,11 @@ func (t *TblVendorQuotationRepository) Update(ctx context.Context, lastUpby, las
 		}
 	}()
 
+	audit, err := newAuditTrail(ctx, tx, "VendorQuotation", data.DocNo)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, detail := range data.Details {
 		// detail cancel
 		placeholders = append(placeholders, ` WHEN DocNo = ? AND DNo = ? THEN ? `)
@@ -340,10 +352,8 @@ func (t *TblVendorQuotationRepository) Update(ctx context.Context, lastUpby, las
 	}
 
 	// Update log activity
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
 	fmt.Println("--Update Log--")
-	if _, err = tx.ExecContext(ctx, query, lastUpby, data.DocNo, "VendorQuotation", lastUpDate); err != nil {
+	if err = audit.Write(ctx, tx, lastUpby, lastUpDate); err != nil {
 		log.Printf("Detailed error: %+v", err)
 		return nil, fmt.Errorf("error inserting log activity: %w", err)
 	}
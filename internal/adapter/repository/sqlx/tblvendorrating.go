@@ -15,6 +15,10 @@ import (
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
 
+// TblVendorRatingRepository indikator penilaian vendor. Weight dipakai saat
+// menghitung skor vendor di perbandingan quotation.
+//
+//	ALTER TABLE tblvendorrating ADD COLUMN Weight DECIMAL(9,2) NOT NULL DEFAULT 1;
 type TblVendorRatingRepository struct {
 	DB *repository.Sqlx `inject:"database"`
 }
@@ -56,6 +60,7 @@ func (t *TblVendorRatingRepository) Fetch(ctx context.Context, name, active stri
 	var items []*tblvendorrating.Read
 	query := `SELECT IndicatorCode,
 				Description,
+				Weight,
 				ActiveInd,
 				CreateDt
 				FROM tblvendorrating
@@ -110,16 +115,18 @@ func (t *TblVendorRatingRepository) Create(ctx context.Context, data *tblvendorr
 				(
 					IndicatorCode,
 					Description,
+					Weight,
 					ActiveInd,
 					CreateDt,
 					CreateBy
 				)
 				VALUES
-				(?, ?, ?, ?, ?)`
+				(?, ?, ?, ?, ?, ?)`
 
 	_, err := t.DB.ExecContext(ctx, query,
 		data.IndicatorCode,
 		data.Description,
+		data.Weight,
 		data.Active,
 		data.CreateDate,
 		data.CreateBy,
@@ -136,6 +143,7 @@ func (t *TblVendorRatingRepository) Create(ctx context.Context, data *tblvendorr
 func (t *TblVendorRatingRepository) Update(ctx context.Context, data *tblvendorrating.Update) (*tblvendorrating.Update, error) {
 	query := `UPDATE tblvendorrating SET
 			Description = ?,
+			Weight = IF(? > 0, ?, Weight),
 			ActiveInd = ?
 			WHERE IndicatorCode = ?`
 
@@ -145,8 +153,18 @@ func (t *TblVendorRatingRepository) Update(ctx context.Context, data *tblvendorr
 		return nil, fmt.Errorf("error starting transaction: %w", err)
 	}
 
+	audit, err := newAuditTrail(ctx, tx, "VendorRating", data.IndicatorCode)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Failed to rollback transaction: %+v", rbErr)
+		}
+		return nil, err
+	}
+
 	result, err := tx.ExecContext(ctx, query,
 		data.Description,
+		data.Weight,
+		data.Weight,
 		data.Active,
 		data.IndicatorCode,
 	)
@@ -174,9 +192,7 @@ func (t *TblVendorRatingRepository) Update(ctx context.Context, data *tblvendorr
 		return nil, customerrors.ErrNoDataEdited
 	}
 
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
-	_, err = tx.ExecContext(ctx, query, data.LastUpdateBy, data.IndicatorCode, "VendorRating", data.LastUpdateDate)
+	err = audit.Write(ctx, tx, data.LastUpdateBy, data.LastUpdateDate)
 
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
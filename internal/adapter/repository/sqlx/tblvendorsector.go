@@ -285,6 +285,11 @@ func (t *TblVendorSectorRepository) Update(ctx context.Context, data *tblvendors
 		}
 	}()
 
+	audit, err := newAuditTrail(ctx, tx, "VendorSector", data.SectorCode)
+	if err != nil {
+		return nil, err
+	}
+
 	var resultHdr, resultDtl sql.Result
 	var rowsAffectedHdr, rowsAffectedDtl int64
 	// HEADER
@@ -411,9 +416,7 @@ func (t *TblVendorSectorRepository) Update(ctx context.Context, data *tblvendors
 		return nil, customerrors.ErrNoDataEdited
 	}
 
-	query = "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-
-	_, err = tx.ExecContext(ctx, query, data.LastUpdateBy, data.SectorCode, "VendorSector", data.LastUpdateDate)
+	err = audit.Write(ctx, tx, data.LastUpdateBy, data.LastUpdateDate)
 
 	if err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
@@ -431,7 +434,6 @@ func (t *TblVendorSectorRepository) Update(ctx context.Context, data *tblvendors
 	return data, nil
 }
 
-
 func (t *TblVendorSectorRepository) GetSector(ctx context.Context) ([]tblvendorsector.GetSector, error) {
 	query := `SELECT SectorCode, SectorName FROM tblvendorsectorhdr WHERE Active = 'Y';`
 
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/adapter/repository"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/tblwarehouse"
@@ -138,18 +139,25 @@ func (t *TblWarehouseRepository) Create(ctx context.Context, data *tblwarehouse.
 
 	log.Printf("[INFO] Creating new warehouse: %+v", data)
 
-	query := "INSERT INTO tblwarehouse (WhsCode, WhsName, WhsCtCode, Address, CityCode, PostalCd, Phone, Fax, Email, Mobile, ContactPerson, Remark, CreateBy, CreateDt) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
-	log.Printf("[QUERY] %s", query)
-
-	if _, err := t.DB.ExecContext(ctx, query, data.WhsCode, data.WhsName, data.WhsCtCode, data.Address, data.CityCode, data.PostalCd, data.Phone, data.Fax, data.Email, data.Mobile, data.ContactPerson, data.Remark, data.CreateBy, data.CreateDate); err != nil {
+	if err := insertWarehouse(ctx, t.DB, data); err != nil {
 		log.Printf("[ERROR] Failed to create warehouse: %v", err)
-		return nil, fmt.Errorf("error creating warehouse: %w", err)
+		return nil, err
 	}
 
 	log.Printf("[INFO] Successfully created warehouse: %+v", data)
 	return data, nil
 }
 
+func insertWarehouse(ctx context.Context, db sqlx.ExecerContext, data *tblwarehouse.CreateTblWarehouse) error {
+	query := "INSERT INTO tblwarehouse (WhsCode, WhsName, WhsCtCode, Address, CityCode, PostalCd, Phone, Fax, Email, Mobile, ContactPerson, Remark, CreateBy, CreateDt) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	log.Printf("[QUERY] %s", query)
+
+	if _, err := db.ExecContext(ctx, query, data.WhsCode, data.WhsName, data.WhsCtCode, data.Address, data.CityCode, data.PostalCd, data.Phone, data.Fax, data.Email, data.Mobile, data.ContactPerson, data.Remark, data.CreateBy, data.CreateDate); err != nil {
+		return fmt.Errorf("error creating warehouse: %w", err)
+	}
+	return nil
+}
+
 // Update to modify an existing warehouse
 func (t *TblWarehouseRepository) Update(ctx context.Context, data *tblwarehouse.UpdateTblWarehouse) (*tblwarehouse.UpdateTblWarehouse, error) {
 	// Validasi input data
@@ -185,6 +193,11 @@ func (t *TblWarehouseRepository) Update(ctx context.Context, data *tblwarehouse.
 		}
 	}()
 
+	audit, err := newAuditTrail(ctx, tx, "Warehouse", data.WhsCode)
+	if err != nil {
+		return nil, err
+	}
+
 	if _, err = tx.ExecContext(ctx, query, data.WhsName, data.WhsCtCode, data.Address, data.CityCode, data.PostalCd, data.Phone, data.Fax, data.Email, data.Mobile, data.ContactPerson, data.Remark, data.LastUpBy, data.LastUpdateDate, data.WhsCode); err != nil {
 		_ = tx.Rollback()
 		log.Printf("[ERROR] Failed to update warehouse: %v", err)
@@ -192,10 +205,7 @@ func (t *TblWarehouseRepository) Update(ctx context.Context, data *tblwarehouse.
 	}
 
 	// Log activity
-	logActivityQuery := "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-	log.Printf("[QUERY] %s", logActivityQuery)
-
-	if _, err = tx.ExecContext(ctx, logActivityQuery, data.LastUpBy, data.WhsCode, "Warehouse", data.LastUpdateDate); err != nil {
+	if err = audit.Write(ctx, tx, data.LastUpBy, data.LastUpdateDate); err != nil {
 		_ = tx.Rollback()
 		log.Printf("[ERROR] Failed to log activity: %v", err)
 		return nil, fmt.Errorf("error logging activity: %w", err)
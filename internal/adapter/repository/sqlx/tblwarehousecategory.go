@@ -160,6 +160,11 @@ func (t *TblWarehouseCategoryRepository) Update(ctx context.Context, data *tblwa
 		}
 	}()
 
+	audit, err := newAuditTrail(ctx, tx, "WarehouseCategory", data.WhsCtCode)
+	if err != nil {
+		return nil, err
+	}
+
 	if _, err = tx.ExecContext(ctx, query, data.WhsCtName, data.LastUpdateBy, data.LastUpdateDate, data.WhsCtCode); err != nil {
 		_ = tx.Rollback()
 		log.Printf("[ERROR] Failed to update warehouse category: %v", err)
@@ -167,10 +172,7 @@ func (t *TblWarehouseCategoryRepository) Update(ctx context.Context, data *tblwa
 	}
 
 	// Log activity
-	logActivityQuery := "INSERT INTO tbllogactivity (UserCode, Code, Category, LastUpDt) VALUES (?, ?, ?, ?)"
-	log.Printf("[QUERY] %s", logActivityQuery)
-
-	if _, err = tx.ExecContext(ctx, logActivityQuery, data.LastUpdateBy, data.WhsCtCode, "WarehouseCategory", data.LastUpdateDate); err != nil {
+	if err = audit.Write(ctx, tx, data.LastUpdateBy, data.LastUpdateDate); err != nil {
 		_ = tx.Rollback()
 		log.Printf("[ERROR] Failed to insert log activity: %v", err)
 		return nil, fmt.Errorf("error logging activity: %w", err)
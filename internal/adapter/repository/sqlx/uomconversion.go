@@ -0,0 +1,269 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/uomconversion"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+// UomConversionRepository mengelola konversi antar UoM. Baris dengan ItCode
+// kosong berlaku untuk semua item.
+//
+//	CREATE TABLE tbluomconversion (
+//		ItCode VARCHAR(40) NOT NULL DEFAULT '',
+//		UomFrom VARCHAR(16) NOT NULL,
+//		UomTo VARCHAR(16) NOT NULL,
+//		Factor DECIMAL(18,6) NOT NULL,
+//		CreateBy VARCHAR(16) NOT NULL,
+//		CreateDt VARCHAR(12) NOT NULL,
+//		LastUpBy VARCHAR(16) NULL,
+//		LastUpDt VARCHAR(12) NULL,
+//		PRIMARY KEY (ItCode, UomFrom, UomTo)
+//	);
+type UomConversionRepository struct {
+	DB *repository.Sqlx `inject:"database"`
+}
+
+// kolom tblitem untuk tiap jenis UoM item
+var itemUomColumns = map[string]string{
+	uomconversion.Purchase:  "i.PurchaseUomCode",
+	uomconversion.Inventory: "i.InventoryUOMCode",
+	uomconversion.Sales:     "i.SalesUomCode",
+}
+
+type itemUom struct {
+	ItCode  string `db:"ItCode"`
+	UomFrom string `db:"UomFrom"`
+	UomTo   string `db:"UomTo"`
+}
+
+func (t *UomConversionRepository) Fetch(ctx context.Context, itemCode, uom string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	var totalRecords int
+	where := "c.ItCode LIKE ? AND (c.UomFrom LIKE ? OR c.UomTo LIKE ?)"
+	args := []interface{}{"%" + itemCode + "%", "%" + uom + "%", "%" + uom + "%"}
+
+	countQuery := "SELECT COUNT(*) FROM tbluomconversion c WHERE " + where
+	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+
+	var totalPages, offset int
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+	}
+
+	data := make([]*uomconversion.Conversion, 0)
+	query := `SELECT
+			c.ItCode,
+			COALESCE(i.ItName, '') AS ItName,
+			c.UomFrom,
+			c.UomTo,
+			c.Factor
+		FROM tbluomconversion c
+		LEFT JOIN tblitem i ON c.ItCode = i.ItCode
+		WHERE ` + where + `
+		ORDER BY c.ItCode, c.UomFrom, c.UomTo
+		LIMIT ? OFFSET ?`
+	if err := t.DB.SelectContext(ctx, &data, query, append(args, param.PageSize, offset)...); err != nil {
+		return nil, fmt.Errorf("error Fetch uom conversion: %w", err)
+	}
+
+	j := offset
+	for _, d := range data {
+		j++
+		d.Number = uint(j)
+	}
+
+	return &pagination.PaginationResponse{
+		Data:         data,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}, nil
+}
+
+// Save membuat atau mengganti faktor konversi
+func (t *UomConversionRepository) Save(ctx context.Context, data *uomconversion.Conversion) (*uomconversion.Conversion, error) {
+	query := `INSERT INTO tbluomconversion (
+			ItCode,
+			UomFrom,
+			UomTo,
+			Factor,
+			CreateBy,
+			CreateDt
+		) VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			Factor = VALUES(Factor),
+			LastUpBy = VALUES(CreateBy),
+			LastUpDt = VALUES(CreateDt)`
+	if _, err := t.DB.ExecContext(ctx, query,
+		data.ItemCode,
+		data.UomFrom,
+		data.UomTo,
+		data.Factor,
+		data.CreateBy,
+		data.CreateDate,
+	); err != nil {
+		log.Printf("Detailed error: %+v", err)
+		return nil, fmt.Errorf("error Save Uom Conversion: %w", err)
+	}
+
+	return data, nil
+}
+
+func (t *UomConversionRepository) Delete(ctx context.Context, itemCode, uomFrom, uomTo string) error {
+	res, err := t.DB.ExecContext(ctx, "DELETE FROM tbluomconversion WHERE ItCode = ? AND UomFrom = ? AND UomTo = ?", itemCode, uomFrom, uomTo)
+	if err != nil {
+		return fmt.Errorf("error Delete Uom Conversion: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return customerrors.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// ItemFactors dipakai saat posting, semua item harus punya konversi
+func (t *UomConversionRepository) ItemFactors(ctx context.Context, q sqlx.QueryerContext, itemCodes []string, from, to string) (map[string]float32, error) {
+	items, err := t.itemUoms(ctx, q, itemCodes, itemUomColumns[from], itemUomColumns[to])
+	if err != nil {
+		return nil, err
+	}
+	conversions, err := t.conversions(ctx, q, itemCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	factors := make(map[string]float32, len(items))
+	for _, i := range items {
+		factor, ok := uomFactor(conversions, i.ItCode, i.UomFrom, i.UomTo)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s from %s to %s", customerrors.ErrUomConversion, i.ItCode, i.UomFrom, i.UomTo)
+		}
+		factors[i.ItCode] = factor
+	}
+
+	return factors, nil
+}
+
+// ToUom dipakai laporan. Satu laporan selalu dalam satu UoM, jadi item yang
+// tidak bisa dikonversi ditolak dengan ErrUomConversion seperti saat posting.
+func (t *UomConversionRepository) ToUom(ctx context.Context, itemCodes []string, uomCode string) (string, map[string]float32, error) {
+	var uomName string
+	if err := t.DB.GetContext(ctx, &uomName, "SELECT UomName FROM tbluom WHERE UomCode = ?", uomCode); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil, fmt.Errorf("%w: unknown uom %s", customerrors.ErrUomConversion, uomCode)
+		}
+		return "", nil, fmt.Errorf("error get uom: %w", err)
+	}
+
+	factors := map[string]float32{}
+	if len(itemCodes) == 0 {
+		return uomName, factors, nil
+	}
+
+	items, err := t.itemUoms(ctx, t.DB, itemCodes, itemUomColumns[uomconversion.Inventory], "''")
+	if err != nil {
+		return "", nil, err
+	}
+	conversions, err := t.conversions(ctx, t.DB, itemCodes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var missing []string
+	for _, i := range items {
+		factor, ok := uomFactor(conversions, i.ItCode, i.UomFrom, uomCode)
+		if !ok {
+			missing = append(missing, i.ItCode)
+			continue
+		}
+		factors[i.ItCode] = factor
+	}
+	if len(missing) > 0 {
+		return "", nil, fmt.Errorf("%w: %s to %s", customerrors.ErrUomConversion, strings.Join(missing, ", "), uomCode)
+	}
+
+	return uomName, factors, nil
+}
+
+// itemUoms membaca pasangan UoM per item, from dan to adalah kolom tblitem
+// (atau ” kalau UoM tujuan tidak diambil dari item)
+func (t *UomConversionRepository) itemUoms(ctx context.Context, q sqlx.QueryerContext, itemCodes []string, from, to string) ([]itemUom, error) {
+	if from == "" || to == "" {
+		return nil, customerrors.ErrInvalidInput
+	}
+
+	placeholders := make([]string, len(itemCodes))
+	args := make([]interface{}, len(itemCodes))
+	for i, code := range itemCodes {
+		placeholders[i] = "?"
+		args[i] = code
+	}
+
+	var items []itemUom
+	query := "SELECT i.ItCode, " + from + " AS UomFrom, " + to + " AS UomTo FROM tblitem i WHERE i.ItCode IN (" + strings.Join(placeholders, ",") + ")"
+	if err := sqlx.SelectContext(ctx, q, &items, query, args...); err != nil {
+		return nil, fmt.Errorf("error get item uom: %w", err)
+	}
+
+	return items, nil
+}
+
+func (t *UomConversionRepository) conversions(ctx context.Context, q sqlx.QueryerContext, itemCodes []string) ([]uomconversion.Conversion, error) {
+	placeholders := make([]string, len(itemCodes))
+	args := make([]interface{}, len(itemCodes))
+	for i, code := range itemCodes {
+		placeholders[i] = "?"
+		args[i] = code
+	}
+
+	var conversions []uomconversion.Conversion
+	query := "SELECT ItCode, UomFrom, UomTo, Factor FROM tbluomconversion WHERE ItCode = '' OR ItCode IN (" + strings.Join(placeholders, ",") + ")"
+	if err := sqlx.SelectContext(ctx, q, &conversions, query, args...); err != nil {
+		return nil, fmt.Errorf("error get uom conversion: %w", err)
+	}
+
+	return conversions, nil
+}
+
+// uomFactor mencari faktor from -> to, konversi item didahulukan dari global
+func uomFactor(conversions []uomconversion.Conversion, itemCode, from, to string) (float32, bool) {
+	if from == to {
+		return 1, true
+	}
+
+	for _, scope := range []string{itemCode, ""} {
+		for _, c := range conversions {
+			if c.ItemCode != scope {
+				continue
+			}
+			if c.UomFrom == from && c.UomTo == to {
+				return c.Factor, true
+			}
+			if c.UomFrom == to && c.UomTo == from {
+				return 1 / c.Factor, true
+			}
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,91 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/uomconversion"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+const (
+	queryUomItem       = "SELECT i.ItCode, i.PurchaseUomCode AS UomFrom, i.InventoryUOMCode AS UomTo FROM tblitem i WHERE i.ItCode IN (?,?)"
+	queryUomConversion = "SELECT ItCode, UomFrom, UomTo, Factor FROM tbluomconversion WHERE ItCode = '' OR ItCode IN (?,?)"
+)
+
+type UomConversionRepositorySuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	repo    *UomConversionRepository
+	db      *sqlx.DB
+}
+
+func (suite *UomConversionRepositorySuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.repo = &UomConversionRepository{
+		DB: &repository.Sqlx{DB: suite.db},
+	}
+}
+
+func (suite *UomConversionRepositorySuite) TearDownTest() {
+	suite.db.Close()
+}
+
+// IT001 punya konversi sendiri (1 BOX = 12 PCS) yang menimpa global 1 BOX = 10 PCS,
+// IT002 memakai global dari arah sebaliknya (1 PCS = 0.5 PAK)
+func (suite *UomConversionRepositorySuite) TestItemFactors_ItemOverridesGlobal() {
+	suite.mockSQL.ExpectBegin()
+	tx, err := suite.db.Beginx()
+	suite.Require().NoError(err)
+
+	suite.mockSQL.ExpectQuery(queryUomItem).
+		WithArgs("IT001", "IT002").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "UomFrom", "UomTo"}).
+			AddRow("IT001", "BOX", "PCS").
+			AddRow("IT002", "PAK", "PCS"))
+	suite.mockSQL.ExpectQuery(queryUomConversion).
+		WithArgs("IT001", "IT002").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "UomFrom", "UomTo", "Factor"}).
+			AddRow("", "BOX", "PCS", 10).
+			AddRow("IT001", "BOX", "PCS", 12).
+			AddRow("", "PCS", "PAK", 0.5))
+
+	factors, err := suite.repo.ItemFactors(context.Background(), tx, []string{"IT001", "IT002"}, uomconversion.Purchase, uomconversion.Inventory)
+
+	suite.Require().NoError(err)
+	suite.Equal(float32(12), factors["IT001"])
+	suite.Equal(float32(2), factors["IT002"])
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func (suite *UomConversionRepositorySuite) TestItemFactors_MissingConversion() {
+	suite.mockSQL.ExpectBegin()
+	tx, err := suite.db.Beginx()
+	suite.Require().NoError(err)
+
+	suite.mockSQL.ExpectQuery(queryUomItem).
+		WithArgs("IT001", "IT002").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "UomFrom", "UomTo"}).
+			AddRow("IT001", "PCS", "PCS").
+			AddRow("IT002", "BOX", "PCS"))
+	suite.mockSQL.ExpectQuery(queryUomConversion).
+		WithArgs("IT001", "IT002").
+		WillReturnRows(sqlmock.NewRows([]string{"ItCode", "UomFrom", "UomTo", "Factor"}))
+
+	_, err = suite.repo.ItemFactors(context.Background(), tx, []string{"IT001", "IT002"}, uomconversion.Purchase, uomconversion.Inventory)
+
+	suite.ErrorIs(err, customerrors.ErrUomConversion)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func TestUomConversionRepositorySuite(t *testing.T) {
+	suite.Run(t, new(UomConversionRepositorySuite))
+}
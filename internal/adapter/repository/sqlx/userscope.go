@@ -0,0 +1,99 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+)
+
+// warehouseScope menghasilkan kondisi WHERE yang membatasi kolom gudang ke
+// gudang milik user di ctx. Dengan beberapa kolom (mis. gudang asal dan tujuan)
+// dokumen cukup cocok di salah satunya. String kosong berarti tanpa batasan:
+// user punya "*" atau ctx tidak membawa claims (proses internal).
+func warehouseScope(ctx context.Context, columns ...string) (string, []interface{}) {
+	claims, ok := jwt.FromContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	return scopeCondition(claims.Warehouses, columns)
+}
+
+func siteScope(ctx context.Context, columns ...string) (string, []interface{}) {
+	claims, ok := jwt.FromContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	return scopeCondition(claims.Sites, columns)
+}
+
+func scopeCondition(allowed []string, columns []string) (string, []interface{}) {
+	if slices.Contains(allowed, "*") {
+		return "", nil
+	}
+	if len(allowed) == 0 {
+		return "1 = 0", nil
+	}
+
+	var conditions []string
+	var args []interface{}
+	for _, column := range columns {
+		conditions = append(conditions, column+" IN (?"+strings.Repeat(",?", len(allowed)-1)+")")
+		for _, code := range allowed {
+			args = append(args, code)
+		}
+	}
+
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+// checkWarehouse menolak create / update terhadap gudang di luar hak user.
+func checkWarehouse(ctx context.Context, whsCodes ...string) error {
+	claims, ok := jwt.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	for _, whsCode := range whsCodes {
+		if !claims.AllowWarehouse(whsCode) {
+			return fmt.Errorf("%w: %s", customerrors.ErrWarehouseNotAllowed, whsCode)
+		}
+	}
+	return nil
+}
+
+func checkSite(ctx context.Context, siteCodes ...string) error {
+	claims, ok := jwt.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	for _, siteCode := range siteCodes {
+		// site opsional di beberapa dokumen
+		if siteCode != "" && !claims.AllowSite(siteCode) {
+			return fmt.Errorf("%w: %s", customerrors.ErrSiteNotAllowed, siteCode)
+		}
+	}
+	return nil
+}
+
+// checkDocWarehouse mengecek gudang dokumen yang tersimpan, bukan yang dikirim
+// client, sebelum dokumen di-update.
+func checkDocWarehouse(ctx context.Context, tx *sqlx.Tx, table, column, docNo string) error {
+	if claims, ok := jwt.FromContext(ctx); !ok || claims.AllWarehouse() {
+		return nil
+	}
+
+	var whsCode string
+	if err := tx.GetContext(ctx, &whsCode, "SELECT "+column+" FROM "+table+" WHERE DocNo = ?", docNo); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return customerrors.ErrDataNotFound
+		}
+		return fmt.Errorf("error fetching document warehouse: %w", err)
+	}
+	return checkWarehouse(ctx, whsCode)
+}
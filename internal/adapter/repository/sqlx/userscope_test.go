@@ -0,0 +1,96 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+)
+
+type UserScopeSuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	db      *sqlx.DB
+}
+
+func (suite *UserScopeSuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+}
+
+func (suite *UserScopeSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func userContext(warehouses, sites []string) context.Context {
+	return context.WithValue(context.Background(), "user", &jwt.Claims{
+		UserCode:   "clerk",
+		Warehouses: warehouses,
+		Sites:      sites,
+	})
+}
+
+func (suite *UserScopeSuite) TestWarehouseScope_Assigned() {
+	scope, args := warehouseScope(userContext([]string{"WH1", "WH2"}, nil), "h.WhsCodeFrom", "h.WhsCodeTo")
+
+	suite.Equal("(h.WhsCodeFrom IN (?,?) OR h.WhsCodeTo IN (?,?))", scope)
+	suite.Equal([]interface{}{"WH1", "WH2", "WH1", "WH2"}, args)
+}
+
+func (suite *UserScopeSuite) TestWarehouseScope_AllWarehouse() {
+	scope, args := warehouseScope(userContext([]string{"*"}, nil), "s.WhsCode")
+
+	suite.Empty(scope)
+	suite.Nil(args)
+}
+
+// user tanpa gudang tidak boleh melihat dokumen apa pun
+func (suite *UserScopeSuite) TestWarehouseScope_NoWarehouse() {
+	scope, args := warehouseScope(userContext(nil, nil), "s.WhsCode")
+
+	suite.Equal("1 = 0", scope)
+	suite.Nil(args)
+}
+
+func (suite *UserScopeSuite) TestWarehouseScope_WithoutClaims() {
+	scope, _ := warehouseScope(context.Background(), "s.WhsCode")
+
+	suite.Empty(scope)
+}
+
+func (suite *UserScopeSuite) TestCheckWarehouse() {
+	ctx := userContext([]string{"WH1"}, []string{"S1"})
+
+	suite.NoError(checkWarehouse(ctx, "WH1"))
+	suite.ErrorIs(checkWarehouse(ctx, "WH1", "WH9"), customerrors.ErrWarehouseNotAllowed)
+	suite.NoError(checkSite(ctx, "S1", ""))
+	suite.ErrorIs(checkSite(ctx, "S9"), customerrors.ErrSiteNotAllowed)
+}
+
+func (suite *UserScopeSuite) TestCheckDocWarehouse_StoredWarehouse() {
+	ctx := userContext([]string{"WH1"}, nil)
+
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectQuery("SELECT WhsCode FROM tblstockmutationhdr WHERE DocNo = ?").
+		WithArgs("0001/R1/SM/01/25").
+		WillReturnRows(sqlmock.NewRows([]string{"WhsCode"}).AddRow("WH2"))
+
+	tx, err := suite.db.Beginx()
+	suite.Require().NoError(err)
+
+	err = checkDocWarehouse(ctx, tx, "tblstockmutationhdr", "WhsCode", "0001/R1/SM/01/25")
+
+	suite.ErrorIs(err, customerrors.ErrWarehouseNotAllowed)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func TestUserScopeSuite(t *testing.T) {
+	suite.Run(t, new(UserScopeSuite))
+}
@@ -0,0 +1,470 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitlab.com/ayaka/internal/adapter/repository"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+	"gitlab.com/ayaka/internal/domain/shared/formatid"
+	"gitlab.com/ayaka/internal/domain/vendorinvoice"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+// VendorInvoiceRepository mencatat invoice vendor (AP invoice) per baris PO +
+// baris purchase material receive dan menjalankan three-way match. Invoice
+// dengan baris yang tidak match ditandai PaymentBlockInd = 'Y'.
+//
+//	CREATE TABLE tblvendorinvoicehdr (
+//		DocNo VARCHAR(30) NOT NULL PRIMARY KEY,
+//		DocDt VARCHAR(8) NOT NULL,
+//		VendorCode VARCHAR(16) NOT NULL,
+//		VdInvNo VARCHAR(40) NOT NULL,
+//		CurCode VARCHAR(16) NOT NULL,
+//		DueDt VARCHAR(8) NOT NULL,
+//		Amt DECIMAL(18,4) NOT NULL DEFAULT 0,
+//		PaymentBlockInd CHAR(1) NOT NULL DEFAULT 'N',
+//		Remark VARCHAR(400) NULL,
+//		CreateBy VARCHAR(16) NOT NULL,
+//		CreateDt VARCHAR(12) NOT NULL,
+//		LastUpBy VARCHAR(16) NULL,
+//		LastUpDt VARCHAR(12) NULL,
+//		UNIQUE KEY uk_vendor_invoice (VendorCode, VdInvNo),
+//		KEY idx_vendor_invoice_due (VendorCode, DueDt)
+//	);
+//
+//	CREATE TABLE tblvendorinvoicedtl (
+//		DocNo VARCHAR(30) NOT NULL,
+//		DNo VARCHAR(3) NOT NULL,
+//		PurchaseOrderDocNo VARCHAR(30) NOT NULL,
+//		PurchaseOrderDNo VARCHAR(3) NOT NULL,
+//		ReceiveDocNo VARCHAR(30) NOT NULL,
+//		ReceiveDNo VARCHAR(3) NOT NULL,
+//		ItCode VARCHAR(40) NOT NULL,
+//		Qty DECIMAL(18,4) NOT NULL,
+//		UPrice DECIMAL(18,4) NOT NULL,
+//		Amt DECIMAL(18,4) NOT NULL,
+//		PoQty DECIMAL(18,4) NOT NULL,
+//		PoPrice DECIMAL(18,4) NOT NULL,
+//		ReceivedQty DECIMAL(18,4) NOT NULL,
+//		InvoicedQty DECIMAL(18,4) NOT NULL,
+//		MatchStatus VARCHAR(30) NOT NULL,
+//		Remark VARCHAR(400) NULL,
+//		CreateBy VARCHAR(16) NOT NULL,
+//		CreateDt VARCHAR(12) NOT NULL,
+//		PRIMARY KEY (DocNo, DNo),
+//		KEY idx_vendor_invoice_receive (ReceiveDocNo, ReceiveDNo)
+//	);
+type VendorInvoiceRepository struct {
+	DB *repository.Sqlx            `inject:"database"`
+	ID *formatid.GenerateIDHandler `inject:"generateID"`
+}
+
+const vendorInvoiceSelect = `SELECT
+		h.DocNo,
+		h.DocDt,
+		h.VendorCode,
+		v.VendorName,
+		h.VdInvNo,
+		h.CurCode,
+		h.DueDt,
+		h.Amt,
+		h.PaymentBlockInd,
+		h.Remark,
+		h.CreateBy
+	FROM tblvendorinvoicehdr h
+	JOIN tblvendorhdr v ON h.VendorCode = v.VendorCode`
+
+func (t *VendorInvoiceRepository) Fetch(ctx context.Context, doc, vendor string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	var totalRecords int
+	where := "(h.DocNo LIKE ? OR h.VdInvNo LIKE ?) AND h.VendorCode LIKE ?"
+	args := []interface{}{"%" + doc + "%", "%" + doc + "%", "%" + vendor + "%"}
+
+	countQuery := "SELECT COUNT(*) FROM tblvendorinvoicehdr h WHERE " + where
+	if err := t.DB.GetContext(ctx, &totalRecords, countQuery, args...); err != nil {
+		return nil, fmt.Errorf("error counting records: %w", err)
+	}
+
+	var totalPages, offset int
+	if param != nil {
+		totalPages, offset = pagination.CountPagination(param, totalRecords)
+	} else {
+		param = &pagination.PaginationParam{
+			PageSize: totalRecords,
+			Page:     1,
+		}
+		totalPages = 1
+	}
+
+	data := make([]*vendorinvoice.Invoice, 0)
+	query := vendorInvoiceSelect + " WHERE " + where + " ORDER BY h.CreateDt DESC LIMIT ? OFFSET ?"
+	if err := t.DB.SelectContext(ctx, &data, query, append(args, param.PageSize, offset)...); err != nil {
+		return nil, fmt.Errorf("error fetch vendor invoice: %w", err)
+	}
+
+	j := offset
+	for _, d := range data {
+		j++
+		d.Number = uint(j)
+		d.Date = share.FormatDate(d.Date)
+		d.DueDate = share.FormatDate(d.DueDate)
+	}
+
+	return &pagination.PaginationResponse{
+		Data:         data,
+		TotalRecords: totalRecords,
+		TotalPages:   totalPages,
+		CurrentPage:  param.Page,
+		PageSize:     param.PageSize,
+		HasNext:      param.Page < totalPages,
+		HasPrevious:  param.Page > 1,
+	}, nil
+}
+
+func (t *VendorInvoiceRepository) Detail(ctx context.Context, docNo string) (*vendorinvoice.Invoice, error) {
+	var detail vendorinvoice.Invoice
+	if err := t.DB.GetContext(ctx, &detail, vendorInvoiceSelect+" WHERE h.DocNo = ?", docNo); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerrors.ErrDataNotFound
+		}
+		return nil, fmt.Errorf("error detail vendor invoice: %w", err)
+	}
+	detail.Date = share.FormatDate(detail.Date)
+	detail.DueDate = share.FormatDate(detail.DueDate)
+
+	query := `SELECT
+			d.DNo,
+			d.PurchaseOrderDocNo,
+			d.PurchaseOrderDNo,
+			d.ReceiveDocNo,
+			d.ReceiveDNo,
+			d.ItCode,
+			i.ItName,
+			d.Qty,
+			d.UPrice,
+			d.Amt,
+			d.PoQty,
+			d.PoPrice,
+			d.ReceivedQty,
+			d.InvoicedQty,
+			d.MatchStatus,
+			d.Remark
+		FROM tblvendorinvoicedtl d
+		JOIN tblitem i ON d.ItCode = i.ItCode
+		WHERE d.DocNo = ?
+		ORDER BY d.DNo`
+	if err := t.DB.SelectContext(ctx, &detail.Details, query, docNo); err != nil {
+		return nil, fmt.Errorf("error detail vendor invoice lines: %w", err)
+	}
+
+	return &detail, nil
+}
+
+// receiptLine baris penerimaan beserta PO dan term of payment quotation-nya
+type receiptLine struct {
+	ReceiveDocNo       string  `db:"ReceiveDocNo"`
+	ReceiveDNo         string  `db:"ReceiveDNo"`
+	PurchaseOrderDocNo string  `db:"PurchaseOrderDocNo"`
+	PurchaseOrderDNo   string  `db:"PurchaseOrderDNo"`
+	ItCode             string  `db:"ItCode"`
+	VendorCode         string  `db:"VendorCode"`
+	ReceivedQty        float32 `db:"ReceivedQty"`
+	PoQty              float32 `db:"PoQty"`
+	PoPrice            float32 `db:"PoPrice"`
+	InvoicedQty        float32 `db:"InvoicedQty"`
+	TermOfPayment      string  `db:"TermOfPayment"`
+}
+
+// Create menyimpan invoice dan hasil matching-nya. Baris penerimaan dikunci
+// supaya dua invoice yang dibuat bersamaan tidak sama-sama lolos cek qty.
+func (t *VendorInvoiceRepository) Create(ctx context.Context, data *vendorinvoice.Invoice, tolerance vendorinvoice.Tolerance) (*vendorinvoice.Invoice, error) {
+	var err error
+	tx, err := t.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to start transaction: %+v", err)
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("Failed to rollback transaction: %+v", rbErr)
+			}
+		}
+	}()
+
+	var exists bool
+	if err = tx.GetContext(ctx, &exists, "SELECT EXISTS(SELECT 1 FROM tblvendorinvoicehdr WHERE VendorCode = ? AND VdInvNo = ?)", data.VendorCode, data.VendorInvoiceNo); err != nil {
+		return nil, fmt.Errorf("error check vendor invoice number: %w", err)
+	}
+	if exists {
+		err = fmt.Errorf("%w: vendor invoice %s already recorded", customerrors.ErrInvalidInput, data.VendorInvoiceNo)
+		return nil, err
+	}
+
+	tuples := make([]string, len(data.Details))
+	var args []interface{}
+	for i, d := range data.Details {
+		tuples[i] = "(?, ?)"
+		args = append(args, d.ReceiveDocNo, d.ReceiveDNo)
+	}
+
+	var lines []receiptLine
+	query := `SELECT
+			r.DocNo AS ReceiveDocNo,
+			r.DNo AS ReceiveDNo,
+			r.PurchaseOrderDocNo,
+			r.PurchaseOrderDNo,
+			r.ItCode,
+			ph.VendorCode,
+			r.PurchaseQty AS ReceivedQty,
+			p.Qty AS PoQty,
+			CASE WHEN p.Qty = 0 THEN 0 ELSE p.Total / p.Qty END AS PoPrice,
+			COALESCE((
+				SELECT SUM(vi.Qty)
+				FROM tblvendorinvoicedtl vi
+				WHERE vi.ReceiveDocNo = r.DocNo AND vi.ReceiveDNo = r.DNo
+			), 0) AS InvoicedQty,
+			COALESCE(vqh.TermOfPayment, '') AS TermOfPayment
+		FROM tblpurchasematerialreceivedtl r
+		JOIN tblpurchaseorderdtl p ON r.PurchaseOrderDocNo = p.DocNo AND r.PurchaseOrderDNo = p.DNo
+		JOIN tblpurchaseorderhdr ph ON p.DocNo = ph.DocNo
+		LEFT JOIN tblpurchaseorderreqdtl por ON p.PurchaseOrderReqDocNo = por.DocNo AND p.PurchaseOrderReqDNo = por.DNo
+		LEFT JOIN tblvendorquotationhdr vqh ON por.VendorQTDocNo = vqh.DocNo
+		WHERE (r.CancelInd IS NULL OR r.CancelInd != 'Y')
+		AND (r.DocNo, r.DNo) IN (` + strings.Join(tuples, ",") + `)
+		FOR UPDATE`
+	if err = tx.SelectContext(ctx, &lines, query, args...); err != nil {
+		return nil, fmt.Errorf("error get received lines: %w", err)
+	}
+
+	receipts := make(map[string]receiptLine, len(lines))
+	for _, l := range lines {
+		receipts[l.ReceiveDocNo+"*"+l.ReceiveDNo] = l
+	}
+
+	// jatuh tempo memakai term paling pendek dari PO yang ditagihkan
+	termDays := -1
+	invoiced := map[string]float32{}
+	data.Amount = 0
+	data.PaymentBlock = booldatatype.FromBool(false)
+	for i := range data.Details {
+		d := &data.Details[i]
+		key := d.ReceiveDocNo + "*" + d.ReceiveDNo
+		r, ok := receipts[key]
+		if !ok || r.PurchaseOrderDocNo != d.PurchaseOrderDocNo || r.PurchaseOrderDNo != d.PurchaseOrderDNo {
+			err = fmt.Errorf("%w: receive %s line %s is not a receipt of purchase order %s line %s", customerrors.ErrInvalidInput, d.ReceiveDocNo, d.ReceiveDNo, d.PurchaseOrderDocNo, d.PurchaseOrderDNo)
+			return nil, err
+		}
+		if r.VendorCode != data.VendorCode {
+			err = fmt.Errorf("%w: purchase order %s belongs to another vendor", customerrors.ErrInvalidInput, d.PurchaseOrderDocNo)
+			return nil, err
+		}
+
+		d.DNo = fmt.Sprintf("%03d", i+1)
+		d.ItemCode = r.ItCode
+		d.PoQty = r.PoQty
+		d.PoPrice = r.PoPrice
+		d.ReceivedQty = r.ReceivedQty
+		// baris penerimaan yang sama bisa muncul dua kali di satu invoice
+		d.InvoicedQty = r.InvoicedQty + invoiced[key]
+		invoiced[key] += d.Qty
+		d.Amount = d.Qty * d.UnitPrice
+		d.MatchStatus = threeWayMatch(d, tolerance)
+
+		data.Amount += d.Amount
+		if d.MatchStatus != vendorinvoice.Matched {
+			data.PaymentBlock = booldatatype.FromBool(true)
+		}
+		if days := paymentTermDays(r.TermOfPayment); termDays < 0 || days < termDays {
+			termDays = days
+		}
+	}
+
+	docDt, err := time.Parse("20060102", data.Date)
+	if err != nil {
+		err = customerrors.ErrInvalidInput
+		return nil, err
+	}
+	data.DueDate = docDt.AddDate(0, 0, max(termDays, 0)).Format("20060102")
+
+	data.DocNo, err = t.ID.GenerateID(ctx, tx, "VendorInvoice", data.Date)
+	if err != nil {
+		log.Printf("Error generate id: %+v", err)
+		return nil, fmt.Errorf("error Generate ID: %w", err)
+	}
+
+	query = `INSERT INTO tblvendorinvoicehdr (
+			DocNo,
+			DocDt,
+			VendorCode,
+			VdInvNo,
+			CurCode,
+			DueDt,
+			Amt,
+			PaymentBlockInd,
+			Remark,
+			CreateBy,
+			CreateDt
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	if _, err = tx.ExecContext(ctx, query,
+		data.DocNo,
+		data.Date,
+		data.VendorCode,
+		data.VendorInvoiceNo,
+		data.CurCode,
+		data.DueDate,
+		data.Amount,
+		data.PaymentBlock,
+		data.Remark,
+		data.CreateBy,
+		data.CreateDate,
+	); err != nil {
+		log.Printf("Error insert vendor invoice header: %+v", err)
+		return nil, fmt.Errorf("error Insert Header: %w", err)
+	}
+
+	var placeholders []string
+	args = args[:0]
+	for _, d := range data.Details {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		args = append(args,
+			data.DocNo,
+			d.DNo,
+			d.PurchaseOrderDocNo,
+			d.PurchaseOrderDNo,
+			d.ReceiveDocNo,
+			d.ReceiveDNo,
+			d.ItemCode,
+			d.Qty,
+			d.UnitPrice,
+			d.Amount,
+			d.PoQty,
+			d.PoPrice,
+			d.ReceivedQty,
+			d.InvoicedQty,
+			d.MatchStatus,
+			d.Remark,
+			data.CreateBy,
+			data.CreateDate,
+		)
+	}
+
+	query = `INSERT INTO tblvendorinvoicedtl (
+			DocNo,
+			DNo,
+			PurchaseOrderDocNo,
+			PurchaseOrderDNo,
+			ReceiveDocNo,
+			ReceiveDNo,
+			ItCode,
+			Qty,
+			UPrice,
+			Amt,
+			PoQty,
+			PoPrice,
+			ReceivedQty,
+			InvoicedQty,
+			MatchStatus,
+			Remark,
+			CreateBy,
+			CreateDt
+		) VALUES ` + strings.Join(placeholders, ",")
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		log.Printf("Error insert vendor invoice detail: %+v", err)
+		return nil, fmt.Errorf("error Insert Detail: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Failed to commit transaction: %+v", err)
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	data.Date = share.FormatDate(data.Date)
+	data.DueDate = share.FormatDate(data.DueDate)
+
+	return data, nil
+}
+
+// Payables belum ada modul pembayaran, jadi semua invoice dianggap terbuka
+func (t *VendorInvoiceRepository) Payables(ctx context.Context, vendor, dueDate, base string) ([]*vendorinvoice.Payable, error) {
+	filters := []string{"h.VendorCode LIKE ?"}
+	args := append(baseRateArgs(base), "%"+vendor+"%")
+	if dueDate != "" {
+		filters = append(filters, "h.DueDt <= ?")
+		args = append(args, dueDate)
+	}
+
+	data := make([]*vendorinvoice.Payable, 0)
+	query := `SELECT
+			h.VendorCode,
+			v.VendorName,
+			h.DocNo,
+			h.VdInvNo,
+			h.DocDt,
+			h.DueDt,
+			h.CurCode,
+			h.Amt,
+			h.PaymentBlockInd = 'Y' AS Blocked,
+			` + baseRateSQL("h.CurCode", "h.DocDt") + ` AS Rate
+		FROM tblvendorinvoicehdr h
+		JOIN tblvendorhdr v ON h.VendorCode = v.VendorCode
+		WHERE ` + strings.Join(filters, " AND ") + `
+		ORDER BY v.VendorName, h.DueDt, h.DocNo`
+	if err := t.DB.SelectContext(ctx, &data, query, args...); err != nil {
+		return nil, fmt.Errorf("error fetch payables: %w", err)
+	}
+
+	today, _ := time.Parse("20060102", time.Now().Format("20060102"))
+	for _, d := range data {
+		if due, err := time.Parse("20060102", d.DueDate); err == nil {
+			d.DaysOverdue = int(today.Sub(due).Hours() / 24)
+		}
+		d.Date = share.FormatDate(d.Date)
+		d.DueDate = share.FormatDate(d.DueDate)
+		d.BaseCurrency = base
+		d.BaseAmount = float64(d.Amount) * d.Rate
+		d.RateMissing = d.Rate == 0
+	}
+
+	return data, nil
+}
+
+// threeWayMatch membandingkan qty invoice (kumulatif dengan invoice sebelumnya)
+// terhadap qty diterima dan harga invoice terhadap harga satuan PO
+func threeWayMatch(d *vendorinvoice.Detail, tolerance vendorinvoice.Tolerance) string {
+	qtyOk := d.InvoicedQty+d.Qty <= d.ReceivedQty+d.ReceivedQty*tolerance.Qty/100
+	priceOk := math.Abs(float64(d.UnitPrice-d.PoPrice)) <= float64(d.PoPrice*tolerance.Price/100)
+
+	switch {
+	case qtyOk && priceOk:
+		return vendorinvoice.Matched
+	case priceOk:
+		return vendorinvoice.QtyVariance
+	case qtyOk:
+		return vendorinvoice.PriceVariance
+	}
+	return vendorinvoice.BothVariance
+}
+
+var termDigits = regexp.MustCompile(`\d+`)
+
+// paymentTermDays membaca jumlah hari dari TermOfPayment quotation yang berupa
+// teks bebas ("30", "NET 30", "30 hari"); tanpa angka dianggap tunai
+func paymentTermDays(term string) int {
+	days, err := strconv.Atoi(termDigits.FindString(term))
+	if err != nil {
+		return 0
+	}
+	return days
+}
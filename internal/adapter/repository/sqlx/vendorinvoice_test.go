@@ -0,0 +1,88 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/internal/adapter/repository"
+	"gitlab.com/ayaka/internal/domain/vendorinvoice"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+const (
+	queryVendorInvoiceExists = "SELECT EXISTS(SELECT 1 FROM tblvendorinvoicehdr WHERE VendorCode = ? AND VdInvNo = ?)"
+	queryVendorInvoiceLines  = "SELECT r.DocNo AS ReceiveDocNo, r.DNo AS ReceiveDNo, r.PurchaseOrderDocNo, r.PurchaseOrderDNo, r.ItCode, ph.VendorCode, r.PurchaseQty AS ReceivedQty, p.Qty AS PoQty, CASE WHEN p.Qty = 0 THEN 0 ELSE p.Total / p.Qty END AS PoPrice, COALESCE(( SELECT SUM(vi.Qty) FROM tblvendorinvoicedtl vi WHERE vi.ReceiveDocNo = r.DocNo AND vi.ReceiveDNo = r.DNo ), 0) AS InvoicedQty, COALESCE(vqh.TermOfPayment, '') AS TermOfPayment FROM tblpurchasematerialreceivedtl r JOIN tblpurchaseorderdtl p ON r.PurchaseOrderDocNo = p.DocNo AND r.PurchaseOrderDNo = p.DNo JOIN tblpurchaseorderhdr ph ON p.DocNo = ph.DocNo LEFT JOIN tblpurchaseorderreqdtl por ON p.PurchaseOrderReqDocNo = por.DocNo AND p.PurchaseOrderReqDNo = por.DNo LEFT JOIN tblvendorquotationhdr vqh ON por.VendorQTDocNo = vqh.DocNo WHERE (r.CancelInd IS NULL OR r.CancelInd != 'Y') AND (r.DocNo, r.DNo) IN ((?, ?)) FOR UPDATE"
+)
+
+type VendorInvoiceRepositorySuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	repo    *VendorInvoiceRepository
+	db      *sqlx.DB
+}
+
+func (suite *VendorInvoiceRepositorySuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.repo = &VendorInvoiceRepository{
+		DB: &repository.Sqlx{DB: suite.db},
+	}
+}
+
+func (suite *VendorInvoiceRepositorySuite) TearDownTest() {
+	suite.db.Close()
+}
+
+// qty dihitung kumulatif dengan invoice sebelumnya, toleransi dalam persen
+func (suite *VendorInvoiceRepositorySuite) TestThreeWayMatch() {
+	tolerance := vendorinvoice.Tolerance{Qty: 5, Price: 2}
+	cases := []struct {
+		detail vendorinvoice.Detail
+		status string
+	}{
+		{vendorinvoice.Detail{Qty: 50, UnitPrice: 101, ReceivedQty: 100, InvoicedQty: 55, PoPrice: 100}, vendorinvoice.Matched},
+		{vendorinvoice.Detail{Qty: 50, UnitPrice: 100, ReceivedQty: 100, InvoicedQty: 60, PoPrice: 100}, vendorinvoice.QtyVariance},
+		{vendorinvoice.Detail{Qty: 10, UnitPrice: 103, ReceivedQty: 100, PoPrice: 100}, vendorinvoice.PriceVariance},
+		{vendorinvoice.Detail{Qty: 110, UnitPrice: 90, ReceivedQty: 100, PoPrice: 100}, vendorinvoice.BothVariance},
+	}
+
+	for _, c := range cases {
+		suite.Equal(c.status, threeWayMatch(&c.detail, tolerance))
+	}
+	suite.Equal(30, paymentTermDays("NET 30"))
+	suite.Equal(0, paymentTermDays("COD"))
+}
+
+func (suite *VendorInvoiceRepositorySuite) TestCreate_RejectsOtherVendorPO() {
+	suite.mockSQL.ExpectBegin()
+	suite.mockSQL.ExpectQuery(queryVendorInvoiceExists).
+		WithArgs("V001", "INV-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	suite.mockSQL.ExpectQuery(queryVendorInvoiceLines).
+		WithArgs("RCV1", "001").
+		WillReturnRows(sqlmock.NewRows([]string{"ReceiveDocNo", "ReceiveDNo", "PurchaseOrderDocNo", "PurchaseOrderDNo", "ItCode", "VendorCode", "ReceivedQty", "PoQty", "PoPrice", "InvoicedQty", "TermOfPayment"}).
+			AddRow("RCV1", "001", "PO1", "001", "IT001", "V002", 10, 10, 100, 0, "30"))
+	suite.mockSQL.ExpectRollback()
+
+	_, err := suite.repo.Create(context.Background(), &vendorinvoice.Invoice{
+		Date:            "20260110",
+		VendorCode:      "V001",
+		VendorInvoiceNo: "INV-1",
+		Details: []vendorinvoice.Detail{
+			{PurchaseOrderDocNo: "PO1", PurchaseOrderDNo: "001", ReceiveDocNo: "RCV1", ReceiveDNo: "001", Qty: 10, UnitPrice: 100},
+		},
+	}, vendorinvoice.Tolerance{})
+
+	suite.ErrorIs(err, customerrors.ErrInvalidInput)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func TestVendorInvoiceRepositorySuite(t *testing.T) {
+	suite.Run(t, new(VendorInvoiceRepositorySuite))
+}
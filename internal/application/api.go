@@ -32,6 +32,8 @@ type Api struct {
 	TblDirectPurchaseReceive          api.TblDirectPurchaseReceiveApi     `inject:"tblDirectPurchaseReceiveHandler"`
 	TblTaxGroupHandler                api.TblTaxGroupApi                  `inject:"tblTaxGroupHandler"`
 	TblTaxHandler                     api.TblTaxApi                       `inject:"tblTaxHandler"`
+	TaxEngineHandler                  api.TaxEngineApi                    `inject:"taxEngineHandler"`
+	EfakturHandler                    api.EfakturApi                      `inject:"efakturHandler"`
 	TblCustomerCategoryHandler        api.TblCustomerCategoryApi          `inject:"tblCustomerCategoryHandler"`
 	TblSiteHandler                    api.TblSiteApi                      `inject:"tblSiteHandler"`
 	TblVendorCategoryHandler          api.TblVendorCategoryApi            `inject:"tblVendorCategoryHandler"`
@@ -44,6 +46,7 @@ type Api struct {
 	TblDirectSalesDeliveryHandler     api.TblDirectSalesDeliveryApi       `inject:"tblDirectSalesDeliveryHandler"`
 	TblMaterialTransferHandler        api.TblMaterialTransferApi          `inject:"tblMaterialTransferHandler"`
 	TblMaterialReceiveHandler         api.TblMaterialReceiveApi           `inject:"tblMaterialReceiveHandler"`
+	InTransitHandler                  api.InTransitApi                    `inject:"inTransitHandler"`
 	TblTransferItemBetweenWhsHandler  api.TblTransferItemBetweenWhsApi    `inject:"tblTransferItemBetweenWhsHandler"`
 	TblDirectMaterialReceiveHandler   api.TblDirectMaterialReceiveApi     `inject:"tblDirectMaterialReceiveHandler"`
 	TblMaterialRequestHandler         api.TblMaterialRequestApi           `inject:"tblMaterialRequestHandler"`
@@ -54,6 +57,24 @@ type Api struct {
 	TblPurchaseReturnDeliveryHandler  api.TblPurchaseReturnDeliveryApi    `inject:"tblPurchaseReturnDeliveryHandler"`
 	TblOrderReportHandler             api.TblOrderReportApi               `inject:"tblOrderReportHandler"`
 	DashboardHandler                  api.DashboardApi                    `inject:"DashboardHandler"`
+	TblUserGroupHandler               api.TblUserGroupApi                 `inject:"tblUserGroupHandler"`
+	TblUserScopeHandler               api.TblUserScopeApi                 `inject:"tblUserScopeHandler"`
+	DocApprovalHandler                api.DocApprovalApi                  `inject:"docApprovalHandler"`
+	PrintoutHandler                   api.PrintoutApi                     `inject:"printoutHandler"`
+	MasterImportHandler               api.MasterImportApi                 `inject:"masterImportHandler"`
+	StockValuationHandler             api.StockValuationApi               `inject:"stockValuationHandler"`
+	JournalHandler                    api.JournalApi                      `inject:"journalHandler"`
+	StockCardHandler                  api.StockCardApi                    `inject:"stockCardHandler"`
+	StockOpnameHandler                api.StockOpnameApi                  `inject:"stockOpnameHandler"`
+	BinHandler                        api.BinApi                          `inject:"binHandler"`
+	BatchHandler                      api.BatchApi                        `inject:"batchHandler"`
+	ReplenishmentHandler              api.ReplenishmentApi                `inject:"replenishmentHandler"`
+	UomConversionHandler              api.UomConversionApi                `inject:"uomConversionHandler"`
+	VendorInvoiceHandler              api.VendorInvoiceApi                `inject:"vendorInvoiceHandler"`
+	QuotationComparisonHandler        api.QuotationComparisonApi          `inject:"quotationComparisonHandler"`
+	TblCustomerHandler                api.TblCustomerApi                  `inject:"tblCustomerHandler"`
+	TblSalesOrderHandler              api.TblSalesOrderApi                `inject:"tblSalesOrderHandler"`
+	ExchangeRateHandler               api.ExchangeRateApi                 `inject:"exchangeRateHandler"`
 }
 
 func (a *Api) Startup() error {
@@ -80,275 +101,427 @@ func (a *Api) Startup() error {
 	v1 := a.App.Group("/v1")
 	v1.Use(a.MiddlewareHandler.AuthRequired())
 
+	// permission menu:action dari tblgroupmenu, lihat tblusergroup.Permission
+	perm := a.MiddlewareHandler.RequirePermission
+
 	// log route
 	log := v1.Group("/log")
-	log.Get("/", a.TblLogHandler.GetLog)
+	log.Get("/", perm("log:read"), a.TblLogHandler.GetLog)
 
 	// country routes group
 	country := v1.Group("/country")
-	country.Get("/", a.TblCountryHandler.FetchCountries) //get and search countries with pagination and all countries without pagination
-	country.Post("/", a.TblCountryHandler.Create)        //create a new country
-	country.Put("/:code", a.TblCountryHandler.Update)    //edit a country by country code
+	country.Get("/", perm("country:read"), a.TblCountryHandler.FetchCountries) //get and search countries with pagination and all countries without pagination
+	country.Post("/", perm("country:create"), a.TblCountryHandler.Create)        //create a new country
+	country.Put("/:code", perm("country:update"), a.TblCountryHandler.Update)    //edit a country by country code
 
 	province := v1.Group("/province")
-	province.Get("/", a.TblProvinceHandler.FetchProvinces)         // Get and search provinces with pagination
-	province.Get("/group", a.TblProvinceHandler.GetGroupProvinces) // Get all provinces grouped by criteria
-	province.Get("/:search", a.TblProvinceHandler.DetailProvince)  // Get detail of a province by province code
-	province.Post("/", a.TblProvinceHandler.Create)                // Create a new province
-	province.Put("/:id", a.TblProvinceHandler.Update)              // Update a province by province code
+	province.Get("/", perm("province:read"), a.TblProvinceHandler.FetchProvinces)         // Get and search provinces with pagination
+	province.Get("/group", perm("province:read"), a.TblProvinceHandler.GetGroupProvinces) // Get all provinces grouped by criteria
+	province.Get("/:search", perm("province:read"), a.TblProvinceHandler.DetailProvince)  // Get detail of a province by province code
+	province.Post("/", perm("province:create"), a.TblProvinceHandler.Create)                // Create a new province
+	province.Put("/:id", perm("province:update"), a.TblProvinceHandler.Update)              // Update a province by province code
 
 	//city routes group
 	city := v1.Group("/city")
-	city.Get("/group", a.TblCityHandler.GetGroupCities) //get all cities group by province
-	city.Get("/", a.TblCityHandler.FetchCities)         //get and search cities with pagination
-	city.Post("/", a.TblCityHandler.Create)             //create a new city
-	city.Put("/:code", a.TblCityHandler.Update)         //update a city by city code
+	city.Get("/group", perm("city:read"), a.TblCityHandler.GetGroupCities) //get all cities group by province
+	city.Get("/", perm("city:read"), a.TblCityHandler.FetchCities)         //get and search cities with pagination
+	city.Post("/", perm("city:create"), a.TblCityHandler.Create)             //create a new city
+	city.Put("/:code", perm("city:update"), a.TblCityHandler.Update)         //update a city by city code
 
 	// UOM routes group
 	uom := v1.Group("/uom")
-	uom.Get("/", a.TblUomHandler.FetchUom) //get and search uoms with pagination and all uoms data without pagination
-	uom.Post("/", a.TblUomHandler.Create)  //create a new uom
-	uom.Put("/:code", a.TblUomHandler.Update)
+	uom.Get("/", perm("uom:read"), a.TblUomHandler.FetchUom) //get and search uoms with pagination and all uoms data without pagination
+	uom.Post("/", perm("uom:create"), a.TblUomHandler.Create)  //create a new uom
+	uom.Put("/:code", perm("uom:update"), a.TblUomHandler.Update)
+	uom.Post("/import", perm("uom:create"), a.MasterImportHandler.Uom)
+
+	// konversi UoM global dan per item
+	uomConversion := v1.Group("/uom-conversion")
+	uomConversion.Get("/", perm("uom-conversion:read"), a.UomConversionHandler.Fetch)
+	uomConversion.Post("/", perm("uom-conversion:create"), a.UomConversionHandler.Save)
+	uomConversion.Delete("/", perm("uom-conversion:delete"), a.UomConversionHandler.Delete)
 
 	coa := v1.Group("/coa")
-	coa.Get("/", a.TblCoaHandler.FetchCoa) //get and search co
+	coa.Get("/", perm("coa:read"), a.TblCoaHandler.FetchCoa) //get and search co
 
 	itemCat := v1.Group("/item-category")
-	itemCat.Get("/", a.TblItemCatHandler.FetchItemCategories)
-	itemCat.Post("/", a.TblItemCatHandler.Create)
-	itemCat.Put("/:code", a.TblItemCatHandler.Update)
+	itemCat.Get("/", perm("item-category:read"), a.TblItemCatHandler.FetchItemCategories)
+	itemCat.Post("/", perm("item-category:create"), a.TblItemCatHandler.Create)
+	itemCat.Put("/:code", perm("item-category:update"), a.TblItemCatHandler.Update)
 
 	// Warehouse routes group
 	warehouse := v1.Group("/warehouse")
-	warehouse.Get("/", a.TblWarehouseHandler.FetchWarehouse)         // Get and search warehouses with pagination
-	warehouse.Get("/:search", a.TblWarehouseHandler.DetailWarehouse) // Get detail of a warehouse by warehouse code
-	warehouse.Post("/", a.TblWarehouseHandler.Create)                // Create a new warehouse
-	warehouse.Put("/:code", a.TblWarehouseHandler.Update)            // Update a warehouse by warehouse code
+	warehouse.Get("/", perm("warehouse:read"), a.TblWarehouseHandler.FetchWarehouse)         // Get and search warehouses with pagination
+	warehouse.Get("/:search", perm("warehouse:read"), a.TblWarehouseHandler.DetailWarehouse) // Get detail of a warehouse by warehouse code
+	warehouse.Post("/", perm("warehouse:create"), a.TblWarehouseHandler.Create)                // Create a new warehouse
+	warehouse.Put("/:code", perm("warehouse:update"), a.TblWarehouseHandler.Update)            // Update a warehouse by warehouse code
+	warehouse.Post("/import", perm("warehouse:create"), a.MasterImportHandler.Warehouse)       // Import warehouses from csv/xlsx
 
 	// Warehouse Category routes group
 	warehouseCategory := v1.Group("/warehouse-category")
-	warehouseCategory.Get("/", a.TblWarehouseCategoryHandler.FetchWarehouseCategory)
-	warehouseCategory.Get("/:search", a.TblWarehouseCategoryHandler.DetailWarehouseCategory)
-	warehouseCategory.Post("/", a.TblWarehouseCategoryHandler.Create)
-	warehouseCategory.Put("/:code", a.TblWarehouseCategoryHandler.Update)
+	warehouseCategory.Get("/", perm("warehouse-category:read"), a.TblWarehouseCategoryHandler.FetchWarehouseCategory)
+	warehouseCategory.Get("/:search", perm("warehouse-category:read"), a.TblWarehouseCategoryHandler.DetailWarehouseCategory)
+	warehouseCategory.Post("/", perm("warehouse-category:create"), a.TblWarehouseCategoryHandler.Create)
+	warehouseCategory.Put("/:code", perm("warehouse-category:update"), a.TblWarehouseCategoryHandler.Update)
 
 	item := v1.Group("/item")
-	item.Get("/", a.TblItemHandler.FetchItems)    //get and search items with pagination and all
-	item.Get("/:search", a.TblItemHandler.Detail) //get details from a spesific item
-	item.Post("/", a.TblItemHandler.Create)       //create an item
-	item.Put("/:code", a.TblItemHandler.Update)   //update an item
+	item.Get("/", perm("item:read"), a.TblItemHandler.FetchItems)    //get and search items with pagination and all
+	item.Get("/:search", perm("item:read"), a.TblItemHandler.Detail) //get details from a spesific item
+	item.Post("/", perm("item:create"), a.TblItemHandler.Create)       //create an item
+	item.Put("/:code", perm("item:update"), a.TblItemHandler.Update)   //update an item
+	item.Post("/import", perm("item:create"), a.MasterImportHandler.Item) //import items from csv/xlsx
 
 	currency := v1.Group("/currency")
-	currency.Get("/", a.TblCurrencyHandler.Fetch)       //get and search currency
-	currency.Post("/", a.TblCurrencyHandler.Create)     // create a new currency
-	currency.Put("/:code", a.TblCurrencyHandler.Update) // update a currency
+	currency.Get("/", perm("currency:read"), a.TblCurrencyHandler.Fetch)       //get and search currency
+	currency.Post("/", perm("currency:create"), a.TblCurrencyHandler.Create)     // create a new currency
+	currency.Put("/:code", perm("currency:update"), a.TblCurrencyHandler.Update) // update a currency
+
+	// kurs harian ke base currency
+	exchangeRate := v1.Group("/exchange-rate")
+	exchangeRate.Get("/", perm("exchange-rate:read"), a.ExchangeRateHandler.Fetch)
+	exchangeRate.Post("/", perm("exchange-rate:create"), a.ExchangeRateHandler.Save)
+	exchangeRate.Post("/import", perm("exchange-rate:create"), a.MasterImportHandler.ExchangeRate)
+	exchangeRate.Delete("/", perm("exchange-rate:delete"), a.ExchangeRateHandler.Delete)
 
 	initStock := v1.Group("/initial-stock")
-	initStock.Get("/", a.TblInitStockHandler.Fetch)       // get and search initial stock
-	initStock.Get("/:code", a.TblInitStockHandler.Detail) // get detail initial stock
-	initStock.Post("/", a.TblInitStockHandler.Create)     // Create a new initial stock
-	initStock.Put("/:code", a.TblInitStockHandler.Update) // update a currency
+	initStock.Get("/", perm("initial-stock:read"), a.TblInitStockHandler.Fetch)       // get and search initial stock
+	initStock.Get("/:code", perm("initial-stock:read"), a.TblInitStockHandler.Detail) // get detail initial stock
+	initStock.Post("/", perm("initial-stock:create"), a.TblInitStockHandler.Create)     // Create a new initial stock
+	initStock.Put("/:code", perm("initial-stock:update"), a.TblInitStockHandler.Update) // update a currency
 
 	stockAdjust := v1.Group("/stock-adjustment")
-	stockAdjust.Get("/", a.TblStockAdjustHandler.Fetch)       //  get and search stock adjjustment
-	stockAdjust.Get("/:code", a.TblStockAdjustHandler.Detail) // get a spesific deteail
-	stockAdjust.Post("/", a.TblStockAdjustHandler.Create)     // create a new stock adjustment
+	stockAdjust.Get("/", perm("stock-adjustment:read"), a.TblStockAdjustHandler.Fetch)       //  get and search stock adjjustment
+	stockAdjust.Get("/:code", perm("stock-adjustment:read"), a.TblStockAdjustHandler.Detail) // get a spesific deteail
+	stockAdjust.Post("/", perm("stock-adjustment:create"), a.TblStockAdjustHandler.Create)     // create a new stock adjustment
+
+	// stock opname, gudang dibekukan selama status masih open
+	stockOpname := v1.Group("stock-opname")
+	stockOpname.Get("/", perm("stock-opname:read"), a.StockOpnameHandler.Fetch)
+	stockOpname.Get("/:code", perm("stock-opname:read"), a.StockOpnameHandler.Detail)
+	stockOpname.Post("/", perm("stock-opname:create"), a.StockOpnameHandler.Create)
+	stockOpname.Post("/:code/count", perm("stock-opname:update"), a.StockOpnameHandler.Count)
+	stockOpname.Post("/:code/approve", perm("stock-opname:update"), a.StockOpnameHandler.Approve)
+	stockOpname.Post("/:code/cancel", perm("stock-opname:update"), a.StockOpnameHandler.Cancel)
+
+	// bin / lokasi di dalam gudang
+	bin := v1.Group("bin")
+	bin.Get("/", perm("bin:read"), a.BinHandler.Fetch)
+	bin.Get("/stock", perm("bin:read"), a.BinHandler.Stock)         // saldo per bin
+	bin.Get("/put-away", perm("bin:read"), a.BinHandler.PutAway)    // saran bin untuk dokumen penerimaan
+	bin.Get("/pick-list", perm("bin:read"), a.BinHandler.PickList)  // urutan ambil untuk dokumen pengeluaran
+	bin.Get("/:code", perm("bin:read"), a.BinHandler.Detail)
+	bin.Post("/", perm("bin:create"), a.BinHandler.Create)
+	bin.Put("/:code", perm("bin:update"), a.BinHandler.Update)
+
+	binTransfer := v1.Group("bin-transfer")
+	binTransfer.Get("/", perm("bin-transfer:read"), a.BinHandler.FetchTransfer)
+	binTransfer.Get("/:code", perm("bin-transfer:read"), a.BinHandler.DetailTransfer)
+	binTransfer.Post("/", perm("bin-transfer:create"), a.BinHandler.CreateTransfer)
 
 	// stock summary
 	stockSummary := v1.Group("stock-summary")
-	stockSummary.Get("/", a.TblStockSummaryHandler.Fetch) //get reporting stock summary
+	stockSummary.Get("/", perm("stock-summary:read"), a.TblStockSummaryHandler.Fetch) //get reporting stock summary
+
+	// stock valuation
+	stockValuation := v1.Group("stock-valuation")
+	stockValuation.Get("/", perm("stock-valuation:read"), a.StockValuationHandler.Fetch) // nilai persediaan per tanggal
+
+	// stock card
+	stockCard := v1.Group("stock-card")
+	stockCard.Get("/", perm("stock-card:read"), a.StockCardHandler.Fetch) // saldo awal, mutasi dan saldo berjalan per item
+
+	// master batch (tanggal produksi / kedaluwarsa)
+	batch := v1.Group("batch")
+	batch.Get("/", perm("batch:read"), a.BatchHandler.Fetch)
+	batch.Post("/", perm("batch:create"), a.BatchHandler.Save)
+
+	expiringStock := v1.Group("expiring-stock")
+	expiringStock.Get("/", perm("expiring-stock:read"), a.BatchHandler.Expiring) // stok per batch yang mendekati / lewat kedaluwarsa
+
+	// general ledger journal
+	journal := v1.Group("journal")
+	journal.Get("/", perm("journal:read"), a.JournalHandler.Fetch)
+	journal.Get("/trial-balance", perm("journal:read"), a.JournalHandler.TrialBalance) // neraca saldo per periode
+	journal.Get("/:code", perm("journal:read"), a.JournalHandler.Detail)
+
+	journalAccount := v1.Group("journal-account")
+	journalAccount.Get("/", perm("journal-account:read"), a.JournalHandler.FetchAccount)
+	journalAccount.Post("/", perm("journal-account:create"), a.JournalHandler.SaveAccount) // mapping akun per kategori item / gudang
 
 	getItem := v1.Group("get-item")
-	getItem.Get("/", a.TblStockSummaryHandler.GetItem) // get all item in a warehouse
+	getItem.Get("/", perm("get-item:read"), a.TblStockSummaryHandler.GetItem) // get all item in a warehouse
 
 	// stock mutation
 	stockMutation := v1.Group("stock-mutation")
-	stockMutation.Get("/", a.TblStockMutationHandler.Fetch)
-	stockMutation.Get("/:code", a.TblStockMutationHandler.Detail)
-	stockMutation.Post("/", a.TblStockMutationHandler.Create)
-	stockMutation.Put("/:code", a.TblStockMutationHandler.Update)
+	stockMutation.Get("/", perm("stock-mutation:read"), a.TblStockMutationHandler.Fetch)
+	stockMutation.Get("/:code", perm("stock-mutation:read"), a.TblStockMutationHandler.Detail)
+	stockMutation.Post("/", perm("stock-mutation:create"), a.TblStockMutationHandler.Create)
+	stockMutation.Put("/:code", perm("stock-mutation:update"), a.TblStockMutationHandler.Update)
 
 	// stock movement
 	stockMovement := v1.Group("stock-movement")
-	stockMovement.Get("/", a.TblStockMovement.Fetch) // get stock movement
+	stockMovement.Get("/", perm("stock-movement:read"), a.TblStockMovement.Fetch) // get stock movement
 
 	// tax group
 	taxGroup := v1.Group("/tax-group")
-	taxGroup.Get("/", a.TblTaxGroupHandler.Fetch)
-	taxGroup.Post("/", a.TblTaxGroupHandler.Create)
-	taxGroup.Put("/:code", a.TblTaxGroupHandler.Update)
+	taxGroup.Get("/", perm("tax-group:read"), a.TblTaxGroupHandler.Fetch)
+	taxGroup.Post("/", perm("tax-group:create"), a.TblTaxGroupHandler.Create)
+	taxGroup.Put("/:code", perm("tax-group:update"), a.TblTaxGroupHandler.Update)
 
 	// tax
 	tax := v1.Group("/tax")
-	tax.Get("/", a.TblTaxHandler.Fetch)
-	tax.Post("/", a.TblTaxHandler.Create)
-	tax.Put("/:code", a.TblTaxHandler.Update)
+	tax.Get("/", perm("tax:read"), a.TblTaxHandler.Fetch)
+	tax.Post("/", perm("tax:create"), a.TblTaxHandler.Create)
+	tax.Put("/:code", perm("tax:update"), a.TblTaxHandler.Update)
+	tax.Post("/calculate", a.TaxEngineHandler.Calculate)
+
+	// e-Faktur keluaran dari direct sales delivery
+	eFaktur := v1.Group("/e-faktur")
+	eFaktur.Get("/range", perm("e-faktur:read"), a.EfakturHandler.FetchRange)
+	eFaktur.Post("/range", perm("e-faktur:create"), a.EfakturHandler.CreateRange)
+	eFaktur.Post("/export", perm("e-faktur:export"), a.EfakturHandler.Export)
+	eFaktur.Get("/export/:batch", perm("e-faktur:export"), a.EfakturHandler.Reexport)
 
 	// customer category
 	customerCategory := v1.Group("/customer-category")
-	customerCategory.Get("/", a.TblCustomerCategoryHandler.Fetch)
-	customerCategory.Post("/", a.TblCustomerCategoryHandler.Create)
-	customerCategory.Put("/:code", a.TblCustomerCategoryHandler.Update)
+	customerCategory.Get("/", perm("customer-category:read"), a.TblCustomerCategoryHandler.Fetch)
+	customerCategory.Post("/", perm("customer-category:create"), a.TblCustomerCategoryHandler.Create)
+	customerCategory.Put("/:code", perm("customer-category:update"), a.TblCustomerCategoryHandler.Update)
 
 	// site
 	site := v1.Group("/site")
-	site.Get("/", a.TblSiteHandler.Fetch)
-	site.Post("/", a.TblSiteHandler.Create)
-	site.Put("/:code", a.TblSiteHandler.Update)
+	site.Get("/", perm("site:read"), a.TblSiteHandler.Fetch)
+	site.Post("/", perm("site:create"), a.TblSiteHandler.Create)
+	site.Put("/:code", perm("site:update"), a.TblSiteHandler.Update)
 
 	// vendor category
 	vendorCat := v1.Group("/vendor-category")
-	vendorCat.Get("/", a.TblVendorCategoryHandler.Fetch)
-	vendorCat.Post("/", a.TblVendorCategoryHandler.Create)
-	vendorCat.Put("/:code", a.TblVendorCategoryHandler.Update)
+	vendorCat.Get("/", perm("vendor-category:read"), a.TblVendorCategoryHandler.Fetch)
+	vendorCat.Post("/", perm("vendor-category:create"), a.TblVendorCategoryHandler.Create)
+	vendorCat.Put("/:code", perm("vendor-category:update"), a.TblVendorCategoryHandler.Update)
 
 	// vendor rating
 	vendorRating := v1.Group("/vendor-rating")
-	vendorRating.Get("/", a.TblVendorRatingHandler.Fetch)
-	vendorRating.Post("/", a.TblVendorRatingHandler.Create)
-	vendorRating.Put("/:code", a.TblVendorRatingHandler.Update)
+	vendorRating.Get("/", perm("vendor-rating:read"), a.TblVendorRatingHandler.Fetch)
+	vendorRating.Post("/", perm("vendor-rating:create"), a.TblVendorRatingHandler.Create)
+	vendorRating.Put("/:code", perm("vendor-rating:update"), a.TblVendorRatingHandler.Update)
 
 	// vendor sector
 	vendorSector := v1.Group("/vendor-sector")
-	vendorSector.Get("/", a.TblVendorSectorHandler.Fetch)
-	vendorSector.Post("/", a.TblVendorSectorHandler.Create)
-	vendorSector.Put("/:code", a.TblVendorSectorHandler.Update)
+	vendorSector.Get("/", perm("vendor-sector:read"), a.TblVendorSectorHandler.Fetch)
+	vendorSector.Post("/", perm("vendor-sector:create"), a.TblVendorSectorHandler.Create)
+	vendorSector.Put("/:code", perm("vendor-sector:update"), a.TblVendorSectorHandler.Update)
 
 	getSector := vendorSector.Group("/get-sector")
-	getSector.Get("/", a.TblVendorSectorHandler.GetSector)
-	getSector.Get("/:code", a.TblVendorSectorHandler.GetSubSector)
+	getSector.Get("/", perm("vendor-sector:read"), a.TblVendorSectorHandler.GetSector)
+	getSector.Get("/:code", perm("vendor-sector:read"), a.TblVendorSectorHandler.GetSubSector)
 
 	// master vendor
 	vendor := v1.Group("/master-vendor")
-	vendor.Get("/", a.TblVendorHandler.Fetch)
-	vendor.Get("/:code", a.TblVendorHandler.Detail)
-	vendor.Post("/", a.TblVendorHandler.Create)
-	vendor.Put("/:code", a.TblVendorHandler.Update)
+	vendor.Get("/", perm("master-vendor:read"), a.TblVendorHandler.Fetch)
+	vendor.Get("/:code", perm("master-vendor:read"), a.TblVendorHandler.Detail)
+	vendor.Post("/", perm("master-vendor:create"), a.TblVendorHandler.Create)
+	vendor.Put("/:code", perm("master-vendor:update"), a.TblVendorHandler.Update)
+	vendor.Post("/import", perm("master-vendor:create"), a.MasterImportHandler.Vendor)
 
 	// master vendor
 	contactVendor := v1.Group("/contact-vendor")
-	contactVendor.Get("/", a.TblVendorHandler.GetContact)
+	contactVendor.Get("/", perm("contact-vendor:read"), a.TblVendorHandler.GetContact)
 
 	// history of stock
 	historyOfStock := v1.Group("/history-of-stock")
-	historyOfStock.Get("/", a.TblHistoryOfStockHandler.Fetch)
+	historyOfStock.Get("/", perm("history-of-stock:read"), a.TblHistoryOfStockHandler.Fetch)
 
 	// daily stock movement
 	dailyStockMovement := v1.Group("/daily-stock-movement")
-	dailyStockMovement.Get("/", a.TblDailyStockMovementHandler.Fetch)
+	dailyStockMovement.Get("/", perm("daily-stock-movement:read"), a.TblDailyStockMovementHandler.Fetch)
 
 	// direct purchase receive
 	directPurchaseRcv := v1.Group("/direct-purchase-receive")
-	directPurchaseRcv.Get("/", a.TblDirectPurchaseRcvHandler.Fetch)
-	directPurchaseRcv.Post("/", a.TblDirectPurchaseRcvHandler.Create)
-	directPurchaseRcv.Put("/:code", a.TblDirectPurchaseRcvHandler.Update)
+	directPurchaseRcv.Get("/", perm("direct-purchase-receive:read"), a.TblDirectPurchaseRcvHandler.Fetch)
+	directPurchaseRcv.Post("/", perm("direct-purchase-receive:create"), a.TblDirectPurchaseRcvHandler.Create)
+	directPurchaseRcv.Put("/:code", perm("direct-purchase-receive:update"), a.TblDirectPurchaseRcvHandler.Update)
+
+	// master customer
+	masterCustomer := v1.Group("/master-customer")
+	masterCustomer.Get("/", perm("master-customer:read"), a.TblCustomerHandler.Fetch)
+	masterCustomer.Get("/:code", perm("master-customer:read"), a.TblCustomerHandler.Detail)
+	masterCustomer.Post("/", perm("master-customer:create"), a.TblCustomerHandler.Create)
+	masterCustomer.Put("/:code", perm("master-customer:update"), a.TblCustomerHandler.Update)
+
+	// sales order
+	salesOrder := v1.Group("/sales-order")
+	salesOrder.Get("/", perm("sales-order:read"), a.TblSalesOrderHandler.Fetch)
+	salesOrder.Post("/", perm("sales-order:create"), a.TblSalesOrderHandler.Create)
+	salesOrder.Put("/:code", perm("sales-order:update"), a.TblSalesOrderHandler.Update)
+
+	// outstanding sales order
+	outstandingSalesOrder := v1.Group("/outstanding-sales-order")
+	outstandingSalesOrder.Get("/", perm("outstanding-sales-order:read"), a.TblSalesOrderHandler.Outstanding)
 
 	// direct sales delivery
 	directSalesDelivery := v1.Group("/direct-sales-delivery")
-	directSalesDelivery.Get("/", a.TblDirectSalesDeliveryHandler.Fetch)
-	directSalesDelivery.Post("/", a.TblDirectSalesDeliveryHandler.Create)
-	directSalesDelivery.Put("/:code", a.TblDirectSalesDeliveryHandler.Update)
+	directSalesDelivery.Get("/", perm("direct-sales-delivery:read"), a.TblDirectSalesDeliveryHandler.Fetch)
+	directSalesDelivery.Post("/", perm("direct-sales-delivery:create"), a.TblDirectSalesDeliveryHandler.Create)
+	directSalesDelivery.Put("/:code", perm("direct-sales-delivery:update"), a.TblDirectSalesDeliveryHandler.Update)
+	directSalesDelivery.Get("/:code/print", perm("direct-sales-delivery:read"), a.PrintoutHandler.DirectSalesDelivery)
 
 	// material transfer
 	materialTransfer := v1.Group("/material-transfer")
-	materialTransfer.Get("/", a.TblMaterialTransferHandler.Fetch)
-	materialTransfer.Post("/", a.TblMaterialTransferHandler.Create)
-	materialTransfer.Put("/:code", a.TblMaterialTransferHandler.Update)
+	materialTransfer.Get("/", perm("material-transfer:read"), a.TblMaterialTransferHandler.Fetch)
+	materialTransfer.Post("/", perm("material-transfer:create"), a.TblMaterialTransferHandler.Create)
+	materialTransfer.Put("/:code", perm("material-transfer:update"), a.TblMaterialTransferHandler.Update)
+	materialTransfer.Get("/:code/print", perm("material-transfer:read"), a.PrintoutHandler.MaterialTransfer)
 
 	// material receive
 	materialReceive := v1.Group("/material-receive")
-	materialReceive.Get("/", a.TblMaterialReceiveHandler.Fetch)
-	materialReceive.Post("/", a.TblMaterialReceiveHandler.Create)
+	materialReceive.Get("/", perm("material-receive:read"), a.TblMaterialReceiveHandler.Fetch)
+	materialReceive.Post("/", perm("material-receive:create"), a.TblMaterialReceiveHandler.Create)
+
+	// barang material transfer yang masih di jalan dan discrepancy penerimaannya
+	inTransit := v1.Group("/in-transit")
+	inTransit.Get("/", perm("in-transit:read"), a.InTransitHandler.Report)
+	inTransit.Get("/discrepancy", perm("in-transit:read"), a.InTransitHandler.FetchDiscrepancy)
+	inTransit.Post("/discrepancy/resolve", perm("material-transfer-discrepancy:resolve"), a.InTransitHandler.Resolve)
 
 	// Get Material
 	getMaterial := v1.Group("/get-material-transfer")
-	getMaterial.Get("/", a.TblTransferItemBetweenWhsHandler.GetMaterial)
+	getMaterial.Get("/", perm("get-material-transfer:read"), a.TblTransferItemBetweenWhsHandler.GetMaterial)
 
 	// direct material receive
 	directMaterialReceive := v1.Group("/direct-material-receive")
-	directMaterialReceive.Get("/", a.TblDirectMaterialReceiveHandler.Fetch)
-	directMaterialReceive.Post("/", a.TblDirectMaterialReceiveHandler.Create)
-	directMaterialReceive.Put("/:code", a.TblDirectMaterialReceiveHandler.Update)
+	directMaterialReceive.Get("/", perm("direct-material-receive:read"), a.TblDirectMaterialReceiveHandler.Fetch)
+	directMaterialReceive.Post("/", perm("direct-material-receive:create"), a.TblDirectMaterialReceiveHandler.Create)
+	directMaterialReceive.Put("/:code", perm("direct-material-receive:update"), a.TblDirectMaterialReceiveHandler.Update)
 
 	// material request
 	materialRequest := v1.Group("/material-request")
-	materialRequest.Get("/", a.TblMaterialRequestHandler.Fetch)
-	materialRequest.Post("/", a.TblMaterialRequestHandler.Create)
-	materialRequest.Put("/:code", a.TblMaterialRequestHandler.Update)
+	materialRequest.Get("/", perm("material-request:read"), a.TblMaterialRequestHandler.Fetch)
+	materialRequest.Post("/", perm("material-request:create"), a.TblMaterialRequestHandler.Create)
+	materialRequest.Put("/:code", perm("material-request:update"), a.TblMaterialRequestHandler.Update)
 
 	// vendor quotation
 	vendorQuotation := v1.Group("/vendor-quotation")
-	vendorQuotation.Get("/", a.TblVendorQuotationHandler.Fetch)
-	vendorQuotation.Post("/", a.TblVendorQuotationHandler.Create)
-	vendorQuotation.Put("/:code", a.TblVendorQuotationHandler.Update)
+	vendorQuotation.Get("/", perm("vendor-quotation:read"), a.TblVendorQuotationHandler.Fetch)
+	vendorQuotation.Post("/", perm("vendor-quotation:create"), a.TblVendorQuotationHandler.Create)
+	vendorQuotation.Put("/:code", perm("vendor-quotation:update"), a.TblVendorQuotationHandler.Update)
 
 	// purchase order request
 	purchaseOrderRequest := v1.Group("/purchase-order-request")
-	purchaseOrderRequest.Get("/", a.TblPurchaseOrderRequestHandler.Fetch)
-	purchaseOrderRequest.Post("/", a.TblPurchaseOrderRequestHandler.Create)
-	purchaseOrderRequest.Put("/:code", a.TblPurchaseOrderRequestHandler.Update)
+	purchaseOrderRequest.Get("/", perm("purchase-order-request:read"), a.TblPurchaseOrderRequestHandler.Fetch)
+	purchaseOrderRequest.Post("/", perm("purchase-order-request:create"), a.TblPurchaseOrderRequestHandler.Create)
+	purchaseOrderRequest.Put("/:code", perm("purchase-order-request:update"), a.TblPurchaseOrderRequestHandler.Update)
 
 	// get material request
 	getMaterialRequest := v1.Group("/get-material-request")
-	getMaterialRequest.Get("/", a.TblMaterialRequestHandler.GetMaterialRequest)
+	getMaterialRequest.Get("/", perm("get-material-request:read"), a.TblMaterialRequestHandler.GetMaterialRequest)
 
 	// get vendor quotation
 	getVendorQuotation := v1.Group("/get-vendor-quotation")
-	getVendorQuotation.Get("/", a.TblVendorQuotationHandler.GetVendorQuotation)
+	getVendorQuotation.Get("/", perm("get-vendor-quotation:read"), a.TblVendorQuotationHandler.GetVendorQuotation)
+
+	// perbandingan vendor quotation
+	quotationComparison := v1.Group("/vendor-quotation-comparison")
+	quotationComparison.Get("/", perm("vendor-quotation-comparison:read"), a.QuotationComparisonHandler.Compare)
+	quotationComparison.Post("/select", perm("vendor-quotation-comparison:select"), a.QuotationComparisonHandler.Select)
 
 	// get purchase order req
 	getPurchaseOrderRequest := v1.Group("/get-purchase-order-request")
-	getPurchaseOrderRequest.Get("/", a.TblPurchaseOrderRequestHandler.GetPurchaseOrderRequest)
+	getPurchaseOrderRequest.Get("/", perm("get-purchase-order-request:read"), a.TblPurchaseOrderRequestHandler.GetPurchaseOrderRequest)
 
 	// purchase order
 	purchaseOrder := v1.Group("/purchase-order")
-	purchaseOrder.Get("/", a.TblPurchaseOrderHandler.Fetch)
-	purchaseOrder.Post("/", a.TblPurchaseOrderHandler.Create)
-	purchaseOrder.Put("/:code", a.TblPurchaseOrderHandler.Update)
+	purchaseOrder.Get("/", perm("purchase-order:read"), a.TblPurchaseOrderHandler.Fetch)
+	purchaseOrder.Post("/", perm("purchase-order:create"), a.TblPurchaseOrderHandler.Create)
+	purchaseOrder.Put("/:code", perm("purchase-order:update"), a.TblPurchaseOrderHandler.Update)
+	purchaseOrder.Get("/:code/print", perm("purchase-order:read"), a.PrintoutHandler.PurchaseOrder)
 
 	// purchase material receive
 	purchaseMaterialReceive := v1.Group("/purchase-material-receive")
-	purchaseMaterialReceive.Get("/", a.TblPurchaseMaterialReceiveHandler.Fetch)
-	purchaseMaterialReceive.Post("/", a.TblPurchaseMaterialReceiveHandler.Create)
-	purchaseMaterialReceive.Put("/:code", a.TblPurchaseMaterialReceiveHandler.Update)
+	purchaseMaterialReceive.Get("/", perm("purchase-material-receive:read"), a.TblPurchaseMaterialReceiveHandler.Fetch)
+	purchaseMaterialReceive.Post("/", perm("purchase-material-receive:create"), a.TblPurchaseMaterialReceiveHandler.Create)
+	purchaseMaterialReceive.Put("/:code", perm("purchase-material-receive:update"), a.TblPurchaseMaterialReceiveHandler.Update)
+	purchaseMaterialReceive.Get("/:code/print", perm("purchase-material-receive:read"), a.PrintoutHandler.PurchaseMaterialReceive)
+
+	// invoice vendor dengan three-way match PO / penerimaan / invoice
+	vendorInvoice := v1.Group("/vendor-invoice")
+	vendorInvoice.Get("/", perm("vendor-invoice:read"), a.VendorInvoiceHandler.Fetch)
+	vendorInvoice.Get("/payables", perm("vendor-invoice:read"), a.VendorInvoiceHandler.Payables)
+	vendorInvoice.Get("/:code", perm("vendor-invoice:read"), a.VendorInvoiceHandler.Detail)
+	vendorInvoice.Post("/", perm("vendor-invoice:create"), a.VendorInvoiceHandler.Create)
 
 	// get purchase order req
 	getPurchaseOrder := v1.Group("/get-purchase-order")
-	getPurchaseOrder.Get("/", a.TblPurchaseOrderHandler.GetPurchaseOrder)
+	getPurchaseOrder.Get("/", perm("get-purchase-order:read"), a.TblPurchaseOrderHandler.GetPurchaseOrder)
 
 	// purchase material receive
 	purchaseReturnDelivery := v1.Group("/purchase-return-delivery")
-	purchaseReturnDelivery.Get("/", a.TblPurchaseReturnDeliveryHandler.Fetch)
-	purchaseReturnDelivery.Post("/", a.TblPurchaseReturnDeliveryHandler.Create)
-	purchaseReturnDelivery.Put("/:code", a.TblPurchaseReturnDeliveryHandler.Update)
+	purchaseReturnDelivery.Get("/", perm("purchase-return-delivery:read"), a.TblPurchaseReturnDeliveryHandler.Fetch)
+	purchaseReturnDelivery.Post("/", perm("purchase-return-delivery:create"), a.TblPurchaseReturnDeliveryHandler.Create)
+	purchaseReturnDelivery.Put("/:code", perm("purchase-return-delivery:update"), a.TblPurchaseReturnDeliveryHandler.Update)
 
 	// get return material
 	getReturnMaterial := v1.Group("/get-return-material")
-	getReturnMaterial.Get("/", a.TblPurchaseReturnDeliveryHandler.GetReturnMaterial)
+	getReturnMaterial.Get("/", perm("get-return-material:read"), a.TblPurchaseReturnDeliveryHandler.GetReturnMaterial)
 
 	// transfer between warehouse
 	transferBetweehWhs := v1.Group("/transfer-between-warehouse")
-	transferBetweehWhs.Get("/", a.TblTransferItemBetweenWhsHandler.Fetch)
+	transferBetweehWhs.Get("/", perm("transfer-between-warehouse:read"), a.TblTransferItemBetweenWhsHandler.Fetch)
 
 	// purchase material receive
 	purchaseMaterialReceiveReport := v1.Group("/purchase-material-receive-report")
-	purchaseMaterialReceiveReport.Get("/", a.TblPurchaseMaterialReceiveHandler.Reporting)
+	purchaseMaterialReceiveReport.Get("/", perm("purchase-material-receive-report:read"), a.TblPurchaseMaterialReceiveHandler.Reporting)
 
 	// Outstanding Material Request
 	outstandingMaterialReq := v1.Group("/outstanding-material-request")
-	outstandingMaterialReq.Get("/", a.TblMaterialRequestHandler.OutstandingMaterial)
+	outstandingMaterialReq.Get("/", perm("outstanding-material-request:read"), a.TblMaterialRequestHandler.OutstandingMaterial)
+
+	// reorder point / min-max dan usulan material request
+	replenishment := v1.Group("/replenishment")
+	replenishment.Get("/", perm("replenishment:read"), a.ReplenishmentHandler.Proposals)
+	replenishment.Get("/setting", perm("replenishment:read"), a.ReplenishmentHandler.FetchSetting)
+	replenishment.Post("/setting", perm("replenishment:update"), a.ReplenishmentHandler.SaveSetting)
+	replenishment.Post("/material-request", perm("material-request:create"), a.ReplenishmentHandler.CreateRequest)
 
 	// Outstanding Purchase Order
 	outstandingPurchaseOrder := v1.Group("/outstanding-purchase-order")
-	outstandingPurchaseOrder.Get("/", a.TblPurchaseOrderHandler.OutstandingPO)
+	outstandingPurchaseOrder.Get("/", perm("outstanding-purchase-order:read"), a.TblPurchaseOrderHandler.OutstandingPO)
 
 	// Order Report by Vendor
 	ourderReportByVendor := v1.Group("/order-report-by-vendor")
-	ourderReportByVendor.Get("/", a.TblOrderReportHandler.ByVendor)
+	ourderReportByVendor.Get("/", perm("order-report-by-vendor:read"), a.TblOrderReportHandler.ByVendor)
 
 	// dashboard
 	dashboard := v1.Group("/dashboard")
-	dashboard.Get("/", a.DashboardHandler.Fetch)
+	dashboard.Get("/", perm("dashboard:read"), a.DashboardHandler.Fetch)
+
+	// user group & permission
+	userGroup := v1.Group("/user-group")
+	userGroup.Get("/", perm("user-group:read"), a.TblUserGroupHandler.Fetch)
+	userGroup.Get("/:code", perm("user-group:read"), a.TblUserGroupHandler.Detail)
+	userGroup.Post("/", perm("user-group:create"), a.TblUserGroupHandler.Create)
+	userGroup.Put("/:code", perm("user-group:update"), a.TblUserGroupHandler.Update)
+	userGroup.Put("/:code/users", perm("user-group:update"), a.TblUserGroupHandler.AssignUser)
+
+	// gudang & site per user
+	userScope := v1.Group("/user-scope")
+	userScope.Get("/:code", perm("user-scope:read"), a.TblUserScopeHandler.Detail)
+	userScope.Put("/:code", perm("user-scope:update"), a.TblUserScopeHandler.Update)
+
+	// approval rule & proses approval dokumen
+	approvalRule := v1.Group("/approval-rule")
+	approvalRule.Get("/", perm("approval-rule:read"), a.DocApprovalHandler.FetchRule)
+	approvalRule.Get("/:code", perm("approval-rule:read"), a.DocApprovalHandler.DetailRule)
+	approvalRule.Post("/", perm("approval-rule:create"), a.DocApprovalHandler.CreateRule)
+	approvalRule.Put("/:code", perm("approval-rule:update"), a.DocApprovalHandler.UpdateRule)
+
+	approval := v1.Group("/approval")
+	approval.Get("/inbox", perm("approval:read"), a.DocApprovalHandler.Inbox)
+	approval.Get("/:type/:code", perm("approval:read"), a.DocApprovalHandler.Detail)
+	approval.Post("/:type/:code/approve", perm("approval:update"), a.DocApprovalHandler.Approve)
+	approval.Post("/:type/:code/reject", perm("approval:update"), a.DocApprovalHandler.Reject)
+	approval.Post("/:type/:code/return", perm("approval:update"), a.DocApprovalHandler.Return)
+	approval.Post("/:type/:code/resubmit", perm("approval:create"), a.DocApprovalHandler.Resubmit)
 
 	return nil
 }
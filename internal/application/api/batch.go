@@ -0,0 +1,117 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"gitlab.com/ayaka/internal/application/service"
+	"gitlab.com/ayaka/internal/domain/batch"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/custommiddleware"
+	"gitlab.com/ayaka/internal/pkg/export"
+	"gitlab.com/ayaka/internal/pkg/formatter"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+	"gitlab.com/ayaka/internal/pkg/validator"
+)
+
+type BatchApi interface {
+	Fetch(c *fiber.Ctx) error
+	Save(c *fiber.Ctx) error
+	Expiring(c *fiber.Ctx) error
+}
+
+type BatchHandler struct {
+	Service   service.BatchService                 `inject:"batchService"`
+	Validator validator.Validator                  `inject:"validator"`
+	Log       *custommiddleware.LogActivityHandler `inject:"logActivity"`
+}
+
+func (h *BatchHandler) Fetch(c *fiber.Ctx) error {
+	itemCode := c.Query("item_code", "")
+	batchNo := c.Query("batch", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	param, err := pageParam(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input batch")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	result, err := h.Service.Fetch(c.Context(), itemCode, batchNo, param)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error fetch batch: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all batch")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *BatchHandler) Save(c *fiber.Ctx) error {
+	var req *batch.Save
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse batch: %s", err.Error()))
+		return err
+	}
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate batch: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to save batch", err.Error()))
+	}
+
+	result, err := h.Service.Save(c.Context(), req, user.UserName)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrInvalidInput) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid date input batch")
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Invalid manufacturing or expired date", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error save batch: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to save batch", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Save batch %s %s", result.ItemCode, result.Batch))
+
+	return c.Status(fiber.StatusCreated).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+// Expiring laporan batch yang akan / sudah kedaluwarsa, horizons dalam hari
+// dipisah koma (default 30,60,90), uom opsional
+func (h *BatchHandler) Expiring(c *fiber.Ctx) error {
+	warehouse := c.Query("warehouse", "")
+	horizons := c.Query("horizons", "")
+	uom := c.Query("uom", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	format, err := export.Requested(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid export format expiring stock")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	result, err := h.Service.Expiring(c.Context(), warehouse, horizons, uom)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrUomConversion) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Expiring stock uom: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		}
+		if errors.Is(err, customerrors.ErrInvalidInput) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid horizons expiring stock")
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Horizons must be positive numbers of days", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error expiring stock: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	if format != "" {
+		go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Export %s expiring stock", format))
+		return export.Send(c, format, "expiring-stock", result.Lines)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch expiring stock")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
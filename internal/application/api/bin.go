@@ -0,0 +1,248 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gitlab.com/ayaka/internal/application/service"
+	"gitlab.com/ayaka/internal/domain/bin"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/custommiddleware"
+	"gitlab.com/ayaka/internal/pkg/formatter"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+	"gitlab.com/ayaka/internal/pkg/validator"
+)
+
+type BinApi interface {
+	Fetch(c *fiber.Ctx) error
+	Detail(c *fiber.Ctx) error
+	Create(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	Stock(c *fiber.Ctx) error
+	PutAway(c *fiber.Ctx) error
+	PickList(c *fiber.Ctx) error
+	FetchTransfer(c *fiber.Ctx) error
+	DetailTransfer(c *fiber.Ctx) error
+	CreateTransfer(c *fiber.Ctx) error
+}
+
+type BinHandler struct {
+	Service   service.BinService                   `inject:"binService"`
+	Validator validator.Validator                  `inject:"validator"`
+	Log       *custommiddleware.LogActivityHandler `inject:"logActivity"`
+}
+
+func (h *BinHandler) Fetch(c *fiber.Ctx) error {
+	warehouse := c.Query("warehouse", "")
+	search := c.Query("search", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	param, err := pageParam(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input bin")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	result, err := h.Service.Fetch(c.Context(), warehouse, search, param)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error fetch bin: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all bin")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *BinHandler) Detail(c *fiber.Ctx) error {
+	code := c.Params("code")
+	user := c.Locals("user").(*jwt.Claims)
+
+	result, err := h.Service.Detail(c.Context(), code)
+	if err != nil {
+		return h.failed(c, user, "detail bin", err)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Get detail bin %s", code))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *BinHandler) Create(c *fiber.Ctx) error {
+	var req *bin.Create
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse bin: %s", err.Error()))
+		return err
+	}
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate bin: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to create bin", err.Error()))
+	}
+
+	result, err := h.Service.Create(c.Context(), req, user.UserName)
+	if err != nil {
+		return h.failed(c, user, "create bin", err)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Create bin %s", result.BinCode))
+
+	return c.Status(fiber.StatusCreated).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *BinHandler) Update(c *fiber.Ctx) error {
+	var req *bin.Update
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse bin: %s", err.Error()))
+		return err
+	}
+	req.BinCode = c.Params("code")
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate bin: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to update bin", err.Error()))
+	}
+
+	result, err := h.Service.Update(c.Context(), req, user.UserName)
+	if err != nil {
+		return h.failed(c, user, "update bin", err)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Update bin %s", req.BinCode))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+// Stock menampilkan saldo per bin, filter warehouse / bin / item_code
+func (h *BinHandler) Stock(c *fiber.Ctx) error {
+	user := c.Locals("user").(*jwt.Claims)
+
+	result, err := h.Service.Stock(c.Context(), c.Query("warehouse", ""), c.Query("bin", ""), c.Query("item_code", ""))
+	if err != nil {
+		return h.failed(c, user, "fetch bin stock", err)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch bin stock")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+// PutAway menyarankan bin untuk dokumen penerimaan
+// (document_type purchase-material-receive / material-receive)
+func (h *BinHandler) PutAway(c *fiber.Ctx) error {
+	docType := c.Query("document_type", "")
+	docNo := c.Query("document_number", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	result, err := h.Service.PutAway(c.Context(), docType, docNo)
+	if err != nil {
+		return h.failed(c, user, "put-away", err)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Get put-away %s %s", docType, docNo))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+// PickList menyusun pick list dokumen pengeluaran
+// (document_type direct-sales-delivery / material-transfer)
+func (h *BinHandler) PickList(c *fiber.Ctx) error {
+	docType := c.Query("document_type", "")
+	docNo := c.Query("document_number", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	result, err := h.Service.PickList(c.Context(), docType, docNo)
+	if err != nil {
+		return h.failed(c, user, "pick list", err)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Get pick list %s %s", docType, docNo))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *BinHandler) FetchTransfer(c *fiber.Ctx) error {
+	docNo := c.Query("document_number", "")
+	warehouse := c.Query("warehouse", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	param, err := pageParam(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input bin transfer")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	result, err := h.Service.FetchTransfer(c.Context(), docNo, warehouse, param)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error fetch bin transfer: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all bin transfer")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *BinHandler) DetailTransfer(c *fiber.Ctx) error {
+	docNo := strings.ReplaceAll(c.Params("code"), "-", "/")
+	user := c.Locals("user").(*jwt.Claims)
+
+	result, err := h.Service.DetailTransfer(c.Context(), docNo)
+	if err != nil {
+		return h.failed(c, user, "detail bin transfer", err)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Get detail bin transfer %s", docNo))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *BinHandler) CreateTransfer(c *fiber.Ctx) error {
+	var req *bin.Transfer
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse bin transfer: %s", err.Error()))
+		return err
+	}
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate bin transfer: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to create bin transfer", err.Error()))
+	}
+
+	result, err := h.Service.CreateTransfer(c.Context(), req, user.UserName)
+	if err != nil {
+		return h.failed(c, user, "create bin transfer", err)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Create bin transfer %s", result.DocNo))
+
+	return c.Status(fiber.StatusCreated).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *BinHandler) failed(c *fiber.Ctx, user *jwt.Claims, action string, err error) error {
+	switch {
+	case errors.Is(err, customerrors.ErrDataNotFound):
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Data not found on %s", action))
+		return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "Data not found", ""))
+	case errors.Is(err, customerrors.ErrWarehouseNotAllowed):
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Forbidden %s: %s", action, err.Error()))
+		return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have access to this warehouse", ""))
+	case errors.Is(err, customerrors.ErrWarehouseFrozen):
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Failed %s: %s", action, err.Error()))
+		return c.Status(fiber.StatusConflict).JSON(formatter.NewErrorResponse(formatter.DataConflict, err.Error(), ""))
+	case errors.Is(err, customerrors.ErrInvalidInput), errors.Is(err, customerrors.ErrInsufficientStock):
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Failed %s: %s", action, err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error %s: %s", action, err.Error()))
+	return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, fmt.Sprintf("Failed to %s", action), ""))
+}
@@ -0,0 +1,307 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gitlab.com/ayaka/internal/application/service"
+	"gitlab.com/ayaka/internal/domain/docapproval"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/custommiddleware"
+	"gitlab.com/ayaka/internal/pkg/formatter"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+	"gitlab.com/ayaka/internal/pkg/validator"
+)
+
+type DocApprovalApi interface {
+	Detail(c *fiber.Ctx) error
+	Approve(c *fiber.Ctx) error
+	Reject(c *fiber.Ctx) error
+	Return(c *fiber.Ctx) error
+	Resubmit(c *fiber.Ctx) error
+	Inbox(c *fiber.Ctx) error
+	FetchRule(c *fiber.Ctx) error
+	DetailRule(c *fiber.Ctx) error
+	CreateRule(c *fiber.Ctx) error
+	UpdateRule(c *fiber.Ctx) error
+}
+
+type DocApprovalHandler struct {
+	Service   service.DocApprovalService           `inject:"docApprovalService"`
+	Validator validator.Validator                  `inject:"validator"`
+	Log       *custommiddleware.LogActivityHandler `inject:"logActivity"`
+}
+
+// :type pada url mengikuti nama menu dokumen, sekaligus dipakai untuk cek permission
+var approvalDocType = map[string]string{
+	"material-request":       docapproval.MaterialRequest,
+	"purchase-order-request": docapproval.PurchaseOrderRequest,
+	"purchase-order":         docapproval.PurchaseOrder,
+}
+
+func (h *DocApprovalHandler) Detail(c *fiber.Ctx) error {
+	user := c.Locals("user").(*jwt.Claims)
+	menu := c.Params("type")
+	code := c.Params("code")
+
+	docType, ok := approvalDocType[menu]
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "Document type not found", ""))
+	}
+	if !user.HasPermission(menu + ":read") {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Forbidden access %s:read", menu))
+		return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have permission to access this resource", ""))
+	}
+
+	result, err := h.Service.Detail(c.Context(), docType, code)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrDataNotFound) {
+			go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Detail approval %s not found", code))
+			return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "Document not found", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error detail approval: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Detail approval %s", code))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *DocApprovalHandler) Approve(c *fiber.Ctx) error {
+	return h.act(c, docapproval.Approved)
+}
+
+func (h *DocApprovalHandler) Reject(c *fiber.Ctx) error {
+	return h.act(c, docapproval.Rejected)
+}
+
+func (h *DocApprovalHandler) Return(c *fiber.Ctx) error {
+	return h.act(c, docapproval.Returned)
+}
+
+// act tidak memakai permission menu, yang boleh bertindak hanya approver
+// pada level yang sedang berjalan.
+func (h *DocApprovalHandler) act(c *fiber.Ctx, status string) error {
+	req := &docapproval.Act{}
+	user := c.Locals("user").(*jwt.Claims)
+	code := c.Params("code")
+
+	docType, ok := approvalDocType[c.Params("type")]
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "Document type not found", ""))
+	}
+
+	// approve boleh tanpa body
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(req); err != nil {
+			go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse approval: %s", err.Error()))
+			return err
+		}
+	}
+	req.DocType = docType
+	req.DocNo = code
+	req.Status = status
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate approval: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to process approval", err.Error()))
+	}
+
+	// reject dan return wajib menyertakan alasan
+	if status != docapproval.Approved && strings.TrimSpace(req.Remark) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Comment is required", ""))
+	}
+
+	result, err := h.Service.Act(c.Context(), req, user.UserCode)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrDataNotFound):
+			go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Approval %s not found", code))
+			return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "Document not found", ""))
+		case errors.Is(err, customerrors.ErrNotApprover):
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Not approver of %s", code))
+			return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You are not the current approver of this document", ""))
+		case errors.Is(err, customerrors.ErrInvalidApprovalStatus):
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Approval %s: %s", code, err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Document is not waiting for approval", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error approval: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to process approval", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("%s %s %s", status, docType, code))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *DocApprovalHandler) Resubmit(c *fiber.Ctx) error {
+	user := c.Locals("user").(*jwt.Claims)
+	menu := c.Params("type")
+	code := c.Params("code")
+
+	docType, ok := approvalDocType[menu]
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "Document type not found", ""))
+	}
+	if !user.HasPermission(menu + ":update") {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Forbidden access %s:update", menu))
+		return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have permission to access this resource", ""))
+	}
+
+	result, err := h.Service.Resubmit(c.Context(), docType, code, user.UserCode)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrDataNotFound):
+			go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Resubmit %s not found", code))
+			return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "Document not found", ""))
+		case errors.Is(err, customerrors.ErrInvalidApprovalStatus):
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Resubmit %s: %s", code, err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Only returned document can be resubmitted", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error resubmit approval: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to resubmit document", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Resubmit %s %s", docType, code))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *DocApprovalHandler) Inbox(c *fiber.Ctx) error {
+	user := c.Locals("user").(*jwt.Claims)
+
+	result, err := h.Service.Inbox(c.Context(), user.UserCode)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error approval inbox: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch approval inbox")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *DocApprovalHandler) FetchRule(c *fiber.Ctx) error {
+	pageStr := c.Query("page", "")
+	pageSizeStr := c.Query("page_size", "")
+	search := c.Query("search")
+	user := c.Locals("user").(*jwt.Claims)
+
+	param := &pagination.PaginationParam{}
+	if pageStr != "" && pageSizeStr != "" {
+		page, err := strconv.Atoi(pageStr)
+		if err != nil {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input approval rule")
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Invalid Page", ""))
+		}
+
+		pageSize, err := strconv.Atoi(pageSizeStr)
+		if err != nil {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page size input approval rule")
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Invalid Page Size", ""))
+		}
+
+		// Validasi nilai
+		if page < 1 {
+			page = 1
+		}
+		if pageSize < 1 {
+			pageSize = 10
+		}
+
+		param = &pagination.PaginationParam{
+			Page:     page,
+			PageSize: pageSize,
+		}
+	} else {
+		param = nil
+	}
+
+	result, err := h.Service.FetchRule(c.Context(), search, param)
+
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error fetch approval rule: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all approval rule")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *DocApprovalHandler) DetailRule(c *fiber.Ctx) error {
+	user := c.Locals("user").(*jwt.Claims)
+	code := c.Params("code")
+
+	result, err := h.Service.DetailRule(c.Context(), code)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrDataNotFound) {
+			go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Detail approval rule %s not found", code))
+			return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "Approval rule not found", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error detail approval rule: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Detail approval rule %s", code))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *DocApprovalHandler) CreateRule(c *fiber.Ctx) error {
+	var req *docapproval.CreateRule
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse create approval rule: %s", err.Error()))
+		return err
+	}
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate create approval rule: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to create approval rule", err.Error()))
+	}
+
+	result, err := h.Service.CreateRule(c.Context(), req, user.UserName)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error create approval rule: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to create approval rule", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Create approval rule %s", req.RuleCode))
+
+	return c.Status(fiber.StatusCreated).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *DocApprovalHandler) UpdateRule(c *fiber.Ctx) error {
+	var req *docapproval.UpdateRule
+
+	code := c.Params("code")
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse update approval rule: %s", err.Error()))
+		return err
+	}
+	req.RuleCode = code
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate update approval rule: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to Update approval rule", err.Error()))
+	}
+
+	result, err := h.Service.UpdateRule(c.Context(), req, user.UserCode)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error update approval rule: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to Update approval rule", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Update approval rule %s", req.RuleCode))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
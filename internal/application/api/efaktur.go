@@ -0,0 +1,138 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"gitlab.com/ayaka/internal/application/service"
+	"gitlab.com/ayaka/internal/domain/efaktur"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/custommiddleware"
+	"gitlab.com/ayaka/internal/pkg/formatter"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+	"gitlab.com/ayaka/internal/pkg/validator"
+)
+
+type EfakturApi interface {
+	FetchRange(c *fiber.Ctx) error
+	CreateRange(c *fiber.Ctx) error
+	Export(c *fiber.Ctx) error
+	Reexport(c *fiber.Ctx) error
+}
+
+type EfakturHandler struct {
+	Service   service.EfakturService               `inject:"efakturService"`
+	Validator validator.Validator                  `inject:"validator"`
+	Log       *custommiddleware.LogActivityHandler `inject:"logActivity"`
+}
+
+func (h *EfakturHandler) FetchRange(c *fiber.Ctx) error {
+	user := c.Locals("user").(*jwt.Claims)
+
+	param, err := pageParam(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input tax invoice range")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	result, err := h.Service.FetchRange(c.Context(), param)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error fetch tax invoice range: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all tax invoice range")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *EfakturHandler) CreateRange(c *fiber.Ctx) error {
+	var req *efaktur.Range
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse tax invoice range: %s", err.Error()))
+		return err
+	}
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate tax invoice range: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to create tax invoice range", err.Error()))
+	}
+
+	result, err := h.Service.CreateRange(c.Context(), req, user.UserName)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrDataAlreadyExists) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Tax invoice range %s %d-%d overlaps", req.Prefix, req.StartNo, req.EndNo))
+			return c.Status(fiber.StatusConflict).JSON(formatter.NewErrorResponse(formatter.DataConflict, "Range overlaps an existing tax invoice range", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error create tax invoice range: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to create tax invoice range", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Create tax invoice range %s %d-%d", result.Prefix, result.StartNo, result.EndNo))
+
+	return c.Status(fiber.StatusCreated).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *EfakturHandler) Export(c *fiber.Ctx) error {
+	startDate := c.Query("start_date", "")
+	endDate := c.Query("end_date", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	result, err := h.Service.Export(c.Context(), startDate, endDate, user.UserName)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrInvalidInput):
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Failed export e-Faktur: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		case errors.Is(err, customerrors.ErrTaxInvoiceRange):
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Failed export e-Faktur: %s", err.Error()))
+			return c.Status(fiber.StatusConflict).JSON(formatter.NewErrorResponse(formatter.DataConflict, err.Error(), ""))
+		case errors.Is(err, customerrors.ErrDataNotFound):
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("No document to export e-Faktur %s - %s", startDate, endDate))
+			return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "No document to export", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error export e-Faktur: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to export e-Faktur", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Export %d e-Faktur %s - %s batch %s", result.Count, startDate, endDate, result.Batch))
+
+	return sendEfaktur(c, result)
+}
+
+// Reexport download ulang file satu batch export tanpa nomor faktur baru
+func (h *EfakturHandler) Reexport(c *fiber.Ctx) error {
+	batch := c.Params("batch")
+	user := c.Locals("user").(*jwt.Claims)
+
+	result, err := h.Service.Reexport(c.Context(), batch)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrInvalidInput):
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Failed re-export e-Faktur: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		case errors.Is(err, customerrors.ErrDataNotFound):
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("e-Faktur batch %s not found", batch))
+			return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "e-Faktur batch not found", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error re-export e-Faktur: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to export e-Faktur", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Re-export %d e-Faktur batch %s", result.Count, batch))
+
+	return sendEfaktur(c, result)
+}
+
+func sendEfaktur(c *fiber.Ctx, result *efaktur.Export) error {
+	c.Set(fiber.HeaderContentType, "text/csv")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, result.FileName))
+	c.Set("X-Export-Count", strconv.Itoa(result.Count))
+	c.Set("X-Export-Batch", result.Batch)
+
+	return c.Status(fiber.StatusOK).Send(result.Content)
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"gitlab.com/ayaka/internal/application/service"
+	"gitlab.com/ayaka/internal/domain/exchangerate"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/custommiddleware"
+	"gitlab.com/ayaka/internal/pkg/formatter"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+	"gitlab.com/ayaka/internal/pkg/validator"
+)
+
+type ExchangeRateApi interface {
+	Fetch(c *fiber.Ctx) error
+	Save(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+}
+
+type ExchangeRateHandler struct {
+	Service   service.ExchangeRateService          `inject:"exchangeRateService"`
+	Validator validator.Validator                  `inject:"validator"`
+	Log       *custommiddleware.LogActivityHandler `inject:"logActivity"`
+}
+
+func (h *ExchangeRateHandler) Fetch(c *fiber.Ctx) error {
+	curCode := c.Query("currency", "")
+	startDate := c.Query("start_date", "")
+	endDate := c.Query("end_date", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	param, err := pageParam(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input exchange rate")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	result, err := h.Service.Fetch(c.Context(), curCode, startDate, endDate, param)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrInvalidInput) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid date range exchange rate")
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Invalid date format", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error fetch exchange rate: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all exchange rate")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *ExchangeRateHandler) Save(c *fiber.Ctx) error {
+	var req *exchangerate.Rate
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse exchange rate: %s", err.Error()))
+		return err
+	}
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate exchange rate: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to save exchange rate", err.Error()))
+	}
+
+	result, err := h.Service.Save(c.Context(), req, user.UserName)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error save exchange rate: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to save exchange rate", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Save exchange rate %s to %s on %s", result.CurCode1, result.CurCode2, result.Date))
+
+	return c.Status(fiber.StatusCreated).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *ExchangeRateHandler) Delete(c *fiber.Ctx) error {
+	curCode1 := c.Query("currency_from", "")
+	curCode2 := c.Query("currency_to", "")
+	date := c.Query("rate_date", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := h.Service.Delete(c.Context(), curCode1, curCode2, date); err != nil {
+		if errors.Is(err, customerrors.ErrInvalidInput) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Invalid rate date %s", date))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Invalid date format", ""))
+		}
+		if errors.Is(err, customerrors.ErrDataNotFound) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Exchange rate %s to %s on %s not found", curCode1, curCode2, date))
+			return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "Exchange rate not found", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error delete exchange rate: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to delete exchange rate", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Delete exchange rate %s to %s on %s", curCode1, curCode2, date))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, nil))
+}
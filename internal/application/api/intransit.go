@@ -0,0 +1,127 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"gitlab.com/ayaka/internal/application/service"
+	"gitlab.com/ayaka/internal/domain/intransit"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/custommiddleware"
+	"gitlab.com/ayaka/internal/pkg/export"
+	"gitlab.com/ayaka/internal/pkg/formatter"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+	"gitlab.com/ayaka/internal/pkg/validator"
+)
+
+type InTransitApi interface {
+	Report(c *fiber.Ctx) error
+	FetchDiscrepancy(c *fiber.Ctx) error
+	Resolve(c *fiber.Ctx) error
+}
+
+type InTransitHandler struct {
+	Service   service.InTransitService             `inject:"inTransitService"`
+	Validator validator.Validator                  `inject:"validator"`
+	Log       *custommiddleware.LogActivityHandler `inject:"logActivity"`
+}
+
+func (h *InTransitHandler) Report(c *fiber.Ctx) error {
+	warehouseFrom := c.Query("warehouse_from", "")
+	warehouseTo := c.Query("warehouse_to", "")
+	horizons := c.Query("horizons", "")
+	uom := c.Query("uom", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	format, err := export.Requested(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid export format in transit stock")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	result, err := h.Service.Report(c.Context(), warehouseFrom, warehouseTo, horizons, uom)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrUomConversion) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("In transit stock uom: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		}
+		if errors.Is(err, customerrors.ErrInvalidInput) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid horizons in transit stock")
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Horizons must be positive numbers of days", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error in transit stock: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	if format != "" {
+		go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Export %s in transit stock", format))
+		return export.Send(c, format, "in-transit-stock", result.Lines)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch in transit stock")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *InTransitHandler) FetchDiscrepancy(c *fiber.Ctx) error {
+	status := c.Query("status", "")
+	warehouseFrom := c.Query("warehouse_from", "")
+	warehouseTo := c.Query("warehouse_to", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	param, err := pageParam(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input transfer discrepancy")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	result, err := h.Service.FetchDiscrepancy(c.Context(), status, warehouseFrom, warehouseTo, param)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error fetch transfer discrepancy: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all transfer discrepancy")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *InTransitHandler) Resolve(c *fiber.Ctx) error {
+	var req *intransit.Resolve
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse resolve discrepancy: %s", err.Error()))
+		return err
+	}
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate resolve discrepancy: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to resolve discrepancy", err.Error()))
+	}
+
+	result, err := h.Service.Resolve(c.Context(), req, user.UserName)
+	if err != nil {
+		switch {
+		case errors.Is(err, customerrors.ErrDataNotFound):
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Discrepancy %s %s not found", req.DocNo, req.DNo))
+			return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "Discrepancy not found", ""))
+		case errors.Is(err, customerrors.ErrWarehouseNotAllowed):
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Forbidden resolve discrepancy: %s", err.Error()))
+			return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have access to this warehouse", ""))
+		case errors.Is(err, customerrors.ErrWarehouseFrozen):
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Warehouse frozen: %s", err.Error()))
+			return c.Status(fiber.StatusConflict).JSON(formatter.NewErrorResponse(formatter.DataConflict, "Warehouse is being counted in an open stock opname", ""))
+		case errors.Is(err, customerrors.ErrInvalidInput), errors.Is(err, customerrors.ErrInsufficientStock), errors.Is(err, customerrors.ErrJournalAccount):
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Failed resolve discrepancy: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error resolve discrepancy: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to resolve discrepancy", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Resolve discrepancy %s %s as %s", result.DocNo, result.DNo, result.Status))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
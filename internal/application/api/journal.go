@@ -0,0 +1,186 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gitlab.com/ayaka/internal/application/service"
+	"gitlab.com/ayaka/internal/domain/journal"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/custommiddleware"
+	"gitlab.com/ayaka/internal/pkg/export"
+	"gitlab.com/ayaka/internal/pkg/formatter"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+	"gitlab.com/ayaka/internal/pkg/validator"
+)
+
+type JournalApi interface {
+	Fetch(c *fiber.Ctx) error
+	Detail(c *fiber.Ctx) error
+	TrialBalance(c *fiber.Ctx) error
+	FetchAccount(c *fiber.Ctx) error
+	SaveAccount(c *fiber.Ctx) error
+}
+
+type JournalHandler struct {
+	Service   service.JournalService               `inject:"journalService"`
+	Validator validator.Validator                  `inject:"validator"`
+	Log       *custommiddleware.LogActivityHandler `inject:"logActivity"`
+}
+
+func (h *JournalHandler) Fetch(c *fiber.Ctx) error {
+	search := c.Query("search", "")
+	startDate := c.Query("start_date", "")
+	endDate := c.Query("end_date", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	param, err := pageParam(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input journal")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	result, err := h.Service.Fetch(c.Context(), search, startDate, endDate, param)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrInvalidInput) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid date input journal")
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Invalid Date", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error fetch journal: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all journal")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *JournalHandler) Detail(c *fiber.Ctx) error {
+	docNo := strings.ReplaceAll(c.Params("code"), "-", "/")
+	user := c.Locals("user").(*jwt.Claims)
+
+	result, err := h.Service.Detail(c.Context(), docNo)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrDataNotFound) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Journal %s not found", docNo))
+			return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "Journal not found", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error detail journal: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Get detail journal %s", docNo))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *JournalHandler) TrialBalance(c *fiber.Ctx) error {
+	startDate := c.Query("start_date", "")
+	endDate := c.Query("end_date", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	format, err := export.Requested(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid export format trial balance")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	result, err := h.Service.TrialBalance(c.Context(), startDate, endDate)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrInvalidInput) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid period input trial balance")
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Invalid Period", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error trial balance: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	if format != "" {
+		go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Export %s trial balance", format))
+		return export.Send(c, format, "trial-balance", result.Accounts)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch trial balance")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *JournalHandler) FetchAccount(c *fiber.Ctx) error {
+	search := c.Query("search", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	param, err := pageParam(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input journal account")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	result, err := h.Service.FetchAccount(c.Context(), search, param)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error fetch journal account: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all journal account")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *JournalHandler) SaveAccount(c *fiber.Ctx) error {
+	var req *journal.Account
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse journal account: %s", err.Error()))
+		return err
+	}
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate journal account: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to save journal account", err.Error()))
+	}
+
+	result, err := h.Service.SaveAccount(c.Context(), req, user.UserName)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error save journal account: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to save journal account", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Save journal account %s %s", req.ItemCategoryCode, req.WarehouseCode))
+
+	return c.Status(fiber.StatusCreated).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+// pageParam mengembalikan nil (tanpa paging) jika page / page_size kosong
+func pageParam(c *fiber.Ctx) (*pagination.PaginationParam, error) {
+	pageStr := c.Query("page", "")
+	pageSizeStr := c.Query("page_size", "")
+	if pageStr == "" || pageSizeStr == "" {
+		return nil, nil
+	}
+
+	page, err := strconv.Atoi(pageStr)
+	if err != nil {
+		return nil, errors.New("Invalid Page")
+	}
+	pageSize, err := strconv.Atoi(pageSizeStr)
+	if err != nil {
+		return nil, errors.New("Invalid Page Size")
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	return &pagination.PaginationParam{
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"gitlab.com/ayaka/internal/application/service"
+	"gitlab.com/ayaka/internal/domain/logactivity"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/custommiddleware"
 	"gitlab.com/ayaka/internal/pkg/formatter"
@@ -28,7 +29,21 @@ func (h *TblLogHandler) GetLog(c *fiber.Ctx) error {
 	code := c.Query("code")
 	category := c.Query("category")
 
-	result, err := h.Service.GetLog(c.Context(), code, category)
+	filter := &logactivity.Filter{
+		Category:  category,
+		Code:      code,
+		UserCode:  c.Query("user"),
+		StartDate: c.Query("start_date"),
+		EndDate:   c.Query("end_date"),
+	}
+
+	param, err := pageParam(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input log")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	result, err := h.Service.GetLog(c.Context(), filter, param)
 
 	if err != nil {
 		if errors.Is(err, customerrors.ErrDataNotFound) {
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"gitlab.com/ayaka/internal/application/service"
+	"gitlab.com/ayaka/internal/domain/masterimport"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/custommiddleware"
+	"gitlab.com/ayaka/internal/pkg/formatter"
+	"gitlab.com/ayaka/internal/pkg/importer"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+)
+
+type MasterImportApi interface {
+	Item(c *fiber.Ctx) error
+	Vendor(c *fiber.Ctx) error
+	Warehouse(c *fiber.Ctx) error
+	Uom(c *fiber.Ctx) error
+	ExchangeRate(c *fiber.Ctx) error
+}
+
+type MasterImportHandler struct {
+	Service service.MasterImportService          `inject:"masterImportService"`
+	Log     *custommiddleware.LogActivityHandler `inject:"logActivity"`
+}
+
+func (h *MasterImportHandler) Item(c *fiber.Ctx) error {
+	return h.importFile(c, masterimport.Item)
+}
+
+func (h *MasterImportHandler) Vendor(c *fiber.Ctx) error {
+	return h.importFile(c, masterimport.Vendor)
+}
+
+func (h *MasterImportHandler) Warehouse(c *fiber.Ctx) error {
+	return h.importFile(c, masterimport.Warehouse)
+}
+
+func (h *MasterImportHandler) Uom(c *fiber.Ctx) error {
+	return h.importFile(c, masterimport.Uom)
+}
+
+func (h *MasterImportHandler) ExchangeRate(c *fiber.Ctx) error {
+	return h.importFile(c, masterimport.ExchangeRate)
+}
+
+// importFile menerima multipart: file (csv/xlsx), mapping (opsional, json {"header file": "field"})
+// dan dry_run. Dry run hanya mengembalikan laporan error per baris.
+func (h *MasterImportHandler) importFile(c *fiber.Ctx, entity string) error {
+	user := c.Locals("user").(*jwt.Claims)
+
+	dryRun, err := strconv.ParseBool(c.Query("dry_run", c.FormValue("dry_run", "false")))
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Invalid dry_run import %s", entity))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Invalid dry_run", ""))
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Import %s without file", entity))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "File is required", ""))
+	}
+
+	var mapping map[string]string
+	if raw := c.FormValue("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Invalid mapping import %s: %s", entity, err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Invalid column mapping", ""))
+		}
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error open import file: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+	defer f.Close()
+
+	rows, err := importer.Read(file.Filename, f, file.Size)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error read import file %s: %s", file.Filename, err.Error()))
+		if errors.Is(err, customerrors.ErrUnsupportedFormat) {
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "File must be csv or xlsx", ""))
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Invalid file", ""))
+	}
+
+	result, err := h.Service.Import(c.Context(), entity, rows, mapping, dryRun, user.UserName)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrInvalidInput) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Failed import %s: %s", entity, err.Error()))
+			if result != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponseList(formatter.InvalidRequest, "Import has invalid rows, nothing was saved", "", result))
+			}
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error import %s: %s", entity, err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	if dryRun {
+		go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Dry run import %s: %d of %d records valid", entity, result.ValidRecords, result.TotalRecords))
+		return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Import %s: %d records", entity, result.Imported))
+
+	return c.Status(fiber.StatusCreated).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
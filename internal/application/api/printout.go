@@ -0,0 +1,82 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gitlab.com/ayaka/internal/application/service"
+	"gitlab.com/ayaka/internal/domain/printout"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/custommiddleware"
+	"gitlab.com/ayaka/internal/pkg/formatter"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+)
+
+type PrintoutApi interface {
+	PurchaseOrder(c *fiber.Ctx) error
+	DirectSalesDelivery(c *fiber.Ctx) error
+	MaterialTransfer(c *fiber.Ctx) error
+	PurchaseMaterialReceive(c *fiber.Ctx) error
+}
+
+type PrintoutHandler struct {
+	Service service.PrintoutService              `inject:"printoutService"`
+	Log     *custommiddleware.LogActivityHandler `inject:"logActivity"`
+}
+
+func (h *PrintoutHandler) PurchaseOrder(c *fiber.Ctx) error {
+	return h.print(c, printout.PurchaseOrder)
+}
+
+func (h *PrintoutHandler) DirectSalesDelivery(c *fiber.Ctx) error {
+	return h.print(c, printout.DirectSalesDelivery)
+}
+
+func (h *PrintoutHandler) MaterialTransfer(c *fiber.Ctx) error {
+	return h.print(c, printout.MaterialTransfer)
+}
+
+func (h *PrintoutHandler) PurchaseMaterialReceive(c *fiber.Ctx) error {
+	return h.print(c, printout.PurchaseMaterialReceive)
+}
+
+// print mengembalikan dokumen dalam bentuk pdf (default) atau html lewat ?format=
+func (h *PrintoutHandler) print(c *fiber.Ctx, docType string) error {
+	user := c.Locals("user").(*jwt.Claims)
+	code := c.Params("code")
+	format := c.Query("format", printout.PDF)
+
+	printedBy := user.UserName
+	if printedBy == "" {
+		printedBy = user.UserCode
+	}
+
+	result, err := h.Service.Print(c.Context(), docType, code, format, printedBy)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrDataNotFound) {
+			go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Print %s %s not found", docType, code))
+			return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "Document not found", ""))
+		}
+		if errors.Is(err, customerrors.ErrUnsupportedFormat) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Print %s with unsupported format %s", docType, format))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Format must be pdf or html", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error print %s: %s", docType, err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Print %s %s", docType, code))
+
+	if format == printout.HTML {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	} else {
+		// nomor dokumen memakai "/" sehingga tidak bisa langsung jadi nama file
+		filename := strings.ReplaceAll(code, "/", "-")
+		c.Set(fiber.HeaderContentType, "application/pdf")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s.pdf"`, filename))
+	}
+
+	return c.Status(fiber.StatusOK).Send(result)
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"gitlab.com/ayaka/internal/application/service"
+	"gitlab.com/ayaka/internal/domain/quotationcomparison"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/custommiddleware"
+	"gitlab.com/ayaka/internal/pkg/formatter"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+	"gitlab.com/ayaka/internal/pkg/validator"
+)
+
+type QuotationComparisonApi interface {
+	Compare(c *fiber.Ctx) error
+	Select(c *fiber.Ctx) error
+}
+
+type QuotationComparisonHandler struct {
+	Service   service.QuotationComparisonService   `inject:"quotationComparisonService"`
+	Validator validator.Validator                  `inject:"validator"`
+	Log       *custommiddleware.LogActivityHandler `inject:"logActivity"`
+}
+
+// Compare ranking quotation per baris purchase_order_request, atau per
+// item_code (dipisah koma) kalau PO request belum dibuat
+func (h *QuotationComparisonHandler) Compare(c *fiber.Ctx) error {
+	porDocNo := c.Query("purchase_order_request", "")
+	itemCodes := c.Query("item_code", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	result, err := h.Service.Compare(c.Context(), porDocNo, itemCodes)
+	if err != nil {
+		return h.failed(c, user, "compare vendor quotation", err)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Compare vendor quotation")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *QuotationComparisonHandler) Select(c *fiber.Ctx) error {
+	var req *quotationcomparison.Selection
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse vendor quotation selection: %s", err.Error()))
+		return err
+	}
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate vendor quotation selection: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to select vendor quotation", err.Error()))
+	}
+
+	result, err := h.Service.Select(c.Context(), req, user.UserName)
+	if err != nil {
+		return h.failed(c, user, "select vendor quotation", err)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Select vendor quotation %s for purchase order request %s", result.VendorQuotationDocNo, result.PurchaseOrderRequestDocNo))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *QuotationComparisonHandler) failed(c *fiber.Ctx, user *jwt.Claims, action string, err error) error {
+	switch {
+	case errors.Is(err, customerrors.ErrDataNotFound):
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Data not found on %s", action))
+		return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "Data not found", ""))
+	case errors.Is(err, customerrors.ErrInvalidInput):
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Failed %s: %s", action, err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error %s: %s", action, err.Error()))
+	return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, fmt.Sprintf("Failed to %s", action), ""))
+}
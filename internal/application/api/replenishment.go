@@ -0,0 +1,144 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"gitlab.com/ayaka/internal/application/service"
+	"gitlab.com/ayaka/internal/domain/replenishment"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/custommiddleware"
+	"gitlab.com/ayaka/internal/pkg/export"
+	"gitlab.com/ayaka/internal/pkg/formatter"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+	"gitlab.com/ayaka/internal/pkg/validator"
+)
+
+type ReplenishmentApi interface {
+	FetchSetting(c *fiber.Ctx) error
+	SaveSetting(c *fiber.Ctx) error
+	Proposals(c *fiber.Ctx) error
+	CreateRequest(c *fiber.Ctx) error
+}
+
+type ReplenishmentHandler struct {
+	Service   service.ReplenishmentService         `inject:"replenishmentService"`
+	Validator validator.Validator                  `inject:"validator"`
+	Log       *custommiddleware.LogActivityHandler `inject:"logActivity"`
+}
+
+func (h *ReplenishmentHandler) FetchSetting(c *fiber.Ctx) error {
+	warehouse := c.Query("warehouse", "")
+	search := c.Query("search", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	param, err := pageParam(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input replenishment setting")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	result, err := h.Service.FetchSetting(c.Context(), warehouse, search, param)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error fetch replenishment setting: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all replenishment setting")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *ReplenishmentHandler) SaveSetting(c *fiber.Ctx) error {
+	var req *replenishment.Setting
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse replenishment setting: %s", err.Error()))
+		return err
+	}
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate replenishment setting: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to save replenishment setting", err.Error()))
+	}
+
+	result, err := h.Service.SaveSetting(c.Context(), req, user.UserName)
+	if err != nil {
+		return h.failed(c, user, "save replenishment setting", err)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Save replenishment setting %s %s", result.ItemCode, result.WarehouseCode))
+
+	return c.Status(fiber.StatusCreated).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+// Proposals usulan replenishment per item + gudang, all=true untuk ikut
+// menampilkan yang stoknya masih cukup, uom opsional
+func (h *ReplenishmentHandler) Proposals(c *fiber.Ctx) error {
+	warehouse := c.Query("warehouse", "")
+	itemCategory := c.Query("item_category", "")
+	uom := c.Query("uom", "")
+	all := c.QueryBool("all", false)
+	user := c.Locals("user").(*jwt.Claims)
+
+	format, err := export.Requested(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid export format replenishment")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	result, err := h.Service.Proposals(c.Context(), warehouse, itemCategory, uom, all)
+	if err != nil {
+		return h.failed(c, user, "fetch replenishment", err)
+	}
+
+	if format != "" {
+		go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Export %s replenishment", format))
+		return export.Send(c, format, "replenishment", result)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch replenishment")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+// CreateRequest membuat draft material request dari proposal terpilih
+func (h *ReplenishmentHandler) CreateRequest(c *fiber.Ctx) error {
+	var req *replenishment.Request
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse replenishment request: %s", err.Error()))
+		return err
+	}
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate replenishment request: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to create material request", err.Error()))
+	}
+
+	result, err := h.Service.CreateRequest(c.Context(), req, user.UserName)
+	if err != nil {
+		return h.failed(c, user, "create material request from replenishment", err)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Create material request %s from replenishment", result.DocNo))
+
+	return c.Status(fiber.StatusCreated).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *ReplenishmentHandler) failed(c *fiber.Ctx, user *jwt.Claims, action string, err error) error {
+	switch {
+	case errors.Is(err, customerrors.ErrWarehouseNotAllowed), errors.Is(err, customerrors.ErrSiteNotAllowed):
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Forbidden %s: %s", action, err.Error()))
+		return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have access to this warehouse", ""))
+	case errors.Is(err, customerrors.ErrInvalidInput), errors.Is(err, customerrors.ErrUomConversion):
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Failed %s: %s", action, err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error %s: %s", action, err.Error()))
+	return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, fmt.Sprintf("Failed to %s", action), ""))
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"gitlab.com/ayaka/internal/application/service"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/custommiddleware"
+	"gitlab.com/ayaka/internal/pkg/export"
+	"gitlab.com/ayaka/internal/pkg/formatter"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+)
+
+type StockCardApi interface {
+	Fetch(c *fiber.Ctx) error
+}
+
+type StockCardHandler struct {
+	Service service.StockCardService             `inject:"stockCardService"`
+	Log     *custommiddleware.LogActivityHandler `inject:"logActivity"`
+}
+
+func (h *StockCardHandler) Fetch(c *fiber.Ctx) error {
+	itemCode := c.Query("item_code", "")
+	warehouse := c.Query("warehouse", "")
+	batch := c.Query("batch", "")
+	startDate := c.Query("start_date", "")
+	endDate := c.Query("end_date", "")
+	uom := c.Query("uom", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	format, err := export.Requested(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid export format stock card")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	result, err := h.Service.Fetch(c.Context(), itemCode, warehouse, batch, startDate, endDate, uom)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrInvalidInput) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid input stock card")
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Item code and a valid period are required", ""))
+		}
+		if errors.Is(err, customerrors.ErrUomConversion) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Stock card uom: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		}
+		if errors.Is(err, customerrors.ErrDataNotFound) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Item %s not found for stock card", itemCode))
+			return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "Item not found", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error stock card: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	if format != "" {
+		go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Export %s stock card %s", format, itemCode))
+		return export.Send(c, format, "stock-card", result.Lines)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Fetch stock card %s", itemCode))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
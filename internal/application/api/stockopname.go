@@ -0,0 +1,173 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gitlab.com/ayaka/internal/application/service"
+	"gitlab.com/ayaka/internal/domain/stockopname"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/custommiddleware"
+	"gitlab.com/ayaka/internal/pkg/formatter"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+	"gitlab.com/ayaka/internal/pkg/validator"
+)
+
+type StockOpnameApi interface {
+	Fetch(c *fiber.Ctx) error
+	Detail(c *fiber.Ctx) error
+	Create(c *fiber.Ctx) error
+	Count(c *fiber.Ctx) error
+	Approve(c *fiber.Ctx) error
+	Cancel(c *fiber.Ctx) error
+}
+
+type StockOpnameHandler struct {
+	Service   service.StockOpnameService           `inject:"stockOpnameService"`
+	Validator validator.Validator                  `inject:"validator"`
+	Log       *custommiddleware.LogActivityHandler `inject:"logActivity"`
+}
+
+func (h *StockOpnameHandler) Fetch(c *fiber.Ctx) error {
+	docNo := c.Query("document_number", "")
+	warehouse := c.Query("warehouse", "")
+	status := c.Query("status", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	param, err := pageParam(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input stock opname")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	result, err := h.Service.Fetch(c.Context(), docNo, warehouse, status, param)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error fetch stock opname: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all stock opname")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *StockOpnameHandler) Detail(c *fiber.Ctx) error {
+	docNo := strings.ReplaceAll(c.Params("code"), "-", "/")
+	user := c.Locals("user").(*jwt.Claims)
+
+	result, err := h.Service.Detail(c.Context(), docNo)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrDataNotFound) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Stock opname %s not found", docNo))
+			return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "Stock opname not found", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error detail stock opname: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Get detail stock opname %s", docNo))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+// Create membekukan gudang dan menyimpan snapshot stoknya
+func (h *StockOpnameHandler) Create(c *fiber.Ctx) error {
+	var req *stockopname.Create
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse stock opname: %s", err.Error()))
+		return err
+	}
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate stock opname: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to create stock opname", err.Error()))
+	}
+
+	result, err := h.Service.Create(c.Context(), req, user.UserName)
+	if err != nil {
+		return h.failed(c, user, "create", err)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Create stock opname %s", result.DocNo))
+
+	return c.Status(fiber.StatusCreated).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+// Count menyimpan hasil hitung satu round
+func (h *StockOpnameHandler) Count(c *fiber.Ctx) error {
+	var req *stockopname.Count
+	docNo := strings.ReplaceAll(c.Params("code"), "-", "/")
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse stock opname count: %s", err.Error()))
+		return err
+	}
+	req.DocNo = docNo
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate stock opname count: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to submit stock opname count", err.Error()))
+	}
+
+	result, err := h.Service.Count(c.Context(), req, user.UserName)
+	if err != nil {
+		return h.failed(c, user, "count", err)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Count stock opname %s round %d", docNo, req.Round))
+
+	return c.Status(fiber.StatusCreated).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+// Approve menutup opname dan membuat stock adjustment atas selisihnya
+func (h *StockOpnameHandler) Approve(c *fiber.Ctx) error {
+	docNo := strings.ReplaceAll(c.Params("code"), "-", "/")
+	user := c.Locals("user").(*jwt.Claims)
+
+	result, err := h.Service.Approve(c.Context(), docNo, user.UserName)
+	if err != nil {
+		return h.failed(c, user, "approve", err)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Approve stock opname %s", docNo))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *StockOpnameHandler) Cancel(c *fiber.Ctx) error {
+	docNo := strings.ReplaceAll(c.Params("code"), "-", "/")
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := h.Service.Cancel(c.Context(), docNo, user.UserName); err != nil {
+		return h.failed(c, user, "cancel", err)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Cancel stock opname %s", docNo))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, nil))
+}
+
+func (h *StockOpnameHandler) failed(c *fiber.Ctx, user *jwt.Claims, action string, err error) error {
+	switch {
+	case errors.Is(err, customerrors.ErrDataNotFound):
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Stock opname not found on %s", action))
+		return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "Stock opname not found", ""))
+	case errors.Is(err, customerrors.ErrWarehouseNotAllowed):
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Forbidden %s: %s", action, err.Error()))
+		return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have access to this warehouse", ""))
+	case errors.Is(err, customerrors.ErrDataAlreadyExists):
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Failed %s stock opname: %s", action, err.Error()))
+		return c.Status(fiber.StatusConflict).JSON(formatter.NewErrorResponse(formatter.DataConflict, "Warehouse already has an open stock opname", ""))
+	case errors.Is(err, customerrors.ErrInvalidInput), errors.Is(err, customerrors.ErrInsufficientStock):
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Failed %s stock opname: %s", action, err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error %s stock opname: %s", action, err.Error()))
+	return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, fmt.Sprintf("Failed to %s stock opname", action), ""))
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"gitlab.com/ayaka/internal/application/service"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/custommiddleware"
+	"gitlab.com/ayaka/internal/pkg/export"
+	"gitlab.com/ayaka/internal/pkg/formatter"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type StockValuationApi interface {
+	Fetch(c *fiber.Ctx) error
+}
+
+type StockValuationHandler struct {
+	Service service.StockValuationService        `inject:"stockValuationService"`
+	Log     *custommiddleware.LogActivityHandler `inject:"logActivity"`
+}
+
+func (h *StockValuationHandler) Fetch(c *fiber.Ctx) error {
+	pageStr := c.Query("page", "")
+	pageSizeStr := c.Query("page_size", "")
+	date := c.Query("date", "")
+	warehouse := c.Query("warehouse", "")
+	itemCatCode := c.Query("item_category", "")
+	itemName := c.Query("item_name", "")
+	uom := c.Query("uom", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	format, err := export.Requested(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid export format stock valuation")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	var param *pagination.PaginationParam
+	if pageStr != "" && pageSizeStr != "" && format == "" {
+		page, err := strconv.Atoi(pageStr)
+		if err != nil {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input stock valuation")
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Invalid Page", ""))
+		}
+
+		pageSize, err := strconv.Atoi(pageSizeStr)
+		if err != nil {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page size input stock valuation")
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Invalid Page Size", ""))
+		}
+
+		if page < 1 {
+			page = 1
+		}
+		if pageSize < 1 {
+			pageSize = 10
+		}
+
+		param = &pagination.PaginationParam{
+			Page:     page,
+			PageSize: pageSize,
+		}
+	}
+
+	result, err := h.Service.Fetch(c.Context(), date, warehouse, itemCatCode, itemName, uom, param)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrUomConversion) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Stock valuation uom: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		}
+		if errors.Is(err, customerrors.ErrInvalidInput) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid date input stock valuation")
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Invalid Date", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error fetch stock valuation: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	if format != "" {
+		go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Export %s stock valuation", format))
+		return export.Send(c, format, "stock-valuation", result.Data)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch stock valuation")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
@@ -1,13 +1,16 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"gitlab.com/ayaka/internal/application/service"
 
+	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/custommiddleware"
+	"gitlab.com/ayaka/internal/pkg/export"
 	"gitlab.com/ayaka/internal/pkg/formatter"
 	"gitlab.com/ayaka/internal/pkg/jwt"
 	"gitlab.com/ayaka/internal/pkg/pagination"
@@ -29,10 +32,17 @@ func (h *TblDailyStockMovementHandler) Fetch(c *fiber.Ctx) error {
 	warehouse := c.Query("warehouse", "")
 	itemCategory := c.Query("item_category")
 	date := c.Query("date", "")
+	uom := c.Query("uom", "")
 	user := c.Locals("user").(*jwt.Claims)
 
+	format, err := export.Requested(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid export format daily stock movement")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
 	param := &pagination.PaginationParam{}
-	if pageStr != "" && pageSizeStr != "" {
+	if pageStr != "" && pageSizeStr != "" && format == "" {
 		page, err := strconv.Atoi(pageStr)
 		if err != nil {
 			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input daily stock movement")
@@ -61,13 +71,22 @@ func (h *TblDailyStockMovementHandler) Fetch(c *fiber.Ctx) error {
 		param = nil
 	}
 
-	result, err := h.Service.Fetch(c.Context(), warehouse, date, item, itemCategory, param)
+	result, err := h.Service.Fetch(c.Context(), warehouse, date, item, itemCategory, uom, param)
 
 	if err != nil {
+		if errors.Is(err, customerrors.ErrUomConversion) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Daily stock movement uom: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		}
 		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error fetch daily stock movement: %s", err.Error()))
 		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
 	}
 
+	if format != "" {
+		go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Export %s daily stock movement", format))
+		return export.Send(c, format, "daily-stock-movement", result.Data)
+	}
+
 	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all daily stock movement")
 
 	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
@@ -108,6 +108,14 @@ func (h *TblDirectMaterialReceiveHandler) Create(c *fiber.Ctx) error {
 
 	result, err := h.Service.Create(c.Context(), req, user.UserName)
 	if err != nil {
+		if errors.Is(err, customerrors.ErrWarehouseNotAllowed) || errors.Is(err, customerrors.ErrSiteNotAllowed) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Forbidden create: %s", err.Error()))
+			return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have access to this warehouse", ""))
+		}
+		if errors.Is(err, customerrors.ErrWarehouseFrozen) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Warehouse frozen: %s", err.Error()))
+			return c.Status(fiber.StatusConflict).JSON(formatter.NewErrorResponse(formatter.DataConflict, "Warehouse is being counted in an open stock opname", ""))
+		}
 		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error create item: %s", err.Error()))
 		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to create direct material receive", ""))
 	}
@@ -142,6 +150,14 @@ func (h *TblDirectMaterialReceiveHandler) Update(c *fiber.Ctx) error {
 
 	result, err := h.Service.Update(c.Context(), req, user.UserCode)
 	if err != nil {
+		if errors.Is(err, customerrors.ErrWarehouseNotAllowed) || errors.Is(err, customerrors.ErrSiteNotAllowed) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Forbidden update: %s", err.Error()))
+			return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have access to this warehouse", ""))
+		}
+		if errors.Is(err, customerrors.ErrWarehouseFrozen) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Warehouse frozen: %s", err.Error()))
+			return c.Status(fiber.StatusConflict).JSON(formatter.NewErrorResponse(formatter.DataConflict, "Warehouse is being counted in an open stock opname", ""))
+		}
 		if errors.Is(err, customerrors.ErrNoDataEdited) {
 			go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Update data direct material receive %s", req.DocNo))
 			return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
@@ -107,6 +107,30 @@ func (h *TblDirectSalesDeliveryHandler) Create(c *fiber.Ctx) error {
 
 	result, err := h.Service.Create(c.Context(), req, user.UserName)
 	if err != nil {
+		if errors.Is(err, customerrors.ErrWarehouseNotAllowed) || errors.Is(err, customerrors.ErrSiteNotAllowed) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Forbidden create: %s", err.Error()))
+			return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have access to this warehouse", ""))
+		}
+		if errors.Is(err, customerrors.ErrWarehouseFrozen) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Warehouse frozen: %s", err.Error()))
+			return c.Status(fiber.StatusConflict).JSON(formatter.NewErrorResponse(formatter.DataConflict, "Warehouse is being counted in an open stock opname", ""))
+		}
+		if errors.Is(err, customerrors.ErrJournalAccount) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Journal account: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		}
+		if errors.Is(err, customerrors.ErrInsufficientStock) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Insufficient stock: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Stock can not less than quantity", ""))
+		}
+		if errors.Is(err, customerrors.ErrBatchExpired) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Batch expired: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		}
+		if errors.Is(err, customerrors.ErrInvalidInput) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Invalid sales order line: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		}
 		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error create item: %s", err.Error()))
 		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to create direct sales delivery", ""))
 	}
@@ -141,6 +165,18 @@ func (h *TblDirectSalesDeliveryHandler) Update(c *fiber.Ctx) error {
 
 	result, err := h.Service.Update(c.Context(), req, user.UserCode)
 	if err != nil {
+		if errors.Is(err, customerrors.ErrWarehouseNotAllowed) || errors.Is(err, customerrors.ErrSiteNotAllowed) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Forbidden update: %s", err.Error()))
+			return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have access to this warehouse", ""))
+		}
+		if errors.Is(err, customerrors.ErrWarehouseFrozen) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Warehouse frozen: %s", err.Error()))
+			return c.Status(fiber.StatusConflict).JSON(formatter.NewErrorResponse(formatter.DataConflict, "Warehouse is being counted in an open stock opname", ""))
+		}
+		if errors.Is(err, customerrors.ErrJournalAccount) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Journal account: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		}
 		if errors.Is(err, customerrors.ErrNoDataEdited) {
 			go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Update data direct sales delivery %s", req.DocNo))
 			return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
@@ -8,6 +8,7 @@ import (
 	"gitlab.com/ayaka/internal/application/service"
 
 	"gitlab.com/ayaka/internal/pkg/custommiddleware"
+	"gitlab.com/ayaka/internal/pkg/export"
 	"gitlab.com/ayaka/internal/pkg/formatter"
 	"gitlab.com/ayaka/internal/pkg/jwt"
 	"gitlab.com/ayaka/internal/pkg/pagination"
@@ -30,8 +31,14 @@ func (h *TblHistoryOfStockHandler) Fetch(c *fiber.Ctx) error {
 	source := c.Query("source")
 	user := c.Locals("user").(*jwt.Claims)
 
+	format, err := export.Requested(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid export format history of stock")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
 	param := &pagination.PaginationParam{}
-	if pageStr != "" && pageSizeStr != "" {
+	if pageStr != "" && pageSizeStr != "" && format == "" {
 		page, err := strconv.Atoi(pageStr)
 		if err != nil {
 			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input history of stock")
@@ -67,6 +74,11 @@ func (h *TblHistoryOfStockHandler) Fetch(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
 	}
 
+	if format != "" {
+		go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Export %s history of stock", format))
+		return export.Send(c, format, "history-of-stock", result.Data)
+	}
+
 	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all history of stock")
 
 	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
@@ -101,6 +101,10 @@ func (h *TblInitStockHandler) Detail(c *fiber.Ctx) error {
 
 	res, err := h.Service.Detail(c.Context(), res.DocNo)
 	if err != nil {
+		if errors.Is(err, customerrors.ErrDataNotFound) {
+			go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Detail initial stock %s not found", docNo))
+			return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "Data not found", ""))
+		}
 		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error detail initial stock: %s", err.Error()))
 		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal server error", ""))
 	}
@@ -132,6 +136,14 @@ func (h *TblInitStockHandler) Create(c *fiber.Ctx) error {
 
 	result, err := h.Service.Create(c.Context(), req, user.UserName)
 	if err != nil {
+		if errors.Is(err, customerrors.ErrWarehouseNotAllowed) || errors.Is(err, customerrors.ErrSiteNotAllowed) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Forbidden create: %s", err.Error()))
+			return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have access to this warehouse", ""))
+		}
+		if errors.Is(err, customerrors.ErrWarehouseFrozen) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Warehouse frozen: %s", err.Error()))
+			return c.Status(fiber.StatusConflict).JSON(formatter.NewErrorResponse(formatter.DataConflict, "Warehouse is being counted in an open stock opname", ""))
+		}
 		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error create item: %s", err.Error()))
 		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to create initial stock", ""))
 	}
@@ -166,6 +178,14 @@ func (h *TblInitStockHandler) Update(c *fiber.Ctx) error {
 
 	result, err := h.Service.Update(c.Context(), req, user.UserCode)
 	if err != nil {
+		if errors.Is(err, customerrors.ErrWarehouseNotAllowed) || errors.Is(err, customerrors.ErrSiteNotAllowed) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Forbidden update: %s", err.Error()))
+			return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have access to this warehouse", ""))
+		}
+		if errors.Is(err, customerrors.ErrWarehouseFrozen) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Warehouse frozen: %s", err.Error()))
+			return c.Status(fiber.StatusConflict).JSON(formatter.NewErrorResponse(formatter.DataConflict, "Warehouse is being counted in an open stock opname", ""))
+		}
 		if errors.Is(err, customerrors.ErrNoDataEdited) {
 			go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Update data initial stock %s", req.DocNo))
 			return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
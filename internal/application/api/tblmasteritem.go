@@ -170,6 +170,10 @@ func (h *TblItemHandler) Update(c *fiber.Ctx) error {
 			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Internal server error create item: %s", err.Error()))
 			return c.Status(fiber.StatusConflict).JSON(formatter.NewErrorResponse(formatter.DataConflict, err.Error(), ""))
 		}
+		if errors.Is(err, customerrors.ErrInvalidInput) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Failed update item: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		}
 		if errors.Is(err, customerrors.ErrNoDataEdited) {
 			go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Update data item %s", req.ItemCode))
 			return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
@@ -1,7 +1,7 @@
 package api
 
 import (
-	// "errors"
+	"errors"
 
 	"fmt"
 	"strconv"
@@ -9,6 +9,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"gitlab.com/ayaka/internal/application/service"
 	"gitlab.com/ayaka/internal/domain/tblmaterialreceive"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/custommiddleware"
 	"gitlab.com/ayaka/internal/pkg/formatter"
 	"gitlab.com/ayaka/internal/pkg/jwt"
@@ -101,6 +102,18 @@ func (h *TblMaterialReceiveHandler) Create(c *fiber.Ctx) error {
 
 	result, err := h.Service.Create(c.Context(), req, user.UserName)
 	if err != nil {
+		if errors.Is(err, customerrors.ErrWarehouseNotAllowed) || errors.Is(err, customerrors.ErrSiteNotAllowed) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Forbidden create: %s", err.Error()))
+			return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have access to this warehouse", ""))
+		}
+		if errors.Is(err, customerrors.ErrWarehouseFrozen) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Warehouse frozen: %s", err.Error()))
+			return c.Status(fiber.StatusConflict).JSON(formatter.NewErrorResponse(formatter.DataConflict, "Warehouse is being counted in an open stock opname", ""))
+		}
+		if errors.Is(err, customerrors.ErrInvalidQuantity) || errors.Is(err, customerrors.ErrInsufficientStock) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Invalid receive quantity: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		}
 		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error create material receive: %s", err.Error()))
 		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to create material receive", ""))
 	}
@@ -15,6 +15,7 @@ import (
 
 	// "gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/export"
 	"gitlab.com/ayaka/internal/pkg/custommiddleware"
 	"gitlab.com/ayaka/internal/pkg/formatter"
 	"gitlab.com/ayaka/internal/pkg/jwt"
@@ -108,6 +109,10 @@ func (h *TblMaterialRequestHandler) Create(c *fiber.Ctx) error {
 
 	result, err := h.Service.Create(c.Context(), req, user.UserName)
 	if err != nil {
+		if errors.Is(err, customerrors.ErrWarehouseNotAllowed) || errors.Is(err, customerrors.ErrSiteNotAllowed) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Forbidden create: %s", err.Error()))
+			return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have access to this warehouse", ""))
+		}
 		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error create material request: %s", err.Error()))
 		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to create material request", ""))
 	}
@@ -142,6 +147,10 @@ func (h *TblMaterialRequestHandler) Update(c *fiber.Ctx) error {
 
 	result, err := h.Service.Update(c.Context(), req, user.UserCode)
 	if err != nil {
+		if errors.Is(err, customerrors.ErrWarehouseNotAllowed) || errors.Is(err, customerrors.ErrSiteNotAllowed) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Forbidden update: %s", err.Error()))
+			return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have access to this warehouse", ""))
+		}
 		if errors.Is(err, customerrors.ErrNoDataEdited) {
 			go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Update data material request %s", req.DocNo))
 			return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
@@ -216,8 +225,14 @@ func (h *TblMaterialRequestHandler) OutstandingMaterial(c *fiber.Ctx) error {
 	endDate := c.Query("end_date", "")
 	user := c.Locals("user").(*jwt.Claims)
 
+	format, err := export.Requested(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid export format outstanding material request")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
 	param := &pagination.PaginationParam{}
-	if pageStr != "" && pageSizeStr != "" {
+	if pageStr != "" && pageSizeStr != "" && format == "" {
 		page, err := strconv.Atoi(pageStr)
 		if err != nil {
 			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input outstanding material request")
@@ -253,6 +268,11 @@ func (h *TblMaterialRequestHandler) OutstandingMaterial(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
 	}
 
+	if format != "" {
+		go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Export %s outstanding material request", format))
+		return export.Send(c, format, "outstanding-material-request", result.Data)
+	}
+
 	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all outstanding material requests")
 
 	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
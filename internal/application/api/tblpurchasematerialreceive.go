@@ -15,6 +15,7 @@ import (
 
 	// "gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/export"
 	"gitlab.com/ayaka/internal/pkg/custommiddleware"
 	"gitlab.com/ayaka/internal/pkg/formatter"
 	"gitlab.com/ayaka/internal/pkg/jwt"
@@ -109,6 +110,22 @@ func (h *TblPurchaseMaterialReceiveHandler) Create(c *fiber.Ctx) error {
 
 	result, err := h.Service.Create(c.Context(), req, user.UserName)
 	if err != nil {
+		if errors.Is(err, customerrors.ErrWarehouseNotAllowed) || errors.Is(err, customerrors.ErrSiteNotAllowed) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Forbidden create: %s", err.Error()))
+			return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have access to this warehouse", ""))
+		}
+		if errors.Is(err, customerrors.ErrWarehouseFrozen) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Warehouse frozen: %s", err.Error()))
+			return c.Status(fiber.StatusConflict).JSON(formatter.NewErrorResponse(formatter.DataConflict, "Warehouse is being counted in an open stock opname", ""))
+		}
+		if errors.Is(err, customerrors.ErrJournalAccount) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Journal account: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		}
+		if errors.Is(err, customerrors.ErrUomConversion) || errors.Is(err, customerrors.ErrExchangeRate) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Failed create purchase material receive: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		}
 		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error create item: %s", err.Error()))
 		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to create purchase material receive", ""))
 	}
@@ -143,6 +160,18 @@ func (h *TblPurchaseMaterialReceiveHandler) Update(c *fiber.Ctx) error {
 
 	result, err := h.Service.Update(c.Context(), req, user.UserCode)
 	if err != nil {
+		if errors.Is(err, customerrors.ErrWarehouseNotAllowed) || errors.Is(err, customerrors.ErrSiteNotAllowed) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Forbidden update: %s", err.Error()))
+			return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have access to this warehouse", ""))
+		}
+		if errors.Is(err, customerrors.ErrWarehouseFrozen) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Warehouse frozen: %s", err.Error()))
+			return c.Status(fiber.StatusConflict).JSON(formatter.NewErrorResponse(formatter.DataConflict, "Warehouse is being counted in an open stock opname", ""))
+		}
+		if errors.Is(err, customerrors.ErrJournalAccount) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Journal account: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		}
 		if errors.Is(err, customerrors.ErrNoDataEdited) {
 			go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Update data purchase material receive %s", req.DocNo))
 			return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
@@ -171,8 +200,14 @@ func (h *TblPurchaseMaterialReceiveHandler) Reporting(c *fiber.Ctx) error {
 	endDate := c.Query("end_date", "")
 	user := c.Locals("user").(*jwt.Claims)
 
+	format, err := export.Requested(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid export format purchase material receive report")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
 	param := &pagination.PaginationParam{}
-	if pageStr != "" && pageSizeStr != "" {
+	if pageStr != "" && pageSizeStr != "" && format == "" {
 		page, err := strconv.Atoi(pageStr)
 		if err != nil {
 			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input purchase material receive")
@@ -208,6 +243,11 @@ func (h *TblPurchaseMaterialReceiveHandler) Reporting(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
 	}
 
+	if format != "" {
+		go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Export %s purchase material receive report", format))
+		return export.Send(c, format, "purchase-material-receive-report", result.Data)
+	}
+
 	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all purchase material receives")
 
 	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
@@ -12,6 +12,7 @@ import (
 
 	// "gitlab.com/ayaka/internal/domain/stockMovement"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/export"
 	"gitlab.com/ayaka/internal/pkg/custommiddleware"
 	"gitlab.com/ayaka/internal/pkg/formatter"
 	"gitlab.com/ayaka/internal/pkg/jwt"
@@ -40,10 +41,17 @@ func (h *TblStockMovementHandler) Fetch(c *fiber.Ctx) error {
 	itemCatCode := c.Query("item_category", "")
 	batch := c.Query("batch", "")
 	itemName := c.Query("item_name", "")
+	uom := c.Query("uom", "")
 	user := c.Locals("user").(*jwt.Claims)
 
+	format, err := export.Requested(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid export format stock movement")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
 	param := &pagination.PaginationParam{}
-	if pageStr != "" && pageSizeStr != "" {
+	if pageStr != "" && pageSizeStr != "" && format == "" {
 		page, err := strconv.Atoi(pageStr)
 		if err != nil {
 			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input stock Movement")
@@ -72,8 +80,12 @@ func (h *TblStockMovementHandler) Fetch(c *fiber.Ctx) error {
 		param = nil
 	}
 
-	result, err := h.Service.Fetch(c.Context(), warehouse, dateRangeStart, dateRangeEnd, docType, itemCatCode, itemName, batch, param)
+	result, err := h.Service.Fetch(c.Context(), warehouse, dateRangeStart, dateRangeEnd, docType, itemCatCode, itemName, batch, uom, param)
 	if err != nil {
+		if errors.Is(err, customerrors.ErrUomConversion) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Stock movement uom: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		}
 		if errors.Is(err, customerrors.ErrInvalidArrayFormat) {
 			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid warehouse input stock Movement")
 			return c.Status(fiber.StatusBadRequest).JSON(formatter.InvalidRequest, customerrors.ErrInvalidArrayFormat.Error())
@@ -82,6 +94,11 @@ func (h *TblStockMovementHandler) Fetch(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
 	}
 
+	if format != "" {
+		go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Export %s stock movement", format))
+		return export.Send(c, format, "stock-movement", result.Data)
+	}
+
 	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all stock Movement")
 
 	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
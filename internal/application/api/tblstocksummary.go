@@ -12,6 +12,7 @@ import (
 
 	// "gitlab.com/ayaka/internal/domain/stocksummary"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/export"
 	"gitlab.com/ayaka/internal/pkg/custommiddleware"
 	"gitlab.com/ayaka/internal/pkg/formatter"
 	"gitlab.com/ayaka/internal/pkg/jwt"
@@ -38,10 +39,17 @@ func (h *TblStockSummaryHandler) Fetch(c *fiber.Ctx) error {
 	itemCatCode := c.Query("item_category", "")
 	itemCode := c.Query("item_code", "")
 	itemName := c.Query("item_name", "")
+	uom := c.Query("uom", "")
 	user := c.Locals("user").(*jwt.Claims)
 
+	format, err := export.Requested(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid export format stock summary")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
 	param := &pagination.PaginationParam{}
-	if pageStr != "" && pageSizeStr != "" {
+	if pageStr != "" && pageSizeStr != "" && format == "" {
 		page, err := strconv.Atoi(pageStr)
 		if err != nil {
 			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input stock summary")
@@ -70,16 +78,25 @@ func (h *TblStockSummaryHandler) Fetch(c *fiber.Ctx) error {
 		param = nil
 	}
 
-	result, err := h.Service.Fetch(c.Context(), warehouse, date, itemCatCode, itemCode, itemName, param)
+	result, err := h.Service.Fetch(c.Context(), warehouse, date, itemCatCode, itemCode, itemName, uom, param)
 	if err != nil {
 		if errors.Is(err, customerrors.ErrInvalidArrayFormat) {
 			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid warehouse input stock summary")
 			return c.Status(fiber.StatusBadRequest).JSON(formatter.InvalidRequest, customerrors.ErrInvalidArrayFormat.Error())
 		}
+		if errors.Is(err, customerrors.ErrUomConversion) {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Stock summary uom: %s", err.Error()))
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+		}
 		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error fetch stock summary: %s", err.Error()))
 		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
 	}
 
+	if format != "" {
+		go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Export %s stock summary", format))
+		return export.Send(c, format, "stock-summary", result.Data)
+	}
+
 	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all stock summary")
 
 	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
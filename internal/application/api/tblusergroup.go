@@ -0,0 +1,184 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"gitlab.com/ayaka/internal/application/service"
+	"gitlab.com/ayaka/internal/domain/tblusergroup"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/custommiddleware"
+	"gitlab.com/ayaka/internal/pkg/formatter"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+	"gitlab.com/ayaka/internal/pkg/validator"
+)
+
+type TblUserGroupApi interface {
+	Fetch(c *fiber.Ctx) error
+	Detail(c *fiber.Ctx) error
+	Create(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	AssignUser(c *fiber.Ctx) error
+}
+
+type TblUserGroupHandler struct {
+	Service   service.TblUserGroupService          `inject:"tblUserGroupService"`
+	Validator validator.Validator                  `inject:"validator"`
+	Log       *custommiddleware.LogActivityHandler `inject:"logActivity"`
+}
+
+func (h *TblUserGroupHandler) Fetch(c *fiber.Ctx) error {
+	pageStr := c.Query("page", "")
+	pageSizeStr := c.Query("page_size", "")
+	search := c.Query("search")
+	user := c.Locals("user").(*jwt.Claims)
+
+	param := &pagination.PaginationParam{}
+	if pageStr != "" && pageSizeStr != "" {
+		page, err := strconv.Atoi(pageStr)
+		if err != nil {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input user group")
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Invalid Page", ""))
+		}
+
+		pageSize, err := strconv.Atoi(pageSizeStr)
+		if err != nil {
+			go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page size input user group")
+			return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, "Invalid Page Size", ""))
+		}
+
+		// Validasi nilai
+		if page < 1 {
+			page = 1
+		}
+		if pageSize < 1 {
+			pageSize = 10
+		}
+
+		param = &pagination.PaginationParam{
+			Page:     page,
+			PageSize: pageSize,
+		}
+	} else {
+		param = nil
+	}
+
+	result, err := h.Service.Fetch(c.Context(), search, param)
+
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error fetch user group: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all user group")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *TblUserGroupHandler) Detail(c *fiber.Ctx) error {
+	user := c.Locals("user").(*jwt.Claims)
+	code := c.Params("code")
+
+	result, err := h.Service.Detail(c.Context(), code)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrDataNotFound) {
+			go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Detail user group %s not found", code))
+			return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "User group not found", ""))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error detail user group: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Detail user group %s", code))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *TblUserGroupHandler) Create(c *fiber.Ctx) error {
+	var req *tblusergroup.Create
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse create user group: %s", err.Error()))
+		return err
+	}
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate create user group: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to create user group", err.Error()))
+	}
+
+	result, err := h.Service.Create(c.Context(), req, user.UserName)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error create user group: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Failed to create user group", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Create user group %s", req.GroupCode))
+
+	return c.Status(fiber.StatusCreated).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *TblUserGroupHandler) Update(c *fiber.Ctx) error {
+	var req *tblusergroup.Update
+
+	code := c.Params("code")
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse update user group: %s", err.Error()))
+		return err
+	}
+	req.GroupCode = code
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate update user group: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to Update user group", err.Error()))
+	}
+
+	result, err := h.Service.Update(c.Context(), req, user.UserCode)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error update user group: %s", err.Error()))
+		return err
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Update data user group %s", req.GroupCode))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+// AssignUser memindahkan user ke group, permission baru berlaku setelah user login ulang.
+func (h *TblUserGroupHandler) AssignUser(c *fiber.Ctx) error {
+	var req *tblusergroup.AssignUser
+
+	code := c.Params("code")
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse assign user group: %s", err.Error()))
+		return err
+	}
+	req.GroupCode = code
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate assign user group: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to assign user group", err.Error()))
+	}
+
+	result, err := h.Service.AssignUser(c.Context(), req, user.UserCode)
+	if err != nil {
+		if errors.Is(err, customerrors.ErrNoDataEdited) {
+			go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Assign user group %s", req.GroupCode))
+			return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+		}
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error assign user group: %s", err.Error()))
+		return err
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Assign user group %s", req.GroupCode))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gitlab.com/ayaka/internal/application/service"
+	"gitlab.com/ayaka/internal/domain/vendorinvoice"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/custommiddleware"
+	"gitlab.com/ayaka/internal/pkg/export"
+	"gitlab.com/ayaka/internal/pkg/formatter"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+	"gitlab.com/ayaka/internal/pkg/validator"
+)
+
+type VendorInvoiceApi interface {
+	Fetch(c *fiber.Ctx) error
+	Detail(c *fiber.Ctx) error
+	Create(c *fiber.Ctx) error
+	Payables(c *fiber.Ctx) error
+}
+
+type VendorInvoiceHandler struct {
+	Service   service.VendorInvoiceService         `inject:"vendorInvoiceService"`
+	Validator validator.Validator                  `inject:"validator"`
+	Log       *custommiddleware.LogActivityHandler `inject:"logActivity"`
+}
+
+func (h *VendorInvoiceHandler) Fetch(c *fiber.Ctx) error {
+	docNo := c.Query("document_number", "")
+	vendor := c.Query("vendor", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	param, err := pageParam(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid page input vendor invoice")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	result, err := h.Service.Fetch(c.Context(), docNo, vendor, param)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error fetch vendor invoice: %s", err.Error()))
+		return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, "Internal Server Error", ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch all vendor invoice")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *VendorInvoiceHandler) Detail(c *fiber.Ctx) error {
+	docNo := strings.ReplaceAll(c.Params("code"), "-", "/")
+	user := c.Locals("user").(*jwt.Claims)
+
+	result, err := h.Service.Detail(c.Context(), docNo)
+	if err != nil {
+		return h.failed(c, user, "detail vendor invoice", err)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Get detail vendor invoice %s", docNo))
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+// Create invoice tetap tersimpan walau tidak match, payment_block pada
+// response menandakan invoice belum boleh dibayar
+func (h *VendorInvoiceHandler) Create(c *fiber.Ctx) error {
+	var req *vendorinvoice.Invoice
+	user := c.Locals("user").(*jwt.Claims)
+
+	if err := c.BodyParser(&req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error parse vendor invoice: %s", err.Error()))
+		return err
+	}
+
+	if err := h.Validator.Validate(c.Context(), req); err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Error validate vendor invoice: %s", err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorFieldResponse(formatter.InvalidRequest, "Failed to create vendor invoice", err.Error()))
+	}
+
+	result, err := h.Service.Create(c.Context(), req, user.UserName)
+	if err != nil {
+		return h.failed(c, user, "create vendor invoice", err)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Create vendor invoice %s", result.DocNo))
+
+	return c.Status(fiber.StatusCreated).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+// Payables hutang terbuka per vendor, due_date membatasi jatuh tempo sampai tanggal tersebut
+func (h *VendorInvoiceHandler) Payables(c *fiber.Ctx) error {
+	vendor := c.Query("vendor", "")
+	dueDate := c.Query("due_date", "")
+	user := c.Locals("user").(*jwt.Claims)
+
+	format, err := export.Requested(c)
+	if err != nil {
+		go h.Log.LogUserInfo(user.UserCode, "WARN", "Invalid export format open payables")
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	result, err := h.Service.Payables(c.Context(), vendor, dueDate)
+	if err != nil {
+		return h.failed(c, user, "fetch open payables", err)
+	}
+
+	if format != "" {
+		go h.Log.LogUserInfo(user.UserCode, "INFO", fmt.Sprintf("Export %s open payables", format))
+		return export.Send(c, format, "open-payables", result)
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "INFO", "Fetch open payables")
+
+	return c.Status(fiber.StatusOK).JSON(formatter.NewSuccessResponse(formatter.Success, result))
+}
+
+func (h *VendorInvoiceHandler) failed(c *fiber.Ctx, user *jwt.Claims, action string, err error) error {
+	switch {
+	case errors.Is(err, customerrors.ErrDataNotFound):
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Data not found on %s", action))
+		return c.Status(fiber.StatusNotFound).JSON(formatter.NewErrorResponse(formatter.DataNotFound, "Data not found", ""))
+	case errors.Is(err, customerrors.ErrInvalidInput):
+		go h.Log.LogUserInfo(user.UserCode, "WARN", fmt.Sprintf("Failed %s: %s", action, err.Error()))
+		return c.Status(fiber.StatusBadRequest).JSON(formatter.NewErrorResponse(formatter.InvalidRequest, err.Error(), ""))
+	}
+
+	go h.Log.LogUserInfo(user.UserCode, "ERROR", fmt.Sprintf("Internal server error %s: %s", action, err.Error()))
+	return c.Status(fiber.StatusInternalServerError).JSON(formatter.NewErrorResponse(formatter.InternalServerError, fmt.Sprintf("Failed to %s", action), ""))
+}
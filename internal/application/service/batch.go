@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/internal/domain/batch"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	sharedfunc "gitlab.com/ayaka/internal/domain/shared/sharedFunc"
+	"gitlab.com/ayaka/internal/domain/uomconversion"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+// defaultHorizons dipakai kalau laporan expiring stock tidak diberi horizons
+const defaultHorizons = "30,60,90"
+
+type BatchService interface {
+	Fetch(ctx context.Context, itemCode, batchNo string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Save(ctx context.Context, data *batch.Save, userName string) (*batch.Save, error)
+	Expiring(ctx context.Context, warehouse, horizons, uom string) (*batch.ExpiringReport, error)
+}
+
+type Batch struct {
+	TemplateRepo batch.Repository         `inject:"batchRepository"`
+	Uom          uomconversion.Repository `inject:"uomConversionRepository"`
+}
+
+func (s *Batch) Fetch(ctx context.Context, itemCode, batchNo string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	return s.TemplateRepo.Fetch(ctx, itemCode, batchNo, param)
+}
+
+func (s *Batch) Save(ctx context.Context, data *batch.Save, userName string) (*batch.Save, error) {
+	data.CreateBy = userName
+	data.CreateDate = time.Now().Format("200601021504")
+
+	for _, dt := range []*string{&data.ManufacturingDate.String, &data.ExpiredDate.String} {
+		if *dt == "" {
+			continue
+		}
+		compact, err := share.FormatToCompactDateTime(*dt)
+		if err != nil {
+			return nil, customerrors.ErrInvalidInput
+		}
+		*dt = compact
+	}
+	data.ManufacturingDate.SetNullIfEmpty()
+	data.ExpiredDate.SetNullIfEmpty()
+	data.VendorLot.SetNullIfEmpty()
+	data.Remark.SetNullIfEmpty()
+
+	if data.ManufacturingDate.Valid && data.ExpiredDate.Valid && data.ExpiredDate.String < data.ManufacturingDate.String {
+		return nil, customerrors.ErrInvalidInput
+	}
+
+	res, err := s.TemplateRepo.Save(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error save batch: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Expiring mengelompokkan stok per horizon (hari dari hari ini). Batch yang
+// sudah lewat masuk bucket "expired", sisanya ke horizon terkecil yang memuat.
+// Stok dalam UoM inventory kecuali uom diisi.
+func (s *Batch) Expiring(ctx context.Context, warehouse, horizons, uom string) (*batch.ExpiringReport, error) {
+	if horizons == "" {
+		horizons = defaultHorizons
+	}
+
+	days, err := parseHorizons(horizons)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	until := today.AddDate(0, 0, days[len(days)-1]).Format("20060102")
+
+	lines, err := s.TemplateRepo.Expiring(ctx, warehouse, until)
+	if err != nil {
+		return nil, err
+	}
+	if uom != "" {
+		itemCodes := make([]string, 0, len(lines))
+		for _, l := range lines {
+			itemCodes = append(itemCodes, l.ItemCode)
+		}
+		uomName, factors, err := s.Uom.ToUom(ctx, sharedfunc.UniqueStringSlice(itemCodes), uom)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range lines {
+			l.Stock *= factors[l.ItemCode]
+			l.UomName = uomName
+		}
+	}
+
+	report := &batch.ExpiringReport{
+		Date:     share.FormatDate(today.Format("20060102")),
+		Horizons: days,
+		Buckets:  []*batch.Bucket{{Name: "expired"}},
+		Lines:    lines,
+	}
+	buckets := map[string]*batch.Bucket{"expired": report.Buckets[0]}
+	for _, d := range days {
+		b := &batch.Bucket{Name: fmt.Sprintf("<= %d days", d)}
+		report.Buckets = append(report.Buckets, b)
+		buckets[b.Name] = b
+	}
+
+	for _, l := range lines {
+		exp, err := time.ParseInLocation("20060102", l.ExpiredDate, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expired date %s batch %s: %w", l.ItemCode, l.Batch, err)
+		}
+		l.DaysLeft = int(exp.Sub(today).Hours() / 24)
+		l.ExpiredDate = share.FormatDate(l.ExpiredDate)
+
+		l.Bucket = "expired"
+		if l.DaysLeft >= 0 {
+			for _, d := range days {
+				if l.DaysLeft <= d {
+					l.Bucket = fmt.Sprintf("<= %d days", d)
+					break
+				}
+			}
+		}
+		buckets[l.Bucket].Lines++
+		buckets[l.Bucket].Stock += l.Stock
+	}
+
+	return report, nil
+}
+
+// parseHorizons membaca "30,60,90" menjadi daftar hari yang unik dan terurut
+func parseHorizons(horizons string) ([]int, error) {
+	seen := map[int]bool{}
+	var days []int
+	for _, h := range strings.Split(horizons, ",") {
+		d, err := strconv.Atoi(strings.TrimSpace(h))
+		if err != nil || d <= 0 {
+			return nil, customerrors.ErrInvalidInput
+		}
+		if !seen[d] {
+			seen[d] = true
+			days = append(days, d)
+		}
+	}
+	sort.Ints(days)
+
+	return days, nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/internal/domain/bin"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type BinService interface {
+	Fetch(ctx context.Context, warehouse, search string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Detail(ctx context.Context, code string) (*bin.Read, error)
+	Create(ctx context.Context, data *bin.Create, userName string) (*bin.Create, error)
+	Update(ctx context.Context, data *bin.Update, userName string) (*bin.Update, error)
+	Stock(ctx context.Context, warehouse, binCode, itemCode string) ([]*bin.Stock, error)
+	PutAway(ctx context.Context, docType, docNo string) (*bin.PutAway, error)
+	PickList(ctx context.Context, docType, docNo string) (*bin.PickList, error)
+	FetchTransfer(ctx context.Context, doc, warehouse string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	DetailTransfer(ctx context.Context, docNo string) (*bin.Transfer, error)
+	CreateTransfer(ctx context.Context, data *bin.Transfer, userName string) (*bin.Transfer, error)
+}
+
+type Bin struct {
+	TemplateRepo bin.Repository `inject:"binRepository"`
+}
+
+func (s *Bin) Fetch(ctx context.Context, warehouse, search string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	return s.TemplateRepo.Fetch(ctx, warehouse, search, param)
+}
+
+func (s *Bin) Detail(ctx context.Context, code string) (*bin.Read, error) {
+	return s.TemplateRepo.Detail(ctx, code)
+}
+
+func (s *Bin) Create(ctx context.Context, data *bin.Create, userName string) (*bin.Create, error) {
+	// "-" dipakai sebagai penanda stok yang belum di-put-away
+	if data.BinCode == bin.Unassigned {
+		return nil, customerrors.ErrInvalidInput
+	}
+
+	data.CreateBy = userName
+	data.CreateDate = time.Now().Format("200601021504")
+	data.Remark.SetNullIfEmpty()
+
+	res, err := s.TemplateRepo.Create(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error create bin: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *Bin) Update(ctx context.Context, data *bin.Update, userName string) (*bin.Update, error) {
+	data.LastUpBy = userName
+	data.LastUpdateDate = time.Now().Format("200601021504")
+	data.Remark.SetNullIfEmpty()
+
+	res, err := s.TemplateRepo.Update(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error update bin: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *Bin) Stock(ctx context.Context, warehouse, binCode, itemCode string) ([]*bin.Stock, error) {
+	if warehouse == "" && binCode == "" && itemCode == "" {
+		return nil, customerrors.ErrInvalidInput
+	}
+
+	return s.TemplateRepo.Stock(ctx, warehouse, binCode, itemCode)
+}
+
+func (s *Bin) PutAway(ctx context.Context, docType, docNo string) (*bin.PutAway, error) {
+	return s.TemplateRepo.PutAway(ctx, docType, docNo)
+}
+
+func (s *Bin) PickList(ctx context.Context, docType, docNo string) (*bin.PickList, error) {
+	return s.TemplateRepo.PickList(ctx, docType, docNo)
+}
+
+func (s *Bin) FetchTransfer(ctx context.Context, doc, warehouse string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	return s.TemplateRepo.FetchTransfer(ctx, doc, warehouse, param)
+}
+
+func (s *Bin) DetailTransfer(ctx context.Context, docNo string) (*bin.Transfer, error) {
+	return s.TemplateRepo.DetailTransfer(ctx, docNo)
+}
+
+func (s *Bin) CreateTransfer(ctx context.Context, data *bin.Transfer, userName string) (*bin.Transfer, error) {
+	data.CreateBy = userName
+	data.CreateDate = time.Now().Format("200601021504")
+
+	var err error
+	if data.Date, err = share.FormatToCompactDateTime(data.Date); err != nil {
+		return nil, customerrors.ErrInvalidInput
+	}
+
+	data.Remark.SetNullIfEmpty()
+	for i := range data.Details {
+		data.Details[i].Remark.SetNullIfEmpty()
+	}
+
+	res, err := s.TemplateRepo.CreateTransfer(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error create bin transfer: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 
+	"gitlab.com/ayaka/config"
 	"gitlab.com/ayaka/internal/domain/dashboard"
 )
 
@@ -12,8 +13,9 @@ type DashboardService interface {
 
 type Dashboard struct {
 	TemplateRepo dashboard.Repository `inject:"DashboardRepository"`
+	Conf         *config.Config       `inject:"config"`
 }
 
 func (s *Dashboard) Fetch(ctx context.Context) (*dashboard.Read, error) {
-	return s.TemplateRepo.Fetch(ctx)
+	return s.TemplateRepo.Fetch(ctx, s.Conf.Currency.Base)
 }
\ No newline at end of file
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/internal/domain/docapproval"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type DocApprovalService interface {
+	Detail(ctx context.Context, docType, docNo string) (*docapproval.Detail, error)
+	Act(ctx context.Context, data *docapproval.Act, userCode string) (*docapproval.Act, error)
+	Resubmit(ctx context.Context, docType, docNo, userCode string) (*docapproval.Detail, error)
+	Inbox(ctx context.Context, userCode string) ([]*docapproval.Inbox, error)
+	FetchRule(ctx context.Context, search string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	DetailRule(ctx context.Context, code string) (*docapproval.Rule, error)
+	CreateRule(ctx context.Context, data *docapproval.CreateRule, userName string) (*docapproval.CreateRule, error)
+	UpdateRule(ctx context.Context, data *docapproval.UpdateRule, userCode string) (*docapproval.UpdateRule, error)
+}
+
+type DocApproval struct {
+	TemplateRepo docapproval.Repository `inject:"docApprovalRepository"`
+}
+
+func (s *DocApproval) Detail(ctx context.Context, docType, docNo string) (*docapproval.Detail, error) {
+	return s.TemplateRepo.Detail(ctx, docType, docNo)
+}
+
+func (s *DocApproval) Act(ctx context.Context, data *docapproval.Act, userCode string) (*docapproval.Act, error) {
+	data.UserCode = userCode
+	data.Date = time.Now().Format("200601021504")
+
+	res, err := s.TemplateRepo.Act(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error approval action: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *DocApproval) Resubmit(ctx context.Context, docType, docNo, userCode string) (*docapproval.Detail, error) {
+	if err := s.TemplateRepo.Resubmit(ctx, docType, docNo, userCode, time.Now().Format("200601021504")); err != nil {
+		golog.Error(ctx, "Error resubmit approval: "+err.Error(), err)
+		return nil, err
+	}
+
+	return s.TemplateRepo.Detail(ctx, docType, docNo)
+}
+
+func (s *DocApproval) Inbox(ctx context.Context, userCode string) ([]*docapproval.Inbox, error) {
+	return s.TemplateRepo.Inbox(ctx, userCode)
+}
+
+func (s *DocApproval) FetchRule(ctx context.Context, search string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	return s.TemplateRepo.FetchRule(ctx, search, param)
+}
+
+func (s *DocApproval) DetailRule(ctx context.Context, code string) (*docapproval.Rule, error) {
+	return s.TemplateRepo.DetailRule(ctx, code)
+}
+
+func (s *DocApproval) CreateRule(ctx context.Context, data *docapproval.CreateRule, userName string) (*docapproval.CreateRule, error) {
+	data.CreateBy = userName
+	data.CreateDt = time.Now().Format("200601021504")
+
+	res, err := s.TemplateRepo.CreateRule(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error create approval rule: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *DocApproval) UpdateRule(ctx context.Context, data *docapproval.UpdateRule, userCode string) (*docapproval.UpdateRule, error) {
+	data.LastUpBy = userCode
+	data.LastUpDt = time.Now().Format("200601021504")
+
+	res, err := s.TemplateRepo.UpdateRule(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error update approval rule: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
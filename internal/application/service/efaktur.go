@@ -0,0 +1,122 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/config"
+	"gitlab.com/ayaka/internal/domain/efaktur"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type EfakturService interface {
+	FetchRange(ctx context.Context, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	CreateRange(ctx context.Context, data *efaktur.Range, userName string) (*efaktur.Range, error)
+	Export(ctx context.Context, startDate, endDate, userName string) (*efaktur.Export, error)
+	Reexport(ctx context.Context, batch string) (*efaktur.Export, error)
+}
+
+type Efaktur struct {
+	TemplateRepo efaktur.Repository `inject:"efakturRepository"`
+	Conf         *config.Config     `inject:"config"`
+}
+
+func (s *Efaktur) FetchRange(ctx context.Context, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	return s.TemplateRepo.FetchRange(ctx, param)
+}
+
+func (s *Efaktur) CreateRange(ctx context.Context, data *efaktur.Range, userName string) (*efaktur.Range, error) {
+	data.CreateBy = userName
+	data.CreateDate = time.Now().Format("200601021504")
+
+	res, err := s.TemplateRepo.CreateRange(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error create tax invoice range: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Export memberi nomor faktur ke dokumen yang belum diexport. File yang sama
+// bisa diambil lagi lewat Reexport dengan Batch hasil export ini.
+func (s *Efaktur) Export(ctx context.Context, startDate, endDate, userName string) (*efaktur.Export, error) {
+	seller, err := s.seller()
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := share.FormatToCompactDateTime(startDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid start date", customerrors.ErrInvalidInput)
+	}
+	end, err := share.FormatToCompactDateTime(endDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid end date", customerrors.ErrInvalidInput)
+	}
+
+	invoices, err := s.TemplateRepo.Export(ctx, start, end, userName, time.Now().Format("200601021504"))
+	if err != nil {
+		golog.Error(ctx, "Error export e-Faktur: "+err.Error(), err)
+		return nil, err
+	}
+	if len(invoices) == 0 {
+		return nil, customerrors.ErrDataNotFound
+	}
+
+	return efakturFile(seller, invoices, fmt.Sprintf("efaktur-%s-%s-%s.csv", seller.NPWP, start, end))
+}
+
+// Reexport membuat ulang file satu batch export, misalnya kalau file pertama
+// gagal diimport, tanpa memakai nomor faktur baru
+func (s *Efaktur) Reexport(ctx context.Context, batch string) (*efaktur.Export, error) {
+	seller, err := s.seller()
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := s.TemplateRepo.Reexport(ctx, batch)
+	if err != nil {
+		golog.Error(ctx, "Error re-export e-Faktur: "+err.Error(), err)
+		return nil, err
+	}
+	if len(invoices) == 0 {
+		return nil, customerrors.ErrDataNotFound
+	}
+
+	return efakturFile(seller, invoices, fmt.Sprintf("efaktur-%s-%s.csv", seller.NPWP, batch))
+}
+
+// seller penjual dari config company. NPWP penjual tidak ada di baris FK
+// (FK membawa NPWP pembeli), tapi dipakai di nama file dan harus sama dengan
+// NPWP yang login di aplikasi e-Faktur.
+func (s *Efaktur) seller() (efaktur.Seller, error) {
+	seller := efaktur.Seller{
+		Name:    s.Conf.Company.Name,
+		NPWP:    s.Conf.Company.NPWP,
+		Address: s.Conf.Company.Address,
+	}
+	if seller.NPWP == "" || seller.Name == "" {
+		return seller, fmt.Errorf("%w: company name and NPWP are not configured", customerrors.ErrInvalidInput)
+	}
+	return seller, nil
+}
+
+func efakturFile(seller efaktur.Seller, invoices []efaktur.Invoice, fileName string) (*efaktur.Export, error) {
+	var buf bytes.Buffer
+	if err := efaktur.Write(&buf, seller, invoices); err != nil {
+		return nil, fmt.Errorf("error writing e-Faktur csv: %w", err)
+	}
+
+	return &efaktur.Export{
+		Batch:    invoices[0].TaxInvoiceNo,
+		FileName: fileName,
+		Count:    len(invoices),
+		Content:  buf.Bytes(),
+	}, nil
+}
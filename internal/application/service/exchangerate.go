@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/internal/domain/exchangerate"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type ExchangeRateService interface {
+	Fetch(ctx context.Context, curCode, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Save(ctx context.Context, data *exchangerate.Rate, userName string) (*exchangerate.Rate, error)
+	Delete(ctx context.Context, curCode1, curCode2, date string) error
+}
+
+type ExchangeRate struct {
+	TemplateRepo exchangerate.Repository `inject:"exchangeRateRepository"`
+}
+
+func (s *ExchangeRate) Fetch(ctx context.Context, curCode, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	if startDate != "" && endDate != "" {
+		var err error
+		if startDate, err = share.FormatToCompactDateTime(startDate); err != nil {
+			return nil, customerrors.ErrInvalidInput
+		}
+		if endDate, err = share.FormatToCompactDateTime(endDate); err != nil {
+			return nil, customerrors.ErrInvalidInput
+		}
+	}
+
+	return s.TemplateRepo.Fetch(ctx, curCode, startDate, endDate, param)
+}
+
+func (s *ExchangeRate) Save(ctx context.Context, data *exchangerate.Rate, userName string) (*exchangerate.Rate, error) {
+	if err := prepareRate(data, userName); err != nil {
+		return nil, err
+	}
+
+	res, err := s.TemplateRepo.Save(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error save exchange rate: "+err.Error(), err)
+		return nil, err
+	}
+	res.Date = share.ToDatePicker(res.Date)
+
+	return res, nil
+}
+
+func (s *ExchangeRate) Delete(ctx context.Context, curCode1, curCode2, date string) error {
+	date, err := share.FormatToCompactDateTime(date)
+	if err != nil {
+		return customerrors.ErrInvalidInput
+	}
+
+	return s.TemplateRepo.Delete(ctx, curCode1, curCode2, date)
+}
+
+// prepareRate dipakai juga oleh import, tanggal dari yyyy-mm-dd ke yyyymmdd
+func prepareRate(data *exchangerate.Rate, userName string) error {
+	date, err := share.FormatToCompactDateTime(data.Date)
+	if err != nil {
+		return customerrors.ErrInvalidInput
+	}
+	data.Date = date
+	data.CreateBy = userName
+	data.CreateDate = time.Now().Format("200601021504")
+
+	return nil
+}
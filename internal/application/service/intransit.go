@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/internal/domain/intransit"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	sharedfunc "gitlab.com/ayaka/internal/domain/shared/sharedFunc"
+	"gitlab.com/ayaka/internal/domain/uomconversion"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+// defaultAgingHorizons umur (hari) barang di jalan kalau laporan tidak diberi horizons
+const defaultAgingHorizons = "7,14,30"
+
+type InTransitService interface {
+	Report(ctx context.Context, warehouseFrom, warehouseTo, horizons, uom string) (*intransit.Report, error)
+	FetchDiscrepancy(ctx context.Context, status, warehouseFrom, warehouseTo string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Resolve(ctx context.Context, data *intransit.Resolve, userName string) (*intransit.Discrepancy, error)
+}
+
+type InTransit struct {
+	TemplateRepo intransit.Repository     `inject:"inTransitRepository"`
+	Uom          uomconversion.Repository `inject:"uomConversionRepository"`
+}
+
+// Report mengelompokkan sisa barang di jalan per umur sejak tanggal transfer,
+// ke horizon terkecil yang memuat, sisanya ke bucket "> n days". Qty dalam
+// UoM inventory kecuali uom diisi.
+func (s *InTransit) Report(ctx context.Context, warehouseFrom, warehouseTo, horizons, uom string) (*intransit.Report, error) {
+	if horizons == "" {
+		horizons = defaultAgingHorizons
+	}
+
+	days, err := parseHorizons(horizons)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := s.TemplateRepo.Stock(ctx, warehouseFrom, warehouseTo)
+	if err != nil {
+		return nil, err
+	}
+	if uom != "" {
+		itemCodes := make([]string, 0, len(lines))
+		for _, l := range lines {
+			itemCodes = append(itemCodes, l.ItCode)
+		}
+		uomName, factors, err := s.Uom.ToUom(ctx, sharedfunc.UniqueStringSlice(itemCodes), uom)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range lines {
+			factor := factors[l.ItCode]
+			l.Qty *= factor
+			l.QtyReceived *= factor
+			l.QtyInTransit *= factor
+			l.UomName = uomName
+		}
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	report := &intransit.Report{
+		Date:     share.FormatDate(today.Format("20060102")),
+		Horizons: days,
+		Lines:    lines,
+	}
+	buckets := make(map[string]*intransit.Bucket)
+	for _, d := range days {
+		b := &intransit.Bucket{Name: fmt.Sprintf("<= %d days", d)}
+		report.Buckets = append(report.Buckets, b)
+		buckets[b.Name] = b
+	}
+	older := &intransit.Bucket{Name: fmt.Sprintf("> %d days", days[len(days)-1])}
+	report.Buckets = append(report.Buckets, older)
+	buckets[older.Name] = older
+
+	for _, l := range lines {
+		docDt, err := time.ParseInLocation("20060102", l.Date, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date material transfer %s: %w", l.DocNo, err)
+		}
+		// dibulatkan supaya pergantian DST tidak mengurangi satu hari
+		l.AgeDays = int(math.Round(today.Sub(docDt).Hours() / 24))
+		l.Date = share.FormatDate(l.Date)
+
+		l.Bucket = older.Name
+		for _, d := range days {
+			if l.AgeDays <= d {
+				l.Bucket = fmt.Sprintf("<= %d days", d)
+				break
+			}
+		}
+		buckets[l.Bucket].Lines++
+		buckets[l.Bucket].Qty += l.QtyInTransit
+	}
+
+	return report, nil
+}
+
+func (s *InTransit) FetchDiscrepancy(ctx context.Context, status, warehouseFrom, warehouseTo string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	return s.TemplateRepo.FetchDiscrepancy(ctx, status, warehouseFrom, warehouseTo, param)
+}
+
+func (s *InTransit) Resolve(ctx context.Context, data *intransit.Resolve, userName string) (*intransit.Discrepancy, error) {
+	data.ResolveBy = userName
+	data.ResolveDt = time.Now().Format("200601021504")
+	data.Remark.SetNullIfEmpty()
+
+	res, err := s.TemplateRepo.Resolve(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error resolve discrepancy: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/ayaka/internal/domain/intransit"
+)
+
+// fakeInTransitRepo mengembalikan baris transit tetap untuk laporan aging
+type fakeInTransitRepo struct {
+	intransit.Repository
+	lines []*intransit.Stock
+}
+
+func (f *fakeInTransitRepo) Stock(ctx context.Context, warehouseFrom, warehouseTo string) ([]*intransit.Stock, error) {
+	return f.lines, nil
+}
+
+func transitStock(docNo string, age int, qty float32) *intransit.Stock {
+	return &intransit.Stock{
+		DocNo:        docNo,
+		Date:         time.Now().AddDate(0, 0, -age).Format("20060102"),
+		QtyInTransit: qty,
+	}
+}
+
+// tiap baris masuk ke horizon terkecil yang memuat umurnya, sisanya ke "> n days"
+func TestInTransitReport_AgingBuckets(t *testing.T) {
+	s := &InTransit{TemplateRepo: &fakeInTransitRepo{lines: []*intransit.Stock{
+		transitStock("MT1", 0, 1),
+		transitStock("MT2", 7, 2),
+		transitStock("MT3", 8, 3),
+		transitStock("MT4", 31, 4),
+	}}}
+
+	report, err := s.Report(context.Background(), "", "", "", "")
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{7, 14, 30}, report.Horizons)
+	require.Len(t, report.Buckets, 4)
+	assert.Equal(t, intransit.Bucket{Name: "<= 7 days", Lines: 2, Qty: 3}, *report.Buckets[0])
+	assert.Equal(t, intransit.Bucket{Name: "<= 14 days", Lines: 1, Qty: 3}, *report.Buckets[1])
+	assert.Equal(t, intransit.Bucket{Name: "<= 30 days", Lines: 0, Qty: 0}, *report.Buckets[2])
+	assert.Equal(t, intransit.Bucket{Name: "> 30 days", Lines: 1, Qty: 4}, *report.Buckets[3])
+	assert.Equal(t, 31, report.Lines[3].AgeDays)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/internal/domain/journal"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type JournalService interface {
+	Fetch(ctx context.Context, search, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Detail(ctx context.Context, docNo string) (*journal.Read, error)
+	TrialBalance(ctx context.Context, startDate, endDate string) (*journal.TrialBalanceReport, error)
+	FetchAccount(ctx context.Context, search string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	SaveAccount(ctx context.Context, data *journal.Account, userName string) (*journal.Account, error)
+}
+
+type Journal struct {
+	TemplateRepo journal.Repository `inject:"journalRepository"`
+}
+
+func (s *Journal) Fetch(ctx context.Context, search, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	var err error
+	if startDate != "" {
+		if startDate, err = share.FormatToCompactDateTime(startDate); err != nil {
+			return nil, customerrors.ErrInvalidInput
+		}
+	}
+	if endDate != "" {
+		if endDate, err = share.FormatToCompactDateTime(endDate); err != nil {
+			return nil, customerrors.ErrInvalidInput
+		}
+	}
+
+	return s.TemplateRepo.Fetch(ctx, search, startDate, endDate, param)
+}
+
+func (s *Journal) Detail(ctx context.Context, docNo string) (*journal.Read, error) {
+	return s.TemplateRepo.Detail(ctx, docNo)
+}
+
+// TrialBalance default periode bulan berjalan sampai hari ini
+func (s *Journal) TrialBalance(ctx context.Context, startDate, endDate string) (*journal.TrialBalanceReport, error) {
+	now := time.Now()
+	start, end := now.Format("200601")+"01", now.Format("20060102")
+
+	var err error
+	if startDate != "" {
+		if start, err = share.FormatToCompactDateTime(startDate); err != nil {
+			return nil, customerrors.ErrInvalidInput
+		}
+	}
+	if endDate != "" {
+		if end, err = share.FormatToCompactDateTime(endDate); err != nil {
+			return nil, customerrors.ErrInvalidInput
+		}
+	}
+	if start > end {
+		return nil, customerrors.ErrInvalidInput
+	}
+
+	return s.TemplateRepo.TrialBalance(ctx, start, end)
+}
+
+func (s *Journal) FetchAccount(ctx context.Context, search string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	return s.TemplateRepo.FetchAccount(ctx, search, param)
+}
+
+func (s *Journal) SaveAccount(ctx context.Context, data *journal.Account, userName string) (*journal.Account, error) {
+	data.CreateBy = userName
+	data.CreateDt = time.Now().Format("200601021504")
+	data.Inventory.SetNullIfEmpty()
+	data.GRIR.SetNullIfEmpty()
+	data.COGS.SetNullIfEmpty()
+	data.Adjustment.SetNullIfEmpty()
+	data.TaxPayable.SetNullIfEmpty()
+
+	res, err := s.TemplateRepo.SaveAccount(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error save journal account: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -4,16 +4,17 @@ import (
 	"context"
 
 	"gitlab.com/ayaka/internal/domain/logactivity"
+	"gitlab.com/ayaka/internal/pkg/pagination"
 )
 
 type TblLogService interface {
-	GetLog(ctx context.Context, code, category string) ([]*logactivity.LogActivity, error)
+	GetLog(ctx context.Context, filter *logactivity.Filter, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
 }
 
 type TblLog struct {
 	TemplateRepo logactivity.Repository `inject:"tblLogRepository"`
 }
 
-func (s *TblLog) GetLog(ctx context.Context, code, category string) ([]*logactivity.LogActivity, error) {
-	return s.TemplateRepo.GetActivityLog(ctx, code, category)
+func (s *TblLog) GetLog(ctx context.Context, filter *logactivity.Filter, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	return s.TemplateRepo.GetActivityLog(ctx, filter, param)
 }
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/internal/domain/exchangerate"
+	"gitlab.com/ayaka/internal/domain/masterimport"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/domain/tblmasteritem"
+	"gitlab.com/ayaka/internal/domain/tblmastervendor"
+	"gitlab.com/ayaka/internal/domain/tbluom"
+	"gitlab.com/ayaka/internal/domain/tblwarehouse"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/importer"
+	"gitlab.com/ayaka/internal/pkg/validator"
+)
+
+type MasterImportService interface {
+	Import(ctx context.Context, entity string, rows [][]string, mapping map[string]string, dryRun bool, userName string) (*masterimport.Result, error)
+}
+
+type MasterImport struct {
+	TemplateRepo masterimport.Repository `inject:"masterImportRepository"`
+	Validator    validator.Validator     `inject:"validator"`
+}
+
+// importRecord adalah satu data yang akan dibuat beserta nomor baris pertamanya di file
+type importRecord struct {
+	row  int
+	data interface{}
+}
+
+var importTargets = map[string]func() interface{}{
+	masterimport.Item:         func() interface{} { return new(tblmasteritem.Create) },
+	masterimport.Vendor:       func() interface{} { return new(tblmastervendor.Create) },
+	masterimport.Warehouse:    func() interface{} { return new(tblwarehouse.CreateTblWarehouse) },
+	masterimport.Uom:          func() interface{} { return new(tbluom.CreateTblUom) },
+	masterimport.ExchangeRate: func() interface{} { return new(exchangerate.Rate) },
+}
+
+// Import memvalidasi semua baris dengan aturan yang sama seperti create satuan.
+// Dry run hanya mengembalikan laporan; selain itu data disimpan dalam satu transaksi
+// dan hanya bila tidak ada baris yang error (ErrInvalidInput beserta laporannya).
+func (s *MasterImport) Import(ctx context.Context, entity string, rows [][]string, mapping map[string]string, dryRun bool, userName string) (*masterimport.Result, error) {
+	newTarget, ok := importTargets[entity]
+	if !ok {
+		return nil, customerrors.ErrKeyNotFound
+	}
+	if len(rows) < 2 {
+		return nil, customerrors.ErrInvalidInput
+	}
+
+	columns, ignored, err := importer.Columns(newTarget(), rows[0], mapping)
+	if err != nil {
+		return nil, errors.Join(customerrors.ErrInvalidInput, err)
+	}
+
+	result := &masterimport.Result{
+		Entity:         entity,
+		DryRun:         dryRun,
+		IgnoredColumns: ignored,
+		Errors:         []masterimport.RowError{},
+	}
+
+	var records []importRecord
+	for i, row := range rows[1:] {
+		values := importer.Values(row, columns)
+		if blankRow(values) {
+			continue
+		}
+		result.TotalRows++
+		rowNo := i + 2
+
+		target := newTarget()
+		for field, msg := range importer.Decode(target, values) {
+			result.Errors = append(result.Errors, masterimport.RowError{Row: rowNo, Field: field, Message: msg})
+		}
+
+		// baris vendor tanpa nama melanjutkan vendor sebelumnya (contact, item category, sector, rating tambahan)
+		if vendor, ok := target.(*tblmastervendor.Create); ok && vendor.VendorName == "" && len(records) > 0 {
+			prev := records[len(records)-1].data.(*tblmastervendor.Create)
+			prev.ContactVendor = append(prev.ContactVendor, vendor.ContactVendor...)
+			prev.ItemCategoryVendor = append(prev.ItemCategoryVendor, vendor.ItemCategoryVendor...)
+			prev.SectorVendor = append(prev.SectorVendor, vendor.SectorVendor...)
+			prev.RatingVendor = append(prev.RatingVendor, vendor.RatingVendor...)
+			continue
+		}
+
+		records = append(records, importRecord{row: rowNo, data: target})
+	}
+	result.TotalRecords = len(records)
+
+	invalid := make(map[int]bool)
+	for _, e := range result.Errors {
+		invalid[e.Row] = true
+	}
+
+	seen := make(map[string]int)
+	for _, record := range records {
+		prepareImport(record.data, userName)
+
+		var fieldErrs []masterimport.RowError
+		if err := s.Validator.Validate(ctx, record.data); err != nil {
+			var validationErr *validator.ValidationError
+			if !errors.As(err, &validationErr) {
+				return nil, err
+			}
+			for field, msg := range validationErr.ErrorFields {
+				fieldErrs = append(fieldErrs, masterimport.RowError{Row: record.row, Field: field, Message: msg})
+			}
+		}
+		fieldErrs = append(fieldErrs, duplicateInFile(record, seen)...)
+
+		result.Errors = append(result.Errors, fieldErrs...)
+		if len(fieldErrs) == 0 && !invalid[record.row] {
+			result.ValidRecords++
+		}
+	}
+
+	sort.Slice(result.Errors, func(i, j int) bool {
+		if result.Errors[i].Row != result.Errors[j].Row {
+			return result.Errors[i].Row < result.Errors[j].Row
+		}
+		return result.Errors[i].Field < result.Errors[j].Field
+	})
+
+	if dryRun {
+		return result, nil
+	}
+	if len(result.Errors) > 0 {
+		return result, customerrors.ErrInvalidInput
+	}
+
+	if err := s.save(ctx, entity, records); err != nil {
+		golog.Error(ctx, "Error import "+entity+": "+err.Error(), err)
+		return nil, err
+	}
+	result.Imported = len(records)
+
+	return result, nil
+}
+
+func (s *MasterImport) save(ctx context.Context, entity string, records []importRecord) error {
+	switch entity {
+	case masterimport.Item:
+		data := make([]*tblmasteritem.Create, len(records))
+		for i, record := range records {
+			data[i] = record.data.(*tblmasteritem.Create)
+		}
+		return s.TemplateRepo.ImportItems(ctx, data)
+	case masterimport.Vendor:
+		data := make([]*tblmastervendor.Create, len(records))
+		for i, record := range records {
+			data[i] = record.data.(*tblmastervendor.Create)
+		}
+		return s.TemplateRepo.ImportVendors(ctx, data)
+	case masterimport.Warehouse:
+		data := make([]*tblwarehouse.CreateTblWarehouse, len(records))
+		for i, record := range records {
+			data[i] = record.data.(*tblwarehouse.CreateTblWarehouse)
+		}
+		return s.TemplateRepo.ImportWarehouses(ctx, data)
+	case masterimport.ExchangeRate:
+		// tanggal baru diubah ke yyyymmdd setelah lolos validasi
+		data := make([]*exchangerate.Rate, len(records))
+		for i, record := range records {
+			data[i] = record.data.(*exchangerate.Rate)
+			data[i].Date, _ = share.FormatToCompactDateTime(data[i].Date)
+		}
+		return s.TemplateRepo.ImportExchangeRates(ctx, data)
+	default:
+		data := make([]*tbluom.CreateTblUom, len(records))
+		for i, record := range records {
+			data[i] = record.data.(*tbluom.CreateTblUom)
+		}
+		return s.TemplateRepo.ImportUoms(ctx, data)
+	}
+}
+
+// prepareImport mengisi field yang biasanya diisi service saat create satuan
+func prepareImport(data interface{}, userName string) {
+	switch d := data.(type) {
+	case *tblmasteritem.Create:
+		prepareItemCreate(d, userName)
+	case *tblmastervendor.Create:
+		prepareVendorCreate(d, userName)
+	case *tblwarehouse.CreateTblWarehouse:
+		d.CreateBy = userName
+		d.CreateDate = time.Now().Format("200601021504")
+	case *tbluom.CreateTblUom:
+		d.CreateBy = userName
+		d.CreateDate = time.Now().Format("200601021504")
+	case *exchangerate.Rate:
+		d.CreateBy = userName
+		d.CreateDate = time.Now().Format("200601021504")
+	}
+}
+
+// duplicateInFile mengecek field bertag unique= terhadap baris lain di file yang sama,
+// karena validator hanya mengecek ke database.
+func duplicateInFile(record importRecord, seen map[string]int) []masterimport.RowError {
+	var errs []masterimport.RowError
+	v := reflect.ValueOf(record.data).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !strings.Contains(field.Tag.Get("validate"), "unique=") || field.Type.Kind() != reflect.String {
+			continue
+		}
+		value := strings.ToLower(v.Field(i).String())
+		if value == "" {
+			continue
+		}
+
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		key := name + "\x00" + value
+		if first, ok := seen[key]; ok {
+			errs = append(errs, masterimport.RowError{Row: record.row, Field: name, Message: fmt.Sprintf("%s duplicates row %d", v.Field(i).String(), first)})
+			continue
+		}
+		seen[key] = record.row
+	}
+	return errs
+}
+
+func blankRow(values map[string]string) bool {
+	for _, value := range values {
+		if value != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/config"
+	"gitlab.com/ayaka/internal/domain/printout"
+	"gitlab.com/ayaka/internal/pkg/printer"
+)
+
+type PrintoutService interface {
+	Print(ctx context.Context, docType, docNo, format, userName string) ([]byte, error)
+}
+
+type Printout struct {
+	TemplateRepo printout.Repository `inject:"printoutRepository"`
+	Conf         *config.Config      `inject:"config"`
+}
+
+func (s *Printout) Print(ctx context.Context, docType, docNo, format, userName string) ([]byte, error) {
+	doc, err := s.TemplateRepo.Document(ctx, docType, docNo)
+	if err != nil {
+		return nil, err
+	}
+
+	// dokumen tanpa site memakai site instance ini untuk memilih template
+	if doc.SiteCode == "" {
+		doc.SiteCode = s.Conf.DocNumber.Site
+	}
+	doc.PrintedBy = userName
+	doc.PrintedAt = time.Now().Format("02/01/2006 15:04")
+
+	var buf bytes.Buffer
+	p := &printer.Printer{Dir: s.Conf.Print.TemplateDir}
+	if err := p.Render(&buf, format, doc); err != nil {
+		golog.Error(ctx, "Error render print document: "+err.Error(), err)
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/config"
+	"gitlab.com/ayaka/internal/domain/quotationcomparison"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+type QuotationComparisonService interface {
+	Compare(ctx context.Context, porDocNo, itemCodes string) ([]*quotationcomparison.Comparison, error)
+	Select(ctx context.Context, data *quotationcomparison.Selection, userName string) (*quotationcomparison.Selection, error)
+}
+
+type QuotationComparison struct {
+	TemplateRepo quotationcomparison.Repository `inject:"quotationComparisonRepository"`
+	Conf         *config.Config                 `inject:"config"`
+}
+
+// Compare itemCodes dipisah koma, dipakai kalau porDocNo kosong
+func (s *QuotationComparison) Compare(ctx context.Context, porDocNo, itemCodes string) ([]*quotationcomparison.Comparison, error) {
+	var items []string
+	for _, code := range strings.Split(itemCodes, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			items = append(items, code)
+		}
+	}
+	if porDocNo == "" && len(items) == 0 {
+		return nil, customerrors.ErrInvalidInput
+	}
+
+	var base string
+	var weight quotationcomparison.Weight
+	if s.Conf != nil {
+		base = s.Conf.Currency.Base
+		ranking := s.Conf.Purchase.QuotationRanking
+		weight = quotationcomparison.Weight{
+			Price:         ranking.Price,
+			Payment:       ranking.Payment,
+			Delivery:      ranking.Delivery,
+			Rating:        ranking.Rating,
+			DeliveryTypes: ranking.DeliveryTypes,
+		}
+	}
+
+	return s.TemplateRepo.Compare(ctx, porDocNo, items, base, weight)
+}
+
+func (s *QuotationComparison) Select(ctx context.Context, data *quotationcomparison.Selection, userName string) (*quotationcomparison.Selection, error) {
+	data.LastUpdateBy = userName
+	data.LastUpdateDate = time.Now().Format("200601021504")
+
+	res, err := s.TemplateRepo.Select(ctx, data)
+	if err != nil {
+		if !errors.Is(err, customerrors.ErrInvalidInput) && !errors.Is(err, customerrors.ErrDataNotFound) {
+			golog.Error(ctx, "Error select vendor quotation: "+err.Error(), err)
+		}
+		return nil, err
+	}
+
+	return res, nil
+}
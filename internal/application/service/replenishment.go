@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/internal/domain/replenishment"
+	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+	sharedfunc "gitlab.com/ayaka/internal/domain/shared/sharedFunc"
+	"gitlab.com/ayaka/internal/domain/tblmaterialrequest"
+	"gitlab.com/ayaka/internal/domain/uomconversion"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type ReplenishmentService interface {
+	FetchSetting(ctx context.Context, warehouse, search string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	SaveSetting(ctx context.Context, data *replenishment.Setting, userName string) (*replenishment.Setting, error)
+	Proposals(ctx context.Context, warehouse, itemCategory, uom string, all bool) ([]*replenishment.Proposal, error)
+	CreateRequest(ctx context.Context, data *replenishment.Request, userName string) (*tblmaterialrequest.Create, error)
+}
+
+type Replenishment struct {
+	TemplateRepo    replenishment.Repository  `inject:"replenishmentRepository"`
+	MaterialRequest TblMaterialRequestService `inject:"tblMaterialRequestService"`
+	Uom             uomconversion.Repository  `inject:"uomConversionRepository"`
+}
+
+func (s *Replenishment) FetchSetting(ctx context.Context, warehouse, search string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	return s.TemplateRepo.FetchSetting(ctx, warehouse, search, param)
+}
+
+func (s *Replenishment) SaveSetting(ctx context.Context, data *replenishment.Setting, userName string) (*replenishment.Setting, error) {
+	if data.MaxQty > 0 && data.MaxQty < max(data.MinQty, data.ReorderPoint) {
+		return nil, customerrors.ErrInvalidInput
+	}
+
+	data.CreateBy = userName
+	data.CreateDate = time.Now().Format("200601021504")
+	if !data.Active.Valid {
+		data.Active = booldatatype.FromBool(true)
+	}
+	data.Remark.SetNullIfEmpty()
+
+	res, err := s.TemplateRepo.SaveSetting(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error save replenishment setting: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Proposals default hanya yang perlu dipesan, all menampilkan semua setting.
+// Qty dalam UoM inventory kecuali uom diisi.
+func (s *Replenishment) Proposals(ctx context.Context, warehouse, itemCategory, uom string, all bool) ([]*replenishment.Proposal, error) {
+	proposals, err := s.TemplateRepo.Proposals(ctx, warehouse, itemCategory)
+	if err != nil {
+		return nil, err
+	}
+	if uom != "" {
+		itemCodes := make([]string, 0, len(proposals))
+		for _, p := range proposals {
+			itemCodes = append(itemCodes, p.ItemCode)
+		}
+		uomName, factors, err := s.Uom.ToUom(ctx, sharedfunc.UniqueStringSlice(itemCodes), uom)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range proposals {
+			factor := factors[p.ItemCode]
+			p.SafetyStock *= factor
+			p.ReorderPoint *= factor
+			p.MinQty *= factor
+			p.MaxQty *= factor
+			p.OnHand *= factor
+			p.OpenPO *= factor
+			p.OpenRequest *= factor
+			p.Projected *= factor
+			p.SuggestedQty *= factor
+			p.UomName = uomName
+		}
+	}
+	if all {
+		return proposals, nil
+	}
+
+	reorder := make([]*replenishment.Proposal, 0, len(proposals))
+	for _, p := range proposals {
+		if p.Reorder {
+			reorder = append(reorder, p)
+		}
+	}
+
+	return reorder, nil
+}
+
+// CreateRequest menghitung ulang proposal lalu membuat satu material request
+// lewat TblMaterialRequestService.Create, jadi approval dan penomoran tetap
+// sama dengan material request manual. Qty material request dalam UoM beli.
+func (s *Replenishment) CreateRequest(ctx context.Context, data *replenishment.Request, userName string) (*tblmaterialrequest.Create, error) {
+	date, err := time.Parse("2006-01-02", data.Date)
+	if err != nil {
+		return nil, customerrors.ErrInvalidInput
+	}
+
+	proposals, err := s.TemplateRepo.Proposals(ctx, "", "")
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]*replenishment.Proposal, len(proposals))
+	for _, p := range proposals {
+		index[p.ItemCode+"*"+p.WarehouseCode] = p
+	}
+
+	request := &tblmaterialrequest.Create{
+		Date:       data.Date,
+		SiteCode:   data.SiteCode,
+		Department: data.Department,
+		Remark:     data.Remark,
+	}
+	if request.Remark.String == "" {
+		request.Remark = nulldatatype.NewNullStringDataType("Replenishment")
+	}
+
+	for _, l := range data.Lines {
+		key := l.ItemCode + "*" + l.WarehouseCode
+		p, ok := index[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: no replenishment setting for %s in %s", customerrors.ErrInvalidInput, l.ItemCode, l.WarehouseCode)
+		}
+		// satu proposal hanya boleh dipilih sekali
+		delete(index, key)
+
+		qty := l.Qty
+		if qty == 0 {
+			qty = p.SuggestedQty
+		}
+		if qty <= 0 {
+			return nil, fmt.Errorf("%w: nothing to order for %s in %s", customerrors.ErrInvalidInput, l.ItemCode, l.WarehouseCode)
+		}
+
+		request.Details = append(request.Details, tblmaterialrequest.Detail{
+			ItCode:         p.ItemCode,
+			Qty:            qty / p.PurchaseFactor,
+			UsageDt:        date.AddDate(0, 0, p.LeadTime).Format("2006-01-02"),
+			CurCode:        data.CurCode,
+			EstimatedPrice: l.EstimatedPrice,
+			Remark:         nulldatatype.NewNullStringDataType("Replenishment " + p.WarehouseCode),
+		})
+	}
+
+	return s.MaterialRequest.Create(ctx, request, userName)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/domain/stockcard"
+	"gitlab.com/ayaka/internal/domain/uomconversion"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+type StockCardService interface {
+	Fetch(ctx context.Context, itemCode, warehouse, batch, startDate, endDate, uom string) (*stockcard.Read, error)
+}
+
+type StockCard struct {
+	TemplateRepo stockcard.Repository     `inject:"stockCardRepository"`
+	Uom          uomconversion.Repository `inject:"uomConversionRepository"`
+}
+
+// Fetch default periode awal bulan berjalan sampai hari ini. Kalau uom diisi
+// semua qty dikonversi dari UoM inventory item ke uom tersebut.
+func (s *StockCard) Fetch(ctx context.Context, itemCode, warehouse, batch, startDate, endDate, uom string) (*stockcard.Read, error) {
+	if itemCode == "" {
+		return nil, customerrors.ErrInvalidInput
+	}
+
+	now := time.Now()
+	start, end := now.Format("200601")+"01", now.Format("20060102")
+
+	var err error
+	if startDate != "" {
+		if start, err = share.FormatToCompactDateTime(startDate); err != nil {
+			return nil, customerrors.ErrInvalidInput
+		}
+	}
+	if endDate != "" {
+		if end, err = share.FormatToCompactDateTime(endDate); err != nil {
+			return nil, customerrors.ErrInvalidInput
+		}
+	}
+	if start > end {
+		return nil, customerrors.ErrInvalidInput
+	}
+
+	res, err := s.TemplateRepo.Fetch(ctx, itemCode, warehouse, batch, start, end)
+	if err != nil || uom == "" {
+		return res, err
+	}
+
+	uomName, factors, err := s.Uom.ToUom(ctx, []string{itemCode}, uom)
+	if err != nil {
+		return nil, err
+	}
+	factor, ok := factors[itemCode]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s to %s", customerrors.ErrUomConversion, itemCode, uom)
+	}
+
+	res.Uom = uomName
+	res.Opening *= factor
+	res.TotalIn *= factor
+	res.TotalOut *= factor
+	res.Closing *= factor
+	res.SummaryBalance *= factor
+	for _, l := range res.Lines {
+		l.InQty *= factor
+		l.OutQty *= factor
+		l.Balance *= factor
+	}
+
+	return res, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/runsystemid/golog"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/domain/stockopname"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type StockOpnameService interface {
+	Fetch(ctx context.Context, doc, warehouse, status string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Detail(ctx context.Context, docNo string) (*stockopname.Detail, error)
+	Create(ctx context.Context, data *stockopname.Create, userName string) (*stockopname.Create, error)
+	Count(ctx context.Context, data *stockopname.Count, userName string) (*stockopname.Count, error)
+	Approve(ctx context.Context, docNo, userName string) (*stockopname.Detail, error)
+	Cancel(ctx context.Context, docNo, userName string) error
+}
+
+type StockOpname struct {
+	TemplateRepo stockopname.Repository `inject:"stockOpnameRepository"`
+}
+
+func (s *StockOpname) Fetch(ctx context.Context, doc, warehouse, status string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	return s.TemplateRepo.Fetch(ctx, doc, warehouse, status, param)
+}
+
+func (s *StockOpname) Detail(ctx context.Context, docNo string) (*stockopname.Detail, error) {
+	return s.TemplateRepo.Detail(ctx, docNo)
+}
+
+func (s *StockOpname) Create(ctx context.Context, data *stockopname.Create, userName string) (*stockopname.Create, error) {
+	data.CreateBy = userName
+	data.CreateDate = time.Now().Format("200601021504")
+
+	var err error
+	if data.Date, err = share.FormatToCompactDateTime(data.Date); err != nil {
+		return nil, customerrors.ErrInvalidInput
+	}
+
+	data.ItemCategoryCode.SetNullIfEmpty()
+	data.Bin.SetNullIfEmpty()
+	data.Remark.SetNullIfEmpty()
+
+	res, err := s.TemplateRepo.Create(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error create stock opname: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *StockOpname) Count(ctx context.Context, data *stockopname.Count, userName string) (*stockopname.Count, error) {
+	data.CreateBy = userName
+	data.CreateDate = time.Now().Format("200601021504")
+
+	return s.TemplateRepo.Count(ctx, data)
+}
+
+func (s *StockOpname) Approve(ctx context.Context, docNo, userName string) (*stockopname.Detail, error) {
+	res, err := s.TemplateRepo.Approve(ctx, docNo, userName, time.Now().Format("200601021504"))
+	if err != nil {
+		golog.Error(ctx, "Error approve stock opname: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *StockOpname) Cancel(ctx context.Context, docNo, userName string) error {
+	return s.TemplateRepo.Cancel(ctx, docNo, userName, time.Now().Format("200601021504"))
+}
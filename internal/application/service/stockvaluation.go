@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	share "gitlab.com/ayaka/internal/domain/shared"
+	sharedfunc "gitlab.com/ayaka/internal/domain/shared/sharedFunc"
+	"gitlab.com/ayaka/internal/domain/stockvaluation"
+	"gitlab.com/ayaka/internal/domain/uomconversion"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type StockValuationService interface {
+	Fetch(ctx context.Context, date, warehouse, itemCatCode, itemName, uom string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+}
+
+type StockValuation struct {
+	TemplateRepo stockvaluation.Repository `inject:"stockValuationRepository"`
+	Uom          uomconversion.Repository  `inject:"uomConversionRepository"`
+}
+
+// Fetch mengembalikan nilai persediaan per tanggal (yyyy-mm-dd), default hari ini.
+// uom diisi untuk menampilkan qty dan harga satuan dalam UoM lain, nilainya tetap.
+func (s *StockValuation) Fetch(ctx context.Context, date, warehouse, itemCatCode, itemName, uom string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	asOf := time.Now().Format("20060102")
+	if date != "" {
+		var err error
+		if asOf, err = share.FormatToCompactDateTime(date); err != nil {
+			return nil, customerrors.ErrInvalidInput
+		}
+	}
+
+	res, err := s.TemplateRepo.Fetch(ctx, asOf, warehouse, itemCatCode, itemName, param)
+	if err != nil || uom == "" {
+		return res, err
+	}
+
+	rows, ok := res.Data.([]*stockvaluation.Read)
+	if !ok {
+		return res, nil
+	}
+	itemCodes := make([]string, 0, len(rows))
+	for _, r := range rows {
+		itemCodes = append(itemCodes, r.ItemCode)
+	}
+	uomName, factors, err := s.Uom.ToUom(ctx, sharedfunc.UniqueStringSlice(itemCodes), uom)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		r.Quantity *= factors[r.ItemCode]
+		r.UnitCost /= factors[r.ItemCode]
+		r.Uom = uomName
+	}
+
+	return res, nil
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/internal/domain/taxengine"
+)
+
+type TaxEngineService interface {
+	Calculate(ctx context.Context, data *taxengine.Request) (*taxengine.Breakdown, error)
+}
+
+type TaxEngine struct {
+	TemplateRepo taxengine.Repository `inject:"taxEngineRepository"`
+}
+
+func (s *TaxEngine) Calculate(ctx context.Context, data *taxengine.Request) (*taxengine.Breakdown, error) {
+	res, err := s.TemplateRepo.Calculate(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error calculate tax: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
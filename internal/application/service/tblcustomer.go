@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/internal/domain/tblmastercustomer"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type TblCustomerService interface {
+	Fetch(ctx context.Context, name, cat string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Detail(ctx context.Context, customerCode string) (*tblmastercustomer.Detail, error)
+	Create(ctx context.Context, data *tblmastercustomer.Create, userName string) (*tblmastercustomer.Create, error)
+	Update(ctx context.Context, data *tblmastercustomer.Update, userCode string) (*tblmastercustomer.Update, error)
+}
+
+type TblCustomer struct {
+	TemplateRepo tblmastercustomer.Repository `inject:"tblCustomerRepository"`
+}
+
+func (s *TblCustomer) Fetch(ctx context.Context, name, cat string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	return s.TemplateRepo.Fetch(ctx, name, cat, param)
+}
+
+func (s *TblCustomer) Detail(ctx context.Context, customerCode string) (*tblmastercustomer.Detail, error) {
+	return s.TemplateRepo.Detail(ctx, customerCode)
+}
+
+func (s *TblCustomer) Create(ctx context.Context, data *tblmastercustomer.Create, userName string) (*tblmastercustomer.Create, error) {
+	data.CreateBy = userName
+	data.CreateDate = time.Now().Format("200601021504")
+
+	data.Address.SetNullIfEmpty()
+	data.PostalCode.SetNullIfEmpty()
+	data.Phone.SetNullIfEmpty()
+	data.Mobile.SetNullIfEmpty()
+	data.Email.SetNullIfEmpty()
+	data.Remark.SetNullIfEmpty()
+	data.TaxGroupCode.SetNullIfEmpty()
+	data.NPWP.SetNullIfEmpty()
+	prepareCustomerDetails(data.ContactCustomer, data.AddressCustomer)
+
+	res, err := s.TemplateRepo.Create(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error create customer: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *TblCustomer) Update(ctx context.Context, data *tblmastercustomer.Update, userCode string) (*tblmastercustomer.Update, error) {
+	data.LastUpdateBy = userCode
+	data.LastUpdateDate = time.Now().Format("200601021504")
+
+	data.Address.SetNullIfEmpty()
+	data.PostalCode.SetNullIfEmpty()
+	data.Phone.SetNullIfEmpty()
+	data.Mobile.SetNullIfEmpty()
+	data.Email.SetNullIfEmpty()
+	data.Remark.SetNullIfEmpty()
+	data.TaxGroupCode.SetNullIfEmpty()
+	data.NPWP.SetNullIfEmpty()
+	prepareCustomerDetails(data.ContactCustomer, data.AddressCustomer)
+
+	res, err := s.TemplateRepo.Update(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error update customer: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func prepareCustomerDetails(contacts []tblmastercustomer.ContactCustomer, addresses []tblmastercustomer.AddressCustomer) {
+	for i := range contacts {
+		contacts[i].Position.SetNullIfEmpty()
+		contacts[i].Type.SetNullIfEmpty()
+	}
+	for i := range addresses {
+		addresses[i].Address.SetNullIfEmpty()
+		addresses[i].PostalCode.SetNullIfEmpty()
+		addresses[i].Phone.SetNullIfEmpty()
+	}
+}
@@ -5,19 +5,23 @@ import (
 	"time"
 
 	share "gitlab.com/ayaka/internal/domain/shared"
+	sharedfunc "gitlab.com/ayaka/internal/domain/shared/sharedFunc"
 	"gitlab.com/ayaka/internal/domain/tbldailystockmovement"
+	"gitlab.com/ayaka/internal/domain/uomconversion"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
 
 type TblDailyStockMovementService interface {
-	Fetch(ctx context.Context, warehouse, date, itemName, itemCategoryName string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Fetch(ctx context.Context, warehouse, date, itemName, itemCategoryName, uom string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
 }
 
 type TblDailyStockMovement struct {
 	TemplateRepo tbldailystockmovement.Repository `inject:"tblDailyStockMovementRepository"`
+	Uom          uomconversion.Repository         `inject:"uomConversionRepository"`
 }
 
-func (s *TblDailyStockMovement) Fetch(ctx context.Context, warehouse, date, itemName, itemCategoryName string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+// Fetch qty dalam UoM inventory kecuali uom diisi
+func (s *TblDailyStockMovement) Fetch(ctx context.Context, warehouse, date, itemName, itemCategoryName, uom string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
 	var err error
 
 	if date != "" {
@@ -33,5 +37,32 @@ func (s *TblDailyStockMovement) Fetch(ctx context.Context, warehouse, date, item
 			return nil, err
 		}
 	}
-	return s.TemplateRepo.Fetch(ctx, warehouse, date, itemName,itemCategoryName, param)
+	res, err := s.TemplateRepo.Fetch(ctx, warehouse, date, itemName,itemCategoryName, param)
+	if err != nil || uom == "" {
+		return res, err
+	}
+
+	rows, ok := res.Data.([]*tbldailystockmovement.Read)
+	if !ok {
+		return res, nil
+	}
+	itemCodes := make([]string, 0, len(rows))
+	for _, r := range rows {
+		itemCodes = append(itemCodes, r.ItemCode)
+	}
+	uomName, factors, err := s.Uom.ToUom(ctx, sharedfunc.UniqueStringSlice(itemCodes), uom)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		factor := factors[r.ItemCode]
+		r.Init *= factor
+		r.In *= factor
+		r.Out *= factor
+		r.Total *= factor
+		r.RealStock *= factor
+		r.Uom = uomName
+	}
+
+	return res, nil
 }
\ No newline at end of file
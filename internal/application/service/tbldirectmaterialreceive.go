@@ -9,7 +9,6 @@ import (
 	"github.com/runsystemid/golog"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
-	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/domain/tbldirectmaterialreceive"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
@@ -22,7 +21,6 @@ type TblDirectMaterialReceiveService interface {
 
 type TblDirectMaterialReceive struct {
 	TemplateRepo tbldirectmaterialreceive.Repository `inject:"tblDirectMaterialReceiveRepository"`
-	ID           *formatid.GenerateIDHandler         `inject:"generateID"`
 }
 
 func (s *TblDirectMaterialReceive) Fetch(ctx context.Context, doc, warehouse, warehouseTo, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -46,13 +44,7 @@ func (s *TblDirectMaterialReceive) Create(ctx context.Context, data *tbldirectma
 	data.CreateBy = userName
 	data.CreateDt = time.Now().Format("200601021504")
 
-	var err error
-	data.DocNo, err = s.ID.GenerateID(ctx, "DirectMaterialReceive")
 	data.Remark.SetNullIfEmpty()
-	if err != nil {
-		golog.Error(ctx, "Error generate id create direct material receive: "+err.Error(), err)
-		return nil, fmt.Errorf("Error generate id create direct material receive: " + err.Error())
-	}
 
 	t, err := time.Parse("2006-01-02", data.Date)
 	if err != nil {
@@ -67,7 +59,6 @@ func (s *TblDirectMaterialReceive) Create(ctx context.Context, data *tbldirectma
 		if data.Details[i].BatchNo == "" {
 			data.Details[i].BatchNo = data.Date
 		}
-		data.Details[i].Source = fmt.Sprintf("%s*%s*%s", data.Date[6:8], data.DocNo, data.Details[i].DNo)
 	}
 
 	res, err := s.TemplateRepo.Create(ctx, data)
@@ -7,9 +7,9 @@ import (
 	"time"
 
 	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/config"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
-	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/domain/tbldirectpurchasercv"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
@@ -22,7 +22,7 @@ type TblDirectPurchaseRcvService interface {
 
 type TblDirectPurchaseRcv struct {
 	TemplateRepo tbldirectpurchasercv.Repository `inject:"tblDirectPurchaseRcvRepository"`
-	ID           *formatid.GenerateIDHandler     `inject:"generateID"`
+	Conf         *config.Config                  `inject:"config"`
 }
 
 func (s *TblDirectPurchaseRcv) Fetch(ctx context.Context, doc, warehouse, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -46,20 +46,17 @@ func (s *TblDirectPurchaseRcv) Create(ctx context.Context, data *tbldirectpurcha
 	data.CreateBy = userName
 	data.CreateDt = time.Now().Format("200601021504")
 
-	var err error
-	data.DocNo, err = s.ID.GenerateID(ctx, "DirectPurchaseReceive")
 	data.Remark.SetNullIfEmpty()
 	data.SiteCode.SetNullIfEmpty()
-	if err != nil {
-		golog.Error(ctx, "Error generate id create direct purchase receive: "+err.Error(), err)
-		return nil, fmt.Errorf("Error generate id create direct purchase receive: " + err.Error())
-	}
+	data.TaxCode.SetNullIfEmpty()
+	data.TaxInclusive = booldatatype.FromBool(data.TaxInclusive.ToBool())
 
 	t, err := time.Parse("2006-01-02", data.Date)
 	if err != nil {
 		return nil, err
 	}
 	data.Date = t.Format("20060102")
+	data.BaseCurrency = s.Conf.Currency.Base
 
 	for i := 0; i < len(data.Details); i++ {
 		data.Details[i].DNo = fmt.Sprintf("%03d", i+1)
@@ -68,7 +65,6 @@ func (s *TblDirectPurchaseRcv) Create(ctx context.Context, data *tbldirectpurcha
 		if data.Details[i].BatchNo == "" {
 			data.Details[i].BatchNo = data.Date
 		}
-		data.Details[i].Source = fmt.Sprintf("%s*%s*%s", data.Date[6:8], data.DocNo, data.Details[i].DNo)
 	}
 
 	res, err := s.TemplateRepo.Create(ctx, data)
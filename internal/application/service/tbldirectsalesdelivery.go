@@ -9,7 +9,6 @@ import (
 	"github.com/runsystemid/golog"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
-	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/domain/tbldirectsalesdelivery"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
@@ -22,7 +21,6 @@ type TblDirectSalesDeliveryService interface {
 
 type TblDirectSalesDelivery struct {
 	TemplateRepo tbldirectsalesdelivery.Repository `inject:"tblDirectSalesDeliveryRepository"`
-	ID           *formatid.GenerateIDHandler       `inject:"generateID"`
 }
 
 func (s *TblDirectSalesDelivery) Fetch(ctx context.Context, doc, warehouse, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -46,8 +44,6 @@ func (s *TblDirectSalesDelivery) Create(ctx context.Context, data *tbldirectsale
 	data.CreateBy = userName
 	data.CreateDt = time.Now().Format("200601021504")
 
-	var err error
-	data.DocNo, err = s.ID.GenerateID(ctx, "DirectSalesDelivery")
 	data.Remark.SetNullIfEmpty()
 	data.Address.SetNullIfEmpty()
 	data.CityCode.SetNullIfEmpty()
@@ -55,11 +51,10 @@ func (s *TblDirectSalesDelivery) Create(ctx context.Context, data *tbldirectsale
 	data.Phone.SetNullIfEmpty()
 	data.Email.SetNullIfEmpty()
 	data.Mobile.SetNullIfEmpty()
+	data.NPWP.SetNullIfEmpty()
 	data.TaxCode.SetNullIfEmpty()
-	if err != nil {
-		golog.Error(ctx, "Error generate id create direct sales delivery: "+err.Error(), err)
-		return nil, fmt.Errorf("Error generate id create direct sales delivery: " + err.Error())
-	}
+	data.CustomerCode.SetNullIfEmpty()
+	data.TaxInclusive = booldatatype.FromBool(data.TaxInclusive.ToBool())
 
 	t, err := time.Parse("2006-01-02", data.Date)
 	if err != nil {
@@ -70,11 +65,12 @@ func (s *TblDirectSalesDelivery) Create(ctx context.Context, data *tbldirectsale
 	for i := 0; i < len(data.Details); i++ {
 		data.Details[i].DNo = fmt.Sprintf("%03d", i+1)
 		data.Details[i].Cancel = booldatatype.FromBool(false)
+		data.Details[i].SalesOrderDocNo.SetNullIfEmpty()
+		data.Details[i].SalesOrderDNo.SetNullIfEmpty()
 
 		if data.Details[i].BatchNo == "" {
 			data.Details[i].BatchNo = data.Date
 		}
-		data.Details[i].Source = fmt.Sprintf("%s*%s*%s", data.Date[6:8], data.DocNo, data.Details[i].DNo)
 	}
 
 	res, err := s.TemplateRepo.Create(ctx, data)
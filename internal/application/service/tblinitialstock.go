@@ -10,7 +10,6 @@ import (
 	"github.com/runsystemid/golog"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
-	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/domain/tblinitialstock"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/pagination"
@@ -24,8 +23,7 @@ type TblInitStockService interface {
 }
 
 type TblInitStock struct {
-	TemplateRepo tblinitialstock.Repository  `inject:"tblInitStockRepository"`
-	ID           *formatid.GenerateIDHandler `inject:"generateID"`
+	TemplateRepo tblinitialstock.Repository `inject:"tblInitStockRepository"`
 }
 
 func (s *TblInitStock) Fetch(ctx context.Context, doc, warehouse, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -53,13 +51,7 @@ func (s *TblInitStock) Create(ctx context.Context, data *tblinitialstock.Create,
 	data.CreateBy = userName
 	data.CreateDate = time.Now().Format("200601021504")
 
-	var err error
-	data.DocNo, err = s.ID.GenerateID(ctx, "StockInitial")
 	data.Remark.SetNullIfEmpty()
-	if err != nil {
-		golog.Error(ctx, "Error generate id create initial stock: "+err.Error(), err)
-		return nil, fmt.Errorf("Error generate id create initial stock: " + err.Error())
-	}
 
 	t, err := time.Parse("2006-01-02", data.Date)
 	if err != nil {
@@ -77,7 +69,6 @@ func (s *TblInitStock) Create(ctx context.Context, data *tblinitialstock.Create,
 		if data.Detail[i].Batch == "" {
 			data.Detail[i].Batch = data.Date
 		}
-		data.Detail[i].Source = fmt.Sprintf("%s*%s*%s", data.Date[6:8], data.DocNo, data.Detail[i].DNo)
 	}
 
 	res, err := s.TemplateRepo.Create(ctx, data)
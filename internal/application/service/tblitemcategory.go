@@ -7,6 +7,7 @@ import (
 
 	"github.com/runsystemid/golog"
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+	"gitlab.com/ayaka/internal/domain/stockvaluation"
 	"gitlab.com/ayaka/internal/domain/tblitemcategory"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/pagination"
@@ -36,6 +37,9 @@ func (s *TblItemCat) Create(ctx context.Context, data *tblitemcategory.Create, u
 	data.CoaSalesReturn.SetNullIfEmpty()
 	data.CoaStock.SetNullIfEmpty()
 	data.Active = booldatatype.FromBool(true)
+	if data.CostMethod == "" {
+		data.CostMethod = stockvaluation.MovingAverage
+	}
 
 	res, err := s.TemplateRepo.Create(ctx, data)
 	if err != nil {
@@ -39,6 +39,19 @@ func (s *TblItem) Detail(ctx context.Context, itemCode string) (*tblmasteritem.D
 }
 
 func (s *TblItem) Create(ctx context.Context, data *tblmasteritem.Create, userName string, confirm bool) (*tblmasteritem.Create, error) {
+	prepareItemCreate(data, userName)
+
+	res, err := s.TemplateRepo.Create(ctx, data, confirm)
+	if err != nil {
+		golog.Error(ctx, "Error create item: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// prepareItemCreate juga dipakai import master data
+func prepareItemCreate(data *tblmasteritem.Create, userName string) {
 	data.CreateBy = userName
 	data.CreateDate = time.Now().Format("200601021504")
 	data.LocalCode.SetNullIfEmpty()
@@ -53,14 +66,6 @@ func (s *TblItem) Create(ctx context.Context, data *tblmasteritem.Create, userNa
 	data.PurchaseItem = booldatatype.FromBool(data.PurchaseItem.ToBool())
 	data.SalesItem = booldatatype.FromBool(data.SalesItem.ToBool())
 	data.TaxLiable = booldatatype.FromBool(data.TaxLiable.ToBool())
-
-	res, err := s.TemplateRepo.Create(ctx, data, confirm)
-	if err != nil {
-		golog.Error(ctx, "Error create item: "+err.Error(), err)
-		return nil, err
-	}
-
-	return res, nil
 }
 
 func (s *TblItem) Update(ctx context.Context, data *tblmasteritem.Update, userCode string, confirm bool) (*tblmasteritem.Update, error) {
@@ -9,7 +9,6 @@ import (
 
 	"github.com/runsystemid/golog"
 	share "gitlab.com/ayaka/internal/domain/shared"
-	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/domain/tblmaterialreceive"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
@@ -21,7 +20,6 @@ type TblMaterialReceiveService interface {
 
 type TblMaterialReceive struct {
 	TemplateRepo tblmaterialreceive.Repository `inject:"tblMaterialReceiveRepository"`
-	ID           *formatid.GenerateIDHandler   `inject:"generateID"`
 }
 
 func (s *TblMaterialReceive) Fetch(ctx context.Context, doc, warehouseFrom, warehouseTo, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -45,15 +43,8 @@ func (s *TblMaterialReceive) Create(ctx context.Context, data *tblmaterialreceiv
 	data.CreateBy = userName
 	data.CreateDt = time.Now().Format("200601021504")
 
-	var err error
-	data.DocNo, err = s.ID.GenerateID(ctx, "MaterialReceive")
 	data.Remark.SetNullIfEmpty()
 
-	if err != nil {
-		golog.Error(ctx, "Error generate id create material receive: "+err.Error(), err)
-		return nil, fmt.Errorf("Error generate id create material receive: " + err.Error())
-	}
-
 	t, err := time.Parse("2006-01-02", data.Date)
 	if err != nil {
 		return nil, err
@@ -67,7 +58,6 @@ func (s *TblMaterialReceive) Create(ctx context.Context, data *tblmaterialreceiv
 		if data.Details[i].BatchNo == "" {
 			data.Details[i].BatchNo = data.Date
 		}
-		data.Details[i].Source = fmt.Sprintf("%s*%s*%s", data.Date[6:8], data.DocNo, data.Details[i].DNo)
 	}
 
 	res, err := s.TemplateRepo.Create(ctx, data)
@@ -10,7 +10,6 @@ import (
 	"github.com/runsystemid/golog"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
-	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/domain/tblmaterialrequest"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
@@ -25,7 +24,6 @@ type TblMaterialRequestService interface {
 
 type TblMaterialRequest struct {
 	TemplateRepo tblmaterialrequest.Repository `inject:"tblMaterialRequestRepository"`
-	ID           *formatid.GenerateIDHandler   `inject:"generateID"`
 }
 
 func (s *TblMaterialRequest) Fetch(ctx context.Context, doc, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -49,16 +47,9 @@ func (s *TblMaterialRequest) Create(ctx context.Context, data *tblmaterialreques
 	data.CreateBy = userName
 	data.CreateDt = time.Now().Format("200601021504")
 
-	var err error
-	data.DocNo, err = s.ID.GenerateID(ctx, "MaterialRequest")
 	data.SiteCode.SetNullIfEmpty()
 	data.Remark.SetNullIfEmpty()
 
-	if err != nil {
-		golog.Error(ctx, "Error generate id create material request: "+err.Error(), err)
-		return nil, fmt.Errorf("Error generate id create material request: " + err.Error())
-	}
-
 	t, err := time.Parse("2006-01-02", data.Date)
 	if err != nil {
 		return nil, err
@@ -10,7 +10,6 @@ import (
 	"github.com/runsystemid/golog"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
-	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/domain/tblmaterialtransfer"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
@@ -23,7 +22,6 @@ type TblMaterialTransferService interface {
 
 type TblMaterialTransfer struct {
 	TemplateRepo tblmaterialtransfer.Repository `inject:"tblMaterialTransferRepository"`
-	ID           *formatid.GenerateIDHandler    `inject:"generateID"`
 }
 
 func (s *TblMaterialTransfer) Fetch(ctx context.Context, doc, warehouseFrom, warehouseTo, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -48,7 +46,6 @@ func (s *TblMaterialTransfer) Create(ctx context.Context, data *tblmaterialtrans
 	data.CreateDt = time.Now().Format("200601021504")
 
 	var err error
-	data.DocNo, err = s.ID.GenerateID(ctx, "MaterialTransfer")
 	data.Note.SetNullIfEmpty()
 	data.VendorCode.SetNullIfEmpty()
 	data.Driver.SetNullIfEmpty()
@@ -56,11 +53,6 @@ func (s *TblMaterialTransfer) Create(ctx context.Context, data *tblmaterialtrans
 	data.LicenceNo.SetNullIfEmpty()
 	data.Status = "Outstanding"
 
-	if err != nil {
-		golog.Error(ctx, "Error generate id create material transfer: "+err.Error(), err)
-		return nil, fmt.Errorf("Error generate id create material transfer: " + err.Error())
-	}
-
 	t, err := time.Parse("2006-01-02", data.Date)
 	if err != nil {
 		return nil, err
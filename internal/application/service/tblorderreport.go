@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"gitlab.com/ayaka/config"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/tblorderreport"
 	"gitlab.com/ayaka/internal/pkg/pagination"
@@ -16,6 +17,7 @@ type TblOrderReportService interface {
 
 type TblOrderReport struct {
 	TemplateRepo tblorderreport.Repository `inject:"tblOrderReportRepository"`
+	Conf         *config.Config            `inject:"config"`
 }
 
 func (s *TblOrderReport) ByVendor(ctx context.Context, date string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -25,5 +27,5 @@ func (s *TblOrderReport) ByVendor(ctx context.Context, date string, param *pagin
 		date = time.Now().Format("200601")
 	}
 	fmt.Println("date service: ", date)
-	return s.TemplateRepo.ByVendor(ctx, date, param)
-}
\ No newline at end of file
+	return s.TemplateRepo.ByVendor(ctx, date, s.Conf.Currency.Base, param)
+}
@@ -7,9 +7,9 @@ import (
 	"time"
 
 	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/config"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
-	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/domain/tblpurchasematerialreceive"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
@@ -23,7 +23,7 @@ type TblPurchaseMaterialReceiveService interface {
 
 type TblPurchaseMaterialReceive struct {
 	TemplateRepo tblpurchasematerialreceive.Repository `inject:"tblPurchaseMaterialReceiveRepository"`
-	ID           *formatid.GenerateIDHandler           `inject:"generateID"`
+	Conf         *config.Config                        `inject:"config"`
 }
 
 func (s *TblPurchaseMaterialReceive) Fetch(ctx context.Context, doc, warehouse, vendor, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -47,20 +47,15 @@ func (s *TblPurchaseMaterialReceive) Create(ctx context.Context, data *tblpurcha
 	data.CreateBy = userName
 	data.CreateDt = time.Now().Format("200601021504")
 
-	var err error
-	data.DocNo, err = s.ID.GenerateID(ctx, "PurchaseMaterialReceive")
 	data.Remark.SetNullIfEmpty()
 	data.SiteCode.SetNullIfEmpty()
-	if err != nil {
-		golog.Error(ctx, "Error generate id create purchase material receive: "+err.Error(), err)
-		return nil, fmt.Errorf("Error generate id create purchase material receive: " + err.Error())
-	}
 
 	t, err := time.Parse("2006-01-02", data.Date)
 	if err != nil {
 		return nil, err
 	}
 	data.Date = t.Format("20060102")
+	data.BaseCurrency = s.Conf.Currency.Base
 
 	for i := 0; i < len(data.Details); i++ {
 		data.Details[i].DNo = fmt.Sprintf("%03d", i+1)
@@ -69,7 +64,6 @@ func (s *TblPurchaseMaterialReceive) Create(ctx context.Context, data *tblpurcha
 		if data.Details[i].BatchNo == "" {
 			data.Details[i].BatchNo = data.Date
 		}
-		data.Details[i].Source = fmt.Sprintf("%s*%s*%s", data.Date[6:8], data.DocNo, data.Details[i].DNo)
 	}
 
 	res, err := s.TemplateRepo.Create(ctx, data)
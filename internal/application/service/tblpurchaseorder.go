@@ -9,9 +9,9 @@ import (
 	"time"
 
 	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/config"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
-	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/domain/tblpurchaseorder"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/pagination"
@@ -27,7 +27,7 @@ type TblPurchaseOrderService interface {
 
 type TblPurchaseOrder struct {
 	TemplateRepo tblpurchaseorder.Repository `inject:"tblPurchaseOrderRepository"`
-	ID           *formatid.GenerateIDHandler `inject:"generateID"`
+	Conf         *config.Config              `inject:"config"`
 }
 
 func (s *TblPurchaseOrder) Fetch(ctx context.Context, doc, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -51,15 +51,9 @@ func (s *TblPurchaseOrder) Create(ctx context.Context, data *tblpurchaseorder.Cr
 	data.CreateBy = userName
 	data.CreateDt = time.Now().Format("200601021504")
 
-	var err error
-	data.DocNo, err = s.ID.GenerateID(ctx, "PurchaseOrder")
 	data.Remark.SetNullIfEmpty()
 	data.TaxCode.SetNullIfEmpty()
-
-	if err != nil {
-		golog.Error(ctx, "Error generate id create purchase order : "+err.Error(), err)
-		return nil, fmt.Errorf("Error generate id create purchase order : " + err.Error())
-	}
+	data.TaxInclusive = booldatatype.FromBool(data.TaxInclusive.ToBool())
 
 	t, err := time.Parse("2006-01-02", data.Date)
 	if err != nil {
@@ -124,5 +118,5 @@ func (s *TblPurchaseOrder) OutstandingPO(ctx context.Context, doc, startDate, en
 		}
 	}
 
-	return s.TemplateRepo.OutstandingPO(ctx, doc, startDate, endDate, param)
+	return s.TemplateRepo.OutstandingPO(ctx, doc, startDate, endDate, s.Conf.Currency.Base, param)
 }
\ No newline at end of file
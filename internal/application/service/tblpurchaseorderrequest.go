@@ -11,7 +11,6 @@ import (
 	"github.com/runsystemid/golog"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
-	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/domain/tblpurchaseorderrequest"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/pagination"
@@ -26,7 +25,6 @@ type TblPurchaseOrderRequestService interface {
 
 type TblPurchaseOrderRequest struct {
 	TemplateRepo tblpurchaseorderrequest.Repository `inject:"tblPurchaseOrderRequestRepository"`
-	ID           *formatid.GenerateIDHandler        `inject:"generateID"`
 }
 
 func (s *TblPurchaseOrderRequest) Fetch(ctx context.Context, doc, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -50,15 +48,8 @@ func (s *TblPurchaseOrderRequest) Create(ctx context.Context, data *tblpurchaseo
 	data.CreateBy = userName
 	data.CreateDt = time.Now().Format("200601021504")
 
-	var err error
-	data.DocNo, err = s.ID.GenerateID(ctx, "PurchaseOrderRequest")
 	data.Remark.SetNullIfEmpty()
 
-	if err != nil {
-		golog.Error(ctx, "Error generate id create purchase order request: "+err.Error(), err)
-		return nil, fmt.Errorf("Error generate id create purchase order request: " + err.Error())
-	}
-
 	t, err := time.Parse("2006-01-02", data.Date)
 	if err != nil {
 		return nil, err
@@ -9,7 +9,6 @@ import (
 	"github.com/runsystemid/golog"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
-	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/domain/tblpurchasereturndelivery"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
@@ -23,7 +22,6 @@ type TblPurchaseReturnDeliveryService interface {
 
 type TblPurchaseReturnDelivery struct {
 	TemplateRepo tblpurchasereturndelivery.Repository `inject:"tblPurchaseReturnDeliveryRepository"`
-	ID           *formatid.GenerateIDHandler          `inject:"generateID"`
 }
 
 func (s *TblPurchaseReturnDelivery) Fetch(ctx context.Context, doc, warehouse, vendor, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -47,13 +45,7 @@ func (s *TblPurchaseReturnDelivery) Create(ctx context.Context, data *tblpurchas
 	data.CreateBy = userName
 	data.CreateDt = time.Now().Format("200601021504")
 
-	var err error
-	data.DocNo, err = s.ID.GenerateID(ctx, "PurchaseReturnDelivery")
 	data.Remark.SetNullIfEmpty()
-	if err != nil {
-		golog.Error(ctx, "Error generate id create purchase return delivery: "+err.Error(), err)
-		return nil, fmt.Errorf("Error generate id create purchase return delivery: " + err.Error())
-	}
 
 	t, err := time.Parse("2006-01-02", data.Date)
 	if err != nil {
@@ -69,7 +61,6 @@ func (s *TblPurchaseReturnDelivery) Create(ctx context.Context, data *tblpurchas
 		if data.Details[i].BatchNo == "" {
 			data.Details[i].BatchNo = data.Date
 		}
-		data.Details[i].Source = fmt.Sprintf("%s*%s*%s", data.Date[6:8], data.DocNo, data.Details[i].DNo)
 	}
 
 	res, err := s.TemplateRepo.Create(ctx, data)
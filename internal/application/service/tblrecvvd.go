@@ -38,14 +38,8 @@ func (s *TblDirectPurchaseReceive) Create(ctx context.Context, data *tblrecvvdhd
 	data.CreateBy = userName
 	data.CreateDate = time.Now().Format("200601021504")
 
-	var err error
-	data.DocNo, err = s.ID.GenerateID(ctx, "DirectPurchaseReceive")
 	data.Remark.SetNullIfEmpty()
 	data.LocalCode.SetNullIfEmpty()
-	if err != nil {
-		golog.Error(ctx, "Error generate id create direct purchase receive: "+err.Error(), err)
-		return nil, fmt.Errorf("Error generate id create direct purchase receive: " + err.Error())
-	}
 
 	t, err := time.Parse("2006-01-02", data.Date)
 	if err != nil {
@@ -67,7 +61,6 @@ func (s *TblDirectPurchaseReceive) Create(ctx context.Context, data *tblrecvvdhd
 		if data.Detail[i].Batch == "" {
 			data.Detail[i].Batch = data.Date
 		}
-		data.Detail[i].LocalCode = fmt.Sprintf("%s*%s*%s", data.Date[6:8], data.DocNo, data.Detail[i].DNo)
 
 		if data.Detail[i].Expired != "" {
 			tFormat, err := time.Parse("2006-01-02", data.Detail[i].Expired)
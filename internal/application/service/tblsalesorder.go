@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/runsystemid/golog"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+	"gitlab.com/ayaka/internal/domain/tblsalesorder"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type TblSalesOrderService interface {
+	Fetch(ctx context.Context, doc, customer, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Create(ctx context.Context, data *tblsalesorder.Create, userName string) (*tblsalesorder.Create, error)
+	Update(ctx context.Context, data *tblsalesorder.Read, userCode string) (*tblsalesorder.Read, error)
+	Outstanding(ctx context.Context, search, customer, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+}
+
+type TblSalesOrder struct {
+	TemplateRepo tblsalesorder.Repository `inject:"tblSalesOrderRepository"`
+}
+
+func (s *TblSalesOrder) Fetch(ctx context.Context, doc, customer, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	if startDate != "" && endDate != "" {
+		var err error
+		if startDate, err = share.FormatToCompactDateTime(startDate); err != nil {
+			return nil, err
+		}
+		if endDate, err = share.FormatToCompactDateTime(endDate); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.TemplateRepo.Fetch(ctx, doc, customer, startDate, endDate, param)
+}
+
+func (s *TblSalesOrder) Create(ctx context.Context, data *tblsalesorder.Create, userName string) (*tblsalesorder.Create, error) {
+	data.CreateBy = userName
+	data.CreateDt = time.Now().Format("200601021504")
+
+	var err error
+	if data.Date, err = share.FormatToCompactDateTime(data.Date); err != nil {
+		return nil, customerrors.ErrInvalidInput
+	}
+
+	data.TaxCode.SetNullIfEmpty()
+	data.Remark.SetNullIfEmpty()
+	for i := range data.Details {
+		data.Details[i].Remark.SetNullIfEmpty()
+	}
+
+	res, err := s.TemplateRepo.Create(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error create sales order: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *TblSalesOrder) Update(ctx context.Context, data *tblsalesorder.Read, userCode string) (*tblsalesorder.Read, error) {
+	lastUpDt := time.Now().Format("200601021504")
+
+	for i := range data.Details {
+		data.Details[i].Cancel = booldatatype.FromBool(data.Details[i].Cancel.ToBool())
+	}
+
+	res, err := s.TemplateRepo.Update(ctx, userCode, lastUpDt, data)
+	if err != nil {
+		if !errors.Is(err, customerrors.ErrInvalidInput) {
+			golog.Error(ctx, "Error update sales order: "+err.Error(), err)
+		}
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *TblSalesOrder) Outstanding(ctx context.Context, search, customer, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	if startDate != "" && endDate != "" {
+		var err error
+		if startDate, err = share.FormatToCompactDateTime(startDate); err != nil {
+			return nil, err
+		}
+		if endDate, err = share.FormatToCompactDateTime(endDate); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.TemplateRepo.Outstanding(ctx, search, customer, startDate, endDate, param)
+}
@@ -16,7 +16,6 @@ import (
 	"gitlab.com/ayaka/internal/domain/tblstockadjustmenthdr"
 
 	// "gitlab.com/ayaka/internal/domain/shared/booldatatype"
-	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 	// "gitlab.com/ayaka/internal/pkg/customerrors"
 )
@@ -29,7 +28,6 @@ type TblStockAdjustService interface {
 
 type TblStockAdjust struct {
 	TemplateRepo tblstockadjustmenthdr.Repository `inject:"tblStockAdjustRepository"`
-	ID           *formatid.GenerateIDHandler      `inject:"generateID"`
 }
 
 func (s *TblStockAdjust) Fetch(ctx context.Context, doc, warehouse, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -57,13 +55,7 @@ func (s *TblStockAdjust) Create(ctx context.Context, data *tblstockadjustmenthdr
 	data.CreateBy = userName
 	data.CreateDate = time.Now().Format("200601021504")
 
-	var err error
-	data.DocNo, err = s.ID.GenerateID(ctx, "StockAdjustment")
 	data.Remark.SetNullIfEmpty()
-	if err != nil {
-		golog.Error(ctx, "Error generate id create initial stock: "+err.Error(), err)
-		return nil, fmt.Errorf("Error generate id create initial stock: " + err.Error())
-	}
 
 	t, err := time.Parse("2006-01-02", data.Date)
 	if err != nil {
@@ -77,7 +69,6 @@ func (s *TblStockAdjust) Create(ctx context.Context, data *tblstockadjustmenthdr
 		if data.Details[i].Batch == "" {
 			data.Details[i].Batch = data.Date
 		}
-		data.Details[i].Source = fmt.Sprintf("%s*%s*%s", data.Date[6:8], data.DocNo, data.Details[i].DNo)
 	}
 
 	res, err := s.TemplateRepo.Create(ctx, data)
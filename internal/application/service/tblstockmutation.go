@@ -14,7 +14,6 @@ import (
 
 	// "gitlab.com/ayaka/internal/domain/shared/booldatatype"
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
-	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/domain/tblstockmutationhdr"
 
 	// "gitlab.com/ayaka/internal/pkg/customerrors"
@@ -31,7 +30,6 @@ type TblStockMutationService interface {
 
 type TblStockMutation struct {
 	TemplateRepo tblstockmutationhdr.Repository `inject:"tblStockMutationRepository"`
-	ID           *formatid.GenerateIDHandler    `inject:"generateID"`
 }
 
 func (s *TblStockMutation) Fetch(ctx context.Context, doc, warehouse, batch string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -46,13 +44,6 @@ func (s *TblStockMutation) Create(ctx context.Context, data *tblstockmutationhdr
 	data.CreateBy = userName
 	data.CreateDate = time.Now().Format("200601021504")
 
-	var err error
-	data.DocNo, err = s.ID.GenerateID(ctx, "StockMutation")
-	if err != nil {
-		golog.Error(ctx, "Error generate id create initial stock: "+err.Error(), err)
-		return nil, fmt.Errorf("Error generate id create initial stock: " + err.Error())
-	}
-
 	data.Remark.SetNullIfEmpty()
 
 	t, err := time.Parse("2006-01-02", data.DocDate)
@@ -76,7 +67,6 @@ func (s *TblStockMutation) Create(ctx context.Context, data *tblstockmutationhdr
 		if data.FromArray[i].BatchNo == "" {
 			data.FromArray[i].BatchNo = data.DocDate
 		}
-		data.FromArray[i].Source = fmt.Sprintf("%s*%s*%s", data.DocDate[4:6], data.DocNo, data.FromArray[i].DNo)
 	}
 
 	for i := 0; i < len(data.ToArray); i++ {
@@ -89,7 +79,6 @@ func (s *TblStockMutation) Create(ctx context.Context, data *tblstockmutationhdr
 		if data.ToArray[i].BatchNo == "" {
 			data.ToArray[i].BatchNo = data.DocDate
 		}
-		data.ToArray[i].Source = fmt.Sprintf("%s*%s*%s", data.DocDate[6:8], data.DocNo, data.ToArray[i].DNo)
 	}
 
 	return s.TemplateRepo.Create(ctx, data)
@@ -13,21 +13,25 @@ import (
 	share "gitlab.com/ayaka/internal/domain/shared"
 	// "gitlab.com/ayaka/internal/domain/shared/booldatatype"
 	"gitlab.com/ayaka/internal/domain/shared/formatid"
+	sharedfunc "gitlab.com/ayaka/internal/domain/shared/sharedFunc"
 	"gitlab.com/ayaka/internal/domain/tblstockmovement"
+	"gitlab.com/ayaka/internal/domain/uomconversion"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
 
 type TblStockMovementService interface {
-	Fetch(ctx context.Context, warehouse, dateRangeStart, dateRangeEnd, docType, itemCategory, itemName, batch string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Fetch(ctx context.Context, warehouse, dateRangeStart, dateRangeEnd, docType, itemCategory, itemName, batch, uom string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
 }
 
 type TblStockMovement struct {
 	TemplateRepo tblstockmovement.Repository `inject:"tblStockMovementRepository"`
 	ID           *formatid.GenerateIDHandler `inject:"generateID"`
+	Uom          uomconversion.Repository    `inject:"uomConversionRepository"`
 }
 
-func (s *TblStockMovement) Fetch(ctx context.Context, warehouse, dateRangeStart, dateRangeEnd, docType, itemCategory, itemName, batch string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+// Fetch qty dalam UoM inventory kecuali uom diisi
+func (s *TblStockMovement) Fetch(ctx context.Context, warehouse, dateRangeStart, dateRangeEnd, docType, itemCategory, itemName, batch, uom string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
 	var arrayWarehouse []string
 	var err error
 
@@ -50,5 +54,27 @@ func (s *TblStockMovement) Fetch(ctx context.Context, warehouse, dateRangeStart,
 			return nil, err
 		}
 	}
-	return s.TemplateRepo.Fetch(ctx, arrayWarehouse, dateRangeStart, dateRangeEnd, docType, itemCategory, itemName, batch, param)
+	res, err := s.TemplateRepo.Fetch(ctx, arrayWarehouse, dateRangeStart, dateRangeEnd, docType, itemCategory, itemName, batch, param)
+	if err != nil || uom == "" {
+		return res, err
+	}
+
+	rows, ok := res.Data.([]*tblstockmovement.Fetch)
+	if !ok {
+		return res, nil
+	}
+	itemCodes := make([]string, 0, len(rows))
+	for _, r := range rows {
+		itemCodes = append(itemCodes, r.ItCode)
+	}
+	uomName, factors, err := s.Uom.ToUom(ctx, sharedfunc.UniqueStringSlice(itemCodes), uom)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		r.Qty *= factors[r.ItCode]
+		r.UomName = uomName
+	}
+
+	return res, nil
 }
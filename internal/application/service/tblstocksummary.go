@@ -14,21 +14,25 @@ import (
 	// "gitlab.com/ayaka/internal/domain/shared/booldatatype"
 	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/domain/tblstocksummary"
+	"gitlab.com/ayaka/internal/domain/uomconversion"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
 
 type TblStockSummaryService interface {
-	Fetch(ctx context.Context, warehouse, date, itemCatCode, itemCode, itemName string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Fetch(ctx context.Context, warehouse, date, itemCatCode, itemCode, itemName, uom string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
 	GetItem(ctx context.Context, itemName, itemCatCode, batch, warehouse string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
 }
 
 type TblStockSummary struct {
 	TemplateRepo tblstocksummary.Repository  `inject:"tblStockSummaryRepository"`
 	ID           *formatid.GenerateIDHandler `inject:"generateID"`
+	Uom          uomconversion.Repository    `inject:"uomConversionRepository"`
 }
 
-func (s *TblStockSummary) Fetch(ctx context.Context, warehouse, date, itemCatCode, itemCode, itemName string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+// Fetch qty dalam UoM inventory, uom diisi untuk menampilkan dalam UoM lain.
+// Item yang tidak punya konversi ke uom tersebut ditolak dengan ErrUomConversion.
+func (s *TblStockSummary) Fetch(ctx context.Context, warehouse, date, itemCatCode, itemCode, itemName, uom string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
 	var arrayWarehouse []string
 	var err error
 
@@ -44,7 +48,29 @@ func (s *TblStockSummary) Fetch(ctx context.Context, warehouse, date, itemCatCod
 			return nil, err
 		}
 	}
-	return s.TemplateRepo.Fetch(ctx, arrayWarehouse, date, itemCatCode, itemCode, itemName, param)
+	res, err := s.TemplateRepo.Fetch(ctx, arrayWarehouse, date, itemCatCode, itemCode, itemName, param)
+	if err != nil || uom == "" {
+		return res, err
+	}
+
+	rows, ok := res.Data.([]*tblstocksummary.Fetch)
+	if !ok {
+		return res, nil
+	}
+	itemCodes := make([]string, 0, len(rows))
+	for _, r := range rows {
+		itemCodes = append(itemCodes, r.ItemCode)
+	}
+	uomName, factors, err := s.Uom.ToUom(ctx, itemCodes, uom)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		r.Quantity *= factors[r.ItemCode]
+		r.Uom = uomName
+	}
+
+	return res, nil
 }
 
 func (s *TblStockSummary) GetItem(ctx context.Context, itemName, itemCatCode, batch, warehouse string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
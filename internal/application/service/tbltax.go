@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+	"gitlab.com/ayaka/internal/domain/taxengine"
 	"gitlab.com/ayaka/internal/domain/tbltax"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/pagination"
@@ -28,6 +30,10 @@ func (s *TblTax) Fetch(ctx context.Context, search, category string, param *pagi
 func (s *TblTax) Create(ctx context.Context, data *tbltax.Create, userName string) (*tbltax.Create, error) {
 	data.CreateBy = userName
 	data.CreateDate = time.Now().Format("200601021504")
+	data.Withholding = booldatatype.FromBool(data.Withholding.ToBool())
+	if data.RoundingMode == "" {
+		data.RoundingMode = taxengine.RoundHalfUp
+	}
 
 	res, err := s.TemplateRepo.Create(ctx, data)
 	if err != nil {
@@ -41,6 +47,10 @@ func (s *TblTax) Create(ctx context.Context, data *tbltax.Create, userName strin
 func (s *TblTax) Update(ctx context.Context, data *tbltax.Update, userCode string) (*tbltax.Update, error) {
 	data.LastUpdateBy = userCode
 	data.LastUpdateDate = time.Now().Format("200601021504")
+	data.Withholding = booldatatype.FromBool(data.Withholding.ToBool())
+	if data.RoundingMode == "" {
+		data.RoundingMode = taxengine.RoundHalfUp
+	}
 
 	res, err := s.TemplateRepo.Update(ctx, data)
 	if err != nil {
@@ -8,6 +8,8 @@ import (
 	"github.com/runsystemid/golog"
 	"gitlab.com/ayaka/config"
 	"gitlab.com/ayaka/internal/domain/tbluser"
+	"gitlab.com/ayaka/internal/domain/tblusergroup"
+	"gitlab.com/ayaka/internal/domain/tbluserscope"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/custommiddleware"
 	"gitlab.com/ayaka/internal/pkg/email"
@@ -25,6 +27,8 @@ type TblUserService interface {
 type TblUser struct {
 	TemplateRepo      tbluser.Repository                   `inject:"tblUserRepository"`
 	TemplateCacheRepo tbluser.RepositoryLoginCache         `inject:"tblUserCacheRepository"`
+	GroupRepo         tblusergroup.Repository              `inject:"tblUserGroupRepository"`
+	ScopeRepo         tbluserscope.Repository              `inject:"tblUserScopeRepository"`
 	JwtHandler        *share.JwtHandler                    `inject:"jwtHandler"`
 	EmailHandler      *email.MailSMTP                      `inject:"mail"`
 	Conf              *config.Config                       `inject:"config"`
@@ -45,7 +49,25 @@ func (t *TblUser) Login(ctx context.Context, data *tbluser.Logintbluser) (string
 		return "", 0, customerrors.ErrInvalidPassword
 	}
 
-	token, _ := t.JwtHandler.GenerateToken(user.UserCode, user.UserName, t.Conf.JWT.JWTKEY, time.Duration(t.Conf.JWT.JWTDuration), time.Hour)
+	permissions, err := t.GroupRepo.PermissionsOf(ctx, user.UserCode)
+	if err != nil {
+		golog.Error(ctx, "Error get user permission: "+err.Error(), err)
+		return "", 0, err
+	}
+
+	scope, err := t.ScopeRepo.Detail(ctx, user.UserCode)
+	if err != nil {
+		golog.Error(ctx, "Error get user scope: "+err.Error(), err)
+		return "", 0, err
+	}
+
+	access := share.Access{
+		Permissions: permissions,
+		Warehouses:  scope.Warehouses,
+		Sites:       scope.Sites,
+	}
+
+	token, _ := t.JwtHandler.GenerateToken(user.UserCode, user.UserName, access, t.Conf.JWT.JWTKEY, time.Duration(t.Conf.JWT.JWTDuration), time.Hour)
 	tokenReturn, err := t.TemplateCacheRepo.Login(ctx, token)
 
 	if err != nil {
@@ -79,7 +101,7 @@ func (t *TblUser) SendEmailForgotPassword(ctx context.Context, emailUser string)
 		return nil, err
 	}
 
-	tokenJWT, err := t.JwtHandler.GenerateToken(user.UserCode, user.UserName, t.Conf.JWT.ChangePassKey, time.Duration(t.Conf.JWT.ChangePassDuration), time.Minute)
+	tokenJWT, err := t.JwtHandler.GenerateToken(user.UserCode, user.UserName, share.Access{}, t.Conf.JWT.ChangePassKey, time.Duration(t.Conf.JWT.ChangePassDuration), time.Minute)
 	if err != nil {
 		golog.Error(ctx, "Error send email: "+err.Error(), err)
 		return nil, err
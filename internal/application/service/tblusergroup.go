@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/internal/domain/tblusergroup"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type TblUserGroupService interface {
+	Fetch(ctx context.Context, search string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Detail(ctx context.Context, code string) (*tblusergroup.Detail, error)
+	Create(ctx context.Context, data *tblusergroup.Create, userName string) (*tblusergroup.Create, error)
+	Update(ctx context.Context, data *tblusergroup.Update, userCode string) (*tblusergroup.Update, error)
+	AssignUser(ctx context.Context, data *tblusergroup.AssignUser, userCode string) (*tblusergroup.AssignUser, error)
+}
+
+type TblUserGroup struct {
+	TemplateRepo tblusergroup.Repository `inject:"tblUserGroupRepository"`
+}
+
+func (s *TblUserGroup) Fetch(ctx context.Context, search string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	return s.TemplateRepo.Fetch(ctx, search, param)
+}
+
+func (s *TblUserGroup) Detail(ctx context.Context, code string) (*tblusergroup.Detail, error) {
+	return s.TemplateRepo.Detail(ctx, code)
+}
+
+func (s *TblUserGroup) Create(ctx context.Context, data *tblusergroup.Create, userName string) (*tblusergroup.Create, error) {
+	data.CreateBy = userName
+	data.CreateDate = time.Now().Format("200601021504")
+	data.Permissions = uniquePermissions(data.Permissions)
+
+	res, err := s.TemplateRepo.Create(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error create user group: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *TblUserGroup) Update(ctx context.Context, data *tblusergroup.Update, userCode string) (*tblusergroup.Update, error) {
+	data.LastUpdateBy = userCode
+	data.LastUpdateDate = time.Now().Format("200601021504")
+	data.Permissions = uniquePermissions(data.Permissions)
+
+	res, err := s.TemplateRepo.Update(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error update user group: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *TblUserGroup) AssignUser(ctx context.Context, data *tblusergroup.AssignUser, userCode string) (*tblusergroup.AssignUser, error) {
+	data.LastUpdateBy = userCode
+	data.LastUpdateDate = time.Now().Format("200601021504")
+
+	res, err := s.TemplateRepo.AssignUser(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error assign user group: "+err.Error(), err)
+		if errors.Is(err, customerrors.ErrNoDataEdited) {
+			return data, err
+		}
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// permission yang sama dikirim dua kali akan melanggar primary key tblgroupmenu
+func uniquePermissions(permissions []tblusergroup.Permission) []tblusergroup.Permission {
+	seen := make(map[tblusergroup.Permission]bool, len(permissions))
+	result := make([]tblusergroup.Permission, 0, len(permissions))
+	for _, permission := range permissions {
+		if !seen[permission] {
+			seen[permission] = true
+			result = append(result, permission)
+		}
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/internal/domain/tbluserscope"
+)
+
+type TblUserScopeService interface {
+	Detail(ctx context.Context, userCode string) (*tbluserscope.Detail, error)
+	Update(ctx context.Context, data *tbluserscope.Update, userCode string) (*tbluserscope.Update, error)
+}
+
+type TblUserScope struct {
+	TemplateRepo tbluserscope.Repository `inject:"tblUserScopeRepository"`
+}
+
+func (s *TblUserScope) Detail(ctx context.Context, userCode string) (*tbluserscope.Detail, error) {
+	return s.TemplateRepo.Detail(ctx, userCode)
+}
+
+func (s *TblUserScope) Update(ctx context.Context, data *tbluserscope.Update, userCode string) (*tbluserscope.Update, error) {
+	data.LastUpdateBy = userCode
+	data.LastUpdateDate = time.Now().Format("200601021504")
+
+	// kode yang sama dua kali akan melanggar primary key
+	slices.Sort(data.Warehouses)
+	data.Warehouses = slices.Compact(data.Warehouses)
+	slices.Sort(data.Sites)
+	data.Sites = slices.Compact(data.Sites)
+
+	res, err := s.TemplateRepo.Update(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error update user scope: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -3,12 +3,10 @@ package service
 import (
 	"context"
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/runsystemid/golog"
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
-	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/domain/tblmastervendor"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/pagination"
@@ -23,22 +21,20 @@ type TblVendorService interface {
 }
 
 type TblVendor struct {
-	TemplateRepo tblmastervendor.Repository  `inject:"tblVendorRepository"`
-	ID           *formatid.GenerateIDHandler `inject:"generateID"`
+	TemplateRepo tblmastervendor.Repository `inject:"tblVendorRepository"`
 }
 
 func (s *TblVendor) Create(ctx context.Context, data *tblmastervendor.Create, userName string) (*tblmastervendor.Create, error) {
+	prepareVendorCreate(data, userName)
+
+	return s.TemplateRepo.Create(ctx, data)
+}
+
+// prepareVendorCreate juga dipakai import master data
+func prepareVendorCreate(data *tblmastervendor.Create, userName string) {
 	data.CreateBy = userName
 	data.CreateDate = time.Now().Format("200601021504")
 
-	// Generate VendorCode
-	vendorCode, err := s.ID.GenerateIDCode(ctx, "tblvendorhdr")
-	if err != nil {
-		golog.Error(ctx, "Error generate id create initial stock: "+err.Error(), err)
-		return nil, fmt.Errorf("Error generate id create initial stock: " + err.Error())
-	}
-	data.VendorCode = vendorCode
-
 	// setup nullable and boolean
 	data.Address.SetNullIfEmpty()
 	data.PostalCode.SetNullIfEmpty()
@@ -48,6 +44,8 @@ func (s *TblVendor) Create(ctx context.Context, data *tblmastervendor.Create, us
 	data.Mobile.SetNullIfEmpty()
 	data.Email.SetNullIfEmpty()
 	data.Remark.SetNullIfEmpty()
+	data.TaxGroupCode.SetNullIfEmpty()
+	data.NPWP.SetNullIfEmpty()
 
 	if len(data.ContactVendor) != 0 {
 		for i := range data.ContactVendor {
@@ -84,8 +82,6 @@ func (s *TblVendor) Create(ctx context.Context, data *tblmastervendor.Create, us
 			data.RatingVendor[i].Active = booldatatype.FromBool(true)
 		}
 	}
-
-	return s.TemplateRepo.Create(ctx, data)
 }
 
 func (s *TblVendor) Fetch(ctx context.Context, name, cat string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -109,6 +105,8 @@ func (s *TblVendor) Update(ctx context.Context, data *tblmastervendor.Update, us
 	data.Mobile.SetNullIfEmpty()
 	data.Email.SetNullIfEmpty()
 	data.Remark.SetNullIfEmpty()
+	data.TaxGroupCode.SetNullIfEmpty()
+	data.NPWP.SetNullIfEmpty()
 
 	if len(data.ContactVendor) != 0 {
 		for i := range data.ContactVendor {
@@ -10,7 +10,6 @@ import (
 	"github.com/runsystemid/golog"
 	share "gitlab.com/ayaka/internal/domain/shared"
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
-	"gitlab.com/ayaka/internal/domain/shared/formatid"
 	"gitlab.com/ayaka/internal/domain/tblvendorquotation"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
 	"gitlab.com/ayaka/internal/pkg/pagination"
@@ -24,8 +23,7 @@ type TblVendorQuotationService interface {
 }
 
 type TblVendorQuotation struct {
-	TemplateRepo tblvendorquotation.Repository  `inject:"tblVendorQuotationRepository"`
-	ID           *formatid.GenerateIDHandler `inject:"generateID"`
+	TemplateRepo tblvendorquotation.Repository `inject:"tblVendorQuotationRepository"`
 }
 
 func (s *TblVendorQuotation) Fetch(ctx context.Context, doc, vendor, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
@@ -49,13 +47,7 @@ func (s *TblVendorQuotation) Create(ctx context.Context, data *tblvendorquotatio
 	data.CreateBy = userName
 	data.CreateDt = time.Now().Format("200601021504")
 
-	var err error
-	data.DocNo, err = s.ID.GenerateID(ctx, "VendorQuotation")
 	data.Remark.SetNullIfEmpty()
-	if err != nil {
-		golog.Error(ctx, "Error generate id create vendor quotation: "+err.Error(), err)
-		return nil, fmt.Errorf("Error generate id create vendor quotation: " + err.Error())
-	}
 
 	t, err := time.Parse("2006-01-02", data.Date)
 	if err != nil {
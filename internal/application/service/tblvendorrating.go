@@ -35,6 +35,9 @@ func (s *TblVendorRating) Create(ctx context.Context, data *tblvendorrating.Crea
 	data.CreateBy = userName
 	data.CreateDate = time.Now().Format("200601021504")
 	data.Active = booldatatype.FromBool(data.Active.ToBool())
+	if data.Weight == 0 {
+		data.Weight = 1
+	}
 
 	res, err := s.TemplateRepo.Create(ctx, data)
 	if err != nil {
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/internal/domain/uomconversion"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type UomConversionService interface {
+	Fetch(ctx context.Context, itemCode, uom string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Save(ctx context.Context, data *uomconversion.Conversion, userName string) (*uomconversion.Conversion, error)
+	Delete(ctx context.Context, itemCode, uomFrom, uomTo string) error
+}
+
+type UomConversion struct {
+	TemplateRepo uomconversion.Repository `inject:"uomConversionRepository"`
+}
+
+func (s *UomConversion) Fetch(ctx context.Context, itemCode, uom string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	return s.TemplateRepo.Fetch(ctx, itemCode, uom, param)
+}
+
+func (s *UomConversion) Save(ctx context.Context, data *uomconversion.Conversion, userName string) (*uomconversion.Conversion, error) {
+	data.CreateBy = userName
+	data.CreateDate = time.Now().Format("200601021504")
+
+	res, err := s.TemplateRepo.Save(ctx, data)
+	if err != nil {
+		golog.Error(ctx, "Error save uom conversion: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *UomConversion) Delete(ctx context.Context, itemCode, uomFrom, uomTo string) error {
+	return s.TemplateRepo.Delete(ctx, itemCode, uomFrom, uomTo)
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/runsystemid/golog"
+	"gitlab.com/ayaka/config"
+	share "gitlab.com/ayaka/internal/domain/shared"
+	"gitlab.com/ayaka/internal/domain/vendorinvoice"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type VendorInvoiceService interface {
+	Fetch(ctx context.Context, doc, vendor string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Detail(ctx context.Context, docNo string) (*vendorinvoice.Invoice, error)
+	Create(ctx context.Context, data *vendorinvoice.Invoice, userName string) (*vendorinvoice.Invoice, error)
+	Payables(ctx context.Context, vendor, dueDate string) ([]*vendorinvoice.Payable, error)
+}
+
+type VendorInvoice struct {
+	TemplateRepo vendorinvoice.Repository `inject:"vendorInvoiceRepository"`
+	Conf         *config.Config           `inject:"config"`
+}
+
+func (s *VendorInvoice) Fetch(ctx context.Context, doc, vendor string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error) {
+	return s.TemplateRepo.Fetch(ctx, doc, vendor, param)
+}
+
+func (s *VendorInvoice) Detail(ctx context.Context, docNo string) (*vendorinvoice.Invoice, error) {
+	return s.TemplateRepo.Detail(ctx, docNo)
+}
+
+// Create toleransi matching diambil dari konfigurasi purchase
+func (s *VendorInvoice) Create(ctx context.Context, data *vendorinvoice.Invoice, userName string) (*vendorinvoice.Invoice, error) {
+	data.CreateBy = userName
+	data.CreateDate = time.Now().Format("200601021504")
+
+	var err error
+	if data.Date, err = share.FormatToCompactDateTime(data.Date); err != nil {
+		return nil, customerrors.ErrInvalidInput
+	}
+
+	data.Remark.SetNullIfEmpty()
+	for i := range data.Details {
+		data.Details[i].Remark.SetNullIfEmpty()
+	}
+
+	var tolerance vendorinvoice.Tolerance
+	if s.Conf != nil {
+		tolerance = vendorinvoice.Tolerance{
+			Qty:   s.Conf.Purchase.QtyTolerance,
+			Price: s.Conf.Purchase.PriceTolerance,
+		}
+	}
+
+	res, err := s.TemplateRepo.Create(ctx, data, tolerance)
+	if err != nil {
+		golog.Error(ctx, "Error create vendor invoice: "+err.Error(), err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *VendorInvoice) Payables(ctx context.Context, vendor, dueDate string) ([]*vendorinvoice.Payable, error) {
+	if dueDate != "" {
+		var err error
+		if dueDate, err = share.FormatToCompactDateTime(dueDate); err != nil {
+			return nil, customerrors.ErrInvalidInput
+		}
+	}
+
+	return s.TemplateRepo.Payables(ctx, vendor, dueDate, s.Conf.Currency.Base)
+}
@@ -26,6 +26,21 @@ func RegisterRepository() {
 	appContainer.RegisterService("tblUserRepository", new(sqlx.TblUserRepository))
 	appContainer.RegisterService("tblUserCacheRepository", new(cache.TblUserRepository))
 
+	appContainer.RegisterService("inventoryLedgerRepository", new(sqlx.InventoryLedgerRepository))
+	appContainer.RegisterService("stockValuationRepository", new(sqlx.StockValuationRepository))
+	appContainer.RegisterService("journalRepository", new(sqlx.JournalRepository))
+	appContainer.RegisterService("stockCardRepository", new(sqlx.StockCardRepository))
+	appContainer.RegisterService("stockOpnameRepository", new(sqlx.StockOpnameRepository))
+	appContainer.RegisterService("binRepository", new(sqlx.BinRepository))
+	appContainer.RegisterService("batchRepository", new(sqlx.BatchRepository))
+	appContainer.RegisterService("replenishmentRepository", new(sqlx.ReplenishmentRepository))
+	appContainer.RegisterService("uomConversionRepository", new(sqlx.UomConversionRepository))
+	appContainer.RegisterService("vendorInvoiceRepository", new(sqlx.VendorInvoiceRepository))
+	appContainer.RegisterService("exchangeRateRepository", new(sqlx.ExchangeRateRepository))
+	appContainer.RegisterService("quotationComparisonRepository", new(sqlx.QuotationComparisonRepository))
+	appContainer.RegisterService("tblCustomerRepository", new(sqlx.TblCustomerRepository))
+	appContainer.RegisterService("tblSalesOrderRepository", new(sqlx.TblSalesOrderRepository))
+
 	appContainer.RegisterService("tblCountryRepository", new(sqlx.TblCountryRepository))
 
 	appContainer.RegisterService("tblProvinceRepository", new(sqlx.TblProvinceRepository))
@@ -62,6 +77,8 @@ func RegisterRepository() {
 
 	appContainer.RegisterService("tblTaxGroupRepository", new(sqlx.TblTaxGroupRepository))
 	appContainer.RegisterService("tblTaxRepository", new(sqlx.TblTaxRepository))
+	appContainer.RegisterService("taxEngineRepository", new(sqlx.TaxEngineRepository))
+	appContainer.RegisterService("efakturRepository", new(sqlx.EfakturRepository))
 	appContainer.RegisterService("tblCustomerCategoryRepository", new(sqlx.TblCustomerCategoryRepository))
 	appContainer.RegisterService("tblSiteRepository", new(sqlx.TblSiteRepository))
 	appContainer.RegisterService("tblVendorCategoryRepository", new(sqlx.TblVendorCategoryRepository))
@@ -73,6 +90,7 @@ func RegisterRepository() {
 	appContainer.RegisterService("tblDirectPurchaseRcvRepository", new(sqlx.TblDirectPurchaseRcvRepository))
 	appContainer.RegisterService("tblDirectSalesDeliveryRepository", new(sqlx.TblDirectSalesDeliveryRepository))
 	appContainer.RegisterService("tblMaterialTransferRepository", new(sqlx.TblMaterialTransferRepository))
+	appContainer.RegisterService("inTransitRepository", new(sqlx.InTransitRepository))
 	appContainer.RegisterService("tblMaterialReceiveRepository", new(sqlx.TblMaterialReceiveRepository))
 	appContainer.RegisterService("tblTransferItemBetweenWhsRepository", new(sqlx.TblTransferItemBetweenWhsRepository))
 	appContainer.RegisterService("tblDirectMaterialReceiveRepository", new(sqlx.TblDirectMaterialReceiveRepository))
@@ -84,6 +102,11 @@ func RegisterRepository() {
 	appContainer.RegisterService("tblPurchaseReturnDeliveryRepository", new(sqlx.TblPurchaseReturnDeliveryRepository))
 	appContainer.RegisterService("tblOrderReportRepository", new(sqlx.TblOrderReportRepository))
 	appContainer.RegisterService("DashboardRepository", new(sqlx.DashboardRepository))
+	appContainer.RegisterService("tblUserGroupRepository", new(sqlx.TblUserGroupRepository))
+	appContainer.RegisterService("tblUserScopeRepository", new(sqlx.TblUserScopeRepository))
+	appContainer.RegisterService("docApprovalRepository", new(sqlx.DocApprovalRepository))
+	appContainer.RegisterService("printoutRepository", new(sqlx.PrintoutRepository))
+	appContainer.RegisterService("masterImportRepository", new(sqlx.MasterImportRepository))
 }
 
 func RegisterHandler() {
@@ -27,6 +27,8 @@ func RegisterService() {
 	appContainer.RegisterService("tblDirectPurchaseReceiveService", new(service.TblDirectPurchaseReceive))
 	appContainer.RegisterService("tblTaxGroupService", new(service.TblTaxGroup))
 	appContainer.RegisterService("tblTaxService", new(service.TblTax))
+	appContainer.RegisterService("taxEngineService", new(service.TaxEngine))
+	appContainer.RegisterService("efakturService", new(service.Efaktur))
 	appContainer.RegisterService("tblCustomerCategoryService", new(service.TblCustomerCategory))
 	appContainer.RegisterService("tblSiteService", new(service.TblSite))
 	appContainer.RegisterService("tblVendorCategoryService", new(service.TblVendorCategory))
@@ -38,6 +40,7 @@ func RegisterService() {
 	appContainer.RegisterService("tblDirectPurchaseRcvService", new(service.TblDirectPurchaseRcv))
 	appContainer.RegisterService("tblDirectSalesDeliveryService", new(service.TblDirectSalesDelivery))
 	appContainer.RegisterService("tblMaterialTransferService", new(service.TblMaterialTransfer))
+	appContainer.RegisterService("inTransitService", new(service.InTransit))
 	appContainer.RegisterService("tblMaterialReceiveService", new(service.TblMaterialReceive))
 	appContainer.RegisterService("tblTransferItemBetweenWhsService", new(service.TblTransferItemBetweenWhs))
 	appContainer.RegisterService("tblDirectMaterialReceiveService", new(service.TblDirectMaterialReceive))
@@ -49,6 +52,24 @@ func RegisterService() {
 	appContainer.RegisterService("tblPurchaseReturnDeliveryService", new(service.TblPurchaseReturnDelivery))
 	appContainer.RegisterService("tblOrderReportService", new(service.TblOrderReport))
 	appContainer.RegisterService("DashboardService", new(service.Dashboard))
+	appContainer.RegisterService("tblUserGroupService", new(service.TblUserGroup))
+	appContainer.RegisterService("tblUserScopeService", new(service.TblUserScope))
+	appContainer.RegisterService("docApprovalService", new(service.DocApproval))
+	appContainer.RegisterService("printoutService", new(service.Printout))
+	appContainer.RegisterService("masterImportService", new(service.MasterImport))
+	appContainer.RegisterService("stockValuationService", new(service.StockValuation))
+	appContainer.RegisterService("journalService", new(service.Journal))
+	appContainer.RegisterService("stockCardService", new(service.StockCard))
+	appContainer.RegisterService("stockOpnameService", new(service.StockOpname))
+	appContainer.RegisterService("binService", new(service.Bin))
+	appContainer.RegisterService("batchService", new(service.Batch))
+	appContainer.RegisterService("replenishmentService", new(service.Replenishment))
+	appContainer.RegisterService("uomConversionService", new(service.UomConversion))
+	appContainer.RegisterService("vendorInvoiceService", new(service.VendorInvoice))
+	appContainer.RegisterService("quotationComparisonService", new(service.QuotationComparison))
+	appContainer.RegisterService("tblCustomerService", new(service.TblCustomer))
+	appContainer.RegisterService("tblSalesOrderService", new(service.TblSalesOrder))
+	appContainer.RegisterService("exchangeRateService", new(service.ExchangeRate))
 }
 
 func RegisterApi() {
@@ -75,6 +96,8 @@ func RegisterApi() {
 	appContainer.RegisterService("tblDirectPurchaseReceiveHandler", new(api.TblDirectPurchaseReceiveHandler))
 	appContainer.RegisterService("tblTaxGroupHandler", new(api.TblTaxGroupHandler))
 	appContainer.RegisterService("tblTaxHandler", new(api.TblTaxHandler))
+	appContainer.RegisterService("taxEngineHandler", new(api.TaxEngineHandler))
+	appContainer.RegisterService("efakturHandler", new(api.EfakturHandler))
 	appContainer.RegisterService("tblCustomerCategoryHandler", new(api.TblCustomerCategoryHandler))
 	appContainer.RegisterService("tblSiteHandler", new(api.TblSiteHandler))
 	appContainer.RegisterService("tblVendorCategoryHandler", new(api.TblVendorCategoryHandler))
@@ -86,6 +109,7 @@ func RegisterApi() {
 	appContainer.RegisterService("tblDirectPurchaseRcvHandler", new(api.TblDirectPurchaseRcvHandler))
 	appContainer.RegisterService("tblDirectSalesDeliveryHandler", new(api.TblDirectSalesDeliveryHandler))
 	appContainer.RegisterService("tblMaterialTransferHandler", new(api.TblMaterialTransferHandler))
+	appContainer.RegisterService("inTransitHandler", new(api.InTransitHandler))
 	appContainer.RegisterService("tblMaterialReceiveHandler", new(api.TblMaterialReceiveHandler))
 	appContainer.RegisterService("tblTransferItemBetweenWhsHandler", new(api.TblTransferItemBetweenWhsHandler))
 	appContainer.RegisterService("tblDirectMaterialReceiveHandler", new(api.TblDirectMaterialReceiveHandler))
@@ -97,6 +121,24 @@ func RegisterApi() {
 	appContainer.RegisterService("tblPurchaseReturnDeliveryHandler", new(api.TblPurchaseReturnDeliveryHandler))
 	appContainer.RegisterService("tblOrderReportHandler", new(api.TblOrderReportHandler))
 	appContainer.RegisterService("DashboardHandler", new(api.DashboardHandler))
+	appContainer.RegisterService("tblUserGroupHandler", new(api.TblUserGroupHandler))
+	appContainer.RegisterService("tblUserScopeHandler", new(api.TblUserScopeHandler))
+	appContainer.RegisterService("docApprovalHandler", new(api.DocApprovalHandler))
+	appContainer.RegisterService("printoutHandler", new(api.PrintoutHandler))
+	appContainer.RegisterService("masterImportHandler", new(api.MasterImportHandler))
+	appContainer.RegisterService("stockValuationHandler", new(api.StockValuationHandler))
+	appContainer.RegisterService("journalHandler", new(api.JournalHandler))
+	appContainer.RegisterService("stockCardHandler", new(api.StockCardHandler))
+	appContainer.RegisterService("stockOpnameHandler", new(api.StockOpnameHandler))
+	appContainer.RegisterService("binHandler", new(api.BinHandler))
+	appContainer.RegisterService("batchHandler", new(api.BatchHandler))
+	appContainer.RegisterService("replenishmentHandler", new(api.ReplenishmentHandler))
+	appContainer.RegisterService("uomConversionHandler", new(api.UomConversionHandler))
+	appContainer.RegisterService("vendorInvoiceHandler", new(api.VendorInvoiceHandler))
+	appContainer.RegisterService("quotationComparisonHandler", new(api.QuotationComparisonHandler))
+	appContainer.RegisterService("tblCustomerHandler", new(api.TblCustomerHandler))
+	appContainer.RegisterService("tblSalesOrderHandler", new(api.TblSalesOrderHandler))
+	appContainer.RegisterService("exchangeRateHandler", new(api.ExchangeRateHandler))
 
 	appContainer.RegisterService("api", new(application.Api))
 }
@@ -0,0 +1,66 @@
+package batch
+
+import "gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+
+// Read adalah master satu batch item. Tanggal disimpan yyyymmdd dan
+// ditampilkan lewat share.FormatDate.
+type Read struct {
+	Number            uint                      `json:"number"`
+	ItemCode          string                    `db:"ItCode" json:"item_code"`
+	ItemName          string                    `db:"ItName" json:"item_name"`
+	Batch             string                    `db:"BatchNo" json:"batch"`
+	ManufacturingDate nulldatatype.NullDataType `db:"MfgDt" json:"manufacturing_date"`
+	ExpiredDate       nulldatatype.NullDataType `db:"ExpDt" json:"expired_date"`
+	VendorLot         nulldatatype.NullDataType `db:"VendorLot" json:"vendor_lot"`
+	Remark            nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	CreateDate        string                    `db:"CreateDt" json:"create_date"`
+}
+
+// Save membuat atau mengganti master batch (ItCode + BatchNo). Tanggal dari
+// client berformat yyyy-mm-dd.
+type Save struct {
+	ItemCode          string                    `db:"ItCode" json:"item_code" validate:"required,incolumn=tblitem->ItCode" label:"Item"`
+	Batch             string                    `db:"BatchNo" json:"batch" validate:"required,max=60" label:"Batch"`
+	ManufacturingDate nulldatatype.NullDataType `db:"MfgDt" json:"manufacturing_date"`
+	ExpiredDate       nulldatatype.NullDataType `db:"ExpDt" json:"expired_date"`
+	VendorLot         nulldatatype.NullDataType `db:"VendorLot" json:"vendor_lot"`
+	Remark            nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	CreateBy          string                    `db:"CreateBy" json:"-"`
+	CreateDate        string                    `db:"CreateDt" json:"-"`
+}
+
+// Expiring adalah saldo satu batch di satu gudang yang kedaluwarsa dalam
+// horizon laporan. DaysLeft negatif berarti sudah lewat.
+type Expiring struct {
+	WarehouseCode string  `db:"WhsCode" json:"warehouse_code" label:"Warehouse Code"`
+	WarehouseName string  `db:"WhsName" json:"warehouse_name" label:"Warehouse"`
+	ItemCode      string  `db:"ItCode" json:"item_code" label:"Item Code"`
+	ItemName      string  `db:"ItName" json:"item_name" label:"Item"`
+	Batch         string  `db:"BatchNo" json:"batch" label:"Batch"`
+	VendorLot     string  `db:"VendorLot" json:"vendor_lot" label:"Vendor Lot"`
+	ExpiredDate   string  `db:"ExpDt" json:"expired_date" label:"Expired Date"`
+	DaysLeft      int     `json:"days_left" label:"Days Left"`
+	Bucket        string  `json:"bucket" label:"Bucket"`
+	Stock         float32 `db:"Stock" json:"stock" label:"Stock"`
+	UomName       string  `db:"UomName" json:"uom_name" label:"UoM"`
+}
+
+// Bucket merangkum jumlah baris dan stok per horizon.
+type Bucket struct {
+	Name  string  `json:"name"`
+	Lines int     `json:"lines"`
+	Stock float32 `json:"stock"`
+}
+
+type ExpiringReport struct {
+	Date     string      `json:"date"`
+	Horizons []int       `json:"horizons"`
+	Buckets  []*Bucket   `json:"buckets"`
+	Lines    []*Expiring `json:"lines"`
+}
+
+// Line adalah pasangan item + batch yang akan dikeluarkan oleh sebuah dokumen.
+type Line struct {
+	ItemCode string
+	Batch    string
+}
@@ -0,0 +1,15 @@
+package batch
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type Repository interface {
+	Fetch(ctx context.Context, itemCode, batch string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Save(ctx context.Context, data *Save) (*Save, error)
+	Expiring(ctx context.Context, warehouse, until string) ([]*Expiring, error)
+	CheckExpired(ctx context.Context, tx *sqlx.Tx, docDt string, lines []Line) error
+}
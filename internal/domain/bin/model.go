@@ -0,0 +1,168 @@
+package bin
+
+import (
+	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+)
+
+// Unassigned adalah bin untuk stok yang belum di-put-away, nilai default kolom
+// Bin di tblstocksummary dan tblstockmovement.
+const Unassigned = "-"
+
+// Dokumen yang bisa dimintai saran put-away / pick list
+const (
+	DocPurchaseMaterialReceive = "purchase-material-receive"
+	DocMaterialReceive         = "material-receive"
+	DocDirectSalesDelivery     = "direct-sales-delivery"
+	DocMaterialTransfer        = "material-transfer"
+)
+
+// Read adalah satu bin beserta isi saat ini. Capacity 0 berarti tidak dibatasi.
+type Read struct {
+	Number        uint                      `json:"number"`
+	BinCode       string                    `db:"BinCode" json:"bin_code"`
+	WarehouseCode string                    `db:"WhsCode" json:"warehouse_code"`
+	WarehouseName string                    `db:"WhsName" json:"warehouse_name"`
+	Zone          string                    `db:"Zone" json:"zone"`
+	Aisle         string                    `db:"Aisle" json:"aisle"`
+	Rack          string                    `db:"Rack" json:"rack"`
+	Level         string                    `db:"Level" json:"level"`
+	Capacity      float32                   `db:"Capacity" json:"capacity"`
+	Occupied      float32                   `db:"Occupied" json:"occupied"`
+	Active        booldatatype.BoolDataType `db:"ActInd" json:"active"`
+	Remark        nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	CreateDate    string                    `db:"CreateDt" json:"create_date"`
+}
+
+type Create struct {
+	BinCode       string                    `db:"BinCode" json:"bin_code" validate:"required,unique=tblbin->BinCode,max=16" label:"Bin Code"`
+	WarehouseCode string                    `db:"WhsCode" json:"warehouse_code" validate:"required,incolumn=tblwarehouse->WhsCode" label:"Warehouse"`
+	Zone          string                    `db:"Zone" json:"zone" validate:"required,max=10" label:"Zone"`
+	Aisle         string                    `db:"Aisle" json:"aisle" validate:"required,max=10" label:"Aisle"`
+	Rack          string                    `db:"Rack" json:"rack" validate:"required,max=10" label:"Rack"`
+	Level         string                    `db:"Level" json:"level" validate:"required,max=10" label:"Level"`
+	Capacity      float32                   `db:"Capacity" json:"capacity" validate:"min=0" label:"Capacity"`
+	Remark        nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	CreateBy      string                    `db:"CreateBy" json:"-"`
+	CreateDate    string                    `db:"CreateDt" json:"-"`
+}
+
+// Update tidak mengubah gudang, bin yang pindah gudang dibuat sebagai bin baru.
+type Update struct {
+	BinCode        string                    `db:"BinCode" json:"bin_code" validate:"required,incolumn=tblbin->BinCode" label:"Bin Code"`
+	Zone           string                    `db:"Zone" json:"zone" validate:"required,max=10" label:"Zone"`
+	Aisle          string                    `db:"Aisle" json:"aisle" validate:"required,max=10" label:"Aisle"`
+	Rack           string                    `db:"Rack" json:"rack" validate:"required,max=10" label:"Rack"`
+	Level          string                    `db:"Level" json:"level" validate:"required,max=10" label:"Level"`
+	Capacity       float32                   `db:"Capacity" json:"capacity" validate:"min=0" label:"Capacity"`
+	Active         booldatatype.BoolDataType `db:"ActInd" json:"active" validate:"required" label:"Active"`
+	Remark         nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	LastUpBy       string                    `db:"LastUpBy" json:"-"`
+	LastUpdateDate string                    `db:"LastUpDt" json:"-"`
+}
+
+// Stock adalah saldo tblstocksummary per bin dan source.
+type Stock struct {
+	WarehouseCode string                    `db:"WhsCode" json:"warehouse_code"`
+	BinCode       string                    `db:"Bin" json:"bin_code"`
+	Zone          nulldatatype.NullDataType `db:"Zone" json:"zone"`
+	Aisle         nulldatatype.NullDataType `db:"Aisle" json:"aisle"`
+	Rack          nulldatatype.NullDataType `db:"Rack" json:"rack"`
+	Level         nulldatatype.NullDataType `db:"Level" json:"level"`
+	ItemCode      string                    `db:"ItCode" json:"item_code"`
+	ItemName      string                    `db:"ItName" json:"item_name"`
+	Batch         string                    `db:"BatchNo" json:"batch"`
+	Source        string                    `db:"Source" json:"source"`
+	Qty           float32                   `db:"Qty" json:"qty"`
+}
+
+// Location adalah bin beserta quantity yang disarankan untuk ditaruh / diambil.
+type Location struct {
+	BinCode string  `json:"bin_code"`
+	Zone    string  `json:"zone"`
+	Aisle   string  `json:"aisle"`
+	Rack    string  `json:"rack"`
+	Level   string  `json:"level"`
+	Qty     float32 `json:"qty"`
+}
+
+// PutAwayLine adalah saran penempatan satu baris dokumen penerimaan.
+// Unplaced adalah sisa yang tidak muat di bin manapun.
+type PutAwayLine struct {
+	DNo         string      `db:"DNo" json:"dno"`
+	ItemCode    string      `db:"ItCode" json:"item_code"`
+	ItemName    string      `db:"ItName" json:"item_name"`
+	Batch       string      `db:"BatchNo" json:"batch"`
+	Source      string      `db:"Source" json:"source"`
+	Qty         float32     `db:"Qty" json:"qty"`
+	Suggestions []*Location `json:"suggestions"`
+	Unplaced    float32     `json:"unplaced"`
+}
+
+type PutAway struct {
+	DocType       string         `json:"document_type"`
+	DocNo         string         `json:"document_number"`
+	WarehouseCode string         `json:"warehouse_code"`
+	Lines         []*PutAwayLine `json:"lines"`
+}
+
+// PickLine adalah satu langkah pengambilan, sudah diurutkan per bin.
+type PickLine struct {
+	Number   uint    `json:"number"`
+	BinCode  string  `json:"bin_code"`
+	Zone     string  `json:"zone"`
+	Aisle    string  `json:"aisle"`
+	Rack     string  `json:"rack"`
+	Level    string  `json:"level"`
+	DNo      string  `json:"dno"`
+	ItemCode string  `json:"item_code"`
+	ItemName string  `json:"item_name"`
+	Batch    string  `json:"batch"`
+	Source   string  `json:"source"`
+	Qty      float32 `json:"qty"`
+}
+
+type PickList struct {
+	DocType       string      `json:"document_type"`
+	DocNo         string      `json:"document_number"`
+	WarehouseCode string      `json:"warehouse_code"`
+	Lines         []*PickLine `json:"lines"`
+	// Shortage adalah quantity dokumen yang tidak tersedia di bin manapun
+	Shortage float32 `json:"shortage"`
+}
+
+// DocLine adalah baris dokumen sumber put-away / pick list. Source kosong
+// berarti boleh diambil dari source manapun.
+type DocLine struct {
+	WhsCode string  `db:"WhsCode"`
+	DNo     string  `db:"DNo"`
+	ItCode  string  `db:"ItCode"`
+	ItName  string  `db:"ItName"`
+	BatchNo string  `db:"BatchNo"`
+	Source  string  `db:"Source"`
+	Qty     float32 `db:"Qty"`
+}
+
+type Transfer struct {
+	Number        uint                      `json:"number"`
+	DocNo         string                    `db:"DocNo" json:"document_number"`
+	Date          string                    `db:"DocDt" json:"date" validate:"required"`
+	WarehouseCode string                    `db:"WhsCode" json:"warehouse_code" validate:"required,incolumn=tblwarehouse->WhsCode" label:"Warehouse"`
+	WarehouseName string                    `db:"WhsName" json:"warehouse_name"`
+	Remark        nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	Details       []TransferDetail          `json:"details,omitempty" validate:"required,min=1,dive"`
+	CreateBy      string                    `db:"CreateBy" json:"create_by"`
+	CreateDate    string                    `db:"CreateDt" json:"-"`
+}
+
+type TransferDetail struct {
+	DNo      string                    `db:"DNo" json:"dno"`
+	ItemCode string                    `db:"ItCode" json:"item_code" validate:"required,incolumn=tblitem->ItCode" label:"Item"`
+	ItemName string                    `db:"ItName" json:"item_name"`
+	Batch    string                    `db:"BatchNo" json:"batch" validate:"required" label:"Batch"`
+	Source   string                    `db:"Source" json:"source" validate:"required" label:"Source"`
+	BinFrom  string                    `db:"BinFrom" json:"bin_from" validate:"required" label:"Bin From"`
+	BinTo    string                    `db:"BinTo" json:"bin_to" validate:"required,nefield=BinFrom" label:"Bin To"`
+	Qty      float32                   `db:"Qty" json:"qty" validate:"gt=0" label:"Quantity"`
+	Remark   nulldatatype.NullDataType `db:"Remark" json:"remark"`
+}
@@ -0,0 +1,20 @@
+package bin
+
+import (
+	"context"
+
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type Repository interface {
+	Fetch(ctx context.Context, warehouse, search string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Detail(ctx context.Context, code string) (*Read, error)
+	Create(ctx context.Context, data *Create) (*Create, error)
+	Update(ctx context.Context, data *Update) (*Update, error)
+	Stock(ctx context.Context, warehouse, bin, itemCode string) ([]*Stock, error)
+	PutAway(ctx context.Context, docType, docNo string) (*PutAway, error)
+	PickList(ctx context.Context, docType, docNo string) (*PickList, error)
+	FetchTransfer(ctx context.Context, doc, warehouse string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	DetailTransfer(ctx context.Context, docNo string) (*Transfer, error)
+	CreateTransfer(ctx context.Context, data *Transfer) (*Transfer, error)
+}
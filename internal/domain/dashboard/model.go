@@ -1,7 +1,16 @@
 package dashboard
 
+import "gitlab.com/ayaka/internal/domain/exchangerate"
+
 type Read struct {
 	OutstandingMaterialTransfer uint `db:"OutstandingMaterialTransfer" json:"outstanding_material_transfer"`
 	OutstandingPurchaseOrder    uint `db:"OutstandingPurchaseOrder" json:"outstanding_purchase_material_order"`
 	OutstandingMaterialOrder    uint `db:"OutstandingMaterialOrder" json:"outstanding_material_order"`
-}
\ No newline at end of file
+	OpenTransferDiscrepancy     uint `json:"open_transfer_discrepancy"`
+
+	// nilai sisa PO dalam base (kurs tanggal PO) dan per mata uang aslinya
+	BaseCurrency                    string                `json:"base_currency"`
+	OutstandingPurchaseOrderValue   float64               `json:"outstanding_purchase_order_value"`
+	OutstandingPurchaseOrderAmounts []exchangerate.Amount `json:"outstanding_purchase_order_amounts"`
+	OutstandingPurchaseOrderNoRate  bool                  `json:"outstanding_purchase_order_rate_missing"`
+}
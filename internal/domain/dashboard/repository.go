@@ -3,5 +3,6 @@ package dashboard
 import "context"
 
 type Repository interface {
-	Fetch(ctx context.Context) (*Read, error)
+	// Fetch base adalah mata uang dasar untuk nilai di dashboard
+	Fetch(ctx context.Context, base string) (*Read, error)
 }
\ No newline at end of file
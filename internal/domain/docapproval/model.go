@@ -0,0 +1,109 @@
+package docapproval
+
+import "gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+
+// jenis dokumen yang melewati approval
+const (
+	MaterialRequest      = "MaterialRequest"
+	PurchaseOrderRequest = "PurchaseOrderRequest"
+	PurchaseOrder        = "PurchaseOrder"
+)
+
+// status approval pada header dokumen maupun per approver. Skipped dipakai
+// untuk approver lain di level yang sama setelah salah satunya bertindak.
+const (
+	Pending  = "Pending"
+	Approved = "Approved"
+	Rejected = "Rejected"
+	Returned = "Returned"
+	Skipped  = "Skipped"
+)
+
+// Submitted hanya muncul di history, saat dokumen dibuat atau diajukan ulang.
+const Submitted = "Submitted"
+
+// Level adalah satu approver pada rule. Level dengan MinAmount di atas nilai
+// dokumen tidak ikut dalam approval dokumen tersebut.
+type Level struct {
+	Level     int     `db:"Level" json:"level" validate:"required,min=1" label:"Level"`
+	UserCode  string  `db:"UserCode" json:"user_code" validate:"required,incolumn=tbluser->UserCode" label:"User Code"`
+	UserName  string  `db:"UserName" json:"user_name"`
+	MinAmount float32 `db:"MinAmount" json:"min_amount" validate:"min=0" label:"Minimum Amount"`
+}
+
+type Rule struct {
+	Number     uint    `json:"number"`
+	RuleCode   string  `db:"RuleCode" json:"rule_code"`
+	RuleName   string  `db:"RuleName" json:"rule_name"`
+	DocType    string  `db:"DocType" json:"document_type"`
+	SiteCode   string  `db:"SiteCode" json:"site_code"`
+	Department string  `db:"Department" json:"department"`
+	ActiveInd  string  `db:"ActInd" json:"active"`
+	Levels     []Level `json:"levels"`
+}
+
+// SiteCode / Department kosong berarti berlaku untuk semua site / department.
+type CreateRule struct {
+	RuleCode   string  `db:"RuleCode" json:"rule_code" validate:"required,unique=tblapprovalrule->RuleCode,max=20" label:"Rule Code"`
+	RuleName   string  `db:"RuleName" json:"rule_name" validate:"required,max=80" label:"Rule Name"`
+	DocType    string  `db:"DocType" json:"document_type" validate:"required,oneof=MaterialRequest PurchaseOrderRequest PurchaseOrder" label:"Document Type"`
+	SiteCode   string  `db:"SiteCode" json:"site_code" validate:"omitempty,incolumn=tblsite->SiteCode" label:"Site"`
+	Department string  `db:"Department" json:"department" validate:"max=50" label:"Department"`
+	Levels     []Level `json:"levels" validate:"required,min=1,dive" label:"Level"`
+	CreateDt   string  `db:"CreateDt" json:"create_date"`
+	CreateBy   string  `db:"CreateBy" json:"create_by"`
+}
+
+type UpdateRule struct {
+	RuleCode   string  `db:"RuleCode" json:"rule_code" validate:"required,incolumn=tblapprovalrule->RuleCode" label:"Rule Code"`
+	RuleName   string  `db:"RuleName" json:"rule_name" validate:"required,max=80" label:"Rule Name"`
+	SiteCode   string  `db:"SiteCode" json:"site_code" validate:"omitempty,incolumn=tblsite->SiteCode" label:"Site"`
+	Department string  `db:"Department" json:"department" validate:"max=50" label:"Department"`
+	ActiveInd  string  `db:"ActInd" json:"active" validate:"required,oneof=Y N" label:"Active"`
+	Levels     []Level `json:"levels" validate:"required,min=1,dive" label:"Level"`
+	LastUpDt   string  `db:"LastUpDt" json:"last_update_date"`
+	LastUpBy   string  `db:"LastUpBy" json:"last_update_by"`
+}
+
+type Approver struct {
+	Level    int                       `db:"Level" json:"level"`
+	UserCode string                    `db:"UserCode" json:"user_code"`
+	UserName string                    `db:"UserName" json:"user_name"`
+	Status   string                    `db:"Status" json:"status"`
+	Remark   nulldatatype.NullDataType `db:"Remark" json:"comment"`
+	LastUpDt nulldatatype.NullDataType `db:"LastUpDt" json:"last_update_date"`
+}
+
+type History struct {
+	UserCode string                    `db:"UserCode" json:"user_code"`
+	Action   string                    `db:"Action" json:"action"`
+	Remark   nulldatatype.NullDataType `db:"Remark" json:"comment"`
+	CreateDt string                    `db:"CreateDt" json:"date"`
+}
+
+type Detail struct {
+	DocType        string     `json:"document_type"`
+	DocNo          string     `json:"document_number"`
+	ApprovalStatus string     `db:"ApprovalStatus" json:"approval_status"`
+	Approvers      []Approver `json:"approvers"`
+	History        []History  `json:"history"`
+}
+
+// Act adalah keputusan approver atas dokumen, Status berisi Approved,
+// Rejected atau Returned.
+type Act struct {
+	DocType  string `json:"document_type"`
+	DocNo    string `json:"document_number"`
+	Status   string `json:"status"`
+	Remark   string `json:"comment" validate:"max=250" label:"Comment"`
+	UserCode string `json:"user_code"`
+	Date     string `json:"date"`
+}
+
+// Inbox adalah dokumen yang sedang menunggu keputusan user.
+type Inbox struct {
+	DocType string `db:"DocType" json:"document_type"`
+	DocNo   string `db:"DocNo" json:"document_number"`
+	Level   int    `db:"Level" json:"level"`
+	Date    string `db:"CreateDt" json:"date"`
+}
@@ -0,0 +1,23 @@
+package docapproval
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type Repository interface {
+	// Start dipanggil repository dokumen di dalam transaksi create, mencari
+	// rule yang cocok lalu mengisi approver dan ApprovalStatus header.
+	Start(ctx context.Context, tx *sqlx.Tx, docType, docNo, userCode, date string) error
+	Detail(ctx context.Context, docType, docNo string) (*Detail, error)
+	Act(ctx context.Context, data *Act) (*Act, error)
+	Resubmit(ctx context.Context, docType, docNo, userCode, date string) error
+	Inbox(ctx context.Context, userCode string) ([]*Inbox, error)
+
+	FetchRule(ctx context.Context, search string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	DetailRule(ctx context.Context, code string) (*Rule, error)
+	CreateRule(ctx context.Context, data *CreateRule) (*CreateRule, error)
+	UpdateRule(ctx context.Context, data *UpdateRule) (*UpdateRule, error)
+}
@@ -0,0 +1,76 @@
+package efaktur
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"strconv"
+)
+
+// NPWP kosong untuk pembeli tanpa NPWP
+const emptyNPWP = "000000000000000"
+
+var headerRows = [][]string{
+	{"FK", "KD_JENIS_TRANSAKSI", "FG_PENGGANTI", "NOMOR_FAKTUR", "MASA_PAJAK", "TAHUN_PAJAK", "TANGGAL_FAKTUR", "NPWP", "NAMA", "ALAMAT_LENGKAP", "JUMLAH_DPP", "JUMLAH_PPN", "JUMLAH_PPNBM", "ID_KETERANGAN_TAMBAHAN", "FG_UANG_MUKA", "UANG_MUKA_DPP", "UANG_MUKA_PPN", "UANG_MUKA_PPNBM", "REFERENSI", "KODE_DOKUMEN_PENDUKUNG"},
+	{"LT", "NPWP", "NAMA", "JALAN", "BLOK", "NOMOR", "RT", "RW", "KECAMATAN", "KELURAHAN", "KABUPATEN", "PROPINSI", "KODE_POS", "NOMOR_TELEPON"},
+	{"OF", "KODE_OBJEK", "NAMA", "HARGA_SATUAN", "JUMLAH_BARANG", "HARGA_TOTAL", "DISKON", "DPP", "PPN", "TARIF_PPNBM", "PPNBM"},
+}
+
+// Write menulis layout import e-Faktur DJP: tiga baris judul lalu baris FK,
+// FAPR (penjual), LT (pembeli) dan OF per faktur. JUMLAH_DPP / JUMLAH_PPN di FK
+// adalah jumlah DPP / PPN baris OF yang sudah dibulatkan, supaya tidak selisih
+// saat divalidasi. Tanpa BOM, aplikasi e-Faktur menolak file dengan BOM.
+func Write(w io.Writer, seller Seller, invoices []Invoice) error {
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(headerRows); err != nil {
+		return err
+	}
+
+	for _, inv := range invoices {
+		npwp := inv.NPWP
+		if npwp == "" {
+			npwp = emptyNPWP
+		}
+		// DocDt yyyymmdd
+		year, month, day := inv.DocDt[0:4], inv.DocDt[4:6], inv.DocDt[6:8]
+		masa, _ := strconv.Atoi(month)
+
+		var dpp, vat float64
+		var lines [][]string
+		for _, line := range inv.Lines {
+			price := line.Price
+			if line.Qty != 0 {
+				price = line.DPP / line.Qty
+			}
+			dpp += rupiah(line.DPP)
+			vat += rupiah(line.VAT)
+			lines = append(lines, []string{"OF", line.ItCode, line.ItName, decimal(price), decimal(line.Qty), decimal(line.DPP), "0", amount(rupiah(line.DPP)), amount(rupiah(line.VAT)), "0", "0"})
+		}
+
+		rows := [][]string{
+			{"FK", "01", "0", inv.TaxInvoiceNo, strconv.Itoa(masa), year, day + "/" + month + "/" + year, npwp, inv.Name, inv.Address, amount(dpp), amount(vat), "0", "", "0", "0", "0", "0", inv.DocNo, ""},
+			{"FAPR", seller.Name, seller.Address, "", "", "", "", "", "", "", "", "", ""},
+			{"LT", npwp, inv.Name, inv.Address, "", "", "", "", "", "", inv.City, "", inv.PostalCode, inv.Phone},
+		}
+		rows = append(rows, lines...)
+
+		if err := writer.WriteAll(rows); err != nil {
+			return err
+		}
+	}
+
+	return writer.Error()
+}
+
+// rupiah DPP/PPN di e-Faktur tanpa desimal, dibulatkan ke bawah
+func rupiah(v float64) float64 {
+	return math.Floor(v + 1e-9)
+}
+
+func amount(v float64) string {
+	return strconv.FormatFloat(v, 'f', 0, 64)
+}
+
+func decimal(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,36 @@
+package efaktur
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, Seller{Name: "PT Ayaka", NPWP: "012345678901000", Address: "Jl. Thamrin 2"}, []Invoice{{
+		DocNo:        "0001/DSD/10/26",
+		DocDt:        "20261005",
+		TaxInvoiceNo: "0102600000123",
+		Name:         "PT Maju",
+		Address:      "Jl. Sudirman 1",
+		DPP:          2001.5,
+		VAT:          220.16,
+		Lines: []Line{
+			{ItCode: "IT01", ItName: "Barang", Price: 500.25, Qty: 3, DPP: 1500.75, VAT: 165.08},
+			{ItCode: "IT02", ItName: "Jasa", Price: 500.75, Qty: 1, DPP: 500.75, VAT: 55.08},
+		},
+	}})
+
+	assert.NoError(t, err)
+	rows := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, rows, 8)
+	assert.False(t, strings.HasPrefix(rows[0], "\uFEFF"))
+	// total FK = jumlah OF yang sudah dibulatkan (1500 + 500, 165 + 55)
+	assert.Equal(t, "FK,01,0,0102600000123,10,2026,05/10/2026,000000000000000,PT Maju,Jl. Sudirman 1,2000,220,0,,0,0,0,0,0001/DSD/10/26,", rows[3])
+	assert.Equal(t, "FAPR,PT Ayaka,Jl. Thamrin 2,,,,,,,,,,", rows[4])
+	assert.Equal(t, "OF,IT01,Barang,500.25,3,1500.75,0,1500,165,0,0", rows[6])
+	assert.Equal(t, "OF,IT02,Jasa,500.75,1,500.75,0,500,55,0,0", rows[7])
+}
@@ -0,0 +1,62 @@
+package efaktur
+
+// Range rentang nomor seri faktur pajak dari DJP. Prefix = kode cabang 3 digit
+// + 2 digit tahun, nomor faktur = Prefix + nomor urut 8 digit.
+type Range struct {
+	Number     uint   `json:"number"`
+	Prefix     string `db:"Prefix" json:"prefix" validate:"required,numeric,len=5"`
+	StartNo    int    `db:"StartNo" json:"start_number" validate:"required,min=1,max=99999999"`
+	EndNo      int    `db:"EndNo" json:"end_number" validate:"required,gtefield=StartNo,max=99999999"`
+	LastNo     int    `db:"LastNo" json:"last_number"`
+	Remaining  int    `json:"remaining"`
+	CreateBy   string `db:"CreateBy" json:"-"`
+	CreateDate string `db:"CreateDt" json:"-"`
+}
+
+// Seller profil perusahaan dari config company. NPWP penjual tidak punya kolom
+// di layout import (aplikasi e-Faktur memakai NPWP yang login), jadi dipakai di
+// nama file dan dicek sebelum export.
+type Seller struct {
+	Name    string
+	NPWP    string
+	Address string
+}
+
+// Invoice satu direct sales delivery yang diexport sebagai faktur keluaran.
+// DPP dan VAT hanya dari pajak non-withholding.
+type Invoice struct {
+	DocNo        string  `db:"DocNo"`
+	DocDt        string  `db:"DocDt"`
+	TaxInvoiceNo string  `db:"TaxInvoiceNo"`
+	NPWP         string  `db:"NPWP"`
+	Name         string  `db:"CustomerName"`
+	Address      string  `db:"Address"`
+	City         string  `db:"CityName"`
+	PostalCode   string  `db:"PostalCode"`
+	Phone        string  `db:"Phone"`
+	TaxCode      string  `db:"TaxCode"`
+	Inclusive    string  `db:"TaxInclusiveInd"`
+	DPP          float64 `db:"-"`
+	VAT          float64 `db:"-"`
+	Lines        []Line  `db:"-"`
+}
+
+type Line struct {
+	DocNo  string  `db:"DocNo"`
+	DNo    string  `db:"DNo"`
+	ItCode string  `db:"ItCode"`
+	ItName string  `db:"ItName"`
+	Price  float64 `db:"Price"`
+	Qty    float64 `db:"Qty"`
+	DPP    float64 `db:"-"`
+	VAT    float64 `db:"-"`
+}
+
+// Export hasil export, Content berisi file CSV siap import ke aplikasi e-Faktur.
+// Batch adalah nomor faktur pertama pada export, dipakai untuk download ulang.
+type Export struct {
+	Batch    string
+	FileName string
+	Count    int
+	Content  []byte
+}
@@ -0,0 +1,18 @@
+package efaktur
+
+import (
+	"context"
+
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type Repository interface {
+	FetchRange(ctx context.Context, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	// CreateRange menolak rentang yang beririsan dengan rentang lain di Prefix yang sama
+	CreateRange(ctx context.Context, data *Range) (*Range, error)
+	// Export mengambil direct sales delivery ber-PPN yang belum diexport pada
+	// rentang tanggal (yyyymmdd), memberi nomor faktur dan menandainya exported
+	Export(ctx context.Context, startDate, endDate, userCode, date string) ([]Invoice, error)
+	// Reexport membaca ulang faktur satu batch export tanpa memberi nomor baru
+	Reexport(ctx context.Context, batch string) ([]Invoice, error)
+}
@@ -0,0 +1,20 @@
+package exchangerate
+
+// Rate berarti 1 CurCode1 = Rate CurCode2, berlaku mulai Date sampai ada kurs
+// yang lebih baru. Kurs kebalikannya dihitung 1 / Rate bila tidak dicatat.
+type Rate struct {
+	Number     uint    `json:"number"`
+	CurCode1   string  `db:"CurCode1" json:"currency_from" validate:"required,incolumn=tblcurrency->CurCode" label:"Currency From"`
+	CurCode2   string  `db:"CurCode2" json:"currency_to" validate:"required,nefield=CurCode1,incolumn=tblcurrency->CurCode" label:"Currency To"`
+	Date       string  `db:"RateDt" json:"rate_date" validate:"required,datetime=2006-01-02" label:"Rate Date"`
+	Rate       float64 `db:"Rate" json:"rate" validate:"gt=0" label:"Rate"`
+	CreateBy   string  `db:"CreateBy" json:"-"`
+	CreateDate string  `db:"CreateDt" json:"-"`
+}
+
+// Amount nilai dalam mata uang dokumen, dipakai laporan yang menjumlah
+// lintas mata uang di samping total dalam base currency
+type Amount struct {
+	CurCode string  `db:"CurCode" json:"currency_code"`
+	Amount  float64 `db:"Amount" json:"amount"`
+}
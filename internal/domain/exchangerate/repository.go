@@ -0,0 +1,17 @@
+package exchangerate
+
+import (
+	"context"
+
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type Repository interface {
+	Fetch(ctx context.Context, curCode, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	// Save membuat atau mengganti kurs pasangan mata uang pada tanggal yang sama
+	Save(ctx context.Context, data *Rate) (*Rate, error)
+	Delete(ctx context.Context, curCode1, curCode2, date string) error
+	// Rates kurs tiap CurCode ke base yang berlaku pada date (yyyymmdd),
+	// mata uang tanpa kurs tidak ada di map
+	Rates(ctx context.Context, curCodes []string, base, date string) (map[string]float64, error)
+}
@@ -0,0 +1,86 @@
+package intransit
+
+import "gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+
+// Warehouse gudang virtual tempat barang material transfer selama di jalan.
+// Transfer memindahkan stok gudang asal ke sini, receive mengeluarkannya ke
+// gudang tujuan, sehingga barangnya tetap terlihat di laporan stok.
+const Warehouse = "TRANSIT"
+
+// Status discrepancy, kekurangan terima tetap di gudang transit selama Open.
+// Baris transfer hanya diterima sekali, pengiriman susulan tidak ditunggu.
+const (
+	Open   = "Open"
+	Loss   = "Loss"
+	Return = "Return"
+)
+
+// Stock sisa satu baris material transfer yang belum diterima / diselesaikan.
+// AgeDays dihitung dari tanggal transfer.
+type Stock struct {
+	DocNo        string  `db:"DocNo" json:"document_number" label:"Document"`
+	DNo          string  `db:"DNo" json:"detail_number" label:"Detail"`
+	Date         string  `db:"DocDt" json:"date" label:"Date"`
+	WhsCodeFrom  string  `db:"WhsCodeFrom" json:"warehouse_code_from" label:"From Warehouse Code"`
+	WhsNameFrom  string  `db:"WhsNameFrom" json:"warehouse_name_from" label:"From Warehouse"`
+	WhsCodeTo    string  `db:"WhsCodeTo" json:"warehouse_code_to" label:"To Warehouse Code"`
+	WhsNameTo    string  `db:"WhsNameTo" json:"warehouse_name_to" label:"To Warehouse"`
+	ItCode       string  `db:"ItCode" json:"item_code" label:"Item Code"`
+	ItName       string  `db:"ItName" json:"item_name" label:"Item"`
+	BatchNo      string  `db:"BatchNo" json:"batch" label:"Batch"`
+	UomName      string  `db:"UomName" json:"uom_name" label:"UoM"`
+	Qty          float32 `db:"Qty" json:"quantity" label:"Quantity"`
+	QtyReceived  float32 `db:"QtyReceived" json:"qty_received" label:"Received"`
+	QtyInTransit float32 `db:"QtyInTransit" json:"qty_in_transit" label:"In Transit"`
+	Discrepancy  bool    `db:"Discrepancy" json:"discrepancy" label:"Discrepancy"`
+	AgeDays      int     `json:"age_days" label:"Age (Days)"`
+	Bucket       string  `json:"bucket" label:"Bucket"`
+}
+
+// Bucket merangkum jumlah baris dan qty per umur.
+type Bucket struct {
+	Name  string  `json:"name"`
+	Lines int     `json:"lines"`
+	Qty   float32 `json:"qty"`
+}
+
+type Report struct {
+	Date     string    `json:"date"`
+	Horizons []int     `json:"horizons"`
+	Buckets  []*Bucket `json:"buckets"`
+	Lines    []*Stock  `json:"lines"`
+}
+
+// Discrepancy kekurangan terima satu baris material receive terhadap qty
+// yang dikirim baris material transfer-nya.
+type Discrepancy struct {
+	Number        uint                      `json:"number"`
+	DocNo         string                    `db:"DocNo" json:"document_number"`
+	DNo           string                    `db:"DNo" json:"detail_number"`
+	Date          string                    `db:"DocDt" json:"date"`
+	TransferDocNo string                    `db:"TransferDocNo" json:"transfer_document_number"`
+	TransferDNo   string                    `db:"TransferDNo" json:"transfer_detail_number"`
+	WhsCodeFrom   string                    `db:"WhsCodeFrom" json:"warehouse_code_from"`
+	WhsNameFrom   string                    `db:"WhsNameFrom" json:"warehouse_name_from"`
+	WhsCodeTo     string                    `db:"WhsCodeTo" json:"warehouse_code_to"`
+	WhsNameTo     string                    `db:"WhsNameTo" json:"warehouse_name_to"`
+	ItCode        string                    `db:"ItCode" json:"item_code"`
+	ItName        string                    `db:"ItName" json:"item_name"`
+	BatchNo       string                    `db:"BatchNo" json:"batch"`
+	Qty           float32                   `db:"Qty" json:"quantity"`
+	Status        string                    `db:"Status" json:"status"`
+	ResolveBy     nulldatatype.NullDataType `db:"ResolveBy" json:"resolve_by"`
+	ResolveDate   nulldatatype.NullDataType `db:"ResolveDt" json:"resolve_date"`
+	Remark        nulldatatype.NullDataType `db:"Remark" json:"remark"`
+}
+
+// Resolve menyelesaikan discrepancy Open: Loss dibukukan sebagai adjustment
+// keluar dari gudang transit, Return mengembalikan barang ke gudang asal.
+type Resolve struct {
+	DocNo      string                    `json:"document_number" validate:"required"`
+	DNo        string                    `json:"detail_number" validate:"required"`
+	Resolution string                    `json:"resolution" validate:"required,oneof=Loss Return"`
+	Remark     nulldatatype.NullDataType `json:"remark"`
+	ResolveBy  string                    `json:"-"`
+	ResolveDt  string                    `json:"-"`
+}
@@ -0,0 +1,14 @@
+package intransit
+
+import (
+	"context"
+
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type Repository interface {
+	// Stock baris transfer yang masih punya qty di gudang transit
+	Stock(ctx context.Context, warehouseFrom, warehouseTo string) ([]*Stock, error)
+	FetchDiscrepancy(ctx context.Context, status, warehouseFrom, warehouseTo string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Resolve(ctx context.Context, data *Resolve) (*Discrepancy, error)
+}
@@ -0,0 +1,38 @@
+package inventoryledger
+
+import "gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+
+// Direction menentukan kolom tblstocksummary / tblstockmovement yang diisi
+// oleh sebuah movement: Qty (stok awal), Qty2 (masuk) atau Qty3 (keluar).
+type Direction int
+
+const (
+	Initial Direction = iota
+	In
+	Out
+)
+
+// Movement adalah satu baris posting stok dari sebuah dokumen.
+// Qty selalu positif, arah mutasi ditentukan oleh Direction. UnitCost adalah
+// harga pokok per unit barang masuk; 0 berarti ditentukan oleh valuasi.
+// Bin kosong berarti barang masuk ke bin unassigned ("-") dan barang keluar
+// diambil dari bin-bin yang ada sesuai urutan pick. BinMove menandai
+// perpindahan antar bin yang tidak mengubah nilai persediaan gudang.
+type Movement struct {
+	DocType   string                    `db:"DocType"`
+	DocNo     string                    `db:"DocNo"`
+	DNo       string                    `db:"DNo"`
+	DocDt     string                    `db:"DocDt"`
+	WhsCode   string                    `db:"WhsCode"`
+	Bin       string                    `db:"Bin"`
+	Source    string                    `db:"Source"`
+	ItCode    string                    `db:"ItCode"`
+	BatchNo   string                    `db:"BatchNo"`
+	Qty       float32                   `db:"Qty"`
+	Direction Direction                 `db:"-"`
+	UnitCost  float32                   `db:"-"`
+	BinMove   bool                      `db:"-"`
+	Remark    nulldatatype.NullDataType `db:"Remark"`
+	CreateBy  string                    `db:"CreateBy"`
+	CreateDt  string                    `db:"CreateDt"`
+}
@@ -0,0 +1,12 @@
+package inventoryledger
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository interface {
+	Post(ctx context.Context, tx *sqlx.Tx, movements []Movement) error
+	Reverse(ctx context.Context, tx *sqlx.Tx, movements []Movement) error
+}
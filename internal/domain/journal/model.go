@@ -0,0 +1,74 @@
+package journal
+
+import "gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+
+// Account adalah mapping akun COA per item category dan gudang. WhsCode kosong
+// berlaku untuk semua gudang yang tidak punya mapping sendiri.
+type Account struct {
+	ItemCategoryCode string                    `db:"ItCtCode" json:"item_category_code" validate:"required,incolumn=tblitemcategory->ItCtCode" label:"Item Category"`
+	ItemCategoryName string                    `db:"ItCtName" json:"item_category_name"`
+	WarehouseCode    string                    `db:"WhsCode" json:"warehouse_code" validate:"omitempty,incolumn=tblwarehouse->WhsCode" label:"Warehouse"`
+	WarehouseName    string                    `db:"WhsName" json:"warehouse_name"`
+	Inventory        nulldatatype.NullDataType `db:"AcInventory" json:"coa_inventory" validate:"omitempty,incolumn=tblcoa->AcNo" label:"COA Account(Inventory)"`
+	GRIR             nulldatatype.NullDataType `db:"AcGRIR" json:"coa_grir_clearing" validate:"omitempty,incolumn=tblcoa->AcNo" label:"COA Account(GR/IR Clearing)"`
+	COGS             nulldatatype.NullDataType `db:"AcCOGS" json:"coa_cogs" validate:"omitempty,incolumn=tblcoa->AcNo" label:"COA Account(COGS)"`
+	Adjustment       nulldatatype.NullDataType `db:"AcAdjustment" json:"coa_adjustment" validate:"omitempty,incolumn=tblcoa->AcNo" label:"COA Account(Adjustment Gain/Loss)"`
+	TaxPayable       nulldatatype.NullDataType `db:"AcTaxPayable" json:"coa_tax_payable" validate:"omitempty,incolumn=tblcoa->AcNo" label:"COA Account(Tax Payable)"`
+	CreateBy         string                    `db:"CreateBy" json:"-"`
+	CreateDt         string                    `db:"CreateDt" json:"-"`
+}
+
+// Tax pajak satu baris dokumen pembelian dalam base currency. Amount positif
+// untuk PPN (mendebit akun tax payable), negatif untuk PPh yang dipotong.
+type Tax struct {
+	DocType  string  `db:"DocType"`
+	DocNo    string  `db:"DocNo"`
+	DNo      string  `db:"DNo"`
+	DocDt    string  `db:"DocDt"`
+	WhsCode  string  `db:"WhsCode"`
+	ItCode   string  `db:"ItCode"`
+	Amount   float32 `db:"Amount"`
+	CreateBy string  `db:"CreateBy"`
+	CreateDt string  `db:"CreateDt"`
+}
+
+type Line struct {
+	DNo    string                    `db:"DNo" json:"d_no"`
+	AcNo   string                    `db:"AcNo" json:"account"`
+	AcDesc string                    `db:"AcDesc" json:"account_description"`
+	Debit  float32                   `db:"DAmt" json:"debit"`
+	Credit float32                   `db:"CAmt" json:"credit"`
+	Remark nulldatatype.NullDataType `db:"Remark" json:"remark"`
+}
+
+type Read struct {
+	Number      uint                      `json:"number"`
+	DocNo       string                    `db:"DocNo" json:"document_number"`
+	Date        string                    `db:"DocDt" json:"document_date"`
+	RefDocType  string                    `db:"RefDocType" json:"reference_type"`
+	RefDocNo    string                    `db:"RefDocNo" json:"reference_number"`
+	Remark      nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	TotalDebit  float32                   `db:"TotalDebit" json:"total_debit"`
+	TotalCredit float32                   `db:"TotalCredit" json:"total_credit"`
+	CreateBy    string                    `db:"CreateBy" json:"create_by"`
+	Lines       []Line                    `json:"lines,omitempty"`
+}
+
+// TrialBalance adalah saldo satu akun: saldo awal sebelum StartDate, mutasi
+// debit / kredit dalam periode dan saldo akhir (debit positif).
+type TrialBalance struct {
+	AcNo    string  `db:"AcNo" json:"account" label:"Account"`
+	AcDesc  string  `db:"AcDesc" json:"account_description" label:"Account Description"`
+	Opening float32 `db:"Opening" json:"opening_balance" label:"Opening Balance"`
+	Debit   float32 `db:"Debit" json:"debit" label:"Debit"`
+	Credit  float32 `db:"Credit" json:"credit" label:"Credit"`
+	Closing float32 `json:"closing_balance" label:"Closing Balance"`
+}
+
+type TrialBalanceReport struct {
+	StartDate   string          `json:"start_date"`
+	EndDate     string          `json:"end_date"`
+	TotalDebit  float32         `json:"total_debit"`
+	TotalCredit float32         `json:"total_credit"`
+	Accounts    []*TrialBalance `json:"accounts"`
+}
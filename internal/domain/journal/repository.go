@@ -0,0 +1,22 @@
+package journal
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/domain/stockvaluation"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type Repository interface {
+	Post(ctx context.Context, tx *sqlx.Tx, entries []stockvaluation.Entry, cancel bool) error
+	// PostTax menjurnal pajak baris dokumen pembelian ke akun tax payable
+	PostTax(ctx context.Context, tx *sqlx.Tx, taxes []Tax) error
+	// ReverseTax membalik jurnal pajak baris dokumen yang dibatalkan
+	ReverseTax(ctx context.Context, tx *sqlx.Tx, docType, docNo string, dNos []string, cancelBy, cancelDt string) error
+	Fetch(ctx context.Context, search, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Detail(ctx context.Context, docNo string) (*Read, error)
+	TrialBalance(ctx context.Context, startDate, endDate string) (*TrialBalanceReport, error)
+	FetchAccount(ctx context.Context, search string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	SaveAccount(ctx context.Context, data *Account) (*Account, error)
+}
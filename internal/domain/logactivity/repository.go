@@ -2,8 +2,10 @@ package logactivity
 
 import (
 	"context"
+
+	"gitlab.com/ayaka/internal/pkg/pagination"
 )
 
 type Repository interface {
-	GetActivityLog(ctx context.Context, code, category string) ([]*LogActivity, error)
+	GetActivityLog(ctx context.Context, filter *Filter, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
 }
\ No newline at end of file
@@ -3,8 +3,40 @@ package logactivity
 import "gitlab.com/ayaka/internal/pkg/customerrors"
 
 type LogActivity struct {
-	Log  string `db:"Log" json:"log"`
-	Date string `db:"Date" json:"date"`
+	Log       string   `db:"Log" json:"log"`
+	Date      string   `db:"Date" json:"date"`
+	Category  string   `db:"Category" json:"category"`
+	Code      string   `db:"Code" json:"code"`
+	UserCode  string   `db:"UserCode" json:"user_code"`
+	UserName  string   `db:"UserName" json:"user_name"`
+	RequestId string   `db:"RequestId" json:"request_id"`
+	Changes   []Change `db:"-" json:"changes"`
+}
+
+// Change adalah perubahan satu field. Field detail dokumen ditulis dengan
+// nama tabel dan key barisnya, mis. tblpurchaseorderdtl[001].Qty. Nilai nil
+// berarti kolom NULL atau baris belum / sudah tidak ada.
+type Change struct {
+	Field    string  `json:"field"`
+	OldValue *string `json:"old_value"`
+	NewValue *string `json:"new_value"`
+}
+
+// Filter untuk /log, semua field opsional. Tanggal berformat YYYYMMDD.
+type Filter struct {
+	Category  string
+	Code      string
+	UserCode  string
+	StartDate string
+	EndDate   string
+}
+
+// DetailTable adalah tabel anak yang ikut dibandingkan di audit trail. Parent
+// adalah kolom yang merujuk ke primary key header, Key membedakan tiap baris.
+type DetailTable struct {
+	Table  string
+	Parent string
+	Key    []string
 }
 
 var listTable = map[string]string{
@@ -44,6 +76,16 @@ var listTable = map[string]string{
 	"PurchaseOrder":           "tblpurchaseorderhdr",
 	"PurchaseMaterialReceive": "tblpurchasematerialreceivehdr",
 	"PurchaseReturnDelivery":  "tblpurchasereturndeliveryhdr",
+	"UserGroup":               "tblgroup",
+	"UserScope":               "tbluser",
+	"ApprovalRule":            "tblapprovalrule",
+	"Journal":                 "tbljournalhdr",
+	"StockOpname":             "tblstockopnamehdr",
+	"Bin":                     "tblbin",
+	"BinTransfer":             "tblbintransferhdr",
+	"VendorInvoice":           "tblvendorinvoicehdr",
+	"MasterCustomer":          "tblcustomerhdr",
+	"SalesOrder":              "tblsalesorderhdr",
 }
 
 var listCode = map[string]string{
@@ -82,6 +124,54 @@ var listCode = map[string]string{
 	"PurchaseOrder":           "DocNo",
 	"PurchaseMaterialReceive": "DocNo",
 	"PurchaseReturnDelivery":  "DocNo",
+	"UserGroup":               "GrpCode",
+	"UserScope":               "UserCode",
+	"ApprovalRule":            "RuleCode",
+	"Journal":                 "DocNo",
+	"StockOpname":             "DocNo",
+	"Bin":                     "BinCode",
+	"BinTransfer":             "DocNo",
+	"VendorInvoice":           "DocNo",
+	"MasterCustomer":          "CustCode",
+	"SalesOrder":              "DocNo",
+}
+
+var listDetail = map[string][]DetailTable{
+	"StockInitial":            {{"tblstockinitialdtl", "DocNo", []string{"DNo"}}},
+	"StockAdjustment":         {{"tblstockadjustmentdtl", "DocNo", []string{"DNo"}}},
+	"StockMutation":           {{"tblstockmutationdtl", "DocNo", []string{"FromTo", "DNo"}}},
+	"DirectPurchaseReceive":   {{"tbldirectpurchasercvdtl", "DocNo", []string{"DNo"}}},
+	"DirectSalesDelivery":     {{"tbldirectsalesdelivdtl", "DocNo", []string{"DNo"}}},
+	"MaterialTransfer":        {{"tblmaterialtransferdtl", "DocNo", []string{"DNo"}}},
+	"MaterialReceive":         {{"tblmaterialreceivedtl", "DocNo", []string{"DNo"}}},
+	"DirectMaterialReceive":   {{"tbldirectmaterialreceivedtl", "DocNo", []string{"DNo"}}},
+	"MaterialRequest":         {{"tblmaterialrequestdtl", "DocNo", []string{"DNo"}}},
+	"VendorQuotation":         {{"tblvendorquotationdtl", "DocNo", []string{"DNo"}}},
+	"PurchaseOrderRequest":    {{"tblpurchaseorderreqdtl", "DocNo", []string{"DNo"}}},
+	"PurchaseOrder":           {{"tblpurchaseorderdtl", "DocNo", []string{"DNo"}}},
+	"PurchaseMaterialReceive": {{"tblpurchasematerialreceivedtl", "DocNo", []string{"DNo"}}},
+	"PurchaseReturnDelivery":  {{"tblpurchasereturndeliverydtl", "DocNo", []string{"DNo"}}},
+	"VendorSector":            {{"tblvendorsectordtl", "SectorCode", []string{"DNo"}}},
+	"UserGroup":               {{"tblgroupmenu", "GrpCode", []string{"Menu", "Action"}}},
+	"UserScope": {
+		{"tbluserwarehouse", "UserCode", []string{"WhsCode"}},
+		{"tblusersite", "UserCode", []string{"SiteCode"}},
+	},
+	"ApprovalRule":  {{"tblapprovalruledtl", "RuleCode", []string{"Level", "UserCode"}}},
+	"StockOpname":   {{"tblstockopnamedtl", "DocNo", []string{"DNo"}}},
+	"BinTransfer":   {{"tblbintransferdtl", "DocNo", []string{"DNo"}}},
+	"VendorInvoice": {{"tblvendorinvoicedtl", "DocNo", []string{"DNo"}}},
+	"MasterVendor": {
+		{"tblcontactvendordtl", "VendorCode", []string{"DNo"}},
+		{"tblitemcategoryvendordtl", "VendorCode", []string{"ItCatCode"}},
+		{"tblsectorvendordtl", "VendorCode", []string{"VendorSectorCode", "DNoVendorSector"}},
+		{"tblratingvendordtl", "VendorCode", []string{"VendorRatingCode"}},
+	},
+	"MasterCustomer": {
+		{"tblcontactcustomerdtl", "CustCode", []string{"DNo"}},
+		{"tbladdresscustomerdtl", "CustCode", []string{"DNo"}},
+	},
+	"SalesOrder": {{"tblsalesorderdtl", "DocNo", []string{"DNo"}}},
 }
 
 var listDoc = map[string]string{
@@ -99,6 +189,35 @@ var listDoc = map[string]string{
 	"PurchaseOrder":           "PO",
 	"PurchaseMaterialReceive": "PMRV",
 	"PurchaseReturnDelivery":  "PRDV",
+	"Journal":                 "JN",
+	"StockOpname":             "OPN",
+	"BinTransfer":             "BTF",
+	"VendorInvoice":           "VI",
+	"SalesOrder":              "SO",
+}
+
+const (
+	PeriodMonthly = "monthly"
+	PeriodYearly  = "yearly"
+)
+
+// DocPattern adalah format nomor dokumen. Token yang dikenali:
+// {seq:N} nomor urut dengan lebar N, {site}, {doc}, {MM}, {YY} dan {YYYY}.
+// Period kosong berarti nomor urut tidak pernah di-reset.
+type DocPattern struct {
+	Format string
+	Period string
+}
+
+var defaultPattern = DocPattern{
+	Format: "{seq:4}/{site}/{doc}/{MM}/{YY}",
+	Period: PeriodMonthly,
+}
+
+// kategori master yang nomornya tidak memakai kode dokumen
+var listPattern = map[string]DocPattern{
+	"MasterVendor":   {Format: "{seq:5}"},
+	"MasterCustomer": {Format: "{seq:5}"},
 }
 
 func TableOf(category string) (string, error) {
@@ -121,3 +240,21 @@ func DocNumberOf(category string) (string, error) {
 	}
 	return "", customerrors.ErrKeyNotFound
 }
+
+// PatternOf mengembalikan pattern default sebuah kategori, kategori dokumen
+// yang tidak didefinisikan di listPattern memakai defaultPattern.
+func PatternOf(category string) (DocPattern, error) {
+	if pattern, exists := listPattern[category]; exists {
+		return pattern, nil
+	}
+	if _, exists := listDoc[category]; exists {
+		return defaultPattern, nil
+	}
+	return DocPattern{}, customerrors.ErrKeyNotFound
+}
+
+// DetailTablesOf mengembalikan tabel detail kategori, kosong untuk master
+// yang tidak punya detail.
+func DetailTablesOf(category string) []DetailTable {
+	return listDetail[category]
+}
@@ -0,0 +1,30 @@
+package masterimport
+
+// master data yang bisa di-import
+const (
+	Item      = "item"
+	Vendor    = "vendor"
+	Warehouse = "warehouse"
+	Uom       = "uom"
+	// kurs harian, baris dengan pasangan dan tanggal yang sama ditimpa
+	ExchangeRate = "exchange-rate"
+)
+
+type RowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Result adalah laporan import. Satu record vendor bisa terdiri dari beberapa baris
+// (baris lanjutan untuk contact/sector/rating), jadi TotalRecords bisa < TotalRows.
+type Result struct {
+	Entity         string     `json:"entity"`
+	DryRun         bool       `json:"dry_run"`
+	TotalRows      int        `json:"total_rows"`
+	TotalRecords   int        `json:"total_records"`
+	ValidRecords   int        `json:"valid_records"`
+	Imported       int        `json:"imported"`
+	IgnoredColumns []string   `json:"ignored_columns"`
+	Errors         []RowError `json:"errors"`
+}
@@ -0,0 +1,20 @@
+package masterimport
+
+import (
+	"context"
+
+	"gitlab.com/ayaka/internal/domain/exchangerate"
+	"gitlab.com/ayaka/internal/domain/tblmasteritem"
+	"gitlab.com/ayaka/internal/domain/tblmastervendor"
+	"gitlab.com/ayaka/internal/domain/tbluom"
+	"gitlab.com/ayaka/internal/domain/tblwarehouse"
+)
+
+// Repository menyimpan semua baris dalam satu transaksi; satu baris gagal berarti tidak ada yang tersimpan.
+type Repository interface {
+	ImportItems(ctx context.Context, data []*tblmasteritem.Create) error
+	ImportVendors(ctx context.Context, data []*tblmastervendor.Create) error
+	ImportWarehouses(ctx context.Context, data []*tblwarehouse.CreateTblWarehouse) error
+	ImportUoms(ctx context.Context, data []*tbluom.CreateTblUom) error
+	ImportExchangeRates(ctx context.Context, data []*exchangerate.Rate) error
+}
@@ -0,0 +1,80 @@
+package printout
+
+// jenis dokumen yang bisa dicetak, sama dengan nama route-nya
+const (
+	PurchaseOrder           = "purchase-order"
+	DirectSalesDelivery     = "direct-sales-delivery"
+	MaterialTransfer        = "material-transfer"
+	PurchaseMaterialReceive = "purchase-material-receive"
+)
+
+// format hasil cetak
+const (
+	HTML = "html"
+	PDF  = "pdf"
+)
+
+// Party adalah vendor, customer atau gudang yang tercantum di dokumen
+type Party struct {
+	Code            string `db:"Code"`
+	Name            string `db:"Name"`
+	Address         string `db:"Address"`
+	City            string `db:"City"`
+	PostalCode      string `db:"PostalCode"`
+	Phone           string `db:"Phone"`
+	Email           string `db:"Email"`
+	ContactName     string `db:"ContactName"`
+	ContactNumber   string `db:"ContactNumber"`
+	ContactPosition string `db:"ContactPosition"`
+}
+
+// Info adalah keterangan tambahan di header, contoh Driver atau No PO
+type Info struct {
+	Label string
+	Value string
+}
+
+type Line struct {
+	No        int
+	ItemCode  string  `db:"ItCode"`
+	ItemName  string  `db:"ItName"`
+	Batch     string  `db:"BatchNo"`
+	Qty       float32 `db:"Qty"`
+	Uom       string  `db:"UomName"`
+	UnitPrice float32 `db:"Price"`
+	Total     float32 `db:"Total"`
+	Reference string  `db:"Reference"`
+	Remark    string  `db:"Remark"`
+}
+
+// Document adalah data cetak yang sudah lengkap, dipakai template HTML maupun layout PDF.
+// Parties berisi vendor/customer, atau gudang asal dan tujuan untuk transfer.
+type Document struct {
+	Type        string
+	Title       string
+	DocNo       string
+	Date        string
+	Status      string
+	SiteCode    string
+	SiteName    string
+	SiteAddress string
+	Parties     []NamedParty
+	Info        []Info
+	Lines       []Line
+	ShowPrice   bool
+	Currency    string
+	SubTotal    float32
+	TaxCode     string
+	TaxName     string
+	TaxRate     float32
+	TaxAmount   float32
+	GrandTotal  float32
+	Remark      string
+	PrintedBy   string
+	PrintedAt   string
+}
+
+type NamedParty struct {
+	Label string
+	Party
+}
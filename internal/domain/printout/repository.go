@@ -0,0 +1,9 @@
+package printout
+
+import "context"
+
+type Repository interface {
+	// Document mengambil data cetak sebuah dokumen sesuai jenisnya,
+	// ErrDataNotFound jika tidak ada atau di luar scope gudang user.
+	Document(ctx context.Context, docType, docNo string) (*Document, error)
+}
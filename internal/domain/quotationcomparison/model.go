@@ -0,0 +1,60 @@
+package quotationcomparison
+
+import "gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+
+// Weight bobot kriteria ranking, DeliveryTypes nilai 0..1 per delivery type
+// (key lowercase)
+type Weight struct {
+	Price         float64
+	Payment       float64
+	Delivery      float64
+	Rating        float64
+	DeliveryTypes map[string]float64
+}
+
+// Comparison satu baris PO request (atau satu item) beserta quotation yang
+// sudah diurutkan, Candidates[0] adalah pemenang. MissingRates mata uang
+// quotation yang belum punya kurs ke base (lihat /exchange-rate).
+type Comparison struct {
+	PurchaseOrderRequestDocNo string       `db:"DocNo" json:"purchase_order_request_document"`
+	PurchaseOrderRequestDNo   string       `db:"DNo" json:"purchase_order_request_detail"`
+	ItemCode                  string       `db:"ItCode" json:"item_code"`
+	ItemName                  string       `db:"ItName" json:"item_name"`
+	Qty                       float32      `db:"Qty" json:"quantity"`
+	VendorQuotationDocNo      string       `db:"VendorQTDocNo" json:"current_vendor_quotation_document"`
+	VendorQuotationDNo        string       `db:"VendorQTDNo" json:"current_vendor_quotation_detail"`
+	BaseCurrency              string       `json:"base_currency"`
+	MissingRates              []string     `json:"missing_rates"`
+	Candidates                []*Candidate `json:"candidates"`
+}
+
+type Candidate struct {
+	Rank                 int                       `json:"rank"`
+	VendorQuotationDocNo string                    `db:"DocNo" json:"vendor_quotation_document"`
+	VendorQuotationDNo   string                    `db:"DNo" json:"vendor_quotation_detail"`
+	ItemCode             string                    `db:"ItCode" json:"item_code"`
+	VendorCode           string                    `db:"VendorCode" json:"vendor_code"`
+	VendorName           string                    `db:"VendorName" json:"vendor_name"`
+	CurCode              string                    `db:"CurCode" json:"currency_code"`
+	Price                float64                   `db:"Price" json:"price"`
+	Rate                 float64                   `json:"rate"`
+	NormalizedPrice      float64                   `json:"normalized_price"`
+	RateMissing          bool                      `json:"rate_missing"`
+	TermOfPayment        string                    `db:"TermOfPayment" json:"term_of_payment"`
+	PaymentDays          int                       `json:"payment_days"`
+	DeliveryType         nulldatatype.NullDataType `db:"DeliveryType" json:"delivery_type"`
+	VendorRating         float64                   `db:"VendorRating" json:"vendor_rating"`
+	Score                float64                   `json:"score"`
+}
+
+type Selection struct {
+	PurchaseOrderRequestDocNo string  `json:"purchase_order_request_document" validate:"required"`
+	PurchaseOrderRequestDNo   string  `json:"purchase_order_request_detail" validate:"required"`
+	VendorQuotationDocNo      string  `json:"vendor_quotation_document" validate:"required"`
+	VendorQuotationDNo        string  `json:"vendor_quotation_detail" validate:"required"`
+	VendorCode                string  `json:"vendor_code"`
+	Price                     float64 `json:"price"`
+	Total                     float64 `json:"total"`
+	LastUpdateBy              string  `json:"last_update_by"`
+	LastUpdateDate            string  `json:"last_update_date"`
+}
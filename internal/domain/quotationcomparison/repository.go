@@ -0,0 +1,11 @@
+package quotationcomparison
+
+import "context"
+
+type Repository interface {
+	// Compare membandingkan quotation aktif yang belum dipakai untuk baris PO
+	// request porDocNo, atau untuk itemCodes kalau porDocNo kosong
+	Compare(ctx context.Context, porDocNo string, itemCodes []string, base string, weight Weight) ([]*Comparison, error)
+	// Select memakai quotation untuk baris PO request
+	Select(ctx context.Context, data *Selection) (*Selection, error)
+}
@@ -0,0 +1,68 @@
+package replenishment
+
+import (
+	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+)
+
+// Setting adalah parameter replenishment satu item di satu gudang. MaxQty 0
+// berarti pesan sampai ReorderPoint + SafetyStock, LeadTime dalam hari.
+type Setting struct {
+	Number        uint                      `json:"number"`
+	ItemCode      string                    `db:"ItCode" json:"item_code" validate:"required,incolumn=tblitem->ItCode" label:"Item"`
+	ItemName      string                    `db:"ItName" json:"item_name"`
+	WarehouseCode string                    `db:"WhsCode" json:"warehouse_code" validate:"required,incolumn=tblwarehouse->WhsCode" label:"Warehouse"`
+	WarehouseName string                    `db:"WhsName" json:"warehouse_name"`
+	SafetyStock   float32                   `db:"SafetyStock" json:"safety_stock" validate:"min=0" label:"Safety Stock"`
+	ReorderPoint  float32                   `db:"ReorderPoint" json:"reorder_point" validate:"min=0" label:"Reorder Point"`
+	MinQty        float32                   `db:"MinQty" json:"min_qty" validate:"min=0" label:"Min Qty"`
+	MaxQty        float32                   `db:"MaxQty" json:"max_qty" validate:"min=0" label:"Max Qty"`
+	LeadTime      int                       `db:"LeadTime" json:"lead_time" validate:"min=0" label:"Lead Time"`
+	Active        booldatatype.BoolDataType `db:"ActInd" json:"active"`
+	Remark        nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	CreateBy      string                    `db:"CreateBy" json:"-"`
+	CreateDate    string                    `db:"CreateDt" json:"-"`
+}
+
+// Proposal adalah hasil kalkulator replenishment. Projected = OnHand + OpenPO
+// + OpenRequest, Reorder true kalau Projected sudah di bawah batas pesan.
+// Semua qty dalam UoM inventory, PurchaseFactor mengubah 1 UoM beli ke UoM inventory.
+type Proposal struct {
+	ItemCode       string  `db:"ItCode" json:"item_code" label:"Item Code"`
+	ItemName       string  `db:"ItName" json:"item_name" label:"Item"`
+	WarehouseCode  string  `db:"WhsCode" json:"warehouse_code" label:"Warehouse Code"`
+	WarehouseName  string  `db:"WhsName" json:"warehouse_name" label:"Warehouse"`
+	UomName        string  `db:"UomName" json:"uom_name" label:"UoM"`
+	SafetyStock    float32 `db:"SafetyStock" json:"safety_stock" label:"Safety Stock"`
+	ReorderPoint   float32 `db:"ReorderPoint" json:"reorder_point" label:"Reorder Point"`
+	MinQty         float32 `db:"MinQty" json:"min_qty" label:"Min Qty"`
+	MaxQty         float32 `db:"MaxQty" json:"max_qty" label:"Max Qty"`
+	LeadTime       int     `db:"LeadTime" json:"lead_time" label:"Lead Time (Days)"`
+	OnHand         float32 `db:"OnHand" json:"on_hand" label:"On Hand"`
+	OpenPO         float32 `json:"open_po" label:"Open PO"`
+	OpenRequest    float32 `json:"open_material_request" label:"Open Material Request"`
+	Projected      float32 `json:"projected" label:"Projected"`
+	Reorder        bool    `json:"reorder" label:"Reorder"`
+	SuggestedQty   float32 `json:"suggested_qty" label:"Suggested Qty"`
+	PurchaseFactor float32 `json:"-"`
+}
+
+// Selection adalah proposal yang dipilih untuk dijadikan material request.
+// Qty dalam UoM inventory seperti proposal, 0 memakai SuggestedQty.
+type Selection struct {
+	ItemCode       string  `json:"item_code" validate:"required" label:"Item"`
+	WarehouseCode  string  `json:"warehouse_code" validate:"required" label:"Warehouse"`
+	Qty            float32 `json:"quantity" validate:"min=0" label:"Quantity"`
+	EstimatedPrice float32 `json:"estimated_price" validate:"min=0" label:"Estimated Price"`
+}
+
+// Request membuat draft material request dari proposal terpilih. Date
+// berformat yyyy-mm-dd, usage date tiap baris = Date + LeadTime.
+type Request struct {
+	Date       string                    `json:"date" validate:"required" label:"Date"`
+	SiteCode   nulldatatype.NullDataType `json:"site_code"`
+	Department string                    `json:"department" validate:"required" label:"Department"`
+	CurCode    string                    `json:"currency_code" validate:"required,incolumn=tblcurrency->CurCode" label:"Currency"`
+	Remark     nulldatatype.NullDataType `json:"remark"`
+	Lines      []Selection               `json:"lines" validate:"required,min=1,dive" label:"Lines"`
+}
@@ -0,0 +1,13 @@
+package replenishment
+
+import (
+	"context"
+
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type Repository interface {
+	FetchSetting(ctx context.Context, warehouse, search string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	SaveSetting(ctx context.Context, data *Setting) (*Setting, error)
+	Proposals(ctx context.Context, warehouse, itemCategory string) ([]*Proposal, error)
+}
@@ -4,20 +4,28 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/config"
 	"gitlab.com/ayaka/internal/adapter/repository"
 	"gitlab.com/ayaka/internal/domain/logactivity"
 )
 
+const defaultSite = "R1"
+
+var seqToken = regexp.MustCompile(`\{seq(?::(\d+))?\}`)
+
 type GenerateIDModel struct {
 	LastId string `db:"LastId"`
 }
 
 type GenerateIDHandler struct {
-	DB *repository.Sqlx `inject:"database"`
+	DB   *repository.Sqlx `inject:"database"`
+	Conf *config.Config   `inject:"config"`
 }
 
 func FormatId(id string, prefixParts string) (string, error) {
@@ -36,52 +44,189 @@ func FormatId(id string, prefixParts string) (string, error) {
 	return newId, nil
 }
 
-func (s *GenerateIDHandler) GenerateID(ctx context.Context, category string) (string, error) {
-	doc, err := logactivity.DocNumberOf(category)
+// GenerateID mengambil nomor dokumen berikutnya untuk site default, dipakai
+// dokumen yang tidak punya site. Dokumen ber-site memakai GenerateIDForSite.
+func (s *GenerateIDHandler) GenerateID(ctx context.Context, tx *sqlx.Tx, category, docDt string) (string, error) {
+	return s.GenerateIDForSite(ctx, tx, category, "", docDt)
+}
+
+// GenerateIDForSite menaikkan nomor urut di tbldocsequence di dalam transaksi
+// pemanggil. Baris sequence terkunci sampai transaksi selesai sehingga create
+// yang berjalan bersamaan tidak mendapat nomor yang sama, dan nomor ikut
+// di-rollback jika insert dokumen gagal. Periode dan token tanggal diambil dari
+// docDt (YYYYMMDD), bukan tanggal hari ini, supaya dokumen backdate masuk ke
+// nomor urut periodenya.
+//
+//	CREATE TABLE tbldocsequence (
+//		Category VARCHAR(50) NOT NULL,
+//		SiteCode VARCHAR(20) NOT NULL,
+//		Period   VARCHAR(6)  NOT NULL,
+//		LastNo   INT         NOT NULL,
+//		PRIMARY KEY (Category, SiteCode, Period)
+//	);
+func (s *GenerateIDHandler) GenerateIDForSite(ctx context.Context, tx *sqlx.Tx, category, site, docDt string) (string, error) {
+	pattern, err := s.patternOf(category)
 	if err != nil {
 		return "", err
 	}
+	if site == "" {
+		site = s.defaultSite()
+	}
 
-	primKey, err := logactivity.PrimaryKeyOf(category)
+	date, err := docDate(docDt)
 	if err != nil {
 		return "", err
 	}
-
-	table, err := logactivity.TableOf(category)
+	period, err := periodOf(pattern.Period, date)
 	if err != nil {
 		return "", err
 	}
 
-	date := "'%" +time.Now().Format("01/06") + "%'"
+	// satu statement untuk membuat atau menaikkan sequence, nilainya dibaca
+	// lewat LAST_INSERT_ID() milik koneksi transaksi ini
+	query := "INSERT INTO tbldocsequence (Category, SiteCode, Period, LastNo) VALUES (?, ?, ?, LAST_INSERT_ID(1)) ON DUPLICATE KEY UPDATE LastNo = LAST_INSERT_ID(LastNo + 1)"
+	res, err := tx.ExecContext(ctx, query, category, site, period)
+	if err != nil {
+		return "", fmt.Errorf("failed to increment sequence: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return "", fmt.Errorf("failed to increment sequence: %w", err)
+	}
 
-	query := fmt.Sprintf("SELECT %s AS LastId FROM %s WHERE %s LIKE %s ORDER BY %s DESC LIMIT 1", primKey, table, primKey, date, primKey)
-	var lastId GenerateIDModel
+	// baris baru (affected 1) masih terkunci oleh transaksi ini, lanjutkan dari
+	// nomor terakhir yang sudah tersimpan agar tidak bentrok dengan dokumen lama
+	if affected == 1 {
+		last, err := lastNumber(ctx, tx, category, pattern.Format, site, date)
+		if err != nil {
+			return "", err
+		}
+		if last > 0 {
+			query := "UPDATE tbldocsequence SET LastNo = LAST_INSERT_ID(?) WHERE Category = ? AND SiteCode = ? AND Period = ?"
+			if _, err := tx.ExecContext(ctx, query, last+1, category, site, period); err != nil {
+				return "", fmt.Errorf("failed to seed sequence: %w", err)
+			}
+		}
+	}
 
-	err = s.DB.GetContext(ctx, &lastId, query)
-	if err != nil && err != sql.ErrNoRows {
-		return "", fmt.Errorf("failed to get last ID: %w", err)
+	var num int
+	if err = tx.GetContext(ctx, &num, "SELECT LAST_INSERT_ID()"); err != nil {
+		return "", fmt.Errorf("failed to get sequence: %w", err)
 	}
 
-	var splitId string
-	if lastId.LastId != "" {
-		parts := strings.Split(lastId.LastId, "/")
-		if len(parts) > 0 {
-			splitId = parts[0]
+	return render(pattern.Format, num, site, docCodeOf(category), date), nil
+}
+
+// docDate membaca tanggal dokumen YYYYMMDD, CreateDt (YYYYMMDDhhmm) juga diterima.
+func docDate(docDt string) (time.Time, error) {
+	if len(docDt) < 8 {
+		return time.Time{}, fmt.Errorf("invalid document date: %q", docDt)
+	}
+	date, err := time.Parse("20060102", docDt[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid document date: %q", docDt)
+	}
+	return date, nil
+}
+
+func (s *GenerateIDHandler) patternOf(category string) (logactivity.DocPattern, error) {
+	pattern, err := logactivity.PatternOf(category)
+	if err != nil {
+		return pattern, err
+	}
+
+	if s.Conf != nil {
+		if custom, ok := s.Conf.DocNumber.Patterns[strings.ToLower(category)]; ok && custom.Format != "" {
+			pattern = logactivity.DocPattern{Format: custom.Format, Period: custom.Period}
 		}
 	}
 
-	if splitId == "" {
-		splitId = "0"
+	if !seqToken.MatchString(pattern.Format) {
+		return pattern, fmt.Errorf("pattern %q for %s has no {seq} token", pattern.Format, category)
 	}
 
-	num, err := strconv.Atoi(splitId)
+	return pattern, nil
+}
+
+func (s *GenerateIDHandler) defaultSite() string {
+	if s.Conf != nil && s.Conf.DocNumber.Site != "" {
+		return s.Conf.DocNumber.Site
+	}
+	return defaultSite
+}
+
+func periodOf(period string, t time.Time) (string, error) {
+	switch period {
+	case logactivity.PeriodMonthly:
+		return t.Format("200601"), nil
+	case logactivity.PeriodYearly:
+		return t.Format("2006"), nil
+	case "":
+		return "", nil
+	default:
+		return "", fmt.Errorf("invalid sequence period: %s", period)
+	}
+}
+
+func docCodeOf(category string) string {
+	doc, _ := logactivity.DocNumberOf(category)
+	return doc
+}
+
+// render mengganti token pada format, lihat logactivity.DocPattern.
+func render(format string, seq int, site, doc string, t time.Time) string {
+	result := seqToken.ReplaceAllStringFunc(format, func(token string) string {
+		width := 0
+		if match := seqToken.FindStringSubmatch(token); match[1] != "" {
+			width, _ = strconv.Atoi(match[1])
+		}
+		return fmt.Sprintf("%0*d", width, seq)
+	})
+
+	return strings.NewReplacer(
+		"{site}", site,
+		"{doc}", doc,
+		"{MM}", t.Format("01"),
+		"{YYYY}", t.Format("2006"),
+		"{YY}", t.Format("06"),
+	).Replace(result)
+}
+
+// lastNumber mencari nomor urut terbesar yang sudah dipakai di tabel dokumen
+// dengan prefix dan suffix pattern yang sama.
+func lastNumber(ctx context.Context, tx *sqlx.Tx, category, format, site string, t time.Time) (int, error) {
+	table, err := logactivity.TableOf(category)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse numeric part: %v", err)
+		return 0, err
 	}
 
-	newId := fmt.Sprintf("%04d/R1/%s/%s", num+1, doc, time.Now().Format("01/06"))
+	primKey, err := logactivity.PrimaryKeyOf(category)
+	if err != nil {
+		return 0, err
+	}
 
-	return newId, nil
+	loc := seqToken.FindStringIndex(format)
+	doc := docCodeOf(category)
+	prefix := render(format[:loc[0]], 0, site, doc, t)
+	suffix := render(format[loc[1]:], 0, site, doc, t)
+
+	query := fmt.Sprintf("SELECT %s AS LastId FROM %s WHERE %s LIKE ? ORDER BY %s DESC LIMIT 1", primKey, table, primKey, primKey)
+	var lastId GenerateIDModel
+
+	err = tx.GetContext(ctx, &lastId, query, prefix+"%"+suffix)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get last ID: %w", err)
+	}
+
+	num, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(lastId.LastId, prefix), suffix))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse numeric part: %v", err)
+	}
+
+	return num, nil
 }
 
 // to get last detail number from a table
@@ -100,19 +245,3 @@ func (s *GenerateIDHandler) GetLastDetailNumber(ctx context.Context, category st
 
 	return total, nil
 }
-
-// Generate Code
-func (s *GenerateIDHandler) GenerateIDCode(ctx context.Context, tblName string) (string, error) {
-	query := fmt.Sprintf("SELECT COUNT(*) FROM %s;", tblName)
-	var count int
-	err := s.DB.GetContext(ctx, &count, query)
-	if err != nil {
-		return "", fmt.Errorf("failed to get count: %w", err)
-	}
-
-	if count == 0 {
-		return "00001", nil
-	}
-
-	return fmt.Sprintf("%05d", count+1), nil
-}
\ No newline at end of file
@@ -0,0 +1,140 @@
+package formatid
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"gitlab.com/ayaka/config"
+)
+
+const (
+	queryUpsert   = "INSERT INTO tbldocsequence (Category, SiteCode, Period, LastNo) VALUES (?, ?, ?, LAST_INSERT_ID(1)) ON DUPLICATE KEY UPDATE LastNo = LAST_INSERT_ID(LastNo + 1)"
+	querySeed     = "UPDATE tbldocsequence SET LastNo = LAST_INSERT_ID(?) WHERE Category = ? AND SiteCode = ? AND Period = ?"
+	querySequence = "SELECT LAST_INSERT_ID()"
+)
+
+type GenerateIDHandlerSuite struct {
+	suite.Suite
+	mockSQL sqlmock.Sqlmock
+	handler *GenerateIDHandler
+	db      *sqlx.DB
+	tx      *sqlx.Tx
+}
+
+func (suite *GenerateIDHandlerSuite) SetupTest() {
+	mockDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	suite.Require().NoError(err)
+
+	suite.mockSQL = mock
+	suite.db = sqlx.NewDb(mockDb, "mysql")
+	suite.handler = &GenerateIDHandler{Conf: &config.Config{}}
+
+	suite.mockSQL.ExpectBegin()
+	suite.tx, err = suite.db.Beginx()
+	suite.Require().NoError(err)
+}
+
+func (suite *GenerateIDHandlerSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *GenerateIDHandlerSuite) expectSequence(num int) {
+	suite.mockSQL.ExpectQuery(querySequence).
+		WillReturnRows(sqlmock.NewRows([]string{"LAST_INSERT_ID()"}).AddRow(num))
+}
+
+func (suite *GenerateIDHandlerSuite) TestGenerateID_ExistingSequence() {
+	suite.mockSQL.ExpectExec(queryUpsert).
+		WithArgs("PurchaseOrder", "R1", "202503").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.expectSequence(7)
+
+	id, err := suite.handler.GenerateID(context.Background(), suite.tx, "PurchaseOrder", "20250314")
+
+	suite.NoError(err)
+	suite.Equal("0007/R1/PO/03/25", id)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// dokumen backdate memakai periode dan token tanggal dari tanggal dokumen
+func (suite *GenerateIDHandlerSuite) TestGenerateID_BackdatedDocumentUsesDocumentPeriod() {
+	suite.mockSQL.ExpectExec(queryUpsert).
+		WithArgs("PurchaseOrder", "R1", "202412").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.expectSequence(21)
+
+	id, err := suite.handler.GenerateID(context.Background(), suite.tx, "PurchaseOrder", "20241231")
+
+	suite.NoError(err)
+	suite.Equal("0021/R1/PO/12/24", id)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+// sequence baru dimulai dari nomor terakhir yang sudah ada di tabel dokumen
+func (suite *GenerateIDHandlerSuite) TestGenerateID_NewPeriodSeededFromTable() {
+	suffix := "/SITE2/PO/03/25"
+
+	suite.mockSQL.ExpectExec(queryUpsert).
+		WithArgs("PurchaseOrder", "SITE2", "202503").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSQL.ExpectQuery("SELECT DocNo AS LastId FROM tblpurchaseorderhdr WHERE DocNo LIKE ? ORDER BY DocNo DESC LIMIT 1").
+		WithArgs("%" + suffix).
+		WillReturnRows(sqlmock.NewRows([]string{"LastId"}).AddRow("0012" + suffix))
+	suite.mockSQL.ExpectExec(querySeed).
+		WithArgs(13, "PurchaseOrder", "SITE2", "202503").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.expectSequence(13)
+
+	id, err := suite.handler.GenerateIDForSite(context.Background(), suite.tx, "PurchaseOrder", "SITE2", "20250314")
+
+	suite.NoError(err)
+	suite.Equal("0013"+suffix, id)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func (suite *GenerateIDHandlerSuite) TestGenerateID_YearlyPatternFromConfig() {
+	suite.handler.Conf.DocNumber = config.DocNumberConfig{
+		Site: "JKT",
+		Patterns: map[string]config.DocNumberPattern{
+			"stockmutation": {Format: "{doc}-{site}-{YYYY}-{seq:6}", Period: "yearly"},
+		},
+	}
+
+	suite.mockSQL.ExpectExec(queryUpsert).
+		WithArgs("StockMutation", "JKT", "2025").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.expectSequence(42)
+
+	id, err := suite.handler.GenerateID(context.Background(), suite.tx, "StockMutation", "20250314")
+
+	suite.NoError(err)
+	suite.Equal("SM-JKT-2025-000042", id)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func (suite *GenerateIDHandlerSuite) TestGenerateID_MasterCodeNeverResets() {
+	suite.mockSQL.ExpectExec(queryUpsert).
+		WithArgs("MasterVendor", "R1", "").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.expectSequence(3)
+
+	id, err := suite.handler.GenerateID(context.Background(), suite.tx, "MasterVendor", "202503141000")
+
+	suite.NoError(err)
+	suite.Equal("00003", id)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func (suite *GenerateIDHandlerSuite) TestGenerateID_InvalidDocumentDate() {
+	_, err := suite.handler.GenerateID(context.Background(), suite.tx, "PurchaseOrder", "")
+
+	suite.Error(err)
+	suite.NoError(suite.mockSQL.ExpectationsWereMet())
+}
+
+func TestGenerateIDHandlerSuite(t *testing.T) {
+	suite.Run(t, new(GenerateIDHandlerSuite))
+}
@@ -0,0 +1,38 @@
+package stockcard
+
+import "gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+
+// Line adalah satu baris tblstockmovement beserta saldo berjalannya.
+type Line struct {
+	Number        uint                      `json:"number" label:"No"`
+	DocDt         string                    `db:"DocDt" json:"doc_date" label:"Date"`
+	DocType       string                    `db:"DocType" json:"doc_type" label:"Document Type"`
+	DocNo         string                    `db:"DocNo" json:"doc_no" label:"Document"`
+	FromTo        nulldatatype.NullDataType `db:"FromTo" json:"from_to" label:"From/To"`
+	WarehouseName string                    `db:"WhsName" json:"warehouse_name" label:"Warehouse"`
+	BatchNo       string                    `db:"BatchNo" json:"batch_no" label:"Batch"`
+	InQty         float32                   `db:"InQty" json:"in_qty" label:"In"`
+	OutQty        float32                   `db:"OutQty" json:"out_qty" label:"Out"`
+	Balance       float32                   `json:"balance" label:"Balance"`
+	Remark        nulldatatype.NullDataType `db:"Remark" json:"remark" label:"Remark"`
+}
+
+// Read adalah kartu stok satu item untuk periode StartDate - EndDate.
+// SummaryBalance adalah saldo tblstocksummary saat ini dengan filter yang sama,
+// Reconciled true jika Closing ditambah movement setelah EndDate sama dengannya.
+type Read struct {
+	ItemCode       string  `db:"ItCode" json:"item_code"`
+	ItemName       string  `db:"ItName" json:"item_name"`
+	Uom            string  `db:"UomName" json:"uom"`
+	WarehouseCode  string  `json:"warehouse_code,omitempty"`
+	BatchNo        string  `json:"batch_no,omitempty"`
+	StartDate      string  `json:"start_date"`
+	EndDate        string  `json:"end_date"`
+	Opening        float32 `json:"opening_balance"`
+	TotalIn        float32 `json:"total_in"`
+	TotalOut       float32 `json:"total_out"`
+	Closing        float32 `json:"closing_balance"`
+	SummaryBalance float32 `json:"summary_balance"`
+	Reconciled     bool    `json:"reconciled"`
+	Lines          []*Line `json:"lines"`
+}
@@ -0,0 +1,7 @@
+package stockcard
+
+import "context"
+
+type Repository interface {
+	Fetch(ctx context.Context, itemCode, warehouse, batch, startDate, endDate string) (*Read, error)
+}
@@ -0,0 +1,82 @@
+package stockopname
+
+import "gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+
+// Status stock opname. Selama masih Open, posting stok ke gudang (dan item
+// category-nya, jika dibatasi) ditolak oleh inventory ledger.
+const (
+	Open      = "O"
+	Approved  = "A"
+	Cancelled = "C"
+)
+
+type Read struct {
+	Number           uint                      `json:"number"`
+	DocNo            string                    `db:"DocNo" json:"document_number"`
+	Date             string                    `db:"DocDt" json:"date"`
+	WarehouseCode    string                    `db:"WhsCode" json:"warehouse_code"`
+	WarehouseName    string                    `db:"WhsName" json:"warehouse_name"`
+	ItemCategoryCode nulldatatype.NullDataType `db:"ItCtCode" json:"item_category_code"`
+	ItemCategoryName nulldatatype.NullDataType `db:"ItCtName" json:"item_category_name"`
+	Bin              nulldatatype.NullDataType `db:"Bin" json:"bin"`
+	Status           string                    `db:"Status" json:"status"`
+	AdjustmentDocNo  nulldatatype.NullDataType `db:"AdjDocNo" json:"adjustment_document_number"`
+	Remark           nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	CreateBy         string                    `db:"CreateBy" json:"create_by"`
+}
+
+// Variance adalah perbandingan stok snapshot dengan hasil hitung round terakhir
+// per item dan batch. Baris yang belum dihitung tidak ikut di-adjust.
+type Variance struct {
+	ItemCode   string  `db:"ItCode" json:"item_code"`
+	ItemName   string  `db:"ItName" json:"item_name"`
+	Batch      string  `db:"BatchNo" json:"batch"`
+	Uom        string  `db:"UomName" json:"uom_name"`
+	SystemQty  float32 `db:"Qty" json:"system_qty"`
+	Counted    bool    `json:"counted"`
+	Round      int     `db:"Round" json:"round"`
+	CountedQty float32 `db:"QtyCount" json:"counted_qty"`
+	Variance   float32 `json:"variance"`
+}
+
+type Detail struct {
+	Read
+	Lines []*Variance `json:"lines"`
+}
+
+type Create struct {
+	DocNo            string                    `db:"DocNo" json:"document_number"`
+	Date             string                    `db:"DocDt" json:"date" validate:"required"`
+	WarehouseCode    string                    `db:"WhsCode" json:"warehouse_code" validate:"required,incolumn=tblwarehouse->WhsCode" label:"Warehouse"`
+	ItemCategoryCode nulldatatype.NullDataType `db:"ItCtCode" json:"item_category_code" validate:"omitempty,incolumn=tblitemcategory->ItCtCode" label:"Item Category"`
+	Bin              nulldatatype.NullDataType `db:"Bin" json:"bin"`
+	Remark           nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	TotalItems       int                       `json:"total_items"`
+	CreateBy         string                    `db:"CreateBy" json:"-"`
+	CreateDate       string                    `db:"CreateDt" json:"-"`
+}
+
+// Count adalah hasil hitung satu round. Item / batch yang dikirim ulang pada
+// round yang sama menimpa hitungan sebelumnya.
+type Count struct {
+	DocNo      string        `json:"document_number"`
+	Round      int           `json:"round" validate:"required,min=1" label:"Count Round"`
+	Details    []CountDetail `json:"details" validate:"required,min=1,dive"`
+	CreateBy   string        `json:"-"`
+	CreateDate string        `json:"-"`
+}
+
+type CountDetail struct {
+	ItemCode string  `json:"item_code" validate:"required,incolumn=tblitem->ItCode" label:"Item"`
+	Batch    string  `json:"batch" validate:"required" label:"Batch"`
+	Qty      float32 `json:"counted_qty" validate:"min=0" label:"Counted Qty"`
+}
+
+// Snapshot adalah saldo tblstocksummary per source saat opname dibuat.
+type Snapshot struct {
+	DNo     string  `db:"DNo"`
+	ItCode  string  `db:"ItCode"`
+	BatchNo string  `db:"BatchNo"`
+	Source  string  `db:"Source"`
+	Qty     float32 `db:"Qty"`
+}
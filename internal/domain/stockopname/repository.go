@@ -0,0 +1,19 @@
+package stockopname
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type Repository interface {
+	Fetch(ctx context.Context, doc, warehouse, status string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Detail(ctx context.Context, docNo string) (*Detail, error)
+	Create(ctx context.Context, data *Create) (*Create, error)
+	Count(ctx context.Context, data *Count) (*Count, error)
+	Approve(ctx context.Context, docNo, userName, date string) (*Detail, error)
+	Cancel(ctx context.Context, docNo, userName, date string) error
+	CheckFrozen(ctx context.Context, tx *sqlx.Tx, movements []inventoryledger.Movement) error
+}
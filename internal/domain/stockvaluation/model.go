@@ -0,0 +1,45 @@
+package stockvaluation
+
+// Metode costing per item category (tblitemcategory.CostMethod)
+const (
+	MovingAverage = "A"
+	FIFO          = "F"
+)
+
+// Layer adalah sisa stok dari satu barang masuk beserta harga pokoknya.
+type Layer struct {
+	LayerID   int64   `db:"LayerId"`
+	RemainQty float32 `db:"RemainQty"`
+	UnitCost  float32 `db:"UnitCost"`
+}
+
+// Entry adalah satu baris tblstockvaluation. Qty dan Value negatif untuk barang
+// keluar / pembatalan barang masuk, DocDt pembatalan adalah tanggal cancel.
+type Entry struct {
+	DocType  string  `db:"DocType"`
+	DocNo    string  `db:"DocNo"`
+	DNo      string  `db:"DNo"`
+	DocDt    string  `db:"DocDt"`
+	WhsCode  string  `db:"WhsCode"`
+	ItCode   string  `db:"ItCode"`
+	BatchNo  string  `db:"BatchNo"`
+	Qty      float32 `db:"Qty"`
+	Value    float32 `db:"Value"`
+	CreateBy string  `db:"CreateBy"`
+	CreateDt string  `db:"CreateDt"`
+}
+
+// Read adalah saldo nilai persediaan per gudang dan item pada suatu tanggal.
+type Read struct {
+	Number        uint    `json:"number" label:"No"`
+	WarehouseCode string  `db:"WhsCode" json:"warehouse_code" label:"Warehouse Code"`
+	WarehouseName string  `db:"WhsName" json:"warehouse_name" label:"Warehouse"`
+	ItemCode      string  `db:"ItCode" json:"item_code" label:"Item Code"`
+	ItemName      string  `db:"ItName" json:"item_name" label:"Item"`
+	Category      string  `db:"ItCtName" json:"item_category_name" label:"Category"`
+	CostMethod    string  `db:"CostMethod" json:"cost_method" label:"Cost Method"`
+	Quantity      float32 `db:"Qty" json:"quantity" label:"Quantity"`
+	Uom           string  `db:"UomName" json:"uom" label:"UoM"`
+	UnitCost      float32 `json:"unit_cost" label:"Unit Cost"`
+	TotalValue    float32 `db:"Value" json:"total_value" label:"Total Value"`
+}
@@ -0,0 +1,15 @@
+package stockvaluation
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/domain/inventoryledger"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type Repository interface {
+	Post(ctx context.Context, tx *sqlx.Tx, movements []inventoryledger.Movement) ([]Entry, error)
+	Reverse(ctx context.Context, tx *sqlx.Tx, movements []inventoryledger.Movement) ([]Entry, error)
+	Fetch(ctx context.Context, date, warehouse, itemCatCode, itemName string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+}
@@ -0,0 +1,113 @@
+package taxengine
+
+import "math"
+
+// Calculate menghitung pajak tiap baris dari Taxes yang sudah di-resolve.
+// Pada mode inclusive harga sudah termasuk pajak non-withholding: DPP awal =
+// Amount / (1 + total tarifnya), pajak dibulatkan, lalu DPP = Amount - pajak
+// supaya DPP + pajak tetap sama dengan harga. Withholding dihitung dari DPP.
+func Calculate(inclusive bool, lines []Line) *Breakdown {
+	var taxes []LineTax
+	for _, line := range lines {
+		amounts := make([]float64, len(line.Taxes))
+
+		base := line.Amount
+		if inclusive {
+			var rate float64
+			for _, tax := range line.Taxes {
+				if !tax.Withholding.ToBool() {
+					rate += tax.TaxRate
+				}
+			}
+			var added float64
+			for i, tax := range line.Taxes {
+				if !tax.Withholding.ToBool() {
+					amounts[i] = Round(line.Amount/(1+rate)*tax.TaxRate, tax.RoundingMode, tax.RoundingDecimal)
+					added += amounts[i]
+				}
+			}
+			base = line.Amount - added
+		}
+
+		for i, tax := range line.Taxes {
+			if !inclusive || tax.Withholding.ToBool() {
+				amounts[i] = Round(base*tax.TaxRate, tax.RoundingMode, tax.RoundingDecimal)
+			}
+			taxes = append(taxes, LineTax{
+				DNo:         line.DNo,
+				TaxCode:     tax.TaxCode,
+				TaxName:     tax.TaxName,
+				TaxRate:     tax.TaxRate,
+				Withholding: tax.Withholding,
+				BaseAmount:  base,
+				TaxAmount:   amounts[i],
+			})
+		}
+	}
+
+	return Summarize(inclusive, lines, taxes)
+}
+
+// Summarize menyusun breakdown dari pajak per baris yang sudah dihitung
+// (dari Calculate atau dari tbldoctaxdtl). Baris tanpa pajak DPP-nya Amount.
+func Summarize(inclusive bool, lines []Line, taxes []LineTax) *Breakdown {
+	result := &Breakdown{
+		Inclusive: inclusive,
+		Lines:     make([]LineTax, 0),
+		Taxes:     make([]TaxTotal, 0),
+	}
+
+	bases := make(map[string]float64)
+	totals := make(map[string]int)
+	for _, tax := range taxes {
+		bases[tax.DNo] = tax.BaseAmount
+		result.Lines = append(result.Lines, tax)
+
+		i, ok := totals[tax.TaxCode]
+		if !ok {
+			i = len(result.Taxes)
+			totals[tax.TaxCode] = i
+			result.Taxes = append(result.Taxes, TaxTotal{
+				TaxCode:     tax.TaxCode,
+				TaxName:     tax.TaxName,
+				TaxRate:     tax.TaxRate,
+				Withholding: tax.Withholding,
+			})
+		}
+		result.Taxes[i].BaseAmount += tax.BaseAmount
+		result.Taxes[i].TaxAmount += tax.TaxAmount
+
+		if tax.Withholding.ToBool() {
+			result.TotalWithholding += tax.TaxAmount
+		} else {
+			result.TotalTax += tax.TaxAmount
+		}
+	}
+
+	for _, line := range lines {
+		if base, ok := bases[line.DNo]; ok {
+			result.SubTotal += base
+		} else {
+			result.SubTotal += line.Amount
+		}
+	}
+	result.GrandTotal = result.SubTotal + result.TotalTax - result.TotalWithholding
+
+	return result
+}
+
+// Round membulatkan v ke decimals digit sesuai mode, default HALF_UP
+func Round(v float64, mode string, decimals int) float64 {
+	p := math.Pow10(decimals)
+	// toleransi kecil untuk sisa floating point, mis. 1.005 * 100
+	x := v * p
+	switch mode {
+	case RoundUp:
+		x = math.Ceil(x - 1e-9)
+	case RoundDown:
+		x = math.Floor(x + 1e-9)
+	default:
+		x = math.Round(x + 1e-9)
+	}
+	return x / p
+}
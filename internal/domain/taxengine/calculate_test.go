@@ -0,0 +1,40 @@
+package taxengine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+)
+
+var (
+	vat = Tax{TaxCode: "PPN11", TaxRate: 0.11, Withholding: booldatatype.FromBool(false), RoundingMode: RoundHalfUp, RoundingDecimal: 0}
+	wht = Tax{TaxCode: "PPH23", TaxRate: 0.02, Withholding: booldatatype.FromBool(true), RoundingMode: RoundDown, RoundingDecimal: 0}
+)
+
+// harga inclusive: DPP + PPN tetap sama dengan harga, PPh dipotong dari DPP
+func TestCalculate_InclusiveWithWithholding(t *testing.T) {
+	result := Calculate(true, []Line{{DNo: "001", Amount: 1000, Taxes: []Tax{vat, wht}}})
+
+	assert.Len(t, result.Lines, 2)
+	assert.Equal(t, 901.0, result.SubTotal)
+	assert.Equal(t, 99.0, result.TotalTax)
+	assert.Equal(t, 18.0, result.TotalWithholding)
+	assert.Equal(t, 982.0, result.GrandTotal)
+}
+
+func TestCalculate_ExclusiveRoundingUp(t *testing.T) {
+	up := vat
+	up.RoundingMode = RoundUp
+	up.RoundingDecimal = 2
+
+	result := Calculate(false, []Line{
+		{DNo: "001", Amount: 333.33, Taxes: []Tax{up}},
+		{DNo: "002", Amount: 100},
+	})
+
+	assert.InDelta(t, 433.33, result.SubTotal, 1e-9)
+	assert.InDelta(t, 36.67, result.TotalTax, 1e-9)
+	assert.InDelta(t, 470.00, result.GrandTotal, 1e-9)
+	assert.Len(t, result.Taxes, 1)
+}
@@ -0,0 +1,81 @@
+package taxengine
+
+import "gitlab.com/ayaka/internal/domain/shared/booldatatype"
+
+// jenis dokumen pemilik breakdown di tbldoctaxdtl
+const (
+	PurchaseOrder         = "PurchaseOrder"
+	DirectSalesDelivery   = "DirectSalesDelivery"
+	DirectPurchaseReceive = "DirectPurchaseReceive"
+)
+
+// mode pembulatan pajak per baris
+const (
+	RoundHalfUp = "HALF_UP"
+	RoundUp     = "UP"
+	RoundDown   = "DOWN"
+)
+
+// Tax definisi pajak dari tbltax. Pajak withholding (PPh) dipotong dari
+// tagihan, selain itu (PPN) ditambahkan.
+type Tax struct {
+	TaxCode         string                    `db:"TaxCode" json:"tax_code"`
+	TaxName         string                    `db:"TaxName" json:"tax_name"`
+	TaxRate         float64                   `db:"TaxRate" json:"tax_rate"`
+	Withholding     booldatatype.BoolDataType `db:"WithholdInd" json:"withholding"`
+	RoundingMode    string                    `db:"RoundingMode" json:"rounding_mode"`
+	RoundingDecimal int                       `db:"RoundingDecimal" json:"rounding_decimal"`
+}
+
+// Line baris dokumen yang dihitung, Amount = qty x harga (termasuk pajak
+// kalau mode inclusive). TaxCodes kosong berarti pakai default.
+type Line struct {
+	DNo      string   `json:"detail_number" validate:"required"`
+	Amount   float64  `json:"amount" validate:"min=0"`
+	TaxCodes []string `json:"tax_codes" validate:"max=5,dive,incolumn=tbltax->TaxCode"`
+	Taxes    []Tax    `json:"-"`
+}
+
+// Request input perhitungan. Urutan pajak per baris: TaxCodes baris, lalu
+// TaxCode header, lalu tax group default vendor/customer.
+type Request struct {
+	VendorCode   string `json:"vendor_code" validate:"omitempty,incolumn=tblvendorhdr->VendorCode"`
+	CustomerCode string `json:"customer_code" validate:"omitempty,incolumn=tblcustomerhdr->CustCode"`
+	TaxCode      string `json:"tax_code" validate:"omitempty,incolumn=tbltax->TaxCode"`
+	Inclusive    bool   `json:"tax_inclusive"`
+	Lines        []Line `json:"lines" validate:"required,dive"`
+}
+
+// LineTax satu pajak di satu baris dokumen, disimpan di tbldoctaxdtl
+type LineTax struct {
+	DocNo       string                    `db:"DocNo" json:"-"`
+	DNo         string                    `db:"DNo" json:"detail_number"`
+	TaxCode     string                    `db:"TaxCode" json:"tax_code"`
+	TaxName     string                    `db:"TaxName" json:"tax_name"`
+	TaxRate     float64                   `db:"TaxRate" json:"tax_rate"`
+	Withholding booldatatype.BoolDataType `db:"WithholdInd" json:"withholding"`
+	BaseAmount  float64                   `db:"BaseAmt" json:"base_amount"`
+	TaxAmount   float64                   `db:"TaxAmt" json:"tax_amount"`
+}
+
+// TaxTotal total per kode pajak satu dokumen
+type TaxTotal struct {
+	TaxCode     string                    `json:"tax_code"`
+	TaxName     string                    `json:"tax_name"`
+	TaxRate     float64                   `json:"tax_rate"`
+	Withholding booldatatype.BoolDataType `json:"withholding"`
+	BaseAmount  float64                   `json:"base_amount"`
+	TaxAmount   float64                   `json:"tax_amount"`
+}
+
+// Breakdown rincian pajak dokumen. SubTotal adalah DPP, GrandTotal =
+// SubTotal + TotalTax - TotalWithholding.
+type Breakdown struct {
+	Inclusive        bool       `json:"tax_inclusive"`
+	Lines            []LineTax  `json:"lines"`
+	Taxes            []TaxTotal `json:"taxes"`
+	SubTotal         float64    `json:"sub_total"`
+	TotalTax         float64    `json:"total_tax"`
+	TotalWithholding float64    `json:"total_withholding"`
+	GrandTotal       float64    `json:"grand_total"`
+}
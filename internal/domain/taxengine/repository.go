@@ -0,0 +1,16 @@
+package taxengine
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Repository interface {
+	// Calculate hanya menghitung (preview), tidak menyimpan apa pun
+	Calculate(ctx context.Context, req *Request) (*Breakdown, error)
+	// Apply dipanggil repository dokumen di dalam transaksi create, hasilnya
+	// disimpan ke tbldoctaxdtl untuk dibaca ulang oleh Breakdowns
+	Apply(ctx context.Context, tx *sqlx.Tx, docType, docNo string, req *Request) (*Breakdown, error)
+	Breakdowns(ctx context.Context, docType string, docNos []string) (map[string][]LineTax, error)
+}
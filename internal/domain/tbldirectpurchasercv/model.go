@@ -3,6 +3,7 @@ package tbldirectpurchasercv
 import (
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
 	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+	"gitlab.com/ayaka/internal/domain/taxengine"
 )
 
 type Detail struct {
@@ -16,6 +17,8 @@ type Detail struct {
 	Source  string                    `db:"Source" json:"source"`
 	Price   float32                   `db:"Price" json:"price" validate:"min=0"`
 	Qty     float32                   `db:"Qty" json:"quantity" validate:"min=0"`
+	// kosong berarti pakai TaxCode header atau tax group vendor
+	TaxCodes []string `json:"tax_codes" validate:"max=5,dive,incolumn=tbltax->TaxCode"`
 }
 
 type Create struct {
@@ -28,11 +31,14 @@ type Create struct {
 	TermOfPayment string                    `json:"term_of_payment" validate:"required"`
 	CurCode       string                    `json:"currency_code" validate:"required"`
 	TaxCode       nulldatatype.NullDataType                    `json:"tax_code" validate:"incolumn=tbltax->TaxCode"`
+	TaxInclusive  booldatatype.BoolDataType `json:"tax_inclusive"`
 	GrandTotal    float32                   `json:"grand_total"`
 	Remark        nulldatatype.NullDataType `json:"remark"`
 	CreateBy      string
 	CreateDt      string
+	BaseCurrency  string   `json:"-"`
 	Details       []Detail `json:"details" validate:"dive"`
+	TaxBreakdown  *taxengine.Breakdown `json:"tax_breakdown"`
 }
 
 type Read struct {
@@ -49,9 +55,11 @@ type Read struct {
 	TermOfPayment string                    `db:"TermOfPayment" json:"term_of_payment"`
 	CurCode       string                    `db:"CurCode" json:"currency_code"`
 	TaxCode       nulldatatype.NullDataType                    `db:"TaxCode" json:"tax_code"`
+	TaxInclusive  booldatatype.BoolDataType `db:"TaxInclusiveInd" json:"tax_inclusive"`
 	TotalTax      float32                   `json:"total_tax"`
 	TotalQuantity float32 `json:"total_quantity"`
 	GrandTotal    float32                   `json:"grand_total"`
 	Remark        nulldatatype.NullDataType `db:"Remark" json:"remark"`
 	Details       []Detail                  `json:"details"`
+	TaxBreakdown  *taxengine.Breakdown      `json:"tax_breakdown"`
 }
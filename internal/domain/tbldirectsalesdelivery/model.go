@@ -3,6 +3,7 @@ package tbldirectsalesdelivery
 import (
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
 	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+	"gitlab.com/ayaka/internal/domain/taxengine"
 )
 
 type Detail struct {
@@ -17,6 +18,11 @@ type Detail struct {
 	Stock   float32                   `db:"Stock" json:"stock"`
 	Qty     float32                   `db:"Qty" json:"quantity"`
 	Price   float32                   `db:"Price" json:"price"`
+	// baris sales order yang dipenuhi, kosong untuk penjualan langsung
+	SalesOrderDocNo nulldatatype.NullDataType `db:"SalesOrderDocNo" json:"sales_order_number"`
+	SalesOrderDNo   nulldatatype.NullDataType `db:"SalesOrderDNo" json:"sales_order_detail_number"`
+	// kosong berarti pakai TaxCode header atau tax group customer
+	TaxCodes []string `json:"tax_codes" validate:"max=5,dive,incolumn=tbltax->TaxCode"`
 }
 
 type Read struct {
@@ -25,6 +31,7 @@ type Read struct {
 	Date          string                    `db:"DocDt" json:"document_date"`
 	WhsCode       string                    `db:"WhsCode" json:"warehouse_code"`
 	WhsName       string                    `db:"WhsName" json:"warehouse_name"`
+	CustomerCode  nulldatatype.NullDataType `db:"CustCode" json:"customer_code"`
 	Customer      string                    `db:"CustomerName" json:"customer_name"`
 	Address       nulldatatype.NullDataType `db:"Address" json:"address"`
 	CityCode      nulldatatype.NullDataType `db:"CityCode" json:"city_code"`
@@ -32,31 +39,41 @@ type Read struct {
 	Phone         nulldatatype.NullDataType `db:"Phone" json:"phone"`
 	Email         nulldatatype.NullDataType `db:"Email" json:"email"`
 	Mobile        nulldatatype.NullDataType `db:"Mobile" json:"mobile"`
+	NPWP          nulldatatype.NullDataType `db:"NPWP" json:"npwp"`
 	TaxCode       nulldatatype.NullDataType `db:"TaxCode" json:"tax_code"`
 	TaxRate       float32                   `db:"TaxRate" json:"tax_rate"`
+	TaxInclusive  booldatatype.BoolDataType `db:"TaxInclusiveInd" json:"tax_inclusive"`
+	TaxInvoiceNo  nulldatatype.NullDataType `db:"TaxInvoiceNo" json:"tax_invoice_number"`
 	TotalAmount   float32                   `json:"total_amount"`
 	TotalQuantity float32                   `json:"total_quantity"`
 	Remark        nulldatatype.NullDataType `db:"Remark" json:"remark"`
 	TblDate       string                    `json:"table_date"`
 	Details       []Detail                  `db:"Detail" json:"details"`
+	TaxBreakdown  *taxengine.Breakdown      `json:"tax_breakdown"`
 }
 
 type Create struct {
-	DocNo      string                    `db:"DocNo" json:"document_number" validate:"incolumn=tbldirectsalesdelivhdr->DocNo"`
-	Date       string                    `db:"DocDt" json:"document_date" validate:"required"`
-	WhsCode    string                    `db:"WhsCode" json:"warehouse_code" validate:"required"`
-	WhsName    string                    `db:"WhsName" json:"warehouse_name"`
-	Customer   string                    `db:"Customer" json:"customer_name" validate:"required"`
-	Address    nulldatatype.NullDataType `db:"Address" json:"address"`
-	CityCode   nulldatatype.NullDataType `db:"CityCode" json:"city_code"`
-	PostalCode nulldatatype.NullDataType `db:"PostalCode" json:"postal_code"`
-	Phone      nulldatatype.NullDataType `db:"Phone" json:"phone"`
-	Email      nulldatatype.NullDataType `db:"Email" json:"email"`
-	Mobile     nulldatatype.NullDataType `db:"Mobile" json:"mobile"`
-	TaxCode    nulldatatype.NullDataType `db:"TaxCode" json:"tax_code"`
-	TaxRate    float32                   `db:"TaxRate" json:"tax_rate"`
-	Remark     nulldatatype.NullDataType `db:"Remark" json:"remark"`
-	CreateBy   string
-	CreateDt   string
-	Details    []Detail `db:"Detail" json:"details"`
+	DocNo   string `db:"DocNo" json:"document_number" validate:"incolumn=tbldirectsalesdelivhdr->DocNo"`
+	Date    string `db:"DocDt" json:"document_date" validate:"required"`
+	WhsCode string `db:"WhsCode" json:"warehouse_code" validate:"required"`
+	WhsName string `db:"WhsName" json:"warehouse_name"`
+	// kalau CustomerCode diisi, data customer diambil dari master customer
+	CustomerCode nulldatatype.NullDataType `db:"CustCode" json:"customer_code" validate:"omitempty,incolumn=tblcustomerhdr->CustCode"`
+	AddressDNo   string                    `json:"customer_address_number"`
+	Customer     string                    `db:"Customer" json:"customer_name" validate:"required_without=CustomerCode"`
+	Address      nulldatatype.NullDataType `db:"Address" json:"address"`
+	CityCode     nulldatatype.NullDataType `db:"CityCode" json:"city_code"`
+	PostalCode   nulldatatype.NullDataType `db:"PostalCode" json:"postal_code"`
+	Phone        nulldatatype.NullDataType `db:"Phone" json:"phone"`
+	Email        nulldatatype.NullDataType `db:"Email" json:"email"`
+	Mobile       nulldatatype.NullDataType `db:"Mobile" json:"mobile"`
+	NPWP         nulldatatype.NullDataType `db:"NPWP" json:"npwp" validate:"omitempty,numeric,min=15,max=16"`
+	TaxCode      nulldatatype.NullDataType `db:"TaxCode" json:"tax_code"`
+	TaxRate      float32                   `db:"TaxRate" json:"tax_rate"`
+	TaxInclusive booldatatype.BoolDataType `db:"TaxInclusiveInd" json:"tax_inclusive"`
+	Remark       nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	CreateBy     string
+	CreateDt     string
+	Details      []Detail             `db:"Detail" json:"details"`
+	TaxBreakdown *taxengine.Breakdown `json:"tax_breakdown"`
 }
@@ -1,9 +1,9 @@
 package tblhistoryofstock
 
 type Read struct {
-	Number   uint   `json:"number"`
-	ItemCode string `db:"ItCode" json:"item_code"`
-	ItemName string `db:"ItName" json:"item_name"`
-	Batch    string `db:"BatchNo" json:"batch"`
-	Source   string `db:"Source" json:"source"`
+	Number   uint   `json:"number" label:"No"`
+	ItemCode string `db:"ItCode" json:"item_code" label:"Item Code"`
+	ItemName string `db:"ItName" json:"item_name" label:"Item"`
+	Batch    string `db:"BatchNo" json:"batch" label:"Batch"`
+	Source   string `db:"Source" json:"source" label:"Source"`
 }
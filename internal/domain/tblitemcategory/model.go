@@ -23,6 +23,7 @@ type ReadTblItemCategory struct {
 	CoaPurchaseReturnDesc  nulldatatype.NullDataType `db:"AcDesc5" json:"coa_purchase_return_description"`
 	CoaConsumptionCost     nulldatatype.NullDataType `db:"AcNo6" json:"coa_consumption_cost"`
 	CoaConsumptionCostDesc nulldatatype.NullDataType `db:"AcDesc6" json:"coa_consumption_cost_description"`
+	CostMethod             string                    `db:"CostMethod" json:"cost_method"`
 }
 
 type Create struct {
@@ -35,6 +36,7 @@ type Create struct {
 	CoaSalesReturn     nulldatatype.NullDataType `db:"AcNo4" json:"coa_sales_return" validate:"omitempty,incolumn=tblcoa->AcNo" label:"COA Account(Sales Return)"`
 	CoaPurchaseReturn  nulldatatype.NullDataType `db:"AcNo5" json:"coa_purchase_return" validate:"omitempty,incolumn=tblcoa->AcNo" label:"COA Account(Purchase Return)"`
 	CoaConsumptionCost nulldatatype.NullDataType `db:"AcNo6" json:"coa_consumption_cost" validate:"omitempty,incolumn=tblcoa->AcNo" label:"COA Account(Consumption Cost)"`
+	CostMethod         string                    `db:"CostMethod" json:"cost_method" validate:"omitempty,oneof=A F" label:"Cost Method"`
 	CreateDate         string                    `db:"CreateDt" json:"create_date"`
 	CreateBy           string                    `db:"CreateBy" json:"create_by"`
 }
@@ -49,6 +51,7 @@ type Update struct {
 	CoaSalesReturn     nulldatatype.NullDataType `db:"AcNo4" json:"coa_sales_return" validate:"omitempty,incolumn=tblcoa->AcNo" label:"COA Account(Sales Return)"`
 	CoaPurchaseReturn  nulldatatype.NullDataType `db:"AcNo5" json:"coa_purchase_return" validate:"omitempty,incolumn=tblcoa->AcNo" label:"COA Account(Purchase Return)"`
 	CoaConsumptionCost nulldatatype.NullDataType `db:"AcNo6" json:"coa_consumption_cost" validate:"omitempty,incolumn=tblcoa->AcNo" label:"COA Account(Consumption Cost)"`
+	CostMethod         string                    `db:"CostMethod" json:"cost_method" validate:"omitempty,oneof=A F" label:"Cost Method"`
 	LastUpdateDate     string                    `db:"LastUpDt" json:"last_update_date"`
 	LastUpdateBy       string                    `db:"LastUpBy" json:"last_update_by"`
 }
@@ -0,0 +1,93 @@
+package tblmastercustomer
+
+import (
+	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+)
+
+type ContactCustomer struct {
+	CustomerCode string                    `db:"CustCode" json:"customer_code"`
+	DNo          string                    `db:"DNo" json:"detail_no"`
+	Name         string                    `db:"Name" json:"name" validate:"required,max=255"`
+	Number       string                    `db:"Number" json:"number" validate:"max=20"`
+	Position     nulldatatype.NullDataType `db:"Position" json:"position" validate:"max=50"`
+	Type         nulldatatype.NullDataType `db:"Type" json:"type" validate:"max=50"`
+	Active       booldatatype.BoolDataType `db:"Active" json:"active"`
+}
+
+// AddressCustomer alamat kirim customer, Name adalah label alamat
+type AddressCustomer struct {
+	CustomerCode string                    `db:"CustCode" json:"customer_code"`
+	DNo          string                    `db:"DNo" json:"detail_no"`
+	Name         string                    `db:"Name" json:"name" validate:"required,max=255"`
+	Address      nulldatatype.NullDataType `db:"Address" json:"address" validate:"max=255"`
+	CityCode     string                    `db:"CityCode" json:"city_code" validate:"required,incolumn=tblcity->CityCode"`
+	PostalCode   nulldatatype.NullDataType `db:"PostalCode" json:"postal_code" validate:"max=10"`
+	Phone        nulldatatype.NullDataType `db:"Phone" json:"phone" validate:"max=20"`
+	Active       booldatatype.BoolDataType `db:"Active" json:"active"`
+}
+
+type Read struct {
+	Number               uint                      `json:"number"`
+	CustomerCode         string                    `db:"CustCode" json:"customer_code"`
+	CustomerName         string                    `db:"CustName" json:"customer_name"`
+	CustomerCategoryName string                    `db:"CustCatName" json:"customer_category_name"`
+	Address              nulldatatype.NullDataType `db:"Address" json:"address"`
+	CityName             string                    `db:"CityName" json:"city_name"`
+	CreateDate           string                    `db:"CreateDt" json:"create_date"`
+}
+
+type Detail struct {
+	CustomerCode         string                    `db:"CustCode" json:"customer_code"`
+	CustomerName         string                    `db:"CustName" json:"customer_name"`
+	CustomerCategoryCode string                    `db:"CustCatCode" json:"customer_category_code"`
+	Address              nulldatatype.NullDataType `db:"Address" json:"address"`
+	CityCode             string                    `db:"CityCode" json:"city_code"`
+	PostalCode           nulldatatype.NullDataType `db:"PostalCode" json:"postal_code"`
+	Phone                nulldatatype.NullDataType `db:"Phone" json:"phone"`
+	Mobile               nulldatatype.NullDataType `db:"Mobile" json:"mobile"`
+	Email                nulldatatype.NullDataType `db:"Email" json:"email"`
+	Remark               nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	TaxGroupCode         nulldatatype.NullDataType `db:"TaxGroupCode" json:"tax_group_code"`
+	NPWP                 nulldatatype.NullDataType `db:"NPWP" json:"npwp"`
+	ContactCustomer      []ContactCustomer         `json:"contact_customer"`
+	AddressCustomer      []AddressCustomer         `json:"address_customer"`
+}
+
+type Create struct {
+	CustomerCode         string                    `json:"customer_code"`
+	CustomerName         string                    `json:"customer_name" validate:"required,max=255"`
+	CustomerCategoryCode string                    `json:"customer_category_code" validate:"required,incolumn=tblcustomercategory->CustCatCode"`
+	Address              nulldatatype.NullDataType `json:"address" validate:"max=255"`
+	CityCode             string                    `json:"city_code" validate:"required,incolumn=tblcity->CityCode"`
+	PostalCode           nulldatatype.NullDataType `json:"postal_code" validate:"max=10"`
+	Phone                nulldatatype.NullDataType `json:"phone" validate:"max=20"`
+	Mobile               nulldatatype.NullDataType `json:"mobile" validate:"max=20"`
+	Email                nulldatatype.NullDataType `json:"email" validate:"max=255"`
+	Remark               nulldatatype.NullDataType `json:"remark" validate:"max=255"`
+	TaxGroupCode         nulldatatype.NullDataType `json:"tax_group_code" validate:"incolumn=tbltaxgroup->TaxGroupCode"`
+	NPWP                 nulldatatype.NullDataType `json:"npwp" validate:"omitempty,numeric,min=15,max=16"`
+	ContactCustomer      []ContactCustomer         `json:"contact_customer" validate:"dive"`
+	AddressCustomer      []AddressCustomer         `json:"address_customer" validate:"dive"`
+	CreateBy             string                    `json:"create_by"`
+	CreateDate           string                    `json:"create_date"`
+}
+
+type Update struct {
+	CustomerCode         string                    `json:"customer_code" validate:"required,incolumn=tblcustomerhdr->CustCode"`
+	CustomerName         string                    `json:"customer_name" validate:"required,max=255"`
+	CustomerCategoryCode string                    `json:"customer_category_code" validate:"required,incolumn=tblcustomercategory->CustCatCode"`
+	Address              nulldatatype.NullDataType `json:"address" validate:"max=255"`
+	CityCode             string                    `json:"city_code" validate:"required,incolumn=tblcity->CityCode"`
+	PostalCode           nulldatatype.NullDataType `json:"postal_code" validate:"max=10"`
+	Phone                nulldatatype.NullDataType `json:"phone" validate:"max=20"`
+	Mobile               nulldatatype.NullDataType `json:"mobile" validate:"max=20"`
+	Email                nulldatatype.NullDataType `json:"email" validate:"max=255"`
+	Remark               nulldatatype.NullDataType `json:"remark" validate:"max=255"`
+	TaxGroupCode         nulldatatype.NullDataType `json:"tax_group_code" validate:"incolumn=tbltaxgroup->TaxGroupCode"`
+	NPWP                 nulldatatype.NullDataType `json:"npwp" validate:"omitempty,numeric,min=15,max=16"`
+	ContactCustomer      []ContactCustomer         `json:"contact_customer" validate:"dive"`
+	AddressCustomer      []AddressCustomer         `json:"address_customer" validate:"dive"`
+	LastUpdateBy         string                    `json:"last_update_by"`
+	LastUpdateDate       string                    `json:"last_update_date"`
+}
@@ -19,6 +19,8 @@ type Read struct {
 	Active        booldatatype.BoolDataType `db:"ActInd" json:"active"`
 	ItemRequest   nulldatatype.NullDataType `db:"ItemRequestDocNo" json:"item_request"`
 	UomCode       string                    `db:"PurchaseUomCode" json:"uom_code"`
+	InventoryUom  string                    `db:"InventoryUOMCode" json:"inventory_uom_code"`
+	SalesUom      string                    `db:"SalesUomCode" json:"sales_uom_code"`
 	HSCode        nulldatatype.NullDataType `db:"HSCode" json:"hs_code"`
 	Remark        nulldatatype.NullDataType `db:"Remark" json:"remark"`
 	InventoryItem booldatatype.BoolDataType `db:"InventoryItemInd" json:"inventory_item"`
@@ -33,6 +35,8 @@ type Detail struct {
 	ItemRequest   nulldatatype.NullDataType `db:"ItemRequestDocNo" json:"item_request"`
 	Uom           string                    `db:"UomName" json:"uom_name"`
 	UomCode       string                    `db:"PurchaseUomCode" json:"uom_code"`
+	InventoryUom  string                    `db:"InventoryUOMCode" json:"inventory_uom_code"`
+	SalesUom      string                    `db:"SalesUomCode" json:"sales_uom_code"`
 	HSCode        nulldatatype.NullDataType `db:"HSCode" json:"hs_code"`
 	Remark        nulldatatype.NullDataType `db:"Remark" json:"remark"`
 	InventoryItem booldatatype.BoolDataType `db:"InventoryItemInd" json:"inventory_item"`
@@ -54,6 +58,8 @@ type Create struct {
 	CreateDate    string                    `db:"CreateDt" json:"create_date"`
 	ItemRequest   nulldatatype.NullDataType `db:"ItemRequestDocNo" json:"item_request" validate:"max=30"  label:"Item Request"`
 	Uom           string                    `db:"PurchaseUomCode" json:"uom_code" validate:"required,incolumn=tbluom->UomCode" label:"Uom"`
+	InventoryUom  string                    `db:"InventoryUOMCode" json:"inventory_uom_code" validate:"omitempty,incolumn=tbluom->UomCode" label:"Inventory Uom"`
+	SalesUom      string                    `db:"SalesUomCode" json:"sales_uom_code" validate:"omitempty,incolumn=tbluom->UomCode" label:"Sales Uom"`
 	HSCode        nulldatatype.NullDataType `db:"HSCode" json:"hs_code" validate:"max=30" label:"HS Code"`
 	Remark        nulldatatype.NullDataType `db:"Remark" json:"remark" validate:"max=1000" label:"Remark"`
 	InventoryItem booldatatype.BoolDataType `db:"InventoryItemInd" json:"inventory_item"`
@@ -75,6 +81,8 @@ type Update struct {
 	Active         booldatatype.BoolDataType `db:"ActInd" json:"active"`
 	HSCode         nulldatatype.NullDataType `db:"HSCode" json:"hs_code" validate:"max=30" label:"HS Code"`
 	Remark         nulldatatype.NullDataType `db:"Remark" json:"remark" validate:"max=1000" label:"Remark"`
+	InventoryUom   string                    `db:"InventoryUOMCode" json:"inventory_uom_code" validate:"omitempty,incolumn=tbluom->UomCode" label:"Inventory Uom"`
+	SalesUom       string                    `db:"SalesUomCode" json:"sales_uom_code" validate:"omitempty,incolumn=tbluom->UomCode" label:"Sales Uom"`
 	InventoryItem  booldatatype.BoolDataType `db:"InventoryItemInd" json:"inventory_item"`
 	SalesItem      booldatatype.BoolDataType `db:"SalesItemInd" json:"sales_item"`
 	PurchaseItem   booldatatype.BoolDataType `db:"PurchaseItemInd" json:"purchase_item"`
@@ -63,6 +63,8 @@ type Detail struct {
 	Mobile             nulldatatype.NullDataType  `db:"Mobile" json:"mobile"`
 	Email              nulldatatype.NullDataType  `db:"Email" json:"email"`
 	Remark             nulldatatype.NullDataType  `db:"Remark" json:"remark"`
+	TaxGroupCode       nulldatatype.NullDataType  `db:"TaxGroupCode" json:"tax_group_code"`
+	NPWP               nulldatatype.NullDataType  `db:"NPWP" json:"npwp"`
 	ContactVendor      []ContactVendorDetail      `json:"contact_vendor"`
 	ItemCategoryVendor []ItemCategoryVendorDetail `json:"item_category_vendor"`
 	SectorVendor       []SectorVendorDetail       `json:"sector_vendor"`
@@ -113,6 +115,8 @@ type Create struct {
 	Mobile             nulldatatype.NullDataType `json:"mobile" validate:"max=20"`
 	Email              nulldatatype.NullDataType `json:"email" validate:"max=255"`
 	Remark             nulldatatype.NullDataType `json:"remark" validate:"max=255"`
+	TaxGroupCode       nulldatatype.NullDataType `json:"tax_group_code" validate:"incolumn=tbltaxgroup->TaxGroupCode"`
+	NPWP               nulldatatype.NullDataType `json:"npwp" validate:"omitempty,numeric,min=15,max=16"`
 	ContactVendor      []ContactVendor           `json:"contact_vendor" validate:"dive"`
 	ItemCategoryVendor []ItemCategoryVendor      `json:"item_category_vendor" validate:"dive"`
 	SectorVendor       []SectorVendor            `json:"sector_vendor" validate:"dive"`
@@ -134,6 +138,8 @@ type Update struct {
 	Mobile             nulldatatype.NullDataType `json:"mobile" validate:"max=20"`
 	Email              nulldatatype.NullDataType `json:"email" validate:"max=255"`
 	Remark             nulldatatype.NullDataType `json:"remark" validate:"max=255"`
+	TaxGroupCode       nulldatatype.NullDataType `json:"tax_group_code" validate:"incolumn=tbltaxgroup->TaxGroupCode"`
+	NPWP               nulldatatype.NullDataType `json:"npwp" validate:"omitempty,numeric,min=15,max=16"`
 	ContactVendor      []ContactVendor           `json:"contact_vendor" validate:"dive"`
 	ItemCategoryVendor []ItemCategoryVendor      `json:"item_category_vendor" validate:"dive"`
 	SectorVendor       []SectorVendor            `json:"sector_vendor" validate:"dive"`
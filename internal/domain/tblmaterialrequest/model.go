@@ -34,14 +34,15 @@ type Create struct {
 }
 
 type Read struct {
-	Number     uint                      `json:"number"`
-	DocNo      string                    `db:"DocNo" json:"document_number"`
-	Date       string                    `db:"DocDt" json:"date"`
-	TblDate    string                    `json:"table_date"`
-	SiteCode   nulldatatype.NullDataType `db:"SiteCode" json:"site_code"`
-	Department string                    `db:"Department" json:"department"`
-	Remark     nulldatatype.NullDataType `db:"Remark" json:"remark"`
-	Details    []Detail                  `json:"details"`
+	Number         uint                      `json:"number"`
+	DocNo          string                    `db:"DocNo" json:"document_number"`
+	Date           string                    `db:"DocDt" json:"date"`
+	TblDate        string                    `json:"table_date"`
+	SiteCode       nulldatatype.NullDataType `db:"SiteCode" json:"site_code"`
+	Department     string                    `db:"Department" json:"department"`
+	ApprovalStatus string                    `db:"ApprovalStatus" json:"approval_status"`
+	Remark         nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	Details        []Detail                  `json:"details"`
 }
 
 type GetMaterialRequest struct {
@@ -57,13 +58,13 @@ type GetMaterialRequest struct {
 }
 
 type OutstandingMaterial struct {
-	Number         uint    `json:"number"`
-	DocNo          string  `db:"DocNo" json:"document_number"`
-	Department     string  `db:"Department" json:"department"`
-	ItName         string  `db:"ItName" json:"item_name"`
-	RequestedQty   float32 `db:"RequestedQty" json:"requested_quantity"`
-	ReceivedQty    float32 `db:"ReceivedQty" json:"received_quantity"`
-	OutstandingQty float32 `db:"OutstandingQty" json:"outstanding_quantity"`
-	UomName        string  `db:"UomName" json:"uom_name"`
-	UsageDate      string  `db:"UsageDt" json:"usage_date"`
+	Number         uint    `json:"number" label:"No"`
+	DocNo          string  `db:"DocNo" json:"document_number" label:"Document"`
+	Department     string  `db:"Department" json:"department" label:"Department"`
+	ItName         string  `db:"ItName" json:"item_name" label:"Item"`
+	RequestedQty   float32 `db:"RequestedQty" json:"requested_quantity" label:"Requested"`
+	ReceivedQty    float32 `db:"ReceivedQty" json:"received_quantity" label:"Received"`
+	OutstandingQty float32 `db:"OutstandingQty" json:"outstanding_quantity" label:"Outstanding"`
+	UomName        string  `db:"UomName" json:"uom_name" label:"UoM"`
+	UsageDate      string  `db:"UsageDt" json:"usage_date" label:"Usage Date"`
 }
@@ -6,17 +6,19 @@ import (
 )
 
 type Detail struct {
-	DocNo     string                    `db:"DocNo" json:"document_number"`
-	DNo       string                    `db:"DNo" json:"detail_number"`
-	ItCode    string                    `db:"ItCode" json:"item_code" validate:"required"`
-	ItName    string                    `db:"ItName" json:"item_name"`
-	BatchNo   string                    `db:"BatchNo" json:"batch"`
-	Stock     float32                   `db:"Stock" json:"stock"`
-	Qty       float32                   `db:"Qty" json:"quantity"`
-	UomName   string                    `db:"UomName" json:"uom_name"`
-	CancelInd booldatatype.BoolDataType `db:"CancelInd" json:"cancel"`
-	SuccesInd booldatatype.BoolDataType `db:"SuccessInd" json:"success"`
-	Remark    nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	DocNo        string                    `db:"DocNo" json:"document_number"`
+	DNo          string                    `db:"DNo" json:"detail_number"`
+	ItCode       string                    `db:"ItCode" json:"item_code" validate:"required"`
+	ItName       string                    `db:"ItName" json:"item_name"`
+	BatchNo      string                    `db:"BatchNo" json:"batch"`
+	Source       string                    `db:"Source" json:"source"`
+	Stock        float32                   `db:"Stock" json:"stock"`
+	Qty          float32                   `db:"Qty" json:"quantity"`
+	QtyInTransit float32                   `db:"QtyInTransit" json:"qty_in_transit"`
+	UomName      string                    `db:"UomName" json:"uom_name"`
+	CancelInd    booldatatype.BoolDataType `db:"CancelInd" json:"cancel"`
+	SuccesInd    booldatatype.BoolDataType `db:"SuccessInd" json:"success"`
+	Remark       nulldatatype.NullDataType `db:"Remark" json:"remark"`
 }
 
 type Create struct {
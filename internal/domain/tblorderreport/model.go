@@ -1,10 +1,18 @@
 package tblorderreport
 
+import "gitlab.com/ayaka/internal/domain/exchangerate"
+
+// OrderReportByVendor nilai order dalam base currency, Amounts adalah nilai
+// aslinya per mata uang dokumen
 type OrderReportByVendor struct {
-	Number                uint    `json:"number"`
-	VendorName            string  `db:"VendorName" json:"vendor_name"`
-	AveragePrice          float32 `db:"AveragePrice" json:"average_price"`
-	TotalOrderFrequency   float32 `db:"OrderFreq" json:"total_order_frequency"`
-	TotalOrderAmount      float32 `db:"TotalOrderAmount" json:"total_order_amount"`
-	OrderAmountPercentage float32 `db:"TotalOrderPercent" json:"total_order_percentage"`
+	Number                uint                  `json:"number" label:"No"`
+	VendorCode            string                `db:"VendorCode" json:"vendor_code" label:"Vendor Code"`
+	VendorName            string                `db:"VendorName" json:"vendor_name" label:"Vendor"`
+	AveragePrice          float32               `db:"AveragePrice" json:"average_price" label:"Average Price"`
+	TotalOrderFrequency   float32               `db:"OrderFreq" json:"total_order_frequency" label:"Order Frequency"`
+	TotalOrderAmount      float32               `db:"TotalOrderAmount" json:"total_order_amount" label:"Order Amount"`
+	OrderAmountPercentage float32               `db:"TotalOrderPercent" json:"total_order_percentage" label:"Order Amount (%)"`
+	RateMissing           bool                  `db:"RateMissing" json:"rate_missing" label:"Rate Missing"`
+	BaseCurrency          string                `json:"base_currency" label:"Base Currency"`
+	Amounts               []exchangerate.Amount `json:"amounts"`
 }
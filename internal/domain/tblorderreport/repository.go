@@ -7,5 +7,5 @@ import (
 )
 
 type Repository interface {
-	ByVendor(ctx context.Context, date string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
-}
\ No newline at end of file
+	ByVendor(ctx context.Context, date, base string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+}
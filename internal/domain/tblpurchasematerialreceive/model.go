@@ -47,19 +47,21 @@ type Create struct {
 	Details    []Detail                  `json:"details"`
 	CreateBy   string                    `db:"CreateBy" json:"created_by"`
 	CreateDt   string                    `db:"CreateDt" json:"created_date"`
+	// mata uang harga pokok di ledger, diisi service dari konfigurasi
+	BaseCurrency string `json:"-"`
 }
 
 type Reporting struct {
-	Number         uint                      `json:"number"`
-	DocNo          string                    `db:"DocNo" json:"document_number"`
-	Date           string                    `db:"DocDt" json:"date"`
-	WhsName        string                    `db:"WhsName" json:"warehouse_name"`
-	PODoc          string                    `db:"PODoc" json:"purchase_document"`
-	VendorName     string                    `db:"VendorName" json:"vendor_name"`
-	ItName         string                    `db:"ItName" json:"item_name"`
-	BatchNo        string                    `db:"BatchNo" json:"batch"`
-	Quantity       float32                   `db:"Qty" json:"quantity"`
-	UomName        string                    `db:"UomName" json:"uom_name"`
-	DocumentRemark nulldatatype.NullDataType `db:"DocumentRemark" json:"document_remark"`
-	ItemRemark     nulldatatype.NullDataType `db:"ItemRemark" json:"item_remark"`
+	Number         uint                      `json:"number" label:"No"`
+	DocNo          string                    `db:"DocNo" json:"document_number" label:"Document"`
+	Date           string                    `db:"DocDt" json:"date" label:"Date"`
+	WhsName        string                    `db:"WhsName" json:"warehouse_name" label:"Warehouse"`
+	PODoc          string                    `db:"PODoc" json:"purchase_document" label:"Purchase Order"`
+	VendorName     string                    `db:"VendorName" json:"vendor_name" label:"Vendor"`
+	ItName         string                    `db:"ItName" json:"item_name" label:"Item"`
+	BatchNo        string                    `db:"BatchNo" json:"batch" label:"Batch"`
+	Quantity       float32                   `db:"Qty" json:"quantity" label:"Quantity"`
+	UomName        string                    `db:"UomName" json:"uom_name" label:"UoM"`
+	DocumentRemark nulldatatype.NullDataType `db:"DocumentRemark" json:"document_remark" label:"Document Remark"`
+	ItemRemark     nulldatatype.NullDataType `db:"ItemRemark" json:"item_remark" label:"Item Remark"`
 }
@@ -3,6 +3,7 @@ package tblpurchaseorder
 import (
 	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
 	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+	"gitlab.com/ayaka/internal/domain/taxengine"
 )
 
 type Detail struct {
@@ -20,6 +21,8 @@ type Detail struct {
 	Total                 float32                   `db:"Total" json:"total"`
 	DeliveryType          nulldatatype.NullDataType `db:"DeliveryType" json:"delivery_type"`
 	Remark                nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	// kosong berarti pakai TaxCode header atau tax group vendor
+	TaxCodes []string `json:"tax_codes" validate:"max=5,dive,incolumn=tbltax->TaxCode"`
 }
 
 type Create struct {
@@ -32,9 +35,11 @@ type Create struct {
 	Remark           nulldatatype.NullDataType `db:"Remark" json:"remark"`
 	TaxCode          nulldatatype.NullDataType `db:"TaxCode" json:"tax_code"`
 	TaxRate          float32                   `db:"TaxRate" json:"tax_rate"`
+	TaxInclusive     booldatatype.BoolDataType `db:"TaxInclusiveInd" json:"tax_inclusive"`
 	CreateBy         string                    `json:"create_by"`
 	CreateDt         string                    `db:"create_date"`
 	Details          []Detail                  `json:"details" validate:"dive"`
+	TaxBreakdown     *taxengine.Breakdown      `json:"tax_breakdown"`
 }
 
 type Read struct {
@@ -43,15 +48,18 @@ type Read struct {
 	Date             string                    `db:"DocDt" json:"date"`
 	TblDate          string                    `json:"table_date"`
 	Status           string                    `db:"Status" json:"status"`
+	ApprovalStatus   string                    `db:"ApprovalStatus" json:"approval_status"`
 	VendorCode       string                    `db:"VendorCode" json:"vendor_code"`
 	VendorName       string                    `db:"VendorName" json:"vendor_name"`
 	ContactPersonDNo string                    `db:"DNo" json:"detail_number_contact_vendor"`
 	Remark           nulldatatype.NullDataType `db:"Remark" json:"remark"`
 	TaxCode          nulldatatype.NullDataType `db:"TaxCode" json:"tax_code"`
 	TaxRate          float32                   `db:"TaxRate" json:"tax_rate"`
+	TaxInclusive     booldatatype.BoolDataType `db:"TaxInclusiveInd" json:"tax_inclusive"`
 	TotalTax         float32                   `json:"total_tax"`
 	GrandTotal       float32                   `json:"grand_total"`
 	Details          []Detail                  `json:"details"`
+	TaxBreakdown     *taxengine.Breakdown      `json:"tax_breakdown"`
 }
 
 type GetPurchaseOrder struct {
@@ -66,16 +74,22 @@ type GetPurchaseOrder struct {
 }
 
 type OutstandingPO struct {
-	Number         uint    `json:"number"`
-	DocNo          string  `db:"DocNo" json:"document_number"`
-	Status         string  `db:"Status" json:"status"`
-	VendorName     string  `db:"VendorName" json:"vendor_name"`
-	Department     string  `db:"Department" json:"department"`
-	ItName         string  `db:"ItName" json:"item_name"`
-	PurchaseQty    float32 `db:"PurchaseQty" json:"purchase_quantity"`
-	OutstandingQty float32 `db:"OutstandingQty" json:"outstanding_quantity"`
-	UomName        string  `db:"UomName" json:"uom_name"`
-	CurName        string  `db:"CurName" json:"currency_name"`
-	Price          float32 `db:"Price" json:"price"`
-	Total          float32 `db:"Total" json:"total"`
+	Number         uint    `json:"number" label:"No"`
+	DocNo          string  `db:"DocNo" json:"document_number" label:"Document"`
+	Status         string  `db:"Status" json:"status" label:"Status"`
+	VendorName     string  `db:"VendorName" json:"vendor_name" label:"Vendor"`
+	Department     string  `db:"Department" json:"department" label:"Department"`
+	ItName         string  `db:"ItName" json:"item_name" label:"Item"`
+	PurchaseQty    float32 `db:"PurchaseQty" json:"purchase_quantity" label:"Purchase Qty"`
+	OutstandingQty float32 `db:"OutstandingQty" json:"outstanding_quantity" label:"Outstanding"`
+	UomName        string  `db:"UomName" json:"uom_name" label:"UoM"`
+	CurName        string  `db:"CurName" json:"currency_name" label:"Currency"`
+	Price          float32 `db:"Price" json:"price" label:"Price"`
+	Total          float32 `db:"Total" json:"total" label:"Total"`
+	// Rate 0 berarti belum ada kurs pada tanggal PO
+	CurCode      string  `db:"CurCode" json:"currency_code" label:"Currency Code"`
+	Rate         float64 `db:"Rate" json:"rate" label:"Rate"`
+	RateMissing  bool    `json:"rate_missing" label:"Rate Missing"`
+	BaseCurrency string  `json:"base_currency" label:"Base Currency"`
+	BaseTotal    float64 `json:"base_total" label:"Base Total"`
 }
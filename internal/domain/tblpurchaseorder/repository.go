@@ -11,5 +11,6 @@ type Repository interface {
 	Fetch(ctx context.Context, doc, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
 	Update(ctx context.Context, lastUpby, lastUpDate string, data *Read) (*Read, error)
 	GetPurchaseOrder(ctx context.Context, doc, vendor, item string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
-	OutstandingPO(ctx context.Context, doc, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	// OutstandingPO nilai tiap baris juga dikonversi ke base pada tanggal PO
+	OutstandingPO(ctx context.Context, doc, startDate, endDate, base string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
 }
\ No newline at end of file
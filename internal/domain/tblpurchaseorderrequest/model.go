@@ -41,12 +41,13 @@ type Create struct {
 }
 
 type Read struct {
-	Number  uint                      `json:"number"`
-	DocNo   string                    `db:"DocNo" json:"document_number"`
-	Date    string                    `db:"DocDt" json:"date"`
-	TblDate string                    `json:"table_date"`
-	Remark  nulldatatype.NullDataType `db:"Remark" json:"remark"`
-	Details []Detail                  `db:"Detail" json:"details"`
+	Number         uint                      `json:"number"`
+	DocNo          string                    `db:"DocNo" json:"document_number"`
+	Date           string                    `db:"DocDt" json:"date"`
+	TblDate        string                    `json:"table_date"`
+	ApprovalStatus string                    `db:"ApprovalStatus" json:"approval_status"`
+	Remark         nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	Details        []Detail                  `db:"Detail" json:"details"`
 }
 
 type GetPurchaseOrderRequest struct {
@@ -0,0 +1,61 @@
+package tblsalesorder
+
+import (
+	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+)
+
+type Detail struct {
+	DocNo        string                    `db:"DocNo" json:"document_number"`
+	DNo          string                    `db:"DNo" json:"detail_number"`
+	Cancel       booldatatype.BoolDataType `db:"CancelInd" json:"cancel"`
+	ItCode       string                    `db:"ItCode" json:"item_code" validate:"required,incolumn=tblitem->ItCode"`
+	ItName       string                    `db:"ItName" json:"item_name"`
+	UomName      string                    `db:"UomName" json:"uom_name"`
+	Qty          float32                   `db:"Qty" json:"quantity" validate:"gt=0"`
+	Price        float32                   `db:"Price" json:"price" validate:"min=0"`
+	DeliveredQty float32                   `db:"DeliveredQty" json:"delivered_quantity"`
+	Remark       nulldatatype.NullDataType `db:"Remark" json:"remark"`
+}
+
+type Read struct {
+	Number       uint                      `json:"number"`
+	DocNo        string                    `db:"DocNo" json:"document_number"`
+	Date         string                    `db:"DocDt" json:"document_date"`
+	TblDate      string                    `json:"table_date"`
+	CustomerCode string                    `db:"CustCode" json:"customer_code"`
+	CustomerName string                    `db:"CustName" json:"customer_name"`
+	TaxCode      nulldatatype.NullDataType `db:"TaxCode" json:"tax_code"`
+	TotalAmount  float32                   `json:"total_amount"`
+	Remark       nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	Details      []Detail                  `json:"details"`
+}
+
+type Create struct {
+	DocNo        string                    `json:"document_number"`
+	Date         string                    `json:"document_date" validate:"required"`
+	CustomerCode string                    `json:"customer_code" validate:"required,incolumn=tblcustomerhdr->CustCode"`
+	TaxCode      nulldatatype.NullDataType `json:"tax_code"`
+	Remark       nulldatatype.NullDataType `json:"remark"`
+	CreateBy     string                    `json:"create_by"`
+	CreateDt     string                    `json:"create_date"`
+	Details      []Detail                  `json:"details" validate:"required,min=1,dive"`
+}
+
+// Outstanding baris sales order yang belum terkirim penuh
+type Outstanding struct {
+	Number         uint    `json:"number" label:"No"`
+	DocNo          string  `db:"DocNo" json:"document_number" label:"Document"`
+	DNo            string  `db:"DNo" json:"detail_number" label:"Detail"`
+	Date           string  `db:"DocDt" json:"document_date" label:"Date"`
+	CustomerCode   string  `db:"CustCode" json:"customer_code" label:"Customer Code"`
+	CustomerName   string  `db:"CustName" json:"customer_name" label:"Customer"`
+	ItCode         string  `db:"ItCode" json:"item_code" label:"Item Code"`
+	ItName         string  `db:"ItName" json:"item_name" label:"Item"`
+	OrderQty       float32 `db:"OrderQty" json:"order_quantity" label:"Ordered"`
+	DeliveredQty   float32 `db:"DeliveredQty" json:"delivered_quantity" label:"Delivered"`
+	OutstandingQty float32 `db:"OutstandingQty" json:"outstanding_quantity" label:"Outstanding"`
+	UomName        string  `db:"UomName" json:"uom_name" label:"UoM"`
+	Price          float32 `db:"Price" json:"price" label:"Price"`
+	Total          float32 `db:"Total" json:"total" label:"Total"`
+}
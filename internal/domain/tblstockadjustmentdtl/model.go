@@ -19,7 +19,8 @@ type Create struct {
 	DNo         string  `db:"DNo" json:"d_no"`
 	ItemCode    string  `db:"ItCode" json:"item_code" validate:"incolumn=tblitem->ItCode"`
 	Batch       string  `db:"BatchNo" json:"batch"`
+	Bin         string  `db:"Bin" json:"-"`
 	StockSystem float32 `db:"Qty" json:"stock_system"`
 	StockActual float32 `db:"QtyActual" json:"stock_actual" validate:"min=1" label:"Actual Stock"`
-	Source      string  `db:"Source"`
+	Source      string  `db:"Source" json:"-"`
 }
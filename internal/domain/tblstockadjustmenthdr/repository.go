@@ -3,6 +3,7 @@ package tblstockadjustmenthdr
 import (
 	"context"
 
+	"github.com/jmoiron/sqlx"
 	"gitlab.com/ayaka/internal/pkg/pagination"
 )
 
@@ -10,4 +11,5 @@ type Repository interface {
 	Fetch(ctx context.Context, doc, warehouse, startDate, endDate string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
 	Detail(ctx context.Context, docNo string) (*Detail, error)
 	Create(ctx context.Context, data *Create) (*Create, error)
+	CreateTx(ctx context.Context, tx *sqlx.Tx, data *Create) (*Create, error)
 }
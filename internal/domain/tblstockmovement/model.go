@@ -14,20 +14,20 @@ type GetItem struct {
 }
 
 type Fetch struct {
-	Number           uint                      `json:"number"`
-	DocType          string                    `db:"DocType" json:"doc_type"`
-	FromTo           nulldatatype.NullDataType `db:"FromTo" json:"from_to,omitempty"`
-	DocNo            string                    `db:"DocNo" json:"doc_no"`
-	Source           string                    `db:"Source" json:"source"`
-	DocDt            string                    `db:"DocDt" json:"doc_date"`
-	WhsName          string                    `db:"WhsName" json:"warehouse_name"`
-	ItCode           string                    `db:"ItCode" json:"item_code"`
-	ItName           string                    `db:"ItName" json:"item_name"`
-	Specification    nulldatatype.NullDataType `db:"Specification" json:"item_specification"`
-	UomName          string                    `db:"UomName" json:"uom_name"`
-	BatchNo          string                    `db:"BatchNo" json:"batch_no"`
-	Qty              float32                   `db:"Qty" json:"quantity"`
-	Remark           nulldatatype.NullDataType `db:"Remark" json:"remark"`
-	CreateBy         string                    `db:"CreateBy" json:"created_by"`
-	CreateDt         string                    `db:"CreateDt" json:"created_date"`
+	Number           uint                      `json:"number" label:"No"`
+	DocType          string                    `db:"DocType" json:"doc_type" label:"Document Type"`
+	FromTo           nulldatatype.NullDataType `db:"FromTo" json:"from_to,omitempty" label:"From/To"`
+	DocNo            string                    `db:"DocNo" json:"doc_no" label:"Document"`
+	Source           string                    `db:"Source" json:"source" label:"Source"`
+	DocDt            string                    `db:"DocDt" json:"doc_date" label:"Date"`
+	WhsName          string                    `db:"WhsName" json:"warehouse_name" label:"Warehouse"`
+	ItCode           string                    `db:"ItCode" json:"item_code" label:"Item Code"`
+	ItName           string                    `db:"ItName" json:"item_name" label:"Item"`
+	Specification    nulldatatype.NullDataType `db:"Specification" json:"item_specification" label:"Specification"`
+	UomName          string                    `db:"UomName" json:"uom_name" label:"UoM"`
+	BatchNo          string                    `db:"BatchNo" json:"batch_no" label:"Batch"`
+	Qty              float32                   `db:"Qty" json:"quantity" label:"Quantity"`
+	Remark           nulldatatype.NullDataType `db:"Remark" json:"remark" label:"Remark"`
+	CreateBy         string                    `db:"CreateBy" json:"created_by" label:"Created By"`
+	CreateDt         string                    `db:"CreateDt" json:"created_date" label:"Created Date"`
 }
@@ -5,23 +5,26 @@ import (
 	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
 )
 
+// GetItem diurutkan FEFO, ExpiredDate kosong kalau batch belum ada di master batch
 type GetItem struct {
-	Number   uint    `json:"number"`
-	ItemCode string  `db:"ItCode" json:"item_code"`
-	ItemName string  `db:"ItName" json:"item_name"`
-	Batch    string  `db:"BatchNo" json:"batch"`
-	Stock    float32 `db:"Stock" json:"stock"`
-	UomName  string  `db:"UomName" json:"uom_name"`
+	Number      uint                      `json:"number"`
+	ItemCode    string                    `db:"ItCode" json:"item_code"`
+	ItemName    string                    `db:"ItName" json:"item_name"`
+	Batch       string                    `db:"BatchNo" json:"batch"`
+	ExpiredDate nulldatatype.NullDataType `db:"ExpDt" json:"expired_date"`
+	Expired     bool                      `json:"expired"`
+	Stock       float32                   `db:"Stock" json:"stock"`
+	UomName     string                    `db:"UomName" json:"uom_name"`
 }
 
 type Fetch struct {
-	Number        uint                      `json:"number"`
-	WarehouseName string                    `db:"WhsName" json:"warehouse_name"`
-	ItemCode      string                    `db:"ItCode" json:"item_code"`
-	LocalCode     nulldatatype.NullDataType `db:"ItCodeInternal" json:"local_code"`
-	ItemName      string                    `db:"ItName" json:"item_name"`
-	Catgory       string                    `db:"ItCtName" json:"item_category_name"`
-	Active        booldatatype.BoolDataType `db:"ActInd" json:"active"`
-	Quantity      float32                   `db:"Stock" json:"quantity"`
-	Uom           string                    `db:"UomName" json:"uom"`
+	Number        uint                      `json:"number" label:"No"`
+	WarehouseName string                    `db:"WhsName" json:"warehouse_name" label:"Warehouse"`
+	ItemCode      string                    `db:"ItCode" json:"item_code" label:"Item Code"`
+	LocalCode     nulldatatype.NullDataType `db:"ItCodeInternal" json:"local_code" label:"Local Code"`
+	ItemName      string                    `db:"ItName" json:"item_name" label:"Item"`
+	Catgory       string                    `db:"ItCtName" json:"item_category_name" label:"Category"`
+	Active        booldatatype.BoolDataType `db:"ActInd" json:"active" label:"Active"`
+	Quantity      float32                   `db:"Stock" json:"quantity" label:"Quantity"`
+	Uom           string                    `db:"UomName" json:"uom" label:"UoM"`
 }
@@ -1,5 +1,7 @@
 package tbltax
 
+import "gitlab.com/ayaka/internal/domain/shared/booldatatype"
+
 type Read struct {
 	Number       uint    `json:"number"`
 	TaxCode      string  `db:"TaxCode" json:"tax_code"`
@@ -8,6 +10,10 @@ type Read struct {
 	TaxGroupCode string  `db:"TaxGroupCode" json:"tax_group_code"`
 	TaxGroupName string  `db:"TaxGroupName" json:"tax_group_name"`
 	CreateDate   string  `db:"CreateDt" json:"create_date"`
+	// withholding (PPh) mengurangi tagihan, selain itu pajak ditambahkan
+	Withholding     booldatatype.BoolDataType `db:"WithholdInd" json:"withholding"`
+	RoundingMode    string                    `db:"RoundingMode" json:"rounding_mode"`
+	RoundingDecimal int                       `db:"RoundingDecimal" json:"rounding_decimal"`
 }
 
 type Create struct {
@@ -17,6 +23,10 @@ type Create struct {
 	TaxGroupCode string  `db:"TaxGroupCode" json:"tax_group_code" validate:"required,incolumn=tbltaxgroup->TaxGroupCode"`
 	CreateDate   string  `db:"CreateDt" json:"create_date"`
 	CreateBy     string  `db:"CreateBy" json:"create_by"`
+	// RoundingMode kosong dianggap HALF_UP
+	Withholding     booldatatype.BoolDataType `db:"WithholdInd" json:"withholding"`
+	RoundingMode    string                    `db:"RoundingMode" json:"rounding_mode" validate:"omitempty,oneof=HALF_UP UP DOWN"`
+	RoundingDecimal int                       `db:"RoundingDecimal" json:"rounding_decimal" validate:"min=0,max=4"`
 }
 
 type Update struct {
@@ -26,4 +36,8 @@ type Update struct {
 	TaxGroupCode   string  `db:"TaxGroupCode" json:"tax_group_code" validate:"required,incolumn=tbltaxgroup->TaxGroupCode"`
 	LastUpdateDate string  `db:"LastUpDt"`
 	LastUpdateBy   string  `db:"LastUpBy"`
+	// RoundingMode kosong dianggap HALF_UP
+	Withholding     booldatatype.BoolDataType `db:"WithholdInd" json:"withholding"`
+	RoundingMode    string                    `db:"RoundingMode" json:"rounding_mode" validate:"omitempty,oneof=HALF_UP UP DOWN"`
+	RoundingDecimal int                       `db:"RoundingDecimal" json:"rounding_decimal" validate:"min=0,max=4"`
 }
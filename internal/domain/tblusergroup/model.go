@@ -0,0 +1,58 @@
+package tblusergroup
+
+// Permission adalah satu sel pada matrix menu/action. Menu mengikuti nama
+// route group di application/api.go (mis. "purchase-order"), Action berisi
+// read, create, update, delete, export, resolve, select atau * untuk semua
+// action pada menu tersebut. Menu "*" dengan Action "*" (String "*:*") memberi
+// akses ke semua menu.
+type Permission struct {
+	Menu   string `db:"Menu" json:"menu" validate:"required,max=50" label:"Menu"`
+	Action string `db:"Action" json:"action" validate:"required,oneof=read create update delete export resolve select *" label:"Action"`
+}
+
+func (p Permission) String() string {
+	return p.Menu + ":" + p.Action
+}
+
+type Read struct {
+	Number     uint   `json:"number"`
+	GroupCode  string `db:"GrpCode" json:"group_code"`
+	GroupName  string `db:"GrpName" json:"group_name"`
+	CreateDate string `db:"CreateDt" json:"create_date"`
+}
+
+type Detail struct {
+	GroupCode   string       `db:"GrpCode" json:"group_code"`
+	GroupName   string       `db:"GrpName" json:"group_name"`
+	CreateDate  string       `db:"CreateDt" json:"create_date"`
+	Permissions []Permission `json:"permissions"`
+	Users       []User       `json:"users"`
+}
+
+type User struct {
+	UserCode string `db:"UserCode" json:"user_code"`
+	UserName string `db:"UserName" json:"user_name"`
+}
+
+type Create struct {
+	GroupCode   string       `db:"GrpCode" json:"group_code" validate:"required,unique=tblgroup->GrpCode,max=20" label:"Group Code"`
+	GroupName   string       `db:"GrpName" json:"group_name" validate:"required,max=80" label:"Group Name"`
+	Permissions []Permission `json:"permissions" validate:"dive"`
+	CreateDate  string       `db:"CreateDt" json:"create_date"`
+	CreateBy    string       `db:"CreateBy" json:"create_by"`
+}
+
+type Update struct {
+	GroupCode      string       `db:"GrpCode" json:"group_code" validate:"required,incolumn=tblgroup->GrpCode,max=20" label:"Group Code"`
+	GroupName      string       `db:"GrpName" json:"group_name" validate:"required,max=80" label:"Group Name"`
+	Permissions    []Permission `json:"permissions" validate:"dive"`
+	LastUpdateDate string       `db:"LastUpDt" json:"last_update_date"`
+	LastUpdateBy   string       `db:"LastUpBy" json:"last_update_by"`
+}
+
+type AssignUser struct {
+	GroupCode      string   `json:"group_code" validate:"required,incolumn=tblgroup->GrpCode" label:"Group Code"`
+	UserCodes      []string `json:"user_codes" validate:"required,min=1,dive,incolumn=tbluser->UserCode" label:"User Code"`
+	LastUpdateDate string   `json:"last_update_date"`
+	LastUpdateBy   string   `json:"last_update_by"`
+}
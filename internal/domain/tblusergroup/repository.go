@@ -0,0 +1,17 @@
+package tblusergroup
+
+import (
+	"context"
+
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type Repository interface {
+	Fetch(ctx context.Context, name string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Detail(ctx context.Context, code string) (*Detail, error)
+	Create(ctx context.Context, data *Create) (*Create, error)
+	Update(ctx context.Context, data *Update) (*Update, error)
+	AssignUser(ctx context.Context, data *AssignUser) (*AssignUser, error)
+	// permission user dalam format menu:action, dipakai saat login
+	PermissionsOf(ctx context.Context, userCode string) ([]string, error)
+}
@@ -0,0 +1,17 @@
+package tbluserscope
+
+// Detail berisi gudang dan site yang boleh diakses user, "*" berarti semua.
+type Detail struct {
+	UserCode   string   `json:"user_code"`
+	UserName   string   `db:"UserName" json:"user_name"`
+	Warehouses []string `json:"warehouses"`
+	Sites      []string `json:"sites"`
+}
+
+type Update struct {
+	UserCode       string   `json:"user_code" validate:"required,incolumn=tbluser->UserCode" label:"User Code"`
+	Warehouses     []string `json:"warehouses" validate:"dive,eq=*|incolumn=tblwarehouse->WhsCode" label:"Warehouse"`
+	Sites          []string `json:"sites" validate:"dive,eq=*|incolumn=tblsite->SiteCode" label:"Site"`
+	LastUpdateDate string   `json:"last_update_date"`
+	LastUpdateBy   string   `json:"last_update_by"`
+}
@@ -0,0 +1,10 @@
+package tbluserscope
+
+import (
+	"context"
+)
+
+type Repository interface {
+	Detail(ctx context.Context, userCode string) (*Detail, error)
+	Update(ctx context.Context, data *Update) (*Update, error)
+}
@@ -6,6 +6,7 @@ type Read struct {
 	Number        uint                      `json:"number"`
 	IndicatorCode string                    `db:"IndicatorCode" json:"indicator_code"`
 	Description   string                    `db:"Description" json:"description"`
+	Weight        float32                   `db:"Weight" json:"weight"`
 	Active        booldatatype.BoolDataType `db:"ActiveInd" json:"active"`
 	CreateDate    string                    `db:"CreateDt" json:"create_date"`
 }
@@ -13,6 +14,7 @@ type Read struct {
 type Create struct {
 	IndicatorCode string                    `validate:"required,whitespace,unique=tblvendorrating->IndicatorCode,max=50" json:"indicator_code"`
 	Description   string                    `validate:"required,max=255" json:"description"`
+	Weight        float32                   `validate:"min=0" json:"weight"`
 	Active        booldatatype.BoolDataType `validate:"required" json:"active"`
 	CreateDate    string                    `json:"create_date"`
 	CreateBy      string                    `json:"create_by"`
@@ -21,6 +23,7 @@ type Create struct {
 type Update struct {
 	IndicatorCode  string                    `validate:"required,whitespace,incolumn=tblvendorrating->IndicatorCode,max=50" json:"indicator_code"`
 	Description    string                    `validate:"max=255" json:"description"`
+	Weight         float32                   `validate:"min=0" json:"weight"`
 	Active         booldatatype.BoolDataType `json:"active"`
 	LastUpdateDate string                    `json:"last_update_date"`
 	LastUpdateBy   string                    `json:"last_update_by"`
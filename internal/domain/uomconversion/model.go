@@ -0,0 +1,21 @@
+package uomconversion
+
+// Kolom UoM di tblitem yang bisa dikonversi satu sama lain
+const (
+	Purchase  = "purchase"
+	Inventory = "inventory"
+	Sales     = "sales"
+)
+
+// Conversion berarti 1 UomFrom = Factor UomTo. ItemCode kosong berlaku global,
+// konversi per item menimpa yang global. Arah sebaliknya dihitung 1 / Factor.
+type Conversion struct {
+	Number     uint    `json:"number"`
+	ItemCode   string  `db:"ItCode" json:"item_code" validate:"omitempty,incolumn=tblitem->ItCode" label:"Item"`
+	ItemName   string  `db:"ItName" json:"item_name"`
+	UomFrom    string  `db:"UomFrom" json:"uom_from" validate:"required,incolumn=tbluom->UomCode" label:"Uom From"`
+	UomTo      string  `db:"UomTo" json:"uom_to" validate:"required,nefield=UomFrom,incolumn=tbluom->UomCode" label:"Uom To"`
+	Factor     float32 `db:"Factor" json:"factor" validate:"gt=0" label:"Factor"`
+	CreateBy   string  `db:"CreateBy" json:"-"`
+	CreateDate string  `db:"CreateDt" json:"-"`
+}
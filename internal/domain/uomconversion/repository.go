@@ -0,0 +1,18 @@
+package uomconversion
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type Repository interface {
+	Fetch(ctx context.Context, itemCode, uom string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Save(ctx context.Context, data *Conversion) (*Conversion, error)
+	Delete(ctx context.Context, itemCode, uomFrom, uomTo string) error
+	// ItemFactors faktor per item dari satu kolom UoM item ke kolom lain (Purchase/Inventory/Sales)
+	ItemFactors(ctx context.Context, q sqlx.QueryerContext, itemCodes []string, from, to string) (map[string]float32, error)
+	// ToUom faktor per item dari UoM inventory ke UomCode, UoM tidak dikenal atau item tanpa konversi ErrUomConversion
+	ToUom(ctx context.Context, itemCodes []string, uomCode string) (string, map[string]float32, error)
+}
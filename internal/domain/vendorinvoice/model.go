@@ -0,0 +1,77 @@
+package vendorinvoice
+
+import (
+	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+)
+
+// Status hasil three-way match per baris
+const (
+	Matched       = "matched"
+	QtyVariance   = "qty variance"
+	PriceVariance = "price variance"
+	BothVariance  = "qty and price variance"
+)
+
+// Tolerance dalam persen, qty terhadap qty diterima dan harga terhadap harga PO
+type Tolerance struct {
+	Qty   float32
+	Price float32
+}
+
+type Invoice struct {
+	Number          uint                      `json:"number"`
+	DocNo           string                    `db:"DocNo" json:"document_number"`
+	Date            string                    `db:"DocDt" json:"date" validate:"required"`
+	VendorCode      string                    `db:"VendorCode" json:"vendor_code" validate:"required,incolumn=tblvendorhdr->VendorCode" label:"Vendor"`
+	VendorName      string                    `db:"VendorName" json:"vendor_name"`
+	VendorInvoiceNo string                    `db:"VdInvNo" json:"vendor_invoice_number" validate:"required,max=40" label:"Vendor Invoice Number"`
+	CurCode         string                    `db:"CurCode" json:"currency_code" validate:"required,incolumn=tblcurrency->CurCode" label:"Currency"`
+	DueDate         string                    `db:"DueDt" json:"due_date"`
+	Amount          float32                   `db:"Amt" json:"amount"`
+	PaymentBlock    booldatatype.BoolDataType `db:"PaymentBlockInd" json:"payment_block"`
+	Remark          nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	Details         []Detail                  `json:"details,omitempty" validate:"required,min=1,dive"`
+	CreateBy        string                    `db:"CreateBy" json:"create_by"`
+	CreateDate      string                    `db:"CreateDt" json:"-"`
+}
+
+// Detail satu baris invoice yang menunjuk baris PO dan baris penerimaannya.
+// PO*, ReceivedQty dan InvoicedQty diisi dari database saat matching.
+type Detail struct {
+	DNo                string                    `db:"DNo" json:"dno"`
+	PurchaseOrderDocNo string                    `db:"PurchaseOrderDocNo" json:"purchase_order_document_number" validate:"required" label:"Purchase Order"`
+	PurchaseOrderDNo   string                    `db:"PurchaseOrderDNo" json:"purchase_order_dno" validate:"required" label:"Purchase Order DNo"`
+	ReceiveDocNo       string                    `db:"ReceiveDocNo" json:"receive_document_number" validate:"required" label:"Purchase Material Receive"`
+	ReceiveDNo         string                    `db:"ReceiveDNo" json:"receive_dno" validate:"required" label:"Purchase Material Receive DNo"`
+	ItemCode           string                    `db:"ItCode" json:"item_code"`
+	ItemName           string                    `db:"ItName" json:"item_name"`
+	Qty                float32                   `db:"Qty" json:"qty" validate:"gt=0" label:"Quantity"`
+	UnitPrice          float32                   `db:"UPrice" json:"unit_price" validate:"gte=0" label:"Unit Price"`
+	Amount             float32                   `db:"Amt" json:"amount"`
+	PoQty              float32                   `db:"PoQty" json:"po_qty"`
+	PoPrice            float32                   `db:"PoPrice" json:"po_price"`
+	ReceivedQty        float32                   `db:"ReceivedQty" json:"received_qty"`
+	InvoicedQty        float32                   `db:"InvoicedQty" json:"previously_invoiced_qty"`
+	MatchStatus        string                    `db:"MatchStatus" json:"match_status"`
+	Remark             nulldatatype.NullDataType `db:"Remark" json:"remark"`
+}
+
+// Payable satu invoice yang belum dibayar, DaysOverdue negatif berarti belum jatuh tempo
+type Payable struct {
+	VendorCode      string  `db:"VendorCode" json:"vendor_code" label:"Vendor Code"`
+	VendorName      string  `db:"VendorName" json:"vendor_name" label:"Vendor"`
+	DocNo           string  `db:"DocNo" json:"document_number" label:"Document"`
+	VendorInvoiceNo string  `db:"VdInvNo" json:"vendor_invoice_number" label:"Vendor Invoice"`
+	Date            string  `db:"DocDt" json:"date" label:"Date"`
+	DueDate         string  `db:"DueDt" json:"due_date" label:"Due Date"`
+	DaysOverdue     int     `json:"days_overdue" label:"Days Overdue"`
+	CurCode         string  `db:"CurCode" json:"currency_code" label:"Currency"`
+	Amount          float32 `db:"Amt" json:"amount" label:"Amount"`
+	Blocked         bool    `db:"Blocked" json:"payment_block" label:"Payment Block"`
+	// kurs pada tanggal invoice, 0 kalau belum ada
+	Rate         float64 `db:"Rate" json:"rate" label:"Rate"`
+	RateMissing  bool    `json:"rate_missing" label:"Rate Missing"`
+	BaseCurrency string  `json:"base_currency" label:"Base Currency"`
+	BaseAmount   float64 `json:"base_amount" label:"Base Amount"`
+}
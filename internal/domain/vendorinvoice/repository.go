@@ -0,0 +1,15 @@
+package vendorinvoice
+
+import (
+	"context"
+
+	"gitlab.com/ayaka/internal/pkg/pagination"
+)
+
+type Repository interface {
+	Fetch(ctx context.Context, doc, vendor string, param *pagination.PaginationParam) (*pagination.PaginationResponse, error)
+	Detail(ctx context.Context, docNo string) (*Invoice, error)
+	Create(ctx context.Context, data *Invoice, tolerance Tolerance) (*Invoice, error)
+	// Payables invoice terbuka dengan jatuh tempo sampai dueDate (yyyymmdd, kosong = semua)
+	Payables(ctx context.Context, vendor, dueDate, base string) ([]*Payable, error)
+}
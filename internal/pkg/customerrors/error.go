@@ -15,4 +15,16 @@ var (
 	ErrInvalidArrayFormat = errors.New("invalid array format")
 	ErrInvalidQuantity = errors.New("invalid quantity")
 	ErrInvalidInput = errors.New("invalid input")
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrWarehouseNotAllowed = errors.New("warehouse not allowed")
+	ErrSiteNotAllowed = errors.New("site not allowed")
+	ErrNotApprover = errors.New("user is not the current approver")
+	ErrInvalidApprovalStatus = errors.New("invalid approval status")
+	ErrUnsupportedFormat = errors.New("unsupported format")
+	ErrWarehouseFrozen = errors.New("warehouse is frozen for stock opname")
+	ErrBatchExpired = errors.New("batch is expired")
+	ErrUomConversion = errors.New("uom conversion not found")
+	ErrExchangeRate = errors.New("exchange rate not found")
+	ErrTaxInvoiceRange = errors.New("no tax invoice number left in range")
+	ErrJournalAccount = errors.New("journal account not mapped")
 )
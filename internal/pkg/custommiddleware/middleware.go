@@ -12,6 +12,7 @@ import (
 	"gitlab.com/ayaka/config"
 	"gitlab.com/ayaka/internal/domain/tbluser"
 	"gitlab.com/ayaka/internal/pkg/customerrors"
+	"gitlab.com/ayaka/internal/pkg/formatter"
 	jwtconfig "gitlab.com/ayaka/internal/pkg/jwt"
 )
 
@@ -85,3 +86,22 @@ func (m *MiddlewareHandler) AuthRequired() fiber.Handler {
 		})
 	}
 }
+
+// RequirePermission dipasang setelah AuthRequired, permission berformat
+// menu:action sesuai matrix di tblgroupmenu.
+func (m *MiddlewareHandler) RequirePermission(permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := c.Locals("user").(*jwtconfig.Claims)
+		if !ok {
+			go m.Log.LogUserInfo("User", "WARN", fmt.Sprintf("Forbidden access %s: no claims", permission))
+			return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have permission to access this resource", ""))
+		}
+
+		if !claims.HasPermission(permission) {
+			go m.Log.LogUserInfo(claims.UserCode, "WARN", fmt.Sprintf("Forbidden access %s", permission))
+			return c.Status(fiber.StatusForbidden).JSON(formatter.NewErrorResponse(formatter.Forbidden, "You don't have permission to access this resource", ""))
+		}
+
+		return c.Next()
+	}
+}
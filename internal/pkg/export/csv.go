@@ -0,0 +1,26 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+func writeCSV(w io.Writer, table *Table) error {
+	// BOM supaya Excel membaca file sebagai UTF-8
+	if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(table.Columns); err != nil {
+		return err
+	}
+	for _, row := range table.Rows {
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
@@ -0,0 +1,84 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+	PDF  Format = "pdf"
+)
+
+var contentType = map[Format]string{
+	CSV:  "text/csv; charset=utf-8",
+	XLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	PDF:  "application/pdf",
+}
+
+// Requested membaca format export dari query "format" atau header Accept.
+// Format kosong berarti response JSON biasa.
+func Requested(c *fiber.Ctx) (Format, error) {
+	if format := strings.ToLower(c.Query("format")); format != "" {
+		if format == "json" {
+			return "", nil
+		}
+		if _, ok := contentType[Format(format)]; !ok {
+			return "", customerrors.ErrUnsupportedFormat
+		}
+		return Format(format), nil
+	}
+
+	accept := c.Get(fiber.HeaderAccept)
+	for format, mime := range contentType {
+		if strings.Contains(accept, strings.Split(mime, ";")[0]) {
+			return format, nil
+		}
+	}
+
+	return "", nil
+}
+
+// Send menulis data (slice of struct hasil report) sebagai file attachment.
+// File dibuat penuh di memory lebih dulu sehingga error penulisan masih bisa
+// dikembalikan ke handler sebelum header attachment dikirim.
+func Send(c *fiber.Ctx, format Format, name string, data interface{}) error {
+	table, err := NewTable(data)
+	if err != nil {
+		return err
+	}
+
+	var write func(w io.Writer, table *Table) error
+	switch format {
+	case CSV:
+		write = writeCSV
+	case XLSX:
+		write = writeXLSX
+	case PDF:
+		write = func(w io.Writer, table *Table) error {
+			return writePDF(w, labelOf(strings.ReplaceAll(name, "-", "_")), table)
+		}
+	default:
+		return customerrors.ErrUnsupportedFormat
+	}
+
+	var body bytes.Buffer
+	if err := write(&body, table); err != nil {
+		return fmt.Errorf("error export %s: %w", name, err)
+	}
+
+	fileName := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102"), format)
+	c.Set(fiber.HeaderContentType, contentType[format])
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, fileName))
+
+	return c.Send(body.Bytes())
+}
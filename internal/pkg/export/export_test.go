@@ -0,0 +1,95 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+	"gitlab.com/ayaka/internal/domain/stockvaluation"
+)
+
+type reportRow struct {
+	Number    uint                      `json:"number"`
+	ItemName  string                    `db:"ItName" json:"item_name" label:"Item"`
+	Remark    nulldatatype.NullDataType `db:"Remark" json:"remark"`
+	Active    booldatatype.BoolDataType `db:"ActInd" json:"active"`
+	Qty       float32                   `db:"Qty" json:"quantity"`
+	CreateDt  string                    `db:"CreateDt" json:"created_date"`
+	Details   []string                  `json:"details"`
+	Unexposed string                    `json:"-"`
+}
+
+func rows() []*reportRow {
+	return []*reportRow{
+		{Number: 1, ItemName: "Baut, 10mm", Active: booldatatype.NewBoolDataType("Y"), Qty: 12.5, CreateDt: "202601150930"},
+		{Number: 2, ItemName: "Mur (M8)", Remark: nulldatatype.NewNullStringDataType("urgent"), Active: booldatatype.NewBoolDataType("N"), Qty: 3, CreateDt: "20260116"},
+	}
+}
+
+func TestTable(t *testing.T) {
+	table, err := NewTable(rows())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"No", "Item", "Remark", "Active", "Quantity", "Created Date"}, table.Columns)
+	assert.Equal(t, []bool{true, false, false, false, true, false}, table.Numeric)
+	assert.Equal(t, []string{"1", "Baut, 10mm", "", "Y", "12.5", "15 Jan 2026, 09:30"}, table.Rows[0])
+	assert.Equal(t, []string{"2", "Mur (M8)", "urgent", "N", "3", "16/Jan/2026"}, table.Rows[1])
+
+	_, err = NewTable("not a slice")
+	assert.Error(t, err)
+}
+
+func TestWriter(t *testing.T) {
+	table, err := NewTable(rows())
+	assert.NoError(t, err)
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, writeCSV(&buf, table))
+		assert.Equal(t, "\uFEFFNo,Item,Remark,Active,Quantity,Created Date\n1,\"Baut, 10mm\",,Y,12.5,\"15 Jan 2026, 09:30\"\n2,Mur (M8),urgent,N,3,16/Jan/2026\n", buf.String())
+	})
+
+	t.Run("xlsx", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, writeXLSX(&buf, table))
+
+		archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		assert.NoError(t, err)
+		assert.Len(t, archive.File, len(xlsxParts)+1)
+
+		sheet, err := archive.Open("xl/worksheets/sheet1.xml")
+		assert.NoError(t, err)
+		content, _ := io.ReadAll(sheet)
+		assert.Contains(t, string(content), `<c r="E2" s="0"><v>12.5</v></c>`)
+		assert.Contains(t, string(content), `<c r="B3" s="0" t="inlineStr"><is><t xml:space="preserve">Mur (M8)</t></is></c>`)
+	})
+
+	t.Run("pdf", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, writePDF(&buf, "Stock Summary", table))
+
+		content := buf.String()
+		assert.True(t, strings.HasPrefix(content, "%PDF-1.4\n"))
+		assert.Contains(t, content, `(Mur \(M8\))`)
+		assert.True(t, strings.HasSuffix(content, "%%EOF\n"))
+	})
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "BA", columnName(52))
+}
+
+func TestTable_ModelLabels(t *testing.T) {
+	table, err := NewTable([]*stockvaluation.Read{{Number: 1, WarehouseName: "Gudang Utama", Uom: "PCS"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"No", "Warehouse Code", "Warehouse", "Item Code", "Item", "Category", "Cost Method", "Quantity", "UoM", "Unit Cost", "Total Value"}, table.Columns)
+}
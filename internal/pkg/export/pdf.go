@@ -0,0 +1,107 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+)
+
+// layout report, A4 landscape
+const (
+	pdfMargin    = 30.0
+	pdfFontSize  = 7.0
+	pdfTitleSize = 11.0
+	pdfRowHeight = 11.0
+	pdfCellPad   = 2.0
+	// teks lebih dari ini tidak menambah lebar kolom
+	pdfMaxColumnChars = 40
+)
+
+// writePDF menulis tabel report, header kolom diulang di setiap halaman.
+func writePDF(w io.Writer, title string, table *Table) error {
+	doc := NewPDFWriter(A4Height, A4Width)
+	widths := pdfColumnWidths(doc.Width(), table)
+	pages := pdfPaginate(doc.Height(), table.Rows)
+
+	printed := time.Now().Format("02/01/2006 15:04")
+	for i, rows := range pages {
+		doc.AddPage()
+		pdfTablePage(doc, table, widths, rows, title)
+		doc.Text(pdfMargin, pdfMargin, pdfFontSize, false, fmt.Sprintf("Printed %s - Page %d of %d", printed, i+1, len(pages)))
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+func pdfPaginate(height float64, rows [][]string) [][][]string {
+	// judul + header kolom memakai tiga baris di atas tabel, footer satu baris
+	perPage := int((height-2*pdfMargin)/pdfRowHeight) - 4
+
+	pages := make([][][]string, 0, len(rows)/perPage+1)
+	for start := 0; start < len(rows); start += perPage {
+		end := start + perPage
+		if end > len(rows) {
+			end = len(rows)
+		}
+		pages = append(pages, rows[start:end])
+	}
+	if len(pages) == 0 {
+		pages = append(pages, nil)
+	}
+	return pages
+}
+
+// lebar kolom proporsional terhadap teks terpanjang di kolom tersebut
+func pdfColumnWidths(pageWidth float64, table *Table) []float64 {
+	chars := make([]int, len(table.Columns))
+	total := 0
+	for i, label := range table.Columns {
+		chars[i] = utf8.RuneCountInString(label)
+		for _, row := range table.Rows {
+			if n := utf8.RuneCountInString(row[i]); n > chars[i] {
+				chars[i] = n
+			}
+		}
+		if chars[i] > pdfMaxColumnChars {
+			chars[i] = pdfMaxColumnChars
+		}
+		if chars[i] < 2 {
+			chars[i] = 2
+		}
+		total += chars[i]
+	}
+
+	widths := make([]float64, len(chars))
+	for i, n := range chars {
+		widths[i] = (pageWidth - 2*pdfMargin) * float64(n) / float64(total)
+	}
+	return widths
+}
+
+func pdfTablePage(doc *PDFWriter, table *Table, widths []float64, rows [][]string, title string) {
+	y := doc.Height() - pdfMargin - pdfTitleSize
+	doc.Text(pdfMargin, y, pdfTitleSize, true, title)
+	y -= 2 * pdfRowHeight
+
+	x := pdfMargin
+	for i, label := range table.Columns {
+		doc.Text(x+pdfCellPad, y, pdfFontSize, true, FitText(label, widths[i]-2*pdfCellPad, pdfFontSize, true))
+		x += widths[i]
+	}
+	doc.Line(pdfMargin, y-3, doc.Width()-pdfMargin, y-3)
+
+	for _, row := range rows {
+		y -= pdfRowHeight
+		x = pdfMargin
+		for i, cell := range row {
+			if table.Numeric[i] {
+				doc.TextRight(x+widths[i]-pdfCellPad, y, pdfFontSize, false, cell)
+			} else {
+				doc.Text(x+pdfCellPad, y, pdfFontSize, false, FitText(cell, widths[i]-2*pdfCellPad, pdfFontSize, false))
+			}
+			x += widths[i]
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 dalam point
+const (
+	A4Width  = 595.0
+	A4Height = 842.0
+)
+
+// lebar glyph Helvetica (per 1000 unit) untuk ASCII 32..126, dari AFM standar
+var helveticaWidth = [95]float64{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// PDFWriter adalah penulis PDF 1.4 sederhana dengan font standar Helvetica (tanpa embed),
+// cukup untuk report dan cetakan dokumen tanpa dependency luar.
+type PDFWriter struct {
+	width  float64
+	height float64
+	pages  []*bytes.Buffer
+}
+
+func NewPDFWriter(width, height float64) *PDFWriter {
+	return &PDFWriter{width: width, height: height}
+}
+
+func (p *PDFWriter) Width() float64 {
+	return p.width
+}
+
+func (p *PDFWriter) Height() float64 {
+	return p.height
+}
+
+func (p *PDFWriter) AddPage() {
+	p.pages = append(p.pages, &bytes.Buffer{})
+}
+
+func (p *PDFWriter) page() *bytes.Buffer {
+	if len(p.pages) == 0 {
+		p.AddPage()
+	}
+	return p.pages[len(p.pages)-1]
+}
+
+func (p *PDFWriter) Text(x, y, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(p.page(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfEscape(text))
+}
+
+// TextRight menulis teks rata kanan terhadap posisi x
+func (p *PDFWriter) TextRight(x, y, size float64, bold bool, text string) {
+	p.Text(x-TextWidth(text, size, bold), y, size, bold, text)
+}
+
+func (p *PDFWriter) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(p.page(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, y1, x2, y2)
+}
+
+// TextWidth mengukur lebar teks dalam point. Bold diperkirakan 5% lebih lebar.
+func TextWidth(text string, size float64, bold bool) float64 {
+	width := 0.0
+	for _, r := range text {
+		if r >= 32 && r <= 126 {
+			width += helveticaWidth[r-32]
+		} else {
+			width += 556
+		}
+	}
+	if bold {
+		width *= 1.05
+	}
+	return width * size / 1000
+}
+
+// FitText memotong teks supaya muat di lebar tertentu
+func FitText(text string, width, size float64, bold bool) string {
+	if TextWidth(text, size, bold) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && TextWidth(string(runes)+"..", size, bold) > width {
+		runes = runes[:len(runes)-1]
+	}
+	if len(runes) == 0 {
+		return ""
+	}
+	return string(runes) + ".."
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	return n, err
+}
+
+func (p *PDFWriter) WriteTo(w io.Writer) (int64, error) {
+	if len(p.pages) == 0 {
+		p.AddPage()
+	}
+
+	out := &countingWriter{w: w}
+	// object 1 catalog, 2 pages, 3-4 font, lalu pasangan page + content per halaman
+	offsets := make([]int64, 0, 4+2*len(p.pages))
+
+	object := func(body string) error {
+		offsets = append(offsets, out.n)
+		_, err := fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+		return err
+	}
+
+	if _, err := io.WriteString(out, "%PDF-1.4\n"); err != nil {
+		return out.n, err
+	}
+
+	kids := make([]string, len(p.pages))
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+
+	header := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	}
+	for _, body := range header {
+		if err := object(body); err != nil {
+			return out.n, err
+		}
+	}
+
+	for i, content := range p.pages {
+		page := fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			p.width, p.height, 6+2*i)
+		if err := object(page); err != nil {
+			return out.n, err
+		}
+		if err := object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.Bytes())); err != nil {
+			return out.n, err
+		}
+	}
+
+	xref := out.n
+	if _, err := fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1); err != nil {
+		return out.n, err
+	}
+	for _, offset := range offsets {
+		if _, err := fmt.Fprintf(out, "%010d 00000 n \n", offset); err != nil {
+			return out.n, err
+		}
+	}
+	_, err := fmt.Fprintf(out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.n, err
+}
+
+// pdfEscape meng-escape karakter khusus string PDF dan mengubah teks ke WinAnsi (Latin-1)
+func pdfEscape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20:
+			b.WriteByte(' ')
+		case r < 0x80:
+			b.WriteRune(r)
+		case r < 0x100:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
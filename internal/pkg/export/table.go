@@ -0,0 +1,203 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	share "gitlab.com/ayaka/internal/domain/shared"
+)
+
+// Table adalah hasil report yang sudah diratakan menjadi baris teks.
+// Numeric menandai kolom angka supaya XLSX menyimpannya sebagai number.
+type Table struct {
+	Columns []string
+	Numeric []bool
+	Rows    [][]string
+}
+
+type column struct {
+	index   int
+	tag     string
+	label   string
+	numeric bool
+}
+
+// label kolom/kata yang tidak cukup hanya dengan kapitalisasi nama json-nya
+var (
+	columnLabel = map[string]string{
+		"number": "No",
+	}
+	wordLabel = map[string]string{
+		"dt":  "Date",
+		"uom": "UoM",
+		"qty": "Qty",
+	}
+)
+
+// NewTable membangun Table dari slice of struct (atau pointer ke struct).
+// Kolom diambil dari field ber-tag json; field slice atau struct biasa (detail) dilewati.
+// Judul kolom memakai tag label jika ada, selain itu dari nama json.
+func NewTable(data interface{}) (*Table, error) {
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("export: data must be a slice, got %T", data)
+	}
+
+	elem := value.Type().Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("export: unsupported row type %s", elem)
+	}
+
+	columns := columnsOf(elem)
+	table := &Table{
+		Columns: make([]string, len(columns)),
+		Numeric: make([]bool, len(columns)),
+		Rows:    make([][]string, 0, value.Len()),
+	}
+	for i, col := range columns {
+		table.Columns[i] = col.label
+		table.Numeric[i] = col.numeric
+	}
+
+	for i := 0; i < value.Len(); i++ {
+		row := reflect.Indirect(value.Index(i))
+		if !row.IsValid() {
+			continue
+		}
+
+		cells := make([]string, len(columns))
+		for j, col := range columns {
+			cell, err := cellOf(row.Field(col.index))
+			if err != nil {
+				return nil, err
+			}
+			if isDateColumn(col.tag) {
+				cell = formatDate(cell)
+			}
+			cells[j] = cell
+		}
+		table.Rows = append(table.Rows, cells)
+	}
+
+	return table, nil
+}
+
+func columnsOf(t reflect.Type) []column {
+	marshaler := reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+	var columns []column
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		switch field.Type.Kind() {
+		case reflect.Slice, reflect.Map, reflect.Ptr, reflect.Interface:
+			continue
+		case reflect.Struct:
+			if !field.Type.Implements(marshaler) {
+				continue
+			}
+		}
+
+		label := field.Tag.Get("label")
+		if label == "" {
+			label = labelOf(tag)
+		}
+
+		columns = append(columns, column{index: i, tag: tag, label: label, numeric: isNumber(field.Type.Kind())})
+	}
+
+	return columns
+}
+
+func cellOf(field reflect.Value) (string, error) {
+	if m, ok := field.Interface().(json.Marshaler); ok {
+		raw, err := m.MarshalJSON()
+		if err != nil {
+			return "", err
+		}
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return "", err
+		}
+		switch v := v.(type) {
+		case nil:
+			return "", nil
+		case bool:
+			return yesNo(v), nil
+		default:
+			return fmt.Sprint(v), nil
+		}
+	}
+
+	switch field.Kind() {
+	case reflect.Float32:
+		return strconv.FormatFloat(field.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(field.Float(), 'f', -1, 64), nil
+	case reflect.Bool:
+		return yesNo(field.Bool()), nil
+	default:
+		return fmt.Sprint(field.Interface()), nil
+	}
+}
+
+func labelOf(tag string) string {
+	if label, ok := columnLabel[tag]; ok {
+		return label
+	}
+
+	words := strings.Split(tag, "_")
+	for i, word := range words {
+		if label, ok := wordLabel[word]; ok {
+			words[i] = label
+		} else if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func isDateColumn(tag string) bool {
+	return tag == "date" || strings.HasSuffix(tag, "_date") || strings.HasSuffix(tag, "_dt")
+}
+
+// tanggal yang belum diformat repository (yyyymmdd / yyyymmddhhmm) diformat seperti response JSON
+func formatDate(value string) string {
+	if len(value) != 8 && len(value) != 12 {
+		return value
+	}
+	if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+		return value
+	}
+	return share.FormatDate(value)
+}
+
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func yesNo(v bool) string {
+	if v {
+		return "Y"
+	}
+	return "N"
+}
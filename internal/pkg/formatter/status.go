@@ -11,6 +11,7 @@ var (
 	InternalServerError Status = "PAKU05"
 	DataConflict        Status = "PAKU06"
 	Unauthorized        Status = "PAKU07"
+	Forbidden           Status = "PAKU08"
 )
 
 func (s Status) String() string {
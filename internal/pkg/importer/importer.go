@@ -0,0 +1,283 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+// Read membaca sheet pertama file csv/xlsx menjadi baris-baris string.
+// Baris pertama adalah header.
+func Read(filename string, r io.ReaderAt, size int64) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return readCSV(io.NewSectionReader(r, 0, size))
+	case ".xlsx":
+		return readXLSX(r, size)
+	default:
+		return nil, customerrors.ErrUnsupportedFormat
+	}
+}
+
+func readCSV(r io.Reader) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error read csv: %w", err)
+	}
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		rows[0][0] = strings.TrimPrefix(rows[0][0], "\uFEFF")
+	}
+	return rows, nil
+}
+
+// Fields mengembalikan key yang bisa diisi dari file untuk struct target: json tag
+// field biasa, dan "<slice>.<field>" untuk field di dalam slice struct (detail).
+func Fields(target interface{}) []string {
+	var fields []string
+	t := reflect.TypeOf(target)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	collectFields(t, "", &fields)
+	return fields
+}
+
+func collectFields(t reflect.Type, prefix string, fields *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := jsonName(field)
+		if name == "" || !field.IsExported() {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct {
+			if prefix == "" {
+				collectFields(field.Type.Elem(), name+".", fields)
+			}
+			continue
+		}
+		if settable(field.Type) {
+			*fields = append(*fields, prefix+name)
+		}
+	}
+}
+
+// Columns memetakan index kolom file ke field target. mapping berisi header file -> field;
+// header yang tidak ada di mapping dicocokkan dengan nama field atau label-nya.
+// Header yang tidak dikenali dikembalikan sebagai ignored.
+func Columns(target interface{}, header []string, mapping map[string]string) (columns map[int]string, ignored []string, err error) {
+	known := make(map[string]string)
+	for _, field := range Fields(target) {
+		known[normalize(field)] = field
+	}
+	for label, field := range labels(target) {
+		known[normalize(label)] = field
+	}
+
+	mapped := make(map[string]string, len(mapping))
+	for column, field := range mapping {
+		if _, ok := known[normalize(field)]; !ok {
+			return nil, nil, fmt.Errorf("unknown field %s for column %s", field, column)
+		}
+		mapped[normalize(column)] = known[normalize(field)]
+	}
+
+	columns = make(map[int]string)
+	used := make(map[string]string)
+	for i, column := range header {
+		field, ok := mapped[normalize(column)]
+		if !ok {
+			field, ok = known[normalize(column)]
+		}
+		if !ok {
+			if strings.TrimSpace(column) != "" {
+				ignored = append(ignored, column)
+			}
+			continue
+		}
+		if other, dup := used[field]; dup {
+			return nil, nil, fmt.Errorf("column %s and %s are both mapped to %s", other, column, field)
+		}
+		used[field] = column
+		columns[i] = field
+	}
+
+	return columns, ignored, nil
+}
+
+func labels(target interface{}) map[string]string {
+	result := make(map[string]string)
+	t := reflect.TypeOf(target)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if label := field.Tag.Get("label"); label != "" && settable(field.Type) {
+			result[label] = jsonName(field)
+		}
+	}
+	return result
+}
+
+// Values mengubah satu baris file menjadi field -> nilai berdasarkan hasil Columns.
+func Values(row []string, columns map[int]string) map[string]string {
+	values := make(map[string]string, len(columns))
+	for i, field := range columns {
+		if i < len(row) {
+			values[field] = strings.TrimSpace(row[i])
+		}
+	}
+	return values
+}
+
+// Decode mengisi struct target dari values. Field detail ("<slice>.<field>") menambah
+// satu elemen slice bila ada nilainya. Error dikembalikan per field.
+func Decode(target interface{}, values map[string]string) map[string]string {
+	errs := make(map[string]string)
+	v := reflect.ValueOf(target).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := jsonName(field)
+		if name == "" || !field.IsExported() {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct {
+			detail := make(map[string]string)
+			for key, value := range values {
+				if sub, ok := strings.CutPrefix(key, name+"."); ok && value != "" {
+					detail[sub] = value
+				}
+			}
+			if len(detail) == 0 {
+				continue
+			}
+			elem := reflect.New(field.Type.Elem())
+			for key, msg := range Decode(elem.Interface(), detail) {
+				errs[name+"."+key] = msg
+			}
+			v.Field(i).Set(reflect.Append(v.Field(i), elem.Elem()))
+			continue
+		}
+
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+		if err := set(v.Field(i), value); err != nil {
+			errs[name] = err.Error()
+		}
+	}
+
+	return errs
+}
+
+var (
+	nullType = reflect.TypeOf(nulldatatype.NullDataType{})
+	boolType = reflect.TypeOf(booldatatype.BoolDataType{})
+)
+
+func settable(t reflect.Type) bool {
+	switch t {
+	case nullType, boolType:
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Float32, reflect.Float64, reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint:
+		return true
+	}
+	return false
+}
+
+func set(field reflect.Value, value string) error {
+	switch field.Type() {
+	case nullType:
+		field.Set(reflect.ValueOf(nulldatatype.NewNullStringDataType(value)))
+		return nil
+	case boolType:
+		if value == "" {
+			return nil
+		}
+		b, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(booldatatype.FromBool(b)))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Float32, reflect.Float64:
+		if value == "" {
+			return nil
+		}
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s is not a valid number", value)
+		}
+		field.SetFloat(f)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		if value == "" {
+			return nil
+		}
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s is not a valid number", value)
+		}
+		field.SetInt(n)
+	case reflect.Uint:
+		if value == "" {
+			return nil
+		}
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s is not a valid number", value)
+		}
+		field.SetUint(n)
+	}
+	return nil
+}
+
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "y", "yes", "true", "1":
+		return true, nil
+	case "n", "no", "false", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("%s is not a valid Y/N value", value)
+}
+
+func jsonName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+// normalize supaya "Item Name", "item_name" dan "ITEM NAME" dianggap sama
+func normalize(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(s)
+}
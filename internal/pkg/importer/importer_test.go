@@ -0,0 +1,108 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/ayaka/internal/domain/shared/booldatatype"
+	"gitlab.com/ayaka/internal/domain/shared/nulldatatype"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+type contact struct {
+	Name   string                    `json:"name"`
+	Active booldatatype.BoolDataType `json:"active"`
+}
+
+type vendorRow struct {
+	VendorCode string                    `json:"vendor_code"`
+	VendorName string                    `json:"vendor_name" label:"Vendor Name"`
+	Remark     nulldatatype.NullDataType `json:"remark"`
+	Rating     float32                   `json:"rating"`
+	Contacts   []contact                 `json:"contact_vendor"`
+	Internal   string                    `json:"-"`
+}
+
+func TestReadCSV(t *testing.T) {
+	content := "\uFEFFvendor_name,remark\n\"PT Maju, Tbk\",  urgent\n"
+
+	rows, err := Read("vendor.CSV", strings.NewReader(content), int64(len(content)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"vendor_name", "remark"}, {"PT Maju, Tbk", "urgent"}}, rows)
+}
+
+func TestReadXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	parts := map[string]string{
+		"xl/workbook.xml":            `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Data" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId3" Target="worksheets/data.xml"/></Relationships>`,
+		"xl/sharedStrings.xml":       `<sst><si><t>Vendor Name</t></si><si><r><t>PT </t></r><r><t>Maju</t></r></si></sst>`,
+		"xl/worksheets/data.xml": `<worksheet><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="inlineStr"><is><t>rating</t></is></c></row>` +
+			`<row r="2"><c r="A2" t="s"><v>1</v></c><c r="C2"><v>4.5</v></c></row>` +
+			`</sheetData></worksheet>`,
+	}
+	for name, content := range parts {
+		w, err := archive.Create(name)
+		assert.NoError(t, err)
+		_, _ = w.Write([]byte(content))
+	}
+	assert.NoError(t, archive.Close())
+
+	rows, err := Read("vendor.xlsx", bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Vendor Name", "", "rating"}, {"PT Maju", "", "4.5"}}, rows)
+}
+
+func TestReadUnsupported(t *testing.T) {
+	_, err := Read("vendor.txt", strings.NewReader(""), 0)
+
+	assert.ErrorIs(t, err, customerrors.ErrUnsupportedFormat)
+}
+
+func TestColumns(t *testing.T) {
+	header := []string{"Vendor Name", "Catatan", "Contact_Vendor.Name", "Unknown"}
+
+	columns, ignored, err := Columns(&vendorRow{}, header, map[string]string{"catatan": "remark"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]string{0: "vendor_name", 1: "remark", 2: "contact_vendor.name"}, columns)
+	assert.Equal(t, []string{"Unknown"}, ignored)
+
+	_, _, err = Columns(&vendorRow{}, header, map[string]string{"Catatan": "note"})
+	assert.Error(t, err)
+
+	_, _, err = Columns(&vendorRow{}, []string{"vendor_name", "Vendor Name"}, nil)
+	assert.Error(t, err)
+}
+
+func TestDecode(t *testing.T) {
+	var row vendorRow
+	errs := Decode(&row, map[string]string{
+		"vendor_name":           "PT Maju",
+		"remark":                "",
+		"rating":                "4.5",
+		"contact_vendor.name":   "Budi",
+		"contact_vendor.active": "Y",
+	})
+
+	assert.Empty(t, errs)
+	assert.Equal(t, "PT Maju", row.VendorName)
+	assert.Equal(t, nulldatatype.NewNullStringDataType(""), row.Remark)
+	assert.Equal(t, float32(4.5), row.Rating)
+	assert.Equal(t, []contact{{Name: "Budi", Active: booldatatype.FromBool(true)}}, row.Contacts)
+
+	row = vendorRow{}
+	errs = Decode(&row, map[string]string{"rating": "lima", "contact_vendor.active": "maybe"})
+
+	assert.Equal(t, map[string]string{
+		"rating":                "lima is not a valid number",
+		"contact_vendor.active": "maybe is not a valid Y/N value",
+	}, errs)
+}
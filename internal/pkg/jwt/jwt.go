@@ -1,8 +1,11 @@
 package jwt
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -15,15 +18,64 @@ type JwtHandler struct {
 }
 
 type Claims struct {
-	UserCode string `json:"userCode"`
-	UserName string `json:"userName"`
+	UserCode    string   `json:"userCode"`
+	UserName    string   `json:"userName"`
+	Permissions []string `json:"permissions,omitempty"`
+	Warehouses  []string `json:"warehouses,omitempty"`
+	Sites       []string `json:"sites,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func (j *JwtHandler) GenerateToken(userCode, userName, signKey string, duration time.Duration, uot time.Duration) (string, error) {
+// Access adalah hak akses user yang dibawa token: permission menu:action serta
+// gudang dan site yang boleh diakses ("*" berarti semua).
+type Access struct {
+	Permissions []string
+	Warehouses  []string
+	Sites       []string
+}
+
+// FromContext mengambil claims yang disimpan AuthRequired lewat c.Locals("user").
+// Context dari c.Context() meneruskan Value ke user value fasthttp.
+func FromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value("user").(*Claims)
+	return claims, ok && claims != nil
+}
+
+// HasPermission mengecek permission menu:action, termasuk wildcard menu:* dan
+// *:* (disimpan sebagai Menu "*" Action "*") atau *.
+func (c *Claims) HasPermission(permission string) bool {
+	menu, _, _ := strings.Cut(permission, ":")
+	for _, p := range c.Permissions {
+		if p == "*" || p == "*:*" || p == permission || p == menu+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Claims) AllWarehouse() bool {
+	return slices.Contains(c.Warehouses, "*")
+}
+
+func (c *Claims) AllowWarehouse(whsCode string) bool {
+	return c.AllWarehouse() || slices.Contains(c.Warehouses, whsCode)
+}
+
+func (c *Claims) AllSite() bool {
+	return slices.Contains(c.Sites, "*")
+}
+
+func (c *Claims) AllowSite(siteCode string) bool {
+	return c.AllSite() || slices.Contains(c.Sites, siteCode)
+}
+
+func (j *JwtHandler) GenerateToken(userCode, userName string, access Access, signKey string, duration time.Duration, uot time.Duration) (string, error) {
 	claims := &Claims{
-		UserCode: userCode,
-		UserName: userName,
+		UserCode:    userCode,
+		UserName:    userName,
+		Permissions: access.Permissions,
+		Warehouses:  access.Warehouses,
+		Sites:       access.Sites,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration * uot)),
 		},
@@ -0,0 +1,42 @@
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/ayaka/internal/pkg/jwt"
+)
+
+func TestHasPermission(t *testing.T) {
+	t.Run("exact permission", func(t *testing.T) {
+		claims := &jwt.Claims{Permissions: []string{"purchase-order:create"}}
+
+		assert.True(t, claims.HasPermission("purchase-order:create"))
+		assert.False(t, claims.HasPermission("purchase-order:update"))
+	})
+
+	t.Run("menu wildcard", func(t *testing.T) {
+		claims := &jwt.Claims{Permissions: []string{"stock-adjustment:*"}}
+
+		assert.True(t, claims.HasPermission("stock-adjustment:create"))
+		assert.False(t, claims.HasPermission("stock-mutation:create"))
+	})
+
+	t.Run("all permission", func(t *testing.T) {
+		claims := &jwt.Claims{Permissions: []string{"*"}}
+
+		assert.True(t, claims.HasPermission("user-group:update"))
+	})
+
+	t.Run("all permission from group matrix", func(t *testing.T) {
+		claims := &jwt.Claims{Permissions: []string{"*:*"}}
+
+		assert.True(t, claims.HasPermission("approval:update"))
+	})
+
+	t.Run("no permission", func(t *testing.T) {
+		claims := &jwt.Claims{}
+
+		assert.False(t, claims.HasPermission("purchase-order:read"))
+	})
+}
@@ -0,0 +1,345 @@
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"gitlab.com/ayaka/internal/domain/printout"
+	"gitlab.com/ayaka/internal/pkg/export"
+	"golang.org/x/net/html"
+)
+
+// layout cetak A4 portrait, satuan point
+const (
+	margin     = 36.0
+	fontSize   = 8.0
+	titleSize  = 14.0
+	lineHeight = 12.0
+	cellPad    = 3.0
+)
+
+// ukuran font heading, elemen lain memakai fontSize
+var headingSize = map[string]float64{
+	"h1": titleSize,
+	"h2": fontSize + 3,
+	"h3": fontSize + 1,
+}
+
+type pdfSegment struct {
+	text string
+	bold bool
+}
+
+type pdfCell struct {
+	text  string
+	bold  bool
+	right bool
+}
+
+// pdfBlock satu paragraf (segments) atau satu tabel
+type pdfBlock struct {
+	size     float64
+	segments []pdfSegment
+	header   [][]pdfCell
+	rows     [][]pdfCell
+}
+
+// pdf merender template HTML yang sama dengan output html (termasuk override per
+// site) lalu menata ulang hasilnya ke PDF. Yang didukung hanya subset HTML untuk
+// cetakan dokumen: heading, paragraf / div / br, bold (strong, b, th) dan tabel
+// (kolom class "num" rata kanan). CSS tidak dibaca.
+func (p *Printer) pdf(w io.Writer, doc *printout.Document) error {
+	var buf bytes.Buffer
+	if err := p.html(&buf, doc); err != nil {
+		return err
+	}
+
+	root, err := html.Parse(&buf)
+	if err != nil {
+		return fmt.Errorf("error parse print html %s: %w", doc.Type, err)
+	}
+
+	l := &pdfLayout{}
+	l.walk(root, false, fontSize)
+	l.flush(fontSize)
+
+	return l.write(w, fmt.Sprintf("%s %s", doc.Title, doc.DocNo))
+}
+
+type pdfLayout struct {
+	blocks  []pdfBlock
+	pending []pdfSegment
+}
+
+func (l *pdfLayout) walk(n *html.Node, bold bool, size float64) {
+	switch n.Type {
+	case html.TextNode:
+		l.inline(n.Data, bold)
+		return
+	case html.ElementNode:
+		switch n.Data {
+		case "head", "style", "script", "title":
+			return
+		case "br":
+			l.flush(size)
+			return
+		case "table":
+			l.flush(size)
+			l.table(n)
+			return
+		case "strong", "b":
+			bold = true
+		case "h1", "h2", "h3":
+			l.flush(size)
+			size = headingSize[n.Data]
+			bold = true
+			defer l.flush(size)
+		case "p", "div", "li", "tr":
+			l.flush(size)
+			defer l.flush(size)
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		l.walk(c, bold, size)
+	}
+}
+
+// inline menambah teks dengan whitespace HTML yang sudah diringkas
+func (l *pdfLayout) inline(text string, bold bool) {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return
+	}
+	if len(l.pending) > 0 {
+		text = " " + text
+	}
+	l.pending = append(l.pending, pdfSegment{text, bold})
+}
+
+func (l *pdfLayout) flush(size float64) {
+	if len(l.pending) == 0 {
+		return
+	}
+	l.blocks = append(l.blocks, pdfBlock{size: size, segments: l.pending})
+	l.pending = nil
+}
+
+func (l *pdfLayout) table(n *html.Node) {
+	var block pdfBlock
+	var rows func(n *html.Node)
+	rows = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			if c.Data != "tr" {
+				rows(c)
+				continue
+			}
+
+			var row []pdfCell
+			header := true
+			for td := c.FirstChild; td != nil; td = td.NextSibling {
+				if td.Type != html.ElementNode || (td.Data != "td" && td.Data != "th") {
+					continue
+				}
+				header = header && td.Data == "th"
+				text, bold := cellText(td)
+				row = append(row, pdfCell{text: text, bold: bold || td.Data == "th", right: hasClass(td, "num")})
+			}
+			if len(row) == 0 {
+				continue
+			}
+			if header && len(block.rows) == 0 {
+				block.header = append(block.header, row)
+			} else {
+				block.rows = append(block.rows, row)
+			}
+		}
+	}
+	rows(n)
+
+	if len(block.header)+len(block.rows) > 0 {
+		block.size = fontSize
+		l.blocks = append(l.blocks, block)
+	}
+}
+
+func cellText(n *html.Node) (string, bool) {
+	var parts []string
+	bold := false
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			if text := strings.Join(strings.Fields(n.Data), " "); text != "" {
+				parts = append(parts, text)
+			}
+			return
+		}
+		if n.Type == html.ElementNode && (n.Data == "strong" || n.Data == "b") {
+			bold = true
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(parts, " "), bold
+}
+
+func hasClass(n *html.Node, class string) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == "class" && strings.Contains(" "+attr.Val+" ", " "+class+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// write menata blok dari atas ke bawah, tabel yang terpotong halaman mengulang
+// baris header-nya. Setiap halaman diberi footer nomor dokumen dan halaman.
+func (l *pdfLayout) write(w io.Writer, footer string) error {
+	pdf := export.NewPDFWriter(export.A4Width, export.A4Height)
+	width := pdf.Width() - 2*margin
+	page := 0
+	var y float64
+
+	newPage := func() {
+		pdf.AddPage()
+		page++
+		pdf.Text(margin, margin/2, fontSize-1, false, fmt.Sprintf("%s - page %d", footer, page))
+		y = pdf.Height() - margin
+	}
+	// ensure pindah halaman kalau sisa ruang kurang dari h
+	ensure := func(h float64) bool {
+		if y-h < margin {
+			newPage()
+			return true
+		}
+		return false
+	}
+	newPage()
+
+	for _, block := range l.blocks {
+		if block.segments != nil {
+			height := max(lineHeight, block.size+4)
+			for _, line := range wrapSegments(block.segments, width, block.size) {
+				ensure(height)
+				y -= height
+				x := margin
+				for _, s := range line {
+					pdf.Text(x, y, block.size, s.bold, s.text)
+					x += export.TextWidth(s.text, block.size, s.bold)
+				}
+			}
+			continue
+		}
+
+		widths := cellWidths(append(block.header, block.rows...), width)
+		drawRow := func(row []pdfCell) {
+			y -= lineHeight
+			x := margin
+			for i, cell := range row {
+				if i >= len(widths) {
+					break
+				}
+				text := export.FitText(cell.text, widths[i]-2*cellPad, fontSize, cell.bold)
+				if text == "" {
+					// sel kosong tidak ditulis
+				} else if cell.right {
+					pdf.TextRight(x+widths[i]-cellPad, y, fontSize, cell.bold, text)
+				} else {
+					pdf.Text(x+cellPad, y, fontSize, cell.bold, text)
+				}
+				x += widths[i]
+			}
+		}
+		drawHeader := func() {
+			for _, row := range block.header {
+				drawRow(row)
+			}
+			if len(block.header) > 0 {
+				pdf.Line(margin, y-4, margin+sum(widths), y-4)
+				y -= 4
+			}
+		}
+
+		y -= lineHeight / 2
+		ensure(float64(len(block.header)+1)*lineHeight + 4)
+		drawHeader()
+		for _, row := range block.rows {
+			if ensure(lineHeight) {
+				drawHeader()
+			}
+			drawRow(row)
+		}
+		y -= lineHeight / 2
+	}
+
+	_, err := pdf.WriteTo(w)
+	return err
+}
+
+// wrapSegments memecah paragraf per kata supaya muat di lebar halaman
+func wrapSegments(segments []pdfSegment, width, size float64) [][]pdfSegment {
+	var lines [][]pdfSegment
+	var line []pdfSegment
+	used := 0.0
+	for _, s := range segments {
+		for i, word := range strings.SplitAfter(s.text, " ") {
+			if word == "" {
+				continue
+			}
+			w := export.TextWidth(word, size, s.bold)
+			if used+w > width && used > 0 {
+				lines = append(lines, line)
+				line, used = nil, 0
+				word = strings.TrimLeft(word, " ")
+				w = export.TextWidth(word, size, s.bold)
+			}
+			if n := len(line); n > 0 && line[n-1].bold == s.bold && i > 0 {
+				line[n-1].text += word
+			} else {
+				line = append(line, pdfSegment{word, s.bold})
+			}
+			used += w
+		}
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// cellWidths lebar kolom sesuai isi terpanjang, diperkecil proporsional kalau
+// tabel lebih lebar dari halaman
+func cellWidths(rows [][]pdfCell, width float64) []float64 {
+	var widths []float64
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			// +1 supaya teks terpanjang tidak terpotong FitText karena pembulatan
+			widths[i] = max(widths[i], export.TextWidth(cell.text, fontSize, cell.bold)+2*cellPad+1)
+		}
+	}
+
+	if total := sum(widths); total > width {
+		for i := range widths {
+			widths[i] *= width / total
+		}
+	}
+	return widths
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
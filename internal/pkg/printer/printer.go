@@ -0,0 +1,124 @@
+package printer
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gitlab.com/ayaka/internal/domain/printout"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+//go:embed templates/*.html
+var defaultTemplates embed.FS
+
+var funcs = template.FuncMap{
+	"money":   Money,
+	"qty":     Quantity,
+	"percent": Percent,
+}
+
+// Printer merender dokumen ke HTML atau PDF dari template yang sama.
+// Dir adalah folder template yang bisa di-override per site.
+type Printer struct {
+	Dir string
+}
+
+func (p *Printer) Render(w io.Writer, format string, doc *printout.Document) error {
+	switch format {
+	case printout.HTML:
+		return p.html(w, doc)
+	case printout.PDF:
+		return p.pdf(w, doc)
+	default:
+		return customerrors.ErrUnsupportedFormat
+	}
+}
+
+func (p *Printer) html(w io.Writer, doc *printout.Document) error {
+	source, err := p.template(doc.SiteCode, doc.Type)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New(doc.Type).Funcs(funcs).Parse(source)
+	if err != nil {
+		return fmt.Errorf("error parse print template %s: %w", doc.Type, err)
+	}
+
+	return tmpl.Execute(w, doc)
+}
+
+// template mencari <Dir>/<site>/<type>.html, lalu <Dir>/<type>.html,
+// lalu template bawaan per type dan terakhir document.html.
+func (p *Printer) template(site, docType string) (string, error) {
+	name := docType + ".html"
+
+	if p.Dir != "" {
+		var candidates []string
+		if site != "" {
+			candidates = append(candidates, filepath.Join(p.Dir, filepath.Base(site), name))
+		}
+		candidates = append(candidates, filepath.Join(p.Dir, name))
+
+		for _, path := range candidates {
+			content, err := os.ReadFile(path)
+			if err == nil {
+				return string(content), nil
+			}
+			if !errors.Is(err, fs.ErrNotExist) {
+				return "", fmt.Errorf("error read print template %s: %w", path, err)
+			}
+		}
+	}
+
+	for _, path := range []string{"templates/" + name, "templates/document.html"} {
+		content, err := defaultTemplates.ReadFile(path)
+		if err == nil {
+			return string(content), nil
+		}
+	}
+
+	return "", fmt.Errorf("print template %s not found", name)
+}
+
+// Money memformat angka dengan pemisah ribuan titik dan 2 desimal koma, contoh 1.250.000,50
+func Money(value float32) string {
+	s := strconv.FormatFloat(float64(value), 'f', 2, 32)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, decimal, _ := strings.Cut(s, ".")
+	var b strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(r)
+	}
+
+	result := b.String() + "," + decimal
+	if negative {
+		result = "-" + result
+	}
+	return result
+}
+
+// Quantity menampilkan qty tanpa nol di belakang koma
+func Quantity(value float32) string {
+	return strings.Replace(strconv.FormatFloat(float64(value), 'f', -1, 32), ".", ",", 1)
+}
+
+// Percent mengubah rate pajak (0.11) menjadi 11%
+func Percent(rate float32) string {
+	percent := math.Round(float64(rate)*10000) / 100
+	return strings.Replace(strconv.FormatFloat(percent, 'f', -1, 64), ".", ",", 1) + "%"
+}
@@ -0,0 +1,116 @@
+package printer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/ayaka/internal/domain/printout"
+	"gitlab.com/ayaka/internal/pkg/customerrors"
+)
+
+func document() *printout.Document {
+	return &printout.Document{
+		Type:       printout.PurchaseOrder,
+		Title:      "Purchase Order",
+		DocNo:      "0001/SBY/PO/01/26",
+		Date:       "15/Jan/2026",
+		Status:     "Outstanding",
+		SiteCode:   "SBY",
+		SiteName:   "Surabaya",
+		Parties:    []printout.NamedParty{{Label: "Vendor", Party: printout.Party{Name: "PT Maju", ContactName: "Budi"}}},
+		Lines:      []printout.Line{{No: 1, ItemCode: "IT01", ItemName: "Baut", Qty: 10, Uom: "PCS", UnitPrice: 1500, Total: 15000}},
+		ShowPrice:  true,
+		Currency:   "IDR",
+		SubTotal:   15000,
+		TaxCode:    "PPN",
+		TaxName:    "PPN",
+		TaxRate:    0.11,
+		TaxAmount:  1650,
+		GrandTotal: 16650,
+	}
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "1.250.000,50", Money(1250000.5))
+	assert.Equal(t, "-1.500,00", Money(-1500))
+	assert.Equal(t, "0,00", Money(0))
+	assert.Equal(t, "2,5", Quantity(2.5))
+	assert.Equal(t, "11%", Percent(0.11))
+}
+
+func TestRenderHTML(t *testing.T) {
+	var buf bytes.Buffer
+	err := (&Printer{}).Render(&buf, printout.HTML, document())
+
+	assert.NoError(t, err)
+	html := buf.String()
+	assert.Contains(t, html, "0001/SBY/PO/01/26")
+	assert.Contains(t, html, `<span class="status">Outstanding</span>`)
+	assert.Contains(t, html, "Contact: Budi")
+	assert.Contains(t, html, "PPN (11%)")
+	assert.Contains(t, html, "IDR 16.650,00")
+}
+
+func TestRenderHTML_SiteTemplate(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "SBY"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "purchase-order.html"), []byte("default {{.DocNo}}"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "SBY", "purchase-order.html"), []byte("sby {{.DocNo}}"), 0o644))
+
+	p := &Printer{Dir: dir}
+	doc := document()
+
+	var buf bytes.Buffer
+	assert.NoError(t, p.Render(&buf, printout.HTML, doc))
+	assert.Equal(t, "sby 0001/SBY/PO/01/26", buf.String())
+
+	buf.Reset()
+	doc.SiteCode = "JKT"
+	assert.NoError(t, p.Render(&buf, printout.HTML, doc))
+	assert.Equal(t, "default 0001/SBY/PO/01/26", buf.String())
+}
+
+func TestRenderPDF(t *testing.T) {
+	doc := document()
+	for i := 0; i < 80; i++ {
+		doc.Lines = append(doc.Lines, doc.Lines[0])
+	}
+
+	var buf bytes.Buffer
+	err := (&Printer{}).Render(&buf, printout.PDF, doc)
+
+	assert.NoError(t, err)
+	pdf := buf.String()
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-"))
+	assert.Contains(t, pdf, "(Purchase Order)")
+	assert.Contains(t, pdf, "(Contact: Budi)")
+	assert.Contains(t, pdf, "(IDR 16.650,00)")
+	assert.Greater(t, strings.Count(pdf, "/Type /Page "), 1)
+	// header tabel diulang di halaman berikutnya
+	assert.Greater(t, strings.Count(pdf, "(Item Code)"), 1)
+}
+
+func TestRenderPDF_SiteTemplate(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "SBY"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "SBY", "purchase-order.html"), []byte("<h1>SBY {{.DocNo}}</h1><table><tr><td class=\"num\">{{money .GrandTotal}}</td></tr></table>"), 0o644))
+
+	var buf bytes.Buffer
+	err := (&Printer{Dir: dir}).Render(&buf, printout.PDF, document())
+
+	assert.NoError(t, err)
+	pdf := buf.String()
+	assert.Contains(t, pdf, "(SBY 0001/SBY/PO/01/26)")
+	assert.Contains(t, pdf, "(16.650,00)")
+	assert.NotContains(t, pdf, "(Item Code)")
+}
+
+func TestRenderUnsupported(t *testing.T) {
+	err := (&Printer{}).Render(&bytes.Buffer{}, "docx", document())
+
+	assert.ErrorIs(t, err, customerrors.ErrUnsupportedFormat)
+}